@@ -354,8 +354,11 @@ rules:
   - delete
 - apiGroups:
   - fission.io
+  # functions/status: the router writes the CircuitClosed condition of
+  # functions that opted into circuit breaking.
   resources:
   - httptriggers/status
+  - functions/status
   verbs:
   - get
   - update
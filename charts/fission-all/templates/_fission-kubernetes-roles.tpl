@@ -219,6 +219,30 @@ rules:
   verbs:
   - get
   - list
+# Executor type job: one Job per invocation, its input in a Secret the Job
+# owns, and its outcome read from the pod's logs.
+- apiGroups:
+  - batch
+  resources:
+  - jobs
+  verbs:
+  - create
+  - get
+  - list
+  - watch
+  - delete
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - create
+- apiGroups:
+  - ""
+  resources:
+  - pods/log
+  verbs:
+  - get
 {{- end }}
 {{- define "kubewatcher-kuberules" }}
 rules:
@@ -417,10 +441,19 @@ rules:
 # TODO: Kept for future in case preupgrade needs any permissions in the future
 rules: []
 {{- end }}
-# TODO: Currently, router needs ingress related permissions only.
+# TODO: Currently, router needs ingress related permissions, plus read access
+# to Secrets for HTTPTrigger API-key auth policies.
 # In future if router's permissions are modified then check the configured namespace.
 {{- define "router-kuberules" }}
 rules:
+# HTTPTrigger spec.auth.apiKey: the router GETs the named Secret (no
+# list/watch — keys are re-read on a timer, so no cluster-wide Secret cache).
+- apiGroups:
+  - ""
+  resources:
+  - secrets
+  verbs:
+  - get
 - apiGroups:
   - networking.k8s.io
   resources:
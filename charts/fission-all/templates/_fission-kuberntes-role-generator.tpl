@@ -23,7 +23,7 @@ metadata:
 {{- if eq "kubewatcher" .component }}
 {{- include "kubewatcher-kuberules" . }}
 {{- end }}
-{{- if eq "kafka" .component }}
+{{- if has .component (list "kafka" "nats" "rabbitmq") }}
 {{- include "kafka-kuberules" . }}
 {{- end }}
 {{- if eq "statestore-mqt" .component }}
@@ -32,7 +32,7 @@ metadata:
 {{- if eq "statesvc" .component }}
 {{- include "statesvc-rules" . }}
 {{- end }}
-{{- if eq "kafka" .component }}
+{{- if has .component (list "kafka" "nats" "rabbitmq") }}
 {{- include "kafka-rules" . }}
 {{- end }}
 {{- if eq "statestore-mqt" .component }}
@@ -110,7 +110,7 @@ metadata:
 {{- if eq "statesvc" .component }}
 {{- include "statesvc-rules" . }}
 {{- end }}
-{{- if eq "kafka" .component }}
+{{- if has .component (list "kafka" "nats" "rabbitmq") }}
 {{- include "kafka-rules" . }}
 {{- end }}
 {{- if eq "keda" .component }}
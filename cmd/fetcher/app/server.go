@@ -38,6 +38,9 @@ func Run(ctx context.Context, clientGen crd.ClientGeneratorInterface, logger log
 	specializePayload := flag.String("specialize-request", "", "JSON payload for specialize request")
 	secretDir := flag.String("secret-dir", "", "Path to shared secrets directory")
 	configDir := flag.String("cfgmap-dir", "", "Path to shared configmap directory")
+	pkgCacheDir := flag.String("package-cache-dir", "", "Path to the node-local package cache directory; empty disables the cache")
+	pkgCacheMaxBytes := flag.Int64("package-cache-max-bytes", 0, "Size limit of the node-local package cache in bytes; 0 disables eviction")
+	signSpecialize := flag.Bool("sign-specialize", false, "Sign the specialize request to the runtime, for runtimes that verify it")
 
 	flag.Parse()
 	if flag.NArg() == 0 {
@@ -76,6 +79,14 @@ func Run(ctx context.Context, clientGen crd.ClientGeneratorInterface, logger log
 	if err != nil {
 		return fmt.Errorf("error making fetcher: %w", err)
 	}
+	if *pkgCacheDir != "" {
+		if err := f.EnablePackageCache(*pkgCacheDir, *pkgCacheMaxBytes); err != nil {
+			return fmt.Errorf("error enabling package cache: %w", err)
+		}
+	}
+	if *signSpecialize {
+		f.SignSpecializeRequests()
+	}
 
 	// do specialization in other goroutine to prevent blocking in newdeploy
 	mgr.Go(func() error {
@@ -163,5 +174,5 @@ func Run(ctx context.Context, clientGen crd.ClientGeneratorInterface, logger log
 }
 
 func fetcherUsage() {
-	fmt.Println("Usage: fetcher [-specialize-on-startup] [-specialize-request <json>] [-secret-dir <string>] [-cfgmap-dir <string>] <shared volume path>")
+	fmt.Println("Usage: fetcher [-specialize-on-startup] [-specialize-request <json>] [-secret-dir <string>] [-cfgmap-dir <string>] [-package-cache-dir <string>] [-package-cache-max-bytes <int>] <shared volume path>")
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/fission/fission/pkg/executor"
 	eclient "github.com/fission/fission/pkg/executor/client"
 	"github.com/fission/fission/pkg/info"
+	"github.com/fission/fission/pkg/jobrunner"
 	"github.com/fission/fission/pkg/kubewatcher"
 	"github.com/fission/fission/pkg/mcp"
 	mqt "github.com/fission/fission/pkg/mqtrigger"
@@ -39,6 +40,7 @@ import (
 	"github.com/fission/fission/pkg/utils/loggerfactory"
 	"github.com/fission/fission/pkg/utils/otel"
 	"github.com/fission/fission/pkg/utils/profile"
+	"github.com/fission/fission/pkg/wasmhost"
 	"github.com/fission/fission/pkg/webhook"
 	"github.com/fission/fission/pkg/workflow"
 )
@@ -66,6 +68,10 @@ type CommandLineArgs struct {
 	statestorePort     int
 	workflowPort       int
 	stateAPIPort       int
+	wasmHostPort       int
+
+	// jobRunner runs one job executor invocation and exits
+	jobRunner bool
 
 	// URL values — empty means "not set": the resolver derives the
 	// in-cluster default from POD_NAMESPACE (see svcinfo.AddressResolver)
@@ -108,6 +114,8 @@ Usage:
   fission-bundle --mqt   [--routerUrl=<url>]
   fission-bundle --mqt_keda [--routerUrl=<url>]
   fission-bundle --webhookPort=<port>
+  fission-bundle --wasmHostPort=<port>
+  fission-bundle --jobRunner
   fission-bundle --version
 Options:
   --canaryConfig		  		  Start canary config server.
@@ -124,6 +132,8 @@ Options:
   --mqt_keda					  Start message queue trigger of kind KEDA
   --builderMgr                    Start builder manager.
   --tenantController              Start the multi-namespace tenant lifecycle controller.
+  --wasmHostPort=<port>           Port that the wasm host (executor type wasm) should listen on.
+  --jobRunner                     Run one invocation of a function of executor type job, then exit.
   --version                       Print version information`
 
 func main() {
@@ -208,6 +218,7 @@ func setupCommandLineArgs() *CommandLineArgs {
 	flag.BoolVar(&args.builderMgr, "builderMgr", false, "Start builder manager")
 	flag.BoolVar(&args.tenantController, "tenantController", false, "Start the multi-namespace tenant lifecycle controller")
 	flag.BoolVar(&args.seedTenants, "seedTenants", false, "Seed FissionTenant CRs from the env namespace config, then exit (migration hook)")
+	flag.BoolVar(&args.jobRunner, "jobRunner", false, "Run one invocation of a function of executor type job, then exit")
 	flag.BoolVar(&args.showVersion, "version", false, "Print version information")
 
 	// Port flags
@@ -220,6 +231,7 @@ func setupCommandLineArgs() *CommandLineArgs {
 	flag.IntVar(&args.statestorePort, "statestorePort", 0, "Port that the embedded statestore should listen on (RFC-0021 embedded mode)")
 	flag.IntVar(&args.workflowPort, "workflowPort", 0, "Port that the workflow engine should listen on (RFC-0022)")
 	flag.IntVar(&args.stateAPIPort, "stateApiPort", 0, "Port that the statesvc function-facing state API should listen on (RFC-0023)")
+	flag.IntVar(&args.wasmHostPort, "wasmHostPort", 0, "Port that the wasm host (executor type wasm) should listen on")
 
 	// URL flags
 	flag.StringVar(&args.executorUrl, "executorUrl", "", "Executor URL (default http://executor.<POD_NAMESPACE>)")
@@ -383,6 +395,26 @@ func bundleServices() []bundleService {
 				return statesvc.Start(ctx, d.clientGen, d.logger, d.mgr, statesvc.Options{Port: d.args.stateAPIPort})
 			},
 		},
+		{
+			// The wasm host runs in the executor's per-namespace host pods,
+			// next to their fetcher; it needs no Kubernetes clients.
+			name:     "Fission-WasmHost",
+			selected: func(a *CommandLineArgs) bool { return a.wasmHostPort != 0 },
+			run: func(ctx context.Context, d bundleDeps) error {
+				return wasmhost.Start(ctx, d.logger, d.mgr, wasmhost.Options{Port: d.args.wasmHostPort})
+			},
+		},
+		{
+			// The job runner is the main container of a job executor pod,
+			// next to the runtime and fetcher sidecars; its exit status is
+			// the Job's.
+			name:     "Fission-JobRunner",
+			selected: func(a *CommandLineArgs) bool { return a.jobRunner },
+			oneShot:  true,
+			run: func(ctx context.Context, d bundleDeps) error {
+				return jobrunner.Run(ctx, d.logger, jobrunner.Options{})
+			},
+		},
 		{
 			name:     "Fission-TenantController",
 			selected: func(a *CommandLineArgs) bool { return a.tenantController },
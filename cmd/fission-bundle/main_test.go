@@ -35,6 +35,8 @@ func TestBundleDispatchTable(t *testing.T) {
 		{"tenantController", CommandLineArgs{tenantController: true}, "Fission-TenantController"},
 		{"builderMgr", CommandLineArgs{builderMgr: true}, "Fission-BuilderMgr"},
 		{"storagesvc", CommandLineArgs{storageServicePort: 8000}, "Fission-StorageSvc"},
+		{"wasmhost", CommandLineArgs{wasmHostPort: 8888}, "Fission-WasmHost"},
+		{"jobrunner", CommandLineArgs{jobRunner: true}, "Fission-JobRunner"},
 		{"none selected", CommandLineArgs{}, "Fission-Unknown"},
 		// Precedence: earlier table entries win when several flags are set
 		// (matching the old early-return chain's order).
@@ -63,5 +65,5 @@ func TestBundleDispatchTableComplete(t *testing.T) {
 		assert.Falsef(t, seen[svc.name], "duplicate service name %s", svc.name)
 		seen[svc.name] = true
 	}
-	assert.Len(t, seen, 16)
+	assert.Len(t, seen, 18)
 }
@@ -21,7 +21,9 @@ import (
 	"github.com/fission/fission/pkg/mqtrigger/egress"
 	"github.com/fission/fission/pkg/mqtrigger/factory"
 	"github.com/fission/fission/pkg/mqtrigger/messageQueue"
+	_ "github.com/fission/fission/pkg/mqtrigger/messageQueue/jetstream"
 	_ "github.com/fission/fission/pkg/mqtrigger/messageQueue/kafka"
+	_ "github.com/fission/fission/pkg/mqtrigger/messageQueue/rabbitmq"
 	_ "github.com/fission/fission/pkg/mqtrigger/messageQueue/statestore"
 	"github.com/fission/fission/pkg/statestore"
 
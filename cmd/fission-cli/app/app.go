@@ -33,7 +33,9 @@ import (
 	"github.com/fission/fission/pkg/fission-cli/console"
 	"github.com/fission/fission/pkg/fission-cli/flag"
 	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
+	_ "github.com/fission/fission/pkg/mqtrigger/messageQueue/jetstream"
 	_ "github.com/fission/fission/pkg/mqtrigger/messageQueue/kafka"
+	_ "github.com/fission/fission/pkg/mqtrigger/messageQueue/rabbitmq"
 	_ "github.com/fission/fission/pkg/mqtrigger/messageQueue/statestore"
 )
 
@@ -20,6 +20,12 @@ const (
 	// ProvisionedWarming = still warming or draining; False with reason
 	// ProvisionedDisabled = provisioned concurrency off (target=0 / spec nil).
 	FunctionConditionProvisioned = "Provisioned"
+	// FunctionConditionCircuitClosed reports the router's circuit breakers
+	// for a function that opted into them (Invocation.CircuitBreaker): True
+	// while every endpoint is in rotation; False with reason EndpointsEjected
+	// while some are ejected or half-open. Written by the router replica that
+	// saw the latest transition, so it is that replica's view.
+	FunctionConditionCircuitClosed = "CircuitClosed"
 
 	// Package conditions
 	PackageConditionBuildSucceeded = "BuildSucceeded"
@@ -142,6 +148,10 @@ const (
 	FunctionReasonProvisionedDisabled  = "ProvisionedDisabled"  // provisioned concurrency off (target=0 / spec field nil)
 	FunctionReasonProvisionedClamped   = "ProvisionedClamped"   // spec.Target exceeded the namespace cap; effective target was clamped
 
+	// CircuitClosed condition reasons (router circuit breakers).
+	FunctionReasonCircuitClosed    = "AllEndpointsInRotation" // no endpoint ejected
+	FunctionReasonEndpointsEjected = "EndpointsEjected"       // some endpoints ejected or half-open
+
 	// Package condition reasons (mirror BuildStatus enum + composites)
 	PackageReasonBuildSucceeded  = "BuildSucceeded"
 	PackageReasonBuildFailed     = "BuildFailed"
@@ -164,6 +174,13 @@ const (
 	HTTPTriggerReasonFunctionNotFound     = "FunctionNotFound"     // the referenced function does not exist; the route is not served
 	HTTPTriggerReasonRouteConflict        = "RouteConflict"        // another trigger registered the same route shape and wins by precedence; this one is shadowed
 	HTTPTriggerReasonInvalidRouteTemplate = "InvalidRouteTemplate" // the path's gorilla template does not compile (capturing groups, unbalanced braces, ...)
+	HTTPTriggerReasonInvalidAuthConfig    = "InvalidAuthConfig"    // the auth policy failed URL/header-name validation; the route is not served
+	HTTPTriggerReasonInvalidRateLimit     = "InvalidRateLimit"     // the rate limit failed validation (e.g. a claim key without a jwt auth policy); the route is not served
+	HTTPTriggerReasonInvalidMirror        = "InvalidMirror"        // the traffic mirror failed validation (e.g. on a FunctionWeights reference); the route is not served
+	HTTPTriggerReasonInvalidMatch         = "InvalidMatch"         // the match block failed validation (e.g. a regex that does not compile); the route is not served
+	HTTPTriggerReasonInvalidCache         = "InvalidCache"         // the response cache failed validation (e.g. a bad Vary header name); the route is not served
+	HTTPTriggerReasonInvalidGRPC          = "InvalidGRPC"          // the grpc block failed validation (e.g. combined with relativeurl); the route is not served
+	HTTPTriggerReasonInvalidTransform     = "InvalidTransform"     // the transform failed validation (e.g. a body template that is not JSON); the route is not served
 
 	// KubernetesWatchTrigger condition reasons
 	KubernetesWatchTriggerReasonSubscribed  = "Subscribed"
@@ -187,6 +204,13 @@ const (
 	CanaryConfigReasonFailed     = "Failed"
 	CanaryConfigReasonAborted    = "Aborted"
 	CanaryConfigReasonUnknown    = "Unknown"
+	// CanaryConfigReasonPaused: Spec.Paused holds the rollout at its
+	// current weights (Progressing=False until it is resumed).
+	CanaryConfigReasonPaused = "Paused"
+	// CanaryConfigReasonHeld: the rollout is progressing but did not
+	// advance this interval — an analysis check had no data yet or a
+	// webhook gate refused the step. The message says which.
+	CanaryConfigReasonHeld = "Held"
 
 	// Workflow condition reasons
 	WorkflowReasonGraphValid   = "GraphValid"
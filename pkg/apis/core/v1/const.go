@@ -4,6 +4,8 @@
 
 package v1
 
+import "time"
+
 var (
 	MinimumKubernetesVersion = [3]int{1, 32, 0}
 )
@@ -130,12 +132,18 @@ const (
 	StreamingSSE       StreamingProtocol = "sse"
 	StreamingChunked   StreamingProtocol = "chunked"
 	StreamingWebSocket StreamingProtocol = "websocket"
+	// StreamingGRPC proxies to the function over HTTP/2 in cleartext (h2c,
+	// prior knowledge), end to end, with response trailers (grpc-status)
+	// passed through.
+	StreamingGRPC StreamingProtocol = "grpc"
 )
 
 const (
 	ExecutorTypePoolmgr   ExecutorType = "poolmgr"
 	ExecutorTypeNewdeploy ExecutorType = "newdeploy"
 	ExecutorTypeContainer ExecutorType = "container"
+	ExecutorTypeWasm      ExecutorType = "wasm"
+	ExecutorTypeJob       ExecutorType = "job"
 )
 
 // RFC-0025 function versioning modes.
@@ -144,6 +152,14 @@ const (
 	VersioningModeManual VersioningMode = "manual"
 )
 
+// Async invocation priority lanes (FunctionSpec.Invocation.Priority). The
+// empty value is the normal lane.
+const (
+	InvocationPriorityHigh   InvocationPriority = "high"
+	InvocationPriorityNormal InvocationPriority = "normal"
+	InvocationPriorityLow    InvocationPriority = "low"
+)
+
 // RFC-0023 keyed-state defaults and sticky-routing sources.
 const (
 	StickySourceHeader     StickySource = "header"
@@ -159,6 +175,99 @@ const (
 	DefaultStateMaxKeys int64 = 10000
 )
 
+// Auto provisioned concurrency defaults and bounds, applied by
+// ProvisionedAutoConfig.Effective for fields left at zero.
+const (
+	DefaultProvisionedAutoPercentile = 95
+	DefaultProvisionedAutoWindow     = "15m"
+
+	MinProvisionedAutoWindow          = time.Minute
+	MaxProvisionedAutoWindow          = time.Hour
+	MaxProvisionedAutoSeasonalityDays = 14
+)
+
+// Environment pool autoscaling defaults and bounds, applied by
+// PoolAutoscaleConfig.Effective for fields left at zero.
+const (
+	DefaultPoolAutoscaleTargetIdle     = 1
+	DefaultPoolAutoscaleWindow         = "1m"
+	DefaultPoolAutoscaleScaleDownDelay = "5m"
+
+	MinPoolAutoscaleWindow         = 10 * time.Second
+	MaxPoolAutoscaleWindow         = 15 * time.Minute
+	MaxPoolAutoscaleScaleDownDelay = time.Hour
+)
+
+// Concurrency scaling defaults and bounds, applied by
+// ConcurrencyScalingConfig.Effective for fields left at zero.
+const (
+	DefaultConcurrencyStabilizationWindow = "5m"
+	MaxConcurrencyStabilizationWindow     = time.Hour
+)
+
+// Scale-to-zero defaults and bounds, applied by ScaleToZeroConfig.Effective
+// for fields left at zero.
+const (
+	DefaultScaleToZeroQueueSize          = 100
+	DefaultScaleToZeroQueueTimeout       = "1m"
+	DefaultScaleToZeroRequestsPerReplica = 10
+
+	MaxScaleToZeroQueueSize    = 10000
+	MinScaleToZeroQueueTimeout = time.Second
+	MaxScaleToZeroQueueTimeout = 10 * time.Minute
+)
+
+// WebAssembly executor defaults and bounds, applied by WasmConfig.Effective
+// for fields left at zero. Memory is in bytes; a wasm page is 64KiB.
+const (
+	DefaultWasmMaxMemory        = "64Mi"
+	DefaultWasmMaxExecutionTime = "30s"
+
+	WasmPageSize            = 65536
+	MaxWasmMaxMemory        = 65536 * WasmPageSize
+	MinWasmMaxExecutionTime = time.Millisecond
+	MaxWasmMaxExecutionTime = 10 * time.Minute
+)
+
+// Job executor defaults and bounds, applied by JobConfig.Effective for
+// fields left at zero.
+const (
+	DefaultJobResultTTL = "24h"
+
+	MaxJobBackoffLimit   = 10
+	MinJobResultTTL      = time.Minute
+	MaxJobResultTTL      = 30 * 24 * time.Hour
+	MinJobActiveDeadline = time.Minute
+	MaxJobActiveDeadline = 7 * 24 * time.Hour
+)
+
+// Router circuit breaker defaults, applied by CircuitBreakerConfig.Effective
+// for fields left at zero.
+const (
+	DefaultCircuitBreakerConsecutiveFailures int32 = 5
+	DefaultCircuitBreakerMinRequests         int32 = 20
+	DefaultCircuitBreakerIntervalSeconds     int32 = 30
+	DefaultCircuitBreakerEjectionSeconds     int32 = 30
+	DefaultCircuitBreakerHalfOpenRequests    int32 = 1
+	DefaultCircuitBreakerMaxEjectionPercent  int32 = 50
+)
+
+// DefaultHTTPTriggerCacheMaxObjectBytes is the largest response body an
+// HTTPTriggerCache that leaves MaxObjectBytes unset stores, and
+// MaxHTTPTriggerCacheObjectBytes the largest it may be set to.
+const (
+	DefaultHTTPTriggerCacheMaxObjectBytes int64 = 1 << 20
+	MaxHTTPTriggerCacheObjectBytes        int64 = 8 << 20
+)
+
+// MaxHTTPTransformRequestBytes is the largest request body the router reads
+// to expand an HTTPRequestTransform body template.
+const MaxHTTPTransformRequestBytes int64 = 1 << 20
+
+// DefaultTrafficMirrorPercent is the share of requests copied by a
+// TrafficMirror that leaves Percent unset.
+const DefaultTrafficMirrorPercent int32 = 100
+
 const (
 	StrategyTypeExecution = "execution"
 )
@@ -182,6 +291,13 @@ const (
 	// MessageQueueTypeStatestore is the RFC-0027 built-in provider: topics are
 	// EventLog streams on the RFC-0021 statestore — no external broker.
 	MessageQueueTypeStatestore = "statestore"
+	// MessageQueueTypeNatsJetStream is the NATS JetStream provider; the same
+	// type name the KEDA nats-jetstream scaler uses, so a trigger can move
+	// between the classic and keda kinds without renaming its type.
+	MessageQueueTypeNatsJetStream = "nats-jetstream"
+	// MessageQueueTypeRabbitMQ is the AMQP 0-9-1 (RabbitMQ) provider; like
+	// nats-jetstream, it shares its name with the KEDA scaler.
+	MessageQueueTypeRabbitMQ = "rabbitmq"
 )
 
 const (
@@ -210,6 +326,10 @@ const (
 
 	// set a max number for iterations to prevent infinite processing of canary config
 	MaxIterationsForCanaryConfig = 10
+
+	// DefaultCanaryWebhookTimeoutSeconds bounds a CanaryWebhook call whose
+	// TimeoutSeconds is unset.
+	DefaultCanaryWebhookTimeoutSeconds int32 = 10
 )
 
 const (
@@ -238,6 +358,12 @@ const (
 	// hash); the pod reconciler routes warm pods on this label. Absent on
 	// pods of plain (fetcher-based) pools.
 	POOL_OCI_IMAGE_HASH = "ociImageHash"
+	// POOL_RESOURCE_CLASS marks the deployments and pods of an
+	// environment's resource class pools with the class name. Pools are
+	// keyed per (env UID, class, image hash) and the pod reconciler routes
+	// warm pods on it like POOL_OCI_IMAGE_HASH. Absent on the pods of the
+	// environment's default pool.
+	POOL_RESOURCE_CLASS = "poolResourceClass"
 
 	// RFC-0002 EndpointSlice-native data plane labels.
 	//
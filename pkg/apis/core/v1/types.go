@@ -30,6 +30,82 @@ const (
 	RouteProviderGateway RouteProviderType = "gateway"
 )
 
+// HTTPTriggerAuthType selects how the router authenticates requests to one
+// HTTPTrigger. It is the type of HTTPTriggerAuth.Type; the allowed values are
+// the constants below (also enforced by the field's kubebuilder Enum marker).
+type HTTPTriggerAuthType string
+
+const (
+	// HTTPTriggerAuthAnonymous admits every request; it records that the
+	// trigger is deliberately public.
+	HTTPTriggerAuthAnonymous HTTPTriggerAuthType = "anonymous"
+	// HTTPTriggerAuthJWT requires a bearer JWT signed by a key in the issuer's
+	// JWKS.
+	HTTPTriggerAuthJWT HTTPTriggerAuthType = "jwt"
+	// HTTPTriggerAuthAPIKey requires an API key listed in a Secret.
+	HTTPTriggerAuthAPIKey HTTPTriggerAuthType = "apiKey"
+
+	// HTTPTriggerAuthHeaderPrefix prefixes every header the router sets from
+	// a verified identity; client-supplied headers with it are stripped on
+	// routes with an Auth policy.
+	HTTPTriggerAuthHeaderPrefix = "X-Fission-Auth-"
+	// HTTPTriggerAuthSubjectHeader carries the verified subject: the JWT
+	// "sub" claim, or the name of the matched API key's Secret entry.
+	HTTPTriggerAuthSubjectHeader = HTTPTriggerAuthHeaderPrefix + "Subject"
+)
+
+// HTTPTriggerRateLimitKeySource selects what an HTTPTrigger rate limit counts
+// requests by. It is the type of HTTPTriggerRateLimitKey.Source; the allowed
+// values are the constants below (also enforced by the field's kubebuilder
+// Enum marker).
+type HTTPTriggerRateLimitKeySource string
+
+const (
+	// RateLimitKeyTrigger shares one bucket across every caller of the
+	// trigger.
+	RateLimitKeyTrigger HTTPTriggerRateLimitKeySource = "trigger"
+	// RateLimitKeyIP gives each client IP address its own bucket.
+	RateLimitKeyIP HTTPTriggerRateLimitKeySource = "ip"
+	// RateLimitKeyHeader gives each value of a request header its own bucket.
+	RateLimitKeyHeader HTTPTriggerRateLimitKeySource = "header"
+	// RateLimitKeyClaim gives each value of a verified JWT claim its own
+	// bucket; it requires a jwt Auth policy on the trigger.
+	RateLimitKeyClaim HTTPTriggerRateLimitKeySource = "claim"
+)
+
+// HTTPMatchType selects how an HTTPMatchCondition compares a request header
+// or query parameter. It is the type of HTTPMatchCondition.Type; the allowed
+// values are the constants below (also enforced by the field's kubebuilder
+// Enum marker).
+type HTTPMatchType string
+
+const (
+	// HTTPMatchExact matches when some value of the header or parameter
+	// equals Value. It is the default.
+	HTTPMatchExact HTTPMatchType = "exact"
+	// HTTPMatchRegex matches when some value matches Value, an RE2 regular
+	// expression anchored at both ends.
+	HTTPMatchRegex HTTPMatchType = "regex"
+	// HTTPMatchPresent matches when the header or parameter is present,
+	// whatever its value; Value must be empty.
+	HTTPMatchPresent HTTPMatchType = "present"
+)
+
+// CanaryWebhookFailurePolicy selects what a canary rollout does when a
+// webhook gate refuses a step (or cannot be reached). It is the type of
+// CanaryWebhook.FailurePolicy; the allowed values are the constants below
+// (also enforced by the field's kubebuilder Enum marker).
+type CanaryWebhookFailurePolicy string
+
+const (
+	// CanaryWebhookHold keeps the rollout at its current weights and asks
+	// the gate again next interval. It is the default.
+	CanaryWebhookHold CanaryWebhookFailurePolicy = "Hold"
+	// CanaryWebhookRollback rolls all traffic back to the old target and
+	// fails the rollout.
+	CanaryWebhookRollback CanaryWebhookFailurePolicy = "Rollback"
+)
+
 // Workflow state kinds (RFC-0022). The enum marker on WorkflowStateType must
 // list exactly these values; both grow together as later phases add
 // Parallel/Map/Wait.
@@ -831,6 +907,7 @@ type (
 	// +kubebuilder:validation:XValidation:rule="(has(self.version) && self.version != '') != (has(self.packageDigest) && self.packageDigest != '')",message="exactly one of version and packageDigest must be set"
 	// +kubebuilder:validation:XValidation:rule="!has(self.weight) || (has(self.secondaryVersion) && self.secondaryVersion != '')",message="weight requires secondaryVersion"
 	// +kubebuilder:validation:XValidation:rule="!has(self.secondaryVersion) || self.secondaryVersion == '' || !has(self.version) || self.secondaryVersion != self.version",message="secondaryVersion must differ from version"
+	// +kubebuilder:validation:XValidation:rule="!has(self.mirror) || ((!has(self.version) || self.mirror.version != self.version) && (!has(self.secondaryVersion) || self.mirror.version != self.secondaryVersion))",message="mirror.version must differ from version and secondaryVersion"
 	FunctionAliasSpec struct {
 		// +kubebuilder:validation:MaxLength=63
 		FunctionName string `json:"functionName"`
@@ -850,6 +927,31 @@ type (
 		// SecondaryVersion receives 100-Weight. Name-pinned only.
 		// +optional
 		SecondaryVersion string `json:"secondaryVersion,omitempty"`
+		// Mirror copies a share of the alias's traffic to a shadow
+		// FunctionVersion, on every route that resolves through the alias.
+		// +optional
+		Mirror *TrafficMirror `json:"mirror,omitempty"`
+	}
+
+	// TrafficMirror shadows live traffic onto a FunctionVersion: the router
+	// copies Percent of the synchronous requests it serves to Version in the
+	// background and discards the shadow's response, recording how its status
+	// and latency compare with the primary's. The client only ever sees the
+	// primary response, and the shadow never slows it down: copies are dropped
+	// (and counted) rather than queued when the router is busy, and requests
+	// with a body over 1 MiB or an upgrade are never copied. The shadow runs
+	// real code against real dependencies, so it should be safe to invoke
+	// twice.
+	TrafficMirror struct {
+		// Version is the FunctionVersion name the copies are sent to. It must
+		// belong to the same function.
+		// +kubebuilder:validation:MaxLength=63
+		Version string `json:"version"`
+		// Percent (1-100) of requests that are copied. Defaults to 100.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=100
+		// +optional
+		Percent int32 `json:"percent,omitempty"`
 	}
 
 	// AliasTargetRecord is one entry in FunctionAliasStatus.History: a
@@ -1159,7 +1261,7 @@ type (
 	// +kubebuilder:validation:XValidation:rule="!(has(self.InvokeStrategy.ExecutionStrategy) && (self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'newdeploy' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'container')) || !has(self.InvokeStrategy.ExecutionStrategy.MaxScale) || self.InvokeStrategy.ExecutionStrategy.MaxScale >= (has(self.InvokeStrategy.ExecutionStrategy.MinScale) ? self.InvokeStrategy.ExecutionStrategy.MinScale : 0)",message="maximum scale must be greater than or equal to minimum scale for newdeploy/container executors"
 	// +kubebuilder:validation:XValidation:rule="!(has(self.InvokeStrategy.ExecutionStrategy) && (self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'newdeploy' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'container')) || !has(self.InvokeStrategy.ExecutionStrategy.TargetCPUPercent) || (self.InvokeStrategy.ExecutionStrategy.TargetCPUPercent >= 0 && self.InvokeStrategy.ExecutionStrategy.TargetCPUPercent <= 100)",message="TargetCPUPercent must be a value between 0 and 100 for newdeploy/container executors"
 	// +kubebuilder:validation:XValidation:rule="!has(self.InvokeStrategy.StrategyType) || self.InvokeStrategy.StrategyType == '' || self.InvokeStrategy.StrategyType == 'execution'",message="InvokeStrategy.StrategyType must be 'execution'"
-	// +kubebuilder:validation:XValidation:rule="!has(self.InvokeStrategy.ExecutionStrategy) || !has(self.InvokeStrategy.ExecutionStrategy.ExecutorType) || self.InvokeStrategy.ExecutionStrategy.ExecutorType == '' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'poolmgr' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'newdeploy' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'container'",message="ExecutionStrategy.ExecutorType must be one of poolmgr, newdeploy, container"
+	// +kubebuilder:validation:XValidation:rule="!has(self.InvokeStrategy.ExecutionStrategy) || !has(self.InvokeStrategy.ExecutionStrategy.ExecutorType) || self.InvokeStrategy.ExecutionStrategy.ExecutorType == '' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'poolmgr' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'newdeploy' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'container' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'wasm' || self.InvokeStrategy.ExecutionStrategy.ExecutorType == 'job'",message="ExecutionStrategy.ExecutorType must be one of poolmgr, newdeploy, container, wasm, job"
 	// Bounded podspec safety rules — CEL admission gate for the simple pod-level
 	// invariants. Per-container SecurityContext checks stay in the webhook
 	// (ValidatePodSpecSafety) because iterating containers exceeds the CEL cost
@@ -1234,6 +1336,12 @@ type (
 		// +optional
 		Resources apiv1.ResourceRequirements `json:"resources"`
 
+		// ResourceClass picks which of the environment's resource classes a
+		// poolmgr function runs in. (Optional) defaults to the environment's
+		// default pool.
+		// +optional
+		ResourceClass string `json:"resourceClass,omitempty"`
+
 		// InvokeStrategy is a set of controls which affect how function executes
 		InvokeStrategy InvokeStrategy `json:"InvokeStrategy"`
 
@@ -1277,8 +1385,9 @@ type (
 		// Invocation, when non-nil, tunes RFC-0024 asynchronous invocation
 		// (X-Fission-Invoke-Mode: async) for this function: the durable retry policy
 		// and the maximum event age before an undelivered invocation is
-		// dead-lettered. A function without it still accepts async mode with platform
-		// defaults; this field only tunes them. Additive and backward compatible.
+		// dead-lettered, and opts it into the router's circuit breaking. A function
+		// without it still accepts async mode with platform defaults; this field only
+		// tunes them. Additive and backward compatible.
 		// +optional
 		Invocation *InvocationConfig `json:"invocation,omitempty"`
 
@@ -1326,7 +1435,7 @@ type (
 	}
 
 	// StreamingProtocol selects how the router treats the upstream response.
-	// +kubebuilder:validation:Enum=auto;sse;chunked;websocket
+	// +kubebuilder:validation:Enum=auto;sse;chunked;websocket;grpc
 	StreamingProtocol string
 
 	// StreamingConfig controls the router's streaming behavior for a function.
@@ -1459,7 +1568,8 @@ type (
 		Name string `json:"name"`
 	}
 
-	// InvocationConfig tunes RFC-0024 asynchronous invocation for a function.
+	// InvocationConfig tunes RFC-0024 asynchronous invocation for a function,
+	// and the router's circuit breaker for every invocation of it.
 	// Presence of the enclosing FunctionSpec.Invocation is optional — a function
 	// without it still accepts async mode (X-Fission-Invoke-Mode: async) with
 	// platform defaults; this struct only tunes them. Field bounds are validated in
@@ -1488,8 +1598,91 @@ type (
 		// spent, or MaxAge exceeded).
 		// +optional
 		OnFailure *DestinationRef `json:"onFailure,omitempty"`
+
+		// Priority is the default async priority lane for this function's
+		// invocations. A request's X-Fission-Invoke-Priority header overrides it.
+		// Each lane is its own statestore queue and the dispatcher polls them
+		// weighted-fair, so a bulk backfill on "low" cannot delay latency-sensitive
+		// work on "high", and "low" still drains while "high" is busy. Empty means
+		// "normal", the lane every invocation used before lanes existed.
+		// +optional
+		Priority InvocationPriority `json:"priority,omitempty"`
+
+		// CircuitBreaker, when non-nil, opts the function into per-endpoint
+		// circuit breaking in the router: an endpoint (specialized pod) that
+		// keeps failing is ejected from the router's endpoint index for a
+		// while, then probed before it takes full traffic again. Applies to
+		// synchronous and asynchronous invocations alike. nil (the default)
+		// leaves a failing pod in rotation, as before.
+		// +optional
+		CircuitBreaker *CircuitBreakerConfig `json:"circuitBreaker,omitempty"`
 	}
 
+	// CircuitBreakerConfig configures the router's per-endpoint circuit
+	// breaker. A failure is a 5xx response or a connection that fails after it
+	// was established (dial failures already quarantine the endpoint on their
+	// own). Each router replica keeps its own breakers: an endpoint is ejected
+	// from the replicas that saw it fail. Only endpoints the router dials
+	// directly from its EndpointSlice index (poolmgr pods; newdeploy and
+	// container pods with endpoint load balancing on) can be ejected.
+	CircuitBreakerConfig struct {
+		// ConsecutiveFailures opens the breaker after this many failures in a
+		// row. 0 means the default (5).
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+		// ErrorRatePercent opens the breaker when at least this percentage of
+		// the requests in one IntervalSeconds window fail, once the window
+		// holds MinRequests requests. 0 (the default) disables the error-rate
+		// check, leaving ConsecutiveFailures as the only trigger.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=100
+		ErrorRatePercent int32 `json:"errorRatePercent,omitempty"`
+
+		// MinRequests is the number of requests a window needs before
+		// ErrorRatePercent is evaluated. 0 means the default (20).
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		MinRequests int32 `json:"minRequests,omitempty"`
+
+		// IntervalSeconds is the length of the error-rate window. 0 means the
+		// default (30).
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=3600
+		IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+
+		// EjectionSeconds is how long an endpoint whose breaker opened stays
+		// out of rotation before it is probed. 0 means the default (30).
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=3600
+		EjectionSeconds int32 `json:"ejectionSeconds,omitempty"`
+
+		// HalfOpenRequests is how many probe requests a half-open endpoint
+		// takes at a time; that many consecutive successes close the breaker,
+		// any failure reopens it. 0 means the default (1).
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		HalfOpenRequests int32 `json:"halfOpenRequests,omitempty"`
+
+		// MaxEjectionPercent caps the share of a function's ready endpoints
+		// that may be ejected at once, so a fault every pod shares (a bad
+		// deploy, a broken dependency) cannot empty the pool and turn errors
+		// into a cold-start storm. A breaker that would exceed the cap stays
+		// closed. 0 means the default (50); 100 allows ejecting every endpoint.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=100
+		MaxEjectionPercent int32 `json:"maxEjectionPercent,omitempty"`
+	}
+
+	// InvocationPriority selects an async invocation priority lane.
+	// +kubebuilder:validation:Enum=high;normal;low
+	InvocationPriority string
+
 	// DestinationRef routes an async invocation's result to exactly one target: a
 	// Function (invoked async through the same machinery, depth-capped) or a Topic
 	// (published to a message queue). Exactly one of Function/Topic must be set.
@@ -1573,6 +1766,64 @@ type (
 		// +listMapKey=name
 		// +kubebuilder:validation:MaxItems=32
 		Windows []ProvisionedWindow `json:"windows,omitempty"`
+
+		// Auto derives the target from the concurrency the function is
+		// observed to serve instead of fixing it at Target. Target stays in
+		// effect until Auto.Window of history has been observed (after an
+		// executor restart too), and an open schedule window still overrides
+		// the derived target.
+		// +optional
+		Auto *ProvisionedAutoConfig `json:"auto,omitempty"`
+	}
+
+	// ProvisionedAutoConfig sizes the provisioned target from traffic
+	// history. Concurrency is the number of requests in flight to the
+	// function, as the routers report it in their taps and as the executor
+	// counts the requests it resolves; it is sampled as the peak of every
+	// 10 seconds. The target is the Percentile of those samples over Window,
+	// or, with seasonality, of the same time of day on the previous days
+	// when that is higher, plus HeadroomPercent, in pods
+	// (RequestsPerPod requests each) and bounded by Min and Max.
+	ProvisionedAutoConfig struct {
+		// Percentile of the samples the target covers, 1 to 100. Defaults
+		// to 95.
+		// +optional
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=100
+		Percentile int `json:"percentile,omitempty"`
+
+		// Window is the sliding window of recent samples, from 1m to 1h.
+		// Format is Go time.ParseDuration. Defaults to 15m.
+		// +optional
+		Window string `json:"window,omitempty"`
+
+		// HeadroomPercent is added to the observed concurrency, 0 to 400.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=400
+		HeadroomPercent int `json:"headroomPercent,omitempty"`
+
+		// Min is the lowest target. 0 (the default) lets an idle function
+		// drain every provisioned pod.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		Min int `json:"min,omitempty"`
+
+		// Max is the highest target. 0 (the default) leaves only the
+		// namespace cap (executor.provisionedConcurrency.maxPerFunction).
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		Max int `json:"max,omitempty"`
+
+		// SeasonalityDays is how many previous days the time-of-day pattern
+		// is learned from, 0 to 14; 0 (the default) disables it. With it the
+		// target also covers the concurrency seen on those days in the
+		// coming 15 minutes, so pods are warm before a daily peak rather
+		// than after it.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=14
+		SeasonalityDays int `json:"seasonalityDays,omitempty"`
 	}
 
 	// ProvisionedWindow describes a schedule window that overrides the base
@@ -1649,6 +1900,8 @@ type (
 		//  - poolmgr
 		//  - newdeploy
 		//  - container
+		//  - wasm
+		//  - job
 		// +optional
 		ExecutorType ExecutorType `json:"ExecutorType"`
 
@@ -1680,6 +1933,128 @@ type (
 		// Applicable for executor type newdeploy and container.
 		// +optional
 		Behavior *asv2.HorizontalPodAutoscalerBehavior `json:"hpaBehavior,omitempty"`
+
+		// ConcurrencyScaling adds a concurrency target to the HPA metrics:
+		// the function keeps at least the replicas its in-flight requests
+		// need, as the routers report them.
+		// Applicable for executor type newdeploy and container.
+		// +optional
+		ConcurrencyScaling *ConcurrencyScalingConfig `json:"concurrencyScaling,omitempty"`
+
+		// ScaleToZero scales the function's deployment to zero replicas once
+		// it is idle, whatever MinScale, and has the routers buffer its
+		// requests while it scales back up.
+		// Applicable for executor type newdeploy and container.
+		// +optional
+		ScaleToZero *ScaleToZeroConfig `json:"scaleToZero,omitempty"`
+
+		// Wasm sets the per-invocation limits of a WebAssembly function.
+		// Applicable for executor type wasm.
+		// +optional
+		Wasm *WasmConfig `json:"wasm,omitempty"`
+
+		// Job sets the retries, deadline and result retention of a function
+		// whose invocations run as Kubernetes Jobs.
+		// Applicable for executor type job.
+		// +optional
+		Job *JobConfig `json:"job,omitempty"`
+	}
+
+	// ConcurrencyScalingConfig scales a function on its in-flight requests,
+	// which suits I/O-bound functions that CPU does not describe. The
+	// executor sums the in-flight counts the routers report in their taps
+	// and keeps the HPA's minimum replicas at that sum over
+	// TargetPerReplica, between MinScale and MaxScale. The HPA still scales
+	// further on its metrics. A higher count applies at once; a lower one
+	// only once it has been the highest over StabilizationWindow.
+	ConcurrencyScalingConfig struct {
+		// TargetPerReplica is the number of in-flight requests one replica
+		// should serve.
+		// +kubebuilder:validation:Minimum=1
+		TargetPerReplica int `json:"targetPerReplica"`
+
+		// StabilizationWindow is how long the replica count holds after the
+		// concurrency drops, from 0 to 1h. Format is Go time.ParseDuration.
+		// Defaults to 5m.
+		// +optional
+		StabilizationWindow string `json:"stabilizationWindow,omitempty"`
+	}
+
+	// ScaleToZeroConfig is the activator mode of a function. Once the
+	// function has been idle for its IdleTimeout, the executor scales its
+	// deployment to zero replicas and keeps the deployment, service and HPA.
+	// A router then buffers the function's requests until a replica is
+	// ready, and asks the executor for one replica per RequestsPerReplica
+	// buffered requests, between MinScale and MaxScale. MinScale applies
+	// again once the function is scaled up.
+	//
+	// Buffering needs the router's EndpointSlice cache, which tells it the
+	// function has no ready endpoint; without it the router waits on the
+	// executor as for any cold start.
+	ScaleToZeroConfig struct {
+		// QueueSize is the number of requests one router buffers for the
+		// function while it scales from zero, from 1 to 10000. Requests
+		// beyond it are rejected with 429. Defaults to 100.
+		// +optional
+		QueueSize int `json:"queueSize,omitempty"`
+
+		// QueueTimeout is how long a buffered request waits for a replica,
+		// from 1s to 10m. Format is Go time.ParseDuration. Defaults to 1m.
+		// +optional
+		QueueTimeout string `json:"queueTimeout,omitempty"`
+
+		// RequestsPerReplica is the number of buffered requests one replica
+		// is started for. Defaults to 10.
+		// +optional
+		RequestsPerReplica int `json:"requestsPerReplica,omitempty"`
+	}
+
+	// WasmConfig limits the invocations of a function run by the wasm
+	// executor. The function's package holds a WASI (preview1) command
+	// module, which a shared host pod in the function namespace compiles
+	// once and instantiates afresh for every request. The host maps the
+	// request onto the module CGI style: the method, path, query and
+	// headers as environment variables, the body as stdin, and a response
+	// of headers, a blank line and the body read from stdout.
+	WasmConfig struct {
+		// MaxMemory is the linear memory one invocation may grow to, from
+		// 64Ki (one page) to 4Gi. Defaults to 64Mi.
+		// +optional
+		MaxMemory *resource.Quantity `json:"maxMemory,omitempty"`
+
+		// MaxExecutionTime is how long one invocation may run before the
+		// host stops it and answers 504, from 1ms to 10m. Format is Go
+		// time.ParseDuration. Defaults to 30s.
+		// +optional
+		MaxExecutionTime string `json:"maxExecutionTime,omitempty"`
+	}
+
+	// JobConfig configures a function run by the job executor. Every
+	// invocation of such a function, sync or async, is a Kubernetes Job
+	// built from the function's environment and package: the router
+	// answers 202 with an invocation id straight away, the Job posts the
+	// request body to the specialized runtime once, and the router records
+	// the runtime's response and output when the Job finishes and fires the
+	// function's OnSuccess/OnFailure destinations. FunctionTimeout does
+	// not apply; ActiveDeadline bounds the whole Job instead.
+	JobConfig struct {
+		// BackoffLimit is how many times a failed Job pod is retried before
+		// the invocation fails, from 0 to 10. Defaults to 0.
+		// +optional
+		BackoffLimit int32 `json:"backoffLimit,omitempty"`
+
+		// ResultTTL is how long an invocation's status and result are kept
+		// after its Job finishes, from 1m to 720h. Format is Go
+		// time.ParseDuration. Defaults to 24h.
+		// +optional
+		ResultTTL string `json:"resultTTL,omitempty"`
+
+		// ActiveDeadline is how long an invocation's Job may run, retries
+		// included, before it is stopped and the invocation fails, from 1m
+		// to 168h. Format is Go time.ParseDuration. Unset, the Job runs
+		// until it finishes.
+		// +optional
+		ActiveDeadline string `json:"activeDeadline,omitempty"`
 	}
 
 	// FunctionReferenceType refers to type of Function
@@ -1888,6 +2263,21 @@ type (
 		// +kubebuilder:validation:Minimum=0
 		Poolsize int `json:"poolsize,omitempty"`
 
+		// PoolAutoscale sizes the pre-warm pool from the environment's recent
+		// specialization rate instead of the static Poolsize.
+		// (Optional) defaults to the static Poolsize.
+		// +optional
+		PoolAutoscale *PoolAutoscaleConfig `json:"poolAutoscale,omitempty"`
+
+		// ResourceClasses are extra pre-warm pools of the environment, each
+		// with its own resources and node placement. A poolmgr function picks
+		// one with FunctionSpec.ResourceClass; functions that pick none run in
+		// the environment's default pool.
+		// +optional
+		// +listType=map
+		// +listMapKey=name
+		ResourceClasses []ResourceClass `json:"resourceClasses,omitempty"`
+
 		// The grace time for pod to perform connection draining before termination. The unit is in seconds.
 		// A terminating function pod keeps serving for the WHOLE grace window
 		// (the preStop hook sleeps through it, then the kubelet kills the pod),
@@ -1925,6 +2315,79 @@ type (
 		// +optional
 		ImagePullSecret string `json:"imagepullsecret"`
 	}
+
+	// PoolAutoscaleConfig sizes an environment's pre-warm pool from demand.
+	// Every specialization consumes a warm pod that the pool then has to
+	// replace, so the pool holds the specializations of the last Window plus
+	// TargetIdle, bounded by MinSize and MaxSize. It grows as soon as that
+	// demand is seen and shrinks one pod at a time once the demand has stayed
+	// lower for ScaleDownDelay. Only version 3 environments with single
+	// functions per container can autoscale their pool.
+	PoolAutoscaleConfig struct {
+		// MinSize is the smallest pool.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		MinSize int `json:"minSize,omitempty"`
+
+		// MaxSize is the largest pool. It must be at least 1 and MinSize.
+		// +kubebuilder:validation:Minimum=1
+		MaxSize int `json:"maxSize"`
+
+		// TargetIdle is how many warm pods are kept on top of the recent
+		// demand. Defaults to 1.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		TargetIdle int `json:"targetIdle,omitempty"`
+
+		// Window is how far back specializations are counted, from 10s to
+		// 15m. It should cover the time a new pool pod takes to become ready.
+		// Format is Go time.ParseDuration. Defaults to 1m.
+		// +optional
+		Window string `json:"window,omitempty"`
+
+		// ScaleDownDelay is how long the demand must stay below the pool
+		// size before the pool shrinks, from 0 to 1h. Format is Go
+		// time.ParseDuration. Defaults to 5m.
+		// +optional
+		ScaleDownDelay string `json:"scaleDownDelay,omitempty"`
+	}
+
+	// ResourceClass is a named pre-warm pool of an environment. Its pods run
+	// the environment's runtime with the class's resources and are placed by
+	// its node selector and tolerations, so a function that needs more memory
+	// or particular nodes does not force a bigger pool on every other function
+	// of the environment.
+	ResourceClass struct {
+		// Name is what FunctionSpec.ResourceClass refers to. It must be a
+		// DNS label.
+		// +kubebuilder:validation:MaxLength=63
+		// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+		Name string `json:"name"`
+
+		// Resources replace the environment's Resources for the pods of this
+		// class.
+		// (Optional) defaults to the environment's Resources.
+		// +optional
+		Resources apiv1.ResourceRequirements `json:"resources,omitempty"`
+
+		// Poolsize is the number of warm pods kept for this class; the
+		// environment's PoolAutoscale does not apply to class pools.
+		// (Optional) defaults to 1.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		Poolsize int `json:"poolsize,omitempty"`
+
+		// NodeSelector is merged into the node selector of the pods of this
+		// class.
+		// +optional
+		NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+		// Tolerations are appended to the tolerations of the pods of this
+		// class.
+		// +optional
+		Tolerations []apiv1.Toleration `json:"tolerations,omitempty"`
+	}
+
 	// AllowedFunctionsPerContainer defaults to 'single'. Related to Fission Workflows
 	AllowedFunctionsPerContainer string
 
@@ -1933,9 +2396,13 @@ type (
 	//
 
 	// HTTPTriggerSpec is for router to expose user functions at the given URL path.
-	// +kubebuilder:validation:XValidation:rule="self.relativeurl != '' || (has(self.prefix) && self.prefix != '')",message="HTTPTriggerSpec: at least one of relativeurl or prefix must be set"
+	// +kubebuilder:validation:XValidation:rule="self.relativeurl != '' || (has(self.prefix) && self.prefix != '') || has(self.grpc)",message="HTTPTriggerSpec: at least one of relativeurl, prefix or grpc must be set"
 	// +kubebuilder:validation:XValidation:rule="self.relativeurl == '' || (self.relativeurl.startsWith('/') && self.relativeurl != '/' && !self.relativeurl.matches('(^|/)[.][.](/|$)') && !(self.relativeurl in ['/router-healthz','/readyz','/_version','/auth/login']) && !self.relativeurl.startsWith('/fission-function/'))",message="HTTPTriggerSpec.relativeurl must start with '/', not be '/', not contain '..' path segments, not collide with a router-owned path (/router-healthz, /readyz, /_version, /auth/login), and not start with /fission-function/"
 	// +kubebuilder:validation:XValidation:rule="!has(self.prefix) || self.prefix == '' || (self.prefix.startsWith('/') && self.prefix != '/' && !self.prefix.matches('(^|/)[.][.](/|$)') && !(self.prefix in ['/router-healthz','/readyz','/_version','/auth/login']) && !self.prefix.startsWith('/fission-function/'))",message="HTTPTriggerSpec.prefix must start with '/', not be '/', not contain '..' path segments, not collide with a router-owned path (/router-healthz, /readyz, /_version, /auth/login), and not start with /fission-function/"
+	// +kubebuilder:validation:XValidation:rule="!has(self.rateLimit) || !has(self.rateLimit.key) || !has(self.rateLimit.key.source) || self.rateLimit.key.source != 'claim' || (has(self.auth) && self.auth.type == 'jwt')",message="rateLimit.key.source 'claim' requires an auth policy of type 'jwt'"
+	// +kubebuilder:validation:XValidation:rule="!has(self.mirror) || self.functionref.type == 'name'",message="mirror is only valid when functionref.type is 'name'"
+	// +kubebuilder:validation:XValidation:rule="!has(self.match) || !has(self.match.hosts) || size(self.match.hosts) == 0 || !has(self.host) || self.host == ''",message="match.hosts cannot be combined with host"
+	// +kubebuilder:validation:XValidation:rule="!has(self.grpc) || (self.relativeurl == '' && (!has(self.prefix) || self.prefix == ''))",message="grpc cannot be combined with relativeurl or prefix"
 	HTTPTriggerSpec struct {
 		// TODO: remove this field since we have IngressConfig already
 		// Deprecated: the original idea of this field is not for setting Ingress.
@@ -2014,6 +2481,462 @@ type (
 		// call this trigger cross-origin.
 		// +optional
 		CorsConfig *HTTPTriggerCorsConfig `json:"corsConfig,omitempty"`
+
+		// Auth is the trigger's authentication policy, enforced by the router
+		// on this route before the request reaches the function. When nil the
+		// route is governed only by the cluster-wide router auth switch
+		// (authentication.enabled); when set it is enforced in addition to
+		// that switch, never instead of it.
+		// +optional
+		Auth *HTTPTriggerAuth `json:"auth,omitempty"`
+
+		// RateLimit caps the request rate the router admits to this trigger,
+		// per trigger or per client. Requests over the limit are answered 429
+		// with Retry-After and never reach the function. Nil means unlimited.
+		// +optional
+		RateLimit *HTTPTriggerRateLimit `json:"rateLimit,omitempty"`
+
+		// Mirror copies a share of this trigger's requests to a shadow
+		// FunctionVersion of the referenced function. Only valid with a
+		// functionref of type name; it takes precedence over a Mirror on the
+		// FunctionAlias the trigger references.
+		// +optional
+		Mirror *TrafficMirror `json:"mirror,omitempty"`
+
+		// Match narrows the requests this trigger serves beyond its path and
+		// methods, by header, query parameter and host. Triggers may share a
+		// path and differ only in Match (X-Api-Version: 2 to one function,
+		// everything else to another); the router tries the more specific
+		// match first. Nil matches every request on the path.
+		// +optional
+		Match *HTTPTriggerMatch `json:"match,omitempty"`
+
+		// Cache lets the router answer repeated GETs from its own response
+		// cache instead of invoking the function. Only for functions whose
+		// GET responses depend on nothing but the URL and the headers named
+		// in Vary. Nil disables caching.
+		// +optional
+		Cache *HTTPTriggerCache `json:"cache,omitempty"`
+
+		// GRPC routes the calls of a gRPC service to the function in place of
+		// RelativeURL and Prefix: the trigger serves POST /<service>/<method>
+		// and keeps the path when it forwards the call. The function should
+		// set Streaming.Protocol to grpc, so the router speaks HTTP/2 to it.
+		// +optional
+		GRPC *HTTPTriggerGRPC `json:"grpc,omitempty"`
+
+		// Transform edits requests on their way to the function and
+		// responses on their way back: headers added, set or removed, the
+		// forwarded path rebuilt from a template, the request body replaced
+		// by a JSON template. It stands in for the small adapter functions
+		// otherwise deployed in front of a function for the same job.
+		// +optional
+		Transform *HTTPTriggerTransform `json:"transform,omitempty"`
+
+		// OpenAPI describes the trigger's operations in the OpenAPI document
+		// generated for its namespace (`fission httptrigger openapi`). The
+		// document is built without it from the trigger's path, methods and
+		// CORS settings and the function's Tool description and input
+		// schema; this block adds what those cannot say.
+		// +optional
+		OpenAPI *HTTPTriggerOpenAPI `json:"openapi,omitempty"`
+	}
+
+	// HTTPTriggerOpenAPI enriches the trigger's operations in the generated
+	// OpenAPI document. It does not change how requests are routed or
+	// checked: the schemas are documentation, not validation.
+	HTTPTriggerOpenAPI struct {
+		// Exclude leaves the trigger out of the document, e.g. a webhook
+		// only a third party calls.
+		// +optional
+		Exclude bool `json:"exclude,omitempty"`
+
+		// Summary is the operations' one-line summary.
+		// +optional
+		// +kubebuilder:validation:MaxLength=256
+		Summary string `json:"summary,omitempty"`
+
+		// Description is the operations' description. Defaults to the
+		// function's Tool description.
+		// +optional
+		// +kubebuilder:validation:MaxLength=4096
+		Description string `json:"description,omitempty"`
+
+		// Tags group the operations in the document.
+		// +optional
+		// +listType=set
+		// +kubebuilder:validation:MaxItems=16
+		// +kubebuilder:validation:items:MaxLength=64
+		Tags []string `json:"tags,omitempty"`
+
+		// RequestSchema is the JSON Schema (draft 2020-12) of the JSON
+		// request body. Defaults to the function's Tool input schema.
+		// +optional
+		// +kubebuilder:pruning:PreserveUnknownFields
+		RequestSchema *apiextensionsv1.JSON `json:"requestSchema,omitempty"`
+
+		// ResponseSchema is the JSON Schema (draft 2020-12) of the JSON
+		// response body.
+		// +optional
+		// +kubebuilder:pruning:PreserveUnknownFields
+		ResponseSchema *apiextensionsv1.JSON `json:"responseSchema,omitempty"`
+	}
+
+	// HTTPTriggerTransform is applied by the router once per request, after
+	// authentication and rate limiting and before the request is queued,
+	// cached, mirrored or proxied, so every one of those sees the transformed
+	// request.
+	//
+	// Request templates (Path, Body and request header values) may use these
+	// placeholders, all read from the request as the client sent it:
+	//
+	//	{{path}}          the path the function would otherwise receive
+	//	{{param.<name>}}  a variable of the trigger's path template
+	//	{{query.<name>}}  a query parameter
+	//	{{header.<name>}} a request header
+	//	{{form.<name>}}   a field of a urlencoded form body (Body only)
+	//
+	// A placeholder with no value expands to the empty string.
+	HTTPTriggerTransform struct {
+		// Request transforms the request forwarded to the function.
+		// +optional
+		Request *HTTPRequestTransform `json:"request,omitempty"`
+
+		// Response transforms the function's response headers. Responses the
+		// router answers itself (errors, rate limits) are not transformed.
+		// +optional
+		Response *HTTPResponseTransform `json:"response,omitempty"`
+	}
+
+	// HTTPRequestTransform transforms a request. The body is replaced first,
+	// then headers, then the path.
+	HTTPRequestTransform struct {
+		// Headers edits the request headers.
+		// +optional
+		Headers *HTTPHeaderTransform `json:"headers,omitempty"`
+
+		// Path is a template for the path forwarded to the function, e.g.
+		// "/v2/users/{{param.id}}". It replaces the path Prefix and KeepPrefix
+		// would produce; {{path}} stands for that path. Placeholder values
+		// are escaped as one path segment.
+		// +optional
+		// +kubebuilder:validation:MaxLength=1024
+		// +kubebuilder:validation:Pattern=`^/`
+		Path string `json:"path,omitempty"`
+
+		// Body is a JSON template that replaces the request body, e.g.
+		// {"user": "{{form.user}}"}. Placeholders are meant to sit inside
+		// JSON strings; their values are escaped for one. The request
+		// Content-Type becomes application/json. A request body over 1 MiB
+		// is rejected with 413.
+		// +optional
+		// +kubebuilder:validation:MaxLength=16384
+		Body string `json:"body,omitempty"`
+	}
+
+	// HTTPResponseTransform transforms a response.
+	HTTPResponseTransform struct {
+		// Headers edits the response headers. Values are literal: there is
+		// no request to expand placeholders from.
+		// +optional
+		Headers *HTTPHeaderTransform `json:"headers,omitempty"`
+	}
+
+	// HTTPHeaderTransform edits headers: Remove is applied first, then Set,
+	// then Add. Names are case insensitive.
+	HTTPHeaderTransform struct {
+		// Set replaces every value of a header with one value.
+		// +optional
+		// +listType=map
+		// +listMapKey=name
+		// +kubebuilder:validation:MaxItems=32
+		Set []HTTPHeaderValue `json:"set,omitempty"`
+
+		// Add appends a value to a header, keeping the values it has.
+		// +optional
+		// +listType=atomic
+		// +kubebuilder:validation:MaxItems=32
+		Add []HTTPHeaderValue `json:"add,omitempty"`
+
+		// Remove deletes headers, e.g. Cookie.
+		// +optional
+		// +listType=set
+		// +kubebuilder:validation:MaxItems=32
+		// +kubebuilder:validation:items:MaxLength=256
+		Remove []string `json:"remove,omitempty"`
+	}
+
+	// HTTPHeaderValue is one header name and value.
+	HTTPHeaderValue struct {
+		// Name of the header.
+		// +kubebuilder:validation:MinLength=1
+		// +kubebuilder:validation:MaxLength=256
+		Name string `json:"name"`
+
+		// Value of the header.
+		// +kubebuilder:validation:MaxLength=4096
+		Value string `json:"value"`
+	}
+
+	// HTTPTriggerGRPC selects the gRPC calls a trigger serves by their
+	// service and, optionally, method. Clients reach the router over HTTP/2
+	// in cleartext (h2c, prior knowledge) or through a TLS-terminating
+	// ingress that speaks h2c upstream.
+	HTTPTriggerGRPC struct {
+		// Service is the fully qualified service name, package included, e.g.
+		// helloworld.Greeter.
+		// +kubebuilder:validation:MaxLength=253
+		// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`
+		Service string `json:"service"`
+
+		// Methods lists the service methods the trigger serves. Empty serves
+		// every method of the service.
+		// +optional
+		// +listType=set
+		// +kubebuilder:validation:MaxItems=64
+		// +kubebuilder:validation:items:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+		Methods []string `json:"methods,omitempty"`
+	}
+
+	// HTTPTriggerCache caches a trigger's GET responses in the router, in a
+	// memory LRU bounded per router replica and optionally in the statestore
+	// KV shared by every replica. The function's Cache-Control is honored:
+	// no-store, no-cache and private responses are never stored, and s-maxage,
+	// max-age or Expires override TTLSeconds. Concurrent misses for the same
+	// response are coalesced into one function call. Entries are keyed by the
+	// function version that produced them, so an update to the function stops
+	// serving its old responses at once.
+	HTTPTriggerCache struct {
+		// TTLSeconds is how long a response stays fresh when it carries no
+		// freshness of its own.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=86400
+		TTLSeconds int32 `json:"ttlSeconds"`
+
+		// StaleWhileRevalidateSeconds is how long past its freshness a
+		// response may still be served while the router refreshes it in the
+		// background. A stale-while-revalidate directive on the response
+		// overrides it. Defaults to 0: expired responses are not served.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		// +kubebuilder:validation:Maximum=86400
+		StaleWhileRevalidateSeconds int32 `json:"staleWhileRevalidateSeconds,omitempty"`
+
+		// Vary lists the request headers whose values select distinct cached
+		// responses, on top of the request URL and the headers named by the
+		// response's own Vary header.
+		// +optional
+		// +listType=set
+		// +kubebuilder:validation:MaxItems=16
+		Vary []string `json:"vary,omitempty"`
+
+		// MaxObjectBytes is the largest response body stored; larger
+		// responses are served uncached. Defaults to 1 MiB.
+		// +optional
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=8388608
+		MaxObjectBytes int64 `json:"maxObjectBytes,omitempty"`
+
+		// Shared also stores responses in the statestore KV, so a response
+		// cached by one router replica is served by all of them. It needs the
+		// statestore (async invocation enabled on the router); without it the
+		// cache stays per replica.
+		// +optional
+		Shared bool `json:"shared,omitempty"`
+	}
+
+	// HTTPTriggerMatch is a set of request conditions that must ALL hold for
+	// a trigger to serve a request. A request that fails them falls through
+	// to the next trigger registered for its path, and to 404 when there is
+	// none.
+	HTTPTriggerMatch struct {
+		// Headers each require a request header to match. Names are case
+		// insensitive.
+		// +optional
+		// +listType=map
+		// +listMapKey=name
+		// +kubebuilder:validation:MaxItems=16
+		Headers []HTTPMatchCondition `json:"headers,omitempty"`
+
+		// QueryParams each require a URL query parameter to match. Names are
+		// case sensitive.
+		// +optional
+		// +listType=map
+		// +listMapKey=name
+		// +kubebuilder:validation:MaxItems=16
+		QueryParams []HTTPMatchCondition `json:"queryParams,omitempty"`
+
+		// Hosts restricts the trigger to requests for one of these hosts,
+		// matched by the router itself against the Host header (port
+		// ignored). An entry is an exact DNS name or a wildcard "*.example.com",
+		// which matches any name with at least one more label. Unlike Host,
+		// Hosts configures no ingress or gateway route.
+		// +optional
+		// +listType=set
+		// +kubebuilder:validation:MaxItems=16
+		// +kubebuilder:validation:items:Pattern=`^(\*\.)?[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+		Hosts []string `json:"hosts,omitempty"`
+	}
+
+	// HTTPMatchCondition matches one request header or query parameter.
+	// +kubebuilder:validation:XValidation:rule="has(self.type) && self.type == 'present' ? (!has(self.value) || self.value == '') : (has(self.value) && self.value != '')",message="value is required unless type is 'present', and must be empty when it is"
+	HTTPMatchCondition struct {
+		// Name of the header or query parameter.
+		// +kubebuilder:validation:MinLength=1
+		// +kubebuilder:validation:MaxLength=256
+		Name string `json:"name"`
+
+		// Type is "exact" (the default), "regex" or "present".
+		// +optional
+		// +kubebuilder:validation:Enum=exact;regex;present
+		Type HTTPMatchType `json:"type,omitempty"`
+
+		// Value is compared according to Type.
+		// +optional
+		// +kubebuilder:validation:MaxLength=1024
+		Value string `json:"value,omitempty"`
+	}
+
+	// HTTPTriggerRateLimit is a token bucket: it holds up to Burst tokens,
+	// refills at Requests per PeriodSeconds, and each admitted request takes
+	// one. Buckets are local to each router replica unless Global is set, so
+	// without Global the cluster-wide limit is the per-replica limit times the
+	// router replica count.
+	HTTPTriggerRateLimit struct {
+		// Requests is the sustained number of requests admitted per period.
+		// +kubebuilder:validation:Minimum=1
+		Requests int32 `json:"requests"`
+
+		// PeriodSeconds is the period Requests is measured over. Defaults to 1.
+		// +optional
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=86400
+		PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+		// Burst is the bucket size: how many requests a client that has been
+		// idle may send at once. Defaults to Requests.
+		// +optional
+		// +kubebuilder:validation:Minimum=1
+		Burst int32 `json:"burst,omitempty"`
+
+		// Key selects what requests are counted by. Defaults to one bucket for
+		// the whole trigger.
+		// +optional
+		Key HTTPTriggerRateLimitKey `json:"key,omitempty"`
+
+		// Global enforces the limit across all router replicas through the
+		// statestore KV, at the cost of a statestore round trip per request.
+		// It needs the statestore (async invocation enabled on the router);
+		// without it, and whenever the statestore is unreachable, the router
+		// falls back to its local buckets rather than failing requests.
+		// +optional
+		Global bool `json:"global,omitempty"`
+	}
+
+	// HTTPTriggerRateLimitKey selects the bucket a request is counted in.
+	// Requests that lack the key (no such header, no such claim) share one
+	// bucket.
+	// +kubebuilder:validation:XValidation:rule="!(self.source in ['header', 'claim']) || (has(self.name) && self.name != '')",message="rateLimit.key.name is required when key.source is 'header' or 'claim'"
+	HTTPTriggerRateLimitKey struct {
+		// Source is "trigger" (the default: one bucket for every caller),
+		// "ip" (the client address), "header" (the value of header Name) or
+		// "claim" (the value of verified JWT claim Name; the trigger needs a
+		// jwt Auth policy).
+		// +optional
+		// +kubebuilder:validation:Enum="";trigger;ip;header;claim
+		Source HTTPTriggerRateLimitKeySource `json:"source,omitempty"`
+
+		// Name is the header or claim name for the header and claim sources.
+		// +optional
+		Name string `json:"name,omitempty"`
+
+		// TrustedProxyHops is, for the ip source, how many proxies in front of
+		// the router append to X-Forwarded-For (e.g. 1 for a single ingress
+		// controller or load balancer). The client address is taken that many
+		// entries from the right; 0, the default, uses the connection's peer
+		// address and ignores the header, which a client can forge.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		TrustedProxyHops int32 `json:"trustedProxyHops,omitempty"`
+	}
+
+	// HTTPTriggerAuth is the per-HTTPTrigger authentication policy. Exactly
+	// the block matching Type is set. A request that passes the policy is
+	// forwarded with the verified identity in X-Fission-Auth-* headers; the
+	// router strips any client-supplied X-Fission-Auth-* headers first, so a
+	// function on an authenticated route can trust them.
+	// +kubebuilder:validation:XValidation:rule="self.type != 'jwt' || has(self.jwt)",message="auth.jwt is required when auth.type is 'jwt'"
+	// +kubebuilder:validation:XValidation:rule="self.type != 'apiKey' || has(self.apiKey)",message="auth.apiKey is required when auth.type is 'apiKey'"
+	// +kubebuilder:validation:XValidation:rule="self.type == 'jwt' || !has(self.jwt)",message="auth.jwt is only valid when auth.type is 'jwt'"
+	// +kubebuilder:validation:XValidation:rule="self.type == 'apiKey' || !has(self.apiKey)",message="auth.apiKey is only valid when auth.type is 'apiKey'"
+	HTTPTriggerAuth struct {
+		// Type selects the policy: "anonymous" admits every request, "jwt"
+		// verifies a bearer token against an issuer's JWKS, "apiKey" checks a
+		// key against a Secret.
+		// +kubebuilder:validation:Enum=anonymous;jwt;apiKey
+		Type HTTPTriggerAuthType `json:"type"`
+
+		// JWT configures bearer-token validation. Required when Type is "jwt".
+		// +optional
+		JWT *HTTPTriggerJWTAuth `json:"jwt,omitempty"`
+
+		// APIKey configures API-key validation. Required when Type is "apiKey".
+		// +optional
+		APIKey *HTTPTriggerAPIKeyAuth `json:"apiKey,omitempty"`
+	}
+
+	// HTTPTriggerJWTAuth validates an "Authorization: Bearer" JWT against the
+	// public keys an OIDC issuer publishes. Only asymmetric signatures (RS*,
+	// PS*, ES*, EdDSA) are accepted; the router caches the key set and
+	// refetches it when a token names an unknown key ID.
+	HTTPTriggerJWTAuth struct {
+		// Issuer is the expected "iss" claim, e.g.
+		// https://accounts.example.com. Unless JWKSURI is set, the key set is
+		// located through the issuer's OpenID discovery document
+		// (<issuer>/.well-known/openid-configuration).
+		// +kubebuilder:validation:MinLength=1
+		Issuer string `json:"issuer"`
+
+		// JWKSURI is the URL of the issuer's JSON Web Key Set. Set it for
+		// issuers that do not serve OpenID discovery.
+		// +optional
+		JWKSURI string `json:"jwksUri,omitempty"`
+
+		// Audiences lists the accepted "aud" values; a token must carry at
+		// least one of them. Empty skips the audience check.
+		// +optional
+		// +listType=set
+		Audiences []string `json:"audiences,omitempty"`
+
+		// RequiredClaims maps a claim name to the value it must have. A claim
+		// holding a list matches when any element equals the value (e.g.
+		// {"groups": "admins"}).
+		// +optional
+		RequiredClaims map[string]string `json:"requiredClaims,omitempty"`
+
+		// ForwardClaims maps a claim name to the request header its value is
+		// forwarded in, in addition to the "sub" claim, which is always
+		// forwarded as X-Fission-Auth-Subject. Header names must start with
+		// X-Fission-Auth-.
+		// +optional
+		ForwardClaims map[string]string `json:"forwardClaims,omitempty"`
+	}
+
+	// HTTPTriggerAPIKeyAuth checks a request header against the keys stored
+	// in a Secret in the trigger's namespace. Each entry of the Secret's data
+	// is one client: the entry name is the client identity (forwarded as
+	// X-Fission-Auth-Subject) and the value is its key. Rotating a key is a
+	// Secret edit; the router picks it up within a minute.
+	HTTPTriggerAPIKeyAuth struct {
+		// SecretName names the Secret holding the keys.
+		// +kubebuilder:validation:MinLength=1
+		SecretName string `json:"secretName"`
+
+		// Header is the request header carrying the key. Defaults to
+		// X-API-Key. The header is removed before the request reaches the
+		// function.
+		// +optional
+		Header string `json:"header,omitempty"`
 	}
 
 	// HTTPTriggerCorsConfig is the per-HTTPTrigger CORS allowlist.
@@ -2318,6 +3241,132 @@ type (
 		FailureThreshold int `json:"failurethreshold"`
 		// +optional
 		FailureType FailureType `json:"failureType"`
+
+		// Analysis adds checks to FailureThreshold that the new target must
+		// pass in each interval before the rollout advances: a minimum
+		// request count, latency percentiles and arbitrary PromQL queries.
+		// A check without data holds the rollout at its current weights; a
+		// check that fails rolls it back.
+		// +optional
+		Analysis *CanaryAnalysis `json:"analysis,omitempty"`
+
+		// Webhooks are gates asked before every weight increment, and before
+		// the final promotion. Each receives a JSON POST describing the step
+		// and approves it with a 2xx response.
+		// +optional
+		// +listType=map
+		// +listMapKey=name
+		// +kubebuilder:validation:MaxItems=8
+		Webhooks []CanaryWebhook `json:"webhooks,omitempty"`
+
+		// Paused holds the rollout at its current weights: no analysis, no
+		// increments, no rollback. Clearing it resumes the rollout from
+		// where it stopped.
+		// +optional
+		Paused bool `json:"paused,omitempty"`
+
+		// Promote skips the remaining increments and the analysis and moves
+		// all traffic to the new target at once, finishing the rollout as
+		// Succeeded. It wins over Paused. Webhook gates are not asked: a
+		// manual promotion is the operator's decision.
+		// +optional
+		Promote bool `json:"promote,omitempty"`
+	}
+
+	// CanaryAnalysis is the set of metric checks a canary rollout evaluates
+	// against the new target each interval, over the last interval's window.
+	// Checks run only once the new target carries traffic.
+	CanaryAnalysis struct {
+		// MinRequests is the number of requests the new target must have
+		// served in the window before any check is judged; until then the
+		// rollout holds. 0 judges whatever traffic there was.
+		// +optional
+		// +kubebuilder:validation:Minimum=0
+		MinRequests int32 `json:"minRequests,omitempty"`
+
+		// Latency caps latency percentiles of the new target, measured by
+		// the router from the request to the function's response headers.
+		// +optional
+		// +listType=map
+		// +listMapKey=percentile
+		// +kubebuilder:validation:MaxItems=4
+		Latency []CanaryLatencyThreshold `json:"latency,omitempty"`
+
+		// Templates are PromQL queries whose result must stay within bounds.
+		// +optional
+		// +listType=map
+		// +listMapKey=name
+		// +kubebuilder:validation:MaxItems=8
+		Templates []CanaryAnalysisTemplate `json:"templates,omitempty"`
+	}
+
+	// CanaryLatencyThreshold fails the rollout when a latency percentile of
+	// the new target exceeds MaxMilliseconds.
+	CanaryLatencyThreshold struct {
+		// Percentile, e.g. 99 for p99.
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=99
+		Percentile int32 `json:"percentile"`
+
+		// MaxMilliseconds is the highest acceptable value of the percentile.
+		// +kubebuilder:validation:Minimum=1
+		MaxMilliseconds int32 `json:"maxMilliseconds"`
+	}
+
+	// CanaryAnalysisTemplate is a PromQL query evaluated each interval. The
+	// placeholders {{function}}, {{version}}, {{namespace}}, {{path}} and
+	// {{window}} are replaced before the query runs: {{function}} is the new
+	// function (the alias's function in alias mode), {{version}} the new
+	// FunctionVersion in alias mode and empty in function-pair mode,
+	// {{path}} the trigger's URL and {{window}} the increment interval. The
+	// query must return a single value; a query that returns no data holds
+	// the rollout. Numeric bounds use resource.Quantity (CRDs cannot carry
+	// floats; Quantity accepts YAML numbers and strings such as "0.5").
+	// +kubebuilder:validation:XValidation:rule="has(self.min) || has(self.max)",message="an analysis template needs min, max or both"
+	CanaryAnalysisTemplate struct {
+		// +kubebuilder:validation:MinLength=1
+		// +kubebuilder:validation:MaxLength=63
+		Name string `json:"name"`
+
+		// +kubebuilder:validation:MinLength=1
+		// +kubebuilder:validation:MaxLength=4096
+		Query string `json:"query"`
+
+		// Min fails the rollout when the result is below it.
+		// +optional
+		Min *resource.Quantity `json:"min,omitempty"`
+
+		// Max fails the rollout when the result is above it.
+		// +optional
+		Max *resource.Quantity `json:"max,omitempty"`
+	}
+
+	// CanaryWebhook is a gate a canary rollout asks before each step. The
+	// controller POSTs a JSON body with the config's name and namespace,
+	// the trigger, the old and new targets, the new target's current weight
+	// and the weight it is about to move to; a 2xx response approves the
+	// step. Any other response, or no response within TimeoutSeconds, is a
+	// refusal handled by FailurePolicy.
+	CanaryWebhook struct {
+		// +kubebuilder:validation:MinLength=1
+		// +kubebuilder:validation:MaxLength=63
+		Name string `json:"name"`
+
+		// +kubebuilder:validation:Pattern=`^https?://`
+		URL string `json:"url"`
+
+		// TimeoutSeconds bounds one call to the gate (default 10).
+		// +optional
+		// +kubebuilder:validation:Minimum=1
+		// +kubebuilder:validation:Maximum=60
+		TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+		// FailurePolicy is what a refusal does: Hold (default) keeps the
+		// current weights and asks again next interval, Rollback moves all
+		// traffic back to the old target and fails the rollout.
+		// +optional
+		// +kubebuilder:validation:Enum=Hold;Rollback
+		FailurePolicy CanaryWebhookFailurePolicy `json:"failurePolicy,omitempty"`
 	}
 
 	// CanaryConfigStatus represents canary config status
@@ -2354,7 +3403,8 @@ type (
 		// +optional
 		ProvisionedTarget int `json:"provisionedTarget,omitempty"`
 
-		// ProvisionedSpecTarget is the raw Target from spec (before the namespace
+		// ProvisionedSpecTarget is the raw Target from spec, or the derived
+		// target under Spec.ProvisionedConcurrency.Auto (before the namespace
 		// cap clamp). When ProvisionedSpecTarget > ProvisionedTarget, the
 		// provisioner clamped the target to the namespace cap
 		// (executor.provisionedConcurrency.maxPerFunction) and the Provisioned
@@ -2363,6 +3413,12 @@ type (
 		// +optional
 		ProvisionedSpecTarget int `json:"provisionedSpecTarget,omitempty"`
 
+		// ProvisionedTargetReason explains ProvisionedTarget when
+		// Spec.ProvisionedConcurrency.Auto is set: the observed concurrency
+		// it was derived from, or why Target is still in effect.
+		// +optional
+		ProvisionedTargetReason string `json:"provisionedTargetReason,omitempty"`
+
 		// Conditions represent the latest observations of the function's state.
 		// +optional
 		// +patchMergeKey=type
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"path/filepath"
@@ -19,6 +20,8 @@ import (
 	"time"
 
 	"github.com/robfig/cron/v3"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 
@@ -360,6 +363,38 @@ func (spec FunctionSpec) Validate() error {
 		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidObject, "FunctionSpec.PodSpec", "", "executor type container requires a pod spec"))
 	}
 
+	// The wasm executor runs the module in a host pod shared by the
+	// namespace's functions, so nothing per-function reaches a pod.
+	if spec.InvokeStrategy.ExecutionStrategy.ExecutorType == ExecutorTypeWasm {
+		if spec.Package.PackageRef.Name == "" {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidObject, "FunctionSpec.Package", "", "executor type wasm requires a package holding the module"))
+		}
+		if spec.PodSpec != nil || spec.State != nil || len(spec.Secrets) > 0 || len(spec.ConfigMaps) > 0 ||
+			len(spec.Env) > 0 || len(spec.EnvFrom) > 0 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidObject, "FunctionSpec", "", "executor type wasm does not support podspec, state, secrets, configmaps, env or envFrom"))
+		}
+	}
+
+	// A job function's Job specializes the environment's runtime with the
+	// package, like newdeploy, but serves exactly one request.
+	if spec.InvokeStrategy.ExecutionStrategy.ExecutorType == ExecutorTypeJob {
+		if spec.Environment.Name == "" {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidObject, "FunctionSpec.Environment", "", "executor type job requires an environment"))
+		}
+		if spec.PodSpec != nil || spec.Streaming != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidObject, "FunctionSpec", "", "executor type job does not support podspec or streaming"))
+		}
+	}
+
+	// Resource classes are pools of the environment, which only poolmgr
+	// functions run in.
+	if spec.ResourceClass != "" {
+		errs = errors.Join(errs, ValidateKubeName("FunctionSpec.ResourceClass", spec.ResourceClass))
+		if et := spec.InvokeStrategy.ExecutionStrategy.ExecutorType; et != "" && et != ExecutorTypePoolmgr {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidObject, "FunctionSpec.ResourceClass", spec.ResourceClass, "requires the poolmgr executor"))
+		}
+	}
+
 	if spec.Streaming != nil {
 		errs = errors.Join(errs, spec.Streaming.Validate())
 	}
@@ -470,7 +505,8 @@ const (
 // a non-negative and well-ordered backoff schedule, and a max age in
 // (0, MaxAsyncMaxAge]. These bounds keep the dispatcher's retry loop well-defined
 // (a zero attempt budget or max age would mean "accepted but never deliverable")
-// and keep one tenant from setting absurd values on the shared queue.
+// and keep one tenant from setting absurd values on the shared queue. A set
+// priority must name a known lane.
 // validateBackoffBounds checks the ordering rules every RetryPolicy consumer
 // shares (base >= 0, cap >= 0, cap >= base). Attempt budgets stay at the
 // callers — async delivery clamps to MaxAsyncAttempts, workflows to
@@ -511,9 +547,58 @@ func (ic *InvocationConfig) Validate() error {
 	if ic.OnFailure != nil {
 		errs = errors.Join(errs, ic.OnFailure.Validate("FunctionSpec.Invocation.OnFailure"))
 	}
+	switch ic.Priority {
+	case "", InvocationPriorityHigh, InvocationPriorityNormal, InvocationPriorityLow:
+	default:
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "FunctionSpec.Invocation.Priority", ic.Priority, "must be one of high, normal, low"))
+	}
+	if ic.CircuitBreaker != nil {
+		errs = errors.Join(errs, ic.CircuitBreaker.Validate())
+	}
 	return errs
 }
 
+// Validate checks the circuit breaker's bounds (mirroring the CRD markers for
+// clients that bypass the API server's schema, such as the CLI's spec files).
+func (c *CircuitBreakerConfig) Validate() error {
+	var errs error
+	for _, f := range []struct {
+		name     string
+		val, max int32
+	}{
+		{"ConsecutiveFailures", c.ConsecutiveFailures, math.MaxInt32},
+		{"ErrorRatePercent", c.ErrorRatePercent, 100},
+		{"MinRequests", c.MinRequests, math.MaxInt32},
+		{"IntervalSeconds", c.IntervalSeconds, 3600},
+		{"EjectionSeconds", c.EjectionSeconds, 3600},
+		{"HalfOpenRequests", c.HalfOpenRequests, math.MaxInt32},
+		{"MaxEjectionPercent", c.MaxEjectionPercent, 100},
+	} {
+		if f.val < 0 || f.val > f.max {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "FunctionSpec.Invocation.CircuitBreaker."+f.name, f.val, fmt.Sprintf("must be between 0 and %d", f.max)))
+		}
+	}
+	return errs
+}
+
+// Effective returns c with the platform defaults applied to every field left
+// at zero (ErrorRatePercent stays 0: zero disables the error-rate check).
+func (c *CircuitBreakerConfig) Effective() CircuitBreakerConfig {
+	e := *c
+	orDefault := func(v *int32, def int32) {
+		if *v == 0 {
+			*v = def
+		}
+	}
+	orDefault(&e.ConsecutiveFailures, DefaultCircuitBreakerConsecutiveFailures)
+	orDefault(&e.MinRequests, DefaultCircuitBreakerMinRequests)
+	orDefault(&e.IntervalSeconds, DefaultCircuitBreakerIntervalSeconds)
+	orDefault(&e.EjectionSeconds, DefaultCircuitBreakerEjectionSeconds)
+	orDefault(&e.HalfOpenRequests, DefaultCircuitBreakerHalfOpenRequests)
+	orDefault(&e.MaxEjectionPercent, DefaultCircuitBreakerMaxEjectionPercent)
+	return e
+}
+
 // Validate checks a destination reference: exactly one of Function/Topic, a
 // function destination that references a single named function (weights make no
 // sense for a destination), and a topic destination on a supported provider —
@@ -545,8 +630,10 @@ var topicNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
 // whose classic heads run an RFC-0027 egress consumer. KEDA-only types (e.g.
 // aws-sqs-queue) have no classic head and therefore no egress loop — rejected.
 var topicDestinationTypes = map[MessageQueueType]struct{}{
-	MessageQueueTypeStatestore: {},
-	MessageQueueTypeKafka:      {},
+	MessageQueueTypeStatestore:    {},
+	MessageQueueTypeKafka:         {},
+	MessageQueueTypeNatsJetStream: {},
+	MessageQueueTypeRabbitMQ:      {},
 }
 
 // kafkaDestinationTopicRegexp mirrors the kafka provider's trigger-side topic
@@ -569,13 +656,16 @@ var kafkaDestinationTopicRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]
 func (tr *TopicRef) Validate(field string) error {
 	if _, ok := topicDestinationTypes[tr.MessageQueueType]; !ok {
 		return MakeValidationErr(ErrorInvalidValue, field+".messageQueueType", tr.MessageQueueType,
-			fmt.Sprintf("topic destinations support %q (built-in) and %q (broker egress)", MessageQueueTypeStatestore, MessageQueueTypeKafka))
+			fmt.Sprintf("topic destinations support %q (built-in), %q, %q and %q (broker egress)",
+				MessageQueueTypeStatestore, MessageQueueTypeKafka, MessageQueueTypeNatsJetStream, MessageQueueTypeRabbitMQ))
 	}
 	return ValidateTopicNameForMQType(field+".topic", string(tr.MessageQueueType), tr.Topic)
 }
 
 // ValidateTopicNameForMQType applies the base topic grammar plus the target
-// type's own restrictions (kafka's stricter rule for kafka). Exported so every
+// type's own restrictions (kafka's stricter rule for kafka, NATS subject token
+// rules for nats-jetstream, the broker-reserved amq. prefix for rabbitmq).
+// Exported so every
 // layer that handles a typed topic — admission, the mqpub publishers, the
 // egress consumer sink, the topic admin API — applies the SAME rule and a name
 // the broker refuses forever is rejected up front instead of churning retries
@@ -588,6 +678,14 @@ func ValidateTopicNameForMQType(field, mqType, topic string) error {
 		return MakeValidationErr(ErrorInvalidValue, field, topic,
 			"kafka topics must start and end with an alphanumeric character")
 	}
+	if mqType == MessageQueueTypeNatsJetStream && slices.Contains(strings.Split(topic, "."), "") {
+		return MakeValidationErr(ErrorInvalidValue, field, topic,
+			"NATS subjects are dot-separated tokens: no leading, trailing or repeated dots")
+	}
+	if mqType == MessageQueueTypeRabbitMQ && strings.HasPrefix(topic, "amq.") {
+		return MakeValidationErr(ErrorInvalidValue, field, topic,
+			"RabbitMQ reserves queue names starting with amq.")
+	}
 	return nil
 }
 
@@ -608,7 +706,7 @@ func (sc *StreamingConfig) Validate() error {
 	var errs error
 
 	switch sc.Protocol {
-	case "", StreamingAuto, StreamingSSE, StreamingChunked, StreamingWebSocket:
+	case "", StreamingAuto, StreamingSSE, StreamingChunked, StreamingWebSocket, StreamingGRPC:
 		// ok
 	default:
 		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "FunctionSpec.Streaming.Protocol", sc.Protocol, "not a valid streaming protocol"))
@@ -735,9 +833,127 @@ func (pc *ProvisionedConcurrencyConfig) Validate() error {
 			windows[window.Name] = struct{}{}
 		}
 	}
+	if pc.Auto != nil {
+		errs = errors.Join(errs, pc.Auto.Validate())
+	}
+	return errs
+}
+
+// Validate checks the auto provisioned concurrency config.
+func (a *ProvisionedAutoConfig) Validate() error {
+	const field = "FunctionSpec.ProvisionedConcurrency.Auto"
+	var errs error
+	if a.Percentile < 0 || a.Percentile > 100 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Percentile", a.Percentile, "must be between 1 and 100"))
+	}
+	if a.Window != "" {
+		if d, err := time.ParseDuration(a.Window); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Window", a.Window, "window is invalid: "+err.Error()))
+		} else if d < MinProvisionedAutoWindow || d > MaxProvisionedAutoWindow {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Window", a.Window,
+				fmt.Sprintf("must be between %v and %v", MinProvisionedAutoWindow, MaxProvisionedAutoWindow)))
+		}
+	}
+	if a.HeadroomPercent < 0 || a.HeadroomPercent > 400 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".HeadroomPercent", a.HeadroomPercent, "must be between 0 and 400"))
+	}
+	if a.Min < 0 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Min", a.Min, "must be >= 0"))
+	}
+	if a.Max < 0 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Max", a.Max, "must be >= 0"))
+	} else if a.Max > 0 && a.Max < a.Min {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Max", a.Max, "must be >= min"))
+	}
+	if a.SeasonalityDays < 0 || a.SeasonalityDays > MaxProvisionedAutoSeasonalityDays {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".SeasonalityDays", a.SeasonalityDays,
+			fmt.Sprintf("must be between 0 and %d", MaxProvisionedAutoSeasonalityDays)))
+	}
+	return errs
+}
+
+// Effective returns the config with defaults applied to zero fields. The
+// Window of a validated config always parses.
+func (a *ProvisionedAutoConfig) Effective() ProvisionedAutoConfig {
+	e := *a
+	if e.Percentile == 0 {
+		e.Percentile = DefaultProvisionedAutoPercentile
+	}
+	if e.Window == "" {
+		e.Window = DefaultProvisionedAutoWindow
+	}
+	return e
+}
+
+func (a *PoolAutoscaleConfig) Validate() error {
+	const field = "EnvironmentSpec.PoolAutoscale"
+	var errs error
+	if a.MinSize < 0 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".MinSize", a.MinSize, "must be >= 0"))
+	}
+	if a.MaxSize < 1 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".MaxSize", a.MaxSize, "must be >= 1"))
+	} else if a.MaxSize < a.MinSize {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".MaxSize", a.MaxSize, "must be >= minSize"))
+	}
+	if a.TargetIdle < 0 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".TargetIdle", a.TargetIdle, "must be >= 0"))
+	}
+	if a.Window != "" {
+		if d, err := time.ParseDuration(a.Window); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Window", a.Window, "window is invalid: "+err.Error()))
+		} else if d < MinPoolAutoscaleWindow || d > MaxPoolAutoscaleWindow {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Window", a.Window,
+				fmt.Sprintf("must be between %v and %v", MinPoolAutoscaleWindow, MaxPoolAutoscaleWindow)))
+		}
+	}
+	if a.ScaleDownDelay != "" {
+		if d, err := time.ParseDuration(a.ScaleDownDelay); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".ScaleDownDelay", a.ScaleDownDelay, "scale down delay is invalid: "+err.Error()))
+		} else if d < 0 || d > MaxPoolAutoscaleScaleDownDelay {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".ScaleDownDelay", a.ScaleDownDelay,
+				fmt.Sprintf("must be between 0s and %v", MaxPoolAutoscaleScaleDownDelay)))
+		}
+	}
+	return errs
+}
+
+func (rc ResourceClass) Validate() error {
+	var errs error
+	errs = errors.Join(errs, ValidateKubeName("ResourceClass.Name", rc.Name))
+	if rc.Poolsize < 0 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "ResourceClass.Poolsize", rc.Poolsize, "must be greater than or equal to 0"))
+	}
 	return errs
 }
 
+// ResourceClass returns the environment's resource class called name, or nil
+// when it declares none by that name.
+func (spec *EnvironmentSpec) ResourceClass(name string) *ResourceClass {
+	for i := range spec.ResourceClasses {
+		if spec.ResourceClasses[i].Name == name {
+			return &spec.ResourceClasses[i]
+		}
+	}
+	return nil
+}
+
+// Effective returns the config with defaults applied to zero fields. The
+// durations of a validated config always parse.
+func (a *PoolAutoscaleConfig) Effective() PoolAutoscaleConfig {
+	e := *a
+	if e.TargetIdle == 0 {
+		e.TargetIdle = DefaultPoolAutoscaleTargetIdle
+	}
+	if e.Window == "" {
+		e.Window = DefaultPoolAutoscaleWindow
+	}
+	if e.ScaleDownDelay == "" {
+		e.ScaleDownDelay = DefaultPoolAutoscaleScaleDownDelay
+	}
+	return e
+}
+
 // EffectiveKeyspace resolves the keyspace, defaulting to the function name.
 func (sc *StateConfig) EffectiveKeyspace(fnName string) string {
 	if sc.Keyspace != "" {
@@ -781,7 +997,7 @@ func (is InvokeStrategy) Validate() error {
 func (es ExecutionStrategy) Validate() error {
 	var errs error
 	switch es.ExecutorType {
-	case ExecutorTypeNewdeploy, ExecutorTypePoolmgr, ExecutorTypeContainer: // no op
+	case ExecutorTypeNewdeploy, ExecutorTypePoolmgr, ExecutorTypeContainer, ExecutorTypeWasm, ExecutorTypeJob: // no op
 	default:
 		errs = errors.Join(errs, MakeValidationErr(ErrorUnsupportedType, "ExecutionStrategy.ExecutorType", es.ExecutorType, "not a valid executor type"))
 	}
@@ -803,15 +1019,197 @@ func (es ExecutionStrategy) Validate() error {
 			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "ExecutionStrategy.TargetCPUPercent", es.TargetCPUPercent, "TargetCPUPercent must be a value between 1 - 100"))
 		}
 
+		if es.ConcurrencyScaling != nil {
+			errs = errors.Join(errs, es.ConcurrencyScaling.Validate())
+		}
+
+		if es.ScaleToZero != nil {
+			errs = errors.Join(errs, es.ScaleToZero.Validate())
+		}
+
 		// TODO Add validation warning
 		// if es.SpecializationTimeout < 120 {
 		//	errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "ExecutionStrategy.SpecializationTimeout", es.SpecializationTimeout, "SpecializationTimeout must be a value equal to or greater than 120"))
 		//}
+	} else {
+		if es.ConcurrencyScaling != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "ExecutionStrategy.ConcurrencyScaling", es.ExecutorType, "only supported by executor types newdeploy and container"))
+		}
+		if es.ScaleToZero != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "ExecutionStrategy.ScaleToZero", es.ExecutorType, "only supported by executor types newdeploy and container"))
+		}
+	}
+
+	if es.Wasm != nil {
+		if es.ExecutorType == ExecutorTypeWasm {
+			errs = errors.Join(errs, es.Wasm.Validate())
+		} else {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "ExecutionStrategy.Wasm", es.ExecutorType, "only supported by executor type wasm"))
+		}
+	}
+
+	if es.Job != nil {
+		if es.ExecutorType == ExecutorTypeJob {
+			errs = errors.Join(errs, es.Job.Validate())
+		} else {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "ExecutionStrategy.Job", es.ExecutorType, "only supported by executor type job"))
+		}
 	}
 
 	return errs
 }
 
+func (c *ConcurrencyScalingConfig) Validate() error {
+	const field = "ExecutionStrategy.ConcurrencyScaling"
+	var errs error
+	if c.TargetPerReplica < 1 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".TargetPerReplica", c.TargetPerReplica, "must be >= 1"))
+	}
+	if c.StabilizationWindow != "" {
+		if d, err := time.ParseDuration(c.StabilizationWindow); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".StabilizationWindow", c.StabilizationWindow, "stabilization window is invalid: "+err.Error()))
+		} else if d < 0 || d > MaxConcurrencyStabilizationWindow {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".StabilizationWindow", c.StabilizationWindow,
+				fmt.Sprintf("must be between 0s and %v", MaxConcurrencyStabilizationWindow)))
+		}
+	}
+	return errs
+}
+
+// Effective returns the config with defaults applied to zero fields. The
+// StabilizationWindow of a validated config always parses.
+func (c *ConcurrencyScalingConfig) Effective() ConcurrencyScalingConfig {
+	e := *c
+	if e.StabilizationWindow == "" {
+		e.StabilizationWindow = DefaultConcurrencyStabilizationWindow
+	}
+	return e
+}
+
+func (c *ScaleToZeroConfig) Validate() error {
+	const field = "ExecutionStrategy.ScaleToZero"
+	var errs error
+	if c.QueueSize < 0 || c.QueueSize > MaxScaleToZeroQueueSize {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".QueueSize", c.QueueSize,
+			fmt.Sprintf("must be between 0 and %d", MaxScaleToZeroQueueSize)))
+	}
+	if c.QueueTimeout != "" {
+		if d, err := time.ParseDuration(c.QueueTimeout); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".QueueTimeout", c.QueueTimeout, "queue timeout is invalid: "+err.Error()))
+		} else if d < MinScaleToZeroQueueTimeout || d > MaxScaleToZeroQueueTimeout {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".QueueTimeout", c.QueueTimeout,
+				fmt.Sprintf("must be between %v and %v", MinScaleToZeroQueueTimeout, MaxScaleToZeroQueueTimeout)))
+		}
+	}
+	if c.RequestsPerReplica < 0 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".RequestsPerReplica", c.RequestsPerReplica, "must be >= 0"))
+	}
+	return errs
+}
+
+// Effective returns the config with defaults applied to zero fields. The
+// QueueTimeout of a validated config always parses.
+func (c *ScaleToZeroConfig) Effective() ScaleToZeroConfig {
+	e := *c
+	if e.QueueSize == 0 {
+		e.QueueSize = DefaultScaleToZeroQueueSize
+	}
+	if e.QueueTimeout == "" {
+		e.QueueTimeout = DefaultScaleToZeroQueueTimeout
+	}
+	if e.RequestsPerReplica == 0 {
+		e.RequestsPerReplica = DefaultScaleToZeroRequestsPerReplica
+	}
+	return e
+}
+
+func (c *WasmConfig) Validate() error {
+	const field = "ExecutionStrategy.Wasm"
+	var errs error
+	if c.MaxMemory != nil {
+		if v := c.MaxMemory.Value(); v < WasmPageSize || v > MaxWasmMaxMemory {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".MaxMemory", c.MaxMemory.String(),
+				fmt.Sprintf("must be between %d and %d bytes", WasmPageSize, MaxWasmMaxMemory)))
+		}
+	}
+	if c.MaxExecutionTime != "" {
+		if d, err := time.ParseDuration(c.MaxExecutionTime); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".MaxExecutionTime", c.MaxExecutionTime, "max execution time is invalid: "+err.Error()))
+		} else if d < MinWasmMaxExecutionTime || d > MaxWasmMaxExecutionTime {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".MaxExecutionTime", c.MaxExecutionTime,
+				fmt.Sprintf("must be between %v and %v", MinWasmMaxExecutionTime, MaxWasmMaxExecutionTime)))
+		}
+	}
+	return errs
+}
+
+// Effective returns the config with defaults applied to zero fields; a nil
+// config is all defaults. The fields of a validated config always parse.
+func (c *WasmConfig) Effective() WasmConfig {
+	var e WasmConfig
+	if c != nil {
+		e = *c
+	}
+	if e.MaxMemory == nil {
+		e.MaxMemory = new(resource.MustParse(DefaultWasmMaxMemory))
+	}
+	if e.MaxExecutionTime == "" {
+		e.MaxExecutionTime = DefaultWasmMaxExecutionTime
+	}
+	return e
+}
+
+func (c *JobConfig) Validate() error {
+	const field = "ExecutionStrategy.Job"
+	var errs error
+	if c.BackoffLimit < 0 || c.BackoffLimit > MaxJobBackoffLimit {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".BackoffLimit", c.BackoffLimit,
+			fmt.Sprintf("must be between 0 and %d", MaxJobBackoffLimit)))
+	}
+	if c.ResultTTL != "" {
+		if d, err := time.ParseDuration(c.ResultTTL); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".ResultTTL", c.ResultTTL, "result TTL is invalid: "+err.Error()))
+		} else if d < MinJobResultTTL || d > MaxJobResultTTL {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".ResultTTL", c.ResultTTL,
+				fmt.Sprintf("must be between %v and %v", MinJobResultTTL, MaxJobResultTTL)))
+		}
+	}
+	if c.ActiveDeadline != "" {
+		if d, err := time.ParseDuration(c.ActiveDeadline); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".ActiveDeadline", c.ActiveDeadline, "active deadline is invalid: "+err.Error()))
+		} else if d < MinJobActiveDeadline || d > MaxJobActiveDeadline {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".ActiveDeadline", c.ActiveDeadline,
+				fmt.Sprintf("must be between %v and %v", MinJobActiveDeadline, MaxJobActiveDeadline)))
+		}
+	}
+	return errs
+}
+
+// Effective returns the config with defaults applied to zero fields; a nil
+// config is all defaults. The fields of a validated config always parse.
+func (c *JobConfig) Effective() JobConfig {
+	var e JobConfig
+	if c != nil {
+		e = *c
+	}
+	if e.ResultTTL == "" {
+		e.ResultTTL = DefaultJobResultTTL
+	}
+	return e
+}
+
+// ScaleFromZeroReplicas returns the replicas a function with ScaleToZero
+// scales from zero to for queued buffered requests: one per
+// RequestsPerReplica, between MinScale (at least one) and MaxScale.
+func (es *ExecutionStrategy) ScaleFromZeroReplicas(queued int) int {
+	rpr := DefaultScaleToZeroRequestsPerReplica
+	if es.ScaleToZero != nil {
+		rpr = es.ScaleToZero.Effective().RequestsPerReplica
+	}
+	floor := max(es.MinScale, 1)
+	return min(max((queued+rpr-1)/rpr, floor), max(es.MaxScale, floor))
+}
+
 // Validate checks a FunctionReference: Type is a supported value, Name is a
 // kube name when Type is "name", and the optional Alias/Version pins
 // (RFC-0025) are each a kube name, mutually exclusive, and valid only when
@@ -900,6 +1298,25 @@ func (spec EnvironmentSpec) Validate() error {
 		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "EnvironmentSpec.Poolsize", spec.Poolsize, "must be greater than or equal to 0"))
 	}
 
+	if spec.PoolAutoscale != nil {
+		if spec.Version < 3 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "EnvironmentSpec.PoolAutoscale", spec.Version, "requires environment version 3"))
+		}
+		if spec.AllowedFunctionsPerContainer == AllowedFunctionsPerContainerInfinite {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "EnvironmentSpec.PoolAutoscale", spec.AllowedFunctionsPerContainer, "not supported with infinite functions per container"))
+		}
+		errs = errors.Join(errs, spec.PoolAutoscale.Validate())
+	}
+
+	classes := make(map[string]struct{}, len(spec.ResourceClasses))
+	for _, rc := range spec.ResourceClasses {
+		errs = errors.Join(errs, rc.Validate())
+		if _, dup := classes[rc.Name]; dup {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "EnvironmentSpec.ResourceClasses", rc.Name, "duplicate resource class name"))
+		}
+		classes[rc.Name] = struct{}{}
+	}
+
 	if spec.TerminationGracePeriod != nil && *spec.TerminationGracePeriod < 0 {
 		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "EnvironmentSpec.TerminationGracePeriod", *spec.TerminationGracePeriod, "must be greater than or equal to 0"))
 	}
@@ -945,6 +1362,14 @@ func (spec HTTPTriggerSpec) Validate() error {
 	if spec.CorsConfig != nil {
 		errs = errors.Join(errs, spec.CorsConfig.Validate())
 	}
+	errs = errors.Join(errs, spec.Auth.Validate())
+	errs = errors.Join(errs, spec.ValidateRateLimit())
+	errs = errors.Join(errs, spec.ValidateMirror())
+	errs = errors.Join(errs, spec.ValidateMatch())
+	errs = errors.Join(errs, spec.Cache.Validate())
+	errs = errors.Join(errs, spec.ValidateGRPC())
+	errs = errors.Join(errs, spec.ValidateTransform())
+	errs = errors.Join(errs, spec.OpenAPI.Validate())
 
 	// Path validation. HTTPTrigger has no admission webhook on current main
 	// (the API server's CEL evaluation is the admission gate); these checks
@@ -955,9 +1380,9 @@ func (spec HTTPTriggerSpec) Validate() error {
 	if spec.Prefix != nil {
 		prefix = *spec.Prefix
 	}
-	if spec.RelativeURL == "" && prefix == "" {
+	if spec.RelativeURL == "" && prefix == "" && spec.GRPC == nil {
 		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec", "",
-			"at least one of relativeurl or prefix must be set"))
+			"at least one of relativeurl, prefix or grpc must be set"))
 	}
 	if spec.RelativeURL != "" {
 		errs = errors.Join(errs, validateTriggerPath("HTTPTriggerSpec.RelativeURL", spec.RelativeURL))
@@ -1063,6 +1488,496 @@ func (c *HTTPTriggerCorsConfig) Validate() error {
 	return errs
 }
 
+// Validate checks the parts of an authentication policy CEL cannot: that the
+// issuer and JWKS locations are absolute http(s) URLs, and that forwarded
+// claims and the API-key header are well-formed header names. Forwarded
+// claims are confined to the X-Fission-Auth- prefix so the router's strip of
+// client-supplied identity headers covers every header it later sets. The
+// router runs this before installing the route, so a bad policy never serves
+// unauthenticated. (Validate is nil-safe.)
+func (a *HTTPTriggerAuth) Validate() error {
+	if a == nil {
+		return nil
+	}
+	var errs error
+	switch a.Type {
+	case HTTPTriggerAuthAnonymous:
+	case HTTPTriggerAuthJWT:
+		if a.JWT == nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.JWT", nil, "required when Type is jwt"))
+		}
+	case HTTPTriggerAuthAPIKey:
+		if a.APIKey == nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.APIKey", nil, "required when Type is apiKey"))
+		}
+	default:
+		errs = errors.Join(errs, MakeValidationErr(ErrorUnsupportedType, "HTTPTriggerSpec.Auth.Type", a.Type, "must be anonymous, jwt or apiKey"))
+	}
+	if a.JWT != nil && a.Type != HTTPTriggerAuthJWT {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.JWT", a.Type, "only valid when Type is jwt"))
+	}
+	if a.APIKey != nil && a.Type != HTTPTriggerAuthAPIKey {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.APIKey", a.Type, "only valid when Type is apiKey"))
+	}
+
+	if j := a.JWT; j != nil {
+		errs = errors.Join(errs, validateAuthURL("HTTPTriggerSpec.Auth.JWT.Issuer", j.Issuer, true))
+		errs = errors.Join(errs, validateAuthURL("HTTPTriggerSpec.Auth.JWT.JWKSURI", j.JWKSURI, false))
+		for claim, header := range j.ForwardClaims {
+			if claim == "" {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.JWT.ForwardClaims", claim, "claim name must not be empty"))
+			}
+			if e := validation.IsHTTPHeaderName(header); len(e) > 0 {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.JWT.ForwardClaims", header, e...))
+				continue
+			}
+			canonical := http.CanonicalHeaderKey(header)
+			if !strings.HasPrefix(canonical, HTTPTriggerAuthHeaderPrefix) || canonical == HTTPTriggerAuthHeaderPrefix {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.JWT.ForwardClaims", header,
+					"header must start with "+HTTPTriggerAuthHeaderPrefix))
+			}
+			if canonical == HTTPTriggerAuthSubjectHeader {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.JWT.ForwardClaims", header,
+					"is reserved for the sub claim"))
+			}
+		}
+	}
+
+	if k := a.APIKey; k != nil {
+		if e := validation.IsDNS1123Subdomain(k.SecretName); len(e) > 0 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.APIKey.SecretName", k.SecretName, e...))
+		}
+		if k.Header != "" {
+			if e := validation.IsHTTPHeaderName(k.Header); len(e) > 0 {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.APIKey.Header", k.Header, e...))
+			} else if strings.HasPrefix(http.CanonicalHeaderKey(k.Header), HTTPTriggerAuthHeaderPrefix) {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Auth.APIKey.Header", k.Header,
+					"must not use the router-owned "+HTTPTriggerAuthHeaderPrefix+" prefix"))
+			}
+		}
+	}
+	return errs
+}
+
+// ValidateRateLimit checks the trigger's rate limit, including the claim key
+// source's dependency on a jwt Auth policy, which spans two fields. The
+// router calls it on its own to gate the route: a claim-keyed limit without
+// verified claims would silently count every caller in one bucket.
+func (spec *HTTPTriggerSpec) ValidateRateLimit() error {
+	rl := spec.RateLimit
+	if rl == nil {
+		return nil
+	}
+	err := rl.Validate()
+	if rl.Key.Source == RateLimitKeyClaim && (spec.Auth == nil || spec.Auth.Type != HTTPTriggerAuthJWT) {
+		err = errors.Join(err, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.RateLimit.Key.Source", rl.Key.Source,
+			"claim requires an Auth policy of type jwt"))
+	}
+	return err
+}
+
+// Validate checks an HTTPTrigger rate limit on its own. It is nil-safe; see
+// HTTPTriggerSpec.ValidateRateLimit for the check against the Auth policy.
+func (rl *HTTPTriggerRateLimit) Validate() error {
+	if rl == nil {
+		return nil
+	}
+	var errs error
+	if rl.Requests < 1 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.RateLimit.Requests", rl.Requests, "must be at least 1"))
+	}
+	if rl.PeriodSeconds < 0 || rl.PeriodSeconds > 86400 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.RateLimit.PeriodSeconds", rl.PeriodSeconds, "must be between 1 and 86400"))
+	}
+	if rl.Burst < 0 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.RateLimit.Burst", rl.Burst, "must be at least 1"))
+	}
+	k := rl.Key
+	switch k.Source {
+	case "", RateLimitKeyTrigger, RateLimitKeyIP:
+	case RateLimitKeyHeader:
+		if e := validation.IsHTTPHeaderName(k.Name); len(e) > 0 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.RateLimit.Key.Name", k.Name, e...))
+		}
+	case RateLimitKeyClaim:
+		if k.Name == "" {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.RateLimit.Key.Name", k.Name, "required when Source is claim"))
+		}
+	default:
+		errs = errors.Join(errs, MakeValidationErr(ErrorUnsupportedType, "HTTPTriggerSpec.RateLimit.Key.Source", k.Source, "must be trigger, ip, header or claim"))
+	}
+	if k.TrustedProxyHops < 0 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.RateLimit.Key.TrustedProxyHops", k.TrustedProxyHops, "must not be negative"))
+	}
+	return errs
+}
+
+// ValidateMirror checks the trigger's traffic mirror, including its
+// dependency on a name-type function reference: a FunctionWeights canary has
+// no single function whose versions the mirror could name. The router calls
+// it on its own to gate the route, like ValidateRateLimit.
+func (spec *HTTPTriggerSpec) ValidateMirror() error {
+	if spec.Mirror == nil {
+		return nil
+	}
+	err := spec.Mirror.Validate("HTTPTriggerSpec.Mirror")
+	if spec.FunctionReference.Type != FunctionReferenceTypeFunctionName {
+		err = errors.Join(err, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Mirror", spec.FunctionReference.Type,
+			"requires a function reference of type name"))
+	}
+	return err
+}
+
+// Validate checks a traffic mirror on its own; field prefixes the error paths
+// so the trigger and alias report their own. It is nil-safe.
+func (m *TrafficMirror) Validate(field string) error {
+	if m == nil {
+		return nil
+	}
+	errs := ValidateKubeName(field+".Version", m.Version)
+	if m.Percent < 0 || m.Percent > 100 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Percent", m.Percent, "must be between 1 and 100"))
+	}
+	return errs
+}
+
+// EffectivePercent returns Percent, or DefaultTrafficMirrorPercent when it is
+// unset.
+func (m *TrafficMirror) EffectivePercent() int32 {
+	if m.Percent == 0 {
+		return DefaultTrafficMirrorPercent
+	}
+	return m.Percent
+}
+
+// ValidateMatch checks the trigger's match block, including that Match.Hosts
+// is not combined with Host: the two would have to hold at once, and only
+// Host reaches the ingress or gateway route. The router calls it on its own
+// to gate the route, like ValidateRateLimit.
+func (spec *HTTPTriggerSpec) ValidateMatch() error {
+	m := spec.Match
+	if m == nil {
+		return nil
+	}
+	var errs error
+	if len(m.Hosts) > 0 && spec.Host != "" {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Match.Hosts", m.Hosts, "cannot be combined with Host"))
+	}
+	return errors.Join(errs, m.Validate())
+}
+
+// Validate checks a match block on its own. It is nil-safe.
+func (m *HTTPTriggerMatch) Validate() error {
+	if m == nil {
+		return nil
+	}
+	var errs error
+	seen := make(map[string]struct{}, len(m.Headers))
+	for i := range m.Headers {
+		c := &m.Headers[i]
+		field := fmt.Sprintf("HTTPTriggerSpec.Match.Headers[%d]", i)
+		if e := validation.IsHTTPHeaderName(c.Name); len(e) > 0 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Name", c.Name, e...))
+		}
+		name := http.CanonicalHeaderKey(c.Name)
+		if _, dup := seen[name]; dup {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Name", c.Name, "duplicate header"))
+		}
+		seen[name] = struct{}{}
+		errs = errors.Join(errs, c.validate(field))
+	}
+	clear(seen)
+	for i := range m.QueryParams {
+		c := &m.QueryParams[i]
+		field := fmt.Sprintf("HTTPTriggerSpec.Match.QueryParams[%d]", i)
+		if c.Name == "" {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Name", c.Name, "is required"))
+		}
+		if _, dup := seen[c.Name]; dup {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Name", c.Name, "duplicate query parameter"))
+		}
+		seen[c.Name] = struct{}{}
+		errs = errors.Join(errs, c.validate(field))
+	}
+	for i, h := range m.Hosts {
+		field := fmt.Sprintf("HTTPTriggerSpec.Match.Hosts[%d]", i)
+		if e := validation.IsDNS1123Subdomain(strings.TrimPrefix(h, "*.")); len(e) > 0 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field, h, e...))
+		}
+	}
+	return errs
+}
+
+// validate checks the condition's type and value; the caller checks the
+// name, whose rules differ between headers and query parameters.
+func (c *HTTPMatchCondition) validate(field string) error {
+	switch c.Type {
+	case "", HTTPMatchExact:
+		if c.Value == "" {
+			return MakeValidationErr(ErrorInvalidValue, field+".Value", c.Value, "is required unless Type is present")
+		}
+	case HTTPMatchRegex:
+		if _, err := c.Regexp(); err != nil {
+			return MakeValidationErr(ErrorInvalidValue, field+".Value", c.Value, err.Error())
+		}
+	case HTTPMatchPresent:
+		if c.Value != "" {
+			return MakeValidationErr(ErrorInvalidValue, field+".Value", c.Value, "must be empty when Type is present")
+		}
+	default:
+		return MakeValidationErr(ErrorUnsupportedType, field+".Type", c.Type, "must be exact, regex or present")
+	}
+	return nil
+}
+
+// Regexp compiles a regex condition's Value, anchored at both ends so a
+// pattern such as "2" matches the value 2 and not 12.
+func (c *HTTPMatchCondition) Regexp() (*regexp.Regexp, error) {
+	if c.Value == "" {
+		return nil, errors.New("is required when Type is regex")
+	}
+	return regexp.Compile("^(?:" + c.Value + ")$")
+}
+
+// Validate checks a response cache. It is nil-safe.
+func (c *HTTPTriggerCache) Validate() error {
+	if c == nil {
+		return nil
+	}
+	var errs error
+	if c.TTLSeconds < 1 || c.TTLSeconds > 86400 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Cache.TTLSeconds", c.TTLSeconds, "must be between 1 and 86400"))
+	}
+	if c.StaleWhileRevalidateSeconds < 0 || c.StaleWhileRevalidateSeconds > 86400 {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Cache.StaleWhileRevalidateSeconds", c.StaleWhileRevalidateSeconds, "must be between 0 and 86400"))
+	}
+	if c.MaxObjectBytes < 0 || c.MaxObjectBytes > MaxHTTPTriggerCacheObjectBytes {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Cache.MaxObjectBytes", c.MaxObjectBytes,
+			fmt.Sprintf("must be between 0 (default) and %d", MaxHTTPTriggerCacheObjectBytes)))
+	}
+	for i, h := range c.Vary {
+		if e := validation.IsHTTPHeaderName(h); len(e) > 0 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, fmt.Sprintf("HTTPTriggerSpec.Cache.Vary[%d]", i), h, e...))
+		}
+	}
+	return errs
+}
+
+// grpcName is a protobuf identifier; grpcService is a fully qualified service
+// name, a dotted sequence of them.
+var (
+	grpcName    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	grpcService = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+)
+
+// ValidateGRPC checks the trigger's gRPC routing: a well-formed service and
+// method names, no path of its own and no method but POST, which is all gRPC
+// uses. The router calls it on its own to gate the route, like
+// ValidateMatch.
+func (spec *HTTPTriggerSpec) ValidateGRPC() error {
+	g := spec.GRPC
+	if g == nil {
+		return nil
+	}
+	var errs error
+	if len(g.Service) > 253 || !grpcService.MatchString(g.Service) {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.GRPC.Service", g.Service, "must be a fully qualified gRPC service name, e.g. helloworld.Greeter"))
+	}
+	for i, m := range g.Methods {
+		if !grpcName.MatchString(m) {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, fmt.Sprintf("HTTPTriggerSpec.GRPC.Methods[%d]", i), m, "must be a gRPC method name"))
+		}
+	}
+	if spec.RelativeURL != "" || spec.Prefix != nil && *spec.Prefix != "" {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.GRPC", g.Service, "cannot be combined with relativeurl or prefix"))
+	}
+	for _, m := range spec.Methods {
+		if m != http.MethodPost {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Methods", spec.Methods, "a grpc trigger serves POST only"))
+			break
+		}
+	}
+	if spec.Method != "" && spec.Method != http.MethodPost {
+		errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Method", spec.Method, "a grpc trigger serves POST only"))
+	}
+	return errs
+}
+
+// PathPrefix is the path every call to the service starts with.
+func (g *HTTPTriggerGRPC) PathPrefix() string {
+	return "/" + g.Service + "/"
+}
+
+// ForwardedPath is the path the router forwards a request for path to the
+// function: the path itself for a gRPC trigger, the path with Prefix trimmed
+// (kept with KeepPrefix) for a prefix trigger, and "/" for a trigger on a
+// RelativeURL. A Transform path template is applied on top of it.
+func (spec *HTTPTriggerSpec) ForwardedPath(path string) string {
+	switch {
+	case spec.GRPC != nil:
+		return path
+	case spec.Prefix != nil && *spec.Prefix != "":
+		if !spec.KeepPrefix {
+			path = strings.TrimPrefix(path, *spec.Prefix)
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		return path
+	}
+	return "/"
+}
+
+// RewritesPath reports whether the transform replaces the forwarded path.
+func (t *HTTPTriggerTransform) RewritesPath() bool {
+	return t != nil && t.Request != nil && t.Request.Path != ""
+}
+
+// HTTPTransformPlaceholder matches a {{...}} placeholder in an
+// HTTPRequestTransform template; the submatch is what is between the braces,
+// a source and, for all but {{path}}, a name after a dot.
+var HTTPTransformPlaceholder = regexp.MustCompile(`\{\{([^{}]*)\}\}`)
+
+// ValidateTransform checks the trigger's transform: known placeholders in
+// every template, a body template that is JSON once expanded, a path
+// template that stays a valid escaped path, and valid header names. A gRPC
+// trigger may only transform headers: its path names the method and its
+// body is protobuf.
+func (spec *HTTPTriggerSpec) ValidateTransform() error {
+	t := spec.Transform
+	if t == nil {
+		return nil
+	}
+	var errs error
+	if r := t.Request; r != nil {
+		errs = errors.Join(errs, r.Headers.validate("HTTPTriggerSpec.Transform.Request.Headers", true))
+		if r.Path != "" {
+			errs = errors.Join(errs, validateTransformTemplate("HTTPTriggerSpec.Transform.Request.Path", r.Path, false))
+			// Every placeholder expands to escaped text, so it is enough that
+			// the literal parts unescape.
+			if !strings.HasPrefix(r.Path, "/") {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Transform.Request.Path", r.Path, "must start with /"))
+			} else if _, err := url.PathUnescape(HTTPTransformPlaceholder.ReplaceAllString(r.Path, "")); err != nil {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Transform.Request.Path", r.Path, err.Error()))
+			}
+		}
+		if r.Body != "" {
+			errs = errors.Join(errs, validateTransformTemplate("HTTPTriggerSpec.Transform.Request.Body", r.Body, true))
+			if !json.Valid([]byte(HTTPTransformPlaceholder.ReplaceAllString(r.Body, ""))) {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Transform.Request.Body", r.Body, "must be a JSON document, with placeholders inside strings"))
+			}
+		}
+		if spec.GRPC != nil && (r.Path != "" || r.Body != "") {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.Transform.Request", spec.GRPC.Service, "a grpc trigger may only transform headers"))
+		}
+	}
+	if r := t.Response; r != nil {
+		errs = errors.Join(errs, r.Headers.validate("HTTPTriggerSpec.Transform.Response.Headers", false))
+	}
+	return errs
+}
+
+func (h *HTTPHeaderTransform) validate(field string, placeholders bool) error {
+	if h == nil {
+		return nil
+	}
+	var errs error
+	for i, name := range h.Remove {
+		if e := validation.IsHTTPHeaderName(name); len(e) > 0 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, fmt.Sprintf("%s.Remove[%d]", field, i), name, e...))
+		}
+	}
+	for _, list := range []struct {
+		name   string
+		values []HTTPHeaderValue
+	}{{"Set", h.Set}, {"Add", h.Add}} {
+		for i, v := range list.values {
+			f := fmt.Sprintf("%s.%s[%d]", field, list.name, i)
+			if e := validation.IsHTTPHeaderName(v.Name); len(e) > 0 {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, f+".Name", v.Name, e...))
+			}
+			if placeholders {
+				errs = errors.Join(errs, validateTransformTemplate(f+".Value", v.Value, false))
+			} else if HTTPTransformPlaceholder.MatchString(v.Value) {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, f+".Value", v.Value, "placeholders are not expanded in response headers"))
+			}
+		}
+	}
+	return errs
+}
+
+// validateTransformTemplate rejects unknown placeholder sources and, outside
+// a body template, {{form.*}}: only the body template reads the form.
+func validateTransformTemplate(field, tmpl string, body bool) error {
+	var errs error
+	for _, m := range HTTPTransformPlaceholder.FindAllStringSubmatch(tmpl, -1) {
+		source, name, dotted := strings.Cut(m[1], ".")
+		switch {
+		case source == "path" && !dotted:
+		case source == "form" && name != "" && !body:
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field, m[0], "{{form.*}} is only available in the body template"))
+		case (source == "param" || source == "query" || source == "header" || source == "form") && name != "":
+		default:
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field, m[0],
+				"unknown placeholder; use {{path}}, {{param.<name>}}, {{query.<name>}}, {{header.<name>}} or {{form.<name>}}"))
+		}
+	}
+	return errs
+}
+
+// Validate checks the OpenAPI block: each schema must be a JSON object or
+// boolean, the two forms a JSON Schema takes. It is nil-safe.
+func (o *HTTPTriggerOpenAPI) Validate() error {
+	if o == nil {
+		return nil
+	}
+	var errs error
+	for _, schema := range []struct {
+		field string
+		value *apiextensionsv1.JSON
+	}{{"RequestSchema", o.RequestSchema}, {"ResponseSchema", o.ResponseSchema}} {
+		if schema.value == nil || len(schema.value.Raw) == 0 {
+			continue
+		}
+		var v any
+		if err := json.Unmarshal(schema.value.Raw, &v); err != nil {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.OpenAPI."+schema.field, string(schema.value.Raw), err.Error()))
+			continue
+		}
+		switch v.(type) {
+		case map[string]any, bool:
+		default:
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "HTTPTriggerSpec.OpenAPI."+schema.field, string(schema.value.Raw), "must be a JSON Schema object or boolean"))
+		}
+	}
+	return errs
+}
+
+// EffectiveMaxObjectBytes returns MaxObjectBytes, or
+// DefaultHTTPTriggerCacheMaxObjectBytes when it is unset.
+func (c *HTTPTriggerCache) EffectiveMaxObjectBytes() int64 {
+	if c.MaxObjectBytes == 0 {
+		return DefaultHTTPTriggerCacheMaxObjectBytes
+	}
+	return c.MaxObjectBytes
+}
+
+// validateAuthURL requires an absolute http(s) URL with a host; an empty value
+// is an error only when required.
+func validateAuthURL(field, raw string, required bool) error {
+	if raw == "" {
+		if required {
+			return MakeValidationErr(ErrorInvalidValue, field, raw, "is required")
+		}
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return MakeValidationErr(ErrorInvalidValue, field, raw, "must be an absolute http or https URL")
+	}
+	return nil
+}
+
 func (config IngressConfig) Validate() error {
 	var errs error
 
@@ -1552,6 +2467,15 @@ func (spec FunctionAliasSpec) Validate() error {
 		}
 	}
 
+	if spec.Mirror != nil {
+		errs = errors.Join(errs, spec.Mirror.Validate("FunctionAliasSpec.Mirror"))
+		// Mirroring a version onto itself doubles its load and compares
+		// nothing.
+		if spec.Mirror.Version != "" && (spec.Mirror.Version == spec.Version || spec.Mirror.Version == spec.SecondaryVersion) {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "FunctionAliasSpec.Mirror.Version", spec.Mirror.Version, "must differ from version and secondaryVersion"))
+		}
+	}
+
 	return errs
 }
 
@@ -1614,3 +2538,97 @@ func (fa *FunctionAlias) Validate() error {
 
 	return errs
 }
+
+// canaryPlaceholder matches a {{...}} placeholder in a CanaryAnalysisTemplate
+// query.
+var canaryPlaceholder = regexp.MustCompile(`\{\{([^{}]*)\}\}`)
+
+// CanaryAnalysisPlaceholders are the placeholders a CanaryAnalysisTemplate
+// query may use; see the type's doc for what each is replaced with.
+var CanaryAnalysisPlaceholders = []string{"function", "version", "namespace", "path", "window"}
+
+// ValidateGates checks the analysis and webhook gates of a canary config,
+// which the CRD schema can only check field by field: duplicate names,
+// unknown query placeholders, inverted bounds and unparsable URLs. A config
+// that fails it is never scheduled.
+func (spec *CanaryConfigSpec) ValidateGates() error {
+	var errs error
+	if a := spec.Analysis; a != nil {
+		if a.MinRequests < 0 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, "CanaryConfigSpec.Analysis.MinRequests", a.MinRequests, "must not be negative"))
+		}
+		percentiles := make(map[int32]struct{}, len(a.Latency))
+		for i, l := range a.Latency {
+			field := fmt.Sprintf("CanaryConfigSpec.Analysis.Latency[%d]", i)
+			if l.Percentile < 1 || l.Percentile > 99 {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Percentile", l.Percentile, "must be between 1 and 99"))
+			}
+			if _, dup := percentiles[l.Percentile]; dup {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Percentile", l.Percentile, "duplicate percentile"))
+			}
+			percentiles[l.Percentile] = struct{}{}
+			if l.MaxMilliseconds < 1 {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".MaxMilliseconds", l.MaxMilliseconds, "must be positive"))
+			}
+		}
+		names := make(map[string]struct{}, len(a.Templates))
+		for i := range a.Templates {
+			t := &a.Templates[i]
+			field := fmt.Sprintf("CanaryConfigSpec.Analysis.Templates[%d]", i)
+			if t.Name == "" {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Name", t.Name, "is required"))
+			}
+			if _, dup := names[t.Name]; dup {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Name", t.Name, "duplicate template"))
+			}
+			names[t.Name] = struct{}{}
+			if strings.TrimSpace(t.Query) == "" {
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Query", t.Query, "is required"))
+			}
+			for _, m := range canaryPlaceholder.FindAllStringSubmatch(t.Query, -1) {
+				if !slices.Contains(CanaryAnalysisPlaceholders, m[1]) {
+					errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Query", m[0],
+						"unknown placeholder; use one of {{"+strings.Join(CanaryAnalysisPlaceholders, "}}, {{")+"}}"))
+				}
+			}
+			switch {
+			case t.Min == nil && t.Max == nil:
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field, t.Name, "needs min, max or both"))
+			case t.Min != nil && t.Max != nil && t.Min.Cmp(*t.Max) > 0:
+				errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Min", t.Min.String(), "must not exceed max"))
+			}
+		}
+	}
+	names := make(map[string]struct{}, len(spec.Webhooks))
+	for i, w := range spec.Webhooks {
+		field := fmt.Sprintf("CanaryConfigSpec.Webhooks[%d]", i)
+		if w.Name == "" {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Name", w.Name, "is required"))
+		}
+		if _, dup := names[w.Name]; dup {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".Name", w.Name, "duplicate webhook"))
+		}
+		names[w.Name] = struct{}{}
+		if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".URL", w.URL, "must be an absolute http or https URL"))
+		}
+		if w.TimeoutSeconds < 0 || w.TimeoutSeconds > 60 {
+			errs = errors.Join(errs, MakeValidationErr(ErrorInvalidValue, field+".TimeoutSeconds", w.TimeoutSeconds, "must be between 1 and 60"))
+		}
+		switch w.FailurePolicy {
+		case "", CanaryWebhookHold, CanaryWebhookRollback:
+		default:
+			errs = errors.Join(errs, MakeValidationErr(ErrorUnsupportedType, field+".FailurePolicy", w.FailurePolicy, "must be Hold or Rollback"))
+		}
+	}
+	return errs
+}
+
+// EffectiveTimeout returns the webhook's call timeout, defaulting an unset
+// TimeoutSeconds to DefaultCanaryWebhookTimeoutSeconds.
+func (w *CanaryWebhook) EffectiveTimeout() time.Duration {
+	if w.TimeoutSeconds == 0 {
+		return time.Duration(DefaultCanaryWebhookTimeoutSeconds) * time.Second
+	}
+	return time.Duration(w.TimeoutSeconds) * time.Second
+}
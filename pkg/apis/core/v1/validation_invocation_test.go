@@ -39,6 +39,9 @@ func TestInvocationConfigValidate(t *testing.T) {
 		{"zero maxAge", InvocationConfig{MaxAge: md(0)}, true},
 		{"negative maxAge", InvocationConfig{MaxAge: md(-time.Hour)}, true},
 		{"positive maxAge ok", InvocationConfig{MaxAge: md(time.Hour)}, false},
+		{"priority high ok", InvocationConfig{Priority: InvocationPriorityHigh}, false},
+		{"priority low ok", InvocationConfig{Priority: InvocationPriorityLow}, false},
+		{"priority unknown", InvocationConfig{Priority: "urgent"}, true},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
@@ -117,7 +120,12 @@ func TestDestinationRefValidate(t *testing.T) {
 		{"neither set", DestinationRef{}, true},
 		{"both set", DestinationRef{Function: fnRef("next"), Topic: &TopicRef{MessageQueueType: "kafka", Topic: "t"}}, true},
 		{"kafka topic ok (egress)", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeKafka, Topic: "t1"}}, false},
-		{"keda-only type rejected (no egress head)", DestinationRef{Topic: &TopicRef{MessageQueueType: "nats-jetstream", Topic: "t"}}, true},
+		{"keda-only type rejected (no egress head)", DestinationRef{Topic: &TopicRef{MessageQueueType: "aws-sqs-queue", Topic: "t"}}, true},
+		{"nats subject ok (egress)", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeNatsJetStream, Topic: "orders.created"}}, false},
+		{"nats empty token rejected", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeNatsJetStream, Topic: "orders..created"}}, true},
+		{"nats trailing dot rejected", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeNatsJetStream, Topic: "orders."}}, true},
+		{"rabbitmq queue ok (egress)", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeRabbitMQ, Topic: "orders.created"}}, false},
+		{"rabbitmq reserved prefix rejected", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeRabbitMQ, Topic: "amq.gen-1"}}, true},
 		{"kafka dot topic rejected (broker refuses . and ..)", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeKafka, Topic: "."}}, true},
 		{"kafka internal-style topic rejected (leading _)", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeKafka, Topic: "__consumer_offsets"}}, true},
 		{"kafka dotted topic ok", DestinationRef{Topic: &TopicRef{MessageQueueType: MessageQueueTypeKafka, Topic: "orders.v2"}}, false},
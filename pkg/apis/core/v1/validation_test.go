@@ -11,6 +11,8 @@ import (
 	"testing"
 
 	"github.com/gkampitakis/go-snaps/snaps"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -176,6 +178,284 @@ func TestHTTPTriggerCorsConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestHTTPTriggerAuth_Validate(t *testing.T) {
+	jwtAuth := func(j HTTPTriggerJWTAuth) *HTTPTriggerAuth {
+		return &HTTPTriggerAuth{Type: HTTPTriggerAuthJWT, JWT: &j}
+	}
+	for _, tc := range []struct {
+		name   string
+		auth   *HTTPTriggerAuth
+		errSub string // "" => valid
+	}{
+		{name: "nil receiver is no-op"},
+		{name: "anonymous", auth: &HTTPTriggerAuth{Type: HTTPTriggerAuthAnonymous}},
+		{name: "valid jwt", auth: jwtAuth(HTTPTriggerJWTAuth{
+			Issuer:        "https://accounts.example.com",
+			JWKSURI:       "https://accounts.example.com/keys",
+			ForwardClaims: map[string]string{"email": "x-fission-auth-email"},
+		})},
+		{name: "valid api key", auth: &HTTPTriggerAuth{Type: HTTPTriggerAuthAPIKey, APIKey: &HTTPTriggerAPIKeyAuth{SecretName: "keys", Header: "X-Key"}}},
+		{name: "unknown type", auth: &HTTPTriggerAuth{Type: "basic"}, errSub: "anonymous, jwt or apiKey"},
+		{name: "jwt block missing", auth: &HTTPTriggerAuth{Type: HTTPTriggerAuthJWT}, errSub: "required when Type is jwt"},
+		{name: "api key block on jwt policy", auth: &HTTPTriggerAuth{
+			Type: HTTPTriggerAuthJWT, JWT: &HTTPTriggerJWTAuth{Issuer: "https://a.example.com"}, APIKey: &HTTPTriggerAPIKeyAuth{SecretName: "keys"},
+		}, errSub: "only valid when Type is apiKey"},
+		{name: "issuer without scheme", auth: jwtAuth(HTTPTriggerJWTAuth{Issuer: "accounts.example.com"}), errSub: "absolute http or https URL"},
+		{name: "jwks uri not http", auth: jwtAuth(HTTPTriggerJWTAuth{Issuer: "https://a.example.com", JWKSURI: "file:///etc/keys"}), errSub: "absolute http or https URL"},
+		{name: "forwarded header outside the prefix", auth: jwtAuth(HTTPTriggerJWTAuth{
+			Issuer: "https://a.example.com", ForwardClaims: map[string]string{"email": "X-User-Email"},
+		}), errSub: "must start with X-Fission-Auth-"},
+		{name: "forwarded header is the subject header", auth: jwtAuth(HTTPTriggerJWTAuth{
+			Issuer: "https://a.example.com", ForwardClaims: map[string]string{"uid": "X-Fission-Auth-Subject"},
+		}), errSub: "reserved for the sub claim"},
+		{name: "forwarded header not a token", auth: jwtAuth(HTTPTriggerJWTAuth{
+			Issuer: "https://a.example.com", ForwardClaims: map[string]string{"email": "X-Fission-Auth-E mail"},
+		}), errSub: "ForwardClaims"},
+		{name: "bad secret name", auth: &HTTPTriggerAuth{Type: HTTPTriggerAuthAPIKey, APIKey: &HTTPTriggerAPIKeyAuth{SecretName: "Keys_1"}}, errSub: "SecretName"},
+		{name: "api key header in the router-owned prefix", auth: &HTTPTriggerAuth{
+			Type: HTTPTriggerAuthAPIKey, APIKey: &HTTPTriggerAPIKeyAuth{SecretName: "keys", Header: "X-Fission-Auth-Key"},
+		}, errSub: "router-owned"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.auth.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %v does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPTriggerRateLimit_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		rl     *HTTPTriggerRateLimit
+		auth   *HTTPTriggerAuth
+		errSub string // "" => valid
+	}{
+		{name: "nil receiver is no-op"},
+		{name: "per trigger defaults", rl: &HTTPTriggerRateLimit{Requests: 10}},
+		{name: "per ip behind one proxy", rl: &HTTPTriggerRateLimit{Requests: 10, PeriodSeconds: 60, Burst: 20, Key: HTTPTriggerRateLimitKey{Source: RateLimitKeyIP, TrustedProxyHops: 1}}},
+		{name: "per header", rl: &HTTPTriggerRateLimit{Requests: 5, Key: HTTPTriggerRateLimitKey{Source: RateLimitKeyHeader, Name: "X-Tenant"}}},
+		{name: "per claim with jwt auth", rl: &HTTPTriggerRateLimit{Requests: 5, Key: HTTPTriggerRateLimitKey{Source: RateLimitKeyClaim, Name: "sub"}},
+			auth: &HTTPTriggerAuth{Type: HTTPTriggerAuthJWT, JWT: &HTTPTriggerJWTAuth{Issuer: "https://a.example.com"}}},
+		{name: "zero requests", rl: &HTTPTriggerRateLimit{}, errSub: "Requests"},
+		{name: "period too long", rl: &HTTPTriggerRateLimit{Requests: 1, PeriodSeconds: 86401}, errSub: "PeriodSeconds"},
+		{name: "unknown source", rl: &HTTPTriggerRateLimit{Requests: 1, Key: HTTPTriggerRateLimitKey{Source: "cookie"}}, errSub: "trigger, ip, header or claim"},
+		{name: "header source without name", rl: &HTTPTriggerRateLimit{Requests: 1, Key: HTTPTriggerRateLimitKey{Source: RateLimitKeyHeader}}, errSub: "Key.Name"},
+		{name: "claim source without name", rl: &HTTPTriggerRateLimit{Requests: 1, Key: HTTPTriggerRateLimitKey{Source: RateLimitKeyClaim}},
+			auth: &HTTPTriggerAuth{Type: HTTPTriggerAuthJWT, JWT: &HTTPTriggerJWTAuth{Issuer: "https://a.example.com"}}, errSub: "required when Source is claim"},
+		{name: "claim source without jwt auth", rl: &HTTPTriggerRateLimit{Requests: 1, Key: HTTPTriggerRateLimitKey{Source: RateLimitKeyClaim, Name: "sub"}},
+			auth: &HTTPTriggerAuth{Type: HTTPTriggerAuthAnonymous}, errSub: "Auth policy of type jwt"},
+		{name: "negative proxy hops", rl: &HTTPTriggerRateLimit{Requests: 1, Key: HTTPTriggerRateLimitKey{Source: RateLimitKeyIP, TrustedProxyHops: -1}}, errSub: "TrustedProxyHops"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := HTTPTriggerSpec{
+				RelativeURL:       "/rl",
+				FunctionReference: FunctionReference{Type: FunctionReferenceTypeFunctionName, Name: "fn"},
+				RateLimit:         tc.rl,
+				Auth:              tc.auth,
+			}
+			err := spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %v does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPTriggerMirror_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		mirror *TrafficMirror
+		ref    FunctionReference
+		errSub string // "" => valid
+	}{
+		{name: "nil receiver is no-op"},
+		{name: "default percent", mirror: &TrafficMirror{Version: "fn-v2"}},
+		{name: "ten percent onto an aliased route", mirror: &TrafficMirror{Version: "fn-v2", Percent: 10},
+			ref: FunctionReference{Type: FunctionReferenceTypeFunctionName, Name: "fn", Alias: "prod"}},
+		{name: "missing version", mirror: &TrafficMirror{Percent: 10}, errSub: "Mirror.Version"},
+		{name: "percent over 100", mirror: &TrafficMirror{Version: "fn-v2", Percent: 101}, errSub: "Mirror.Percent"},
+		{name: "function weights reference", mirror: &TrafficMirror{Version: "fn-v2"},
+			ref:    FunctionReference{Type: FunctionReferenceTypeFunctionWeights, FunctionWeights: map[string]int{"fn": 50, "fn2": 50}},
+			errSub: "requires a function reference of type name"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := HTTPTriggerSpec{
+				RelativeURL:       "/mirror",
+				FunctionReference: FunctionReference{Type: FunctionReferenceTypeFunctionName, Name: "fn"},
+				Mirror:            tc.mirror,
+			}
+			if tc.ref.Type != "" {
+				spec.FunctionReference = tc.ref
+			}
+			err := spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %v does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPTriggerMatch_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		match  *HTTPTriggerMatch
+		host   string
+		errSub string // "" => valid
+	}{
+		{name: "nil receiver is no-op"},
+		{name: "header, query and hosts", match: &HTTPTriggerMatch{
+			Headers:     []HTTPMatchCondition{{Name: "X-Api-Version", Value: "2"}, {Name: "X-Debug", Type: HTTPMatchPresent}},
+			QueryParams: []HTTPMatchCondition{{Name: "beta", Type: HTTPMatchRegex, Value: "true|1"}},
+			Hosts:       []string{"api.example.com", "*.example.org"},
+		}},
+		{name: "bad header name", match: &HTTPTriggerMatch{Headers: []HTTPMatchCondition{{Name: "X Api", Value: "2"}}},
+			errSub: "Headers[0].Name"},
+		{name: "duplicate header differing in case", match: &HTTPTriggerMatch{Headers: []HTTPMatchCondition{
+			{Name: "x-api-version", Value: "1"}, {Name: "X-Api-Version", Value: "2"}}}, errSub: "duplicate header"},
+		{name: "exact without value", match: &HTTPTriggerMatch{QueryParams: []HTTPMatchCondition{{Name: "v"}}},
+			errSub: "QueryParams[0].Value"},
+		{name: "present with value", match: &HTTPTriggerMatch{Headers: []HTTPMatchCondition{{Name: "X-A", Type: HTTPMatchPresent, Value: "1"}}},
+			errSub: "must be empty"},
+		{name: "regex does not compile", match: &HTTPTriggerMatch{Headers: []HTTPMatchCondition{{Name: "X-A", Type: HTTPMatchRegex, Value: "(("}}},
+			errSub: "Headers[0].Value"},
+		{name: "unknown type", match: &HTTPTriggerMatch{Headers: []HTTPMatchCondition{{Name: "X-A", Type: "prefix", Value: "a"}}},
+			errSub: "Headers[0].Type"},
+		{name: "bad host", match: &HTTPTriggerMatch{Hosts: []string{"Example_.com"}}, errSub: "Hosts[0]"},
+		{name: "hosts with host", match: &HTTPTriggerMatch{Hosts: []string{"api.example.com"}}, host: "example.com",
+			errSub: "cannot be combined with Host"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := HTTPTriggerSpec{
+				Host:              tc.host,
+				RelativeURL:       "/match",
+				FunctionReference: FunctionReference{Type: FunctionReferenceTypeFunctionName, Name: "fn"},
+				Match:             tc.match,
+			}
+			err := spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %v does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPTriggerCache_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		cache  *HTTPTriggerCache
+		errSub string // "" => valid
+	}{
+		{name: "nil receiver is no-op"},
+		{name: "full", cache: &HTTPTriggerCache{TTLSeconds: 60, StaleWhileRevalidateSeconds: 30, Vary: []string{"Accept-Language"}, MaxObjectBytes: 1 << 20, Shared: true}},
+		{name: "missing ttl", cache: &HTTPTriggerCache{}, errSub: "Cache.TTLSeconds"},
+		{name: "negative stale window", cache: &HTTPTriggerCache{TTLSeconds: 60, StaleWhileRevalidateSeconds: -1}, errSub: "Cache.StaleWhileRevalidateSeconds"},
+		{name: "object over the cap", cache: &HTTPTriggerCache{TTLSeconds: 60, MaxObjectBytes: MaxHTTPTriggerCacheObjectBytes + 1}, errSub: "Cache.MaxObjectBytes"},
+		{name: "negative object size", cache: &HTTPTriggerCache{TTLSeconds: 60, MaxObjectBytes: -1}, errSub: "between 0 (default) and"},
+		{name: "bad vary header", cache: &HTTPTriggerCache{TTLSeconds: 60, Vary: []string{"Accept Language"}}, errSub: "Cache.Vary[0]"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := HTTPTriggerSpec{
+				RelativeURL:       "/cached",
+				FunctionReference: FunctionReference{Type: FunctionReferenceTypeFunctionName, Name: "fn"},
+				Cache:             tc.cache,
+			}
+			err := spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %v does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPMatchCondition_RegexpIsAnchored(t *testing.T) {
+	re, err := (&HTTPMatchCondition{Type: HTTPMatchRegex, Value: "2|3"}).Regexp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v, want := range map[string]bool{"2": true, "3": true, "12": false, "23": false} {
+		if got := re.MatchString(v); got != want {
+			t.Errorf("MatchString(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestCircuitBreakerConfig_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		cb     CircuitBreakerConfig
+		errSub string // "" => valid
+	}{
+		{name: "all defaults"},
+		{name: "error rate", cb: CircuitBreakerConfig{ErrorRatePercent: 50, MinRequests: 10, IntervalSeconds: 60}},
+		{name: "eject everything", cb: CircuitBreakerConfig{MaxEjectionPercent: 100}},
+		{name: "negative consecutive failures", cb: CircuitBreakerConfig{ConsecutiveFailures: -1}, errSub: "ConsecutiveFailures"},
+		{name: "error rate over 100", cb: CircuitBreakerConfig{ErrorRatePercent: 101}, errSub: "ErrorRatePercent"},
+		{name: "ejection over an hour", cb: CircuitBreakerConfig{EjectionSeconds: 3601}, errSub: "EjectionSeconds"},
+		{name: "max ejection over 100", cb: CircuitBreakerConfig{MaxEjectionPercent: 150}, errSub: "MaxEjectionPercent"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := InvocationConfig{CircuitBreaker: &tc.cb}
+			err := ic.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %v does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerConfig_Effective(t *testing.T) {
+	got := (&CircuitBreakerConfig{ConsecutiveFailures: 3}).Effective()
+	want := CircuitBreakerConfig{
+		ConsecutiveFailures: 3,
+		MinRequests:         DefaultCircuitBreakerMinRequests,
+		IntervalSeconds:     DefaultCircuitBreakerIntervalSeconds,
+		EjectionSeconds:     DefaultCircuitBreakerEjectionSeconds,
+		HalfOpenRequests:    DefaultCircuitBreakerHalfOpenRequests,
+		MaxEjectionPercent:  DefaultCircuitBreakerMaxEjectionPercent,
+	}
+	if got != want {
+		t.Fatalf("Effective() = %+v, want %+v", got, want)
+	}
+}
+
 // versionSample64 duplicates the same-purpose sample64 in
 // functionversion_types_test.go (both are genuinely 64-hex-char digest
 // suffixes). The duplication is necessary, not accidental: that file lives in
@@ -464,6 +744,36 @@ func TestFunctionAliasSpecValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "mirror accepted",
+			spec: FunctionAliasSpec{
+				FunctionName: "fn",
+				Version:      "fn-v1",
+				Mirror:       &TrafficMirror{Version: "fn-v2", Percent: 5},
+			},
+		},
+		{
+			name: "mirror of the primary version rejected",
+			spec: FunctionAliasSpec{
+				FunctionName: "fn",
+				Version:      "fn-v1",
+				Mirror:       &TrafficMirror{Version: "fn-v1"},
+			},
+			wantErr: true,
+			errSub:  "must differ from version and secondaryVersion",
+		},
+		{
+			name: "mirror of the secondary version rejected",
+			spec: FunctionAliasSpec{
+				FunctionName:     "fn",
+				Version:          "fn-v1",
+				Weight:           new(50),
+				SecondaryVersion: "fn-v2",
+				Mirror:           &TrafficMirror{Version: "fn-v2"},
+			},
+			wantErr: true,
+			errSub:  "must differ from version and secondaryVersion",
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := tc.spec.Validate()
@@ -604,3 +914,616 @@ func TestFunctionSpecValidateVersioning(t *testing.T) {
 		}
 	})
 }
+
+func TestCanaryConfigSpecValidateGates(t *testing.T) {
+	q := func(s string) *resource.Quantity { return new(resource.MustParse(s)) }
+	for _, tc := range []struct {
+		name   string
+		spec   CanaryConfigSpec
+		errSub string
+	}{
+		{name: "no gates accepted"},
+		{name: "full analysis accepted", spec: CanaryConfigSpec{
+			Analysis: &CanaryAnalysis{
+				MinRequests: 50,
+				Latency:     []CanaryLatencyThreshold{{Percentile: 99, MaxMilliseconds: 300}},
+				Templates: []CanaryAnalysisTemplate{{
+					Name: "errors", Query: `sum(rate(errs{fn="{{function}}",v="{{version}}"}[{{window}}]))`, Max: q("0.5"),
+				}},
+			},
+			Webhooks: []CanaryWebhook{{Name: "slo", URL: "https://slo.example/check", FailurePolicy: CanaryWebhookRollback}},
+		}},
+		{name: "unknown placeholder rejected", spec: CanaryConfigSpec{Analysis: &CanaryAnalysis{
+			Templates: []CanaryAnalysisTemplate{{Name: "a", Query: `x{fn="{{fn}}"}`, Max: q("1")}},
+		}}, errSub: "unknown placeholder"},
+		{name: "unbounded template rejected", spec: CanaryConfigSpec{Analysis: &CanaryAnalysis{
+			Templates: []CanaryAnalysisTemplate{{Name: "a", Query: "x"}},
+		}}, errSub: "needs min, max or both"},
+		{name: "min above max rejected", spec: CanaryConfigSpec{Analysis: &CanaryAnalysis{
+			Templates: []CanaryAnalysisTemplate{{Name: "a", Query: "x", Min: q("2"), Max: q("1")}},
+		}}, errSub: "must not exceed max"},
+		{name: "duplicate percentile rejected", spec: CanaryConfigSpec{Analysis: &CanaryAnalysis{
+			Latency: []CanaryLatencyThreshold{{Percentile: 99, MaxMilliseconds: 1}, {Percentile: 99, MaxMilliseconds: 2}},
+		}}, errSub: "duplicate percentile"},
+		{name: "percentile out of range rejected", spec: CanaryConfigSpec{Analysis: &CanaryAnalysis{
+			Latency: []CanaryLatencyThreshold{{Percentile: 100, MaxMilliseconds: 1}},
+		}}, errSub: "between 1 and 99"},
+		{name: "duplicate webhook rejected", spec: CanaryConfigSpec{Webhooks: []CanaryWebhook{
+			{Name: "a", URL: "http://a"}, {Name: "a", URL: "http://b"},
+		}}, errSub: "duplicate webhook"},
+		{name: "relative webhook url rejected", spec: CanaryConfigSpec{Webhooks: []CanaryWebhook{
+			{Name: "a", URL: "/check"},
+		}}, errSub: "absolute http or https URL"},
+		{name: "unknown failure policy rejected", spec: CanaryConfigSpec{Webhooks: []CanaryWebhook{
+			{Name: "a", URL: "http://a", FailurePolicy: "Ignore"},
+		}}, errSub: "FailurePolicy"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.ValidateGates()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPTriggerSpecValidateGRPC(t *testing.T) {
+	ref := FunctionReference{Type: FunctionReferenceTypeFunctionName, Name: "fn"}
+	grpc := func(service string, methods ...string) *HTTPTriggerGRPC {
+		return &HTTPTriggerGRPC{Service: service, Methods: methods}
+	}
+	for _, tc := range []struct {
+		name   string
+		spec   HTTPTriggerSpec
+		errSub string
+	}{
+		{name: "whole service accepted", spec: HTTPTriggerSpec{FunctionReference: ref, GRPC: grpc("helloworld.Greeter")}},
+		{name: "methods with POST accepted", spec: HTTPTriggerSpec{
+			FunctionReference: ref, GRPC: grpc("Greeter", "SayHello", "SayBye"), Methods: []string{"POST"},
+		}},
+		{name: "no path at all rejected", spec: HTTPTriggerSpec{FunctionReference: ref}, errSub: "relativeurl, prefix or grpc"},
+		{name: "bad service rejected", spec: HTTPTriggerSpec{FunctionReference: ref, GRPC: grpc("hello/Greeter")}, errSub: "gRPC service name"},
+		{name: "bad method rejected", spec: HTTPTriggerSpec{FunctionReference: ref, GRPC: grpc("Greeter", "Say.Hello")}, errSub: "gRPC method name"},
+		{name: "relativeurl alongside rejected", spec: HTTPTriggerSpec{
+			FunctionReference: ref, GRPC: grpc("Greeter"), RelativeURL: "/greet",
+		}, errSub: "cannot be combined"},
+		{name: "GET rejected", spec: HTTPTriggerSpec{
+			FunctionReference: ref, GRPC: grpc("Greeter"), Methods: []string{"GET"},
+		}, errSub: "POST only"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPTriggerSpecValidateTransform(t *testing.T) {
+	ref := FunctionReference{Type: FunctionReferenceTypeFunctionName, Name: "fn"}
+	spec := func(tr *HTTPTriggerTransform) HTTPTriggerSpec {
+		return HTTPTriggerSpec{FunctionReference: ref, RelativeURL: "/users/{id}", Transform: tr}
+	}
+	for _, tc := range []struct {
+		name   string
+		spec   HTTPTriggerSpec
+		errSub string
+	}{
+		{name: "no transform accepted", spec: spec(nil)},
+		{name: "full transform accepted", spec: spec(&HTTPTriggerTransform{
+			Request: &HTTPRequestTransform{
+				Headers: &HTTPHeaderTransform{
+					Remove: []string{"Cookie"},
+					Set:    []HTTPHeaderValue{{Name: "X-User", Value: "{{header.X-Legacy-User}}"}},
+					Add:    []HTTPHeaderValue{{Name: "X-Tenant", Value: "{{query.tenant}}"}},
+				},
+				Path: "/v2/users/{{param.id}}{{path}}",
+				Body: `{"user": "{{form.user}}", "tags": ["{{query.tag}}"]}`,
+			},
+			Response: &HTTPResponseTransform{Headers: &HTTPHeaderTransform{Remove: []string{"Server"}}},
+		})},
+		{name: "unknown placeholder rejected", spec: spec(&HTTPTriggerTransform{Request: &HTTPRequestTransform{
+			Path: "/{{cookie.session}}",
+		}}), errSub: "unknown placeholder"},
+		{name: "nameless placeholder rejected", spec: spec(&HTTPTriggerTransform{Request: &HTTPRequestTransform{
+			Path: "/{{query}}",
+		}}), errSub: "unknown placeholder"},
+		{name: "form outside body rejected", spec: spec(&HTTPTriggerTransform{Request: &HTTPRequestTransform{
+			Headers: &HTTPHeaderTransform{Set: []HTTPHeaderValue{{Name: "X-User", Value: "{{form.user}}"}}},
+		}}), errSub: "only available in the body template"},
+		{name: "body that is not JSON rejected", spec: spec(&HTTPTriggerTransform{Request: &HTTPRequestTransform{
+			Body: `{"count": {{query.n}}}`,
+		}}), errSub: "must be a JSON document"},
+		{name: "relative path rejected", spec: spec(&HTTPTriggerTransform{Request: &HTTPRequestTransform{
+			Path: "v2/{{path}}",
+		}}), errSub: "must start with /"},
+		{name: "bad escape in path rejected", spec: spec(&HTTPTriggerTransform{Request: &HTTPRequestTransform{
+			Path: "/100%",
+		}}), errSub: "invalid URL escape"},
+		{name: "bad header name rejected", spec: spec(&HTTPTriggerTransform{Request: &HTTPRequestTransform{
+			Headers: &HTTPHeaderTransform{Remove: []string{"Bad Header"}},
+		}}), errSub: "Remove[0]"},
+		{name: "response placeholder rejected", spec: spec(&HTTPTriggerTransform{Response: &HTTPResponseTransform{
+			Headers: &HTTPHeaderTransform{Set: []HTTPHeaderValue{{Name: "X-Path", Value: "{{path}}"}}},
+		}}), errSub: "not expanded in response headers"},
+		{name: "grpc body rejected", spec: HTTPTriggerSpec{FunctionReference: ref, GRPC: &HTTPTriggerGRPC{Service: "Greeter"},
+			Transform: &HTTPTriggerTransform{Request: &HTTPRequestTransform{Body: `{}`}}}, errSub: "may only transform headers"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPTriggerSpecValidateOpenAPI(t *testing.T) {
+	spec := func(o *HTTPTriggerOpenAPI) HTTPTriggerSpec {
+		return HTTPTriggerSpec{FunctionReference: FunctionReference{Type: FunctionReferenceTypeFunctionName, Name: "fn"}, RelativeURL: "/orders", OpenAPI: o}
+	}
+	schema := func(raw string) *apiextensionsv1.JSON { return &apiextensionsv1.JSON{Raw: []byte(raw)} }
+	for _, tc := range []struct {
+		name   string
+		spec   HTTPTriggerSpec
+		errSub string
+	}{
+		{name: "no openapi accepted", spec: spec(nil)},
+		{name: "schemas accepted", spec: spec(&HTTPTriggerOpenAPI{
+			Summary:        "Create an order",
+			RequestSchema:  schema(`{"type": "object"}`),
+			ResponseSchema: schema(`true`),
+		})},
+		{name: "array schema rejected", spec: spec(&HTTPTriggerOpenAPI{RequestSchema: schema(`["object"]`)}),
+			errSub: "must be a JSON Schema object or boolean"},
+		{name: "malformed schema rejected", spec: spec(&HTTPTriggerOpenAPI{ResponseSchema: schema(`{"type":`)}),
+			errSub: "OpenAPI.ResponseSchema"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestHTTPTriggerSpecForwardedPath(t *testing.T) {
+	prefix := "/api"
+	for _, tc := range []struct {
+		name string
+		spec HTTPTriggerSpec
+		path string
+		want string
+	}{
+		{name: "relativeurl forwards root", spec: HTTPTriggerSpec{RelativeURL: "/users"}, path: "/users", want: "/"},
+		{name: "prefix trimmed", spec: HTTPTriggerSpec{Prefix: &prefix}, path: "/api/users", want: "/users"},
+		{name: "prefix kept", spec: HTTPTriggerSpec{Prefix: &prefix, KeepPrefix: true}, path: "/api/users", want: "/api/users"},
+		{name: "prefix alone becomes root", spec: HTTPTriggerSpec{Prefix: &prefix}, path: "/api", want: "/"},
+		{name: "grpc kept", spec: HTTPTriggerSpec{GRPC: &HTTPTriggerGRPC{Service: "pkg.S"}}, path: "/pkg.S/M", want: "/pkg.S/M"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.spec.ForwardedPath(tc.path); got != tc.want {
+				t.Fatalf("ForwardedPath(%q) = %q, want %q", tc.path, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProvisionedConcurrencyConfigValidateAuto(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		auto   *ProvisionedAutoConfig
+		errSub string
+	}{
+		{name: "no auto accepted"},
+		{name: "defaults accepted", auto: &ProvisionedAutoConfig{}},
+		{name: "full config accepted", auto: &ProvisionedAutoConfig{
+			Percentile: 99, Window: "30m", HeadroomPercent: 20, Min: 1, Max: 10, SeasonalityDays: 7,
+		}},
+		{name: "percentile above 100 rejected", auto: &ProvisionedAutoConfig{Percentile: 101},
+			errSub: "Auto.Percentile"},
+		{name: "malformed window rejected", auto: &ProvisionedAutoConfig{Window: "soon"},
+			errSub: "window is invalid"},
+		{name: "window below a minute rejected", auto: &ProvisionedAutoConfig{Window: "30s"},
+			errSub: "must be between 1m0s and 1h0m0s"},
+		{name: "negative headroom rejected", auto: &ProvisionedAutoConfig{HeadroomPercent: -1},
+			errSub: "Auto.HeadroomPercent"},
+		{name: "max below min rejected", auto: &ProvisionedAutoConfig{Min: 5, Max: 2},
+			errSub: "must be >= min"},
+		{name: "unbounded max with min accepted", auto: &ProvisionedAutoConfig{Min: 5}},
+		{name: "seasonality beyond two weeks rejected", auto: &ProvisionedAutoConfig{SeasonalityDays: 15},
+			errSub: "Auto.SeasonalityDays"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := (&ProvisionedConcurrencyConfig{Target: 1, Auto: tc.auto}).Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestEnvironmentSpecValidatePoolAutoscale(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		spec   EnvironmentSpec
+		errSub string
+	}{
+		{name: "max only accepted", spec: EnvironmentSpec{Version: 3, PoolAutoscale: &PoolAutoscaleConfig{MaxSize: 10}}},
+		{name: "full config accepted", spec: EnvironmentSpec{Version: 3, PoolAutoscale: &PoolAutoscaleConfig{
+			MinSize: 2, MaxSize: 20, TargetIdle: 3, Window: "2m", ScaleDownDelay: "0s",
+		}}},
+		{name: "version 2 rejected", spec: EnvironmentSpec{Version: 2, PoolAutoscale: &PoolAutoscaleConfig{MaxSize: 10}},
+			errSub: "requires environment version 3"},
+		{name: "infinite functions per container rejected", spec: EnvironmentSpec{Version: 3,
+			AllowedFunctionsPerContainer: AllowedFunctionsPerContainerInfinite, PoolAutoscale: &PoolAutoscaleConfig{MaxSize: 10}},
+			errSub: "not supported with infinite functions per container"},
+		{name: "missing max rejected", spec: EnvironmentSpec{Version: 3, PoolAutoscale: &PoolAutoscaleConfig{}},
+			errSub: "PoolAutoscale.MaxSize"},
+		{name: "max below min rejected", spec: EnvironmentSpec{Version: 3, PoolAutoscale: &PoolAutoscaleConfig{MinSize: 5, MaxSize: 2}},
+			errSub: "must be >= minSize"},
+		{name: "negative target idle rejected", spec: EnvironmentSpec{Version: 3, PoolAutoscale: &PoolAutoscaleConfig{MaxSize: 2, TargetIdle: -1}},
+			errSub: "PoolAutoscale.TargetIdle"},
+		{name: "window above 15m rejected", spec: EnvironmentSpec{Version: 3, PoolAutoscale: &PoolAutoscaleConfig{MaxSize: 2, Window: "1h"}},
+			errSub: "must be between 10s and 15m0s"},
+		{name: "malformed scale down delay rejected", spec: EnvironmentSpec{Version: 3, PoolAutoscale: &PoolAutoscaleConfig{MaxSize: 2, ScaleDownDelay: "later"}},
+			errSub: "scale down delay is invalid"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestExecutionStrategyValidateConcurrencyScaling(t *testing.T) {
+	newdeploy := func(cs *ConcurrencyScalingConfig) ExecutionStrategy {
+		return ExecutionStrategy{ExecutorType: ExecutorTypeNewdeploy, MaxScale: 5, ConcurrencyScaling: cs}
+	}
+	for _, tc := range []struct {
+		name   string
+		es     ExecutionStrategy
+		errSub string
+	}{
+		{name: "target only accepted", es: newdeploy(&ConcurrencyScalingConfig{TargetPerReplica: 10})},
+		{name: "zero window accepted", es: newdeploy(&ConcurrencyScalingConfig{TargetPerReplica: 1, StabilizationWindow: "0s"})},
+		{name: "container accepted", es: ExecutionStrategy{ExecutorType: ExecutorTypeContainer, MaxScale: 1,
+			ConcurrencyScaling: &ConcurrencyScalingConfig{TargetPerReplica: 4}}},
+		{name: "poolmgr rejected", es: ExecutionStrategy{ExecutorType: ExecutorTypePoolmgr,
+			ConcurrencyScaling: &ConcurrencyScalingConfig{TargetPerReplica: 4}},
+			errSub: "only supported by executor types newdeploy and container"},
+		{name: "missing target rejected", es: newdeploy(&ConcurrencyScalingConfig{}),
+			errSub: "ConcurrencyScaling.TargetPerReplica"},
+		{name: "malformed window rejected", es: newdeploy(&ConcurrencyScalingConfig{TargetPerReplica: 1, StabilizationWindow: "soon"}),
+			errSub: "stabilization window is invalid"},
+		{name: "window above an hour rejected", es: newdeploy(&ConcurrencyScalingConfig{TargetPerReplica: 1, StabilizationWindow: "2h"}),
+			errSub: "must be between 0s and 1h0m0s"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.es.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestExecutionStrategyValidateScaleToZero(t *testing.T) {
+	newdeploy := func(stz *ScaleToZeroConfig) ExecutionStrategy {
+		return ExecutionStrategy{ExecutorType: ExecutorTypeNewdeploy, MinScale: 1, MaxScale: 5, ScaleToZero: stz}
+	}
+	for _, tc := range []struct {
+		name   string
+		es     ExecutionStrategy
+		errSub string
+	}{
+		{name: "defaults accepted", es: newdeploy(&ScaleToZeroConfig{})},
+		{name: "full config accepted", es: newdeploy(&ScaleToZeroConfig{QueueSize: 500, QueueTimeout: "2m", RequestsPerReplica: 4})},
+		{name: "poolmgr rejected", es: ExecutionStrategy{ExecutorType: ExecutorTypePoolmgr, ScaleToZero: &ScaleToZeroConfig{}},
+			errSub: "only supported by executor types newdeploy and container"},
+		{name: "queue size above limit rejected", es: newdeploy(&ScaleToZeroConfig{QueueSize: 10001}),
+			errSub: "ScaleToZero.QueueSize"},
+		{name: "malformed queue timeout rejected", es: newdeploy(&ScaleToZeroConfig{QueueTimeout: "soon"}),
+			errSub: "queue timeout is invalid"},
+		{name: "queue timeout below a second rejected", es: newdeploy(&ScaleToZeroConfig{QueueTimeout: "500ms"}),
+			errSub: "must be between 1s and 10m0s"},
+		{name: "negative requests per replica rejected", es: newdeploy(&ScaleToZeroConfig{RequestsPerReplica: -1}),
+			errSub: "ScaleToZero.RequestsPerReplica"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.es.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+}
+
+func TestExecutionStrategyScaleFromZeroReplicas(t *testing.T) {
+	es := ExecutionStrategy{MinScale: 0, MaxScale: 6, ScaleToZero: &ScaleToZeroConfig{RequestsPerReplica: 5}}
+	for queued, want := range map[int]int{0: 1, 1: 1, 5: 1, 6: 2, 23: 5, 100: 6} {
+		if got := es.ScaleFromZeroReplicas(queued); got != want {
+			t.Fatalf("ScaleFromZeroReplicas(%d) = %d, want %d", queued, got, want)
+		}
+	}
+	es.MinScale = 3
+	if got := es.ScaleFromZeroReplicas(1); got != 3 {
+		t.Fatalf("ScaleFromZeroReplicas(1) with MinScale 3 = %d, want 3", got)
+	}
+	es.ScaleToZero.RequestsPerReplica = 0
+	if got := es.ScaleFromZeroReplicas(40); got != 4 {
+		t.Fatalf("ScaleFromZeroReplicas(40) with the default requests per replica = %d, want 4", got)
+	}
+}
+
+func TestExecutionStrategyValidateWasm(t *testing.T) {
+	wasm := func(cfg *WasmConfig) ExecutionStrategy {
+		return ExecutionStrategy{ExecutorType: ExecutorTypeWasm, Wasm: cfg}
+	}
+	for _, tc := range []struct {
+		name   string
+		es     ExecutionStrategy
+		errSub string
+	}{
+		{name: "no config accepted", es: wasm(nil)},
+		{name: "full config accepted", es: wasm(&WasmConfig{MaxMemory: new(resource.MustParse("16Mi")), MaxExecutionTime: "250ms"})},
+		{name: "newdeploy rejected", es: ExecutionStrategy{ExecutorType: ExecutorTypeNewdeploy, MaxScale: 1, Wasm: &WasmConfig{}},
+			errSub: "only supported by executor type wasm"},
+		{name: "memory below a page rejected", es: wasm(&WasmConfig{MaxMemory: new(resource.MustParse("1Ki"))}),
+			errSub: "Wasm.MaxMemory"},
+		{name: "memory above 4Gi rejected", es: wasm(&WasmConfig{MaxMemory: new(resource.MustParse("5Gi"))}),
+			errSub: "Wasm.MaxMemory"},
+		{name: "malformed execution time rejected", es: wasm(&WasmConfig{MaxExecutionTime: "fast"}),
+			errSub: "max execution time is invalid"},
+		{name: "execution time above limit rejected", es: wasm(&WasmConfig{MaxExecutionTime: "1h"}),
+			errSub: "must be between 1ms and 10m0s"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.es.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+
+	e := (*WasmConfig)(nil).Effective()
+	if e.MaxMemory.Value() != 64<<20 || e.MaxExecutionTime != DefaultWasmMaxExecutionTime {
+		t.Fatalf("nil WasmConfig.Effective() = %v/%s, want the defaults", e.MaxMemory, e.MaxExecutionTime)
+	}
+}
+
+func TestFunctionSpecValidateWasm(t *testing.T) {
+	spec := FunctionSpec{
+		Environment:    EnvironmentReference{Name: "wasm", Namespace: "default"},
+		Package:        FunctionPackageRef{PackageRef: PackageRef{Name: "mod", Namespace: "default"}},
+		InvokeStrategy: InvokeStrategy{StrategyType: StrategyTypeExecution, ExecutionStrategy: ExecutionStrategy{ExecutorType: ExecutorTypeWasm}},
+	}
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	noPkg := spec
+	noPkg.Package = FunctionPackageRef{}
+	if err := noPkg.Validate(); err == nil || !strings.Contains(err.Error(), "requires a package") {
+		t.Fatalf("expected a missing package error, got %v", err)
+	}
+
+	withSecret := spec
+	withSecret.Secrets = []SecretReference{{Name: "s", Namespace: "default"}}
+	if err := withSecret.Validate(); err == nil || !strings.Contains(err.Error(), "executor type wasm does not support") {
+		t.Fatalf("expected an unsupported field error, got %v", err)
+	}
+}
+
+func TestExecutionStrategyValidateJob(t *testing.T) {
+	job := func(cfg *JobConfig) ExecutionStrategy {
+		return ExecutionStrategy{ExecutorType: ExecutorTypeJob, Job: cfg}
+	}
+	for _, tc := range []struct {
+		name   string
+		es     ExecutionStrategy
+		errSub string
+	}{
+		{name: "no config accepted", es: job(nil)},
+		{name: "full config accepted", es: job(&JobConfig{BackoffLimit: 2, ResultTTL: "72h", ActiveDeadline: "6h"})},
+		{name: "poolmgr rejected", es: ExecutionStrategy{ExecutorType: ExecutorTypePoolmgr, Job: &JobConfig{}},
+			errSub: "only supported by executor type job"},
+		{name: "negative backoff limit rejected", es: job(&JobConfig{BackoffLimit: -1}),
+			errSub: "Job.BackoffLimit"},
+		{name: "backoff limit above limit rejected", es: job(&JobConfig{BackoffLimit: 11}),
+			errSub: "Job.BackoffLimit"},
+		{name: "malformed result TTL rejected", es: job(&JobConfig{ResultTTL: "a day"}),
+			errSub: "result TTL is invalid"},
+		{name: "result TTL below limit rejected", es: job(&JobConfig{ResultTTL: "10s"}),
+			errSub: "must be between 1m0s and 720h0m0s"},
+		{name: "malformed active deadline rejected", es: job(&JobConfig{ActiveDeadline: "soon"}),
+			errSub: "active deadline is invalid"},
+		{name: "active deadline above limit rejected", es: job(&JobConfig{ActiveDeadline: "200h"}),
+			errSub: "must be between 1m0s and 168h0m0s"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.es.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+
+	if e := (*JobConfig)(nil).Effective(); e.BackoffLimit != 0 || e.ResultTTL != DefaultJobResultTTL {
+		t.Fatalf("nil JobConfig.Effective() = %+v, want the defaults", e)
+	}
+}
+
+func TestFunctionSpecValidateJob(t *testing.T) {
+	spec := FunctionSpec{
+		Environment:    EnvironmentReference{Name: "python", Namespace: "default"},
+		Package:        FunctionPackageRef{PackageRef: PackageRef{Name: "report", Namespace: "default"}},
+		InvokeStrategy: InvokeStrategy{StrategyType: StrategyTypeExecution, ExecutionStrategy: ExecutionStrategy{ExecutorType: ExecutorTypeJob}},
+	}
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	noEnv := spec
+	noEnv.Environment = EnvironmentReference{}
+	if err := noEnv.Validate(); err == nil || !strings.Contains(err.Error(), "requires an environment") {
+		t.Fatalf("expected a missing environment error, got %v", err)
+	}
+
+	streaming := spec
+	streaming.Streaming = &StreamingConfig{}
+	if err := streaming.Validate(); err == nil || !strings.Contains(err.Error(), "executor type job does not support") {
+		t.Fatalf("expected an unsupported field error, got %v", err)
+	}
+}
+
+func TestEnvironmentSpecValidateResourceClasses(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		spec   EnvironmentSpec
+		errSub string
+	}{
+		{name: "classes accepted", spec: EnvironmentSpec{Version: 3, ResourceClasses: []ResourceClass{
+			{Name: "small"}, {Name: "big", Poolsize: 2, NodeSelector: map[string]string{"pool": "highmem"}},
+		}}},
+		{name: "invalid name rejected", spec: EnvironmentSpec{Version: 3, ResourceClasses: []ResourceClass{{Name: "Big_One"}}},
+			errSub: "ResourceClass.Name"},
+		{name: "duplicate name rejected", spec: EnvironmentSpec{Version: 3, ResourceClasses: []ResourceClass{{Name: "big"}, {Name: "big"}}},
+			errSub: "duplicate resource class name"},
+		{name: "negative pool size rejected", spec: EnvironmentSpec{Version: 3, ResourceClasses: []ResourceClass{{Name: "big", Poolsize: -1}}},
+			errSub: "ResourceClass.Poolsize"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.errSub == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tc.errSub)
+			}
+			if !strings.Contains(err.Error(), tc.errSub) {
+				t.Fatalf("error %q does not contain %q", err, tc.errSub)
+			}
+		})
+	}
+
+	spec := EnvironmentSpec{ResourceClasses: []ResourceClass{{Name: "big", Poolsize: 2}}}
+	if rc := spec.ResourceClass("big"); rc == nil || rc.Poolsize != 2 {
+		t.Fatalf("ResourceClass(big) = %+v, want the declared class", rc)
+	}
+	if rc := spec.ResourceClass("small"); rc != nil {
+		t.Fatalf("ResourceClass(small) = %+v, want nil", rc)
+	}
+}
+
+func TestFunctionSpecValidateResourceClass(t *testing.T) {
+	spec := FunctionSpec{
+		Environment:   EnvironmentReference{Name: "python", Namespace: "default"},
+		ResourceClass: "big",
+	}
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec.InvokeStrategy = InvokeStrategy{StrategyType: StrategyTypeExecution, ExecutionStrategy: ExecutionStrategy{ExecutorType: ExecutorTypeNewdeploy, MinScale: 1, MaxScale: 1}}
+	if err := spec.Validate(); err == nil || !strings.Contains(err.Error(), "requires the poolmgr executor") {
+		t.Fatalf("expected a poolmgr-only error, got %v", err)
+	}
+}
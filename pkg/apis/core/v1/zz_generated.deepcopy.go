@@ -98,12 +98,64 @@ func (in *Builder) DeepCopy() *Builder {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryAnalysis) DeepCopyInto(out *CanaryAnalysis) {
+	*out = *in
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = make([]CanaryLatencyThreshold, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]CanaryAnalysisTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryAnalysis.
+func (in *CanaryAnalysis) DeepCopy() *CanaryAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryAnalysisTemplate) DeepCopyInto(out *CanaryAnalysisTemplate) {
+	*out = *in
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryAnalysisTemplate.
+func (in *CanaryAnalysisTemplate) DeepCopy() *CanaryAnalysisTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryAnalysisTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryConfig) DeepCopyInto(out *CanaryConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -160,6 +212,16 @@ func (in *CanaryConfigList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryConfigSpec) DeepCopyInto(out *CanaryConfigSpec) {
 	*out = *in
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(CanaryAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]CanaryWebhook, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryConfigSpec.
@@ -194,6 +256,36 @@ func (in *CanaryConfigStatus) DeepCopy() *CanaryConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryLatencyThreshold) DeepCopyInto(out *CanaryLatencyThreshold) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryLatencyThreshold.
+func (in *CanaryLatencyThreshold) DeepCopy() *CanaryLatencyThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryLatencyThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryWebhook) DeepCopyInto(out *CanaryWebhook) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryWebhook.
+func (in *CanaryWebhook) DeepCopy() *CanaryWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Checksum) DeepCopyInto(out *Checksum) {
 	*out = *in
@@ -209,6 +301,36 @@ func (in *Checksum) DeepCopy() *Checksum {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreakerConfig) DeepCopyInto(out *CircuitBreakerConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreakerConfig.
+func (in *CircuitBreakerConfig) DeepCopy() *CircuitBreakerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreakerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConcurrencyScalingConfig) DeepCopyInto(out *ConcurrencyScalingConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConcurrencyScalingConfig.
+func (in *ConcurrencyScalingConfig) DeepCopy() *ConcurrencyScalingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConcurrencyScalingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
 	*out = *in
@@ -329,6 +451,18 @@ func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
 	in.Runtime.DeepCopyInto(&out.Runtime)
 	in.Builder.DeepCopyInto(&out.Builder)
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.PoolAutoscale != nil {
+		in, out := &in.PoolAutoscale, &out.PoolAutoscale
+		*out = new(PoolAutoscaleConfig)
+		**out = **in
+	}
+	if in.ResourceClasses != nil {
+		in, out := &in.ResourceClasses, &out.ResourceClasses
+		*out = make([]ResourceClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.TerminationGracePeriod != nil {
 		in, out := &in.TerminationGracePeriod, &out.TerminationGracePeriod
 		*out = new(int64)
@@ -383,6 +517,26 @@ func (in *ExecutionStrategy) DeepCopyInto(out *ExecutionStrategy) {
 		*out = new(v2.HorizontalPodAutoscalerBehavior)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConcurrencyScaling != nil {
+		in, out := &in.ConcurrencyScaling, &out.ConcurrencyScaling
+		*out = new(ConcurrencyScalingConfig)
+		**out = **in
+	}
+	if in.ScaleToZero != nil {
+		in, out := &in.ScaleToZero, &out.ScaleToZero
+		*out = new(ScaleToZeroConfig)
+		**out = **in
+	}
+	if in.Wasm != nil {
+		in, out := &in.Wasm, &out.Wasm
+		*out = new(WasmConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(JobConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecutionStrategy.
@@ -585,6 +739,11 @@ func (in *FunctionAliasSpec) DeepCopyInto(out *FunctionAliasSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(TrafficMirror)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FunctionAliasSpec.
@@ -911,6 +1070,106 @@ func (in *GatewayRouteConfig) DeepCopy() *GatewayRouteConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeaderTransform) DeepCopyInto(out *HTTPHeaderTransform) {
+	*out = *in
+	if in.Set != nil {
+		in, out := &in.Set, &out.Set
+		*out = make([]HTTPHeaderValue, len(*in))
+		copy(*out, *in)
+	}
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make([]HTTPHeaderValue, len(*in))
+		copy(*out, *in)
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeaderTransform.
+func (in *HTTPHeaderTransform) DeepCopy() *HTTPHeaderTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeaderTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHeaderValue) DeepCopyInto(out *HTTPHeaderValue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHeaderValue.
+func (in *HTTPHeaderValue) DeepCopy() *HTTPHeaderValue {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHeaderValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPMatchCondition) DeepCopyInto(out *HTTPMatchCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPMatchCondition.
+func (in *HTTPMatchCondition) DeepCopy() *HTTPMatchCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPMatchCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRequestTransform) DeepCopyInto(out *HTTPRequestTransform) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = new(HTTPHeaderTransform)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRequestTransform.
+func (in *HTTPRequestTransform) DeepCopy() *HTTPRequestTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRequestTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPResponseTransform) DeepCopyInto(out *HTTPResponseTransform) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = new(HTTPHeaderTransform)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPResponseTransform.
+func (in *HTTPResponseTransform) DeepCopy() *HTTPResponseTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPResponseTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPTrigger) DeepCopyInto(out *HTTPTrigger) {
 	*out = *in
@@ -938,6 +1197,66 @@ func (in *HTTPTrigger) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerAPIKeyAuth) DeepCopyInto(out *HTTPTriggerAPIKeyAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerAPIKeyAuth.
+func (in *HTTPTriggerAPIKeyAuth) DeepCopy() *HTTPTriggerAPIKeyAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerAPIKeyAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerAuth) DeepCopyInto(out *HTTPTriggerAuth) {
+	*out = *in
+	if in.JWT != nil {
+		in, out := &in.JWT, &out.JWT
+		*out = new(HTTPTriggerJWTAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(HTTPTriggerAPIKeyAuth)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerAuth.
+func (in *HTTPTriggerAuth) DeepCopy() *HTTPTriggerAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerCache) DeepCopyInto(out *HTTPTriggerCache) {
+	*out = *in
+	if in.Vary != nil {
+		in, out := &in.Vary, &out.Vary
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerCache.
+func (in *HTTPTriggerCache) DeepCopy() *HTTPTriggerCache {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPTriggerCorsConfig) DeepCopyInto(out *HTTPTriggerCorsConfig) {
 	*out = *in
@@ -973,6 +1292,60 @@ func (in *HTTPTriggerCorsConfig) DeepCopy() *HTTPTriggerCorsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerGRPC) DeepCopyInto(out *HTTPTriggerGRPC) {
+	*out = *in
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerGRPC.
+func (in *HTTPTriggerGRPC) DeepCopy() *HTTPTriggerGRPC {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerGRPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerJWTAuth) DeepCopyInto(out *HTTPTriggerJWTAuth) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequiredClaims != nil {
+		in, out := &in.RequiredClaims, &out.RequiredClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ForwardClaims != nil {
+		in, out := &in.ForwardClaims, &out.ForwardClaims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerJWTAuth.
+func (in *HTTPTriggerJWTAuth) DeepCopy() *HTTPTriggerJWTAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerJWTAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPTriggerList) DeepCopyInto(out *HTTPTriggerList) {
 	*out = *in
@@ -1005,6 +1378,97 @@ func (in *HTTPTriggerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerMatch) DeepCopyInto(out *HTTPTriggerMatch) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HTTPMatchCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.QueryParams != nil {
+		in, out := &in.QueryParams, &out.QueryParams
+		*out = make([]HTTPMatchCondition, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerMatch.
+func (in *HTTPTriggerMatch) DeepCopy() *HTTPTriggerMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerOpenAPI) DeepCopyInto(out *HTTPTriggerOpenAPI) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequestSchema != nil {
+		in, out := &in.RequestSchema, &out.RequestSchema
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseSchema != nil {
+		in, out := &in.ResponseSchema, &out.ResponseSchema
+		*out = new(apiextensionsv1.JSON)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerOpenAPI.
+func (in *HTTPTriggerOpenAPI) DeepCopy() *HTTPTriggerOpenAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerOpenAPI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerRateLimit) DeepCopyInto(out *HTTPTriggerRateLimit) {
+	*out = *in
+	out.Key = in.Key
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerRateLimit.
+func (in *HTTPTriggerRateLimit) DeepCopy() *HTTPTriggerRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerRateLimitKey) DeepCopyInto(out *HTTPTriggerRateLimitKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerRateLimitKey.
+func (in *HTTPTriggerRateLimitKey) DeepCopy() *HTTPTriggerRateLimitKey {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerRateLimitKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPTriggerSpec) DeepCopyInto(out *HTTPTriggerSpec) {
 	*out = *in
@@ -1030,6 +1494,46 @@ func (in *HTTPTriggerSpec) DeepCopyInto(out *HTTPTriggerSpec) {
 		*out = new(HTTPTriggerCorsConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(HTTPTriggerAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(HTTPTriggerRateLimit)
+		**out = **in
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(TrafficMirror)
+		**out = **in
+	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(HTTPTriggerMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(HTTPTriggerCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(HTTPTriggerGRPC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Transform != nil {
+		in, out := &in.Transform, &out.Transform
+		*out = new(HTTPTriggerTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenAPI != nil {
+		in, out := &in.OpenAPI, &out.OpenAPI
+		*out = new(HTTPTriggerOpenAPI)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerSpec.
@@ -1064,6 +1568,31 @@ func (in *HTTPTriggerStatus) DeepCopy() *HTTPTriggerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTriggerTransform) DeepCopyInto(out *HTTPTriggerTransform) {
+	*out = *in
+	if in.Request != nil {
+		in, out := &in.Request, &out.Request
+		*out = new(HTTPRequestTransform)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(HTTPResponseTransform)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTriggerTransform.
+func (in *HTTPTriggerTransform) DeepCopy() *HTTPTriggerTransform {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTriggerTransform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressConfig) DeepCopyInto(out *IngressConfig) {
 	*out = *in
@@ -1105,6 +1634,11 @@ func (in *InvocationConfig) DeepCopyInto(out *InvocationConfig) {
 		*out = new(DestinationRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(CircuitBreakerConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InvocationConfig.
@@ -1133,6 +1667,21 @@ func (in *InvokeStrategy) DeepCopy() *InvokeStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobConfig) DeepCopyInto(out *JobConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobConfig.
+func (in *JobConfig) DeepCopy() *JobConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(JobConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubernetesWatchTrigger) DeepCopyInto(out *KubernetesWatchTrigger) {
 	*out = *in
@@ -1501,6 +2050,36 @@ func (in *PackageStatus) DeepCopy() *PackageStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolAutoscaleConfig) DeepCopyInto(out *PoolAutoscaleConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolAutoscaleConfig.
+func (in *PoolAutoscaleConfig) DeepCopy() *PoolAutoscaleConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolAutoscaleConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisionedAutoConfig) DeepCopyInto(out *ProvisionedAutoConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionedAutoConfig.
+func (in *ProvisionedAutoConfig) DeepCopy() *ProvisionedAutoConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisionedAutoConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProvisionedConcurrencyConfig) DeepCopyInto(out *ProvisionedConcurrencyConfig) {
 	*out = *in
@@ -1509,6 +2088,11 @@ func (in *ProvisionedConcurrencyConfig) DeepCopyInto(out *ProvisionedConcurrency
 		*out = make([]ProvisionedWindow, len(*in))
 		copy(*out, *in)
 	}
+	if in.Auto != nil {
+		in, out := &in.Auto, &out.Auto
+		*out = new(ProvisionedAutoConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisionedConcurrencyConfig.
@@ -1536,6 +2120,36 @@ func (in *ProvisionedWindow) DeepCopy() *ProvisionedWindow {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceClass) DeepCopyInto(out *ResourceClass) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceClass.
+func (in *ResourceClass) DeepCopy() *ResourceClass {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
 	*out = *in
@@ -1643,6 +2257,21 @@ func (in *Runtime) DeepCopy() *Runtime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleToZeroConfig) DeepCopyInto(out *ScaleToZeroConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleToZeroConfig.
+func (in *ScaleToZeroConfig) DeepCopy() *ScaleToZeroConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleToZeroConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
@@ -1845,6 +2474,21 @@ func (in *TopicRef) DeepCopy() *TopicRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirror) DeepCopyInto(out *TrafficMirror) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirror.
+func (in *TrafficMirror) DeepCopy() *TrafficMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValidationError) DeepCopyInto(out *ValidationError) {
 	*out = *in
@@ -1880,6 +2524,26 @@ func (in *VersioningConfig) DeepCopy() *VersioningConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WasmConfig) DeepCopyInto(out *WasmConfig) {
+	*out = *in
+	if in.MaxMemory != nil {
+		in, out := &in.MaxMemory, &out.MaxMemory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WasmConfig.
+func (in *WasmConfig) DeepCopy() *WasmConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WasmConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Workflow) DeepCopyInto(out *Workflow) {
 	*out = *in
@@ -71,6 +71,27 @@ func (CanaryConfigList) SwaggerDoc() map[string]string {
 	return map_CanaryConfigList
 }
 
+var map_CanaryAnalysis = map[string]string{
+	"":            "CanaryAnalysis is the set of metric checks a canary rollout evaluates against the new target each interval, over the last interval's window. Checks run only once the new target carries traffic.",
+	"minRequests": "MinRequests is the number of requests the new target must have served in the window before any check is judged; until then the rollout holds. 0 judges whatever traffic there was.",
+	"latency":     "Latency caps latency percentiles of the new target, measured by the router from the request to the function's response headers.",
+	"templates":   "Templates are PromQL queries whose result must stay within bounds.",
+}
+
+func (CanaryAnalysis) SwaggerDoc() map[string]string {
+	return map_CanaryAnalysis
+}
+
+var map_CanaryAnalysisTemplate = map[string]string{
+	"":    "CanaryAnalysisTemplate is a PromQL query evaluated each interval. The placeholders {{function}}, {{version}}, {{namespace}}, {{path}} and {{window}} are replaced before the query runs: {{function}} is the new function (the alias's function in alias mode), {{version}} the new FunctionVersion in alias mode and empty in function-pair mode, {{path}} the trigger's URL and {{window}} the increment interval. The query must return a single value; a query that returns no data holds the rollout. Numeric bounds use resource.Quantity (CRDs cannot carry floats; Quantity accepts YAML numbers and strings such as \"0.5\").",
+	"min": "Min fails the rollout when the result is below it.",
+	"max": "Max fails the rollout when the result is above it.",
+}
+
+func (CanaryAnalysisTemplate) SwaggerDoc() map[string]string {
+	return map_CanaryAnalysisTemplate
+}
+
 var map_CanaryConfigSpec = map[string]string{
 	"":                 "CanaryConfigSpec defines the canary configuration spec",
 	"trigger":          "HTTP trigger that this config references",
@@ -79,6 +100,10 @@ var map_CanaryConfigSpec = map[string]string{
 	"weightincrement":  "Weight increment step for function",
 	"duration":         "Weight increment interval, string representation of time.Duration, ex : 1m, 2h, 2d (default: \"2m\")",
 	"failurethreshold": "Threshold in percentage beyond which the new version of the function is considered unstable",
+	"analysis":         "Analysis adds checks to FailureThreshold that the new target must pass in each interval before the rollout advances: a minimum request count, latency percentiles and arbitrary PromQL queries. A check without data holds the rollout at its current weights; a check that fails rolls it back.",
+	"webhooks":         "Webhooks are gates asked before every weight increment, and before the final promotion. Each receives a JSON POST describing the step and approves it with a 2xx response.",
+	"paused":           "Paused holds the rollout at its current weights: no analysis, no increments, no rollback. Clearing it resumes the rollout from where it stopped.",
+	"promote":          "Promote skips the remaining increments and the analysis and moves all traffic to the new target at once, finishing the rollout as Succeeded. It wins over Paused. Webhook gates are not asked: a manual promotion is the operator's decision.",
 }
 
 func (CanaryConfigSpec) SwaggerDoc() map[string]string {
@@ -94,6 +119,26 @@ func (CanaryConfigStatus) SwaggerDoc() map[string]string {
 	return map_CanaryConfigStatus
 }
 
+var map_CanaryLatencyThreshold = map[string]string{
+	"":                "CanaryLatencyThreshold fails the rollout when a latency percentile of the new target exceeds MaxMilliseconds.",
+	"percentile":      "Percentile, e.g. 99 for p99.",
+	"maxMilliseconds": "MaxMilliseconds is the highest acceptable value of the percentile.",
+}
+
+func (CanaryLatencyThreshold) SwaggerDoc() map[string]string {
+	return map_CanaryLatencyThreshold
+}
+
+var map_CanaryWebhook = map[string]string{
+	"":               "CanaryWebhook is a gate a canary rollout asks before each step. The controller POSTs a JSON body with the config's name and namespace, the trigger, the old and new targets, the new target's current weight and the weight it is about to move to; a 2xx response approves the step. Any other response, or no response within TimeoutSeconds, is a refusal handled by FailurePolicy.",
+	"timeoutSeconds": "TimeoutSeconds bounds one call to the gate (default 10).",
+	"failurePolicy":  "FailurePolicy is what a refusal does: Hold (default) keeps the current weights and asks again next interval, Rollback moves all traffic back to the old target and fails the rollout.",
+}
+
+func (CanaryWebhook) SwaggerDoc() map[string]string {
+	return map_CanaryWebhook
+}
+
 var map_Checksum = map[string]string{
 	"": "Checksum of package contents when the contents are stored outside the Package struct. Type is the checksum algorithm; \"sha256\" is the only currently supported one. Sum is hex encoded.",
 }
@@ -102,6 +147,31 @@ func (Checksum) SwaggerDoc() map[string]string {
 	return map_Checksum
 }
 
+var map_CircuitBreakerConfig = map[string]string{
+	"":                    "CircuitBreakerConfig configures the router's per-endpoint circuit breaker. A failure is a 5xx response or a connection that fails after it was established (dial failures already quarantine the endpoint on their own). Each router replica keeps its own breakers: an endpoint is ejected from the replicas that saw it fail. Only endpoints the router dials directly from its EndpointSlice index (poolmgr pods; newdeploy and container pods with endpoint load balancing on) can be ejected.",
+	"consecutiveFailures": "ConsecutiveFailures opens the breaker after this many failures in a row. 0 means the default (5).",
+	"errorRatePercent":    "ErrorRatePercent opens the breaker when at least this percentage of the requests in one IntervalSeconds window fail, once the window holds MinRequests requests. 0 (the default) disables the error-rate check, leaving ConsecutiveFailures as the only trigger.",
+	"minRequests":         "MinRequests is the number of requests a window needs before ErrorRatePercent is evaluated. 0 means the default (20).",
+	"intervalSeconds":     "IntervalSeconds is the length of the error-rate window. 0 means the default (30).",
+	"ejectionSeconds":     "EjectionSeconds is how long an endpoint whose breaker opened stays out of rotation before it is probed. 0 means the default (30).",
+	"halfOpenRequests":    "HalfOpenRequests is how many probe requests a half-open endpoint takes at a time; that many consecutive successes close the breaker, any failure reopens it. 0 means the default (1).",
+	"maxEjectionPercent":  "MaxEjectionPercent caps the share of a function's ready endpoints that may be ejected at once, so a fault every pod shares (a bad deploy, a broken dependency) cannot empty the pool and turn errors into a cold-start storm. A breaker that would exceed the cap stays closed. 0 means the default (50); 100 allows ejecting every endpoint.",
+}
+
+func (CircuitBreakerConfig) SwaggerDoc() map[string]string {
+	return map_CircuitBreakerConfig
+}
+
+var map_ConcurrencyScalingConfig = map[string]string{
+	"":                    "ConcurrencyScalingConfig scales a function on its in-flight requests, which suits I/O-bound functions that CPU does not describe. The executor sums the in-flight counts the routers report in their taps and keeps the HPA's minimum replicas at that sum over TargetPerReplica, between MinScale and MaxScale. The HPA still scales further on its metrics. A higher count applies at once; a lower one only once it has been the highest over StabilizationWindow.",
+	"targetPerReplica":    "TargetPerReplica is the number of in-flight requests one replica should serve.",
+	"stabilizationWindow": "StabilizationWindow is how long the replica count holds after the concurrency drops, from 0 to 1h. Format is Go time.ParseDuration. Defaults to 5m.",
+}
+
+func (ConcurrencyScalingConfig) SwaggerDoc() map[string]string {
+	return map_ConcurrencyScalingConfig
+}
+
 var map_ConfigMapReference = map[string]string{
 	"":          "ConfigMapReference is a reference to a kubernetes configmap.",
 	"mountPath": "MountPath redirects this configmap's file projection from the default /configs/<namespace>/<name>; relative to the /configs root. See SecretReference.MountPath for the constraint rationale.",
@@ -155,6 +225,8 @@ var map_EnvironmentSpec = map[string]string{
 	"allowAccessToExternalNetwork": "Istio default blocks all egress traffic for safety. To enable accessibility of external network for builder/function pod, set to 'true'. (Optional) defaults to 'false'",
 	"resources":                    "The request and limit CPU/MEM resource setting for poolmanager to set up pods in the pre-warm pool. (Optional) defaults to no limitation.",
 	"poolsize":                     "The initial pool size for environment",
+	"poolAutoscale":                "PoolAutoscale sizes the pre-warm pool from the environment's recent specialization rate instead of the static Poolsize. (Optional) defaults to the static Poolsize.",
+	"resourceClasses":              "ResourceClasses are extra pre-warm pools of the environment, each with its own resources and node placement. A poolmgr function picks one with FunctionSpec.ResourceClass; functions that pick none run in the environment's default pool.",
 	"terminationGracePeriod":       "The grace time for pod to perform connection draining before termination. The unit is in seconds. A terminating function pod keeps serving for the WHOLE grace window (the preStop hook sleeps through it, then the kubelet kills the pod), so this value is exactly how long every teardown — idle reap, env update roll, upgrade, node drain — takes per pod. 90s covers endpoint propagation (seconds) plus the 60s default function timeout with margin, mirroring the router's own 75s-drain/90s-grace posture; set it per environment for functions with longer request timeouts.\n\nThe CRD default below is what makes the documented default true for API-created Environments: the field is an int64, so before it existed an Environment created without the field got 0 — instant SIGKILL, every in-flight request on the pod dying as a connection reset.\n\nConsequence of defaulting a non-pointer field with omitempty: an explicit 0 survives ONLY via raw YAML/JSON. Every typed Go client (the CLI included) marshals 0 as absent, which the apiserver then serves as 90. Restoring 0 as a first-class typed-client value means migrating this field to *int64; until then, \"instant kill\" is a raw-manifest-only setting. (Optional) defaults to 90 seconds",
 	"keeparchive":                  "KeepArchive is used by fetcher to determine if the extracted archive or unarchived file should be placed, which is then used by specialize handler. (This is mainly for the JVM environment because .jar is one kind of zip archive.)",
 	"imagepullsecret":              "ImagePullSecret is the secret for Kubernetes to pull an image from a private registry.",
@@ -174,13 +246,17 @@ func (EnvironmentStatus) SwaggerDoc() map[string]string {
 
 var map_ExecutionStrategy = map[string]string{
 	"":                      "ExecutionStrategy specifies low-level parameters for function execution, such as the number of instances.\n\nMinScale affects the cold start behavior for a function. If MinScale is 0 then the deployment is created on first invocation of function and is good for requests of asynchronous nature. If MinScale is greater than 0 then MinScale number of pods are created at the time of creation of function. This ensures faster response during first invocation at the cost of consuming resources.\n\nMaxScale is the maximum number of pods that function will scale to based on TargetCPUPercent and resources allocated to the function pod.",
-	"ExecutorType":          "ExecutorType is the executor type of function used. Defaults to \"poolmgr\".\n\nAvailable value:\n - poolmgr\n - newdeploy\n - container",
+	"ExecutorType":          "ExecutorType is the executor type of function used. Defaults to \"poolmgr\".\n\nAvailable value:\n - poolmgr\n - newdeploy\n - container\n - wasm\n - job",
 	"MinScale":              "This is only for newdeploy to set up minimum replicas of deployment.",
 	"MaxScale":              "This is only for newdeploy to set up maximum replicas of deployment.",
 	"TargetCPUPercent":      "Deprecated: use hpaMetrics instead. This is only for executor type newdeploy and container to set up target CPU utilization of HPA. Applicable for executor type newdeploy and container.",
 	"SpecializationTimeout": "This is the timeout setting for executor to wait for pod specialization.",
 	"hpaMetrics":            "hpaMetrics is the list of metrics used to determine the desired replica count of the Deployment created for the function. Applicable for executor type newdeploy and container.",
 	"hpaBehavior":           "hpaBehavior is the behavior of HPA when scaling in up/down direction. Applicable for executor type newdeploy and container.",
+	"concurrencyScaling":    "ConcurrencyScaling adds a concurrency target to the HPA metrics: the function keeps at least the replicas its in-flight requests need, as the routers report them. Applicable for executor type newdeploy and container.",
+	"scaleToZero":           "ScaleToZero scales the function's deployment to zero replicas once it is idle, whatever MinScale, and has the routers buffer its requests while it scales back up. Applicable for executor type newdeploy and container.",
+	"wasm":                  "Wasm sets the per-invocation limits of a WebAssembly function. Applicable for executor type wasm.",
+	"job":                   "Job sets the retries, deadline and result retention of a function whose invocations run as Kubernetes Jobs. Applicable for executor type job.",
 }
 
 func (ExecutionStrategy) SwaggerDoc() map[string]string {
@@ -254,6 +330,7 @@ var map_FunctionAliasSpec = map[string]string{
 	"packageDigest":    "PackageDigest pins declaratively (GitOps): resolved asynchronously to the FunctionVersion that recorded this digest; eventually consistent.",
 	"weight":           "Weight (0-100) served by the primary target; nil = 100%.",
 	"secondaryVersion": "SecondaryVersion receives 100-Weight. Name-pinned only.",
+	"mirror":           "Mirror copies a share of the alias's traffic to a shadow FunctionVersion, on every route that resolves through the alias.",
 }
 
 func (FunctionAliasSpec) SwaggerDoc() map[string]string {
@@ -310,13 +387,14 @@ var map_FunctionSpec = map[string]string{
 	"env":                    "Env lists per-function environment variables set on the function's runtime container: literals, plus key-level references into same-namespace Secrets/ConfigMaps via valueFrom (secretKeyRef / configMapKeyRef only — fieldRef and resourceFieldRef are rejected at admission because poolmgr's specialize-time injection cannot honor pod-level field refs portably; RFC-0030 §1). Function Env wins over EnvFrom, which wins over the environment podspec's merged env. Platform-reserved names (FISSION_*, RESOURCE_VERSION_COUNT, and the interpreter/proxy-hijack set) are denied at admission and enforced at injection. Additive and backward compatible.",
 	"envFrom":                "EnvFrom projects whole same-namespace Secrets/ConfigMaps into the function's environment, with an optional prefix; later sources win over earlier ones (Kubernetes semantics), and function Env literals win over all EnvFrom keys.\n\nTwo phase-1 limits, both inherent to native (kubelet) injection: the kubelet expands envFrom BEFORE container env, so a name the environment podspec sets as a literal still beats an EnvFrom-supplied key of the same name; and reserved platform names appearing as data keys of a referenced object are not filtered (they are unknowable at admission and mutable afterwards) — they are only shadowed by the platform vars actually present on the container. Key-level filtering and full precedence arrive with the poolmgr phase, which resolves values itself. Additive and backward compatible.",
 	"resources":              "cpu and memory resources as per K8S standards This is only for newdeploy to set up resource limitation when creating deployment for a function.",
+	"resourceClass":          "ResourceClass picks which of the environment's resource classes a poolmgr function runs in. (Optional) defaults to the environment's default pool.",
 	"InvokeStrategy":         "InvokeStrategy is a set of controls which affect how function executes",
 	"functionTimeout":        "FunctionTimeout provides a maximum amount of duration within which a request for a particular function execution should be complete. This is optional. If not specified default value will be taken as 60s",
 	"idletimeout":            "IdleTimeout specifies the length of time that a function is idle before the function pod(s) are eligible for deletion. If no traffic to the function is detected within the idle timeout, the executor will then recycle the function pod(s) to release resources.",
 	"streaming":              "Streaming opts this function into the router's streaming invocation path: incremental flushing, an idle/max timeout split, and a router-driven pod keepalive for the connection's lifetime. When nil (the default) the function uses the classic buffered, retry-on-transient-error proxy path with a single FunctionTimeout deadline. Additive and backward compatible.",
 	"tool":                   "Tool, when non-nil, advertises this function as a Model Context Protocol (MCP) tool on the fission-bundle --mcpPort server. The MCP server watches Function CRDs and hot-updates its tool list from this field. Presence is the on switch (like Streaming): nil (the default) means the function is never advertised as a tool. Additive and backward compatible.",
 	"state":                  "State, when non-nil, opts this function into the RFC-0023 keyed-state API: a scoped statesvc keyspace backed by the RFC-0021 statestore, with a per-function token injected at specialization time. Presence is the on switch (like Streaming and Tool): nil (the default) means exactly today's behavior. Additive and backward compatible.",
-	"invocation":             "Invocation, when non-nil, tunes RFC-0024 asynchronous invocation (X-Fission-Invoke-Mode: async) for this function: the durable retry policy and the maximum event age before an undelivered invocation is dead-lettered. A function without it still accepts async mode with platform defaults; this field only tunes them. Additive and backward compatible.",
+	"invocation":             "Invocation, when non-nil, tunes RFC-0024 asynchronous invocation (X-Fission-Invoke-Mode: async) for this function: the durable retry policy and the maximum event age before an undelivered invocation is dead-lettered, and opts it into the router's circuit breaking. A function without it still accepts async mode with platform defaults; this field only tunes them. Additive and backward compatible.",
 	"concurrency":            "Maximum number of pods to be specialized which will serve requests This is optional. If not specified default value will be taken as 500",
 	"requestsPerPod":         "RequestsPerPod indicates the maximum number of concurrent requests that can be served by a specialized pod This is optional. If not specified default value will be taken as 1",
 	"onceOnly":               "OnceOnly specifies if specialized pod will serve exactly one request in its lifetime and would be garbage collected after serving that one request This is optional. If not specified default value will be taken as false",
@@ -331,12 +409,13 @@ func (FunctionSpec) SwaggerDoc() map[string]string {
 }
 
 var map_FunctionStatus = map[string]string{
-	"":                      "FunctionStatus describes the observed state of a Function.",
-	"observedGeneration":    "ObservedGeneration reflects the .metadata.generation that the controller observed when it last updated the status.",
-	"provisionedReady":      "ProvisionedReady is the number of warm specialized pods the provisioner is currently maintaining for this function (RFC-0026). Only meaningful when Spec.ProvisionedConcurrency is non-nil. Reported by the executor's provisioner on each reconcile pass.",
-	"provisionedTarget":     "ProvisionedTarget is the effective target the provisioner is currently aiming for (base Target, or a schedule-window override in PR 2). Lets `fission fn get` show \"3/5 provisioned pods ready\".",
-	"provisionedSpecTarget": "ProvisionedSpecTarget is the raw Target from spec (before the namespace cap clamp). When ProvisionedSpecTarget > ProvisionedTarget, the provisioner clamped the target to the namespace cap (executor.provisionedConcurrency.maxPerFunction) and the Provisioned condition carries reason ProvisionedClamped. Lets `fission fn get` show the spec-vs-effective divergence.",
-	"conditions":            "Conditions represent the latest observations of the function's state.",
+	"":                        "FunctionStatus describes the observed state of a Function.",
+	"observedGeneration":      "ObservedGeneration reflects the .metadata.generation that the controller observed when it last updated the status.",
+	"provisionedReady":        "ProvisionedReady is the number of warm specialized pods the provisioner is currently maintaining for this function (RFC-0026). Only meaningful when Spec.ProvisionedConcurrency is non-nil. Reported by the executor's provisioner on each reconcile pass.",
+	"provisionedTarget":       "ProvisionedTarget is the effective target the provisioner is currently aiming for (base Target, or a schedule-window override in PR 2). Lets `fission fn get` show \"3/5 provisioned pods ready\".",
+	"provisionedSpecTarget":   "ProvisionedSpecTarget is the raw Target from spec, or the derived target under Spec.ProvisionedConcurrency.Auto (before the namespace cap clamp). When ProvisionedSpecTarget > ProvisionedTarget, the provisioner clamped the target to the namespace cap (executor.provisionedConcurrency.maxPerFunction) and the Provisioned condition carries reason ProvisionedClamped. Lets `fission fn get` show the spec-vs-effective divergence.",
+	"provisionedTargetReason": "ProvisionedTargetReason explains ProvisionedTarget when Spec.ProvisionedConcurrency.Auto is set: the observed concurrency it was derived from, or why Target is still in effect.",
+	"conditions":              "Conditions represent the latest observations of the function's state.",
 }
 
 func (FunctionStatus) SwaggerDoc() map[string]string {
@@ -392,6 +471,58 @@ func (GatewayRouteConfig) SwaggerDoc() map[string]string {
 	return map_GatewayRouteConfig
 }
 
+var map_HTTPHeaderTransform = map[string]string{
+	"":       "HTTPHeaderTransform edits headers: Remove is applied first, then Set, then Add. Names are case insensitive.",
+	"set":    "Set replaces every value of a header with one value.",
+	"add":    "Add appends a value to a header, keeping the values it has.",
+	"remove": "Remove deletes headers, e.g. Cookie.",
+}
+
+func (HTTPHeaderTransform) SwaggerDoc() map[string]string {
+	return map_HTTPHeaderTransform
+}
+
+var map_HTTPHeaderValue = map[string]string{
+	"":      "HTTPHeaderValue is one header name and value.",
+	"name":  "Name of the header.",
+	"value": "Value of the header.",
+}
+
+func (HTTPHeaderValue) SwaggerDoc() map[string]string {
+	return map_HTTPHeaderValue
+}
+
+var map_HTTPMatchCondition = map[string]string{
+	"":      "HTTPMatchCondition matches one request header or query parameter.",
+	"name":  "Name of the header or query parameter.",
+	"type":  "Type is \"exact\" (the default), \"regex\" or \"present\".",
+	"value": "Value is compared according to Type.",
+}
+
+func (HTTPMatchCondition) SwaggerDoc() map[string]string {
+	return map_HTTPMatchCondition
+}
+
+var map_HTTPRequestTransform = map[string]string{
+	"":        "HTTPRequestTransform transforms a request. The body is replaced first, then headers, then the path.",
+	"headers": "Headers edits the request headers.",
+	"path":    "Path is a template for the path forwarded to the function, e.g. \"/v2/users/{{param.id}}\". It replaces the path Prefix and KeepPrefix would produce; {{path}} stands for that path. Placeholder values are escaped as one path segment.",
+	"body":    "Body is a JSON template that replaces the request body, e.g. {\"user\": \"{{form.user}}\"}. Placeholders are meant to sit inside JSON strings; their values are escaped for one. The request Content-Type becomes application/json. A request body over 1 MiB is rejected with 413.",
+}
+
+func (HTTPRequestTransform) SwaggerDoc() map[string]string {
+	return map_HTTPRequestTransform
+}
+
+var map_HTTPResponseTransform = map[string]string{
+	"":        "HTTPResponseTransform transforms a response.",
+	"headers": "Headers edits the response headers. Values are literal: there is no request to expand placeholders from.",
+}
+
+func (HTTPResponseTransform) SwaggerDoc() map[string]string {
+	return map_HTTPResponseTransform
+}
+
 var map_HTTPTrigger = map[string]string{
 	"": "HTTPTrigger is the trigger invokes user functions when receiving HTTP requests.",
 }
@@ -400,6 +531,40 @@ func (HTTPTrigger) SwaggerDoc() map[string]string {
 	return map_HTTPTrigger
 }
 
+var map_HTTPTriggerAPIKeyAuth = map[string]string{
+	"":           "HTTPTriggerAPIKeyAuth checks a request header against the keys stored in a Secret in the trigger's namespace. Each entry of the Secret's data is one client: the entry name is the client identity (forwarded as X-Fission-Auth-Subject) and the value is its key. Rotating a key is a Secret edit; the router picks it up within a minute.",
+	"secretName": "SecretName names the Secret holding the keys.",
+	"header":     "Header is the request header carrying the key. Defaults to X-API-Key. The header is removed before the request reaches the function.",
+}
+
+func (HTTPTriggerAPIKeyAuth) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerAPIKeyAuth
+}
+
+var map_HTTPTriggerAuth = map[string]string{
+	"":       "HTTPTriggerAuth is the per-HTTPTrigger authentication policy. Exactly the block matching Type is set. A request that passes the policy is forwarded with the verified identity in X-Fission-Auth-* headers; the router strips any client-supplied X-Fission-Auth-* headers first, so a function on an authenticated route can trust them.",
+	"type":   "Type selects the policy: \"anonymous\" admits every request, \"jwt\" verifies a bearer token against an issuer's JWKS, \"apiKey\" checks a key against a Secret.",
+	"jwt":    "JWT configures bearer-token validation. Required when Type is \"jwt\".",
+	"apiKey": "APIKey configures API-key validation. Required when Type is \"apiKey\".",
+}
+
+func (HTTPTriggerAuth) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerAuth
+}
+
+var map_HTTPTriggerCache = map[string]string{
+	"":                            "HTTPTriggerCache caches a trigger's GET responses in the router, in a memory LRU bounded per router replica and optionally in the statestore KV shared by every replica. The function's Cache-Control is honored: no-store, no-cache and private responses are never stored, and s-maxage, max-age or Expires override TTLSeconds. Concurrent misses for the same response are coalesced into one function call. Entries are keyed by the function version that produced them, so an update to the function stops serving its old responses at once.",
+	"ttlSeconds":                  "TTLSeconds is how long a response stays fresh when it carries no freshness of its own.",
+	"staleWhileRevalidateSeconds": "StaleWhileRevalidateSeconds is how long past its freshness a response may still be served while the router refreshes it in the background. A stale-while-revalidate directive on the response overrides it. Defaults to 0: expired responses are not served.",
+	"vary":                        "Vary lists the request headers whose values select distinct cached responses, on top of the request URL and the headers named by the response's own Vary header.",
+	"maxObjectBytes":              "MaxObjectBytes is the largest response body stored; larger responses are served uncached. Defaults to 1 MiB.",
+	"shared":                      "Shared also stores responses in the statestore KV, so a response cached by one router replica is served by all of them. It needs the statestore (async invocation enabled on the router); without it the cache stays per replica.",
+}
+
+func (HTTPTriggerCache) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerCache
+}
+
 var map_HTTPTriggerCorsConfig = map[string]string{
 	"":                 "HTTPTriggerCorsConfig is the per-HTTPTrigger CORS allowlist. It is consumed by the router public listener to attach a CORS middleware to the trigger's route. Triggers without a CorsConfig receive no Access-Control-* response headers and therefore deny cross-origin browser reads at the Same-Origin Policy layer.",
 	"allowOrigins":     "AllowOrigins is the list of allowed origins (scheme + host + port). Use [\"*\"] to allow any origin. Mixing \"*\" with AllowCredentials=true is a configuration error and is rejected by validation; browsers refuse the response in that combination.",
@@ -414,6 +579,29 @@ func (HTTPTriggerCorsConfig) SwaggerDoc() map[string]string {
 	return map_HTTPTriggerCorsConfig
 }
 
+var map_HTTPTriggerGRPC = map[string]string{
+	"":        "HTTPTriggerGRPC selects the gRPC calls a trigger serves by their service and, optionally, method. Clients reach the router over HTTP/2 in cleartext (h2c, prior knowledge) or through a TLS-terminating ingress that speaks h2c upstream.",
+	"service": "Service is the fully qualified service name, package included, e.g. helloworld.Greeter.",
+	"methods": "Methods lists the service methods the trigger serves. Empty serves every method of the service.",
+}
+
+func (HTTPTriggerGRPC) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerGRPC
+}
+
+var map_HTTPTriggerJWTAuth = map[string]string{
+	"":               "HTTPTriggerJWTAuth validates an \"Authorization: Bearer\" JWT against the public keys an OIDC issuer publishes. Only asymmetric signatures (RS*, PS*, ES*, EdDSA) are accepted; the router caches the key set and refetches it when a token names an unknown key ID.",
+	"issuer":         "Issuer is the expected \"iss\" claim, e.g. https://accounts.example.com. Unless JWKSURI is set, the key set is located through the issuer's OpenID discovery document (<issuer>/.well-known/openid-configuration).",
+	"jwksUri":        "JWKSURI is the URL of the issuer's JSON Web Key Set. Set it for issuers that do not serve OpenID discovery.",
+	"audiences":      "Audiences lists the accepted \"aud\" values; a token must carry at least one of them. Empty skips the audience check.",
+	"requiredClaims": "RequiredClaims maps a claim name to the value it must have. A claim holding a list matches when any element equals the value (e.g. {\"groups\": \"admins\"}).",
+	"forwardClaims":  "ForwardClaims maps a claim name to the request header its value is forwarded in, in addition to the \"sub\" claim, which is always forwarded as X-Fission-Auth-Subject. Header names must start with X-Fission-Auth-.",
+}
+
+func (HTTPTriggerJWTAuth) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerJWTAuth
+}
+
 var map_HTTPTriggerList = map[string]string{
 	"": "HTTPTriggerList is a list of HTTPTriggers",
 }
@@ -422,6 +610,55 @@ func (HTTPTriggerList) SwaggerDoc() map[string]string {
 	return map_HTTPTriggerList
 }
 
+var map_HTTPTriggerMatch = map[string]string{
+	"":            "HTTPTriggerMatch is a set of request conditions that must ALL hold for a trigger to serve a request. A request that fails them falls through to the next trigger registered for its path, and to 404 when there is none.",
+	"headers":     "Headers each require a request header to match. Names are case insensitive.",
+	"queryParams": "QueryParams each require a URL query parameter to match. Names are case sensitive.",
+	"hosts":       "Hosts restricts the trigger to requests for one of these hosts, matched by the router itself against the Host header (port ignored). An entry is an exact DNS name or a wildcard \"*.example.com\", which matches any name with at least one more label. Unlike Host, Hosts configures no ingress or gateway route.",
+}
+
+func (HTTPTriggerMatch) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerMatch
+}
+
+var map_HTTPTriggerOpenAPI = map[string]string{
+	"":               "HTTPTriggerOpenAPI enriches the trigger's operations in the generated OpenAPI document. It does not change how requests are routed or checked: the schemas are documentation, not validation.",
+	"exclude":        "Exclude leaves the trigger out of the document, e.g. a webhook only a third party calls.",
+	"summary":        "Summary is the operations' one-line summary.",
+	"description":    "Description is the operations' description. Defaults to the function's Tool description.",
+	"tags":           "Tags group the operations in the document.",
+	"requestSchema":  "RequestSchema is the JSON Schema (draft 2020-12) of the JSON request body. Defaults to the function's Tool input schema.",
+	"responseSchema": "ResponseSchema is the JSON Schema (draft 2020-12) of the JSON response body.",
+}
+
+func (HTTPTriggerOpenAPI) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerOpenAPI
+}
+
+var map_HTTPTriggerRateLimit = map[string]string{
+	"":              "HTTPTriggerRateLimit is a token bucket: it holds up to Burst tokens, refills at Requests per PeriodSeconds, and each admitted request takes one. Buckets are local to each router replica unless Global is set, so without Global the cluster-wide limit is the per-replica limit times the router replica count.",
+	"requests":      "Requests is the sustained number of requests admitted per period.",
+	"periodSeconds": "PeriodSeconds is the period Requests is measured over. Defaults to 1.",
+	"burst":         "Burst is the bucket size: how many requests a client that has been idle may send at once. Defaults to Requests.",
+	"key":           "Key selects what requests are counted by. Defaults to one bucket for the whole trigger.",
+	"global":        "Global enforces the limit across all router replicas through the statestore KV, at the cost of a statestore round trip per request. It needs the statestore (async invocation enabled on the router); without it, and whenever the statestore is unreachable, the router falls back to its local buckets rather than failing requests.",
+}
+
+func (HTTPTriggerRateLimit) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerRateLimit
+}
+
+var map_HTTPTriggerRateLimitKey = map[string]string{
+	"":                 "HTTPTriggerRateLimitKey selects the bucket a request is counted in. Requests that lack the key (no such header, no such claim) share one bucket.",
+	"source":           "Source is \"trigger\" (the default: one bucket for every caller), \"ip\" (the client address), \"header\" (the value of header Name) or \"claim\" (the value of verified JWT claim Name; the trigger needs a jwt Auth policy).",
+	"name":             "Name is the header or claim name for the header and claim sources.",
+	"trustedProxyHops": "TrustedProxyHops is, for the ip source, how many proxies in front of the router append to X-Forwarded-For (e.g. 1 for a single ingress controller or load balancer). The client address is taken that many entries from the right; 0, the default, uses the connection's peer address and ignores the header, which a client can forge.",
+}
+
+func (HTTPTriggerRateLimitKey) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerRateLimitKey
+}
+
 var map_HTTPTriggerSpec = map[string]string{
 	"":               "HTTPTriggerSpec is for router to expose user functions at the given URL path.",
 	"host":           "Deprecated: the original idea of this field is not for setting Ingress. Since we have IngressConfig now, remove Host after couple releases.",
@@ -436,6 +673,14 @@ var map_HTTPTriggerSpec = map[string]string{
 	"ingressconfig":  "IngressConfig for router to set up Ingress. Deprecated: superseded by RouteConfig. See CreateIngress.",
 	"routeConfig":    "RouteConfig declares how the router exposes this trigger through an external route provider (Ingress or the Gateway API). It is the provider-neutral successor to CreateIngress + IngressConfig: when set it takes precedence over those fields. Leave nil to expose the function only through the router's own URL.",
 	"corsConfig":     "CorsConfig configures CORS response headers for browser callers of this trigger. When nil, the router emits no Access-Control-* headers and the browser's Same-Origin Policy enforces cluster isolation from cross-origin pages (the deny-by-default behaviour). Set this field to allowlist specific origins for SPAs that legitimately call this trigger cross-origin.",
+	"auth":           "Auth is the trigger's authentication policy, enforced by the router on this route before the request reaches the function. When nil the route is governed only by the cluster-wide router auth switch (authentication.enabled); when set it is enforced in addition to that switch, never instead of it.",
+	"rateLimit":      "RateLimit caps the request rate the router admits to this trigger, per trigger or per client. Requests over the limit are answered 429 with Retry-After and never reach the function. Nil means unlimited.",
+	"mirror":         "Mirror copies a share of this trigger's requests to a shadow FunctionVersion of the referenced function. Only valid with a functionref of type name; it takes precedence over a Mirror on the FunctionAlias the trigger references.",
+	"match":          "Match narrows the requests this trigger serves beyond its path and methods, by header, query parameter and host. Triggers may share a path and differ only in Match (X-Api-Version: 2 to one function, everything else to another); the router tries the more specific match first. Nil matches every request on the path.",
+	"cache":          "Cache lets the router answer repeated GETs from its own response cache instead of invoking the function. Only for functions whose GET responses depend on nothing but the URL and the headers named in Vary. Nil disables caching.",
+	"grpc":           "GRPC routes the calls of a gRPC service to the function in place of RelativeURL and Prefix: the trigger serves POST /<service>/<method> and keeps the path when it forwards the call. The function should set Streaming.Protocol to grpc, so the router speaks HTTP/2 to it.",
+	"transform":      "Transform edits requests on their way to the function and responses on their way back: headers added, set or removed, the forwarded path rebuilt from a template, the request body replaced by a JSON template. It stands in for the small adapter functions otherwise deployed in front of a function for the same job.",
+	"openapi":        "OpenAPI describes the trigger's operations in the OpenAPI document generated for its namespace (`fission httptrigger openapi`). The document is built without it from the trigger's path, methods and CORS settings and the function's Tool description and input schema; this block adds what those cannot say.",
 }
 
 func (HTTPTriggerSpec) SwaggerDoc() map[string]string {
@@ -450,6 +695,16 @@ func (HTTPTriggerStatus) SwaggerDoc() map[string]string {
 	return map_HTTPTriggerStatus
 }
 
+var map_HTTPTriggerTransform = map[string]string{
+	"":         "HTTPTriggerTransform is applied by the router once per request, after authentication and rate limiting and before the request is queued, cached, mirrored or proxied, so every one of those sees the transformed request.\n\nRequest templates (Path, Body and request header values) may use these placeholders, all read from the request as the client sent it:\n\n\t{{path}}          the path the function would otherwise receive\n\t{{param.<name>}}  a variable of the trigger's path template\n\t{{query.<name>}}  a query parameter\n\t{{header.<name>}} a request header\n\t{{form.<name>}}   a field of a urlencoded form body (Body only)\n\nA placeholder with no value expands to the empty string.",
+	"request":  "Request transforms the request forwarded to the function.",
+	"response": "Response transforms the function's response headers. Responses the router answers itself (errors, rate limits) are not transformed.",
+}
+
+func (HTTPTriggerTransform) SwaggerDoc() map[string]string {
+	return map_HTTPTriggerTransform
+}
+
 var map_IngressConfig = map[string]string{
 	"":            "IngressConfig is for router to set up Ingress. Deprecated: superseded by RouteConfig. The Kubernetes Ingress API is frozen; use RouteConfig with Provider \"gateway\" for new triggers.",
 	"annotations": "Annotations will be added to metadata when creating Ingress.",
@@ -463,11 +718,13 @@ func (IngressConfig) SwaggerDoc() map[string]string {
 }
 
 var map_InvocationConfig = map[string]string{
-	"":          "InvocationConfig tunes RFC-0024 asynchronous invocation for a function. Presence of the enclosing FunctionSpec.Invocation is optional — a function without it still accepts async mode (X-Fission-Invoke-Mode: async) with platform defaults; this struct only tunes them. Field bounds are validated in Go (InvocationConfig.Validate, run at admission via validateForAdmission), not CEL, because metav1.Duration CEL rules are unproven in this CRD. An external dead-letter target is a later RFC-0024 phase.",
-	"retry":     "Retry is the durable delivery retry policy. The zero value means platform defaults (a bounded exponential backoff over DefaultMaxAttempts attempts).",
-	"maxAge":    "MaxAge caps how long an invocation may wait for successful delivery, measured from its enqueue time; once exceeded it is dead-lettered with reason \"expired\". nil means the platform default. Must be > 0 when set.",
-	"onSuccess": "OnSuccess, when set, invokes a destination with a Lambda-shaped result envelope after the invocation is delivered successfully (2xx).",
-	"onFailure": "OnFailure, when set, invokes a destination with the result envelope after the invocation permanently fails (a non-retryable 4xx, the retry budget spent, or MaxAge exceeded).",
+	"":               "InvocationConfig tunes RFC-0024 asynchronous invocation for a function, and the router's circuit breaker for every invocation of it. Presence of the enclosing FunctionSpec.Invocation is optional — a function without it still accepts async mode (X-Fission-Invoke-Mode: async) with platform defaults; this struct only tunes them. Field bounds are validated in Go (InvocationConfig.Validate, run at admission via validateForAdmission), not CEL, because metav1.Duration CEL rules are unproven in this CRD. An external dead-letter target is a later RFC-0024 phase.",
+	"retry":          "Retry is the durable delivery retry policy. The zero value means platform defaults (a bounded exponential backoff over DefaultMaxAttempts attempts).",
+	"maxAge":         "MaxAge caps how long an invocation may wait for successful delivery, measured from its enqueue time; once exceeded it is dead-lettered with reason \"expired\". nil means the platform default. Must be > 0 when set.",
+	"onSuccess":      "OnSuccess, when set, invokes a destination with a Lambda-shaped result envelope after the invocation is delivered successfully (2xx).",
+	"onFailure":      "OnFailure, when set, invokes a destination with the result envelope after the invocation permanently fails (a non-retryable 4xx, the retry budget spent, or MaxAge exceeded).",
+	"priority":       "Priority is the default async priority lane for this function's invocations. A request's X-Fission-Invoke-Priority header overrides it. Each lane is its own statestore queue and the dispatcher polls them weighted-fair, so a bulk backfill on \"low\" cannot delay latency-sensitive work on \"high\", and \"low\" still drains while \"high\" is busy. Empty means \"normal\", the lane every invocation used before lanes existed.",
+	"circuitBreaker": "CircuitBreaker, when non-nil, opts the function into per-endpoint circuit breaking in the router: an endpoint (specialized pod) that keeps failing is ejected from the router's endpoint index for a while, then probed before it takes full traffic again. Applies to synchronous and asynchronous invocations alike. nil (the default) leaves a failing pod in rotation, as before.",
 }
 
 func (InvocationConfig) SwaggerDoc() map[string]string {
@@ -484,6 +741,17 @@ func (InvokeStrategy) SwaggerDoc() map[string]string {
 	return map_InvokeStrategy
 }
 
+var map_JobConfig = map[string]string{
+	"":               "JobConfig configures a function run by the job executor. Every invocation of such a function, sync or async, is a Kubernetes Job built from the function's environment and package: the router answers 202 with an invocation id straight away, the Job posts the request body to the specialized runtime once, and the router records the runtime's response and output when the Job finishes and fires the function's OnSuccess/OnFailure destinations. FunctionTimeout does not apply; ActiveDeadline bounds the whole Job instead.",
+	"backoffLimit":   "BackoffLimit is how many times a failed Job pod is retried before the invocation fails, from 0 to 10. Defaults to 0.",
+	"resultTTL":      "ResultTTL is how long an invocation's status and result are kept after its Job finishes, from 1m to 720h. Format is Go time.ParseDuration. Defaults to 24h.",
+	"activeDeadline": "ActiveDeadline is how long an invocation's Job may run, retries included, before it is stopped and the invocation fails, from 1m to 168h. Format is Go time.ParseDuration. Unset, the Job runs until it finishes.",
+}
+
+func (JobConfig) SwaggerDoc() map[string]string {
+	return map_JobConfig
+}
+
 var map_KubernetesWatchTrigger = map[string]string{
 	"": "KubernetesWatchTrigger watches kubernetes resource events and invokes functions.",
 }
@@ -630,10 +898,38 @@ func (PackageStatus) SwaggerDoc() map[string]string {
 	return map_PackageStatus
 }
 
+var map_PoolAutoscaleConfig = map[string]string{
+	"":               "PoolAutoscaleConfig sizes an environment's pre-warm pool from demand. Every specialization consumes a warm pod that the pool then has to replace, so the pool holds the specializations of the last Window plus TargetIdle, bounded by MinSize and MaxSize. It grows as soon as that demand is seen and shrinks one pod at a time once the demand has stayed lower for ScaleDownDelay. Only version 3 environments with single functions per container can autoscale their pool.",
+	"minSize":        "MinSize is the smallest pool.",
+	"maxSize":        "MaxSize is the largest pool. It must be at least 1 and MinSize.",
+	"targetIdle":     "TargetIdle is how many warm pods are kept on top of the recent demand. Defaults to 1.",
+	"window":         "Window is how far back specializations are counted, from 10s to 15m. It should cover the time a new pool pod takes to become ready. Format is Go time.ParseDuration. Defaults to 1m.",
+	"scaleDownDelay": "ScaleDownDelay is how long the demand must stay below the pool size before the pool shrinks, from 0 to 1h. Format is Go time.ParseDuration. Defaults to 5m.",
+}
+
+func (PoolAutoscaleConfig) SwaggerDoc() map[string]string {
+	return map_PoolAutoscaleConfig
+}
+
+var map_ProvisionedAutoConfig = map[string]string{
+	"":                "ProvisionedAutoConfig sizes the provisioned target from traffic history. Concurrency is the number of requests in flight to the function, as the routers report it in their taps and as the executor counts the requests it resolves; it is sampled as the peak of every 10 seconds. The target is the Percentile of those samples over Window, or, with seasonality, of the same time of day on the previous days when that is higher, plus HeadroomPercent, in pods (RequestsPerPod requests each) and bounded by Min and Max.",
+	"percentile":      "Percentile of the samples the target covers, 1 to 100. Defaults to 95.",
+	"window":          "Window is the sliding window of recent samples, from 1m to 1h. Format is Go time.ParseDuration. Defaults to 15m.",
+	"headroomPercent": "HeadroomPercent is added to the observed concurrency, 0 to 400.",
+	"min":             "Min is the lowest target. 0 (the default) lets an idle function drain every provisioned pod.",
+	"max":             "Max is the highest target. 0 (the default) leaves only the namespace cap (executor.provisionedConcurrency.maxPerFunction).",
+	"seasonalityDays": "SeasonalityDays is how many previous days the time-of-day pattern is learned from, 0 to 14; 0 (the default) disables it. With it the target also covers the concurrency seen on those days in the coming 15 minutes, so pods are warm before a daily peak rather than after it.",
+}
+
+func (ProvisionedAutoConfig) SwaggerDoc() map[string]string {
+	return map_ProvisionedAutoConfig
+}
+
 var map_ProvisionedConcurrencyConfig = map[string]string{
 	"":        "ProvisionedConcurrencyConfig opts this function into eager pre-warming of specialized pods (RFC-0026). Presence is the on switch: nil (the default) means the function uses the classic on-demand cold-start path. When non-nil, the executor's provisioner keeps at least Target specialized pods warm and published to the function's headless Service, exempt from the idle reaper. Additive and backward compatible.",
 	"target":  "Target is the base number of warm specialized pods to maintain outside any schedule window. Must be >= 1. Schedule windows may override this (see Windows). Bounded by the namespace cap (executor.provisionedConcurrency.maxPerFunction, default 20).",
 	"windows": "Windows is an optional list of schedule windows that override Target during specific time ranges (RFC-0026 PR 2). Empty in PR 1 — base Target is always in effect. Each window: a cron start expression, a duration, and a window-local target (0 means \"un-warm\" for the window's duration).",
+	"auto":    "Auto derives the target from the concurrency the function is observed to serve instead of fixing it at Target. Target stays in effect until Auto.Window of history has been observed (after an executor restart too), and an open schedule window still overrides the derived target.",
 }
 
 func (ProvisionedConcurrencyConfig) SwaggerDoc() map[string]string {
@@ -652,6 +948,19 @@ func (ProvisionedWindow) SwaggerDoc() map[string]string {
 	return map_ProvisionedWindow
 }
 
+var map_ResourceClass = map[string]string{
+	"":             "ResourceClass is a named pre-warm pool of an environment. Its pods run the environment's runtime with the class's resources and are placed by its node selector and tolerations, so a function that needs more memory or particular nodes does not force a bigger pool on every other function of the environment.",
+	"name":         "Name is what FunctionSpec.ResourceClass refers to. It must be a DNS label.",
+	"resources":    "Resources replace the environment's Resources for the pods of this class. (Optional) defaults to the environment's Resources.",
+	"poolsize":     "Poolsize is the number of warm pods kept for this class; the environment's PoolAutoscale does not apply to class pools. (Optional) defaults to 1.",
+	"nodeSelector": "NodeSelector is merged into the node selector of the pods of this class.",
+	"tolerations":  "Tolerations are appended to the tolerations of the pods of this class.",
+}
+
+func (ResourceClass) SwaggerDoc() map[string]string {
+	return map_ResourceClass
+}
+
 var map_RetryPolicy = map[string]string{
 	"":            "RetryPolicy is the async delivery retry policy: the attempt budget and the exponential-backoff schedule between delivery attempts. All fields are optional; a nil field takes the platform default.",
 	"maxAttempts": "MaxAttempts is the total number of delivery attempts before the invocation is dead-lettered. nil means DefaultMaxAttempts. Must be >= 1 when set.",
@@ -697,6 +1006,17 @@ func (Runtime) SwaggerDoc() map[string]string {
 	return map_Runtime
 }
 
+var map_ScaleToZeroConfig = map[string]string{
+	"":                   "ScaleToZeroConfig is the activator mode of a function. Once the function has been idle for its IdleTimeout, the executor scales its deployment to zero replicas and keeps the deployment, service and HPA. A router then buffers the function's requests until a replica is ready, and asks the executor for one replica per RequestsPerReplica buffered requests, between MinScale and MaxScale. MinScale applies again once the function is scaled up.\n\nBuffering needs the router's EndpointSlice cache, which tells it the function has no ready endpoint; without it the router waits on the executor as for any cold start.",
+	"queueSize":          "QueueSize is the number of requests one router buffers for the function while it scales from zero, from 1 to 10000. Requests beyond it are rejected with 429. Defaults to 100.",
+	"queueTimeout":       "QueueTimeout is how long a buffered request waits for a replica, from 1s to 10m. Format is Go time.ParseDuration. Defaults to 1m.",
+	"requestsPerReplica": "RequestsPerReplica is the number of buffered requests one replica is started for. Defaults to 10.",
+}
+
+func (ScaleToZeroConfig) SwaggerDoc() map[string]string {
+	return map_ScaleToZeroConfig
+}
+
 var map_SecretReference = map[string]string{
 	"":          "SecretReference is a reference to a kubernetes secret.",
 	"mountPath": "MountPath redirects this secret's file projection from the default /secrets/<namespace>/<name> to the given path, which is relative to the /secrets root (RFC-0030 §4): generic pool pods share a fixed volume set frozen at pool creation, so an arbitrary absolute path is not materializable there, and the container executor applies the same constraint for cross-executor consistency. Empty keeps today's <namespace>/<name> layout, so functions that do not set it are unaffected. No two secrets on one function may RESOLVE to the same directory (an explicit path colliding with another reference's default counts): the final segment written is the object's data key and keys are mutable after admission, so sharing a directory would let one object's later-added key collide with the other's file; the fetcher refuses such a write rather than truncating. Honoured on every executor: poolmgr and newdeploy via the fetcher, the container executor via a native projected volume. Not supported on an allowedFunctionsPerContainer:infinite environment, whose pods share one secrets tree across functions.",
@@ -799,6 +1119,16 @@ func (TopicRef) SwaggerDoc() map[string]string {
 	return map_TopicRef
 }
 
+var map_TrafficMirror = map[string]string{
+	"":        "TrafficMirror shadows live traffic onto a FunctionVersion: the router copies Percent of the synchronous requests it serves to Version in the background and discards the shadow's response, recording how its status and latency compare with the primary's. The client only ever sees the primary response, and the shadow never slows it down: copies are dropped (and counted) rather than queued when the router is busy, and requests with a body over 1 MiB or an upgrade are never copied. The shadow runs real code against real dependencies, so it should be safe to invoke twice.",
+	"version": "Version is the FunctionVersion name the copies are sent to. It must belong to the same function.",
+	"percent": "Percent (1-100) of requests that are copied. Defaults to 100.",
+}
+
+func (TrafficMirror) SwaggerDoc() map[string]string {
+	return map_TrafficMirror
+}
+
 var map_VersioningConfig = map[string]string{
 	"":       "VersioningConfig opts a Function into RFC-0025 immutable version snapshots and named aliases.",
 	"mode":   "Mode auto (default) mints a version on every runtime-affecting update once the referenced package build succeeds; manual mints only on explicit `fission fn publish`.",
@@ -809,6 +1139,16 @@ func (VersioningConfig) SwaggerDoc() map[string]string {
 	return map_VersioningConfig
 }
 
+var map_WasmConfig = map[string]string{
+	"":                 "WasmConfig limits the invocations of a function run by the wasm executor. The function's package holds a WASI (preview1) command module, which a shared host pod in the function namespace compiles once and instantiates afresh for every request. The host maps the request onto the module CGI style: the method, path, query and headers as environment variables, the body as stdin, and a response of headers, a blank line and the body read from stdout.",
+	"maxMemory":        "MaxMemory is the linear memory one invocation may grow to, from 64Ki (one page) to 4Gi. Defaults to 64Mi.",
+	"maxExecutionTime": "MaxExecutionTime is how long one invocation may run before the host stops it and answers 504, from 1ms to 10m. Format is Go time.ParseDuration. Defaults to 30s.",
+}
+
+func (WasmConfig) SwaggerDoc() map[string]string {
+	return map_WasmConfig
+}
+
 var map_Workflow = map[string]string{
 	"": "Workflow declares a durable state machine whose task states are Fission functions (RFC-0022). The engine executes WorkflowRuns against a snapshot of this spec embedded in the run's event stream; editing a Workflow never changes in-flight runs.",
 }
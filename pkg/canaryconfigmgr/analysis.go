@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package canaryconfigmgr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// Progressive-delivery gates (CanaryConfigSpec.Analysis and .Webhooks). Each
+// interval, once the new target carries traffic, analyze judges it on the
+// last window: FailureThreshold as before, then the optional minimum request
+// count, latency percentiles and PromQL templates. A check that cannot be
+// judged yet (no data, a query error) holds the rollout at its current
+// weights; a check that fails rolls it back. When the analysis passes, or
+// before the first increment when there is nothing to analyze yet, every
+// webhook gate is asked to approve the step.
+
+// verdictResult is what a check concluded.
+type verdictResult int
+
+const (
+	verdictPass verdictResult = iota
+	// verdictHold keeps the current weights and retries next interval.
+	verdictHold
+	// verdictFail rolls the traffic back and fails the rollout.
+	verdictFail
+)
+
+// verdict is a check's result and, for a hold or failure, why. reason is
+// empty for the FailureThreshold check, whose outcomes keep the default
+// condition messages they always had.
+type verdict struct {
+	result verdictResult
+	reason string
+}
+
+// outcome maps a verdict onto the step's result; rollback is called on
+// verdictFail.
+func (v verdict) outcome(rollback func() error) (stepOutcome, error) {
+	switch v.result {
+	case verdictHold:
+		return stepOutcome{requeue: true, message: v.reason}, nil
+	case verdictFail:
+		if err := rollback(); err != nil {
+			return stepOutcome{}, err
+		}
+		message := ""
+		if v.reason != "" {
+			message = "canary rollout failed; traffic rolled back: " + v.reason
+		}
+		return stepOutcome{terminalStatus: fv1.CanaryConfigStatusFailed, message: message}, nil
+	}
+	return stepOutcome{}, nil
+}
+
+// analyze runs the rollout's checks against the new target over q's window.
+func (m *canaryConfigMgr) analyze(ctx context.Context, cfg *fv1.CanaryConfig, q failureQuery) verdict {
+	log := m.logger.WithValues("name", cfg.Name, "namespace", cfg.Namespace)
+	a := cfg.Spec.Analysis
+
+	if a != nil && a.MinRequests > 0 {
+		count, err := m.promClient.GetFunctionRequestCount(ctx, q)
+		if err != nil {
+			log.Error(err, "error counting requests; will retry")
+			return verdict{verdictHold, fmt.Sprintf("counting requests failed: %v", err)}
+		}
+		if count < float64(a.MinRequests) {
+			return verdict{verdictHold, fmt.Sprintf("waiting for %d requests to the new target, %.0f in the last window", a.MinRequests, count)}
+		}
+	}
+
+	failurePercent, err := m.promClient.GetFunctionFailurePercentage(ctx, q)
+	if err != nil {
+		// Transient query error — check again next window rather than aborting.
+		log.Error(err, "error calculating failure percentage; will retry")
+		return verdict{result: verdictHold}
+	}
+	if failurePercent == -1 {
+		// No requests reached the url in this window — nothing to evaluate.
+		log.Info("no requests observed for url in window", "url", q.Path)
+		return verdict{result: verdictHold}
+	}
+	if int(failurePercent) > cfg.Spec.FailureThreshold {
+		log.Info("failure percentage crossed threshold; rolling back",
+			"failure_percent", failurePercent, "threshold", cfg.Spec.FailureThreshold)
+		return verdict{result: verdictFail}
+	}
+	if a == nil {
+		return verdict{result: verdictPass}
+	}
+
+	for _, l := range a.Latency {
+		seconds, err := m.promClient.GetFunctionLatencyPercentile(ctx, q, float64(l.Percentile)/100)
+		switch {
+		case err != nil:
+			log.Error(err, "error querying latency; will retry", "percentile", l.Percentile)
+			return verdict{verdictHold, fmt.Sprintf("p%d latency query failed: %v", l.Percentile, err)}
+		case math.IsNaN(seconds):
+			return verdict{verdictHold, fmt.Sprintf("no p%d latency data for the new target yet", l.Percentile)}
+		case seconds*1000 > float64(l.MaxMilliseconds):
+			log.Info("latency crossed threshold; rolling back", "percentile", l.Percentile, "seconds", seconds)
+			return verdict{verdictFail, fmt.Sprintf("p%d latency %.0fms exceeds %dms", l.Percentile, seconds*1000, l.MaxMilliseconds)}
+		}
+	}
+
+	for i := range a.Templates {
+		t := &a.Templates[i]
+		query := renderAnalysisQuery(t.Query, q)
+		value, err := m.promClient.QueryScalar(ctx, query)
+		switch {
+		case err != nil:
+			log.Error(err, "error running analysis template; will retry", "template", t.Name)
+			return verdict{verdictHold, fmt.Sprintf("analysis %q failed to run: %v", t.Name, err)}
+		case math.IsNaN(value):
+			return verdict{verdictHold, fmt.Sprintf("analysis %q returned no data yet", t.Name)}
+		case t.Min != nil && value < t.Min.AsApproximateFloat64():
+			log.Info("analysis template below its minimum; rolling back", "template", t.Name, "value", value)
+			return verdict{verdictFail, fmt.Sprintf("analysis %q returned %g, below the minimum %s", t.Name, value, t.Min.String())}
+		case t.Max != nil && value > t.Max.AsApproximateFloat64():
+			log.Info("analysis template above its maximum; rolling back", "template", t.Name, "value", value)
+			return verdict{verdictFail, fmt.Sprintf("analysis %q returned %g, above the maximum %s", t.Name, value, t.Max.String())}
+		}
+	}
+	return verdict{result: verdictPass}
+}
+
+// renderAnalysisQuery replaces a template's placeholders (see
+// fv1.CanaryAnalysisTemplate) with q's values.
+func renderAnalysisQuery(query string, q failureQuery) string {
+	return strings.NewReplacer(
+		"{{function}}", q.Function,
+		"{{version}}", q.Version,
+		"{{namespace}}", q.Namespace,
+		"{{path}}", q.Path,
+		"{{window}}", q.Window,
+	).Replace(query)
+}
+
+// gateRequest is the JSON body a CanaryWebhook receives.
+type gateRequest struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Trigger    string `json:"trigger"`
+	OldTarget  string `json:"oldTarget"`
+	NewTarget  string `json:"newTarget"`
+	Weight     int    `json:"weight"`
+	NextWeight int    `json:"nextWeight"`
+}
+
+// askGates asks every webhook gate to approve moving the new target from
+// weight to next. The first refusal decides: Hold keeps the weights,
+// Rollback fails the rollout.
+func (m *canaryConfigMgr) askGates(ctx context.Context, cfg *fv1.CanaryConfig, weight, next int) verdict {
+	body, err := json.Marshal(gateRequest{
+		Name:       cfg.Name,
+		Namespace:  cfg.Namespace,
+		Trigger:    cfg.Spec.Trigger,
+		OldTarget:  cfg.Spec.OldFunction,
+		NewTarget:  cfg.Spec.NewFunction,
+		Weight:     weight,
+		NextWeight: next,
+	})
+	if err != nil {
+		return verdict{verdictHold, fmt.Sprintf("encoding webhook request: %v", err)}
+	}
+	for i := range cfg.Spec.Webhooks {
+		w := &cfg.Spec.Webhooks[i]
+		if err := m.callGate(ctx, w, body); err != nil {
+			m.logger.Info("canary webhook gate refused the step",
+				"name", cfg.Name, "namespace", cfg.Namespace, "webhook", w.Name, "error", err.Error())
+			if w.FailurePolicy == fv1.CanaryWebhookRollback {
+				return verdict{verdictFail, fmt.Sprintf("webhook %q refused: %v", w.Name, err)}
+			}
+			return verdict{verdictHold, fmt.Sprintf("webhook %q refused the step to weight %d: %v", w.Name, next, err)}
+		}
+	}
+	return verdict{result: verdictPass}
+}
+
+// callGate POSTs body to a gate, returning nil on a 2xx answer.
+func (m *canaryConfigMgr) callGate(ctx context.Context, w *fv1.CanaryWebhook, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, w.EffectiveTimeout())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := m.gateClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		// A short excerpt of the body lets a gate say why it refused.
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		if msg := strings.TrimSpace(string(excerpt)); msg != "" {
+			return fmt.Errorf("%s: %s", resp.Status, msg)
+		}
+		return errors.New(resp.Status)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
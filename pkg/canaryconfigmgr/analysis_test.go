@@ -0,0 +1,275 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package canaryconfigmgr
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Run("too few requests holds with a message", func(t *testing.T) {
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Analysis = &fv1.CanaryAnalysis{MinRequests: 100}
+		mgr, _, c := newTestEnv(&fakeFailureClient{pct: 0, count: 12}, trigger, cc)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.True(t, out.requeue)
+		assert.Contains(t, out.message, "waiting for 100 requests")
+		assert.Equal(t, 30, getTrigger(t, c).Spec.FunctionReference.FunctionWeights["new"])
+	})
+
+	t.Run("latency over its limit rolls back", func(t *testing.T) {
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Analysis = &fv1.CanaryAnalysis{
+			Latency: []fv1.CanaryLatencyThreshold{{Percentile: 99, MaxMilliseconds: 500}},
+		}
+		mgr, _, c := newTestEnv(&fakeFailureClient{pct: 0, latency: 0.8}, trigger, cc)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.Equal(t, fv1.CanaryConfigStatusFailed, out.terminalStatus)
+		assert.Contains(t, out.message, "p99 latency 800ms exceeds 500ms")
+
+		got := getTrigger(t, c)
+		assert.Equal(t, 0, got.Spec.FunctionReference.FunctionWeights["new"])
+		assert.Equal(t, 100, got.Spec.FunctionReference.FunctionWeights["old"])
+	})
+
+	t.Run("no latency data holds", func(t *testing.T) {
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Analysis = &fv1.CanaryAnalysis{
+			Latency: []fv1.CanaryLatencyThreshold{{Percentile: 95, MaxMilliseconds: 500}},
+		}
+		mgr, _, c := newTestEnv(&fakeFailureClient{pct: 0, latency: math.NaN()}, trigger, cc)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.True(t, out.requeue)
+		assert.Contains(t, out.message, "no p95 latency data")
+		assert.Equal(t, 30, getTrigger(t, c).Spec.FunctionReference.FunctionWeights["new"])
+	})
+
+	t.Run("templates are rendered and bounded", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			value    float64
+			min, max string
+			failed   bool
+		}{
+			{name: "within bounds", value: 0.5, min: "0.1", max: "1"},
+			{name: "below min", value: 0.05, min: "0.1", failed: true},
+			{name: "above max", value: 2, max: "1", failed: true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+				tmpl := fv1.CanaryAnalysisTemplate{
+					Name:  "saturation",
+					Query: `avg(saturation{fn="{{function}}",ns="{{namespace}}"}[{{window}}])`,
+				}
+				if tt.min != "" {
+					tmpl.Min = new(resource.MustParse(tt.min))
+				}
+				if tt.max != "" {
+					tmpl.Max = new(resource.MustParse(tt.max))
+				}
+				cc.Spec.Analysis = &fv1.CanaryAnalysis{Templates: []fv1.CanaryAnalysisTemplate{tmpl}}
+				prom := &fakeFailureClient{pct: 0, scalar: tt.value}
+				mgr, _, c := newTestEnv(prom, trigger, cc)
+
+				out, err := mgr.step(t.Context(), cc)
+				require.NoError(t, err)
+				assert.Equal(t, []string{`avg(saturation{fn="new",ns="default"}[1m])`}, prom.queries)
+				if tt.failed {
+					assert.Equal(t, fv1.CanaryConfigStatusFailed, out.terminalStatus)
+					assert.Equal(t, 0, getTrigger(t, c).Spec.FunctionReference.FunctionWeights["new"])
+				} else {
+					assert.Equal(t, stepOutcome{requeue: true}, out)
+					assert.Equal(t, 60, getTrigger(t, c).Spec.FunctionReference.FunctionWeights["new"])
+				}
+			})
+		}
+	})
+
+	t.Run("alias mode renders the function and version", func(t *testing.T) {
+		trigger, cc, alias, oldVer, newVer := aliasCanaryFixtures(30, 10)
+		w := 70
+		alias.Spec.Weight = &w
+		alias.Spec.SecondaryVersion = "orders-v2"
+		cc.Spec.Analysis = &fv1.CanaryAnalysis{Templates: []fv1.CanaryAnalysisTemplate{{
+			Name: "errors", Query: `x{fn="{{function}}",v="{{version}}"}`, Max: new(resource.MustParse("1")),
+		}}}
+		prom := &fakeFailureClient{pct: 0, scalar: 0}
+		mgr, _, _ := newTestEnv(prom, trigger, cc, alias, oldVer, newVer)
+
+		_, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.Equal(t, []string{`x{fn="orders",v="orders-v2"}`}, prom.queries)
+	})
+}
+
+func TestWebhookGates(t *testing.T) {
+	gate := func(t *testing.T, status int, seen *[]gateRequest) string {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req gateRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err == nil && seen != nil {
+				*seen = append(*seen, req)
+			}
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte("error budget exhausted"))
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+
+	t.Run("approval lets the step proceed", func(t *testing.T) {
+		var seen []gateRequest
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Webhooks = []fv1.CanaryWebhook{{Name: "slo", URL: gate(t, http.StatusOK, &seen)}}
+		mgr, _, c := newTestEnv(&fakeFailureClient{pct: 0}, trigger, cc)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.Equal(t, stepOutcome{requeue: true}, out)
+		assert.Equal(t, 60, getTrigger(t, c).Spec.FunctionReference.FunctionWeights["new"])
+		require.Len(t, seen, 1)
+		assert.Equal(t, gateRequest{
+			Name: "cc", Namespace: "default", Trigger: "trig",
+			OldTarget: "old", NewTarget: "new", Weight: 30, NextWeight: 60,
+		}, seen[0])
+	})
+
+	t.Run("the first increment is gated too", func(t *testing.T) {
+		var seen []gateRequest
+		trigger, cc := canaryFixtures(map[string]int{"new": 0, "old": 100}, 30)
+		cc.Spec.Webhooks = []fv1.CanaryWebhook{{Name: "slo", URL: gate(t, http.StatusOK, &seen)}}
+		prom := &fakeFailureClient{}
+		mgr, _, _ := newTestEnv(prom, trigger, cc)
+
+		_, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		require.Len(t, seen, 1)
+		assert.Equal(t, 0, seen[0].Weight)
+		assert.Equal(t, 30, seen[0].NextWeight)
+		assert.Empty(t, prom.calls, "nothing to analyze before the first increment")
+	})
+
+	t.Run("refusal holds by default", func(t *testing.T) {
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Webhooks = []fv1.CanaryWebhook{{Name: "slo", URL: gate(t, http.StatusServiceUnavailable, nil)}}
+		mgr, _, c := newTestEnv(&fakeFailureClient{pct: 0}, trigger, cc)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.True(t, out.requeue)
+		assert.Contains(t, out.message, `webhook "slo" refused`)
+		assert.Contains(t, out.message, "error budget exhausted")
+		assert.Equal(t, 30, getTrigger(t, c).Spec.FunctionReference.FunctionWeights["new"])
+	})
+
+	t.Run("refusal with Rollback policy fails the rollout", func(t *testing.T) {
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Webhooks = []fv1.CanaryWebhook{{
+			Name: "slo", URL: gate(t, http.StatusForbidden, nil), FailurePolicy: fv1.CanaryWebhookRollback,
+		}}
+		mgr, _, c := newTestEnv(&fakeFailureClient{pct: 0}, trigger, cc)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.Equal(t, fv1.CanaryConfigStatusFailed, out.terminalStatus)
+		assert.Contains(t, out.message, "traffic rolled back")
+		assert.Equal(t, 0, getTrigger(t, c).Spec.FunctionReference.FunctionWeights["new"])
+	})
+
+	t.Run("failed analysis never asks the gates", func(t *testing.T) {
+		var seen []gateRequest
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Webhooks = []fv1.CanaryWebhook{{Name: "slo", URL: gate(t, http.StatusOK, &seen)}}
+		mgr, _, _ := newTestEnv(&fakeFailureClient{pct: 50}, trigger, cc)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.Equal(t, fv1.CanaryConfigStatusFailed, out.terminalStatus)
+		assert.Empty(t, seen)
+	})
+}
+
+func TestManualControls(t *testing.T) {
+	t.Run("promote shifts all traffic in pair mode", func(t *testing.T) {
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Promote = true
+		prom := &fakeFailureClient{pct: 50}
+		mgr, _, c := newTestEnv(prom, trigger, cc)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.Equal(t, fv1.CanaryConfigStatusSucceeded, out.terminalStatus)
+		assert.Empty(t, prom.calls, "promotion skips analysis")
+
+		got := getTrigger(t, c)
+		assert.Equal(t, 100, got.Spec.FunctionReference.FunctionWeights["new"])
+		assert.Equal(t, 0, got.Spec.FunctionReference.FunctionWeights["old"])
+	})
+
+	t.Run("promote flips the alias to the new version", func(t *testing.T) {
+		trigger, cc, alias, oldVer, newVer := aliasCanaryFixtures(30, 10)
+		cc.Spec.Promote = true
+		mgr, _, c := newTestEnv(&fakeFailureClient{}, trigger, cc, alias, oldVer, newVer)
+
+		out, err := mgr.step(t.Context(), cc)
+		require.NoError(t, err)
+		assert.Equal(t, fv1.CanaryConfigStatusSucceeded, out.terminalStatus)
+
+		got := getAliasByName(t, c, "prod")
+		assert.Equal(t, "orders-v2", got.Spec.Version)
+		assert.Nil(t, got.Spec.Weight)
+		assert.Empty(t, got.Spec.SecondaryVersion)
+	})
+
+	t.Run("paused config holds its weights and is not requeued", func(t *testing.T) {
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Paused = true
+		_, r, c := newTestEnv(&fakeFailureClient{pct: 0}, trigger, cc)
+
+		res, err := reconcileCC(t, r)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.Equal(t, 30, getTrigger(t, c).Spec.FunctionReference.FunctionWeights["new"])
+
+		cond := meta.FindStatusCondition(getConfig(t, c).Status.Conditions, fv1.CanaryConfigConditionProgressing)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, fv1.CanaryConfigReasonPaused, cond.Reason)
+	})
+
+	t.Run("held step reports why on the Progressing condition", func(t *testing.T) {
+		trigger, cc := canaryFixtures(map[string]int{"new": 30, "old": 70}, 30)
+		cc.Spec.Analysis = &fv1.CanaryAnalysis{MinRequests: 100}
+		_, r, c := newTestEnv(&fakeFailureClient{pct: 0, count: 1}, trigger, cc)
+
+		_, err := reconcileCC(t, r)
+		require.NoError(t, err)
+
+		cond := meta.FindStatusCondition(getConfig(t, c).Status.Conditions, fv1.CanaryConfigConditionProgressing)
+		require.NotNil(t, cond)
+		assert.Equal(t, fv1.CanaryConfigReasonHeld, cond.Reason)
+		assert.Contains(t, cond.Message, "waiting for 100 requests")
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"net/http"
 	"net/url"
 	"os"
 	"slices"
@@ -46,11 +47,20 @@ type failureQuery struct {
 	Window    string
 }
 
-// failurePercentageGetter computes the error rate of the canary's new
-// function/version over a time window. *PrometheusApiClient satisfies it in
+// failurePercentageGetter is the canary's view of Prometheus: the error rate
+// of the new function/version over a time window, and the other signals
+// CanaryConfigSpec.Analysis judges it by. *PrometheusApiClient satisfies it in
 // production; unit tests inject a deterministic fake.
 type failurePercentageGetter interface {
 	GetFunctionFailurePercentage(ctx context.Context, q failureQuery) (float64, error)
+	// GetFunctionRequestCount is the number of requests in q's window.
+	GetFunctionRequestCount(ctx context.Context, q failureQuery) (float64, error)
+	// GetFunctionLatencyPercentile is the quantile (0..1) of request latency
+	// in q's window, in seconds; NaN when there is no data.
+	GetFunctionLatencyPercentile(ctx context.Context, q failureQuery, quantile float64) (float64, error)
+	// QueryScalar runs an analysis template's rendered query, which must
+	// return at most one value; NaN when it returns none.
+	QueryScalar(ctx context.Context, query string) (float64, error)
 }
 
 // setCanaryConfigConditions mirrors the bare Status string onto the standard
@@ -72,6 +82,11 @@ func setCanaryConfigConditions(s *fv1.CanaryConfigStatus, status string, gen int
 	case fv1.CanaryConfigStatusPending:
 		progStatus, readyStatus = metav1.ConditionTrue, metav1.ConditionFalse
 		reason, message = fv1.CanaryConfigReasonInProgress, "canary rollout in progress"
+		if messageOverride != "" {
+			// A pending rollout with a message is one an analysis check or
+			// webhook gate held at its weights this interval.
+			reason = fv1.CanaryConfigReasonHeld
+		}
 	case fv1.CanaryConfigStatusSucceeded:
 		progStatus, readyStatus = metav1.ConditionFalse, metav1.ConditionTrue
 		reason, message = fv1.CanaryConfigReasonSucceeded, "canary rollout succeeded"
@@ -107,17 +122,43 @@ func setCanaryConfigConditions(s *fv1.CanaryConfigStatus, status string, gen int
 	return changed
 }
 
+// setCanaryConfigPaused marks a pending rollout that Spec.Paused holds: not
+// progressing, not ready. It reports whether any condition changed.
+func setCanaryConfigPaused(s *fv1.CanaryConfigStatus, gen int64) bool {
+	const message = "canary rollout paused at its current weights"
+	changed := conditions.Set(&s.Conditions, metav1.Condition{
+		Type:               fv1.CanaryConfigConditionProgressing,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: gen,
+		Reason:             fv1.CanaryConfigReasonPaused,
+		Message:            message,
+	})
+	if conditions.Set(&s.Conditions, metav1.Condition{
+		Type:               fv1.CanaryConfigConditionReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: gen,
+		Reason:             fv1.CanaryConfigReasonPaused,
+		Message:            message,
+	}) {
+		changed = true
+	}
+	return changed
+}
+
 // canaryConfigMgr holds the side-effecting dependencies a single rollout step
 // needs: the cache-backed client used to read the target HTTPTrigger and shift
-// its function weights, and the Prometheus client used to read the new
-// function's error rate. It is otherwise stateless — the controller-runtime
-// workqueue plus RequeueAfter replace the per-config time.Ticker and
-// cancel-func map the previous informer-based manager maintained.
+// its function weights, the Prometheus client used to read the new function's
+// error rate and analysis metrics, and the HTTP client that asks webhook gates
+// (nil uses http.DefaultClient). It is otherwise stateless — the
+// controller-runtime workqueue plus RequeueAfter replace the per-config
+// time.Ticker and cancel-func map the previous informer-based manager
+// maintained.
 type canaryConfigMgr struct {
 	logger     logr.Logger
 	client     client.Client
 	apiReader  client.Reader
 	promClient failurePercentageGetter
+	gateClient *http.Client
 }
 
 func MakeCanaryConfigMgr(logger logr.Logger, c client.Client, apiReader client.Reader, prometheusSvc string) (*canaryConfigMgr, error) {
@@ -158,6 +199,7 @@ func MakeCanaryConfigMgr(logger logr.Logger, c client.Client, apiReader client.R
 		client:     c,
 		apiReader:  apiReader,
 		promClient: promClient,
+		gateClient: &http.Client{},
 	}, nil
 }
 
@@ -169,10 +211,11 @@ type stepOutcome struct {
 	// requeue asks the reconciler to schedule another step after one
 	// WeightIncrementDuration. Mutually exclusive with terminalStatus.
 	requeue bool
-	// message, when non-empty, overrides the terminalStatus's default
-	// condition message (see setCanaryConfigConditions). Only ever set
-	// alongside terminalStatus == Failed, for the alias-mode reconcile-start
-	// validation refusals that never touched the alias.
+	// message, when non-empty, overrides the status's default condition
+	// message (see setCanaryConfigConditions). Set alongside terminalStatus
+	// == Failed for the alias-mode reconcile-start validation refusals that
+	// never touched the alias and for failed analysis checks, and alongside
+	// requeue when an analysis check or webhook gate held the rollout.
 	message string
 }
 
@@ -219,15 +262,37 @@ func (m *canaryConfigMgr) step(ctx context.Context, cfg *fv1.CanaryConfig) (step
 		return stepOutcome{requeue: true}, nil
 	}
 
-	// Only evaluate the failure rate once the new function is actually taking
-	// traffic; at weight 0 there is nothing to observe.
-	if trigger.Spec.FunctionReference.FunctionWeights[cfg.Spec.NewFunction] != 0 {
+	if cfg.Spec.Promote {
+		log.Info("canary promoted manually; new function now receives all traffic")
+		weights := trigger.Spec.FunctionReference.FunctionWeights
+		weights[cfg.Spec.NewFunction] = 100
+		weights[cfg.Spec.OldFunction] = 0
+		if err := m.updateHttpTriggerWithRetries(ctx, trigger.Namespace, trigger.Name, weights); err != nil {
+			return stepOutcome{}, err
+		}
+		return stepOutcome{terminalStatus: fv1.CanaryConfigStatusSucceeded}, nil
+	}
+
+	// Never leave traffic on a failing function: a rollback error is
+	// surfaced so the reconciler retries with backoff instead of finishing.
+	rollback := func() error {
+		if err := m.rollbackWeights(ctx, cfg, trigger); err != nil {
+			log.Error(err, "error rolling back canary config")
+			return err
+		}
+		return nil
+	}
+
+	// Only evaluate the new function once it is actually taking traffic; at
+	// weight 0 there is nothing to observe.
+	weight := trigger.Spec.FunctionReference.FunctionWeights[cfg.Spec.NewFunction]
+	if weight != 0 {
 		urlPath, methods := triggerRouteInfo(trigger)
 
 		// Version is empty here: function-pair mode's NewFunction is
 		// already a function name, so no function_version label is added —
 		// the query is byte-identical to the pre-shim query.
-		failurePercent, err := m.promClient.GetFunctionFailurePercentage(ctx, failureQuery{
+		v := m.analyze(ctx, cfg, failureQuery{
 			Path:      urlPath,
 			Methods:   methods,
 			Function:  cfg.Spec.NewFunction,
@@ -235,28 +300,13 @@ func (m *canaryConfigMgr) step(ctx context.Context, cfg *fv1.CanaryConfig) (step
 			Namespace: cfg.Namespace,
 			Window:    cfg.Spec.WeightIncrementDuration,
 		})
-		if err != nil {
-			// Transient query error — check again next window rather than aborting.
-			log.Error(err, "error calculating failure percentage; will retry")
-			return stepOutcome{requeue: true}, nil
-		}
-
-		if failurePercent == -1 {
-			// No requests reached the url in this window — nothing to evaluate.
-			log.Info("no requests observed for url in window", "url", urlPath)
-			return stepOutcome{requeue: true}, nil
+		if v.result != verdictPass {
+			return v.outcome(rollback)
 		}
-
-		if int(failurePercent) > cfg.Spec.FailureThreshold {
-			log.Info("failure percentage crossed threshold; rolling back",
-				"failure_percent", failurePercent, "threshold", cfg.Spec.FailureThreshold)
-			if err := m.rollbackWeights(ctx, cfg, trigger); err != nil {
-				// Never leave traffic on a failing function: surface the error so
-				// the reconciler retries with backoff instead of finishing.
-				log.Error(err, "error rolling back canary config")
-				return stepOutcome{}, err
-			}
-			return stepOutcome{terminalStatus: fv1.CanaryConfigStatusFailed}, nil
+	}
+	if len(cfg.Spec.Webhooks) > 0 {
+		if v := m.askGates(ctx, cfg, weight, min(100, weight+cfg.Spec.WeightIncrement)); v.result != verdictPass {
+			return v.outcome(rollback)
 		}
 	}
 
@@ -279,6 +329,9 @@ func triggerRouteInfo(trigger *fv1.HTTPTrigger) (path string, methods []string)
 	if trigger.Spec.Prefix != nil && *trigger.Spec.Prefix != "" {
 		path = *trigger.Spec.Prefix
 	}
+	if trigger.Spec.GRPC != nil {
+		path = trigger.Spec.GRPC.PathPrefix()
+	}
 	methods = trigger.Spec.Methods
 	if len(trigger.Spec.Method) > 0 && !slices.Contains(trigger.Spec.Methods, trigger.Spec.Method) {
 		methods = append(methods, trigger.Spec.Method)
@@ -324,13 +377,28 @@ func (m *canaryConfigMgr) stepAlias(ctx context.Context, cfg *fv1.CanaryConfig,
 		return stepOutcome{terminalStatus: fv1.CanaryConfigStatusFailed, message: failReason}, nil
 	}
 
+	if cfg.Spec.Promote {
+		log.Info("alias canary promoted manually; promoting secondary to primary", "version", cfg.Spec.NewFunction)
+		if err := m.updateFunctionAliasWithRetries(ctx, alias.Namespace, alias.Name, cfg.Spec.NewFunction, nil, ""); err != nil {
+			return stepOutcome{}, err
+		}
+		return stepOutcome{terminalStatus: fv1.CanaryConfigStatusSucceeded}, nil
+	}
+
 	primaryWeight := 100
 	if alias.Spec.Weight != nil {
 		primaryWeight = *alias.Spec.Weight
 	}
+	rollback := func() error {
+		if err := m.rollbackAlias(ctx, cfg, alias); err != nil {
+			log.Error(err, "error rolling back alias canary")
+			return err
+		}
+		return nil
+	}
 
-	// Only evaluate the failure rate once the secondary is actually taking
-	// traffic; at primary weight 100 there is nothing to observe.
+	// Only evaluate the secondary once it is actually taking traffic; at
+	// primary weight 100 there is nothing to observe.
 	if primaryWeight < 100 {
 		urlPath, methods := triggerRouteInfo(trigger)
 
@@ -341,7 +409,7 @@ func (m *canaryConfigMgr) stepAlias(ctx context.Context, cfg *fv1.CanaryConfig,
 		// (RFC L181-182). Passing NewFunction as Function would match zero
 		// series, wedging the rollout in a permanent requeue (failurePercent
 		// == -1 forever).
-		failurePercent, err := m.promClient.GetFunctionFailurePercentage(ctx, failureQuery{
+		v := m.analyze(ctx, cfg, failureQuery{
 			Path:      urlPath,
 			Methods:   methods,
 			Function:  alias.Spec.FunctionName,
@@ -349,24 +417,14 @@ func (m *canaryConfigMgr) stepAlias(ctx context.Context, cfg *fv1.CanaryConfig,
 			Namespace: cfg.Namespace,
 			Window:    cfg.Spec.WeightIncrementDuration,
 		})
-		if err != nil {
-			log.Error(err, "error calculating failure percentage; will retry")
-			return stepOutcome{requeue: true}, nil
+		if v.result != verdictPass {
+			return v.outcome(rollback)
 		}
-
-		if failurePercent == -1 {
-			log.Info("no requests observed for url in window", "url", urlPath)
-			return stepOutcome{requeue: true}, nil
-		}
-
-		if int(failurePercent) > cfg.Spec.FailureThreshold {
-			log.Info("failure percentage crossed threshold; rolling back alias",
-				"failure_percent", failurePercent, "threshold", cfg.Spec.FailureThreshold)
-			if err := m.rollbackAlias(ctx, cfg, alias); err != nil {
-				log.Error(err, "error rolling back alias canary")
-				return stepOutcome{}, err
-			}
-			return stepOutcome{terminalStatus: fv1.CanaryConfigStatusFailed}, nil
+	}
+	if len(cfg.Spec.Webhooks) > 0 {
+		share := 100 - primaryWeight
+		if v := m.askGates(ctx, cfg, share, min(100, share+cfg.Spec.WeightIncrement)); v.result != verdictPass {
+			return v.outcome(rollback)
 		}
 	}
 
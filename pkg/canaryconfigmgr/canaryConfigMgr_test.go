@@ -82,13 +82,33 @@ func TestMakePrometheusClient(t *testing.T) {
 }
 
 // fakeFailureClient is a deterministic failurePercentageGetter for tests. It
-// also records every call's (funcName, funcVersion, funcNs) so alias-mode
-// tests can assert the shim passes alias.Spec.FunctionName (not the version
-// name) as funcName, per RFC-0025 plan-review blocker #2.
+// also records every failure-percentage call's (funcName, funcVersion,
+// funcNs) so alias-mode tests can assert the shim passes
+// alias.Spec.FunctionName (not the version name) as funcName, per RFC-0025
+// plan-review blocker #2. The analysis signals answer count, latency and
+// scalar; queries records each rendered template.
 type fakeFailureClient struct {
 	pct   float64
 	err   error
 	calls []fakeFailureCall
+
+	count   float64
+	latency float64
+	scalar  float64
+	queries []string
+}
+
+func (f *fakeFailureClient) GetFunctionRequestCount(context.Context, failureQuery) (float64, error) {
+	return f.count, nil
+}
+
+func (f *fakeFailureClient) GetFunctionLatencyPercentile(context.Context, failureQuery, float64) (float64, error) {
+	return f.latency, nil
+}
+
+func (f *fakeFailureClient) QueryScalar(_ context.Context, query string) (float64, error) {
+	f.queries = append(f.queries, query)
+	return f.scalar, nil
 }
 
 type fakeFailureCall struct {
@@ -7,6 +7,9 @@ package canaryconfigmgr
 import (
 	"context"
 	"fmt"
+	"math"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -74,6 +77,71 @@ func (promApiClient *PrometheusApiClient) GetFunctionFailurePercentage(ctx conte
 	return failurePercentForFunc, nil
 }
 
+// GetFunctionRequestCount is the number of requests to q's function (and
+// version) on q's route over q's window, summed over its methods.
+func (promApiClient *PrometheusApiClient) GetFunctionRequestCount(ctx context.Context, q failureQuery) (float64, error) {
+	var reqs float64
+	for _, method := range q.Methods {
+		mreqs, err := promApiClient.GetRequestsToFuncInWindow(ctx, q.Path, method, q.Function, q.Version, q.Namespace, q.Window)
+		if err != nil {
+			return 0, err
+		}
+		reqs += mreqs
+	}
+	return reqs, nil
+}
+
+// GetFunctionLatencyPercentile derives a latency quantile from the router's
+// fission_function_overhead_seconds histogram, which despite its name times
+// the whole call up to the function's response headers. NaN when the window
+// has no observations.
+func (promApiClient *PrometheusApiClient) GetFunctionLatencyPercentile(ctx context.Context, q failureQuery, quantile float64) (float64, error) {
+	labels := fmt.Sprintf("function_name=\"%s\",function_namespace=\"%s\",path=\"%s\"", q.Function, q.Namespace, q.Path)
+	if q.Version != "" {
+		labels += fmt.Sprintf(",function_version=\"%s\"", q.Version)
+	}
+	if len(q.Methods) > 0 {
+		methods := make([]string, 0, len(q.Methods))
+		for _, m := range q.Methods {
+			methods = append(methods, regexp.QuoteMeta(m))
+		}
+		labels += fmt.Sprintf(",method=~\"%s\"", strings.Join(methods, "|"))
+	}
+	query := fmt.Sprintf("histogram_quantile(%g, sum by (le) (rate(fission_function_overhead_seconds_bucket{%s}[%v])))", quantile, labels, q.Window)
+	return promApiClient.QueryScalar(ctx, query)
+}
+
+// QueryScalar runs query and returns its single value: a scalar, or a vector
+// of at most one sample. NaN when the query returns nothing (or NaN, as
+// histogram_quantile does over an empty window); an error when it returns
+// more than one series, which an analysis template cannot be judged by.
+func (promApiClient *PrometheusApiClient) QueryScalar(ctx context.Context, query string) (float64, error) {
+	promApiClient.logger.V(1).Info("executing prometheus query", "query", query)
+
+	val, warn, err := promApiClient.client.Query(ctx, query, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("error querying prometheus: %w", err)
+	}
+	if warn != nil {
+		promApiClient.logger.Info("receive prometheus client query warning", "msg", warn)
+	}
+
+	switch v := val.(type) {
+	case *model.Scalar:
+		return float64(v.Value), nil
+	case model.Vector:
+		switch len(v) {
+		case 0:
+			return math.NaN(), nil
+		case 1:
+			return float64(v[0].Value), nil
+		}
+		return 0, fmt.Errorf("query %s returned %d series, want one", query, len(v))
+	default:
+		return 0, fmt.Errorf("query %s returned a %s, want a scalar or a single-sample vector", query, val.Type())
+	}
+}
+
 // getFunctionQueryLabels builds the PromQL label-matcher body shared by every
 // query this client issues. functionVersion is only appended when non-empty
 // (pair mode omits it, keeping the query byte-identical to the pre-alias-mode
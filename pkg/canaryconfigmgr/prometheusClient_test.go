@@ -6,6 +6,7 @@ package canaryconfigmgr
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -87,3 +88,51 @@ func TestGetTotalFailedRequestsToFuncInWindow_QueryStrings(t *testing.T) {
 		assert.Equal(t, `fission_function_errors_total{function_name="orders",function_namespace="default",path="/orders",method="GET",function_version="orders-v2"} offset 1m`, spy.queries[1])
 	})
 }
+
+func TestGetFunctionLatencyPercentile_QueryString(t *testing.T) {
+	spy, c := newSpyClient()
+
+	_, err := c.GetFunctionLatencyPercentile(t.Context(), failureQuery{
+		Path: "/orders", Methods: []string{"GET", "POST"},
+		Function: "orders", Version: "orders-v2", Namespace: "default", Window: "1m",
+	}, 0.99)
+	require.NoError(t, err)
+
+	require.Len(t, spy.queries, 1)
+	assert.Equal(t, `histogram_quantile(0.99, sum by (le) (rate(fission_function_overhead_seconds_bucket{function_name="orders",function_namespace="default",path="/orders",function_version="orders-v2",method=~"GET|POST"}[1m])))`, spy.queries[0])
+}
+
+func TestQueryScalar(t *testing.T) {
+	sample := func(v float64) *model.Sample { return &model.Sample{Value: model.SampleValue(v)} }
+
+	t.Run("scalar", func(t *testing.T) {
+		spy, c := newSpyClient()
+		spy.value = &model.Scalar{Value: 0.25}
+		v, err := c.QueryScalar(t.Context(), "q")
+		require.NoError(t, err)
+		assert.InDelta(t, 0.25, v, 1e-9)
+	})
+
+	t.Run("single sample vector", func(t *testing.T) {
+		spy, c := newSpyClient()
+		spy.value = model.Vector{sample(3)}
+		v, err := c.QueryScalar(t.Context(), "q")
+		require.NoError(t, err)
+		assert.InDelta(t, 3, v, 1e-9)
+	})
+
+	t.Run("empty vector is no data", func(t *testing.T) {
+		spy, c := newSpyClient()
+		spy.value = model.Vector{}
+		v, err := c.QueryScalar(t.Context(), "q")
+		require.NoError(t, err)
+		assert.True(t, math.IsNaN(v))
+	})
+
+	t.Run("several series cannot be judged", func(t *testing.T) {
+		spy, c := newSpyClient()
+		spy.value = model.Vector{sample(1), sample(2)}
+		_, err := c.QueryScalar(t.Context(), "q")
+		assert.Error(t, err)
+	})
+}
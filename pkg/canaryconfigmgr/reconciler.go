@@ -78,6 +78,28 @@ func (r *CanaryConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			"name", cfg.Name, "namespace", cfg.Namespace, "weight_increment", cfg.Spec.WeightIncrement)
 		return ctrl.Result{}, nil
 	}
+	if err := cfg.Spec.ValidateGates(); err != nil {
+		// The schema checks each analysis and webhook field on its own; what
+		// it cannot (unknown placeholders, duplicates, inverted bounds) is
+		// an unworkable spec like the ones above.
+		r.logger.Error(err, "invalid canary analysis or webhooks; not scheduling canary",
+			"name", cfg.Name, "namespace", cfg.Namespace)
+		return ctrl.Result{}, nil
+	}
+
+	// Paused: hold the weights and stop scheduling. Resuming (or promoting)
+	// is a spec change, which the GenerationChangedPredicate delivers as a
+	// fresh reconcile.
+	if cfg.Spec.Paused && !cfg.Spec.Promote {
+		original := cfg.DeepCopy()
+		cfg.Status.Status = fv1.CanaryConfigStatusPending
+		if setCanaryConfigPaused(&cfg.Status, cfg.Generation) || original.Status.Status != cfg.Status.Status {
+			if err := r.client.Status().Patch(ctx, cfg, client.MergeFrom(original)); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
 
 	out, err := r.mgr.step(ctx, cfg)
 	if err != nil {
@@ -97,10 +119,11 @@ func (r *CanaryConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 	if out.requeue {
-		// Keep Progressing/Ready asserted (the write is skipped when unchanged).
-		// The RequeueAfter below reschedules regardless, so a failed status
-		// write here is best-effort and not fatal.
-		if err := r.writeStatus(ctx, cfg, fv1.CanaryConfigStatusPending, ""); err != nil {
+		// Keep Progressing/Ready asserted (the write is skipped when unchanged),
+		// with the reason an analysis check or gate held the rollout, if one
+		// did. The RequeueAfter below reschedules regardless, so a failed
+		// status write here is best-effort and not fatal.
+		if err := r.writeStatus(ctx, cfg, fv1.CanaryConfigStatusPending, out.message); err != nil {
 			r.logger.V(1).Info("canary progressing status update failed",
 				"name", cfg.Name, "namespace", cfg.Namespace, "error", err)
 		}
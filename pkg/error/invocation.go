@@ -34,6 +34,7 @@ const (
 	ReasonConnectionRefused    = "connection_refused"
 	ReasonDialError            = "dial_error"
 	ReasonFunctionError        = "function_error"
+	ReasonRequestTransform     = "request_transform"
 )
 
 // InvocationError attributes a failed function invocation to a Component and a
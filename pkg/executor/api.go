@@ -16,9 +16,11 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
 	hmacauth "github.com/fission/fission/pkg/auth/hmac"
@@ -26,6 +28,7 @@ import (
 	"github.com/fission/fission/pkg/executor/client"
 	"github.com/fission/fission/pkg/executor/executortype"
 	"github.com/fission/fission/pkg/executor/fscache"
+	"github.com/fission/fission/pkg/executor/usage"
 	"github.com/fission/fission/pkg/utils/correlation"
 	"github.com/fission/fission/pkg/utils/httpmux"
 	"github.com/fission/fission/pkg/utils/httpsecurity"
@@ -65,7 +68,7 @@ func (executor *Executor) getServiceForFunctionAPI(w http.ResponseWriter, r *htt
 		if executor.serveFromCache(ctx, w, et, fn, true) {
 			return
 		}
-	} else if t == fv1.ExecutorTypeNewdeploy || t == fv1.ExecutorTypeContainer {
+	} else if t == fv1.ExecutorTypeNewdeploy || t == fv1.ExecutorTypeContainer || t == fv1.ExecutorTypeWasm {
 		if executor.serveFromCache(ctx, w, et, fn, false) {
 			return
 		}
@@ -228,6 +231,160 @@ func (executor *Executor) ensureCapacityHandler(w http.ResponseWriter, r *http.R
 	executor.writeResponse(w, serviceName, fn.Name)
 }
 
+// zeroScaler is the optional executor-type facet scaleFromZeroHandler uses
+// to start every replica a burst of buffered requests needs, ahead of the
+// specialization that waits for the first one.
+type zeroScaler interface {
+	ScaleFromZero(ctx context.Context, fn *fv1.Function, replicas int32) error
+}
+
+// scaleFromZeroHandler serves POST /v2/scaleFromZero: a router buffering
+// requests for a function with ScaleToZero reports how many are queued. The
+// executor raises the function's deployment to the replicas they need (see
+// fv1.ExecutionStrategy.ScaleFromZeroReplicas), then answers like
+// getServiceForFunction once a replica is ready.
+func (executor *Executor) scaleFromZeroHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request", http.StatusInternalServerError)
+		return
+	}
+
+	var req client.ScaleFromZeroRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Function == nil {
+		http.Error(w, "Failed to parse request", http.StatusBadRequest)
+		return
+	}
+	fn := req.Function
+	t := fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType
+	if t != fv1.ExecutorTypeNewdeploy && t != fv1.ExecutorTypeContainer {
+		http.Error(w, fmt.Sprintf("scaleFromZero supports newdeploy and container only, got '%s'", html.EscapeString(string(t))), http.StatusBadRequest)
+		return
+	}
+	et := executor.executorTypes[t]
+	logger := otelUtils.LoggerWithTraceID(ctx, executor.logger)
+
+	if zs, ok := et.(zeroScaler); ok && (!executor.leaderElection || executor.isLeader.Load()) {
+		replicas := fn.Spec.InvokeStrategy.ExecutionStrategy.ScaleFromZeroReplicas(req.Queued)
+		if err := zs.ScaleFromZero(ctx, fn, int32(replicas)); err != nil {
+			// The specialization below still brings up the first replica.
+			logger.Error(err, "error scaling function from zero", "function", fn.Name,
+				"namespace", fn.Namespace, "queued", req.Queued, "replicas", replicas)
+		}
+	}
+	if executor.serveFromCache(ctx, w, et, fn, false) {
+		return
+	}
+	serviceName, err := executor.getServiceForFunction(ctx, fn)
+	if err != nil {
+		code, msg := ferror.GetHTTPError(err)
+		logger.Error(err, "error scaling function from zero", "function", fn.Name,
+			"fission_http_error", msg)
+		http.Error(w, msg, code)
+		return
+	}
+	executor.writeResponse(w, serviceName, fn.Name)
+}
+
+// jobRunner is the optional executor-type facet runJobHandler and
+// jobStatusHandler use: the job executor type runs each invocation of a
+// function as its own Kubernetes Job.
+type jobRunner interface {
+	RunJob(ctx context.Context, fn *fv1.Function, invocationID string, body []byte, contentType string) error
+	JobStatus(ctx context.Context, fn *fv1.Function, invocationID string) (*client.JobStatus, error)
+}
+
+// jobRunnerFor returns the jobRunner of fn's executor type, or writes 400
+// for a function of another type.
+func (executor *Executor) jobRunnerFor(w http.ResponseWriter, fn *fv1.Function) (jobRunner, bool) {
+	t := fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType
+	if t != fv1.ExecutorTypeJob {
+		http.Error(w, fmt.Sprintf("jobs are supported by executor type job only, got '%s'", html.EscapeString(string(t))), http.StatusBadRequest)
+		return nil, false
+	}
+	jr, ok := executor.executorTypes[t].(jobRunner)
+	if !ok {
+		http.Error(w, "executor type job is not available", http.StatusNotImplemented)
+		return nil, false
+	}
+	return jr, true
+}
+
+// runJobHandler serves POST /v2/runJob: the router starts one invocation of
+// a job function. The executor answers once the Job is created.
+func (executor *Executor) runJobHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request", http.StatusInternalServerError)
+		return
+	}
+
+	var req client.RunJobRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Function == nil || req.InvocationID == "" {
+		http.Error(w, "Failed to parse request", http.StatusBadRequest)
+		return
+	}
+	jr, ok := executor.jobRunnerFor(w, req.Function)
+	if !ok {
+		return
+	}
+	if err := jr.RunJob(ctx, req.Function, req.InvocationID, req.Body, req.ContentType); err != nil {
+		code, msg := ferror.GetHTTPError(err)
+		otelUtils.LoggerWithTraceID(ctx, executor.logger).Error(err, "error running job", "function", req.Function.Name,
+			"namespace", req.Function.Namespace, "invocationId", req.InvocationID, "fission_http_error", msg)
+		http.Error(w, msg, code)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// jobStatusHandler serves POST /v2/jobStatus: the router polls the state of
+// a job invocation until it is done.
+func (executor *Executor) jobStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request", http.StatusInternalServerError)
+		return
+	}
+
+	var req client.JobStatusRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Function == nil || req.InvocationID == "" {
+		http.Error(w, "Failed to parse request", http.StatusBadRequest)
+		return
+	}
+	jr, ok := executor.jobRunnerFor(w, req.Function)
+	if !ok {
+		return
+	}
+	status, err := jr.JobStatus(ctx, req.Function, req.InvocationID)
+	if err != nil {
+		code, msg := ferror.GetHTTPError(err)
+		http.Error(w, msg, code)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// concurrencyObserver is the optional executor-type facet tapServices feeds
+// with the in-flight request counts routers report in their taps: the input
+// of poolmgr's auto provisioned concurrency and of the concurrency scaling
+// of newdeploy and container functions. source identifies the reporting
+// router; inflight is its peak over the batch.
+type concurrencyObserver interface {
+	ObserveConcurrency(fnMeta *metav1.ObjectMeta, source string, inflight int)
+}
+
+// functionTapper is the optional executor-type facet tapServices prefers to
+// TapService: the functions of a wasm host share its address, so a tap
+// touches the function it names rather than the address.
+type functionTapper interface {
+	TapFunction(ctx context.Context, fnMeta *metav1.ObjectMeta) error
+}
+
 // find funcSvc and update its atime
 func (executor *Executor) tapServices(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -248,8 +405,18 @@ func (executor *Executor) tapServices(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// A batch carries one tap per service address, each with the router's
+	// function-wide in-flight count, so a function is observed once, at the
+	// max over its addresses.
+	type observation struct {
+		observer concurrencyObserver
+		fnMeta   *metav1.ObjectMeta
+		inflight int
+	}
+	observed := make(map[types.UID]*observation)
+
 	var errs, notFound error
-	for _, req := range tapSvcReqs {
+	for i, req := range tapSvcReqs {
 		svcHost := strings.TrimPrefix(req.ServiceURL, "http://")
 
 		et, exists := executor.executorTypes[req.FnExecutorType]
@@ -259,8 +426,20 @@ func (executor *Executor) tapServices(w http.ResponseWriter, r *http.Request) {
 					req.FnExecutorType))
 			continue
 		}
+		if co, ok := et.(concurrencyObserver); ok && req.FnMetadata.UID != "" {
+			if o, ok := observed[req.FnMetadata.UID]; ok {
+				o.inflight = max(o.inflight, req.Inflight)
+			} else {
+				observed[req.FnMetadata.UID] = &observation{co, &tapSvcReqs[i].FnMetadata, req.Inflight}
+			}
+		}
 
-		if err := et.TapService(ctx, svcHost); err != nil {
+		if ft, ok := et.(functionTapper); ok {
+			err = ft.TapFunction(ctx, &tapSvcReqs[i].FnMetadata)
+		} else {
+			err = et.TapService(ctx, svcHost)
+		}
+		if err != nil {
 			wrapped := fmt.Errorf("error tapping function '%s/%s' with executor '%s' and service url '%s': %w", req.FnMetadata.Namespace, req.FnMetadata.Name, req.FnExecutorType, req.ServiceURL, err)
 			if ferror.IsNotFound(err) {
 				notFound = errors.Join(notFound, wrapped)
@@ -269,6 +448,15 @@ func (executor *Executor) tapServices(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	if len(observed) > 0 {
+		source, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			source = r.RemoteAddr
+		}
+		for _, o := range observed {
+			o.observer.ObserveConcurrency(o.fnMeta, source, o.inflight)
+		}
+	}
 
 	if errs != nil {
 		// Genuine tap failures (unknown executor type, internal errors) must be
@@ -352,6 +540,36 @@ func (executor *Executor) dumpDebugInfo(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 }
 
+// usageHandler serves GET /v2/usage: the usage rollups behind `fission fn
+// usage` and `fission env usage`, summed over the queried hours. 501 means
+// the accounting is off; 503 a replica that cannot answer (see
+// usage.ErrUnavailable).
+func (executor *Executor) usageHandler(w http.ResponseWriter, r *http.Request) {
+	if executor.accountant == nil {
+		http.Error(w, "usage accounting is not enabled", http.StatusNotImplemented)
+		return
+	}
+	q, err := usage.ParseQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := executor.accountant.Query(r.Context(), q)
+	if errors.Is(err, usage.ErrUnavailable) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		executor.logger.Error(err, "error querying usage", "kind", q.Kind, "namespace", q.Namespace, "name", q.Name)
+		http.Error(w, "error querying usage", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		executor.logger.Error(err, "encoding usage response")
+	}
+}
+
 // GetHandler returns an http.Handler.
 func (executor *Executor) GetHandler() http.Handler {
 	// The HMAC verifier wraps the whole mux as the OUTERMOST middleware so 401
@@ -379,9 +597,13 @@ func (executor *Executor) GetHandler() http.Handler {
 	)
 	m.HandleFunc("/v2/getServiceForFunction", executor.getServiceForFunctionAPI).Methods("POST")
 	m.HandleFunc("/v2/ensureCapacity", executor.ensureCapacityHandler).Methods("POST")
+	m.HandleFunc("/v2/scaleFromZero", executor.scaleFromZeroHandler).Methods("POST")
+	m.HandleFunc("/v2/runJob", executor.runJobHandler).Methods("POST")
+	m.HandleFunc("/v2/jobStatus", executor.jobStatusHandler).Methods("POST")
 	m.HandleFunc("/v2/tapServices", executor.tapServices).Methods("POST")
 	m.HandleFunc("/v2/unTapService", executor.unTapService).Methods("POST")
 	m.HandleFunc("/v2/debugInfo", executor.dumpDebugInfo).Methods("GET")
+	m.HandleFunc(usage.Path, executor.usageHandler).Methods("GET")
 	m.HandleFunc("/healthz", executor.healthHandler).Methods("GET")
 	m.HandleFunc("/readyz", executor.readyzHandler).Methods("GET")
 	return m.Handler()
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/executor/client"
+	"github.com/fission/fission/pkg/executor/executortype"
+)
+
+// jobCaller is the facet of the executor client the router type-asserts to
+// (router.JobClient).
+type jobCaller interface {
+	RunJob(ctx context.Context, fn *fv1.Function, invocationID string, body []byte, contentType string) error
+	JobStatus(ctx context.Context, fn *fv1.Function, invocationID string) (*client.JobStatus, error)
+}
+
+// jobRunnerStubExecutorType implements the optional jobRunner facet.
+type jobRunnerStubExecutorType struct {
+	executortype.ExecutorType
+	started map[string]string
+}
+
+func (s *jobRunnerStubExecutorType) RunJob(_ context.Context, _ *fv1.Function, invocationID string, body []byte, _ string) error {
+	s.started[invocationID] = string(body)
+	return nil
+}
+
+func (s *jobRunnerStubExecutorType) JobStatus(_ context.Context, _ *fv1.Function, invocationID string) (*client.JobStatus, error) {
+	if _, ok := s.started[invocationID]; !ok {
+		return nil, ferror.MakeError(ferror.ErrorNotFound, "no such job")
+	}
+	return &client.JobStatus{Phase: client.JobSucceeded, StatusCode: 200, Body: []byte("done")}, nil
+}
+
+func jobFn(name string) *fv1.Function {
+	return &fv1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: "test-uid"},
+		Spec: fv1.FunctionSpec{
+			InvokeStrategy: fv1.InvokeStrategy{
+				ExecutionStrategy: fv1.ExecutionStrategy{ExecutorType: fv1.ExecutorTypeJob},
+			},
+		},
+	}
+}
+
+// TestJobWireContract locks the HTTP contract between the executor client
+// and the runJob/jobStatus handlers.
+func TestJobWireContract(t *testing.T) {
+	logger := logr.Discard()
+	stub := &jobRunnerStubExecutorType{started: map[string]string{}}
+	e := &Executor{
+		logger: logger,
+		executorTypes: map[fv1.ExecutorType]executortype.ExecutorType{
+			fv1.ExecutorTypeJob:     stub,
+			fv1.ExecutorTypePoolmgr: stub,
+		},
+	}
+	srv := httptest.NewServer(e.GetHandler())
+	defer srv.Close()
+	c, ok := client.MakeClient(logger, srv.URL, nil).(jobCaller)
+	require.True(t, ok, "executor client must implement the job facet")
+
+	require.NoError(t, c.RunJob(t.Context(), jobFn("fn"), "inv-1", []byte("input"), "text/plain"))
+	assert.Equal(t, map[string]string{"inv-1": "input"}, stub.started)
+
+	status, err := c.JobStatus(t.Context(), jobFn("fn"), "inv-1")
+	require.NoError(t, err)
+	assert.True(t, status.Done())
+	assert.Equal(t, "done", string(status.Body))
+
+	_, err = c.JobStatus(t.Context(), jobFn("fn"), "inv-2")
+	var fe ferror.Error
+	require.ErrorAs(t, err, &fe)
+	assert.EqualValues(t, ferror.ErrorNotFound, fe.Code)
+
+	// A function of another executor type is refused.
+	err = c.RunJob(t.Context(), poolmgrFn("fn"), "inv-3", nil, "")
+	require.ErrorAs(t, err, &fe)
+	assert.EqualValues(t, ferror.ErrorInvalidArgument, fe.Code)
+	assert.NotContains(t, stub.started, "inv-3")
+}
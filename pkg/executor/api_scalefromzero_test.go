@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/executor/client"
+	"github.com/fission/fission/pkg/executor/dispatch"
+	"github.com/fission/fission/pkg/executor/executortype"
+	"github.com/fission/fission/pkg/executor/fscache"
+)
+
+// zeroScalerCaller is the facet of the executor client the router's
+// activator type-asserts to (router.ScaleFromZeroClient).
+type zeroScalerCaller interface {
+	ScaleFromZero(ctx context.Context, fn *fv1.Function, queued int) (string, error)
+}
+
+// zeroScalerStubExecutorType implements the optional zeroScaler facet plus
+// the cache and specialization methods scaleFromZeroHandler touches.
+type zeroScalerStubExecutorType struct {
+	executortype.ExecutorType
+	fsvc   *fscache.FuncSvc
+	scaled []int32
+}
+
+func (s *zeroScalerStubExecutorType) ScaleFromZero(_ context.Context, _ *fv1.Function, replicas int32) error {
+	s.scaled = append(s.scaled, replicas)
+	return nil
+}
+
+func (s *zeroScalerStubExecutorType) GetFuncSvcFromCache(_ context.Context, _ *fv1.Function) (*fscache.FuncSvc, error) {
+	return nil, ferror.MakeError(ferror.ErrorNotFound, "not cached")
+}
+
+func (s *zeroScalerStubExecutorType) GetFuncSvc(_ context.Context, _ *fv1.Function) (*fscache.FuncSvc, error) {
+	return s.fsvc, nil
+}
+
+func newdeployFn(name string) *fv1.Function {
+	return &fv1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: "test-uid"},
+		Spec: fv1.FunctionSpec{
+			InvokeStrategy: fv1.InvokeStrategy{
+				ExecutionStrategy: fv1.ExecutionStrategy{
+					ExecutorType: fv1.ExecutorTypeNewdeploy,
+					MaxScale:     4,
+					ScaleToZero:  &fv1.ScaleToZeroConfig{RequestsPerReplica: 5},
+				},
+			},
+		},
+	}
+}
+
+// TestScaleFromZeroWireContract locks the HTTP contract between the executor
+// client and scaleFromZeroHandler, as TestEnsureCapacityWireContract does for
+// ensureCapacity.
+func TestScaleFromZeroWireContract(t *testing.T) {
+	logger := logr.Discard()
+	newExecutor := func(stub *zeroScalerStubExecutorType) *Executor {
+		return &Executor{
+			logger: logger,
+			executorTypes: map[fv1.ExecutorType]executortype.ExecutorType{
+				fv1.ExecutorTypeNewdeploy: stub,
+				fv1.ExecutorTypePoolmgr:   stub,
+			},
+			dispatcher: dispatch.New[*fscache.FuncSvc](logger, 0),
+		}
+	}
+
+	t.Run("queued requests size the scale up", func(t *testing.T) {
+		stub := &zeroScalerStubExecutorType{fsvc: &fscache.FuncSvc{
+			Function: &metav1.ObjectMeta{Name: "fn", Namespace: "default"},
+			Address:  "newdeploy-fn.fission-function.svc.cluster.local",
+		}}
+		srv := httptest.NewServer(newExecutor(stub).GetHandler())
+		defer srv.Close()
+		c, ok := client.MakeClient(logger, srv.URL, nil).(zeroScalerCaller)
+		require.True(t, ok, "executor client must implement the scale-from-zero facet")
+
+		addr, err := c.ScaleFromZero(t.Context(), newdeployFn("fn"), 12)
+		require.NoError(t, err)
+		assert.Equal(t, "newdeploy-fn.fission-function.svc.cluster.local", addr)
+		_, err = c.ScaleFromZero(t.Context(), newdeployFn("fn"), 100)
+		require.NoError(t, err)
+		assert.Equal(t, []int32{3, 4}, stub.scaled, "one replica per 5 queued, capped at MaxScale")
+	})
+
+	t.Run("poolmgr function is a 400 without scaling", func(t *testing.T) {
+		stub := &zeroScalerStubExecutorType{}
+		srv := httptest.NewServer(newExecutor(stub).GetHandler())
+		defer srv.Close()
+		c := client.MakeClient(logger, srv.URL, nil).(zeroScalerCaller)
+
+		_, err := c.ScaleFromZero(t.Context(), poolmgrFn("fn"), 3)
+		require.Error(t, err)
+		var fe ferror.Error
+		require.ErrorAs(t, err, &fe)
+		assert.EqualValues(t, ferror.ErrorInvalidArgument, fe.Code)
+		assert.Empty(t, stub.scaled)
+	})
+
+	t.Run("malformed body is a 400", func(t *testing.T) {
+		srv := httptest.NewServer(newExecutor(&zeroScalerStubExecutorType{}).GetHandler())
+		defer srv.Close()
+
+		resp, err := http.Post(srv.URL+"/v2/scaleFromZero", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
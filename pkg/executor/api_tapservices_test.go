@@ -144,3 +144,38 @@ func TestTapServices(t *testing.T) {
 		})
 	}
 }
+
+// observingStubExecutorType adds the concurrencyObserver facet to the tap
+// stub.
+type observingStubExecutorType struct {
+	tapStubExecutorType
+	observed map[string]int
+	sources  []string
+}
+
+func (s *observingStubExecutorType) ObserveConcurrency(fnMeta *metav1.ObjectMeta, source string, inflight int) {
+	s.observed[fnMeta.Name] = inflight
+	s.sources = append(s.sources, source)
+}
+
+func TestTapServicesObservesConcurrency(t *testing.T) {
+	stub := &observingStubExecutorType{observed: map[string]int{}}
+	e := &Executor{
+		logger:        logr.Discard(),
+		executorTypes: map[fv1.ExecutorType]executortype.ExecutorType{fv1.ExecutorTypePoolmgr: stub},
+	}
+
+	a1, a2, b := tapReq(fv1.ExecutorTypePoolmgr), tapReq(fv1.ExecutorTypePoolmgr), tapReq(fv1.ExecutorTypePoolmgr)
+	a1.FnMetadata.UID, a1.Inflight = "uid-a", 3
+	a2.FnMetadata.UID, a2.Inflight, a2.ServiceURL = "uid-a", 7, "http://10.0.0.2:8888"
+	b.FnMetadata.UID, b.FnMetadata.Name = "uid-b", "other"
+	r := tapBody(t, a1, a2, b)
+	r.RemoteAddr = "10.1.2.3:41234"
+
+	rec := httptest.NewRecorder()
+	e.tapServices(rec, r)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]int{"fn": 7, "other": 0}, stub.observed,
+		"one observation per function, at the max over its addresses")
+	assert.Equal(t, []string{"10.1.2.3", "10.1.2.3"}, stub.sources)
+}
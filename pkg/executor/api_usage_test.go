@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/fission/fission/pkg/executor/usage"
+)
+
+func TestUsageHandler(t *testing.T) {
+	// No statestore and not sampling: this replica has nothing to answer with.
+	standby := usage.NewAccountant(logr.Discard(), fake.NewClientset(), nil,
+		usage.Config{Interval: usage.DefaultInterval, Retention: usage.DefaultRetention},
+		func() []string { return nil }, func() []string { return nil })
+
+	tests := []struct {
+		name       string
+		accountant *usage.Accountant
+		query      string
+		want       int
+	}{
+		{"accounting off -> 501", nil, "", http.StatusNotImplemented},
+		{"bad kind -> 400", standby, "?kind=package", http.StatusBadRequest},
+		{"bad range -> 400", standby, "?since=1h&until=2h", http.StatusBadRequest},
+		{"standby without statestore -> 503", standby, "?kind=environment", http.StatusServiceUnavailable},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &Executor{logger: logr.Discard(), accountant: tc.accountant}
+			rec := httptest.NewRecorder()
+			e.usageHandler(rec, httptest.NewRequest(http.MethodGet, usage.Path+tc.query, nil))
+			assert.Equal(t, tc.want, rec.Code)
+		})
+	}
+}
@@ -64,7 +64,7 @@ type (
 	// ClientInterface is the interface for executor client.
 	ClientInterface interface {
 		GetServiceForFunction(ctx context.Context, fn *fv1.Function) (string, error)
-		TapService(fnMeta metav1.ObjectMeta, executorType fv1.ExecutorType, serviceURL url.URL)
+		TapService(fnMeta metav1.ObjectMeta, executorType fv1.ExecutorType, serviceURL url.URL, inflight int)
 		UnTapService(ctx context.Context, fnMeta metav1.ObjectMeta, executorType fv1.ExecutorType, serviceURL *url.URL) error
 		// EnsureCapacity is the RFC-0002 saturation path (POST
 		// /v2/ensureCapacity). A 404 from an executor predating it surfaces
@@ -76,7 +76,9 @@ type (
 	client struct {
 		logger      logr.Logger
 		executorURL string
-		tappedByURL map[string]TapServiceRequest
+		// tapped accumulates taps by service URL and function UID: one
+		// address serves many functions on a shared host (wasm).
+		tapped      map[string]TapServiceRequest
 		requestChan chan TapServiceRequest
 		httpClient  *http.Client
 		// consecutive flushTaps failures; with the RFC-0002 warm path these
@@ -90,6 +92,10 @@ type (
 		FnMetadata     metav1.ObjectMeta
 		FnExecutorType fv1.ExecutorType
 		ServiceURL     string
+		// Inflight is the peak number of requests the router had in flight
+		// to the function over the batch interval. Zero from a router that
+		// predates it, which the executor reads as no report.
+		Inflight int `json:",omitempty"`
 	}
 
 	// EnsureCapacityRequest is the body of POST /v2/ensureCapacity (RFC-0002):
@@ -101,8 +107,61 @@ type (
 		ObservedReadyEndpoints int           `json:"observedReadyEndpoints"`
 		ObservedBusyEndpoints  int           `json:"observedBusyEndpoints"`
 	}
+
+	// ScaleFromZeroRequest is the body of POST /v2/scaleFromZero: a router
+	// buffering requests for a scale-to-zero function reports how many are
+	// queued, and the executor scales the function to the replicas they
+	// need before answering with its address once one is ready.
+	ScaleFromZeroRequest struct {
+		Function *fv1.Function `json:"function"`
+		Queued   int           `json:"queued"`
+	}
+
+	// RunJobRequest is the body of POST /v2/runJob: the router starts one
+	// invocation of a function of executor type job, identified by its
+	// invocation id, with the request body as its input.
+	RunJobRequest struct {
+		Function     *fv1.Function `json:"function"`
+		InvocationID string        `json:"invocationId"`
+		Body         []byte        `json:"body,omitempty"`
+		ContentType  string        `json:"contentType,omitempty"`
+	}
+
+	// JobStatusRequest is the body of POST /v2/jobStatus.
+	JobStatusRequest struct {
+		Function     *fv1.Function `json:"function"`
+		InvocationID string        `json:"invocationId"`
+	}
+
+	// JobStatus is the state of a job invocation. StatusCode, Body and
+	// Truncated are the function's response, and Output the tail of the
+	// runtime's log, once the invocation is done; Message explains a
+	// failure without a response.
+	JobStatus struct {
+		Phase      JobPhase `json:"phase"`
+		StatusCode int      `json:"statusCode,omitempty"`
+		Body       []byte   `json:"body,omitempty"`
+		Truncated  bool     `json:"truncated,omitempty"`
+		Output     string   `json:"output,omitempty"`
+		Message    string   `json:"message,omitempty"`
+	}
+
+	// JobPhase is the phase of a job invocation.
+	JobPhase string
+)
+
+// Phases of a job invocation.
+const (
+	JobRunning   JobPhase = "Running"
+	JobSucceeded JobPhase = "Succeeded"
+	JobFailed    JobPhase = "Failed"
 )
 
+// Done reports whether the invocation has finished.
+func (s *JobStatus) Done() bool {
+	return s.Phase == JobSucceeded || s.Phase == JobFailed
+}
+
 // MakeClient initializes and returns a Client instance.
 //
 // masterSecret enables HMAC-SHA256 request signing per the design at
@@ -129,7 +188,7 @@ func MakeClient(logger logr.Logger, executorURL string, masterSecret []byte) Cli
 	c := &client{
 		logger:      logger.WithName("executor_client"),
 		executorURL: strings.TrimSuffix(executorURL, "/"),
-		tappedByURL: make(map[string]TapServiceRequest),
+		tapped:      make(map[string]TapServiceRequest),
 		requestChan: make(chan TapServiceRequest, 100),
 		httpClient:  hc,
 	}
@@ -213,6 +272,106 @@ func (c *client) EnsureCapacity(ctx context.Context, fn *fv1.Function, observedR
 	return string(svcName), nil
 }
 
+// ScaleFromZero asks the executor to scale a scale-to-zero function for
+// queued buffered requests, and returns its address once a replica is
+// ready. A 404 from an executor predating /v2/scaleFromZero surfaces as an
+// ErrorNotFound ferror so the caller can degrade to GetServiceForFunction.
+func (c *client) ScaleFromZero(ctx context.Context, fn *fv1.Function, queued int) (string, error) {
+	executorURL := c.executorURL + "/v2/scaleFromZero"
+
+	body, err := json.Marshal(ScaleFromZeroRequest{Function: fn, Queued: queued})
+	if err != nil {
+		return "", fmt.Errorf("could not marshal request body for scaling function from zero: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", executorURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("could not create request for scaling function from zero: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	correlation.SetRequestIDHeader(ctx, req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error posting to scaling function from zero: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", ferror.MakeErrorFromHTTP(resp)
+	}
+
+	svcName, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response body from scaling function from zero: %w", err)
+	}
+
+	return string(svcName), nil
+}
+
+// RunJob asks the executor to start a job invocation of fn. Starting the
+// same invocation again is a no-op.
+func (c *client) RunJob(ctx context.Context, fn *fv1.Function, invocationID string, body []byte, contentType string) error {
+	executorURL := c.executorURL + "/v2/runJob"
+
+	reqBody, err := json.Marshal(RunJobRequest{Function: fn, InvocationID: invocationID, Body: body, ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("could not marshal request body for running job: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", executorURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("could not create request for running job: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	correlation.SetRequestIDHeader(ctx, req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error posting to running job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return ferror.MakeErrorFromHTTP(resp)
+	}
+	return nil
+}
+
+// JobStatus returns the state of a job invocation of fn. An invocation whose
+// Job is gone surfaces as an ErrorNotFound ferror.
+func (c *client) JobStatus(ctx context.Context, fn *fv1.Function, invocationID string) (*JobStatus, error) {
+	executorURL := c.executorURL + "/v2/jobStatus"
+
+	body, err := json.Marshal(JobStatusRequest{Function: fn, InvocationID: invocationID})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal request body for getting job status: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", executorURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("could not create request for getting job status: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	correlation.SetRequestIDHeader(ctx, req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error posting to getting job status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, ferror.MakeErrorFromHTTP(resp)
+	}
+
+	var status JobStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("error decoding job status: %w", err)
+	}
+	return &status, nil
+}
+
 // UnTapService sends a request to /v2/unTapService.
 func (c *client) UnTapService(ctx context.Context, fnMeta metav1.ObjectMeta, executorType fv1.ExecutorType, serviceURL *url.URL) error {
 	url := c.executorURL + "/v2/unTapService"
@@ -254,16 +413,20 @@ func (c *client) service() {
 	for {
 		select {
 		case svcReq := <-c.requestChan:
-			c.tappedByURL[svcReq.ServiceURL] = svcReq
+			key := svcReq.ServiceURL + "#" + string(svcReq.FnMetadata.UID)
+			if prev, ok := c.tapped[key]; ok {
+				svcReq.Inflight = max(svcReq.Inflight, prev.Inflight)
+			}
+			c.tapped[key] = svcReq
 		case <-ticker.C:
-			if len(c.tappedByURL) == 0 {
+			if len(c.tapped) == 0 {
 				continue
 			}
 
-			urls := c.tappedByURL
-			c.tappedByURL = make(map[string]TapServiceRequest)
+			taps := c.tapped
+			c.tapped = make(map[string]TapServiceRequest)
 
-			go c.flushTaps(urls)
+			go c.flushTaps(taps)
 		}
 	}
 }
@@ -309,19 +472,23 @@ func (c *client) flushTaps(urls map[string]TapServiceRequest) {
 	}
 }
 
-// TapService sends a TapServiceRequest over the request channel.
-func (c *client) TapService(fnMeta metav1.ObjectMeta, executorType fv1.ExecutorType, serviceURL url.URL) {
+// TapService sends a TapServiceRequest over the request channel. inflight
+// is the router's current in-flight request count for the function; the
+// batch keeps the peak.
+func (c *client) TapService(fnMeta metav1.ObjectMeta, executorType fv1.ExecutorType, serviceURL url.URL, inflight int) {
 	c.requestChan <- TapServiceRequest{
 		FnMetadata: metav1.ObjectMeta{
 			Name:            fnMeta.Name,
 			Namespace:       fnMeta.Namespace,
 			ResourceVersion: fnMeta.ResourceVersion,
 			UID:             fnMeta.UID,
+			Generation:      fnMeta.Generation,
 		},
 		FnExecutorType: executorType,
 		// service url is for executor to know which
 		// pod/service is currently used to serve user function.
 		ServiceURL: serviceURL.String(),
+		Inflight:   inflight,
 	}
 }
 
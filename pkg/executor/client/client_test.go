@@ -51,7 +51,7 @@ func newTestClient(sink logr.LogSink, url string) *client {
 	return &client{
 		logger:      logr.New(sink),
 		executorURL: url,
-		tappedByURL: make(map[string]TapServiceRequest),
+		tapped:      make(map[string]TapServiceRequest),
 		requestChan: make(chan TapServiceRequest, 100),
 		httpClient:  &http.Client{},
 	}
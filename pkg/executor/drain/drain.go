@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package drain coordinates function pod deletion with the router replicas.
+// Before the executor deletes an idle or outdated pod it asks every replica
+// to stop admitting requests to the pod and polls until all of them report
+// nothing in flight on it, so deletion waits exactly as long as it has to
+// instead of sleeping through the pod's whole termination grace period.
+package drain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
+	"github.com/fission/fission/pkg/svcinfo"
+)
+
+// Path is the router internal-listener endpoint that marks a pod draining and
+// reports the replica's in-flight count on it.
+const Path = "/v1/endpoints/drain"
+
+const (
+	// pollInterval spaces the rounds of drain requests to the replicas.
+	pollInterval = 250 * time.Millisecond
+	// requestTimeout bounds one replica's answer within a round.
+	requestTimeout = 2 * time.Second
+)
+
+type (
+	// Request names the pod to drain within its function's warm pool.
+	// Version is the pod's fission.io/function-version label, "" for the
+	// unversioned pool. FunctionUID lets a router without an endpoint index
+	// fall back to the function's in-flight count.
+	Request struct {
+		Namespace   string    `json:"namespace"`
+		Function    string    `json:"function"`
+		FunctionUID types.UID `json:"functionUID,omitempty"`
+		Version     string    `json:"version,omitempty"`
+		PodUID      types.UID `json:"podUID"`
+	}
+
+	// Response carries one replica's in-flight request count on the pod.
+	Response struct {
+		InFlight int64 `json:"inflight"`
+	}
+
+	// Coordinator drains pods across every router replica, found through the
+	// router-peers headless Service.
+	Coordinator struct {
+		logger     logr.Logger
+		httpClient *http.Client
+		host       string
+		port       int
+		// lookup resolves host to the replica addresses; net.DefaultResolver
+		// unless faked in tests.
+		lookup       func(ctx context.Context, host string) ([]string, error)
+		pollInterval time.Duration
+	}
+)
+
+// NewCoordinator returns a Coordinator for the routers in the executor's own
+// namespace, signing its requests with the router-internal key derived from
+// FISSION_INTERNAL_AUTH_SECRET when one is set.
+func NewCoordinator(logger logr.Logger) *Coordinator {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		namespace = "fission"
+	}
+	var rt http.RoundTripper = http.DefaultTransport
+	if master := []byte(os.Getenv("FISSION_INTERNAL_AUTH_SECRET")); len(master) > 0 {
+		rt = hmacauth.ServiceSigner(master, hmacauth.ServiceRouterInternal, rt, time.Now)
+	}
+	return &Coordinator{
+		logger:       logger.WithName("drain"),
+		httpClient:   &http.Client{Transport: rt, Timeout: requestTimeout},
+		host:         svcinfo.RouterPeersHost(namespace),
+		port:         svcinfo.PortRouterInternal,
+		lookup:       net.DefaultResolver.LookupHost,
+		pollInterval: pollInterval,
+	}
+}
+
+// Drain marks the pod draining on every router replica and waits until all of
+// them report no requests in flight on it, giving up after bound (the pod's
+// termination grace period, or whatever upper bound the caller used to sleep
+// through). It reports whether the drain was confirmed; false means bound
+// elapsed (or ctx ended) first, so the caller has already waited as long as
+// it would have without coordination. A round that cannot reach every
+// replica — the peers Service does not resolve, a replica errors — confirms
+// nothing and is retried at the next poll.
+func (c *Coordinator) Drain(ctx context.Context, req Request, bound time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, bound)
+	defer cancel()
+	body, err := json.Marshal(req)
+	if err != nil {
+		c.logger.Error(err, "encoding drain request")
+		<-ctx.Done()
+		return false
+	}
+	logger := c.logger.WithValues("namespace", req.Namespace, "function", req.Function, "pod_uid", req.PodUID)
+	start := time.Now()
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		inFlight, err := c.round(ctx, body)
+		switch {
+		case err != nil:
+			logger.V(1).Info("drain round incomplete", "error", err.Error())
+		case inFlight == 0:
+			logger.V(1).Info("pod drained", "elapsed", time.Since(start))
+			return true
+		default:
+			logger.V(1).Info("waiting for in-flight requests", "inflight", inFlight)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("drain not confirmed within bound, deleting anyway", "bound", bound)
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// round sends the drain request to every replica and returns the total count
+// they report.
+func (c *Coordinator) round(ctx context.Context, body []byte) (int64, error) {
+	addrs, err := c.lookup(ctx, c.host)
+	if err != nil {
+		return 0, fmt.Errorf("resolving router peers: %w", err)
+	}
+	if len(addrs) == 0 {
+		return 0, fmt.Errorf("no router peers behind %s", c.host)
+	}
+	var total int64
+	for _, addr := range addrs {
+		n, err := c.drainPeer(ctx, "http://"+net.JoinHostPort(addr, strconv.Itoa(c.port))+Path, body)
+		if err != nil {
+			return 0, fmt.Errorf("router %s: %w", addr, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (c *Coordinator) drainPeer(ctx context.Context, url string, body []byte) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var out Response
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, fmt.Errorf("decoding drain response: %w", err)
+	}
+	return out.InFlight, nil
+}
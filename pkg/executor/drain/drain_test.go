@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package drain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fission/fission/pkg/utils/loggerfactory"
+)
+
+// newTestCoordinator points a Coordinator at one fake router replica per
+// handler, all sharing a port the way the headless Service's addresses do:
+// each server listens on its own loopback address.
+func newTestCoordinator(t *testing.T, handlers ...http.HandlerFunc) *Coordinator {
+	t.Helper()
+	var addrs []string
+	port := 0
+	for i, h := range handlers {
+		host := "127.0.0." + strconv.Itoa(i+1)
+		ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+		if err != nil {
+			t.Skipf("cannot listen on %s: %v", host, err)
+		}
+		if port == 0 {
+			port = ln.Addr().(*net.TCPAddr).Port
+		}
+		srv := httptest.NewUnstartedServer(h)
+		srv.Listener = ln
+		srv.Start()
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		addrs = append(addrs, u.Hostname())
+	}
+	return &Coordinator{
+		logger:     loggerfactory.GetLogger(),
+		httpClient: &http.Client{Timeout: requestTimeout},
+		host:       "router-peers.fission",
+		port:       port,
+		lookup: func(context.Context, string) ([]string, error) {
+			return addrs, nil
+		},
+		pollInterval: 10 * time.Millisecond,
+	}
+}
+
+// replica reports the in-flight counts in turn, repeating the last one.
+func replica(t *testing.T, counts ...int64) http.HandlerFunc {
+	var calls atomic.Int64
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, Path, r.URL.Path)
+		var req Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, Request{Namespace: "default", Function: "fn", PodUID: "pod-1"}, req)
+		i := min(int(calls.Add(1))-1, len(counts)-1)
+		_ = json.NewEncoder(w).Encode(Response{InFlight: counts[i]})
+	}
+}
+
+func TestDrain(t *testing.T) {
+	t.Parallel()
+	req := Request{Namespace: "default", Function: "fn", PodUID: "pod-1"}
+
+	t.Run("waits for every replica to drain", func(t *testing.T) {
+		t.Parallel()
+		c := newTestCoordinator(t, replica(t, 2, 1, 0), replica(t, 1, 0))
+		start := time.Now()
+		assert.True(t, c.Drain(t.Context(), req, time.Minute))
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+
+	t.Run("gives up at the bound", func(t *testing.T) {
+		t.Parallel()
+		c := newTestCoordinator(t, replica(t, 0), replica(t, 1))
+		start := time.Now()
+		assert.False(t, c.Drain(t.Context(), req, 100*time.Millisecond))
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("an unreachable replica confirms nothing", func(t *testing.T) {
+		t.Parallel()
+		c := newTestCoordinator(t, replica(t, 0), func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		})
+		assert.False(t, c.Drain(t.Context(), req, 100*time.Millisecond))
+	})
+
+	t.Run("no peers confirm nothing", func(t *testing.T) {
+		t.Parallel()
+		c := newTestCoordinator(t)
+		assert.False(t, c.Drain(t.Context(), req, 50*time.Millisecond))
+		c.lookup = func(context.Context, string) ([]string, error) { return nil, errors.New("nxdomain") }
+		assert.False(t, c.Drain(t.Context(), req, 50*time.Millisecond))
+	})
+}
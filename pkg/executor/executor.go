@@ -17,6 +17,7 @@ import (
 	"github.com/fission/fission/pkg/executor/dispatch"
 	"github.com/fission/fission/pkg/executor/executortype"
 	"github.com/fission/fission/pkg/executor/fscache"
+	"github.com/fission/fission/pkg/executor/usage"
 	"github.com/fission/fission/pkg/generated/clientset/versioned"
 	otelUtils "github.com/fission/fission/pkg/utils/otel"
 )
@@ -35,6 +36,9 @@ type (
 		// runs for poolmgr. Replaces the request-channel multiplexer.
 		dispatcher *dispatch.Dispatcher[*fscache.FuncSvc]
 
+		// accountant serves the usage API; nil when usage accounting is off.
+		accountant *usage.Accountant
+
 		// Readiness state. /readyz reports ready only when this process is the
 		// leader (or leader election is disabled) AND informer caches have
 		// synced, so non-leaders are kept out of the Service endpoints and a
@@ -131,7 +131,25 @@ func MakeContainer(
 // Its Deployment/Service reads (IsValid) go through the executor Manager's cache,
 // which controller-runtime syncs before any runnable (including this type's
 // reapers) starts.
-func (caaf *Container) Run(context.Context, *errgroup.Group) {}
+func (caaf *Container) Run(ctx context.Context, mgr *errgroup.Group) {
+	mgr.Go(func() error {
+		caaf.hpaops.RunConcurrencyScaling(ctx)
+		return nil
+	})
+}
+
+// ObserveConcurrency forwards the in-flight requests a router reported in
+// its taps to the concurrency scaling of the function's HPA.
+func (caaf *Container) ObserveConcurrency(fnMeta *metav1.ObjectMeta, source string, inflight int) {
+	caaf.hpaops.ObserveConcurrency(fnMeta.UID, source, inflight)
+}
+
+// ScaleFromZero raises fn's deployment to replicas ahead of the
+// specialization that waits for the first of them.
+func (caaf *Container) ScaleFromZero(ctx context.Context, fn *fv1.Function, replicas int32) error {
+	return executorUtils.ScaleDeploymentUp(ctx, caaf.kubernetesClient, caaf.logger,
+		caaf.nsResolver.GetFunctionNS(fn.Namespace), caaf.getObjName(fn), replicas)
+}
 
 // GetTypeName returns the executor type name.
 func (caaf *Container) GetTypeName(ctx context.Context) fv1.ExecutorType {
@@ -555,6 +573,10 @@ func (caaf *Container) updateFunction(ctx context.Context, oldFn *fv1.Function,
 			hpaChanged = true
 		}
 
+		if caaf.hpaops.ApplyConcurrencyScaling(newFn, hpa) {
+			hpaChanged = true
+		}
+
 		if hpaChanged {
 			err := caaf.hpaops.UpdateHpa(ctx, hpa)
 			if err != nil {
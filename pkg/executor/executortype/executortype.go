@@ -30,7 +30,8 @@ type ExecutorType interface {
 	RegisterReconcilers(mgr ctrl.Manager) error
 
 	// IdleStrategy returns this executor type's idle-reaping strategy, driven by
-	// the shared idle reaper instead of a per-type goroutine.
+	// the shared idle reaper instead of a per-type goroutine; nil for a type
+	// that leaves nothing idle.
 	IdleStrategy() idle.Strategy
 
 	// GetTypeName returns the name of executor type
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package job implements the job executor type. Each invocation of a job
+// function runs as its own Kubernetes Job rather than on a long-lived pod:
+// the Job's pod runs the function's environment runtime and a specializing
+// fetcher as native sidecars, and the fission-bundle job runner (see
+// pkg/jobrunner) as its main container, which posts the invocation's input to
+// the runtime once and exits with the outcome. The router starts Jobs through
+// RunJob and polls them through JobStatus; there is no function service to
+// cache, tap or reap.
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sync/errgroup"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/executor/executortype"
+	"github.com/fission/fission/pkg/executor/fscache"
+	"github.com/fission/fission/pkg/executor/reaper/idle"
+	fetcherConfig "github.com/fission/fission/pkg/fetcher/config"
+	"github.com/fission/fission/pkg/generated/clientset/versioned"
+	"github.com/fission/fission/pkg/utils"
+)
+
+var (
+	_ executortype.ExecutorType   = &Job{}
+	_ executortype.FuncReconciler = &Job{}
+)
+
+type (
+	// Job represents an executor type
+	Job struct {
+		logger logr.Logger
+
+		kubernetesClient kubernetes.Interface
+		fissionClient    versioned.Interface
+		fetcherConfig    *fetcherConfig.Config
+		instanceID       string
+		nsResolver       *utils.NamespaceResolver
+
+		// runnerImage is the fission-bundle image the Jobs' runner
+		// container runs (JOB_RUNNER_IMAGE); empty disables the executor
+		// type.
+		runnerImage            string
+		runtimeImagePullPolicy apiv1.PullPolicy
+	}
+)
+
+// MakeJob initializes and returns an instance of the job executor type.
+func MakeJob(
+	ctx context.Context,
+	logger logr.Logger,
+	fissionClient versioned.Interface,
+	kubernetesClient kubernetes.Interface,
+	fetcherConfig *fetcherConfig.Config,
+	instanceID string,
+) (executortype.ExecutorType, error) {
+	j := &Job{
+		logger: logger.WithName("job"),
+
+		fissionClient:    fissionClient,
+		kubernetesClient: kubernetesClient,
+		fetcherConfig:    fetcherConfig,
+		instanceID:       instanceID,
+		nsResolver:       utils.DefaultNSResolver(),
+
+		runnerImage:            os.Getenv("JOB_RUNNER_IMAGE"),
+		runtimeImagePullPolicy: utils.GetImagePullPolicy(os.Getenv("RUNTIME_IMAGE_PULL_POLICY")),
+	}
+	if j.runnerImage == "" {
+		j.logger.Info("JOB_RUNNER_IMAGE is not set; functions of executor type job cannot be run")
+	}
+	return j, nil
+}
+
+// Run is a no-op: the job executor type has no background work of its own.
+func (j *Job) Run(ctx context.Context, mgr *errgroup.Group) {}
+
+// RegisterReconcilers registers nothing: job functions are reconciled by the
+// shared Function reconciler through ReconcileFunction/DeleteFunction.
+func (j *Job) RegisterReconcilers(mgr ctrl.Manager) error {
+	return nil
+}
+
+// GetTypeName returns the executor type name.
+func (j *Job) GetTypeName(ctx context.Context) fv1.ExecutorType {
+	return fv1.ExecutorTypeJob
+}
+
+// GetFuncSvc refuses: a job function has no service to proxy to. The router
+// starts its invocations through RunJob instead.
+func (j *Job) GetFuncSvc(ctx context.Context, fn *fv1.Function) (*fscache.FuncSvc, error) {
+	return nil, ferror.MakeError(ferror.ErrorInvalidArgument,
+		fmt.Sprintf("function %s/%s of executor type job has no service; invoke it through the router", fn.Namespace, fn.Name))
+}
+
+// GetFuncSvcFromCache always misses: nothing is cached for job functions.
+func (j *Job) GetFuncSvcFromCache(ctx context.Context, fn *fv1.Function) (*fscache.FuncSvc, error) {
+	return nil, ferror.MakeError(ferror.ErrorNotFound,
+		fmt.Sprintf("function %s/%s of executor type job has no service", fn.Namespace, fn.Name))
+}
+
+// DeleteFuncSvcFromCache has not been implemented for job.
+func (j *Job) DeleteFuncSvcFromCache(ctx context.Context, fsvc *fscache.FuncSvc) {
+	// Not Implemented for job.
+}
+
+// TapService has not been implemented for job.
+func (j *Job) TapService(ctx context.Context, svcHost string) error {
+	return nil
+}
+
+// UnTapService has not been implemented for job.
+func (j *Job) UnTapService(ctx context.Context, fnMeta *metav1.ObjectMeta, svcHost string) {
+	// Not Implemented for job.
+}
+
+// MarkSpecializationFailure has not been implemented for job.
+func (j *Job) MarkSpecializationFailure(ctx context.Context, fnMeta *metav1.ObjectMeta) {
+	// Not Implemented for job.
+}
+
+// IsValid reports false: there are no job function services to validate.
+func (j *Job) IsValid(ctx context.Context, fsvc *fscache.FuncSvc) bool {
+	return false
+}
+
+// RefreshFuncPods is a no-op: every Job mounts the function's secrets and
+// configmaps as they are when it starts.
+func (j *Job) RefreshFuncPods(ctx context.Context, logger logr.Logger, f fv1.Function) error {
+	return nil
+}
+
+// AdoptExistingResources is a no-op: Jobs are not tied to the executor
+// instance that created them.
+func (j *Job) AdoptExistingResources(ctx context.Context) {}
+
+// CleanupOldExecutorObjects is a no-op: a Job outlives an executor restart,
+// and the router keeps polling it; finished Jobs are removed by their TTL.
+func (j *Job) CleanupOldExecutorObjects(ctx context.Context) {}
+
+// IdleStrategy returns nil: a Job exits when its invocation is done, so
+// there is nothing to reap.
+func (j *Job) IdleStrategy() idle.Strategy {
+	return nil
+}
+
+func (j *Job) DumpDebugInfo(ctx context.Context) error {
+	return nil
+}
+
+// ReconcileFunction satisfies executortype.FuncReconciler. Jobs are created
+// per invocation, so a function change has nothing to update: running Jobs
+// finish on the generation they started with.
+func (j *Job) ReconcileFunction(ctx context.Context, old, fn *fv1.Function) error {
+	return nil
+}
+
+// DeleteFunction satisfies executortype.FuncReconciler: it deletes the
+// function's Jobs, running or finished, with their pods and input Secrets.
+func (j *Job) DeleteFunction(ctx context.Context, fn *fv1.Function) error {
+	ns := j.nsResolver.GetFunctionNS(fn.Namespace)
+	jobs, err := j.kubernetesClient.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{
+			fv1.EXECUTOR_TYPE: string(fv1.ExecutorTypeJob),
+			fv1.FUNCTION_UID:  string(fn.UID),
+		}.AsSelector().String(),
+	})
+	if err != nil {
+		return fmt.Errorf("error listing jobs of function %s/%s: %w", fn.Namespace, fn.Name, err)
+	}
+	propagation := metav1.DeletePropagationBackground
+	var errs []error
+	for _, job := range jobs.Items {
+		err := j.kubernetesClient.BatchV1().Jobs(ns).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !k8sErrs.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting job %s: %w", job.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
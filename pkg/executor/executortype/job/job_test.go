@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/executor/client"
+	executorUtils "github.com/fission/fission/pkg/executor/util"
+	fetcherConfig "github.com/fission/fission/pkg/fetcher/config"
+	fakeFission "github.com/fission/fission/pkg/generated/clientset/versioned/fake"
+	"github.com/fission/fission/pkg/jobrunner"
+	"github.com/fission/fission/pkg/utils"
+)
+
+func jobFunction() *fv1.Function {
+	return &fv1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default", UID: "uid-fn"},
+		Spec: fv1.FunctionSpec{
+			Environment: fv1.EnvironmentReference{Name: "python", Namespace: "default"},
+			Package: fv1.FunctionPackageRef{
+				PackageRef: fv1.PackageRef{Name: "pkg", Namespace: "default"},
+			},
+			FunctionTimeout: 60,
+			InvokeStrategy: fv1.InvokeStrategy{
+				ExecutionStrategy: fv1.ExecutionStrategy{
+					ExecutorType: fv1.ExecutorTypeJob,
+					Job:          &fv1.JobConfig{BackoffLimit: 2, ActiveDeadline: "30m"},
+				},
+			},
+		},
+	}
+}
+
+func newTestJob(t *testing.T, fn *fv1.Function) *Job {
+	t.Helper()
+	fc, err := fetcherConfig.MakeFetcherConfig("/userfunc")
+	require.NoError(t, err)
+	env := &fv1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "python", Namespace: "default"},
+		Spec:       fv1.EnvironmentSpec{Runtime: fv1.Runtime{Image: "python-env"}},
+	}
+	return &Job{
+		logger:           logr.Discard(),
+		kubernetesClient: fake.NewClientset(),
+		fissionClient:    fakeFission.NewClientset(fn, env),
+		fetcherConfig:    fc,
+		instanceID:       "instance",
+		nsResolver:       utils.DefaultNSResolver(),
+		runnerImage:      "fission-bundle",
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	fn := jobFunction()
+	j := newTestJob(t, fn)
+
+	require.NoError(t, j.RunJob(t.Context(), fn, "inv-1", []byte("input"), "text/plain"))
+	// Starting the same invocation again is a no-op.
+	require.NoError(t, j.RunJob(t.Context(), fn, "inv-1", []byte("input"), "text/plain"))
+
+	ns := j.nsResolver.GetFunctionNS(fn.Namespace)
+	jobs, err := j.kubernetesClient.BatchV1().Jobs(ns).List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, jobs.Items, 1)
+	job := jobs.Items[0]
+	assert.Equal(t, jobName("inv-1"), job.Name)
+	assert.Equal(t, "inv-1", job.Annotations[invocationIDAnnotation])
+	assert.Equal(t, "python", runtimeContainer(&job))
+	assert.Equal(t, int32(2), *job.Spec.BackoffLimit)
+	assert.Equal(t, int64(1800), *job.Spec.ActiveDeadlineSeconds, "the job's deadline, not the function timeout")
+
+	spec := job.Spec.Template.Spec
+	assert.Equal(t, apiv1.RestartPolicyNever, spec.RestartPolicy)
+	require.Len(t, spec.Containers, 1)
+	assert.Equal(t, []string{"/fission-bundle", "--jobRunner"}, spec.Containers[0].Command)
+	var sidecars []string
+	for _, c := range spec.InitContainers {
+		require.NotNil(t, c.RestartPolicy, c.Name)
+		assert.Equal(t, apiv1.ContainerRestartPolicyAlways, *c.RestartPolicy)
+		sidecars = append(sidecars, c.Name)
+	}
+	assert.ElementsMatch(t, []string{"python", executorUtils.FetcherContainerName}, sidecars)
+	assert.False(t, *spec.AutomountServiceAccountToken)
+
+	secret, err := j.kubernetesClient.CoreV1().Secrets(ns).Get(t.Context(), job.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "input", string(secret.Data[jobrunner.InputBodyKey]))
+	assert.Equal(t, "text/plain", string(secret.Data[jobrunner.InputContentTypeKey]))
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, job.Name, secret.OwnerReferences[0].Name)
+
+	// Deleting the function deletes its jobs.
+	require.NoError(t, j.DeleteFunction(t.Context(), fn))
+	jobs, err = j.kubernetesClient.BatchV1().Jobs(ns).List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+}
+
+func TestRunJobWithoutDeadline(t *testing.T) {
+	fn := jobFunction()
+	fn.Spec.InvokeStrategy.ExecutionStrategy.Job.ActiveDeadline = ""
+	j := newTestJob(t, fn)
+
+	require.NoError(t, j.RunJob(t.Context(), fn, "inv-1", nil, ""))
+	job, err := j.kubernetesClient.BatchV1().Jobs(j.nsResolver.GetFunctionNS(fn.Namespace)).Get(t.Context(), jobName("inv-1"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, job.Spec.ActiveDeadlineSeconds, "the function timeout must not bound a job")
+}
+
+func TestRunJobRedeliveryRepairsInput(t *testing.T) {
+	fn := jobFunction()
+	j := newTestJob(t, fn)
+	ns := j.nsResolver.GetFunctionNS(fn.Namespace)
+	kubeClient := j.kubernetesClient.(*fake.Clientset)
+
+	// The input Secret cannot be created: no Job may start without it.
+	kubeClient.PrependReactor("create", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("apiserver unavailable")
+	})
+	require.Error(t, j.RunJob(t.Context(), fn, "inv-1", []byte("input"), "text/plain"))
+	jobs, err := kubeClient.BatchV1().Jobs(ns).List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+
+	kubeClient.ReactionChain = kubeClient.ReactionChain[1:]
+	require.NoError(t, j.RunJob(t.Context(), fn, "inv-1", []byte("input"), "text/plain"))
+
+	// The executor went away after the Job was created and before its input
+	// was: the redelivery must still supply it.
+	require.NoError(t, kubeClient.CoreV1().Secrets(ns).Delete(t.Context(), jobName("inv-1"), metav1.DeleteOptions{}))
+	require.NoError(t, j.RunJob(t.Context(), fn, "inv-1", []byte("input"), "text/plain"))
+
+	secret, err := kubeClient.CoreV1().Secrets(ns).Get(t.Context(), jobName("inv-1"), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "input", string(secret.Data[jobrunner.InputBodyKey]))
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, jobName("inv-1"), secret.OwnerReferences[0].Name)
+	jobs, err = kubeClient.BatchV1().Jobs(ns).List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, jobs.Items, 1)
+}
+
+func TestRunJobDisabled(t *testing.T) {
+	fn := jobFunction()
+	j := newTestJob(t, fn)
+	j.runnerImage = ""
+
+	err := j.RunJob(t.Context(), fn, "inv-1", nil, "")
+	var fe ferror.Error
+	require.ErrorAs(t, err, &fe)
+	assert.EqualValues(t, ferror.ErrorInvalidArgument, fe.Code)
+}
+
+func TestJobStatus(t *testing.T) {
+	fn := jobFunction()
+	j := newTestJob(t, fn)
+	ns := j.nsResolver.GetFunctionNS(fn.Namespace)
+
+	_, err := j.JobStatus(t.Context(), fn, "inv-1")
+	var fe ferror.Error
+	require.ErrorAs(t, err, &fe)
+	assert.EqualValues(t, ferror.ErrorNotFound, fe.Code)
+
+	require.NoError(t, j.RunJob(t.Context(), fn, "inv-1", nil, ""))
+	status, err := j.JobStatus(t.Context(), fn, "inv-1")
+	require.NoError(t, err)
+	assert.Equal(t, client.JobRunning, status.Phase)
+
+	// A failed Job whose pods are gone reports the condition's message.
+	job, err := j.kubernetesClient.BatchV1().Jobs(ns).Get(t.Context(), jobName("inv-1"), metav1.GetOptions{})
+	require.NoError(t, err)
+	job.Status.Conditions = []batchv1.JobCondition{{
+		Type: batchv1.JobFailed, Status: apiv1.ConditionTrue, Message: "Job was active longer than specified deadline",
+	}}
+	_, err = j.kubernetesClient.BatchV1().Jobs(ns).UpdateStatus(t.Context(), job, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	status, err = j.JobStatus(t.Context(), fn, "inv-1")
+	require.NoError(t, err)
+	assert.Equal(t, client.JobFailed, status.Phase)
+	assert.Equal(t, "Job was active longer than specified deadline", status.Message)
+}
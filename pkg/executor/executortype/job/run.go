@@ -0,0 +1,304 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sCache "k8s.io/client-go/tools/cache"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/executor/util"
+	"github.com/fission/fission/pkg/jobrunner"
+	"github.com/fission/fission/pkg/svcinfo"
+)
+
+const (
+	// runnerName names the runner container of a Job's pod.
+	runnerName = "job-runner"
+	// inputVolumeName names the volume of the invocation's input Secret.
+	inputVolumeName = "job-input"
+
+	// invocationIDAnnotation records a Job's invocation id, and
+	// runtimeContainerAnnotation the name of its runtime container, whose
+	// log is the invocation's output.
+	invocationIDAnnotation     = "fission.io/invocation-id"
+	runtimeContainerAnnotation = "fission.io/runtime-container"
+
+	// finishedJobTTL is how long a finished Job stays for the router to
+	// read its outcome before Kubernetes deletes it.
+	finishedJobTTL = int32(3600)
+)
+
+// jobName returns the name of the Job of an invocation; the same invocation
+// always maps to the same Job, so starting it twice is a no-op.
+func jobName(invocationID string) string {
+	sum := sha256.Sum256([]byte(invocationID))
+	return "job-" + hex.EncodeToString(sum[:])[:20]
+}
+
+// RunJob starts the Job of one invocation of fn, with body as its input.
+// It returns once the Job is created; the invocation runs asynchronously
+// and JobStatus reports its outcome. Calling it again for the same
+// invocation creates whatever an earlier call left missing.
+func (j *Job) RunJob(ctx context.Context, fn *fv1.Function, invocationID string, body []byte, contentType string) error {
+	if j.runnerImage == "" {
+		return ferror.MakeError(ferror.ErrorInvalidArgument,
+			"executor type job is not enabled: the executor has no JOB_RUNNER_IMAGE")
+	}
+	if fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType != fv1.ExecutorTypeJob {
+		return ferror.MakeError(ferror.ErrorInvalidArgument,
+			fmt.Sprintf("function %s is not of executor type job", k8sCache.MetaObjectToName(fn)))
+	}
+	env, err := j.fissionClient.CoreV1().Environments(fn.Spec.Environment.Namespace).Get(ctx, fn.Spec.Environment.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrs.IsNotFound(err) {
+			return ferror.MakeError(ferror.ErrorNotFound,
+				fmt.Sprintf("environment %s/%s of function %s not found", fn.Spec.Environment.Namespace, fn.Spec.Environment.Name, k8sCache.MetaObjectToName(fn)))
+		}
+		return err
+	}
+
+	ns := j.nsResolver.GetFunctionNS(fn.Namespace)
+	job, err := j.jobSpec(ctx, fn, env, ns, invocationID)
+	if err != nil {
+		return fmt.Errorf("error building job for function %s: %w", k8sCache.MetaObjectToName(fn), err)
+	}
+	// The input Secret goes first: the Job's pod cannot start without it,
+	// and a redelivery that finds the Job already there must still find its
+	// input.
+	secret := &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name,
+			Namespace: ns,
+			Labels:    job.Labels,
+		},
+		Data: map[string][]byte{
+			jobrunner.InputBodyKey:        body,
+			jobrunner.InputContentTypeKey: []byte(contentType),
+		},
+	}
+	secret, err = j.kubernetesClient.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{})
+	if k8sErrs.IsAlreadyExists(err) {
+		secret, err = j.kubernetesClient.CoreV1().Secrets(ns).Get(ctx, job.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error creating job input for function %s: %w", k8sCache.MetaObjectToName(fn), err)
+	}
+
+	created, err := j.kubernetesClient.BatchV1().Jobs(ns).Create(ctx, job, metav1.CreateOptions{})
+	started := err == nil
+	if k8sErrs.IsAlreadyExists(err) {
+		created, err = j.kubernetesClient.BatchV1().Jobs(ns).Get(ctx, job.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error creating job for function %s: %w", k8sCache.MetaObjectToName(fn), err)
+	}
+
+	// The input Secret is owned by the Job, so it goes with it.
+	if !metav1.IsControlledBy(secret, created) {
+		secret.OwnerReferences = append(secret.OwnerReferences,
+			*metav1.NewControllerRef(created, batchv1.SchemeGroupVersion.WithKind("Job")))
+		if _, err := j.kubernetesClient.CoreV1().Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("error adopting job input for function %s: %w", k8sCache.MetaObjectToName(fn), err)
+		}
+	}
+	if started {
+		j.logger.Info("started job", "function", fn.Name, "namespace", fn.Namespace,
+			"job", created.Name, "invocationId", invocationID)
+	}
+	return nil
+}
+
+// jobSpec returns the Job of one invocation of fn. Its pod is built like a
+// newdeploy pod — the runtime container, the specializing fetcher, the
+// environment's pod spec and the function's env — after which the runtime
+// and the fetcher become native sidecars of the runner, so the pod finishes
+// when the runner does.
+func (j *Job) jobSpec(ctx context.Context, fn *fv1.Function, env *fv1.Environment, ns, invocationID string) (*batchv1.Job, error) {
+	mainContainerName := env.Name
+	if env.Spec.Runtime.Container != nil && env.Spec.Runtime.Container.Name != "" && env.Spec.Runtime.PodSpec != nil {
+		if !util.DoesContainerExistInPodSpec(env.Spec.Runtime.Container.Name, env.Spec.Runtime.PodSpec) {
+			return nil, fmt.Errorf("runtime container %s not found in pod spec", env.Spec.Runtime.Container.Name)
+		}
+		mainContainerName = env.Spec.Runtime.Container.Name
+	}
+
+	rvCount, err := util.ReferencedResourcesRVSum(ctx, j.kubernetesClient, fn.Namespace, fn.Spec)
+	if err != nil {
+		return nil, err
+	}
+	// Platform-owned env for the runtime container; see newdeploy's
+	// getDeploymentSpec.
+	platformEnv := append([]apiv1.EnvVar{
+		{
+			Name:  fv1.ResourceVersionCount,
+			Value: fmt.Sprintf("%d", rvCount),
+		},
+	}, util.StateAPIEnvVars(j.fetcherConfig.SharedMountPath())...)
+
+	runtime, err := util.MergeContainer(&apiv1.Container{
+		Name:                   env.Name,
+		Image:                  env.Spec.Runtime.Image,
+		ImagePullPolicy:        j.runtimeImagePullPolicy,
+		TerminationMessagePath: "/dev/termination-log",
+		Env:                    platformEnv,
+		Ports: []apiv1.ContainerPort{
+			{
+				Name:          "http-env",
+				ContainerPort: int32(svcinfo.PortEnvRuntime),
+			},
+		},
+		Resources: resources(env, fn),
+	}, env.Spec.Runtime.Container)
+	if err != nil {
+		return nil, err
+	}
+
+	// The runtime container does not get the fetcher's ServiceAccount
+	// token; see GHSA-85g2-pmrx-r49q.
+	spec := apiv1.PodSpec{
+		Containers:                   []apiv1.Container{*runtime},
+		ServiceAccountName:           fv1.FissionFetcherSA,
+		AutomountServiceAccountToken: new(false),
+		Volumes:                      []apiv1.Volume{util.FetcherSATokenProjectedVolume()},
+	}
+	if err := j.fetcherConfig.AddSpecializingFetcherToPodSpec(&spec, mainContainerName, ns, fn, env); err != nil {
+		return nil, err
+	}
+	if env.Spec.Runtime.PodSpec != nil {
+		merged, err := util.MergePodSpec(&spec, env.Spec.Runtime.PodSpec)
+		if err != nil {
+			return nil, err
+		}
+		spec = *merged
+		spec.AutomountServiceAccountToken = new(false)
+	}
+	if err := util.ApplyFunctionEnv(&spec, mainContainerName, ns, fn, platformEnv); err != nil {
+		return nil, err
+	}
+	if err := util.MountFetcherSATokenOnFetcher(&spec); err != nil {
+		return nil, err
+	}
+	spec = *util.ApplyImagePullSecret(env.Spec.ImagePullSecret, spec)
+
+	// Every container so far serves the runner: it starts them ahead of
+	// itself and stops them when it exits.
+	for _, c := range spec.Containers {
+		c.RestartPolicy = new(apiv1.ContainerRestartPolicyAlways)
+		spec.InitContainers = append(spec.InitContainers, c)
+	}
+	spec.Containers = []apiv1.Container{{
+		Name:                   runnerName,
+		Image:                  j.runnerImage,
+		ImagePullPolicy:        j.runtimeImagePullPolicy,
+		Command:                []string{"/fission-bundle", "--jobRunner"},
+		TerminationMessagePath: "/dev/termination-log",
+		VolumeMounts: []apiv1.VolumeMount{{
+			Name:      inputVolumeName,
+			MountPath: jobrunner.InputDir,
+			ReadOnly:  true,
+		}},
+		Resources: apiv1.ResourceRequirements{
+			Requests: apiv1.ResourceList{
+				apiv1.ResourceCPU:    resource.MustParse("10m"),
+				apiv1.ResourceMemory: resource.MustParse("16Mi"),
+			},
+		},
+	}}
+	spec.Volumes = append(spec.Volumes, apiv1.Volume{
+		Name: inputVolumeName,
+		VolumeSource: apiv1.VolumeSource{
+			Secret: &apiv1.SecretVolumeSource{SecretName: jobName(invocationID)},
+		},
+	})
+	spec.RestartPolicy = apiv1.RestartPolicyNever
+
+	jobLabels := map[string]string{
+		fv1.EXECUTOR_TYPE:      string(fv1.ExecutorTypeJob),
+		fv1.FUNCTION_NAME:      fn.Name,
+		fv1.FUNCTION_NAMESPACE: fn.Namespace,
+		fv1.FUNCTION_UID:       string(fn.UID),
+	}
+	podLabels := maps.Clone(env.Labels)
+	if podLabels == nil {
+		podLabels = make(map[string]string)
+	}
+	maps.Copy(podLabels, jobLabels)
+	podAnnotations := maps.Clone(env.Annotations)
+
+	cfg := fn.Spec.InvokeStrategy.ExecutionStrategy.Job.Effective()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName(invocationID),
+			Namespace: ns,
+			Labels:    jobLabels,
+			Annotations: map[string]string{
+				invocationIDAnnotation:        invocationID,
+				runtimeContainerAnnotation:    mainContainerName,
+				fv1.EXECUTOR_INSTANCEID_LABEL: j.instanceID,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            new(cfg.BackoffLimit),
+			TTLSecondsAfterFinished: new(finishedJobTTL),
+			Template: apiv1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      podLabels,
+					Annotations: podAnnotations,
+				},
+				Spec: spec,
+			},
+		},
+	}
+	if cfg.ActiveDeadline != "" {
+		d, err := time.ParseDuration(cfg.ActiveDeadline)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job active deadline: %w", err)
+		}
+		job.Spec.ActiveDeadlineSeconds = new(int64(d.Seconds()))
+	}
+	return job, nil
+}
+
+// resources returns the environment's resources with the ones the function
+// overrides; see newdeploy's getResources.
+func resources(env *fv1.Environment, fn *fv1.Function) apiv1.ResourceRequirements {
+	res := apiv1.ResourceRequirements{
+		Requests: maps.Clone(env.Spec.Resources.Requests),
+		Limits:   maps.Clone(env.Spec.Resources.Limits),
+	}
+	if res.Requests == nil {
+		res.Requests = make(apiv1.ResourceList)
+	}
+	if res.Limits == nil {
+		res.Limits = make(apiv1.ResourceList)
+	}
+	for _, name := range []apiv1.ResourceName{apiv1.ResourceCPU, apiv1.ResourceMemory} {
+		if v, ok := fn.Spec.Resources.Requests[name]; ok && !v.IsZero() {
+			res.Requests[name] = v
+		}
+		if v, ok := fn.Spec.Resources.Limits[name]; ok && !v.IsZero() {
+			res.Limits[name] = v
+		}
+	}
+	return res
+}
+
+// runtimeContainer returns the name of a Job's runtime container.
+func runtimeContainer(job *batchv1.Job) string {
+	return job.Annotations[runtimeContainerAnnotation]
+}
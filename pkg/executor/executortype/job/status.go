@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sCache "k8s.io/client-go/tools/cache"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/executor/client"
+	"github.com/fission/fission/pkg/jobrunner"
+)
+
+const (
+	// runnerLogLines is how much of the runner's log is read for its
+	// Result line, which it prints last.
+	runnerLogLines = int64(10)
+
+	// maxOutputBytes and maxOutputLines bound the tail of the runtime's log
+	// kept as an invocation's output.
+	maxOutputBytes = int64(64 << 10)
+	maxOutputLines = int64(1000)
+)
+
+// JobStatus returns the state of one invocation of fn. Once its Job has
+// finished, the status carries the function's response, read from the
+// runner's log, and the tail of the runtime's log as the output. A Job
+// that is gone, e.g. removed by its TTL, is an ErrorNotFound.
+func (j *Job) JobStatus(ctx context.Context, fn *fv1.Function, invocationID string) (*client.JobStatus, error) {
+	ns := j.nsResolver.GetFunctionNS(fn.Namespace)
+	job, err := j.kubernetesClient.BatchV1().Jobs(ns).Get(ctx, jobName(invocationID), metav1.GetOptions{})
+	if err != nil {
+		if k8sErrs.IsNotFound(err) {
+			return nil, ferror.MakeError(ferror.ErrorNotFound,
+				fmt.Sprintf("job of invocation %s of function %s not found", invocationID, k8sCache.MetaObjectToName(fn)))
+		}
+		return nil, err
+	}
+
+	status := &client.JobStatus{Phase: client.JobRunning}
+	var failure string
+	for _, c := range job.Status.Conditions {
+		if c.Status != apiv1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			status.Phase = client.JobSucceeded
+		case batchv1.JobFailed:
+			status.Phase = client.JobFailed
+			failure = c.Message
+		}
+	}
+	if !status.Done() {
+		return status, nil
+	}
+
+	pod, err := j.lastPod(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if pod == nil {
+		status.Message = failure
+		return status, nil
+	}
+	if out, err := j.podLog(ctx, pod, &apiv1.PodLogOptions{Container: runnerName, TailLines: new(runnerLogLines)}); err == nil {
+		if res, ok := jobrunner.ParseResult(out); ok {
+			status.StatusCode = res.StatusCode
+			status.Body = res.Body
+			status.Truncated = res.Truncated
+			status.Message = res.Error
+		}
+	} else {
+		j.logger.Error(err, "error reading job result", "job", job.Name, "namespace", job.Namespace)
+	}
+	if out, err := j.podLog(ctx, pod, &apiv1.PodLogOptions{
+		Container:  runtimeContainer(job),
+		TailLines:  new(maxOutputLines),
+		LimitBytes: new(maxOutputBytes),
+	}); err == nil {
+		status.Output = string(out)
+	} else {
+		j.logger.Error(err, "error reading job output", "job", job.Name, "namespace", job.Namespace)
+	}
+	if status.Message == "" && status.StatusCode == 0 {
+		status.Message = failure
+	}
+	return status, nil
+}
+
+// lastPod returns the newest pod of job, the one whose attempt decided its
+// outcome; nil if it has none left.
+func (j *Job) lastPod(ctx context.Context, job *batchv1.Job) (*apiv1.Pod, error) {
+	pods, err := j.kubernetesClient.CoreV1().Pods(job.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set{batchv1.JobNameLabel: job.Name}.AsSelector().String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var last *apiv1.Pod
+	for i := range pods.Items {
+		if last == nil || last.CreationTimestamp.Before(&pods.Items[i].CreationTimestamp) {
+			last = &pods.Items[i]
+		}
+	}
+	return last, nil
+}
+
+func (j *Job) podLog(ctx context.Context, pod *apiv1.Pod, opts *apiv1.PodLogOptions) ([]byte, error) {
+	if opts.Container == "" {
+		return nil, fmt.Errorf("no container to read the log of in pod %s", pod.Name)
+	}
+	rc, err := j.kubernetesClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
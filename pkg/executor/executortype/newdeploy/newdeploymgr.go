@@ -151,7 +151,25 @@ func MakeNewDeploy(
 // factory. Its Deployment/Service reads (IsValid) go through the executor
 // Manager's cache, which controller-runtime syncs before any runnable (including
 // this type's reapers) starts.
-func (deploy *NewDeploy) Run(context.Context, *errgroup.Group) {}
+func (deploy *NewDeploy) Run(ctx context.Context, mgr *errgroup.Group) {
+	mgr.Go(func() error {
+		deploy.hpaops.RunConcurrencyScaling(ctx)
+		return nil
+	})
+}
+
+// ObserveConcurrency forwards the in-flight requests a router reported in
+// its taps to the concurrency scaling of the function's HPA.
+func (deploy *NewDeploy) ObserveConcurrency(fnMeta *metav1.ObjectMeta, source string, inflight int) {
+	deploy.hpaops.ObserveConcurrency(fnMeta.UID, source, inflight)
+}
+
+// ScaleFromZero raises fn's deployment to replicas ahead of the
+// specialization that waits for the first of them.
+func (deploy *NewDeploy) ScaleFromZero(ctx context.Context, fn *fv1.Function, replicas int32) error {
+	return executorUtils.ScaleDeploymentUp(ctx, deploy.kubernetesClient, deploy.logger,
+		deploy.nsResolver.GetFunctionNS(fn.Namespace), deploy.getObjName(fn), replicas)
+}
 
 // GetTypeName returns the executor type name.
 func (deploy *NewDeploy) GetTypeName(ctx context.Context) fv1.ExecutorType {
@@ -672,6 +690,10 @@ func (deploy *NewDeploy) updateFunction(ctx context.Context, oldFn *fv1.Function
 			hpaChanged = true
 		}
 
+		if deploy.hpaops.ApplyConcurrencyScaling(newFn, hpa) {
+			hpaChanged = true
+		}
+
 		if hpaChanged {
 			err := deploy.hpaops.UpdateHpa(ctx, hpa)
 			if err != nil {
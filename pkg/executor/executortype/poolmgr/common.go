@@ -89,7 +89,11 @@ func envLabelsMatchLiveEnv(labels map[string]string, env *fv1.Environment) bool
 
 func getEnvPoolSize(env *fv1.Environment) int32 {
 	var poolsize int32
-	if env.Spec.Version < 3 {
+	if env.Spec.PoolAutoscale != nil {
+		// An autoscaled pool starts with its idle target.
+		cfg := env.Spec.PoolAutoscale.Effective()
+		poolsize = int32(min(max(cfg.TargetIdle, cfg.MinSize), cfg.MaxSize))
+	} else if env.Spec.Version < 3 {
 		poolsize = 3
 	} else {
 		poolsize = int32(env.Spec.Poolsize)
@@ -77,10 +77,18 @@ type (
 		// ociImageHash is ociPoolHash(oci): keys the pool, labels its
 		// pods, and suffixes the deployment name. Empty for plain pools.
 		ociImageHash string
+		// resourceClass names the environment resource class whose
+		// resources and placement this pool's pods get. Empty for the
+		// environment's default pool. The class itself is looked up in the
+		// environment on every deployment (re)generation, so an environment
+		// update reaches the pool like any other spec change.
+		resourceClass string
 		// lastActive (unix nanos) is the pool's activity clock for the
 		// per-image idle reaper (RFC-0012): stored at creation and on every
 		// GET_POOL. Atomic so the reap pass can read it lock-free.
 		lastActive atomic.Int64
+		// autoscaler sizes the pool when the environment sets PoolAutoscale.
+		autoscaler *poolAutoscaler
 		// TODO: move this field into fsCache
 		podFSVCMap sync.Map
 	}
@@ -115,6 +123,7 @@ func MakeGenericPool(
 	enableIstio bool,
 	podSpecPatch *apiv1.PodSpec,
 	crClient client.Client,
+	resourceClass string,
 	oci *ociPoolSpec,
 	podReadyTimeout time.Duration) *GenericPool {
 
@@ -144,6 +153,8 @@ func MakeGenericPool(
 		podSpecPatch:          podSpecPatch,
 		enableOwnerReferences: utils.IsOwnerReferencesEnabled(),
 		lock:                  sync.Mutex{},
+		autoscaler:            newPoolAutoscaler(),
+		resourceClass:         resourceClass,
 	}
 	if oci != nil {
 		gp.oci = oci.archive
@@ -165,12 +176,14 @@ func (gp *GenericPool) setup(ctx context.Context) error {
 	}
 	// Warm pods of this pool are fed into gp.readyPodQueue by the executor's
 	// Pod reconciler (see reconciler.go); choosePod consumes from it.
-	// updateCPUUtilizationSvc runs for the lifetime of the pool, so it gets a
-	// context tied to the pool (cancelled in destroy) rather than the
-	// request-scoped ctx, which would cancel once the triggering request ends.
+	// updateCPUUtilizationSvc and autoscalePool run for the lifetime of the
+	// pool, so they get a context tied to the pool (cancelled in destroy)
+	// rather than the request-scoped ctx, which would cancel once the
+	// triggering request ends.
 	poolCtx, cancel := context.WithCancel(context.Background())
 	gp.cancelPoolCtx = cancel
 	go gp.updateCPUUtilizationSvc(poolCtx)
+	go gp.autoscalePool(poolCtx)
 	return nil
 }
 
@@ -180,6 +193,8 @@ func (gp *GenericPool) getFuncSvc(ctx context.Context, fn *fv1.Function) (*fscac
 
 	logger.Info("choosing pod from pool")
 	funcLabels := gp.labelsForFunction(&fn.ObjectMeta)
+	// Every specialization takes a warm pod the pool has to replace.
+	gp.autoscaler.observe(time.Now())
 
 	if gp.useIstio {
 		// Istio only allows accessing pod through k8s service, and requests come to
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package poolmgr
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// poolAutoscaleInterval is how often the autoscaler re-evaluates a pool
+// without new demand, and the pace of a scale down: one pod per interval.
+const poolAutoscaleInterval = 15 * time.Second
+
+// poolAutoscaler sizes a GenericPool from its recent specializations (see
+// fv1.PoolAutoscaleConfig). The size itself lives on the pool deployment, so
+// an executor restart picks up where the previous one left off; the demand
+// history starts over.
+type poolAutoscaler struct {
+	mu sync.Mutex
+	// demand holds the specializations of every demandSlotWidth slot of the
+	// last MaxPoolAutoscaleWindow, oldest first. Slots without any are not
+	// stored.
+	demand []demandSample
+	// lowSince is when the desired size first dropped below the pool size;
+	// zero while it is not below.
+	lowSince time.Time
+	// lastDown is when the pool last shrank.
+	lastDown time.Time
+	// kick wakes autoscalePool on new demand, so the pool grows without
+	// waiting for the next tick.
+	kick chan struct{}
+}
+
+func newPoolAutoscaler() *poolAutoscaler {
+	return &poolAutoscaler{kick: make(chan struct{}, 1)}
+}
+
+// observe counts one specialization at now.
+func (a *poolAutoscaler) observe(now time.Time) {
+	a.mu.Lock()
+	start := now.Truncate(demandSlotWidth)
+	if n := len(a.demand); n > 0 && !a.demand[n-1].start.Before(start) {
+		a.demand[n-1].value++
+	} else {
+		a.demand = append(a.demand, demandSample{start: start, value: 1})
+	}
+	a.pruneLocked(now)
+	a.mu.Unlock()
+
+	select {
+	case a.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (a *poolAutoscaler) pruneLocked(now time.Time) {
+	cut := now.Add(-fv1.MaxPoolAutoscaleWindow - demandSlotWidth)
+	i := 0
+	for i < len(a.demand) && a.demand[i].start.Before(cut) {
+		i++
+	}
+	a.demand = slices.Delete(a.demand, 0, i)
+}
+
+// next returns the size of a pool of current pods at now, and the demand it
+// follows: the specializations of the last window. The pool grows to the
+// desired size at once; below it, the pool shrinks by one pod per
+// poolAutoscaleInterval once the desired size has stayed lower for delay.
+func (a *poolAutoscaler) next(cfg fv1.PoolAutoscaleConfig, window, delay time.Duration, current int, now time.Time) (size, demand int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pruneLocked(now)
+	from := now.Add(-window)
+	for _, s := range a.demand {
+		if s.start.Add(demandSlotWidth).After(from) {
+			demand += s.value
+		}
+	}
+
+	// Bounds apply at once, in both directions.
+	current = min(max(current, cfg.MinSize), cfg.MaxSize)
+	desired := min(max(demand+cfg.TargetIdle, cfg.MinSize), cfg.MaxSize)
+	if desired >= current {
+		a.lowSince = time.Time{}
+		return desired, demand
+	}
+	if a.lowSince.IsZero() {
+		a.lowSince = now
+	}
+	if now.Sub(a.lowSince) < delay || now.Sub(a.lastDown) < poolAutoscaleInterval {
+		return current, demand
+	}
+	a.lastDown = now
+	return current - 1, demand
+}
+
+// reset forgets the scale down in progress, for a pool whose autoscaling
+// was turned off.
+func (a *poolAutoscaler) reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lowSince, a.lastDown = time.Time{}, time.Time{}
+}
+
+// autoscalePool resizes the pool for the lifetime of ctx, on every tick and
+// on every specialization. Pools whose environment has no PoolAutoscale are
+// left alone.
+func (gp *GenericPool) autoscalePool(ctx context.Context) {
+	ticker := time.NewTicker(poolAutoscaleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-gp.autoscaler.kick:
+		}
+		if err := gp.autoscaleOnce(ctx, time.Now()); err != nil {
+			gp.logger.Error(err, "error autoscaling pool")
+		}
+	}
+}
+
+func (gp *GenericPool) autoscaleOnce(ctx context.Context, now time.Time) error {
+	// avoid racing create/update/delete of the pool deployment
+	gp.lock.Lock()
+	defer gp.lock.Unlock()
+
+	env := gp.env
+	if env.Spec.PoolAutoscale == nil || gp.oci != nil || gp.resourceClass != "" ||
+		env.Spec.AllowedFunctionsPerContainer == fv1.AllowedFunctionsPerContainerInfinite ||
+		gp.deployment == nil || gp.deployment.Spec.Replicas == nil {
+		gp.autoscaler.reset()
+		return nil
+	}
+	cfg := env.Spec.PoolAutoscale.Effective()
+	window, err := time.ParseDuration(cfg.Window)
+	if err != nil {
+		return fmt.Errorf("invalid pool autoscale window %q: %w", cfg.Window, err)
+	}
+	delay, err := time.ParseDuration(cfg.ScaleDownDelay)
+	if err != nil {
+		return fmt.Errorf("invalid pool autoscale scale down delay %q: %w", cfg.ScaleDownDelay, err)
+	}
+
+	current := int(*gp.deployment.Spec.Replicas)
+	size, demand := gp.autoscaler.next(cfg, window, delay, current, now)
+	recordPoolAutoscale(ctx, env, size, demand)
+	if size == current {
+		return nil
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, size)
+	depl, err := gp.kubernetesClient.AppsV1().Deployments(gp.fnNamespace).Patch(ctx, gp.deployment.Name,
+		k8sTypes.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error scaling pool deployment %s to %d: %w", gp.deployment.Name, size, err)
+	}
+	gp.deployment = depl
+	recordPoolScale(ctx, env, size > current)
+	gp.logger.Info("scaled pool", "env", env.Name, "namespace", env.Namespace,
+		"deployment", depl.Name, "from", current, "to", size, "demand", demand, "window", window)
+	return nil
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package poolmgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/utils/loggerfactory"
+)
+
+func TestPoolAutoscalerNext(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	cfg := (&fv1.PoolAutoscaleConfig{MinSize: 2, MaxSize: 10}).Effective()
+	const window, delay = time.Minute, 2 * time.Minute
+
+	a := newPoolAutoscaler()
+	size, demand := a.next(cfg, window, delay, 3, start)
+	assert.Equal(t, 3, size, "no demand keeps the pool until the scale down delay")
+	assert.Zero(t, demand)
+
+	// A burst grows the pool at once, by the specializations of the window.
+	for i := range 5 {
+		a.observe(start.Add(time.Duration(i) * time.Second))
+	}
+	now := start.Add(10 * time.Second)
+	size, demand = a.next(cfg, window, delay, 3, now)
+	assert.Equal(t, 5, demand)
+	assert.Equal(t, 6, size, "demand plus the idle target")
+
+	// MaxSize caps a larger burst.
+	for i := range 20 {
+		a.observe(now.Add(time.Duration(i) * 100 * time.Millisecond))
+	}
+	size, _ = a.next(cfg, window, delay, 6, now.Add(5*time.Second))
+	assert.Equal(t, 10, size)
+
+	// Once the burst leaves the window the pool holds for the delay, then
+	// shrinks one pod per interval down to MinSize.
+	quiet := now.Add(window + demandSlotWidth)
+	size, demand = a.next(cfg, window, delay, 10, quiet)
+	assert.Zero(t, demand)
+	assert.Equal(t, 10, size)
+	size, _ = a.next(cfg, window, delay, 10, quiet.Add(delay-time.Second))
+	assert.Equal(t, 10, size)
+	at := quiet.Add(delay)
+	size, _ = a.next(cfg, window, delay, 10, at)
+	assert.Equal(t, 9, size)
+	size, _ = a.next(cfg, window, delay, 9, at.Add(time.Second))
+	assert.Equal(t, 9, size, "one pod per interval")
+	for size > cfg.MinSize {
+		at = at.Add(poolAutoscaleInterval)
+		size, _ = a.next(cfg, window, delay, size, at)
+	}
+	assert.Equal(t, 2, size)
+
+	// New demand cancels a scale down in progress.
+	a.observe(at)
+	size, _ = a.next(cfg, window, delay, 2, at.Add(time.Second))
+	assert.Equal(t, 2, size)
+	assert.True(t, a.lowSince.IsZero())
+}
+
+func TestPoolAutoscalerBounds(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	a := newPoolAutoscaler()
+	cfg := (&fv1.PoolAutoscaleConfig{MinSize: 4, MaxSize: 6}).Effective()
+	size, _ := a.next(cfg, time.Minute, time.Hour, 1, now)
+	assert.Equal(t, 4, size, "MinSize applies without waiting")
+	size, _ = a.next(cfg, time.Minute, time.Hour, 30, now)
+	assert.Equal(t, 6, size, "MaxSize applies without the scale down delay")
+}
+
+func TestGenericPoolAutoscaleOnce(t *testing.T) {
+	env := newTestEnv()
+	env.Spec.Version = 3
+	env.Spec.PoolAutoscale = &fv1.PoolAutoscaleConfig{MaxSize: 5, TargetIdle: 2}
+	depl := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "poolmgr-test", Namespace: "fission-function"},
+		Spec:       appsv1.DeploymentSpec{Replicas: new(getEnvPoolSize(env))},
+	}
+	assert.Equal(t, int32(2), *depl.Spec.Replicas, "an autoscaled pool starts at its idle target")
+
+	gp := &GenericPool{
+		logger:           loggerfactory.GetLogger(),
+		env:              env,
+		deployment:       depl,
+		fnNamespace:      depl.Namespace,
+		kubernetesClient: fake.NewClientset(depl),
+		autoscaler:       newPoolAutoscaler(),
+	}
+	now := time.Now()
+	gp.autoscaler.observe(now)
+	gp.autoscaler.observe(now)
+	require.NoError(t, gp.autoscaleOnce(t.Context(), now))
+	got, err := gp.kubernetesClient.AppsV1().Deployments(depl.Namespace).Get(t.Context(), depl.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), *got.Spec.Replicas)
+	assert.Equal(t, int32(4), *gp.deployment.Spec.Replicas)
+
+	// An environment update keeps the autoscaled size within the new bounds.
+	updated := env.DeepCopy()
+	updated.Spec.PoolAutoscale.MaxSize = 3
+	assert.Equal(t, int32(3), gp.poolSize(updated))
+	updated.Spec.PoolAutoscale = nil
+	updated.Spec.Poolsize = 7
+	assert.Equal(t, int32(7), gp.poolSize(updated))
+
+	// Without PoolAutoscale the pool is left alone.
+	gp.env = updated
+	gp.autoscaler.observe(now)
+	require.NoError(t, gp.autoscaleOnce(t.Context(), now))
+	assert.Equal(t, int32(4), *gp.deployment.Spec.Replicas)
+}
@@ -6,6 +6,8 @@ package poolmgr
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"maps"
 	"path/filepath"
@@ -24,7 +26,8 @@ import (
 )
 
 // getPoolName returns a unique name for a pool's deployment: per environment,
-// plus a short image-hash suffix for per-image pools (RFC-0001 Path B).
+// plus a short hash suffix for per-image pools (RFC-0001 Path B) and resource
+// class pools (see poolNameHash).
 func getPoolName(env *fv1.Environment, imageHash string) string {
 	// TODO: get rid of resource version here
 	var envPodName string
@@ -59,13 +62,57 @@ func (gp *GenericPool) genDeploymentMeta(env *fv1.Environment) metav1.ObjectMeta
 		}
 	}
 	return metav1.ObjectMeta{
-		Name:            getPoolName(env, gp.ociImageHash),
+		Name:            getPoolName(env, gp.poolNameHash()),
 		Labels:          deployLabels,
 		Annotations:     deployAnnotations,
 		OwnerReferences: ownerReferences,
 	}
 }
 
+// poolNameHash is the hash that suffixes the pool's deployment name: the image
+// hash of a per-image pool, or for a resource class pool a hash of the class
+// and image hash, so the class pools of one environment never share a name.
+// Empty for the environment's default plain pool.
+func (gp *GenericPool) poolNameHash() string {
+	if gp.resourceClass == "" {
+		return gp.ociImageHash
+	}
+	h := sha256.Sum256([]byte(gp.resourceClass + "\x00" + gp.ociImageHash))
+	return hex.EncodeToString(h[:])
+}
+
+// poolSize is the replica count of the pool deployment for env. The caller
+// holds gp.lock.
+func (gp *GenericPool) poolSize(env *fv1.Environment) int32 {
+	if env.Spec.AllowedFunctionsPerContainer == fv1.AllowedFunctionsPerContainerInfinite {
+		return 1
+	}
+	if gp.oci != nil {
+		// Per-image pools keep ONE warm pod (RFC-0012): the env's poolsize
+		// multiplies per PACKAGE here (N built packages x poolsize pods,
+		// for both variants), and the kubelet's image cache makes recreation
+		// cheap — warm depth is the generic pool's job, bounded-economics is
+		// this pool's. This is the RFC's Gate C poolsize lever, applied by
+		// default.
+		return 1
+	}
+	if gp.resourceClass != "" {
+		// A class pool has its own size; PoolAutoscale only drives the
+		// environment's default pool.
+		if rc := env.Spec.ResourceClass(gp.resourceClass); rc != nil && rc.Poolsize > 0 {
+			return int32(rc.Poolsize)
+		}
+		return 1
+	}
+	if env.Spec.PoolAutoscale != nil && gp.deployment != nil && gp.deployment.Spec.Replicas != nil {
+		// An environment update keeps the size the autoscaler reached,
+		// within the new bounds.
+		cfg := env.Spec.PoolAutoscale.Effective()
+		return int32(min(max(int(*gp.deployment.Spec.Replicas), cfg.MinSize), cfg.MaxSize))
+	}
+	return getEnvPoolSize(env)
+}
+
 func (gp *GenericPool) genDeploymentSpec(env *fv1.Environment) (*appsv1.DeploymentSpec, error) {
 	deployLabels := gp.getEnvironmentPoolLabels(env)
 	// Use long terminationGracePeriodSeconds for connection draining in case that
@@ -196,20 +243,7 @@ func (gp *GenericPool) genDeploymentSpec(env *fv1.Environment) (*appsv1.Deployme
 
 	pod.Spec = *(util.ApplyImagePullSecret(env.Spec.ImagePullSecret, pod.Spec))
 
-	poolsize := getEnvPoolSize(env)
-	switch env.Spec.AllowedFunctionsPerContainer {
-	case fv1.AllowedFunctionsPerContainerInfinite:
-		poolsize = 1
-	}
-	if gp.oci != nil {
-		// Per-image pools keep ONE warm pod (RFC-0012): the env's poolsize
-		// multiplies per PACKAGE here (N built packages x poolsize pods,
-		// for both variants), and the kubelet's image cache makes recreation
-		// cheap — warm depth is the generic pool's job, bounded-economics is
-		// this pool's. This is the RFC's Gate C poolsize lever, applied by
-		// default.
-		poolsize = 1
-	}
+	poolsize := gp.poolSize(env)
 
 	deploymentSpec := appsv1.DeploymentSpec{
 		// TODO: fix this hardcoded value
@@ -239,6 +273,14 @@ func (gp *GenericPool) genDeploymentSpec(env *fv1.Environment) (*appsv1.Deployme
 		if err != nil {
 			return nil, err
 		}
+		// Path B pods get their code from the kubelet, so only plain pools
+		// fetch deployments through the node-local package cache.
+		if gp.oci == nil {
+			err = gp.fetcherConfig.AddPackageCacheToPodSpec(&deploymentSpec.Template.Spec)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if env.Spec.Runtime.PodSpec != nil {
@@ -254,6 +296,14 @@ func (gp *GenericPool) genDeploymentSpec(env *fv1.Environment) (*appsv1.Deployme
 		deploymentSpec.Template.Spec.AutomountServiceAccountToken = new(false)
 	}
 
+	// The resource class goes on last, so its resources and placement win
+	// over the environment's container and pod spec.
+	if gp.resourceClass != "" {
+		if rc := env.Spec.ResourceClass(gp.resourceClass); rc != nil {
+			applyResourceClass(&deploymentSpec.Template.Spec, mainContainerName, rc)
+		}
+	}
+
 	if gp.oci != nil {
 		// Path B: mount the package image read-only at the fetcher's store
 		// path — the path the load request names (LoadReq.FilePath =
@@ -297,6 +347,27 @@ func (gp *GenericPool) genDeploymentSpec(env *fv1.Environment) (*appsv1.Deployme
 	return &deploymentSpec, nil
 }
 
+// applyResourceClass sets rc's resources on the runtime container, when it has
+// any, merges its node selector and appends its tolerations.
+func applyResourceClass(spec *apiv1.PodSpec, mainContainerName string, rc *fv1.ResourceClass) {
+	if len(rc.Resources.Limits) > 0 || len(rc.Resources.Requests) > 0 || len(rc.Resources.Claims) > 0 {
+		for i := range spec.Containers {
+			if spec.Containers[i].Name == mainContainerName {
+				spec.Containers[i].Resources = *rc.Resources.DeepCopy()
+			}
+		}
+	}
+	if len(rc.NodeSelector) > 0 {
+		if spec.NodeSelector == nil {
+			spec.NodeSelector = make(map[string]string, len(rc.NodeSelector))
+		}
+		maps.Copy(spec.NodeSelector, rc.NodeSelector)
+	}
+	for i := range rc.Tolerations {
+		spec.Tolerations = append(spec.Tolerations, *rc.Tolerations[i].DeepCopy())
+	}
+}
+
 // A pool is a deployment of generic containers for an env.  This
 // creates the pool but doesn't wait for any pods to be ready.
 func (gp *GenericPool) createPoolDeployment(ctx context.Context, env *fv1.Environment) error {
@@ -360,20 +431,7 @@ func (gp *GenericPool) updatePoolDeployment(ctx context.Context, env *fv1.Enviro
 	deployMeta.Name = gp.deployment.Name
 	newDeployment.ObjectMeta = deployMeta
 
-	poolsize := getEnvPoolSize(env)
-	switch env.Spec.AllowedFunctionsPerContainer {
-	case fv1.AllowedFunctionsPerContainerInfinite:
-		poolsize = 1
-	}
-	if gp.oci != nil {
-		// Per-image pools keep ONE warm pod (RFC-0012): the env's poolsize
-		// multiplies per PACKAGE here (N built packages x poolsize pods,
-		// for both variants), and the kubelet's image cache makes recreation
-		// cheap — warm depth is the generic pool's job, bounded-economics is
-		// this pool's. This is the RFC's Gate C poolsize lever, applied by
-		// default.
-		poolsize = 1
-	}
+	poolsize := gp.poolSize(env)
 	newDeployment.Spec.Replicas = &poolsize
 
 	depl, err := gp.kubernetesClient.AppsV1().Deployments(gp.fnNamespace).Update(ctx, newDeployment, metav1.UpdateOptions{})
@@ -5,11 +5,13 @@
 package poolmgr
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
@@ -560,3 +562,101 @@ func TestGenDeploymentSpecOCIFetcherVariant(t *testing.T) {
 	// Pod labels carry the (variant-specific) image hash.
 	assert.Equal(t, gp.ociImageHash, pod.Labels[fv1.POOL_OCI_IMAGE_HASH])
 }
+
+// TestGenDeploymentSpecResourceClass asserts a resource class pool's pods get
+// the class's resources over the environment's, its node selector and
+// tolerations, its pool size and its label, and that the default pool of the
+// same environment keeps the environment's settings.
+func TestGenDeploymentSpecResourceClass(t *testing.T) {
+	t.Parallel()
+	env := newTestEnv()
+	env.Spec.Version = 3
+	env.Spec.Poolsize = 3
+	env.Spec.Resources = apiv1.ResourceRequirements{
+		Limits: apiv1.ResourceList{apiv1.ResourceMemory: resource.MustParse("128Mi")},
+	}
+	env.Spec.Runtime.PodSpec = &apiv1.PodSpec{NodeSelector: map[string]string{"pool": "shared"}}
+	env.Spec.ResourceClasses = []fv1.ResourceClass{{
+		Name:      "big",
+		Poolsize:  2,
+		Resources: apiv1.ResourceRequirements{Limits: apiv1.ResourceList{apiv1.ResourceMemory: resource.MustParse("4Gi")}},
+		NodeSelector: map[string]string{
+			"node.kubernetes.io/instance-type": "highmem",
+		},
+		Tolerations: []apiv1.Toleration{{Key: "highmem", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoSchedule}},
+	}}
+
+	runtimeContainer := func(spec *apiv1.PodSpec) *apiv1.Container {
+		for i := range spec.Containers {
+			if spec.Containers[i].Name == envContainerName {
+				return &spec.Containers[i]
+			}
+		}
+		t.Fatal("runtime container must be present in the deployment spec")
+		return nil
+	}
+
+	gp := newTestGenericPool(t)
+	gp.resourceClass = "big"
+	deploymentSpec, err := gp.genDeploymentSpec(env)
+	require.NoError(t, err)
+	pod := deploymentSpec.Template
+	assert.Equal(t, "4Gi", runtimeContainer(&pod.Spec).Resources.Limits.Memory().String())
+	assert.Equal(t, map[string]string{"pool": "shared", "node.kubernetes.io/instance-type": "highmem"}, pod.Spec.NodeSelector)
+	assert.Equal(t, env.Spec.ResourceClasses[0].Tolerations, pod.Spec.Tolerations)
+	assert.Equal(t, int32(2), *deploymentSpec.Replicas)
+	assert.Equal(t, "big", pod.Labels[fv1.POOL_RESOURCE_CLASS])
+	assert.Equal(t, "big", deploymentSpec.Selector.MatchLabels[fv1.POOL_RESOURCE_CLASS])
+	assert.Empty(t, env.Spec.Runtime.PodSpec.Tolerations, "the class must not leak into the environment")
+
+	plain := newTestGenericPool(t)
+	deploymentSpec, err = plain.genDeploymentSpec(env)
+	require.NoError(t, err)
+	pod = deploymentSpec.Template
+	assert.Equal(t, "128Mi", runtimeContainer(&pod.Spec).Resources.Limits.Memory().String())
+	assert.Equal(t, map[string]string{"pool": "shared"}, pod.Spec.NodeSelector)
+	assert.Empty(t, pod.Spec.Tolerations)
+	assert.Equal(t, int32(3), *deploymentSpec.Replicas)
+	assert.NotContains(t, pod.Labels, fv1.POOL_RESOURCE_CLASS)
+
+	// Class pools get their own deployment names; a plain default pool keeps
+	// the unsuffixed name.
+	assert.Empty(t, plain.poolNameHash())
+	other := &GenericPool{resourceClass: "gpu"}
+	assert.NotEqual(t, gp.poolNameHash(), other.poolNameHash())
+	assert.NotEqual(t, getPoolName(env, gp.poolNameHash()), getPoolName(env, plain.poolNameHash()))
+}
+
+// TestGenDeploymentSpecPackageCache asserts that with FETCHER_PACKAGE_CACHE_DIR
+// set, plain pool pods mount the node-local package cache into the fetcher
+// alone and pass it on the fetcher command line, with the shared volume path
+// still last.
+func TestGenDeploymentSpecPackageCache(t *testing.T) {
+	t.Setenv("FETCHER_PACKAGE_CACHE_DIR", "/var/lib/fission/package-cache")
+	t.Setenv("FETCHER_PACKAGE_CACHE_MAX_SIZE", "1Gi")
+	gp := newTestGenericPool(t)
+
+	deploymentSpec, err := gp.genDeploymentSpec(newTestEnv())
+	require.NoError(t, err)
+	pod := deploymentSpec.Template
+
+	var cacheVolume *apiv1.Volume
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].HostPath != nil {
+			cacheVolume = &pod.Spec.Volumes[i]
+		}
+	}
+	require.NotNil(t, cacheVolume, "the package cache must be a hostPath volume")
+	assert.Equal(t, "/var/lib/fission/package-cache", cacheVolume.HostPath.Path)
+
+	for _, c := range pod.Spec.Containers {
+		mounted := slices.ContainsFunc(c.VolumeMounts, func(m apiv1.VolumeMount) bool { return m.Name == cacheVolume.Name })
+		if c.Name != util.FetcherContainerName {
+			assert.False(t, mounted, "container %s must not see the package cache", c.Name)
+			continue
+		}
+		assert.True(t, mounted, "the fetcher must mount the package cache")
+		assert.Subset(t, c.Command, []string{"-package-cache-dir", "-package-cache-max-bytes", "1073741824"})
+		assert.Equal(t, "/userfunc", c.Command[len(c.Command)-1])
+	}
+}
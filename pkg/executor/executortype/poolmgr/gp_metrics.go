@@ -10,13 +10,58 @@ import (
 	"math"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
 	"github.com/fission/fission/pkg/crd"
 	"github.com/fission/fission/pkg/utils"
+	"github.com/fission/fission/pkg/utils/metrics"
 )
 
+var (
+	// Pool autoscaler decisions (PoolAutoscaleConfig). Only autoscaled
+	// pools report them, so the series stay bounded by the environments
+	// that opted in.
+	poolAutoscaleSize = metrics.Int64Gauge(
+		"fission_executor_pool_autoscale_size",
+		"Warm-pod count the pool autoscaler decided for a generic pool, by environment_name, environment_namespace.",
+	)
+	poolAutoscaleDemand = metrics.Int64Gauge(
+		"fission_executor_pool_autoscale_demand",
+		"Specializations within the pool autoscale window of a generic pool, by environment_name, environment_namespace.",
+	)
+	poolAutoscaleScales = metrics.Int64Counter(
+		"fission_executor_pool_autoscale_scales_total",
+		"Pool size changes made by the pool autoscaler, by environment_name, environment_namespace and direction (up|down).",
+	)
+)
+
+func environmentLabels(env *fv1.Environment) metric.MeasurementOption {
+	return metric.WithAttributes(
+		attribute.String("environment_name", env.Name),
+		attribute.String("environment_namespace", env.Namespace),
+	)
+}
+
+// recordPoolAutoscale records the size the autoscaler decided for env's pool
+// and the demand it followed.
+func recordPoolAutoscale(ctx context.Context, env *fv1.Environment, size, demand int) {
+	poolAutoscaleSize.Record(ctx, int64(size), environmentLabels(env))
+	poolAutoscaleDemand.Record(ctx, int64(demand), environmentLabels(env))
+}
+
+// recordPoolScale counts one size change of env's pool.
+func recordPoolScale(ctx context.Context, env *fv1.Environment, up bool) {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+	poolAutoscaleScales.Add(ctx, 1, environmentLabels(env), metric.WithAttributes(attribute.String("direction", direction)))
+}
+
 func (gp *GenericPool) checkMetricsApi() bool {
 	apiGroups, err := gp.metricsClient.Discovery().ServerGroups()
 	if err != nil {
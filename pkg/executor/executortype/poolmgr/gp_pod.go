@@ -36,6 +36,10 @@ func (gp *GenericPool) getEnvironmentPoolLabels(env *fv1.Environment) map[string
 		// the plain pool's seed/selectors from picking them up.
 		envLabels[fv1.POOL_OCI_IMAGE_HASH] = gp.ociImageHash
 	}
+	if gp.resourceClass != "" {
+		// Same for a resource class pool's warm pods.
+		envLabels[fv1.POOL_RESOURCE_CLASS] = gp.resourceClass
+	}
 	return envLabels
 }
 
@@ -39,6 +39,7 @@ import (
 	"github.com/fission/fission/pkg/cache"
 	"github.com/fission/fission/pkg/crd"
 	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/executor/drain"
 	"github.com/fission/fission/pkg/executor/executortype"
 	"github.com/fission/fission/pkg/executor/fscache"
 	"github.com/fission/fission/pkg/executor/metrics"
@@ -68,9 +69,10 @@ type (
 	GenericPoolManager struct {
 		logger logr.Logger
 
-		// pools is keyed by poolKey(env UID, image hash): the env UID alone
-		// for plain fetcher-based pools, or env UID + "/" + image hash for
-		// per-image image-volume pools (RFC-0001 Path B).
+		// pools is keyed by poolKey(env UID, resource class, image hash):
+		// the env UID alone for plain fetcher-based pools, or env UID + "/"
+		// + image hash for per-image image-volume pools (RFC-0001 Path B),
+		// either of them within a resource class.
 		pools            map[string]*GenericPool
 		kubernetesClient kubernetes.Interface
 		metricsClient    metricsclient.Interface
@@ -88,7 +90,7 @@ type (
 		// informer listers). Set in RegisterReconcilers.
 		crClient client.Client
 
-		// readyPodQueues maps pool key (poolKey(env UID, image hash)) -> a
+		// readyPodQueues maps pool key (see pools) -> a
 		// pool's readyPodQueue, published by the actor on pool create and
 		// removed on destroy. The Pod reconciler reads it lock-free
 		// (sync.Map) to feed warm pods into the right pool's queue.
@@ -128,6 +130,13 @@ type (
 		// functions are addressed via Istio services instead.
 		functionServicesEnabled bool
 
+		// drainer coordinates specialized pod deletion with the router
+		// replicas: the idle reaper and the env-update cleanup delete a pod
+		// once no router has requests in flight on it, with the pod's grace
+		// as the upper bound. Set only with function Services on, since the
+		// routers' per-pod accounting lives in the slice-fed index.
+		drainer idle.Drainer
+
 		// fnSvcEnsured debounces ensureFunctionService per (function UID,
 		// version) -- see fnSvcKey: map[fnSvcKey]time.Time keyed on the
 		// struct (uid, versionLabel) pair (unversioned functions get
@@ -157,6 +166,12 @@ type (
 		requestType
 		ctx context.Context
 		env *fv1.Environment
+		// resourceClass selects one of env's resource class pools; empty
+		// selects its default pools.
+		resourceClass string
+		// keep, on CLEANUP_POOL, spares the env's pools it returns true
+		// for; nil destroys them all.
+		keep func(*GenericPool) bool
 		// oci selects a per-image image-volume pool (RFC-0001 Path B),
 		// including its pod-spec variant (RFC-0012 B-fetcher);
 		// nil selects the env's plain fetcher-based pool.
@@ -222,6 +237,9 @@ func MakeGenericPoolManager(ctx context.Context,
 		maxPendingSpecializations:  maxPendingSpecializationsFromEnv(gpmLogger),
 		functionServicesEnabled:    functionServicesEnabled() && !enableIstio,
 	}
+	if gpm.functionServicesEnabled {
+		gpm.drainer = drain.NewCoordinator(gpmLogger)
+	}
 
 	gpm.logger.V(1).Info("inside MakeGenericPoolManager")
 
@@ -310,7 +328,16 @@ func (gpm *GenericPoolManager) GetFuncSvc(ctx context.Context, fn *fv1.Function)
 		}
 	}
 
-	pool, created, err := gpm.getPool(ctx, env, oci)
+	// A function picking a resource class runs in that class's pool. The
+	// class must still exist: a pool made for an undeclared class would get
+	// the environment's resources, not what the function asked for.
+	if rc := fn.Spec.ResourceClass; rc != "" && env.Spec.ResourceClass(rc) == nil {
+		fErr = ferror.MakeError(ferror.ErrorInvalidArgument,
+			fmt.Sprintf("environment %s has no resource class %s", env.Name, rc))
+		return nil, fErr
+	}
+
+	pool, created, err := gpm.getPool(ctx, env, fn.Spec.ResourceClass, oci)
 	if err != nil {
 		fErr = err
 		return nil, fErr
@@ -416,6 +443,20 @@ func (gpm *GenericPoolManager) TapService(ctx context.Context, svcHost string) e
 	return nil
 }
 
+// ActiveRequests returns the function's in-flight requests the pool cache
+// accounts for: the provisioner's auto mode samples it.
+func (gpm *GenericPoolManager) ActiveRequests(uid k8sTypes.UID) int {
+	return gpm.fsCache.ActiveRequests(uid)
+}
+
+// ObserveConcurrency forwards the in-flight requests a router reported in
+// its taps to the provisioner's auto mode.
+func (gpm *GenericPoolManager) ObserveConcurrency(fnMeta *metav1.ObjectMeta, source string, inflight int) {
+	if gpm.provisioner != nil {
+		gpm.provisioner.ObserveConcurrency(fnMeta.UID, source, inflight)
+	}
+}
+
 func (gpm *GenericPoolManager) MarkSpecializationFailure(ctx context.Context, fnMeta *metav1.ObjectMeta) {
 	key := crd.CacheKeyUGFromMeta(fnMeta)
 	otelUtils.SpanTrackEvent(ctx, "MarkSpecializationFailure",
@@ -522,7 +563,7 @@ func (gpm *GenericPoolManager) refreshFuncPodsFiltered(ctx context.Context, logg
 		return err
 	}
 
-	gp, created, err := gpm.getPool(ctx, env, nil)
+	gp, created, err := gpm.getPool(ctx, env, "", nil)
 	if err != nil {
 		return err
 	}
@@ -612,8 +653,8 @@ func (gpm *GenericPoolManager) adoptFunctionServices(ctx context.Context, wg *sy
 }
 
 // adoptPools re-creates (and thereby re-stamps) each environment's plain warm
-// pool, and returns the env map keyed by namespace/name for the specialized-pod
-// adoption pass.
+// pool and resource class pools, and returns the env map keyed by
+// namespace/name for the specialized-pod adoption pass.
 func (gpm *GenericPoolManager) adoptPools(ctx context.Context, wg *sync.WaitGroup) map[string]fv1.Environment {
 	envMap := make(map[string]fv1.Environment)
 
@@ -634,7 +675,7 @@ func (gpm *GenericPoolManager) adoptPools(ctx context.Context, wg *sync.WaitGrou
 
 			if getEnvPoolSize(&env) > 0 {
 				wg.Go(func() {
-					_, created, err := gpm.getPool(ctx, &env, nil)
+					_, created, err := gpm.getPool(ctx, &env, "", nil)
 					if err != nil {
 						gpm.logger.Error(err, "adopt pool failed")
 					}
@@ -643,6 +684,13 @@ func (gpm *GenericPoolManager) adoptPools(ctx context.Context, wg *sync.WaitGrou
 					}
 				})
 			}
+			if len(env.Spec.ResourceClasses) > 0 {
+				wg.Go(func() {
+					if err := gpm.ensureClassPools(ctx, &env); err != nil {
+						gpm.logger.Error(err, "adopt resource class pools failed")
+					}
+				})
+			}
 
 			// create environment map for later use
 			key := k8sCache.MetaObjectToName(&env.ObjectMeta).String()
@@ -954,7 +1002,8 @@ func (gpm *GenericPoolManager) service() {
 }
 
 // handleGetPool returns the env's pool (plain, or per-image when req.oci is
-// set), creating and seeding it on first use.
+// set, in req.resourceClass's pools when set), creating and seeding it on
+// first use.
 func (gpm *GenericPoolManager) handleGetPool(req *request) {
 	// just because they are missing in the cache, we end up creating another duplicate pool.
 	var err error
@@ -963,7 +1012,7 @@ func (gpm *GenericPoolManager) handleGetPool(req *request) {
 	if req.oci != nil {
 		imageHash = ociPoolHash(req.oci)
 	}
-	key := poolKey(req.env.UID, imageHash)
+	key := poolKey(req.env.UID, req.resourceClass, imageHash)
 	pool, ok := gpm.pools[key]
 	if !ok {
 		// To support backward compatibility, if envs are created in default ns, we go ahead
@@ -971,8 +1020,8 @@ func (gpm *GenericPoolManager) handleGetPool(req *request) {
 		ns := gpm.nsResolver.GetFunctionNS(req.env.Namespace)
 		pool = MakeGenericPool(gpm.logger, gpm.fissionClient, gpm.kubernetesClient,
 			gpm.metricsClient, req.env, ns, gpm.fsCache,
-			gpm.fetcherConfig, gpm.instanceID, gpm.enableIstio, gpm.podSpecPatch, gpm.crClient, req.oci,
-			gpm.podReadyTimeout)
+			gpm.fetcherConfig, gpm.instanceID, gpm.enableIstio, gpm.podSpecPatch, gpm.crClient,
+			req.resourceClass, req.oci, gpm.podReadyTimeout)
 		err = pool.setup(req.ctx)
 		if err != nil {
 			req.responseChannel <- &response{error: err}
@@ -990,7 +1039,7 @@ func (gpm *GenericPoolManager) handleGetPool(req *request) {
 		// work, so it must not ride the request context: if the triggering
 		// request is cancelled here the pool would stay published-but-unseeded
 		// and later callers find the existing pool and skip the seed.
-		gpm.seedReadyPodQueue(context.Background(), req.env, imageHash, pool.readyPodQueue)
+		gpm.seedReadyPodQueue(context.Background(), req.env, req.resourceClass, imageHash, pool.readyPodQueue)
 		created = true
 	}
 	// Touch the pool's activity clock (every specialization starts with a
@@ -1001,7 +1050,8 @@ func (gpm *GenericPoolManager) handleGetPool(req *request) {
 }
 
 // handleCleanupPool destroys every pool an env owns: its plain pool plus any
-// per-image pools (RFC-0001 Path B). The caller doesn't wait for a response.
+// per-image (RFC-0001 Path B) and resource class pools, except those req.keep
+// spares. The caller doesn't wait for a response.
 func (gpm *GenericPoolManager) handleCleanupPool(req *request) {
 	env := *req.env
 	gpm.logger.Info("destroying pools",
@@ -1013,6 +1063,9 @@ func (gpm *GenericPoolManager) handleCleanupPool(req *request) {
 		if !envPoolKeyPrefixMatch(key, req.env.UID) {
 			continue
 		}
+		if req.keep != nil && pool != nil && req.keep(pool) {
+			continue
+		}
 		found = true
 		delete(gpm.pools, key)
 		gpm.readyPodQueues.Delete(key)
@@ -1031,7 +1084,8 @@ func (gpm *GenericPoolManager) handleCleanupPool(req *request) {
 	}
 }
 
-// handleGetEnvPools answers with every live pool of an env (plain + per-image).
+// handleGetEnvPools answers with every live pool of an env (plain, per-image
+// and resource class).
 func (gpm *GenericPoolManager) handleGetEnvPools(req *request) {
 	pools := make([]*GenericPool, 0, 1)
 	for key, pool := range gpm.pools {
@@ -1042,13 +1096,14 @@ func (gpm *GenericPoolManager) handleGetEnvPools(req *request) {
 	req.responseChannel <- &response{pools: pools}
 }
 
-func (gpm *GenericPoolManager) getPool(ctx context.Context, env *fv1.Environment, oci *ociPoolSpec) (*GenericPool, bool, error) {
+func (gpm *GenericPoolManager) getPool(ctx context.Context, env *fv1.Environment, resourceClass string, oci *ociPoolSpec) (*GenericPool, bool, error) {
 	otelUtils.SpanTrackEvent(ctx, "getPool", otelUtils.GetAttributesForEnv(env)...)
 	c := make(chan *response)
 	gpm.requestChannel <- &request{
 		ctx:             ctx,
 		requestType:     GET_POOL,
 		env:             env,
+		resourceClass:   resourceClass,
 		oci:             oci,
 		responseChannel: c,
 	}
@@ -1057,7 +1112,7 @@ func (gpm *GenericPoolManager) getPool(ctx context.Context, env *fv1.Environment
 }
 
 // getEnvPools returns every live pool of an env: the plain pool plus any
-// per-image pools (RFC-0001 Path B).
+// per-image (RFC-0001 Path B) and resource class pools.
 func (gpm *GenericPoolManager) getEnvPools(ctx context.Context, env *fv1.Environment) []*GenericPool {
 	c := make(chan *response)
 	gpm.requestChannel <- &request{
@@ -1079,6 +1134,37 @@ func (gpm *GenericPoolManager) cleanupPool(ctx context.Context, env *fv1.Environ
 	}
 }
 
+// cleanupStaleClassPools destroys the pools of resource classes env no longer
+// declares.
+func (gpm *GenericPoolManager) cleanupStaleClassPools(ctx context.Context, env *fv1.Environment) {
+	gpm.requestChannel <- &request{
+		ctx:         ctx,
+		requestType: CLEANUP_POOL,
+		env:         env,
+		keep: func(pool *GenericPool) bool {
+			return pool.resourceClass == "" || env.Spec.ResourceClass(pool.resourceClass) != nil
+		},
+	}
+}
+
+// ensureClassPools gets the plain pool of each of env's resource classes,
+// creating the ones that do not exist yet, so a class keeps warm pods before
+// its first function is invoked.
+func (gpm *GenericPoolManager) ensureClassPools(ctx context.Context, env *fv1.Environment) error {
+	var errs error
+	for _, rc := range env.Spec.ResourceClasses {
+		_, created, err := gpm.getPool(ctx, env, rc.Name, nil)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if created {
+			gpm.logger.Info("created resource class pool for the environment", "env", env.Name, "namespace", env.Namespace, "resourceClass", rc.Name)
+		}
+	}
+	return errs
+}
+
 // markFuncDeleted marks a function's pool service entries deleted in the fsCache
 // so the idle reaper recycles its specialized pods. Driven by the Function
 // reconciler on delete.
@@ -1128,36 +1214,49 @@ func (gpm *GenericPoolManager) enqueueReadyPod(queueKey, podKey string) {
 	}
 }
 
+// poolPodSelector narrows set to the pods of one pool: per-image pools select
+// on their POOL_OCI_IMAGE_HASH label and resource class pools on their
+// POOL_RESOURCE_CLASS label, and a pool without either requires the label to
+// be absent.
+func poolPodSelector(set labels.Set, resourceClass, imageHash string) (labels.Selector, error) {
+	selector := labels.SelectorFromSet(set)
+	for key, value := range map[string]string{
+		fv1.POOL_OCI_IMAGE_HASH: imageHash,
+		fv1.POOL_RESOURCE_CLASS: resourceClass,
+	} {
+		var req *labels.Requirement
+		var err error
+		if value != "" {
+			req, err = labels.NewRequirement(key, selection.Equals, []string{value})
+		} else {
+			req, err = labels.NewRequirement(key, selection.DoesNotExist, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*req)
+	}
+	return selector, nil
+}
+
 // seedReadyPodQueue enqueues an environment's already-Running warm pods into a
 // freshly-published readyPodQueue, so choosePod isn't left blocked on an empty
 // queue when pods were Running before the queue existed (executor restart, or
 // adopting an existing pool deployment). The readyPodQueue dedups, so any overlap
-// with the reconciler's own events is harmless. imageHash scopes the seed to
-// one pool's pods: per-image pools select on their POOL_OCI_IMAGE_HASH label;
-// the plain pool requires the label to be absent, so it never steals an
-// image-volume pod (which has no fetcher and must not serve fetcher-path
-// functions).
-func (gpm *GenericPoolManager) seedReadyPodQueue(ctx context.Context, env *fv1.Environment, imageHash string, queue workqueue.TypedDelayingInterface[string]) {
+// with the reconciler's own events is harmless. resourceClass and imageHash
+// scope the seed to one pool's pods (see poolPodSelector), so the plain pool
+// never steals an image-volume pod (which has no fetcher and must not serve
+// fetcher-path functions) nor a pod sized for another resource class.
+func (gpm *GenericPoolManager) seedReadyPodQueue(ctx context.Context, env *fv1.Environment, resourceClass, imageHash string, queue workqueue.TypedDelayingInterface[string]) {
 	ns := gpm.nsResolver.GetFunctionNS(env.Namespace)
-	selector := labels.SelectorFromSet(labels.Set{
+	selector, err := poolPodSelector(labels.Set{
 		fv1.EXECUTOR_TYPE:   string(fv1.ExecutorTypePoolmgr),
 		fv1.ENVIRONMENT_UID: string(env.UID),
 		"managed":           "true",
-	})
-	if imageHash != "" {
-		req, err := labels.NewRequirement(fv1.POOL_OCI_IMAGE_HASH, selection.Equals, []string{imageHash})
-		if err != nil {
-			gpm.logger.Error(err, "failed to build image-hash selector", "env", env.Name)
-			return
-		}
-		selector = selector.Add(*req)
-	} else {
-		req, err := labels.NewRequirement(fv1.POOL_OCI_IMAGE_HASH, selection.DoesNotExist, nil)
-		if err != nil {
-			gpm.logger.Error(err, "failed to build image-hash selector", "env", env.Name)
-			return
-		}
-		selector = selector.Add(*req)
+	}, resourceClass, imageHash)
+	if err != nil {
+		gpm.logger.Error(err, "failed to build pool pod selector", "env", env.Name)
+		return
 	}
 	podList := &apiv1.PodList{}
 	if err := gpm.crClient.List(ctx, podList, client.InNamespace(ns),
@@ -1179,29 +1278,35 @@ func (gpm *GenericPoolManager) seedReadyPodQueue(ctx context.Context, env *fv1.E
 }
 
 // reconcileEnvPool brings an environment's warm pools to their desired state:
-// ensure the plain pool exists, destroy every pool if the pool size is zero,
-// otherwise update each pool deployment (the plain pool plus any per-image
-// Path B pools). Driven by the Environment reconciler on create/update
-// (replacing poolpodcontroller's envCreateUpdateQueue handler).
+// ensure the plain pool and each resource class pool exist, destroy every pool
+// if the pool size is zero and no class is declared, otherwise destroy the
+// pools of removed classes and update each remaining pool deployment (the
+// plain pool plus any per-image Path B and resource class pools). Driven by
+// the Environment reconciler on create/update (replacing poolpodcontroller's
+// envCreateUpdateQueue handler).
 func (gpm *GenericPoolManager) reconcileEnvPool(ctx context.Context, env *fv1.Environment) error {
 	log := gpm.logger.WithValues("env", env.Name, "namespace", env.Namespace)
-	_, created, err := gpm.getPool(ctx, env, nil)
+	_, created, err := gpm.getPool(ctx, env, "", nil)
 	if err != nil {
 		return err
 	}
 	if created {
 		log.Info("created pool for the environment")
-		return nil
+		return gpm.ensureClassPools(ctx, env)
 	}
-	if getEnvPoolSize(env) == 0 {
+	if getEnvPoolSize(env) == 0 && len(env.Spec.ResourceClasses) == 0 {
 		log.Info("pool size is zero, cleaning up pool")
 		gpm.cleanupPool(ctx, env)
 		return nil
 	}
+	// Serialized on the actor ahead of getEnvPools, so a removed class's
+	// pools are gone before the update pass.
+	gpm.cleanupStaleClassPools(ctx, env)
 	var errs error
 	for _, pool := range gpm.getEnvPools(ctx, env) {
 		errs = errors.Join(errs, pool.updatePoolDeployment(ctx, env))
 	}
+	errs = errors.Join(errs, gpm.ensureClassPools(ctx, env))
 	if errs != nil {
 		return errs
 	}
@@ -1272,7 +1377,7 @@ func (gpm *GenericPoolManager) getFunctionEnv(ctx context.Context, fn *fv1.Funct
 // pods), run by the shared idle reaper.
 func (gpm *GenericPoolManager) IdleStrategy() idle.Strategy {
 	return idle.NewPoolDeleteStrategy(gpm.logger, gpm.fissionClient, gpm.fsCache, gpm.kubernetesClient,
-		gpm.defaultIdlePodReapTime, gpm.objectReaperIntervalSecond, gpm.functionServicesEnabled, gpm.retainedFn)
+		gpm.defaultIdlePodReapTime, gpm.objectReaperIntervalSecond, gpm.functionServicesEnabled, gpm.drainer, gpm.retainedFn)
 }
 
 // SetVersionRetain wires the RFC-0025 alias-retain view's Retained method (or
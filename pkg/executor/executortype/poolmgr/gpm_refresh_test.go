@@ -92,7 +92,7 @@ func newRefreshFixture(t *testing.T) *refreshTestFixture {
 		fissionClient:    fClient.NewSimpleClientset(env),
 		fsCache:          fsCache,
 		nsResolver:       utils.DefaultNSResolver(),
-		pools:            map[string]*GenericPool{poolKey(env.UID, ""): gp},
+		pools:            map[string]*GenericPool{poolKey(env.UID, "", ""): gp},
 		requestChannel:   make(chan *request),
 	}
 	// The pool actor: getPool answers through this channel. The goroutine
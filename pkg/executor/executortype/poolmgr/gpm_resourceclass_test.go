@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package poolmgr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// TestResourceClassPoolLifecycle: a class pool is a pool of its own, with its
+// own key, deployment and label, and goes away with its class while the
+// environment's default pool stays.
+func TestResourceClassPoolLifecycle(t *testing.T) {
+	gpm := newReaperGpm(t)
+	env := &fv1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: metav1.NamespaceDefault, UID: "env-uid", ResourceVersion: "1"},
+		Spec: fv1.EnvironmentSpec{
+			Version:         3,
+			Runtime:         fv1.Runtime{Image: "fission/test-env:latest"},
+			ResourceClasses: []fv1.ResourceClass{{Name: "big"}},
+		},
+	}
+	getPool := func(class string) *GenericPool {
+		respC := make(chan *response, 1)
+		gpm.handleGetPool(&request{ctx: t.Context(), env: env, resourceClass: class, responseChannel: respC})
+		resp := <-respC
+		require.NoError(t, resp.error)
+		require.True(t, resp.created)
+		return resp.pool
+	}
+	plain := getPool("")
+	big := getPool("big")
+	require.Contains(t, gpm.pools, poolKey(env.UID, "", ""))
+	require.Contains(t, gpm.pools, poolKey(env.UID, "big", ""))
+	assert.NotEqual(t, plain.deployment.Name, big.deployment.Name)
+	assert.Equal(t, "big", big.deployment.Labels[fv1.POOL_RESOURCE_CLASS])
+	assert.Equal(t, int32(1), *big.deployment.Spec.Replicas, "a class pool defaults to one warm pod")
+
+	// The environment drops the class: only its pools are destroyed.
+	updated := env.DeepCopy()
+	updated.Spec.ResourceClasses = nil
+	updated.ResourceVersion = "2"
+	gpm.handleCleanupPool(&request{
+		ctx: t.Context(),
+		env: updated,
+		keep: func(pool *GenericPool) bool {
+			return pool.resourceClass == "" || updated.Spec.ResourceClass(pool.resourceClass) != nil
+		},
+	})
+	assert.Contains(t, gpm.pools, poolKey(env.UID, "", ""))
+	assert.NotContains(t, gpm.pools, poolKey(env.UID, "big", ""))
+	_, err := gpm.kubernetesClient.AppsV1().Deployments(big.fnNamespace).Get(t.Context(), big.deployment.Name, metav1.GetOptions{})
+	assert.Error(t, err, "the class pool's deployment must be deleted")
+	_, err = gpm.kubernetesClient.AppsV1().Deployments(plain.fnNamespace).Get(t.Context(), plain.deployment.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+}
@@ -63,16 +63,22 @@ func (gp *GenericPool) hasFetcher() bool {
 
 // poolKey identifies a pool: the env UID alone for plain pools — byte-for-byte
 // the pre-Path-B key, so non-OCI behavior is unchanged — or env UID + "/" +
-// image hash for per-image pools.
-func poolKey(envUID k8sTypes.UID, imageHash string) string {
-	if imageHash == "" {
-		return string(envUID)
+// image hash for per-image pools. A resource class pool inserts "/class:" +
+// class after the env UID; the colon never appears in an image hash, so class
+// and per-image keys cannot collide.
+func poolKey(envUID k8sTypes.UID, resourceClass, imageHash string) string {
+	key := string(envUID)
+	if resourceClass != "" {
+		key += "/class:" + resourceClass
 	}
-	return string(envUID) + "/" + imageHash
+	if imageHash != "" {
+		key += "/" + imageHash
+	}
+	return key
 }
 
 // envPoolKeyPrefixMatch reports whether key belongs to env: its plain pool or
-// any of its per-image pools.
+// any of its per-image or resource class pools.
 func envPoolKeyPrefixMatch(key string, envUID k8sTypes.UID) bool {
 	return key == string(envUID) || strings.HasPrefix(key, string(envUID)+"/")
 }
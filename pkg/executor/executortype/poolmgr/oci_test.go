@@ -24,8 +24,14 @@ func TestPoolKeyEmptyImageMatchesEnvUID(t *testing.T) {
 	// Backward-compat guard: for non-OCI pools the key must be byte-for-byte
 	// the env UID, exactly as before per-image pools existed.
 	uid := k8sTypes.UID("0fdd76f6-b2b5-4b3e-8a52-6c3ca60aa006")
-	assert.Equal(t, string(uid), poolKey(uid, ""))
-	assert.Equal(t, string(uid)+"/abc123", poolKey(uid, "abc123"))
+	assert.Equal(t, string(uid), poolKey(uid, "", ""))
+	assert.Equal(t, string(uid)+"/abc123", poolKey(uid, "", "abc123"))
+	// Resource class pools, plain and per-image, stay within the env's keys
+	// and never collide with a per-image key.
+	assert.Equal(t, string(uid)+"/class:big", poolKey(uid, "big", ""))
+	assert.Equal(t, string(uid)+"/class:big/abc123", poolKey(uid, "big", "abc123"))
+	assert.NotEqual(t, poolKey(uid, "", "abc123"), poolKey(uid, "abc123", ""))
+	assert.True(t, envPoolKeyPrefixMatch(poolKey(uid, "big", "abc123"), uid))
 }
 
 func TestOCIPoolHashStable(t *testing.T) {
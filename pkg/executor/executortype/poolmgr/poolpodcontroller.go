@@ -14,6 +14,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sTypes "k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -25,7 +26,9 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/executor/drain"
 	"github.com/fission/fission/pkg/executor/fscache"
+	"github.com/fission/fission/pkg/executor/reaper"
 	"github.com/fission/fission/pkg/utils"
 )
 
@@ -312,6 +315,14 @@ func (p *PoolPodController) spCleanupPodQueueProcessFunc(ctx context.Context) bo
 			p.logger.Error(nil, "could not convert item from PodToFsvc", "key", key)
 		}
 	}
+	if p.gpm.drainer != nil {
+		// The pod is out of the fsCache, so the executor hands it out no
+		// more; drain it off the queue worker, since the wait can take up to
+		// the pod's termination grace.
+		p.spCleanupPodQueue.Forget(key)
+		go p.drainAndDeletePod(pod)
+		return false
+	}
 	err = p.kubernetesClient.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 	if err != nil {
 		p.logger.Error(err, "failed to delete pod", "pod", name, "pod_namespace", namespace)
@@ -323,3 +334,37 @@ func (p *PoolPodController) spCleanupPodQueueProcessFunc(ctx context.Context) bo
 	p.spCleanupPodQueue.Forget(key)
 	return false
 }
+
+// drainAndDeletePod deletes a specialized pod of an updated or deleted
+// environment once no router replica has requests in flight on it, waiting at
+// most the pod's termination grace period. A confirmed drain deletes with the
+// short drained grace; otherwise the pod keeps its own, as before.
+func (p *PoolPodController) drainAndDeletePod(pod *v1.Pod) {
+	bound := time.Duration(fv1.DefaultTerminationGracePeriod) * time.Second
+	if g := pod.Spec.TerminationGracePeriodSeconds; g != nil {
+		bound = time.Duration(*g) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), bound+time.Minute)
+	defer cancel()
+	req := drain.Request{
+		Namespace:   pod.Labels[fv1.FUNCTION_NAMESPACE],
+		Function:    pod.Labels[fv1.FUNCTION_NAME],
+		FunctionUID: k8sTypes.UID(pod.Labels[fv1.FUNCTION_UID]),
+		Version:     pod.Labels[fv1.FUNCTION_VERSION],
+		PodUID:      pod.UID,
+	}
+	drained := p.gpm.drainer.Drain(ctx, req, bound)
+	var err error
+	if drained {
+		err = reaper.DeleteDrainedPod(ctx, p.kubernetesClient, pod.Namespace, pod.Name)
+	} else {
+		err = p.kubernetesClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		p.logger.Error(err, "failed to delete pod", "pod", pod.Name, "pod_namespace", pod.Namespace)
+		return
+	}
+	p.logger.Info("cleaned specialized pod as environment update/deleted",
+		"pod", pod.Name, "pod_namespace", pod.Namespace,
+		"address", pod.Status.PodIP, "drained", drained)
+}
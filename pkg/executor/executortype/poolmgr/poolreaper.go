@@ -13,7 +13,6 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	fv1 "github.com/fission/fission/pkg/apis/core/v1"
 	"github.com/fission/fission/pkg/executor/metrics"
 	"github.com/fission/fission/pkg/utils"
 )
@@ -146,10 +145,14 @@ func (gpm *GenericPoolManager) handleReapIdlePools(req *request) {
 // (managed=true) belong to the deployment and die with it; specialized pods
 // are serving a function and pin the pool. Cache-backed read.
 func (gpm *GenericPoolManager) poolHasSpecializedPods(ctx context.Context, pool *GenericPool) (bool, error) {
+	selector, err := poolPodSelector(nil, pool.resourceClass, pool.ociImageHash)
+	if err != nil {
+		return false, err
+	}
 	var podList apiv1.PodList
 	if err := gpm.crClient.List(ctx, &podList,
 		client.InNamespace(pool.fnNamespace),
-		client.MatchingLabels{fv1.POOL_OCI_IMAGE_HASH: pool.ociImageHash}); err != nil {
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
 		return false, err
 	}
 	for i := range podList.Items {
@@ -74,7 +74,7 @@ func addTestPool(t *testing.T, gpm *GenericPoolManager, envName, imageHash strin
 		podReadyTimeout:  time.Minute,
 	}
 	pool.lastActive.Store(time.Now().Add(-idleFor).UnixNano())
-	key := poolKey(env.UID, imageHash)
+	key := poolKey(env.UID, "", imageHash)
 	gpm.pools[key] = pool
 	gpm.readyPodQueues.Store(key, pool.readyPodQueue)
 	return key
@@ -41,6 +41,7 @@ type funcSvcGetter interface {
 	GetFuncSvc(ctx context.Context, fn *fv1.Function) (*fscache.FuncSvc, error)
 	UnTapService(ctx context.Context, fnMeta *metav1.ObjectMeta, svcHost string)
 	MarkSpecializationFailure(ctx context.Context, fnMeta *metav1.ObjectMeta)
+	ActiveRequests(uid types.UID) int
 }
 
 // ProvisionerConfig configures the RFC-0026 provisioner.
@@ -67,8 +68,9 @@ type ProvisionerConfig struct {
 // Provisioner maintains warm specialized pods for functions that opt into
 // ProvisionedConcurrency (RFC-0026). It runs a periodic reconcile loop that
 // for each opted-in function:
-//  1. Computes the effective target (base Target only in PR 1; schedule
-//     windows arrive in PR 2).
+//  1. Computes the effective target: base Target, overridden by an active
+//     schedule window, or derived from observed concurrency in auto mode
+//     (provisioner_auto.go).
 //  2. Counts ready provisioned pods (Kubernetes API list, not fsCache —
 //     see countProvisionedPods below).
 //  3. If below target: eagerly specializes delta pods via gpm.GetFuncSvc,
@@ -111,6 +113,9 @@ type Provisioner struct {
 	reconcileLocks sync.Map // map[types.UID]*sync.Mutex
 
 	timers sync.Map // map[types.UID]*fnSchedule
+
+	// demand holds the observed concurrency of functions in auto mode.
+	demand sync.Map // map[types.UID]*demandHistory
 }
 
 // lockFor returns the per-function reconcile lock for fnUID, creating it on
@@ -264,6 +269,7 @@ func NewProvisioner(
 		inflight:         sync.Map{},
 		reconcileLocks:   sync.Map{},
 		timers:           sync.Map{},
+		demand:           sync.Map{},
 	}
 }
 
@@ -346,10 +352,10 @@ func (p *Provisioner) reconcileFunction(ctx context.Context, fn *fv1.Function) {
 // reconcileFunctionLocked is the body of reconcileFunction. The caller
 // MUST hold the per-function reconcile lock (lockFor(fn.UID)).
 func (p *Provisioner) reconcileFunctionLocked(ctx context.Context, fn *fv1.Function) {
-	target := p.effectiveTarget(fn)
+	target, specTarget, reason := p.effectiveTarget(fn)
 	p.armTransition(fn)
 	if target == 0 {
-		p.disableProvisioningLocked(ctx, fn)
+		p.disableProvisioningLocked(ctx, fn, reason)
 		return
 	}
 
@@ -369,8 +375,7 @@ func (p *Provisioner) reconcileFunctionLocked(ctx context.Context, fn *fv1.Funct
 	// Publish observed status on every pass so ProvisionedReady and the
 	// Provisioned=Warming condition surface during warm-up/drain, not only
 	// once ready == target.
-	specTarget := fn.Spec.ProvisionedConcurrency.Target
-	if err := p.updateFunctionStatus(ctx, fn, ready, target, specTarget); err != nil {
+	if err := p.updateFunctionStatus(ctx, fn, ready, target, specTarget, reason); err != nil {
 		p.logger.Error(err, "Unable to update status of the function", "function", fn.Name, "namespace", fn.Namespace, "ready", ready, "target", target)
 	}
 }
@@ -549,17 +554,20 @@ func (p *Provisioner) countProvisionedPods(ctx context.Context, fn *fv1.Function
 	return len(readyAndRunningPods), nil
 }
 
-func statusSet(latestFunc *fv1.Function, ready, target, specTarget int) {
+func statusSet(latestFunc *fv1.Function, ready, target, specTarget int, reason string) {
 	latestFunc.Status.ProvisionedReady = ready
 	latestFunc.Status.ProvisionedTarget = target
 	latestFunc.Status.ProvisionedSpecTarget = specTarget
+	latestFunc.Status.ProvisionedTargetReason = reason
 	if target == 0 {
 		// Provisioned concurrency is off (target=0): condition False,
-		// regardless of ready count (which should also be 0).
+		// regardless of ready count (which should also be 0). In auto mode
+		// the message says why the derived target is 0.
 		meta.SetStatusCondition(&latestFunc.Status.Conditions, metav1.Condition{
 			Type:               fv1.FunctionConditionProvisioned,
 			Status:             metav1.ConditionFalse,
 			Reason:             fv1.FunctionReasonProvisionedDisabled,
+			Message:            reason,
 			ObservedGeneration: latestFunc.Generation,
 		})
 		return
@@ -602,7 +610,7 @@ func reasonForProvisioned(clamped bool, base string) string {
 	return base
 }
 
-func (p *Provisioner) updateFunctionStatus(ctx context.Context, fn *fv1.Function, ready, target, specTarget int) error {
+func (p *Provisioner) updateFunctionStatus(ctx context.Context, fn *fv1.Function, ready, target, specTarget int, reason string) error {
 	metrics.RecordProvisionedTarget(ctx, fn.Name, fn.Namespace, int64(target))
 	metrics.RecordProvisionedReady(ctx, fn.Name, fn.Namespace, int64(ready))
 	backoff := retry.DefaultRetry
@@ -611,24 +619,49 @@ func (p *Provisioner) updateFunctionStatus(ctx context.Context, fn *fv1.Function
 		if err != nil {
 			return err
 		}
-		statusSet(latestFunc, ready, target, specTarget)
+		statusSet(latestFunc, ready, target, specTarget, reason)
 		_, err = p.fissionClient.CoreV1().Functions(fn.Namespace).UpdateStatus(ctx, latestFunc, metav1.UpdateOptions{})
 		return err
 	})
 }
 
 // effectiveTarget resolves the function's effective provisioned target via
-// effectiveTargetAt (base Target, overridden by any active schedule window),
-// logs any windows that failed to evaluate, and clamps to the namespace-wide
-// MaxPerFunction cap.
-func (p *Provisioner) effectiveTarget(fn *fv1.Function) int {
-	target, errs := effectiveTargetAt(fn.Spec.ProvisionedConcurrency, time.Now())
+// windowTargetAt (base Target, overridden by any active schedule window) or,
+// in auto mode outside any window, autoTarget; logs any windows that failed
+// to evaluate, and clamps to the namespace-wide MaxPerFunction cap.
+// specTarget is the target before the clamp (the base Target unless auto
+// derived one) and reason, set in auto mode only, explains the target.
+func (p *Provisioner) effectiveTarget(fn *fv1.Function) (target, specTarget int, reason string) {
+	pc := fn.Spec.ProvisionedConcurrency
+	now := time.Now()
+	target, windowActive, errs := windowTargetAt(pc, now)
 	if len(errs) > 0 {
 		for _, e := range errs {
 			p.logger.Error(e, "provisioned concurrency window evaluation failed", "function", fn.Name, "namespace", fn.Namespace)
 		}
 	}
-	return min(target, p.config.MaxPerFunction)
+	if pc == nil {
+		p.demand.Delete(fn.UID)
+		return 0, 0, ""
+	}
+	specTarget = pc.Target
+	if pc.Auto == nil {
+		p.demand.Delete(fn.UID)
+		return min(target, p.config.MaxPerFunction), specTarget, ""
+	}
+
+	// The executor's own count is sampled once per pass; the routers report
+	// theirs with every tap flush.
+	h := p.demandFor(fn.UID, now)
+	h.observe(now, "", p.gpm.ActiveRequests(fn.UID))
+	if windowActive {
+		reason = "auto: schedule window active"
+	} else if pods, why, ok := autoTarget(h, fn, now); ok {
+		target, specTarget, reason = pods, pods, why
+	} else {
+		reason = why
+	}
+	return min(target, p.config.MaxPerFunction), specTarget, reason
 }
 
 // disableProvisioning clears all provisioned labels, zeroes the function's
@@ -641,12 +674,13 @@ func (p *Provisioner) disableProvisioning(ctx context.Context, fn *fv1.Function)
 	lock := p.lockFor(fn.UID)
 	lock.Lock()
 	defer lock.Unlock()
-	p.disableProvisioningLocked(ctx, fn)
+	p.disableProvisioningLocked(ctx, fn, "")
 }
 
 // disableProvisioningLocked is the body of disableProvisioning. The caller
-// MUST hold the per-function reconcile lock (lockFor(fn.UID)).
-func (p *Provisioner) disableProvisioningLocked(ctx context.Context, fn *fv1.Function) {
+// MUST hold the per-function reconcile lock (lockFor(fn.UID)). reason is
+// the auto-mode explanation of a 0 target, if any.
+func (p *Provisioner) disableProvisioningLocked(ctx context.Context, fn *fv1.Function, reason string) {
 	p.clearProvisionedLabels(ctx, fn, -1)
 	p.inflight.Delete(fn.UID)
 	if fn.Spec.ProvisionedConcurrency == nil {
@@ -659,8 +693,9 @@ func (p *Provisioner) disableProvisioningLocked(ctx context.Context, fn *fv1.Fun
 			sched.mu.Unlock()
 		}
 		p.timers.Delete(fn.UID)
+		p.demand.Delete(fn.UID)
 	}
-	if err := p.updateFunctionStatus(ctx, fn, 0, 0, 0); err != nil {
+	if err := p.updateFunctionStatus(ctx, fn, 0, 0, 0, reason); err != nil {
 		p.logger.Error(err, "Unable to update status of the function",
 			"function", fn.Name, "namespace", fn.Namespace)
 	}
@@ -672,6 +707,7 @@ func (p *Provisioner) disableProvisioningLocked(ctx context.Context, fn *fv1.Fun
 func (p *Provisioner) forget(fnUID types.UID) {
 	p.reconcileLocks.Delete(fnUID)
 	p.inflight.Delete(fnUID)
+	p.demand.Delete(fnUID)
 	if v, ok := p.timers.Load(fnUID); ok {
 		sched := v.(*fnSchedule)
 		sched.mu.Lock()
@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package poolmgr
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+const (
+	// demandSlotWidth is the resolution of the demand history: a slot holds
+	// the peak concurrency observed within it. Routers flush taps every 5s,
+	// so a slot sees at least one report from each router serving traffic.
+	demandSlotWidth = 10 * time.Second
+
+	// demandBucketWidth is the resolution of the time-of-day pattern, and
+	// how far ahead of the clock seasonality looks.
+	demandBucketWidth   = 15 * time.Minute
+	demandBucketsPerDay = int64(24 * time.Hour / demandBucketWidth)
+)
+
+type demandSample struct {
+	start time.Time
+	value int
+}
+
+// demandHistory is one function's observed concurrency. It lives in memory
+// only: an executor restart starts it over, and the target falls back to
+// ProvisionedConcurrencyConfig.Target until a window of history is back.
+//
+// A slot's value is the larger of the executor's own count and the sum of
+// the routers' counts: every request passes through a router, so the sum is
+// the function's concurrency, and the executor's count covers routers that
+// predate the in-flight report.
+type demandHistory struct {
+	mu sync.Mutex
+
+	// since is the first observation; no percentile is trusted until a
+	// window has passed since.
+	since time.Time
+
+	curStart   time.Time
+	curLocal   int
+	curSources map[string]int
+
+	// recent holds the closed slots of the last MaxProvisionedAutoWindow,
+	// oldest first. Slots with no demand are not stored.
+	recent []demandSample
+	// peaks holds the peak slot of every 15-minute bucket (indexed from the
+	// Unix epoch) of the last MaxProvisionedAutoSeasonalityDays+1 days.
+	peaks map[int64]int
+}
+
+func newDemandHistory(now time.Time) *demandHistory {
+	return &demandHistory{
+		since:      now,
+		curStart:   now.Truncate(demandSlotWidth),
+		curSources: make(map[string]int),
+		peaks:      make(map[int64]int),
+	}
+}
+
+// observe records inflight requests seen by source at now. The executor's
+// own count has the empty source.
+func (h *demandHistory) observe(now time.Time, source string, inflight int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rollLocked(now)
+	if source == "" {
+		h.curLocal = max(h.curLocal, inflight)
+	} else {
+		h.curSources[source] = max(h.curSources[source], inflight)
+	}
+}
+
+func (h *demandHistory) currentLocked() int {
+	sum := 0
+	for _, n := range h.curSources {
+		sum += n
+	}
+	return max(h.curLocal, sum)
+}
+
+// rollLocked closes the current slot once now has moved past it and prunes
+// what no config can ask for any more.
+func (h *demandHistory) rollLocked(now time.Time) {
+	start := now.Truncate(demandSlotWidth)
+	if !start.After(h.curStart) {
+		return
+	}
+	if v := h.currentLocked(); v > 0 {
+		h.recent = append(h.recent, demandSample{start: h.curStart, value: v})
+		b := demandBucket(h.curStart)
+		h.peaks[b] = max(h.peaks[b], v)
+	}
+	h.curStart, h.curLocal = start, 0
+	clear(h.curSources)
+
+	cut := now.Add(-fv1.MaxProvisionedAutoWindow)
+	i := 0
+	for i < len(h.recent) && h.recent[i].start.Before(cut) {
+		i++
+	}
+	h.recent = slices.Delete(h.recent, 0, i)
+	oldest := demandBucket(now) - int64(fv1.MaxProvisionedAutoSeasonalityDays+1)*demandBucketsPerDay
+	maps.DeleteFunc(h.peaks, func(b int64, _ int) bool { return b < oldest })
+}
+
+// recentPercentile is the pct percentile of the slots of the last window,
+// the current one included.
+func (h *demandHistory) recentPercentile(now time.Time, window time.Duration, pct int) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rollLocked(now)
+	from := now.Add(-window)
+	values := []int{h.currentLocked()}
+	for _, s := range h.recent {
+		if !s.start.Before(from) {
+			values = append(values, s.value)
+		}
+	}
+	slots := int(window/demandSlotWidth) + 1
+	return percentile(values, max(slots-len(values), 0), pct)
+}
+
+// seasonalPercentile is the pct percentile, over the previous days, of each
+// day's peak in the 15-minute bucket of now and the one after it. days is
+// the number of previous days history covers.
+func (h *demandHistory) seasonalPercentile(now time.Time, maxDays, pct int) (value, days int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rollLocked(now)
+	b, first := demandBucket(now), demandBucket(h.since)
+	var values []int
+	for d := 1; d <= maxDays; d++ {
+		prev := b - int64(d)*demandBucketsPerDay
+		if prev < first {
+			break
+		}
+		values = append(values, max(h.peaks[prev], h.peaks[prev+1]))
+	}
+	if len(values) == 0 {
+		return 0, 0
+	}
+	return percentile(values, 0, pct), len(values)
+}
+
+func demandBucket(t time.Time) int64 {
+	return t.Unix() / int64(demandBucketWidth/time.Second)
+}
+
+// percentile is the nearest-rank pct percentile of values plus zeros more
+// samples of 0.
+func percentile(values []int, zeros, pct int) int {
+	total := len(values) + zeros
+	rank := (pct*total + 99) / 100
+	if rank <= zeros {
+		return 0
+	}
+	slices.Sort(values)
+	return values[min(rank-zeros, len(values))-1]
+}
+
+// demandFor returns fn's demand history, starting one on first use. Only
+// functions in auto mode have one, so routers' reports for every other
+// function are dropped at ObserveConcurrency.
+func (p *Provisioner) demandFor(uid types.UID, now time.Time) *demandHistory {
+	if h, ok := p.demand.Load(uid); ok {
+		return h.(*demandHistory)
+	}
+	h, _ := p.demand.LoadOrStore(uid, newDemandHistory(now))
+	return h.(*demandHistory)
+}
+
+// ObserveConcurrency records the in-flight requests a router reported for
+// the function in its taps.
+func (p *Provisioner) ObserveConcurrency(uid types.UID, source string, inflight int) {
+	if h, ok := p.demand.Load(uid); ok {
+		h.(*demandHistory).observe(time.Now(), source, inflight)
+	}
+}
+
+// autoTarget derives fn's target from its demand history: the pods that
+// serve the larger of the recent and seasonal percentile plus headroom,
+// bounded by Min and Max. ok is false while the history is shorter than the
+// window (or the window does not parse), and the reason then says which
+// Target is used instead.
+func autoTarget(h *demandHistory, fn *fv1.Function, now time.Time) (pods int, reason string, ok bool) {
+	pc := fn.Spec.ProvisionedConcurrency
+	auto := pc.Auto.Effective()
+	window, err := time.ParseDuration(auto.Window)
+	if err != nil {
+		return 0, fmt.Sprintf("auto: invalid window %q, using target %d", auto.Window, pc.Target), false
+	}
+	h.mu.Lock()
+	observed := now.Sub(h.since)
+	h.mu.Unlock()
+	if observed < window {
+		return 0, fmt.Sprintf("auto: collecting history (%v of %v), using target %d",
+			observed.Truncate(time.Second), window, pc.Target), false
+	}
+
+	var b strings.Builder
+	demand := h.recentPercentile(now, window, auto.Percentile)
+	fmt.Fprintf(&b, "auto: p%d %d over %v", auto.Percentile, demand, window)
+	if auto.SeasonalityDays > 0 {
+		if seasonal, days := h.seasonalPercentile(now, auto.SeasonalityDays, auto.Percentile); days > 0 {
+			fmt.Fprintf(&b, ", seasonal %d from %d days", seasonal, days)
+			demand = max(demand, seasonal)
+		}
+	}
+	if auto.HeadroomPercent > 0 {
+		demand = (demand*(100+auto.HeadroomPercent) + 99) / 100
+		fmt.Fprintf(&b, ", +%d%% headroom", auto.HeadroomPercent)
+	}
+	rpp := max(fn.Spec.RequestsPerPod, 1)
+	pods = (demand + rpp - 1) / rpp
+	if rpp > 1 {
+		fmt.Fprintf(&b, " at %d requests per pod", rpp)
+	}
+	fmt.Fprintf(&b, " = %d pods", pods)
+
+	bounded := max(pods, auto.Min)
+	if auto.Max > 0 {
+		bounded = min(bounded, auto.Max)
+	}
+	if bounded != pods {
+		fmt.Fprintf(&b, ", bounded to %d", bounded)
+	}
+	return bounded, b.String(), true
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package poolmgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	fClient "github.com/fission/fission/pkg/generated/clientset/versioned/fake"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		zeros  int
+		pct    int
+		want   int
+	}{
+		{"single", []int{4}, 0, 95, 4},
+		{"median", []int{5, 1, 3, 2, 4}, 0, 50, 3},
+		{"max", []int{5, 1, 3}, 0, 100, 5},
+		{"zeros below rank", []int{9}, 9, 50, 0},
+		{"zeros under rank", []int{9, 7}, 8, 95, 9},
+		{"p90 of ten", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 0, 90, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, percentile(tt.values, tt.zeros, tt.pct))
+		})
+	}
+}
+
+func TestDemandHistoryRecent(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	h := newDemandHistory(start)
+
+	// Two routers report in the same slot: their counts add up, and the
+	// executor's smaller count does not lower the slot.
+	h.observe(start, "10.0.0.1", 3)
+	h.observe(start.Add(2*time.Second), "10.0.0.1", 5)
+	h.observe(start.Add(3*time.Second), "10.0.0.2", 4)
+	h.observe(start.Add(4*time.Second), "", 6)
+	assert.Equal(t, 9, h.recentPercentile(start.Add(5*time.Second), time.Minute, 100))
+
+	// The executor's count wins when it is larger than the routers'.
+	next := start.Add(demandSlotWidth)
+	h.observe(next, "", 12)
+	assert.Equal(t, 12, h.recentPercentile(next, time.Minute, 100))
+
+	// Slots without demand count as zeros: two busy slots out of seven in
+	// a minute do not make the median busy.
+	assert.Equal(t, 0, h.recentPercentile(next.Add(30*time.Second), time.Minute, 50))
+
+	// Slots older than the window drop out.
+	assert.Equal(t, 0, h.recentPercentile(start.Add(2*time.Minute), time.Minute, 100))
+}
+
+func TestDemandHistoryPrunes(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	h := newDemandHistory(start)
+	h.observe(start, "", 3)
+	h.observe(start.Add(fv1.MaxProvisionedAutoWindow+time.Minute), "", 1)
+	assert.Empty(t, h.recent, "slots beyond the longest window are dropped")
+
+	later := start.Add(time.Duration(fv1.MaxProvisionedAutoSeasonalityDays+2) * 24 * time.Hour)
+	h.observe(later, "", 1)
+	assert.Empty(t, h.peaks, "buckets beyond the seasonal horizon are dropped")
+	h.observe(later.Add(demandSlotWidth), "", 0)
+	assert.Len(t, h.peaks, 1)
+}
+
+func TestDemandHistorySeasonal(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	h := newDemandHistory(start)
+	peak := time.Date(2026, 3, 1, 9, 10, 0, 0, time.UTC)
+	// A daily 9:10 peak on three days, growing.
+	for d, n := range []int{8, 10, 12} {
+		at := peak.Add(time.Duration(d) * day)
+		h.observe(at, "10.0.0.1", n)
+		h.observe(at.Add(demandSlotWidth), "", 0)
+	}
+
+	// At 8:50 on the fourth day the next bucket holds the previous days'
+	// peaks, so seasonality already covers it.
+	now := time.Date(2026, 3, 4, 8, 50, 0, 0, time.UTC)
+	v, days := h.seasonalPercentile(now, 7, 100)
+	assert.Equal(t, 3, days, "only days with history count")
+	assert.Equal(t, 12, v)
+	v, _ = h.seasonalPercentile(now, 7, 50)
+	assert.Equal(t, 10, v)
+
+	// Well before the peak nothing is expected.
+	v, _ = h.seasonalPercentile(time.Date(2026, 3, 4, 3, 0, 0, 0, time.UTC), 7, 100)
+	assert.Equal(t, 0, v)
+
+	// Only SeasonalityDays days are consulted.
+	v, days = h.seasonalPercentile(now, 1, 100)
+	assert.Equal(t, 1, days)
+	assert.Equal(t, 12, v)
+}
+
+func autoFn(auto fv1.ProvisionedAutoConfig) *fv1.Function {
+	fn := provisionedFn("auto", 2)
+	fn.Spec.ProvisionedConcurrency.Auto = &auto
+	return fn
+}
+
+func TestAutoTarget(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	busy := func() *demandHistory {
+		h := newDemandHistory(start)
+		for i := range 90 {
+			h.observe(start.Add(time.Duration(i)*demandSlotWidth), "10.0.0.1", 10)
+		}
+		return h
+	}
+	now := start.Add(15 * time.Minute)
+
+	t.Run("collecting history falls back to target", func(t *testing.T) {
+		_, reason, ok := autoTarget(busy(), autoFn(fv1.ProvisionedAutoConfig{}), start.Add(5*time.Minute))
+		assert.False(t, ok)
+		assert.Equal(t, "auto: collecting history (5m0s of 15m0s), using target 2", reason)
+	})
+
+	tests := []struct {
+		name       string
+		auto       fv1.ProvisionedAutoConfig
+		rpp        int
+		want       int
+		wantReason string
+	}{
+		{"percentile", fv1.ProvisionedAutoConfig{}, 0, 10, "auto: p95 10 over 15m0s = 10 pods"},
+		{"headroom and requests per pod", fv1.ProvisionedAutoConfig{HeadroomPercent: 25}, 4, 4,
+			"auto: p95 10 over 15m0s, +25% headroom at 4 requests per pod = 4 pods"},
+		{"max", fv1.ProvisionedAutoConfig{Max: 6}, 0, 6, "auto: p95 10 over 15m0s = 10 pods, bounded to 6"},
+		{"min", fv1.ProvisionedAutoConfig{Min: 3}, 5, 3, "auto: p95 10 over 15m0s at 5 requests per pod = 2 pods, bounded to 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := autoFn(tt.auto)
+			fn.Spec.RequestsPerPod = tt.rpp
+			pods, reason, ok := autoTarget(busy(), fn, now)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, pods)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+
+	t.Run("idle function drains to min", func(t *testing.T) {
+		pods, reason, ok := autoTarget(busy(), autoFn(fv1.ProvisionedAutoConfig{Window: "5m"}), now.Add(10*time.Minute))
+		require.True(t, ok)
+		assert.Zero(t, pods)
+		assert.Equal(t, "auto: p95 0 over 5m0s = 0 pods", reason)
+	})
+}
+
+func TestProvisioner_effectiveTargetAuto(t *testing.T) {
+	p := newTestProvisioner(t)
+	gpm := &fakeGPM{}
+	p.gpm = gpm
+	p.config.MaxPerFunction = 8
+
+	fn := autoFn(fv1.ProvisionedAutoConfig{Window: "1m"})
+	target, specTarget, reason := p.effectiveTarget(fn)
+	assert.Equal(t, 2, target, "Target holds until a window of history exists")
+	assert.Equal(t, 2, specTarget)
+	assert.Contains(t, reason, "auto: collecting history")
+
+	// A history that started before the window, fed by a router's taps
+	// and the executor's own count.
+	p.demand.Store(fn.UID, newDemandHistory(time.Now().Add(-2*time.Minute)))
+	p.ObserveConcurrency(fn.UID, "10.0.0.1", 12)
+	gpm.active.Store(3)
+	target, specTarget, reason = p.effectiveTarget(fn)
+	assert.Equal(t, 8, target, "the derived target is clamped to the namespace cap")
+	assert.Equal(t, 12, specTarget)
+	assert.Equal(t, "auto: p95 12 over 1m0s = 12 pods", reason)
+
+	// Reports for functions not in auto mode are dropped.
+	p.ObserveConcurrency("other-uid", "10.0.0.1", 5)
+	_, ok := p.demand.Load("other-uid")
+	assert.False(t, ok)
+
+	// Leaving auto mode drops the history.
+	fn.Spec.ProvisionedConcurrency.Auto = nil
+	target, _, reason = p.effectiveTarget(fn)
+	assert.Equal(t, 2, target)
+	assert.Empty(t, reason)
+	_, ok = p.demand.Load(fn.UID)
+	assert.False(t, ok)
+}
+
+func TestProvisioner_autoTargetStatus(t *testing.T) {
+	fn := autoFn(fv1.ProvisionedAutoConfig{})
+	p := newTestProvisioner(t, fn)
+	p.fissionClient = fClient.NewSimpleClientset(fn) //nolint:staticcheck
+
+	require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 0, 0, 0, "auto: p95 0 over 15m0s = 0 pods"))
+	st := getFnStatus(t, p, fn.Name)
+	assert.Equal(t, "auto: p95 0 over 15m0s = 0 pods", st.ProvisionedTargetReason)
+	cond := metaFindCondition(st, fv1.FunctionConditionProvisioned)
+	require.NotNil(t, cond)
+	assert.Equal(t, fv1.FunctionReasonProvisionedDisabled, cond.Reason)
+	assert.Equal(t, "auto: p95 0 over 15m0s = 0 pods", cond.Message)
+}
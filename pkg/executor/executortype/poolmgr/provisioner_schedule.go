@@ -83,8 +83,16 @@ func (f *fnSchedule) reBuildWindows(fn *fv1.Function, logger logr.Logger) {
 // kubectl write bypassing admission) are skipped rather than aborting the
 // whole evaluation, and returned in badWindows for the caller to log.
 func effectiveTargetAt(cfg *fv1.ProvisionedConcurrencyConfig, now time.Time) (int, []error) {
+	target, _, badWindows := windowTargetAt(cfg, now)
+	return target, badWindows
+}
+
+// windowTargetAt is effectiveTargetAt that also reports whether a window is
+// active, which auto mode needs: an active window overrides the derived
+// target just as it overrides the base Target.
+func windowTargetAt(cfg *fv1.ProvisionedConcurrencyConfig, now time.Time) (int, bool, []error) {
 	if cfg == nil {
-		return 0, nil
+		return 0, false, nil
 	}
 	target := cfg.Target
 	badWindows := []error{}
@@ -102,7 +110,7 @@ func effectiveTargetAt(cfg *fv1.ProvisionedConcurrencyConfig, now time.Time) (in
 	if active {
 		target = maxActiveTarget
 	}
-	return target, badWindows
+	return target, active, badWindows
 }
 
 // windowActiveAt reports whether window is open at instant now. robfig/cron's
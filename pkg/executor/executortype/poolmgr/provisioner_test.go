@@ -62,6 +62,8 @@ type fakeGPM struct {
 	// so a test can prove the release is keyed on the FuncSvc the call
 	// returned rather than on some other address.
 	lastUntapAddr atomic.Value
+	// active is what ActiveRequests reports.
+	active atomic.Int64
 }
 
 func (f *fakeGPM) GetFuncSvc(ctx context.Context, fn *fv1.Function) (*fscache.FuncSvc, error) {
@@ -96,6 +98,10 @@ func (f *fakeGPM) MarkSpecializationFailure(ctx context.Context, fnMeta *metav1.
 	f.markFailures.Add(1)
 }
 
+func (f *fakeGPM) ActiveRequests(uid types.UID) int {
+	return int(f.active.Load())
+}
+
 // podRef builds an ObjectReference for a pod in the given namespace.
 func podRef(name, ns string) corev1.ObjectReference {
 	return corev1.ObjectReference{Kind: "Pod", Name: name, Namespace: ns}
@@ -304,7 +310,10 @@ func TestProvisioner_effectiveTarget(t *testing.T) {
 					ProvisionedConcurrency: &fv1.ProvisionedConcurrencyConfig{Target: tt.target},
 				},
 			}
-			assert.Equal(t, tt.want, p.effectiveTarget(fn))
+			target, specTarget, reason := p.effectiveTarget(fn)
+			assert.Equal(t, tt.want, target)
+			assert.Equal(t, tt.target, specTarget)
+			assert.Empty(t, reason)
 		})
 	}
 }
@@ -896,7 +905,7 @@ func TestProvisioner_updateFunctionStatus(t *testing.T) {
 	p.fissionClient = fClient.NewSimpleClientset(fn) //nolint:staticcheck
 
 	t.Run("warming: ready < target", func(t *testing.T) {
-		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 2, 5, 5))
+		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 2, 5, 5, ""))
 		st := getFnStatus(t, p, "fn")
 		assert.Equal(t, 2, st.ProvisionedReady)
 		assert.Equal(t, 5, st.ProvisionedTarget)
@@ -908,7 +917,7 @@ func TestProvisioner_updateFunctionStatus(t *testing.T) {
 	})
 
 	t.Run("satisfied: ready >= target", func(t *testing.T) {
-		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 5, 5, 5))
+		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 5, 5, 5, ""))
 		st := getFnStatus(t, p, "fn")
 		assert.Equal(t, 5, st.ProvisionedReady)
 		assert.Equal(t, 5, st.ProvisionedTarget)
@@ -919,7 +928,7 @@ func TestProvisioner_updateFunctionStatus(t *testing.T) {
 	})
 
 	t.Run("oversatisfied: ready > target still True", func(t *testing.T) {
-		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 7, 5, 5))
+		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 7, 5, 5, ""))
 		st := getFnStatus(t, p, "fn")
 		cond := metaFindCondition(st, fv1.FunctionConditionProvisioned)
 		require.NotNil(t, cond)
@@ -927,7 +936,7 @@ func TestProvisioner_updateFunctionStatus(t *testing.T) {
 	})
 
 	t.Run("disabled: target=0 sets False/ProvisionedDisabled", func(t *testing.T) {
-		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 0, 0, 0))
+		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 0, 0, 0, ""))
 		st := getFnStatus(t, p, "fn")
 		assert.Equal(t, 0, st.ProvisionedReady)
 		assert.Equal(t, 0, st.ProvisionedTarget)
@@ -940,7 +949,7 @@ func TestProvisioner_updateFunctionStatus(t *testing.T) {
 	t.Run("clamped: specTarget > target sets ProvisionedClamped reason", func(t *testing.T) {
 		// spec target=50, effective target=20 (clamped by MaxPerFunction).
 		// Warming: ready=5 < target=20 → False/ProvisionedClamped.
-		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 5, 20, 50))
+		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 5, 20, 50, ""))
 		st := getFnStatus(t, p, "fn")
 		assert.Equal(t, 5, st.ProvisionedReady)
 		assert.Equal(t, 20, st.ProvisionedTarget, "effective target clamped")
@@ -955,7 +964,7 @@ func TestProvisioner_updateFunctionStatus(t *testing.T) {
 
 	t.Run("clamped and satisfied: ready >= target still True but reason Clamped", func(t *testing.T) {
 		// spec target=50, effective=20, ready=20 → True/ProvisionedClamped.
-		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 20, 20, 50))
+		require.NoError(t, p.updateFunctionStatus(t.Context(), fn, 20, 20, 50, ""))
 		st := getFnStatus(t, p, "fn")
 		cond := metaFindCondition(st, fv1.FunctionConditionProvisioned)
 		require.NotNil(t, cond)
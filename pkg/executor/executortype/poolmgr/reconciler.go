@@ -117,10 +117,10 @@ func (r *readyPodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	if envUID == "" {
 		return ctrl.Result{}, nil
 	}
-	// Per-image (Path B) pool pods carry the image-hash label; route them to
-	// their own pool's queue. Absent label -> the env's plain pool.
-	imageHash := pod.Labels[fv1.POOL_OCI_IMAGE_HASH]
-	r.enqueuer.enqueueReadyPod(poolKey(k8sTypes.UID(envUID), imageHash), req.String())
+	// Per-image (Path B) and resource class pool pods carry the image-hash
+	// and class labels; route them to their own pool's queue. Absent labels
+	// -> the env's plain pool.
+	r.enqueuer.enqueueReadyPod(poolKey(k8sTypes.UID(envUID), pod.Labels[fv1.POOL_RESOURCE_CLASS], pod.Labels[fv1.POOL_OCI_IMAGE_HASH]), req.String())
 	return ctrl.Result{}, nil
 }
 
@@ -256,4 +256,15 @@ func TestPoolmgrReadyPodReconciler(t *testing.T) {
 		assert.Equal(t, "default/pod", e.enqueued["e1/abcdef0123456789"])
 		assert.NotContains(t, e.enqueued, "e1", "a Path B pod must not reach the plain pool's queue")
 	})
+
+	t.Run("resource class pool pod routes to its class queue", func(t *testing.T) {
+		pod := warmPod("pod", "e1", corev1.PodRunning, "true")
+		pod.Labels[fv1.POOL_RESOURCE_CLASS] = "big"
+		e := &fakeEnqueuer{}
+		r := &readyPodReconciler{logger: logr.Discard(), client: crClientK8s(pod), enqueuer: e}
+		_, err := r.Reconcile(t.Context(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "default/pod", e.enqueued["e1/class:big"])
+		assert.NotContains(t, e.enqueued, "e1", "a class pod must not reach the default pool's queue")
+	})
 }
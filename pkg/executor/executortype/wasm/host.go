@@ -0,0 +1,307 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
+	"github.com/fission/fission/pkg/executor/util"
+	fetcherClient "github.com/fission/fission/pkg/fetcher/client"
+	storagesvcClient "github.com/fission/fission/pkg/storagesvc/client"
+	"github.com/fission/fission/pkg/utils"
+	otelUtils "github.com/fission/fission/pkg/utils/otel"
+)
+
+const (
+	// hostName names the host Deployment of a function namespace and its
+	// wasm host container.
+	hostName = "wasm-host"
+
+	// hostPollInterval is how often a load waits on the host pod to become
+	// ready.
+	hostPollInterval = 500 * time.Millisecond
+)
+
+// hostClient talks to the hosts' loaded/unload API; the calls are small and
+// local to the cluster, so a stuck host fails them fast.
+var hostClient = &http.Client{Timeout: 10 * time.Second}
+
+func hostLabels() map[string]string {
+	return map[string]string{fv1.EXECUTOR_TYPE: string(fv1.ExecutorTypeWasm)}
+}
+
+// hostDeployment returns the host Deployment of namespace ns: one replica of
+// the fission-bundle wasm host with a fetcher sidecar, on the fetcher's
+// ServiceAccount like an environment pool pod.
+func (w *Wasm) hostDeployment(ns string) (*appsv1.Deployment, error) {
+	gracePeriodSeconds := fv1.DefaultTerminationGracePeriod
+
+	podAnnotations := make(map[string]string)
+	// Stamp the HMAC key-scheme so specialize signs with the key the host's
+	// fetcher verifies with, as poolmgr does for its pools.
+	if utils.PerNamespaceKeysEnabled() && w.nsResolver.IsTenant(ns) {
+		podAnnotations[fv1.AuthKeySchemeAnnotation] = fv1.AuthKeySchemeNamespace
+	}
+
+	spec := apiv1.PodSpec{
+		Containers: []apiv1.Container{
+			{
+				Name:                   hostName,
+				Image:                  w.hostImage,
+				ImagePullPolicy:        w.runtimeImagePullPolicy,
+				Command:                []string{"/fission-bundle", "--wasmHostPort", strconv.Itoa(w.hostPort)},
+				TerminationMessagePath: "/dev/termination-log",
+				// Connection-draining preStop hook; see utils.DrainLifecycle.
+				Lifecycle: utils.DrainLifecycle(gracePeriodSeconds),
+				Ports: []apiv1.ContainerPort{
+					{
+						Name:          "http-fetcher",
+						ContainerPort: int32(w.fetcherPort),
+					},
+					{
+						Name:          "http-env",
+						ContainerPort: int32(w.hostPort),
+					},
+				},
+				ReadinessProbe: &apiv1.Probe{
+					ProbeHandler: apiv1.ProbeHandler{
+						HTTPGet: &apiv1.HTTPGetAction{
+							Path: "/healthz",
+							Port: intstr.FromInt(w.hostPort),
+						},
+					},
+					PeriodSeconds: 1,
+				},
+			},
+		},
+		ServiceAccountName: fv1.FissionFetcherSA,
+		// The fetcher container re-mounts the SA token through the
+		// projected volume; the host container does not get it. See
+		// GHSA-85g2-pmrx-r49q.
+		AutomountServiceAccountToken:  new(false),
+		TerminationGracePeriodSeconds: &gracePeriodSeconds,
+		Volumes:                       []apiv1.Volume{util.FetcherSATokenProjectedVolume()},
+	}
+	if err := w.fetcherConfig.AddFetcherToPodSpec(&spec, hostName, ns); err != nil {
+		return nil, err
+	}
+	// The host's specialization API shares its port with every function of
+	// the namespace, so it verifies its callers like the fetcher does.
+	if err := w.fetcherConfig.AddSignedSpecializeToPodSpec(&spec, hostName, ns); err != nil {
+		return nil, err
+	}
+	if err := util.MountFetcherSATokenOnFetcher(&spec); err != nil {
+		return nil, err
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      hostName,
+			Namespace: ns,
+			Labels:    hostLabels(),
+			Annotations: map[string]string{
+				fv1.EXECUTOR_INSTANCEID_LABEL: w.instanceID,
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: new(int32(1)),
+			Selector: &metav1.LabelSelector{MatchLabels: hostLabels()},
+			Template: apiv1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      hostLabels(),
+					Annotations: podAnnotations,
+				},
+				Spec: spec,
+			},
+		},
+	}, nil
+}
+
+// ensureHost creates the host Deployment of namespace ns unless it exists.
+func (w *Wasm) ensureHost(ctx context.Context, ns string) error {
+	_, err := w.kubernetesClient.AppsV1().Deployments(ns).Get(ctx, hostName, metav1.GetOptions{})
+	if err == nil || !k8sErrs.IsNotFound(err) {
+		return err
+	}
+	depl, err := w.hostDeployment(ns)
+	if err != nil {
+		return err
+	}
+	_, err = w.kubernetesClient.AppsV1().Deployments(ns).Create(ctx, depl, metav1.CreateOptions{})
+	if k8sErrs.IsAlreadyExists(err) {
+		return nil
+	}
+	if err == nil {
+		w.logger.Info("created wasm host", "namespace", ns)
+	}
+	return err
+}
+
+// adoptHost stamps the host Deployment of namespace ns, if any, with this
+// executor's instance ID.
+func (w *Wasm) adoptHost(ctx context.Context, ns string) error {
+	depl, err := w.kubernetesClient.AppsV1().Deployments(ns).Get(ctx, hostName, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if depl.Annotations[fv1.EXECUTOR_INSTANCEID_LABEL] == w.instanceID {
+		return nil
+	}
+	if depl.Annotations == nil {
+		depl.Annotations = make(map[string]string)
+	}
+	depl.Annotations[fv1.EXECUTOR_INSTANCEID_LABEL] = w.instanceID
+	_, err = w.kubernetesClient.AppsV1().Deployments(ns).Update(ctx, depl, metav1.UpdateOptions{})
+	return err
+}
+
+// hostPods lists the host pods of namespace ns.
+func (w *Wasm) hostPods(ctx context.Context, ns string) ([]apiv1.Pod, error) {
+	pods, err := w.kubernetesClient.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.Set(hostLabels()).AsSelector().String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// waitForHostPod waits until a host pod of namespace ns is running and
+// ready, or ctx is done.
+func (w *Wasm) waitForHostPod(ctx context.Context, ns string) (*apiv1.Pod, error) {
+	ticker := time.NewTicker(hostPollInterval)
+	defer ticker.Stop()
+	for {
+		pods, err := w.hostPods(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		ready := utils.ReadyAndRunningPodsFilter(&apiv1.PodList{Items: pods})
+		if len(ready) > 0 {
+			return &ready[0], nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for the wasm host in namespace %s: %w", ns, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// specialize has the host pod's fetcher deploy the function's package onto
+// the shared volume and load it into the host.
+func (w *Wasm) specialize(ctx context.Context, pod *apiv1.Pod, fn *fv1.Function) error {
+	fetcherURL := "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(w.fetcherPort)) + "/"
+	otelUtils.LoggerWithTraceID(ctx, w.logger).Info("loading function into wasm host",
+		"function", fn.Name, "pod", pod.Name, "url", fetcherURL)
+
+	specializeReq := w.fetcherConfig.NewWasmSpecializeRequest(fn)
+
+	// Sign with the key the host's fetcher verifies with; see
+	// poolmgr's fetcherSigningNamespace.
+	master := storagesvcClient.HMACSecretFromEnv()
+	var client fetcherClient.ClientInterface
+	if utils.PerNamespaceKeysEnabled() && fv1.HasNamespaceKeyScheme(pod.Annotations) {
+		client = fetcherClient.MakeClientNS(w.logger, fetcherURL, master, pod.Namespace)
+	} else {
+		client = fetcherClient.MakeClient(w.logger, fetcherURL, master)
+	}
+	if err := client.Specialize(ctx, &specializeReq); err != nil {
+		return fmt.Errorf("error loading function into wasm host %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// hostLoaded reports whether the host at addr has the function loaded.
+func (w *Wasm) hostLoaded(ctx context.Context, addr string, fnMeta *metav1.ObjectMeta) (bool, error) {
+	q := url.Values{
+		"uid":        {string(fnMeta.UID)},
+		"generation": {strconv.FormatInt(fnMeta.Generation, 10)},
+	}
+	status, err := w.hostCall(ctx, http.MethodGet, fnMeta, addr, "/v2/loaded", q)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected status %d from wasm host", status)
+	}
+}
+
+// hostUnload unloads the function from the host at addr: one generation, or
+// every generation if generation is 0. purge also forgets the modules and
+// removes their packages; otherwise the host only drops the compiled code
+// and compiles it again on the next request.
+func (w *Wasm) hostUnload(ctx context.Context, addr string, fnMeta *metav1.ObjectMeta, generation int64, purge bool) error {
+	q := url.Values{"uid": {string(fnMeta.UID)}}
+	if generation != 0 {
+		q.Set("generation", strconv.FormatInt(generation, 10))
+	}
+	if purge {
+		q.Set("purge", "true")
+	}
+	status, err := w.hostCall(ctx, http.MethodPost, fnMeta, addr, "/v2/unload", q)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from wasm host", status)
+	}
+	return nil
+}
+
+// hostCall calls the host API of the host at addr serving fnMeta's
+// namespace, signed with the key the host verifies with: the namespace's
+// fetcher key where the host was stamped with that scheme (see
+// hostDeployment), else the master-derived one. Without a master the call
+// goes unsigned, as the host then verifies nothing.
+func (w *Wasm) hostCall(ctx context.Context, method string, fnMeta *metav1.ObjectMeta, addr, path string, q url.Values) (int, error) {
+	u := url.URL{Scheme: "http", Host: addr, Path: path, RawQuery: q.Encode()}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	client := hostClient
+	if master := storagesvcClient.HMACSecretFromEnv(); len(master) > 0 {
+		ns := w.nsResolver.GetFunctionNS(fnMeta.Namespace)
+		var rt http.RoundTripper
+		if utils.PerNamespaceKeysEnabled() && w.nsResolver.IsTenant(ns) {
+			rt = hmacauth.ServiceSignerNS(master, hmacauth.ServiceFetcher, ns, http.DefaultTransport, time.Now)
+		} else {
+			rt = hmacauth.ServiceSigner(master, hmacauth.ServiceFetcher, http.DefaultTransport, time.Now)
+		}
+		client = &http.Client{Transport: rt, Timeout: hostClient.Timeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
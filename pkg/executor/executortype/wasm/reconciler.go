@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// RegisterReconcilers registers nothing: wasm functions are reconciled by
+// the shared Function reconciler through ReconcileFunction/DeleteFunction.
+func (w *Wasm) RegisterReconcilers(mgr ctrl.Manager) error {
+	return nil
+}
+
+// ReconcileFunction satisfies executortype.FuncReconciler. A function is
+// loaded on its first request, not here; the reconcile only makes sure its
+// namespace has a host, and on a spec change evicts the previous
+// generation's compiled module from the hosts so it stops holding memory.
+// The previous generation's module stays loaded for requests pinned to it.
+func (w *Wasm) ReconcileFunction(ctx context.Context, old, fn *fv1.Function) error {
+	if w.hostImage == "" {
+		return nil
+	}
+	ns := w.nsResolver.GetFunctionNS(fn.Namespace)
+	if err := w.ensureHost(ctx, ns); err != nil {
+		return err
+	}
+	if old == nil || old.Generation == fn.Generation {
+		return nil
+	}
+	w.unloadFromHosts(ctx, old, false)
+	if fsvc, err := w.fsCache.GetByFunction(&old.ObjectMeta); err == nil {
+		w.fsCache.DeleteEntry(fsvc)
+	}
+	return nil
+}
+
+// DeleteFunction satisfies executortype.FuncReconciler: it purges every
+// generation of the function from its namespace's hosts. The host itself
+// stays for the namespace's other functions. Failures are logged rather than
+// returned so a host that is down cannot hold the function's finalizer; a
+// restarted host has forgotten the function anyway.
+func (w *Wasm) DeleteFunction(ctx context.Context, fn *fv1.Function) error {
+	w.unloadFromHosts(ctx, fn, true)
+	for _, fsvc := range w.fsCache.ListByFunctionUID(fn.UID) {
+		w.fsCache.DeleteEntry(fsvc)
+	}
+	return nil
+}
+
+// unloadFromHosts unloads fn's generation (every generation if purge) from
+// each host pod of its namespace.
+func (w *Wasm) unloadFromHosts(ctx context.Context, fn *fv1.Function, purge bool) {
+	ns := w.nsResolver.GetFunctionNS(fn.Namespace)
+	pods, err := w.hostPods(ctx, ns)
+	if err != nil {
+		w.logger.Error(err, "error listing wasm hosts", "namespace", ns)
+		return
+	}
+	generation := fn.Generation
+	if purge {
+		generation = 0
+	}
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" {
+			continue
+		}
+		addr := net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(w.hostPort))
+		if err := w.hostUnload(ctx, addr, &fn.ObjectMeta, generation, purge); err != nil {
+			w.logger.Error(err, "error unloading function from wasm host",
+				"function", fn.Name, "namespace", fn.Namespace, "pod", pod.Name)
+		}
+	}
+}
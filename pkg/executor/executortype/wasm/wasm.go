@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wasm implements the wasm executor type. It runs the WASI modules of
+// functions in a shared, long-lived host pod per function namespace (see
+// pkg/wasmhost) rather than a pod per function: specializing a function loads
+// its module into the host through the host's fetcher, and every request to
+// the function runs a fresh instance of the module under the function's
+// memory and execution-time limits. An idle function is unloaded from the
+// host; the host pod itself stays.
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sync/errgroup"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	k8sCache "k8s.io/client-go/tools/cache"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/crd"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/executor/executortype"
+	"github.com/fission/fission/pkg/executor/fscache"
+	"github.com/fission/fission/pkg/executor/metrics"
+	"github.com/fission/fission/pkg/executor/reaper"
+	"github.com/fission/fission/pkg/executor/reaper/idle"
+	executorUtils "github.com/fission/fission/pkg/executor/util"
+	fetcherConfig "github.com/fission/fission/pkg/fetcher/config"
+	"github.com/fission/fission/pkg/generated/clientset/versioned"
+	"github.com/fission/fission/pkg/svcinfo"
+	"github.com/fission/fission/pkg/throttler"
+	"github.com/fission/fission/pkg/utils"
+	otelUtils "github.com/fission/fission/pkg/utils/otel"
+)
+
+var (
+	_ executortype.ExecutorType   = &Wasm{}
+	_ executortype.FuncReconciler = &Wasm{}
+)
+
+type (
+	// Wasm represents an executor type
+	Wasm struct {
+		logger logr.Logger
+
+		kubernetesClient kubernetes.Interface
+		fissionClient    versioned.Interface
+		fetcherConfig    *fetcherConfig.Config
+		instanceID       string
+		nsResolver       *utils.NamespaceResolver
+
+		// hostImage is the fission-bundle image the host pods run
+		// (WASM_HOST_IMAGE); empty disables the executor type.
+		hostImage              string
+		runtimeImagePullPolicy apiv1.PullPolicy
+
+		// hostPort and fetcherPort are the host pod ports of the wasm host
+		// and its fetcher; fixed outside tests.
+		hostPort    int
+		fetcherPort int
+
+		fsCache   *fscache.FunctionServiceCache // cache funcSvc's by function, address and pod name
+		throttler *throttler.Throttler
+
+		defaultIdlePodReapTime     time.Duration
+		objectReaperIntervalSecond time.Duration
+	}
+)
+
+// MakeWasm initializes and returns an instance of the wasm executor type.
+func MakeWasm(
+	ctx context.Context,
+	logger logr.Logger,
+	fissionClient versioned.Interface,
+	kubernetesClient kubernetes.Interface,
+	fetcherConfig *fetcherConfig.Config,
+	instanceID string,
+) (executortype.ExecutorType, error) {
+	w := &Wasm{
+		logger: logger.WithName("wasm"),
+
+		fissionClient:    fissionClient,
+		kubernetesClient: kubernetesClient,
+		fetcherConfig:    fetcherConfig,
+		instanceID:       instanceID,
+		nsResolver:       utils.DefaultNSResolver(),
+
+		hostImage:              os.Getenv("WASM_HOST_IMAGE"),
+		runtimeImagePullPolicy: utils.GetImagePullPolicy(os.Getenv("RUNTIME_IMAGE_PULL_POLICY")),
+
+		hostPort:    svcinfo.PortEnvRuntime,
+		fetcherPort: svcinfo.PortFetcher,
+
+		fsCache:   fscache.MakeFunctionServiceCache(logger),
+		throttler: throttler.MakeThrottler(1 * time.Minute),
+
+		// Unloading only drops a compiled module, and loading it again
+		// costs a compile rather than a pod, so idle functions go sooner
+		// than newdeploy's.
+		defaultIdlePodReapTime:     1 * time.Minute,
+		objectReaperIntervalSecond: time.Duration(executorUtils.GetObjectReaperInterval(logger, fv1.ExecutorTypeWasm, 5)) * time.Second,
+	}
+	if w.hostImage == "" {
+		w.logger.Info("WASM_HOST_IMAGE is not set; functions of executor type wasm cannot be served")
+	}
+	return w, nil
+}
+
+// Run is a no-op: the wasm executor type has no background work of its own.
+func (w *Wasm) Run(ctx context.Context, mgr *errgroup.Group) {}
+
+// GetTypeName returns the executor type name.
+func (w *Wasm) GetTypeName(ctx context.Context) fv1.ExecutorType {
+	return fv1.ExecutorTypeWasm
+}
+
+// UnTapService has not been implemented for wasm.
+func (w *Wasm) UnTapService(ctx context.Context, fnMeta *metav1.ObjectMeta, svcHost string) {
+	// Not Implemented for wasm.
+}
+
+// MarkSpecializationFailure has not been implemented for wasm.
+func (w *Wasm) MarkSpecializationFailure(ctx context.Context, fnMeta *metav1.ObjectMeta) {
+	// Not Implemented for wasm.
+}
+
+// GetFuncSvc loads the function into its namespace's wasm host and returns
+// the host's address.
+func (w *Wasm) GetFuncSvc(ctx context.Context, fn *fv1.Function) (*fscache.FuncSvc, error) {
+	return w.loadFunction(ctx, fn)
+}
+
+// GetFuncSvcFromCache returns a function service from cache; error otherwise.
+// Keyed (UID, Generation): each generation is its own module in the host.
+func (w *Wasm) GetFuncSvcFromCache(ctx context.Context, fn *fv1.Function) (*fscache.FuncSvc, error) {
+	otelUtils.SpanTrackEvent(ctx, "GetFuncSvcFromCache", otelUtils.GetAttributesForFunction(fn)...)
+	return w.fsCache.GetByFunction(&fn.ObjectMeta)
+}
+
+// DeleteFuncSvcFromCache deletes a function service from cache.
+func (w *Wasm) DeleteFuncSvcFromCache(ctx context.Context, fsvc *fscache.FuncSvc) {
+	w.fsCache.DeleteEntry(fsvc)
+}
+
+// TapService makes a TouchByAddress request to the cache. The executor taps
+// wasm functions through TapFunction instead, since a host's address is
+// shared by its functions.
+func (w *Wasm) TapService(ctx context.Context, svcHost string) error {
+	return w.fsCache.TouchByAddress(svcHost)
+}
+
+// TapFunction updates the access time of the function's cache entry.
+func (w *Wasm) TapFunction(ctx context.Context, fnMeta *metav1.ObjectMeta) error {
+	return w.fsCache.TouchByFunction(fnMeta)
+}
+
+// IsValid asks the host at the function service's address whether the
+// function is still loaded; a replaced or restarted host has lost it.
+func (w *Wasm) IsValid(ctx context.Context, fsvc *fscache.FuncSvc) bool {
+	otelUtils.SpanTrackEvent(ctx, "IsValid", fscache.GetAttributesForFuncSvc(fsvc)...)
+	loaded, err := w.hostLoaded(ctx, fsvc.Address, fsvc.Function)
+	if err != nil {
+		otelUtils.LoggerWithTraceID(ctx, w.logger).V(1).Info("error checking function in wasm host",
+			"function", fsvc.Function.Name, "address", fsvc.Address, "error", err.Error())
+		return false
+	}
+	return loaded
+}
+
+// RefreshFuncPods is a no-op: wasm functions reference no secrets or
+// configmaps (see FunctionSpec.Validate).
+func (w *Wasm) RefreshFuncPods(ctx context.Context, logger logr.Logger, f fv1.Function) error {
+	return nil
+}
+
+// AdoptExistingResources re-stamps the host Deployments a previous executor
+// instance created, so CleanupOldExecutorObjects keeps them and the modules
+// they have loaded.
+func (w *Wasm) AdoptExistingResources(ctx context.Context) {
+	for _, ns := range w.nsResolver.FunctionNamespaces() {
+		if err := w.adoptHost(ctx, ns); err != nil {
+			w.logger.Error(err, "error adopting wasm host", "namespace", ns)
+		}
+	}
+}
+
+// CleanupOldExecutorObjects cleans orphaned resources.
+func (w *Wasm) CleanupOldExecutorObjects(ctx context.Context) {
+	reaper.CleanupExecutorObjects(ctx, w.logger, w.kubernetesClient, w.instanceID, fv1.ExecutorTypeWasm)
+}
+
+// IdleStrategy returns the wasm idle-reaping strategy (unload the idle
+// function from its host), run by the shared idle reaper.
+func (w *Wasm) IdleStrategy() idle.Strategy {
+	return idle.NewUnloadStrategy(w.logger, fv1.ExecutorTypeWasm, w.fissionClient,
+		w.fsCache, w.defaultIdlePodReapTime, w.objectReaperIntervalSecond,
+		func(ctx context.Context, fsvc *fscache.FuncSvc) error {
+			return w.hostUnload(ctx, fsvc.Address, fsvc.Function, fsvc.Function.Generation, false)
+		})
+}
+
+func (w *Wasm) DumpDebugInfo(ctx context.Context) error {
+	return nil
+}
+
+func (w *Wasm) loadFunction(ctx context.Context, fn *fv1.Function) (*fscache.FuncSvc, error) {
+	if fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType != fv1.ExecutorTypeWasm {
+		return nil, nil
+	}
+
+	// Single-flight per (UID, Generation): each generation is loaded as its
+	// own module, and a loser must not be handed another generation's fsvc.
+	fsvcObj, err := w.throttler.RunOnce(crd.CacheKeyUGFromMeta(&fn.ObjectMeta).String(), func(ableToCreate bool) (any, error) {
+		if ableToCreate {
+			return w.fnLoad(ctx, fn)
+		}
+		return w.fsCache.GetByFunction(&fn.ObjectMeta)
+	})
+	if err != nil {
+		w.logger.Error(err, "error loading function into wasm host", "function_name", fn.Name,
+			"function_namespace", fn.Namespace)
+		return nil, fmt.Errorf("error loading function %s/%s into wasm host: %w", fn.Namespace, fn.Name, err)
+	}
+
+	fsvc, ok := fsvcObj.(*fscache.FuncSvc)
+	if !ok {
+		w.logger.Error(nil, "receive unknown object while loading function - expected pointer of function service object")
+
+		panic("receive unknown object while loading function - expected pointer of function service object")
+	}
+	return fsvc, nil
+}
+
+func (w *Wasm) fnLoad(ctx context.Context, fn *fv1.Function) (*fscache.FuncSvc, error) {
+	if w.hostImage == "" {
+		return nil, ferror.MakeError(ferror.ErrorInvalidArgument,
+			"executor type wasm is not enabled: the executor has no WASM_HOST_IMAGE")
+	}
+	// Authoritative re-read, as container's fnCreate: the router's copy of
+	// the function can be stale, and loading a function that is being
+	// deleted would leave its module in the host with nothing to unload it.
+	live, err := w.fissionClient.CoreV1().Functions(fn.Namespace).Get(ctx, fn.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrs.IsNotFound(err) {
+			return nil, ferror.MakeError(ferror.ErrorNotFound,
+				fmt.Sprintf("function %s is gone, not loading it", k8sCache.MetaObjectToName(fn)))
+		}
+		return nil, err
+	}
+	if live.UID != fn.UID || !live.DeletionTimestamp.IsZero() {
+		return nil, ferror.MakeError(ferror.ErrorNotFound,
+			fmt.Sprintf("function %s is being deleted, not loading it", k8sCache.MetaObjectToName(fn)))
+	}
+
+	ns := w.nsResolver.GetFunctionNS(fn.Namespace)
+	if err := w.ensureHost(ctx, ns); err != nil {
+		return nil, fmt.Errorf("error creating wasm host: %w", err)
+	}
+	pod, err := w.waitForHostPod(ctx, ns)
+	if err != nil {
+		metrics.RecordColdStartError(ctx, fn.Name, fn.Namespace)
+		return nil, err
+	}
+	if err := w.specialize(ctx, pod, fn); err != nil {
+		metrics.RecordColdStartError(ctx, fn.Name, fn.Namespace)
+		return nil, err
+	}
+
+	fsvc := &fscache.FuncSvc{
+		Name:     pod.Name,
+		Function: &fn.ObjectMeta,
+		Address:  net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(w.hostPort)),
+		KubernetesObjects: []apiv1.ObjectReference{
+			{
+				Kind:            "pod",
+				Name:            pod.Name,
+				APIVersion:      pod.APIVersion,
+				Namespace:       pod.Namespace,
+				ResourceVersion: pod.ResourceVersion,
+				UID:             pod.UID,
+			},
+		},
+		Executor: fv1.ExecutorTypeWasm,
+	}
+	if _, err := w.fsCache.Add(*fsvc); err != nil {
+		w.logger.Error(err, "error adding function to cache", "function", fsvc.Function)
+		metrics.RecordColdStartError(ctx, fn.Name, fn.Namespace)
+		return fsvc, err
+	}
+
+	metrics.RecordColdStart(ctx, fn.Name, fn.Namespace)
+	executorUtils.SetFunctionReady(ctx, w.logger, w.fissionClient, fn, fv1.FunctionReasonReady, "function is loaded into the wasm host")
+	return fsvc, nil
+}
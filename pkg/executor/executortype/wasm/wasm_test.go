@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
+	"github.com/fission/fission/pkg/executor/fscache"
+	executorUtils "github.com/fission/fission/pkg/executor/util"
+	"github.com/fission/fission/pkg/fetcher"
+	fetcherConfig "github.com/fission/fission/pkg/fetcher/config"
+	fakeFission "github.com/fission/fission/pkg/generated/clientset/versioned/fake"
+	"github.com/fission/fission/pkg/throttler"
+	"github.com/fission/fission/pkg/utils"
+)
+
+// fakeHost stands in for a host pod: its fetcher's /specialize and the
+// host's loaded/unload API, on two ports of 127.0.0.1. With a master the
+// host API verifies signatures like the real host does.
+type fakeHost struct {
+	master []byte
+
+	mu          sync.Mutex
+	specialized []fetcher.FunctionSpecializeRequest
+	unloaded    []string
+	loaded      bool
+}
+
+func (h *fakeHost) fetcherHandler(w http.ResponseWriter, r *http.Request) {
+	var req fetcher.FunctionSpecializeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.specialized = append(h.specialized, req)
+	h.loaded = true
+}
+
+func (h *fakeHost) hostHandler(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch r.URL.Path {
+	case "/v2/loaded":
+		if !h.loaded {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case "/v2/unload":
+		h.unloaded = append(h.unloaded, r.URL.RawQuery)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func port(t *testing.T, srv *httptest.Server) int {
+	t.Helper()
+	_, p, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	n, err := strconv.Atoi(p)
+	require.NoError(t, err)
+	return n
+}
+
+func newTestWasm(t *testing.T, fn *fv1.Function, host *fakeHost) *Wasm {
+	t.Helper()
+	fetcherSrv := httptest.NewServer(http.HandlerFunc(host.fetcherHandler))
+	t.Cleanup(fetcherSrv.Close)
+	var hostHandler http.Handler = http.HandlerFunc(host.hostHandler)
+	if host.master != nil {
+		hostHandler = hmacauth.ServiceVerifier(host.master, nil, hmacauth.ServiceFetcher, hmacauth.VerifierOpts{
+			SkewSec: 60, MaxBodyBytes: hmacauth.DefaultMaxBodyBytes,
+		})(hostHandler)
+	}
+	hostSrv := httptest.NewServer(hostHandler)
+	t.Cleanup(hostSrv.Close)
+
+	fc, err := fetcherConfig.MakeFetcherConfig("/userfunc")
+	require.NoError(t, err)
+
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "wasm-host-abc", Namespace: fn.Namespace, Labels: hostLabels()},
+		Status: apiv1.PodStatus{
+			Phase:             apiv1.PodRunning,
+			PodIP:             "127.0.0.1",
+			ContainerStatuses: []apiv1.ContainerStatus{{Name: hostName, Ready: true}},
+		},
+	}
+	return &Wasm{
+		logger:           logr.Discard(),
+		kubernetesClient: fake.NewClientset(pod),
+		fissionClient:    fakeFission.NewClientset(fn),
+		fetcherConfig:    fc,
+		instanceID:       "instance",
+		nsResolver:       utils.DefaultNSResolver(),
+		hostImage:        "fission-bundle",
+		hostPort:         port(t, hostSrv),
+		fetcherPort:      port(t, fetcherSrv),
+		fsCache:          fscache.MakeFunctionServiceCache(logr.Discard()),
+		throttler:        throttler.MakeThrottler(time.Minute),
+
+		defaultIdlePodReapTime:     time.Minute,
+		objectReaperIntervalSecond: time.Minute,
+	}
+}
+
+func wasmFunction() *fv1.Function {
+	return &fv1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "default", UID: "uid-fn", Generation: 2},
+		Spec: fv1.FunctionSpec{
+			Package: fv1.FunctionPackageRef{
+				PackageRef: fv1.PackageRef{Name: "pkg", Namespace: "default"},
+			},
+			InvokeStrategy: fv1.InvokeStrategy{
+				ExecutionStrategy: fv1.ExecutionStrategy{ExecutorType: fv1.ExecutorTypeWasm},
+			},
+		},
+	}
+}
+
+func TestGetFuncSvc(t *testing.T) {
+	fn := wasmFunction()
+	host := &fakeHost{}
+	w := newTestWasm(t, fn, host)
+
+	fsvc, err := w.GetFuncSvc(t.Context(), fn)
+	require.NoError(t, err)
+	assert.Equal(t, net.JoinHostPort("127.0.0.1", strconv.Itoa(w.hostPort)), fsvc.Address)
+	assert.Equal(t, fv1.ExecutorTypeWasm, fsvc.Executor)
+	require.Len(t, host.specialized, 1)
+	assert.Equal(t, fn.UID, host.specialized[0].LoadReq.FunctionMetadata.UID)
+
+	// The namespace got its host.
+	_, err = w.kubernetesClient.AppsV1().Deployments(fn.Namespace).Get(t.Context(), hostName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	cached, err := w.GetFuncSvcFromCache(t.Context(), fn)
+	require.NoError(t, err)
+	assert.Equal(t, fsvc.Address, cached.Address)
+	assert.True(t, w.IsValid(t.Context(), fsvc))
+	require.NoError(t, w.TapFunction(t.Context(), &fn.ObjectMeta))
+
+	// A host that lost the function invalidates the entry.
+	host.mu.Lock()
+	host.loaded = false
+	host.mu.Unlock()
+	assert.False(t, w.IsValid(t.Context(), fsvc))
+
+	// Deleting the function purges it from the host and the cache.
+	require.NoError(t, w.DeleteFunction(t.Context(), fn))
+	assert.Equal(t, []string{"purge=true&uid=uid-fn"}, host.unloaded)
+	_, err = w.GetFuncSvcFromCache(t.Context(), fn)
+	assert.Error(t, err)
+}
+
+func TestGetFuncSvcDeletedFunction(t *testing.T) {
+	fn := wasmFunction()
+	host := &fakeHost{}
+	w := newTestWasm(t, fn, host)
+	require.NoError(t, w.fissionClient.CoreV1().Functions(fn.Namespace).Delete(t.Context(), fn.Name, metav1.DeleteOptions{}))
+
+	_, err := w.GetFuncSvc(t.Context(), fn)
+	require.Error(t, err)
+	assert.Empty(t, host.specialized, "a deleted function is not loaded")
+}
+
+func TestReconcileFunctionEvictsOldGeneration(t *testing.T) {
+	fn := wasmFunction()
+	host := &fakeHost{}
+	w := newTestWasm(t, fn, host)
+
+	old := fn.DeepCopy()
+	old.Generation = 1
+	_, err := w.fsCache.Add(fscache.FuncSvc{
+		Name: "wasm-host-abc", Function: &old.ObjectMeta, Address: "127.0.0.1:1", Executor: fv1.ExecutorTypeWasm,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, w.ReconcileFunction(t.Context(), old, fn))
+	assert.Equal(t, []string{"generation=1&uid=uid-fn"}, host.unloaded)
+	_, err = w.fsCache.GetByFunction(&old.ObjectMeta)
+	assert.Error(t, err)
+
+	// An unchanged generation leaves the hosts alone.
+	require.NoError(t, w.ReconcileFunction(t.Context(), fn, fn))
+	assert.Len(t, host.unloaded, 1)
+}
+
+func TestHostDeployment(t *testing.T) {
+	w := newTestWasm(t, wasmFunction(), &fakeHost{})
+	depl, err := w.hostDeployment("default")
+	require.NoError(t, err)
+
+	assert.Equal(t, "instance", depl.Annotations[fv1.EXECUTOR_INSTANCEID_LABEL])
+	spec := depl.Spec.Template.Spec
+	require.Len(t, spec.Containers, 2)
+	assert.Equal(t, []string{"/fission-bundle", "--wasmHostPort", strconv.Itoa(w.hostPort)}, spec.Containers[0].Command)
+	assert.Equal(t, executorUtils.FetcherContainerName, spec.Containers[1].Name)
+	assert.False(t, *spec.AutomountServiceAccountToken)
+	assert.Equal(t, fv1.FissionFetcherSA, spec.ServiceAccountName)
+	// Only the fetcher gets the ServiceAccount token.
+	mountsToken := func(c apiv1.Container) bool {
+		for _, vm := range c.VolumeMounts {
+			if vm.MountPath == executorUtils.FetcherSATokenMountPath {
+				return true
+			}
+		}
+		return false
+	}
+	assert.False(t, mountsToken(spec.Containers[0]))
+	assert.True(t, mountsToken(spec.Containers[1]))
+
+	// The host verifies its specialization API with the fetcher's key, and
+	// the fetcher signs its load request with it.
+	assert.Contains(t, spec.Containers[1].Command, "-sign-specialize")
+	var hostEnv []string
+	for _, e := range spec.Containers[0].Env {
+		hostEnv = append(hostEnv, e.Name)
+	}
+	assert.Contains(t, hostEnv, "FISSION_INTERNAL_AUTH_SECRET")
+	assert.Contains(t, hostEnv, "FISSION_FETCHER_KEY")
+}
+
+func TestHostCallsAreSigned(t *testing.T) {
+	t.Setenv(fv1.InternalAuthSecretEnv, "master")
+	fn := wasmFunction()
+	host := &fakeHost{master: []byte("master"), loaded: true}
+	w := newTestWasm(t, fn, host)
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(w.hostPort))
+
+	resp, err := http.Get("http://" + addr + "/v2/loaded?uid=uid-fn&generation=2")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	loaded, err := w.hostLoaded(t.Context(), addr, &fn.ObjectMeta)
+	require.NoError(t, err)
+	assert.True(t, loaded)
+	require.NoError(t, w.hostUnload(t.Context(), addr, &fn.ObjectMeta, 0, true))
+	assert.Equal(t, []string{"purge=true&uid=uid-fn"}, host.unloaded)
+}
@@ -277,6 +277,12 @@ func (fsc *FunctionServiceCache) SetCPUUtilizaton(key crd.CacheKeyUG, svcHost st
 	fsc.connFunctionCache.SetCPUUtilization(key, svcHost, cpuUsage)
 }
 
+// ActiveRequests returns the function's in-flight requests the pool cache
+// accounts for (see PoolCache.ActiveRequests).
+func (fsc *FunctionServiceCache) ActiveRequests(uid types.UID) int {
+	return fsc.connFunctionCache.ActiveRequests(uid)
+}
+
 // MarkAvailable marks the value at key [function][address] as available.
 func (fsc *FunctionServiceCache) MarkAvailable(key crd.CacheKeyUG, svcHost string) {
 	fsc.connFunctionCache.MarkAvailable(key, svcHost)
@@ -354,6 +360,21 @@ func (fsc *FunctionServiceCache) _touchByAddress(address string) error {
 	return nil
 }
 
+// TouchByFunction updates the access time of the function's entry. Executors
+// whose functions share an address (one wasm host serves many functions) tap
+// through here, where TouchByAddress would touch whichever function was
+// added at that address last.
+func (fsc *FunctionServiceCache) TouchByFunction(m *metav1.ObjectMeta) error {
+	fsc.lock.Lock()
+	defer fsc.lock.Unlock()
+	fsvc, err := fsc.byFunction.Get(crd.CacheKeyUGFromMeta(m))
+	if err != nil {
+		return err
+	}
+	fsvc.Atime = time.Now()
+	return nil
+}
+
 // DeleteEntry deletes a function service from cache. The byFunctionUG
 // delete is scoped to this fsvc's own (UID, Generation): evicting one
 // version's entry must leave sibling generations sharing the UID reachable
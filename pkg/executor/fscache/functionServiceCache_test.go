@@ -339,6 +339,36 @@ func TestTouchByAddressPoolCacheFallback(t *testing.T) {
 	require.Error(t, err, "an address unknown to BOTH caches still errors")
 }
 
+func TestTouchByFunctionSharedAddress(t *testing.T) {
+	fsc := MakeFunctionServiceCache(loggerfactory.GetLogger())
+	require.NotNil(t, fsc)
+
+	// Two functions served at one address, as by a wasm host.
+	a := &metav1.ObjectMeta{Name: "a", Namespace: "default", UID: "uid-a", Generation: 1}
+	b := &metav1.ObjectMeta{Name: "b", Namespace: "default", UID: "uid-b", Generation: 1}
+	for _, m := range []*metav1.ObjectMeta{a, b} {
+		_, err := fsc.Add(FuncSvc{Name: "wasm-host", Function: m, Address: "10.3.4.5:8888"})
+		require.NoError(t, err)
+	}
+	old := time.Now().Add(-time.Hour)
+	for _, m := range []*metav1.ObjectMeta{a, b} {
+		fsvc, err := fsc.byFunction.Get(crd.CacheKeyUGFromMeta(m))
+		require.NoError(t, err)
+		fsvc.Atime = old
+	}
+
+	require.NoError(t, fsc.TouchByFunction(a))
+	fsvcA, err := fsc.byFunction.Get(crd.CacheKeyUGFromMeta(a))
+	require.NoError(t, err)
+	fsvcB, err := fsc.byFunction.Get(crd.CacheKeyUGFromMeta(b))
+	require.NoError(t, err)
+	require.True(t, fsvcA.Atime.After(old), "the tapped function is touched")
+	require.Equal(t, old, fsvcB.Atime, "the function sharing its address is not")
+
+	err = fsc.TouchByFunction(&metav1.ObjectMeta{UID: "uid-unknown", Generation: 1})
+	require.True(t, IsNotFoundError(err))
+}
+
 // versionedPairFixture seeds the cache with two entries sharing one function
 // UID at different Generations — the RFC-0025 shape where a live function
 // (gen 5, no version label) coexists with a versioned projection (gen 2,
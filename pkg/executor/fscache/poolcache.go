@@ -226,6 +226,28 @@ func (c *PoolCache) TouchByAddress(address string) error {
 	return nil
 }
 
+// ActiveRequests returns the requests the executor has handed out to the
+// function's pods and not yet had released, plus those waiting on a
+// specialization, across every generation of the function. It is the
+// executor's own view of the function's concurrency: requests the router
+// serves from its endpoint index never pass through here.
+func (c *PoolCache) ActiveRequests(uid types.UID) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	n := 0
+	for key, grp := range c.cache {
+		if key.UID != uid {
+			continue
+		}
+		for _, info := range grp.svcs {
+			n += info.activeRequests
+		}
+		n += grp.svcWaiting
+	}
+	return n
+}
+
 // ListAvailableValue returns a list of the available function services stored
 // in the Cache. retained exempts a non-latest generation from the "drain
 // everything but the latest generation" rule below when it reports true for
@@ -564,3 +564,23 @@ func TestPoolCacheTouchByAddress(t *testing.T) {
 	err := c.TouchByAddress("10.9.9.9:1")
 	require.Error(t, err, "unknown address still 404s")
 }
+
+func TestPoolCacheActiveRequests(t *testing.T) {
+	gen1 := crd.CacheKeyUG{UID: "active-fn", Generation: 1}
+	gen2 := crd.CacheKeyUG{UID: "active-fn", Generation: 2}
+	other := crd.CacheKeyUG{UID: "other-fn", Generation: 1}
+	c := NewPoolCache(logr.Discard())
+
+	for key, addr := range map[crd.CacheKeyUG]string{gen1: "10.0.0.1:8888", gen2: "10.0.0.2:8888", other: "10.0.0.3:8888"} {
+		c.SetSvcValue(t.Context(), key, addr, &FuncSvc{Function: &metav1.ObjectMeta{Name: "fn"}, Address: addr}, resource.MustParse("45m"), 10, 0)
+	}
+	base := c.ActiveRequests("active-fn")
+
+	_, err := c.GetSvcValue(t.Context(), gen2, 10, 5)
+	require.NoError(t, err)
+	assert.Equal(t, base+1, c.ActiveRequests("active-fn"), "every generation of the function counts")
+
+	c.MarkAvailable(gen2, "10.0.0.2:8888")
+	assert.Equal(t, base, c.ActiveRequests("active-fn"))
+	assert.Zero(t, c.ActiveRequests("missing-fn"))
+}
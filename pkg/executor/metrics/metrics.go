@@ -83,8 +83,81 @@ var (
 		"fission_provisioned_window_transitions_total",
 		"The number of provisioned window transitions for each function, function_namespace.",
 	)
+
+	// Concurrency scaling (ExecutionStrategy.ConcurrencyScaling) of newdeploy
+	// and container functions: the in-flight requests the routers report and
+	// the HPA minimum replicas derived from them.
+	concurrencyInflight = metrics.Int64Gauge(
+		"fission_executor_concurrency_inflight_requests",
+		"In-flight requests the routers report for a concurrency-scaled function, by function_name, function_namespace.",
+	)
+	concurrencyMinReplicas = metrics.Int64Gauge(
+		"fission_executor_concurrency_min_replicas",
+		"HPA minimum replicas derived from the in-flight requests of a concurrency-scaled function, by function_name, function_namespace.",
+	)
+
+	// Usage accounting: the time function pods were scheduled, and that time
+	// weighted by their requests, so cluster cost can be attributed to
+	// functions. Warm poolmgr pods are charged to their environment instead.
+	functionPodSeconds = metrics.Float64Counter(
+		"fission_function_pod_seconds_total",
+		"Seconds function pods were scheduled, by function_name, function_namespace, function_version, executor_type.",
+	)
+	functionCPUCoreSeconds = metrics.Float64Counter(
+		"fission_function_cpu_request_core_seconds_total",
+		"Requested CPU cores times seconds of function pods, by function_name, function_namespace, function_version, executor_type.",
+	)
+	functionMemoryByteSeconds = metrics.Float64Counter(
+		"fission_function_memory_request_byte_seconds_total",
+		"Requested memory bytes times seconds of function pods, by function_name, function_namespace, function_version, executor_type.",
+	)
+	envPoolPodSeconds = metrics.Float64Counter(
+		"fission_environment_pool_pod_seconds_total",
+		"Seconds warm pool pods were scheduled, by environment_name, environment_namespace.",
+	)
+	envPoolCPUCoreSeconds = metrics.Float64Counter(
+		"fission_environment_pool_cpu_request_core_seconds_total",
+		"Requested CPU cores times seconds of warm pool pods, by environment_name, environment_namespace.",
+	)
+	envPoolMemoryByteSeconds = metrics.Float64Counter(
+		"fission_environment_pool_memory_request_byte_seconds_total",
+		"Requested memory bytes times seconds of warm pool pods, by environment_name, environment_namespace.",
+	)
 )
 
+// RecordFunctionUsage adds one sample of a function version's pod time and
+// request-weighted resource time.
+func RecordFunctionUsage(ctx context.Context, fnName, fnNamespace, fnVersion, executorType string, podSeconds, cpuCoreSeconds, memoryByteSeconds float64) {
+	attrs := metric.WithAttributes(
+		attribute.String("function_name", fnName),
+		attribute.String("function_namespace", fnNamespace),
+		attribute.String("function_version", fnVersion),
+		attribute.String("executor_type", executorType),
+	)
+	functionPodSeconds.Add(ctx, podSeconds, attrs)
+	functionCPUCoreSeconds.Add(ctx, cpuCoreSeconds, attrs)
+	functionMemoryByteSeconds.Add(ctx, memoryByteSeconds, attrs)
+}
+
+// RecordEnvironmentPoolUsage adds one sample of an environment's warm-pool
+// pod time and request-weighted resource time.
+func RecordEnvironmentPoolUsage(ctx context.Context, envName, envNamespace string, podSeconds, cpuCoreSeconds, memoryByteSeconds float64) {
+	attrs := metric.WithAttributes(
+		attribute.String("environment_name", envName),
+		attribute.String("environment_namespace", envNamespace),
+	)
+	envPoolPodSeconds.Add(ctx, podSeconds, attrs)
+	envPoolCPUCoreSeconds.Add(ctx, cpuCoreSeconds, attrs)
+	envPoolMemoryByteSeconds.Add(ctx, memoryByteSeconds, attrs)
+}
+
+// RecordConcurrencyScaling records a concurrency-scaled function's in-flight
+// requests and the HPA minimum replicas derived from them.
+func RecordConcurrencyScaling(ctx context.Context, fnName, fnNamespace string, inflight, minReplicas int64) {
+	concurrencyInflight.Record(ctx, inflight, functionLabels(fnName, fnNamespace))
+	concurrencyMinReplicas.Record(ctx, minReplicas, functionLabels(fnName, fnNamespace))
+}
+
 func RecordProvisionedWindowTransition(ctx context.Context, fnName, fnNamespace string) {
 	fissionProvisionedWindowTransitions.Add(ctx, 1, functionLabels(fnName, fnNamespace))
 }
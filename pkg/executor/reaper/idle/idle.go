@@ -27,6 +27,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/executor/drain"
 	"github.com/fission/fission/pkg/executor/fscache"
 	"github.com/fission/fission/pkg/executor/reaper"
 	"github.com/fission/fission/pkg/generated/clientset/versioned"
@@ -195,6 +196,12 @@ type PoolDeleteStrategy struct {
 	// function-Services flag — without slices there is nothing to drain from.
 	drainBeforeDelete bool
 
+	// drainer, when set, lets a two-step reap delete as soon as every router
+	// replica reports nothing in flight on the pod instead of always waiting
+	// out the drain grace, which becomes the upper bound. nil keeps the fixed
+	// grace.
+	drainer Drainer
+
 	// retained reports whether a live FunctionAlias still references a
 	// (function UID, generation) pin (RFC-0025 versionretain.View.Retained),
 	// forwarded to ListOldForPool so a non-latest generation an alias points
@@ -212,7 +219,7 @@ type PoolDeleteStrategy struct {
 	provisionedPods map[string]struct{}
 }
 
-func NewPoolDeleteStrategy(logger logr.Logger, fissionClient versioned.Interface, fsCache *fscache.FunctionServiceCache, kubeClient kubernetes.Interface, reapTime, interval time.Duration, drainBeforeDelete bool, retained func(uid k8sTypes.UID, gen int64) bool) *PoolDeleteStrategy {
+func NewPoolDeleteStrategy(logger logr.Logger, fissionClient versioned.Interface, fsCache *fscache.FunctionServiceCache, kubeClient kubernetes.Interface, reapTime, interval time.Duration, drainBeforeDelete bool, drainer Drainer, retained func(uid k8sTypes.UID, gen int64) bool) *PoolDeleteStrategy {
 	return &PoolDeleteStrategy{
 		logger:            logger,
 		fissionClient:     fissionClient,
@@ -221,6 +228,7 @@ func NewPoolDeleteStrategy(logger logr.Logger, fissionClient versioned.Interface
 		reapTime:          reapTime,
 		interval:          interval,
 		drainBeforeDelete: drainBeforeDelete,
+		drainer:           drainer,
 		retained:          retained,
 	}
 }
@@ -331,6 +339,13 @@ func (s *PoolDeleteStrategy) Reap(ctx context.Context, fsvc *fscache.FuncSvc) er
 	return nil
 }
 
+// Drainer confirms that no router replica has requests in flight on a pod
+// before it is deleted; satisfied by *drain.Coordinator. Drain reports false
+// when bound elapsed without confirmation.
+type Drainer interface {
+	Drain(ctx context.Context, req drain.Request, bound time.Duration) bool
+}
+
 // drainGraceCap bounds the drain grace so functions with very long timeouts
 // don't pin reaped pods for that long (RFC-0002 open question resolved: cap).
 const (
@@ -340,11 +355,12 @@ const (
 
 // drainThenDelete is the RFC-0002 two-step reap: unlabel now (the pod drops
 // out of its function Service's EndpointSlices, and routers stop admitting it
-// within watch latency), delete after a grace long enough for in-flight
-// requests to finish. The delayed delete is detached (fire-and-forget): if the
-// executor restarts before it fires, the orphaned pod is re-adopted into the
-// fsCache by the adopt pass and reaped again on a later tick — self-healing,
-// no leak.
+// within watch latency), delete once in-flight requests have finished. With a
+// drainer that is as soon as every router replica reports the pod idle, with
+// the grace as the upper bound; without one, after the full grace. The
+// delayed delete is detached (fire-and-forget): if the executor restarts
+// before it fires, the orphaned pod is re-adopted into the fsCache by the
+// adopt pass and reaped again on a later tick — self-healing, no leak.
 func (s *PoolDeleteStrategy) drainThenDelete(ctx context.Context, fsvc *fscache.FuncSvc) {
 	grace := drainGraceMin
 	if fn, ok := s.fnByUID[fsvc.Function.UID]; ok {
@@ -377,43 +393,79 @@ func (s *PoolDeleteStrategy) drainThenDelete(ctx context.Context, fsvc *fscache.
 		"grace", grace,
 	)
 
+	if s.drainer == nil {
+		time.AfterFunc(grace, func() { s.releaseDrained(fsvc, false) })
+		return
+	}
+	go func() {
+		// One deadline for all of the fsvc's pods, so the grace stays the
+		// bound on the whole reap.
+		deadline := time.Now().Add(grace)
+		drained := true
+		for _, obj := range fsvc.KubernetesObjects {
+			if !strings.EqualFold(obj.Kind, "pod") {
+				continue
+			}
+			req := drain.Request{
+				Namespace:   fsvc.Function.Namespace,
+				Function:    fsvc.Function.Name,
+				FunctionUID: fsvc.Function.UID,
+				Version:     fsvc.Function.Labels[fv1.FUNCTION_VERSION],
+				PodUID:      obj.UID,
+			}
+			if !s.drainer.Drain(context.Background(), req, time.Until(deadline)) {
+				drained = false
+			}
+		}
+		s.releaseDrained(fsvc, drained)
+	}()
+}
+
+// releaseDrained deletes the objects of a reaped fsvc after its drain. Pods
+// every router confirmed drained are deleted with a short termination grace
+// (reaper.DrainedPodGracePeriod); otherwise with their own.
+func (s *PoolDeleteStrategy) releaseDrained(fsvc *fscache.FuncSvc, drained bool) {
+	dctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
 	objs := fsvc.KubernetesObjects
-	logger := s.logger
-	kubeClient := s.kubeClient
-	time.AfterFunc(grace, func() {
-		dctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-		defer cancel()
-		for i := range objs {
-			logger.Info(
-				"release drained idle function resources",
-				"function", fsvc.Function.Name,
-				"address", fsvc.Address,
-				"pod", fsvc.Name,
-			)
-			// Bounded retry: the fsvc is already evicted from the cache, so
-			// nothing in the running executor would ever retry a failed delete
-			// — the unlabeled pod would leak until the next executor restart's
-			// adopt pass. Three attempts ride out an apiserver blip.
-			var derr error
-			for attempt := range 3 {
-				if attempt > 0 {
-					time.Sleep(time.Duration(attempt) * 5 * time.Second)
-				}
-				if derr = reaper.DeleteKubeObject(dctx, kubeClient, &objs[i]); derr == nil {
-					break
-				}
+	for i := range objs {
+		s.logger.Info(
+			"release drained idle function resources",
+			"function", fsvc.Function.Name,
+			"address", fsvc.Address,
+			"pod", fsvc.Name,
+			"confirmed", drained,
+		)
+		// Bounded retry: the fsvc is already evicted from the cache, so
+		// nothing in the running executor would ever retry a failed delete
+		// — the unlabeled pod would leak until the next executor restart's
+		// adopt pass. Three attempts ride out an apiserver blip.
+		var derr error
+		for attempt := range 3 {
+			if attempt > 0 {
+				time.Sleep(time.Duration(attempt) * 5 * time.Second)
 			}
-			if derr != nil {
-				logger.Error(derr, "error deleting drained idle resource; pod leaks until the next executor restart",
-					"type", objs[i].Kind, "name", objs[i].Name, "ns", objs[i].Namespace)
+			if drained && strings.EqualFold(objs[i].Kind, "pod") {
+				derr = reaper.DeleteDrainedPod(dctx, s.kubeClient, objs[i].Namespace, objs[i].Name)
+			} else {
+				derr = reaper.DeleteKubeObject(dctx, s.kubeClient, &objs[i])
+			}
+			if derr == nil {
+				break
 			}
-			time.Sleep(50 * time.Millisecond)
 		}
-	})
+		if derr != nil {
+			s.logger.Error(derr, "error deleting drained idle resource; pod leaks until the next executor restart",
+				"type", objs[i].Kind, "name", objs[i].Name, "ns", objs[i].Namespace)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
 }
 
 // ScaleDownStrategy reaps newdeploy/container function services by scaling
-// their deployment down to the function's MinScale. checkEnv mirrors the
+// their deployment down to the function's MinScale, or to zero for a
+// function with ScaleToZero; the deployment, service and HPA stay, and the
+// next request scales it back up. checkEnv mirrors the
 // newdeploy behaviour of logging (and gating the tick on) the environment list;
 // the container executor leaves it false.
 type ScaleDownStrategy struct {
@@ -492,9 +544,67 @@ func (s *ScaleDownStrategy) Reap(ctx context.Context, fsvc *fscache.FuncSvc) err
 		return err
 	}
 	minScale := int32(fn.Spec.InvokeStrategy.ExecutionStrategy.MinScale)
+	if fn.Spec.InvokeStrategy.ExecutionStrategy.ScaleToZero != nil {
+		minScale = 0
+	}
 	// Do nothing if the current replica count is already at or below MinScale.
 	if currentDeploy.Spec.Replicas != nil && *currentDeploy.Spec.Replicas <= minScale {
 		return nil
 	}
 	return scaleDeploymentToMinScale(ctx, s.logger, s.kubeClient, deployObj.Namespace, deployObj.Name, minScale)
 }
+
+// UnloadStrategy reaps the functions of a shared host (the wasm executor) by
+// unloading the idle function from its host and evicting its cache entry; the
+// host pod stays for the functions still in use, and the next request loads
+// the function again.
+type UnloadStrategy struct {
+	logger        logr.Logger
+	execType      fv1.ExecutorType
+	fissionClient versioned.Interface
+	fsCache       *fscache.FunctionServiceCache
+	reapTime      time.Duration
+	interval      time.Duration
+	unload        func(ctx context.Context, fsvc *fscache.FuncSvc) error
+}
+
+func NewUnloadStrategy(logger logr.Logger, execType fv1.ExecutorType, fissionClient versioned.Interface, fsCache *fscache.FunctionServiceCache, reapTime, interval time.Duration, unload func(ctx context.Context, fsvc *fscache.FuncSvc) error) *UnloadStrategy {
+	return &UnloadStrategy{
+		logger:        logger,
+		execType:      execType,
+		fissionClient: fissionClient,
+		fsCache:       fsCache,
+		reapTime:      reapTime,
+		interval:      interval,
+		unload:        unload,
+	}
+}
+
+func (s *UnloadStrategy) Name() string                   { return string(s.execType) }
+func (s *UnloadStrategy) ExecutorType() fv1.ExecutorType { return s.execType }
+func (s *UnloadStrategy) Interval() time.Duration        { return s.interval }
+
+func (s *UnloadStrategy) ListIdle() ([]*fscache.FuncSvc, error) {
+	return s.fsCache.ListOld(idleListAge)
+}
+
+func (s *UnloadStrategy) Prepare(ctx context.Context) error { return nil }
+
+func (s *UnloadStrategy) Reap(ctx context.Context, fsvc *fscache.FuncSvc) error {
+	fn, err := s.fissionClient.CoreV1().Functions(fsvc.Function.Namespace).Get(ctx, fsvc.Function.Name, metav1.GetOptions{})
+	if err != nil {
+		// The executor's delete handling unloads a deleted function itself.
+		if k8sErrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if time.Since(fsvc.Atime) < idleThreshold(fn, s.reapTime) {
+		return nil
+	}
+	if err := s.unload(ctx, fsvc); err != nil {
+		return err
+	}
+	s.fsCache.DeleteEntry(fsvc)
+	return nil
+}
@@ -24,7 +24,9 @@ import (
 	k8stesting "k8s.io/client-go/testing"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/executor/drain"
 	"github.com/fission/fission/pkg/executor/fscache"
+	"github.com/fission/fission/pkg/executor/reaper"
 	fissionfake "github.com/fission/fission/pkg/generated/clientset/versioned/fake"
 )
 
@@ -119,7 +121,7 @@ func TestPoolDeleteStrategy_Skips(t *testing.T) {
 	t.Parallel()
 	newStrategy := func() (*PoolDeleteStrategy, *fscache.FunctionServiceCache) {
 		fc := fscache.MakeFunctionServiceCache(logr.Discard())
-		s := NewPoolDeleteStrategy(logr.Discard(), nil, fc, k8sfake.NewSimpleClientset(), 2*time.Minute, 5*time.Second, false, nil)
+		s := NewPoolDeleteStrategy(logr.Discard(), nil, fc, k8sfake.NewSimpleClientset(), 2*time.Minute, 5*time.Second, false, nil, nil)
 		s.envUIDs = map[types.UID]struct{}{}
 		s.fnByUID = map[types.UID]fv1.Function{}
 		s.provisionedPods = map[string]struct{}{}
@@ -282,6 +284,19 @@ func TestScaleDownStrategy_Reap(t *testing.T) {
 		assert.Equal(t, int32(-1), scaled, "no scale issued when already at MinScale")
 	})
 
+	t.Run("scales a scale-to-zero deployment down to zero", func(t *testing.T) {
+		kc := k8sfake.NewSimpleClientset(deployWithReplicas(2))
+		var scaled int32 = -1
+		captureScale(kc, &scaled)
+		fn := makeFn(2)
+		fn.Spec.InvokeStrategy.ExecutionStrategy.ScaleToZero = &fv1.ScaleToZeroConfig{}
+		s := NewScaleDownStrategy(logr.Discard(), fv1.ExecutorTypeNewdeploy,
+			fissionfake.NewClientset(fn), fscache.MakeFunctionServiceCache(logr.Discard()), kc, 2*time.Minute, 5*time.Second, true)
+
+		require.NoError(t, s.Reap(t.Context(), makeFsvc()))
+		assert.Equal(t, int32(0), scaled, "ScaleToZero scales below MinScale")
+	})
+
 	t.Run("missing function is not an error", func(t *testing.T) {
 		kc := k8sfake.NewSimpleClientset(deployWithReplicas(3))
 		s := NewScaleDownStrategy(logr.Discard(), fv1.ExecutorTypeNewdeploy,
@@ -291,6 +306,47 @@ func TestScaleDownStrategy_Reap(t *testing.T) {
 	})
 }
 
+func TestUnloadStrategy_Reap(t *testing.T) {
+	t.Parallel()
+	fn := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "default", UID: "uid-fn", Generation: 1}}
+	setup := func(t *testing.T, fissionClient *fissionfake.Clientset, atime time.Time) (*fscache.FunctionServiceCache, *[]string) {
+		t.Helper()
+		fsCache := fscache.MakeFunctionServiceCache(logr.Discard())
+		_, err := fsCache.Add(fscache.FuncSvc{Name: "wasm-host", Function: &fn.ObjectMeta, Address: "10.0.0.1:8888", Executor: fv1.ExecutorTypeWasm})
+		require.NoError(t, err)
+		fsvc, err := fsCache.GetByFunction(&fn.ObjectMeta)
+		require.NoError(t, err)
+		fsvc.Atime = atime
+		var unloaded []string
+		s := NewUnloadStrategy(logr.Discard(), fv1.ExecutorTypeWasm, fissionClient, fsCache, 2*time.Minute, 5*time.Second,
+			func(_ context.Context, fsvc *fscache.FuncSvc) error {
+				unloaded = append(unloaded, fsvc.Function.Name)
+				return nil
+			})
+		require.NoError(t, s.Reap(t.Context(), fsvc))
+		return fsCache, &unloaded
+	}
+
+	t.Run("unloads an idle function and evicts it", func(t *testing.T) {
+		fsCache, unloaded := setup(t, fissionfake.NewClientset(fn.DeepCopy()), time.Now().Add(-time.Hour))
+		assert.Equal(t, []string{"fn"}, *unloaded)
+		_, err := fsCache.GetByFunction(&fn.ObjectMeta)
+		assert.True(t, fscache.IsNotFoundError(err), "the next request loads the function again")
+	})
+
+	t.Run("keeps a function used within its idle timeout", func(t *testing.T) {
+		fsCache, unloaded := setup(t, fissionfake.NewClientset(fn.DeepCopy()), time.Now())
+		assert.Empty(t, *unloaded)
+		_, err := fsCache.GetByFunction(&fn.ObjectMeta)
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing function is not an error", func(t *testing.T) {
+		_, unloaded := setup(t, fissionfake.NewClientset(), time.Now().Add(-time.Hour))
+		assert.Empty(t, *unloaded, "a deleted function is unloaded by the executor's delete handling")
+	})
+}
+
 // TestPoolDeleteStrategy_DrainThenDelete: with drainBeforeDelete on, reaping
 // first removes the served label (the pod leaves its function Service's
 // EndpointSlices) and defers the actual delete past a drain grace.
@@ -305,7 +361,7 @@ func TestPoolDeleteStrategy_DrainThenDelete(t *testing.T) {
 	}
 	kc := k8sfake.NewSimpleClientset(pod)
 	fc := fscache.MakeFunctionServiceCache(logr.Discard())
-	s := NewPoolDeleteStrategy(logr.Discard(), nil, fc, kc, 2*time.Minute, 5*time.Second, true, nil)
+	s := NewPoolDeleteStrategy(logr.Discard(), nil, fc, kc, 2*time.Minute, 5*time.Second, true, nil, nil)
 	s.fnByUID = map[types.UID]fv1.Function{}
 
 	f := &fscache.FuncSvc{
@@ -327,3 +383,59 @@ func TestPoolDeleteStrategy_DrainThenDelete(t *testing.T) {
 	assert.False(t, served, "the served label must be removed so the pod leaves the slices")
 	assert.Equal(t, "false", got.Labels["managed"], "other labels are untouched")
 }
+
+// confirmingDrainer records drain requests and confirms each at once.
+type confirmingDrainer struct {
+	mu   sync.Mutex
+	reqs []drain.Request
+}
+
+func (d *confirmingDrainer) Drain(_ context.Context, req drain.Request, bound time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reqs = append(d.reqs, req)
+	return bound > 0
+}
+
+// TestPoolDeleteStrategy_DrainConfirmed: with a drainer, the pod is deleted
+// as soon as the routers confirm it drained, with the short drained grace
+// rather than its own termination grace.
+func TestPoolDeleteStrategy_DrainConfirmed(t *testing.T) {
+	t.Parallel()
+	pod := &apiv1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "fn-pod",
+		Namespace: "default",
+		UID:       "pod-uid",
+		Labels:    map[string]string{fv1.SERVED_LABEL: "true"},
+	}}
+	kc := k8sfake.NewSimpleClientset(pod)
+	deleted := make(chan *int64, 1)
+	kc.PrependReactor("delete", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		deleted <- action.(k8stesting.DeleteActionImpl).DeleteOptions.GracePeriodSeconds
+		return false, nil, nil
+	})
+	drainer := &confirmingDrainer{}
+	s := NewPoolDeleteStrategy(logr.Discard(), nil, fscache.MakeFunctionServiceCache(logr.Discard()), kc, 2*time.Minute, 5*time.Second, true, drainer, nil)
+	s.fnByUID = map[types.UID]fv1.Function{}
+
+	s.drainThenDelete(t.Context(), &fscache.FuncSvc{
+		Name:        "fn-pod",
+		Executor:    fv1.ExecutorTypePoolmgr,
+		Function:    &metav1.ObjectMeta{Name: "fn", Namespace: "default", UID: "u1", Labels: map[string]string{fv1.FUNCTION_VERSION: "v2"}},
+		Environment: &fv1.Environment{},
+		KubernetesObjects: []apiv1.ObjectReference{
+			{Kind: "pod", Name: "fn-pod", Namespace: "default", UID: "pod-uid"},
+		},
+	})
+
+	select {
+	case grace := <-deleted:
+		require.NotNil(t, grace)
+		assert.EqualValues(t, reaper.DrainedPodGracePeriod, *grace)
+	case <-time.After(10 * time.Second):
+		t.Fatal("a confirmed drain must delete the pod without waiting out the grace")
+	}
+	drainer.mu.Lock()
+	defer drainer.mu.Unlock()
+	assert.Equal(t, []drain.Request{{Namespace: "default", Function: "fn", FunctionUID: "u1", Version: "v2", PodUID: "pod-uid"}}, drainer.reqs)
+}
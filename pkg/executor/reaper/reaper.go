@@ -79,6 +79,23 @@ func DeleteKubeObject(ctx context.Context, kubeClient kubernetes.Interface, kube
 	return nil
 }
 
+// DrainedPodGracePeriod is the termination grace, in seconds, of a function
+// pod every router has confirmed drained: the preStop sleep only exists to let
+// uncounted in-flight requests finish, so a drained pod needs just enough time
+// for its runtime to exit.
+const DrainedPodGracePeriod = 5
+
+// DeleteDrainedPod deletes a function pod the routers have drained, cutting
+// its termination grace short to DrainedPodGracePeriod.
+func DeleteDrainedPod(ctx context.Context, kubeClient kubernetes.Interface, namespace, name string) error {
+	grace := int64(DrainedPodGracePeriod)
+	err := kubeClient.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{GracePeriodSeconds: &grace})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 // CleanupDeployments deletes deployment(s) for a given instanceID
 func CleanupDeployments(ctx context.Context, logger logr.Logger, client kubernetes.Interface, instanceID string, listOps metav1.ListOptions) error {
 	cleanupDeployments := func(namespace string) error {
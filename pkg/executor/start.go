@@ -8,8 +8,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -38,15 +40,21 @@ import (
 	"github.com/fission/fission/pkg/executor/envreconciler"
 	"github.com/fission/fission/pkg/executor/executortype"
 	"github.com/fission/fission/pkg/executor/executortype/container"
+	"github.com/fission/fission/pkg/executor/executortype/job"
 	"github.com/fission/fission/pkg/executor/executortype/newdeploy"
 	"github.com/fission/fission/pkg/executor/executortype/poolmgr"
+	"github.com/fission/fission/pkg/executor/executortype/wasm"
 	"github.com/fission/fission/pkg/executor/funcreconciler"
 	"github.com/fission/fission/pkg/executor/reaper/idle"
+	"github.com/fission/fission/pkg/executor/usage"
 	"github.com/fission/fission/pkg/executor/util"
 	"github.com/fission/fission/pkg/executor/versiongc"
 	"github.com/fission/fission/pkg/executor/versionretain"
 	fetcherConfig "github.com/fission/fission/pkg/fetcher/config"
 	"github.com/fission/fission/pkg/generated/clientset/versioned/scheme"
+	"github.com/fission/fission/pkg/statestore"
+	_ "github.com/fission/fission/pkg/statestore/client"   // embedded-mode driver
+	_ "github.com/fission/fission/pkg/statestore/postgres" // external-mode driver
 	"github.com/fission/fission/pkg/svcinfo"
 	"github.com/fission/fission/pkg/tenant"
 	"github.com/fission/fission/pkg/utils"
@@ -185,7 +193,9 @@ func (c *executorControllers) Start(ctx context.Context) error {
 	// only, inheriting this leader-elected runnable's context.
 	strategies := make([]idle.Strategy, 0, len(c.executorTypes))
 	for _, et := range c.executorTypes {
-		strategies = append(strategies, et.IdleStrategy())
+		if s := et.IdleStrategy(); s != nil {
+			strategies = append(strategies, s)
+		}
 	}
 	idleReaper := idle.NewReaper(c.logger, strategies...)
 	gm.Go(func() error { idleReaper.Start(ctx); return nil })
@@ -354,10 +364,32 @@ func StartExecutor(ctx context.Context, clientGen crd.ClientGeneratorInterface,
 		return fmt.Errorf("container manager creation failed: %w", err)
 	}
 
+	// wasm runs WASI modules in one shared host pod per function namespace;
+	// it reads its host Deployments directly rather than through the cache.
+	wsm, err := wasm.MakeWasm(
+		ctx, logger,
+		fissionClient, kubernetesClient,
+		fetcherConfig, executorInstanceID)
+	if err != nil {
+		return fmt.Errorf("wasm manager creation failed: %w", err)
+	}
+
+	// job runs every invocation of a function as its own Kubernetes Job,
+	// started and polled by the router through the executor API.
+	jbm, err := job.MakeJob(
+		ctx, logger,
+		fissionClient, kubernetesClient,
+		fetcherConfig, executorInstanceID)
+	if err != nil {
+		return fmt.Errorf("job manager creation failed: %w", err)
+	}
+
 	executorTypes := make(map[fv1.ExecutorType]executortype.ExecutorType)
 	executorTypes[gpm.GetTypeName(ctx)] = gpm
 	executorTypes[ndm.GetTypeName(ctx)] = ndm
 	executorTypes[cnm.GetTypeName(ctx)] = cnm
+	executorTypes[wsm.GetTypeName(ctx)] = wsm
+	executorTypes[jbm.GetTypeName(ctx)] = jbm
 
 	adoptExistingResources, _ := strconv.ParseBool(os.Getenv("ADOPT_EXISTING_RESOURCES"))
 
@@ -491,6 +523,37 @@ func StartExecutor(ctx context.Context, clientGen crd.ClientGeneratorInterface,
 
 	utils.CreateMissingPermissionForSA(ctx, kubernetesClient, logger)
 
+	// Usage accounting (EXECUTOR_USAGE_ACCOUNTING_ENABLED) charges function
+	// pods' requested CPU and memory time to their function, and warm pool
+	// pods' to their environment. Sampling runs on the leader only; the usage
+	// API on every replica reads the hourly rollups from the statestore when
+	// one is configured (the chart sets STATESTORE_* when statestore is on),
+	// otherwise only the leader holds them, in memory.
+	usageCfg, usageEnabled, err := usage.ConfigFromEnv()
+	if err != nil {
+		logger.Error(err, "usage accounting disabled")
+	}
+	if usageEnabled {
+		var kv statestore.KVStore
+		if ssCfg := statestore.FromEnv(); ssCfg.Driver != "" {
+			opened, err := statestore.Open(ctx, ssCfg)
+			if err != nil {
+				return fmt.Errorf("usage accounting: opening statestore: %w", err)
+			}
+			kv, err = statestore.NewScoped(opened, nil).KV()
+			if err != nil {
+				return fmt.Errorf("usage accounting: statestore kv capability: %w", err)
+			}
+		}
+		resolver := utils.DefaultNSResolver()
+		api.accountant = usage.NewAccountant(logger, kubernetesClient, kv, usageCfg,
+			resolver.FunctionNamespaces,
+			func() []string { return slices.Collect(maps.Keys(resolver.FissionResourceNamespaces())) })
+		if err := crMgr.Add(api.accountant); err != nil {
+			return fmt.Errorf("unable to add usage accounting: %w", err)
+		}
+	}
+
 	controllers := &executorControllers{
 		logger:         logger,
 		api:            api,
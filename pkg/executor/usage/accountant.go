@@ -0,0 +1,378 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package usage
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/fission/fission/pkg/executor/metrics"
+	"github.com/fission/fission/pkg/statestore"
+)
+
+// storeConcurrency bounds the statestore reads of one query.
+const storeConcurrency = 16
+
+type (
+	// Accountant samples function pods and keeps their usage rollups. It is
+	// a leader-only Manager runnable; every replica serves Query, reading the
+	// statestore when it is not the one sampling.
+	Accountant struct {
+		logger     logr.Logger
+		kubeClient kubernetes.Interface
+		cfg        Config
+		// kv persists the hourly buckets; nil without a statestore.
+		kv statestore.KVStore
+		// podNamespaces lists the namespaces function pods run in, and
+		// namespaces the Fission namespaces usage is charged to.
+		podNamespaces func() []string
+		namespaces    func() []string
+		now           func() time.Time
+
+		mu         sync.Mutex
+		buckets    map[bucketID]*bucket
+		lastSample time.Time
+		running    atomic.Bool
+	}
+
+	// bucketID names one hour of one namespace's usage of a kind; the unit
+	// persisted as one statestore value.
+	bucketID struct {
+		kind      Kind
+		namespace string
+		hour      int64
+	}
+
+	rowID struct {
+		name, version, executorType string
+	}
+
+	bucket struct {
+		rows map[rowID]*Usage
+		// dirty marks changes not yet written to the statestore.
+		dirty bool
+	}
+)
+
+// NewAccountant returns an Accountant sampling the pods in podNamespaces. kv
+// may be nil, in which case the rollups live only in the leader's memory.
+func NewAccountant(logger logr.Logger, kubeClient kubernetes.Interface, kv statestore.KVStore, cfg Config, podNamespaces, namespaces func() []string) *Accountant {
+	return &Accountant{
+		logger:        logger.WithName("usage"),
+		kubeClient:    kubeClient,
+		cfg:           cfg,
+		kv:            kv,
+		podNamespaces: podNamespaces,
+		namespaces:    namespaces,
+		now:           time.Now,
+		buckets:       make(map[bucketID]*bucket),
+	}
+}
+
+func (a *Accountant) NeedLeaderElection() bool { return true }
+
+// Start samples every cfg.Interval and flushes changed buckets every
+// flushInterval until ctx ends, then flushes once more so a leadership
+// handover loses no more than the last partial interval.
+func (a *Accountant) Start(ctx context.Context) error {
+	a.logger.Info("starting usage accounting", "interval", a.cfg.Interval, "persistent", a.kv != nil)
+	a.running.Store(true)
+	defer a.running.Store(false)
+
+	// The first sample only sets the baseline: what ran before it was
+	// charged by the previous leader, if any.
+	a.sample(ctx)
+	sampleTicker := time.NewTicker(a.cfg.Interval)
+	defer sampleTicker.Stop()
+	flushTicker := time.NewTicker(flushInterval)
+	defer flushTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+			a.flush(flushCtx)
+			cancel()
+			a.reset()
+			return nil
+		case <-sampleTicker.C:
+			a.sample(ctx)
+		case <-flushTicker.C:
+			a.flush(ctx)
+		}
+	}
+}
+
+// reset drops the in-memory state when sampling stops, so a replica that
+// regains leadership later reloads its buckets from the statestore instead
+// of overwriting them with stale totals.
+func (a *Accountant) reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastSample = time.Time{}
+	clear(a.buckets)
+}
+
+// sample lists the executor pods and charges each the time since the
+// previous sample.
+func (a *Accountant) sample(ctx context.Context) {
+	now := a.now()
+	a.mu.Lock()
+	since := a.lastSample
+	a.lastSample = now
+	a.mu.Unlock()
+	if since.IsZero() {
+		return
+	}
+	var obs []observation
+	for _, ns := range a.podNamespaces() {
+		pods, err := listExecutorPods(ctx, a.kubeClient, ns)
+		if err != nil {
+			a.logger.Error(err, "listing function pods for usage accounting", "namespace", ns)
+			continue
+		}
+		for i := range pods {
+			if o, ok := observe(&pods[i], since, now); ok {
+				obs = append(obs, o)
+			}
+		}
+	}
+	a.record(ctx, obs)
+}
+
+// record exports the observations as metrics and adds them to their hourly
+// buckets, loading a bucket from the statestore the first time it is touched
+// so a new leader continues the previous one's totals.
+func (a *Accountant) record(ctx context.Context, obs []observation) {
+	type charge struct {
+		id  bucketID
+		row rowID
+		u   Usage
+	}
+	var charges []charge
+	for _, o := range obs {
+		total := o.usage(o.from, o.to)
+		switch o.kind {
+		case KindFunction:
+			metrics.RecordFunctionUsage(ctx, o.name, o.namespace, o.version, o.executorType,
+				total.PodSeconds, total.CPUCoreSeconds, total.MemoryByteSeconds)
+		case KindEnvironment:
+			metrics.RecordEnvironmentPoolUsage(ctx, o.name, o.namespace,
+				total.PodSeconds, total.CPUCoreSeconds, total.MemoryByteSeconds)
+		}
+		// Split the interval at hour boundaries so each bucket is charged
+		// exactly its share.
+		for start := o.from; start.Before(o.to); {
+			hour := hourOf(start)
+			end := hour.Add(bucketWidth)
+			if end.After(o.to) {
+				end = o.to
+			}
+			charges = append(charges, charge{
+				id:  bucketID{kind: o.kind, namespace: o.namespace, hour: hour.Unix()},
+				row: rowID{name: o.name, version: o.version, executorType: o.executorType},
+				u:   o.usage(start, end),
+			})
+			start = end
+		}
+	}
+
+	missing := map[bucketID]struct{}{}
+	a.mu.Lock()
+	for _, c := range charges {
+		if _, ok := a.buckets[c.id]; !ok {
+			missing[c.id] = struct{}{}
+		}
+	}
+	a.mu.Unlock()
+	loaded := make(map[bucketID]*bucket, len(missing))
+	for id := range missing {
+		loaded[id] = a.load(ctx, id)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, c := range charges {
+		b, ok := a.buckets[c.id]
+		if !ok {
+			b = loaded[c.id]
+			a.buckets[c.id] = b
+		}
+		u, ok := b.rows[c.row]
+		if !ok {
+			u = &Usage{}
+			b.rows[c.row] = u
+		}
+		u.add(c.u)
+		b.dirty = true
+	}
+}
+
+// load reads a bucket from the statestore, or starts an empty one.
+func (a *Accountant) load(ctx context.Context, id bucketID) *bucket {
+	b := &bucket{rows: make(map[rowID]*Usage)}
+	if a.kv == nil {
+		return b
+	}
+	rows, err := a.get(ctx, id)
+	if err != nil {
+		// Starting empty overwrites the stored hour at the next flush; better
+		// than charging nothing for the rest of it.
+		a.logger.Error(err, "loading usage bucket", "namespace", id.namespace, "kind", id.kind, "hour", time.Unix(id.hour, 0).UTC())
+		return b
+	}
+	for _, r := range rows {
+		b.rows[rowID{name: r.Name, version: r.Version, executorType: r.ExecutorType}] = new(r.Usage)
+	}
+	return b
+}
+
+// flush writes the changed buckets to the statestore and drops the buckets
+// that fell out of memoryWindow.
+func (a *Accountant) flush(ctx context.Context) {
+	horizon := a.now().Add(-memoryWindow).Unix()
+	pending := map[bucketID][]Row{}
+	a.mu.Lock()
+	for id, b := range a.buckets {
+		if b.dirty && a.kv != nil {
+			pending[id] = b.snapshot(id.namespace)
+			b.dirty = false
+		}
+		// A bucket being written stays until the next flush, so a failed
+		// write is retried.
+		if _, flushing := pending[id]; id.hour < horizon && !flushing && (!b.dirty || a.kv == nil) {
+			delete(a.buckets, id)
+		}
+	}
+	a.mu.Unlock()
+
+	for id, rows := range pending {
+		if err := a.put(ctx, id, rows); err != nil {
+			a.logger.Error(err, "persisting usage bucket", "namespace", id.namespace, "kind", id.kind, "hour", time.Unix(id.hour, 0).UTC())
+			a.mu.Lock()
+			if b, ok := a.buckets[id]; ok {
+				b.dirty = true
+			}
+			a.mu.Unlock()
+		}
+	}
+}
+
+func (b *bucket) snapshot(namespace string) []Row {
+	rows := make([]Row, 0, len(b.rows))
+	for id, u := range b.rows {
+		rows = append(rows, Row{Namespace: namespace, Name: id.name, Version: id.version, ExecutorType: id.executorType, Usage: *u})
+	}
+	return rows
+}
+
+// Query sums the usage rows selected by q. Hours this replica holds in
+// memory are answered from there; the rest come from the statestore.
+func (a *Accountant) Query(ctx context.Context, q Query) (Response, error) {
+	running := a.running.Load()
+	if !running && a.kv == nil {
+		return Response{}, ErrUnavailable
+	}
+	since, until := hourOf(q.Since), hourOf(q.Until)
+	if until.Before(q.Until) {
+		until = until.Add(bucketWidth)
+	}
+	if floor := hourOf(a.now().Add(-a.cfg.Retention)); since.Before(floor) {
+		since = floor
+	}
+
+	namespaces := map[string]struct{}{}
+	if q.Namespace != "" {
+		namespaces[q.Namespace] = struct{}{}
+	} else {
+		for _, ns := range a.namespaces() {
+			namespaces[ns] = struct{}{}
+		}
+	}
+
+	found := map[bucketID][]Row{}
+	var fetch []bucketID
+	a.mu.Lock()
+	if q.Namespace == "" {
+		for id := range a.buckets {
+			if id.kind == q.Kind {
+				namespaces[id.namespace] = struct{}{}
+			}
+		}
+	}
+	for ns := range namespaces {
+		for hour := since; hour.Before(until); hour = hour.Add(bucketWidth) {
+			id := bucketID{kind: q.Kind, namespace: ns, hour: hour.Unix()}
+			if b, ok := a.buckets[id]; ok {
+				found[id] = b.snapshot(ns)
+			} else if a.kv != nil {
+				fetch = append(fetch, id)
+			}
+		}
+	}
+	a.mu.Unlock()
+
+	if len(fetch) > 0 {
+		var mu sync.Mutex
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(storeConcurrency)
+		for _, id := range fetch {
+			g.Go(func() error {
+				rows, err := a.get(gctx, id)
+				if err != nil {
+					return err
+				}
+				mu.Lock()
+				found[id] = rows
+				mu.Unlock()
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return Response{}, err
+		}
+	}
+
+	sums := map[Row]*Usage{}
+	for _, rows := range found {
+		for _, r := range rows {
+			if q.Name != "" && r.Name != q.Name {
+				continue
+			}
+			key := Row{Namespace: r.Namespace, Name: r.Name, Version: r.Version, ExecutorType: r.ExecutorType}
+			u, ok := sums[key]
+			if !ok {
+				u = &Usage{}
+				sums[key] = u
+			}
+			u.add(r.Usage)
+		}
+	}
+	out := make([]Row, 0, len(sums))
+	for key, u := range sums {
+		key.Usage = *u
+		out = append(out, key)
+	}
+	slices.SortFunc(out, func(x, y Row) int {
+		return cmp.Or(
+			cmp.Compare(x.Namespace, y.Namespace),
+			cmp.Compare(x.Name, y.Name),
+			cmp.Compare(x.Version, y.Version),
+			cmp.Compare(x.ExecutorType, y.ExecutorType),
+		)
+	})
+	return Response{Since: since, Until: until, Rows: out}, nil
+}
+
+func hourOf(t time.Time) time.Time {
+	return t.UTC().Truncate(bucketWidth)
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package usage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// observation is one pod's scheduled time over [from, to) and its requests.
+type observation struct {
+	kind                                   Kind
+	namespace, name, version, executorType string
+	from, to                               time.Time
+	cpuCores, memoryBytes                  float64
+}
+
+func (o observation) usage(from, to time.Time) Usage {
+	secs := to.Sub(from).Seconds()
+	return Usage{PodSeconds: secs, CPUCoreSeconds: o.cpuCores * secs, MemoryByteSeconds: o.memoryBytes * secs}
+}
+
+// accountedSelector matches the pods of the executor types that keep pods
+// running between invocations. wasm hosts are shared by every function in a
+// namespace and job pods are charged to nobody yet.
+var accountedSelector = func() labels.Selector {
+	req, err := labels.NewRequirement(fv1.EXECUTOR_TYPE, selection.In, []string{
+		string(fv1.ExecutorTypePoolmgr), string(fv1.ExecutorTypeNewdeploy), string(fv1.ExecutorTypeContainer),
+	})
+	if err != nil {
+		// Inputs are compile-time constants, so this cannot fail in practice.
+		panic(fmt.Sprintf("building usage label selector: %v", err))
+	}
+	return labels.NewSelector().Add(*req)
+}().String()
+
+// listExecutorPods lists the accounted pods in namespace. ResourceVersion "0"
+// lets the API server answer from its watch cache: a sample does not need a
+// quorum read.
+func listExecutorPods(ctx context.Context, kubeClient kubernetes.Interface, namespace string) ([]apiv1.Pod, error) {
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector:   accountedSelector,
+		ResourceVersion: "0",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// observe charges pod the part of [since, now) it was scheduled. A pool pod
+// still waiting to be specialized is its environment's overhead; any other
+// pod is charged to the function (version) it serves. Pods not yet bound to a
+// node hold no resources and finished pods no longer do.
+func observe(pod *apiv1.Pod, since, now time.Time) (observation, bool) {
+	if pod.Spec.NodeName == "" || pod.Status.Phase == apiv1.PodSucceeded || pod.Status.Phase == apiv1.PodFailed {
+		return observation{}, false
+	}
+	l := pod.Labels
+	o := observation{executorType: l[fv1.EXECUTOR_TYPE], from: since, to: now}
+	if o.executorType == string(fv1.ExecutorTypePoolmgr) && l[fv1.MANAGED] == "true" {
+		o.kind = KindEnvironment
+		o.namespace, o.name = l[fv1.ENVIRONMENT_NAMESPACE], l[fv1.ENVIRONMENT_NAME]
+	} else {
+		o.kind = KindFunction
+		o.namespace, o.name, o.version = l[fv1.FUNCTION_NAMESPACE], l[fv1.FUNCTION_NAME], l[fv1.FUNCTION_VERSION]
+	}
+	if o.name == "" {
+		return observation{}, false
+	}
+	if o.namespace == "" {
+		o.namespace = pod.Namespace
+	}
+
+	start := pod.CreationTimestamp.Time
+	if pod.Status.StartTime != nil {
+		start = pod.Status.StartTime.Time
+	}
+	if start.After(o.from) {
+		o.from = start
+	}
+	if !o.from.Before(o.to) {
+		return observation{}, false
+	}
+	o.cpuCores, o.memoryBytes = podRequests(pod)
+	return o, true
+}
+
+// podRequests sums the CPU (cores) and memory (bytes) the scheduler reserved
+// for pod: its containers' requests plus the runtime class overhead. Init
+// containers are done by the time a pod is charged.
+func podRequests(pod *apiv1.Pod) (cpuCores, memoryBytes float64) {
+	add := func(rl apiv1.ResourceList) {
+		if q, ok := rl[apiv1.ResourceCPU]; ok {
+			cpuCores += float64(q.MilliValue()) / 1000
+		}
+		if q, ok := rl[apiv1.ResourceMemory]; ok {
+			memoryBytes += float64(q.Value())
+		}
+	}
+	for _, c := range pod.Spec.Containers {
+		add(c.Resources.Requests)
+	}
+	add(pod.Spec.Overhead)
+	return cpuCores, memoryBytes
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fission/fission/pkg/statestore"
+)
+
+// Each hourly bucket is one statestore value in the scope of the namespace it
+// charges, keyed "<kind>/<hour>" with the hour in UTC, holding that hour's
+// rows as a JSON array. A query is then one read per namespace and hour.
+const (
+	storeOwner    = "component/executor"
+	storeKeyspace = "usage"
+	hourLayout    = "2006-01-02T15"
+)
+
+func storeScope(namespace string) statestore.Scope {
+	return statestore.Scope{Namespace: namespace, Owner: storeOwner, Keyspace: storeKeyspace}
+}
+
+func storeKey(id bucketID) string {
+	return string(id.kind) + "/" + time.Unix(id.hour, 0).UTC().Format(hourLayout)
+}
+
+// get reads a bucket's rows; an hour never written has none.
+func (a *Accountant) get(ctx context.Context, id bucketID) ([]Row, error) {
+	v, err := a.kv.Get(ctx, storeScope(id.namespace), storeKey(id))
+	if errors.Is(err, statestore.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	if err := json.Unmarshal(v.Data, &rows); err != nil {
+		return nil, fmt.Errorf("decoding usage bucket %s: %w", storeKey(id), err)
+	}
+	return rows, nil
+}
+
+// put overwrites a bucket's rows, expiring them after the retention period.
+func (a *Accountant) put(ctx context.Context, id bucketID, rows []Row) error {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	return a.kv.Set(ctx, storeScope(id.namespace), storeKey(id), data, statestore.SetOptions{TTL: a.cfg.Retention})
+}
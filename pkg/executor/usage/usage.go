@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package usage attributes cluster cost to functions. The executor leader
+// samples the pods of the poolmgr, newdeploy and container executor types on
+// an interval and integrates each pod's requested CPU and memory over the time
+// it was scheduled: specialized pods are charged to their function (per
+// version), warm poolmgr pods to their environment as pool overhead. The
+// totals are exported as counters and rolled up into hourly buckets that the
+// executor API serves to `fission fn usage` and `fission env usage`; with a
+// statestore configured the buckets outlive the leader and are kept for the
+// retention period.
+//
+// Sampling is at interval granularity: a pod is charged from the later of its
+// start and the previous sample to the current one, so the last partial
+// interval of a pod deleted between two samples is not charged.
+package usage
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fission/fission/pkg/executor/util"
+)
+
+// Path is the executor API endpoint serving usage queries.
+const Path = "/v2/usage"
+
+const (
+	// DefaultInterval spaces the pod samples.
+	DefaultInterval = 30 * time.Second
+	// DefaultRetention is how long hourly buckets are kept in the statestore.
+	DefaultRetention = 90 * 24 * time.Hour
+	// DefaultQueryRange is the range a query covers when it sets no since.
+	DefaultQueryRange = 24 * time.Hour
+
+	// bucketWidth is the rollup granularity.
+	bucketWidth = time.Hour
+	// memoryWindow bounds the buckets kept in memory. Without a statestore it
+	// is also the furthest back a query can see.
+	memoryWindow = 24 * time.Hour
+	// flushInterval spaces the statestore writes of the buckets changed since
+	// the last flush.
+	flushInterval = 5 * time.Minute
+)
+
+// Kind selects what usage is charged to.
+type Kind string
+
+const (
+	// KindFunction is the usage of specialized function pods, charged to the
+	// function version they serve.
+	KindFunction Kind = "function"
+	// KindEnvironment is the warm-pool overhead of poolmgr environments: the
+	// pods kept generic, waiting to be specialized.
+	KindEnvironment Kind = "environment"
+)
+
+// ErrUnavailable is returned by Query on a replica that is not sampling and
+// has no statestore to read another replica's rollups from.
+var ErrUnavailable = errors.New("usage is only held in memory on the executor leader")
+
+type (
+	// Config configures the accounting.
+	Config struct {
+		// Interval spaces the pod samples.
+		Interval time.Duration
+		// Retention is the statestore TTL of an hourly bucket.
+		Retention time.Duration
+	}
+
+	// Usage is resource time accumulated by pods: the seconds they were
+	// scheduled, and those seconds weighted by their CPU and memory requests.
+	Usage struct {
+		PodSeconds        float64 `json:"podSeconds"`
+		CPUCoreSeconds    float64 `json:"cpuCoreSeconds"`
+		MemoryByteSeconds float64 `json:"memoryByteSeconds"`
+	}
+
+	// Row is the usage of one function version, or of one environment's warm
+	// pool. Version is "" for unversioned functions and for environments.
+	Row struct {
+		Namespace    string `json:"namespace"`
+		Name         string `json:"name"`
+		Version      string `json:"version,omitempty"`
+		ExecutorType string `json:"executorType"`
+		Usage
+	}
+
+	// Query selects the rows summed over [Since, Until), widened to whole
+	// hours. An empty Namespace or Name matches all.
+	Query struct {
+		Kind      Kind
+		Namespace string
+		Name      string
+		Since     time.Time
+		Until     time.Time
+	}
+
+	// Response is the usage API's answer: the hour-aligned range actually
+	// covered and one row per function version or environment.
+	Response struct {
+		Since time.Time `json:"since"`
+		Until time.Time `json:"until"`
+		Rows  []Row     `json:"rows"`
+	}
+)
+
+func (u *Usage) add(o Usage) {
+	u.PodSeconds += o.PodSeconds
+	u.CPUCoreSeconds += o.CPUCoreSeconds
+	u.MemoryByteSeconds += o.MemoryByteSeconds
+}
+
+// ConfigFromEnv builds Config from the EXECUTOR_USAGE_ACCOUNTING_* env vars.
+// It returns (zero, false, nil) when EXECUTOR_USAGE_ACCOUNTING_ENABLED is unset
+// or false, and an error when it is set to something unparseable so the
+// caller can log why the accounting is off.
+func ConfigFromEnv() (Config, bool, error) {
+	v := os.Getenv("EXECUTOR_USAGE_ACCOUNTING_ENABLED")
+	if v == "" {
+		return Config{}, false, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return Config{}, false, fmt.Errorf("EXECUTOR_USAGE_ACCOUNTING_ENABLED=%q: %w", v, err)
+	}
+	if !enabled {
+		return Config{}, false, nil
+	}
+	cfg := Config{
+		Interval:  util.DurOr("EXECUTOR_USAGE_ACCOUNTING_INTERVAL", DefaultInterval),
+		Retention: util.DurOr("EXECUTOR_USAGE_ACCOUNTING_RETENTION", DefaultRetention),
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Retention < bucketWidth {
+		cfg.Retention = DefaultRetention
+	}
+	return cfg, true, nil
+}
+
+// ParseQuery reads a usage API query string: kind (function, the default, or
+// environment), namespace, name, and since/until, each an RFC 3339 timestamp
+// or a Go duration meaning that long ago (since=24h). since defaults to
+// DefaultQueryRange ago and until to now.
+func ParseQuery(qs url.Values, now time.Time) (Query, error) {
+	q := Query{
+		Kind:      Kind(qs.Get("kind")),
+		Namespace: qs.Get("namespace"),
+		Name:      qs.Get("name"),
+		Since:     now.Add(-DefaultQueryRange),
+		Until:     now,
+	}
+	switch q.Kind {
+	case "":
+		q.Kind = KindFunction
+	case KindFunction, KindEnvironment:
+	default:
+		return Query{}, fmt.Errorf("kind must be %s or %s", KindFunction, KindEnvironment)
+	}
+	for _, b := range []struct {
+		param string
+		dst   *time.Time
+	}{{"since", &q.Since}, {"until", &q.Until}} {
+		raw := qs.Get(b.param)
+		if raw == "" {
+			continue
+		}
+		t, err := parseTime(raw, now)
+		if err != nil {
+			return Query{}, fmt.Errorf("%s must be an RFC 3339 timestamp or a duration (e.g. 24h)", b.param)
+		}
+		*b.dst = t
+	}
+	if !q.Since.Before(q.Until) {
+		return Query{}, errors.New("since must be before until")
+	}
+	return q, nil
+}
+
+func parseTime(raw string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return time.Time{}, errors.New("invalid time")
+	}
+	return now.Add(-d), nil
+}
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package usage
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/statestore/memory"
+)
+
+const podNS = "fission-function"
+
+var t0 = time.Date(2026, 10, 19, 10, 59, 50, 0, time.UTC)
+
+func testPod(name string, labels map[string]string, cpu, mem string) *apiv1.Pod {
+	return &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: podNS, Labels: labels, CreationTimestamp: metav1.NewTime(t0.Add(-time.Hour))},
+		Spec: apiv1.PodSpec{
+			NodeName: "node-1",
+			Containers: []apiv1.Container{{
+				Name: "main",
+				Resources: apiv1.ResourceRequirements{Requests: apiv1.ResourceList{
+					apiv1.ResourceCPU:    resource.MustParse(cpu),
+					apiv1.ResourceMemory: resource.MustParse(mem),
+				}},
+			}},
+		},
+		Status: apiv1.PodStatus{Phase: apiv1.PodRunning},
+	}
+}
+
+func warmPod(name, env string) *apiv1.Pod {
+	return testPod(name, map[string]string{
+		fv1.EXECUTOR_TYPE:         string(fv1.ExecutorTypePoolmgr),
+		fv1.MANAGED:               "true",
+		fv1.ENVIRONMENT_NAME:      env,
+		fv1.ENVIRONMENT_NAMESPACE: "default",
+	}, "100m", "128Mi")
+}
+
+func fnPod(name, fn, version string, et fv1.ExecutorType) *apiv1.Pod {
+	l := map[string]string{
+		fv1.EXECUTOR_TYPE:      string(et),
+		fv1.FUNCTION_NAME:      fn,
+		fv1.FUNCTION_NAMESPACE: "default",
+	}
+	if et == fv1.ExecutorTypePoolmgr {
+		l[fv1.MANAGED] = "false"
+	}
+	if version != "" {
+		l[fv1.FUNCTION_VERSION] = version
+	}
+	return testPod(name, l, "500m", "256Mi")
+}
+
+func TestObserve(t *testing.T) {
+	since, now := t0, t0.Add(30*time.Second)
+
+	o, ok := observe(warmPod("warm", "nodejs"), since, now)
+	require.True(t, ok)
+	assert.Equal(t, KindEnvironment, o.kind)
+	assert.Equal(t, "nodejs", o.name)
+	assert.Equal(t, "default", o.namespace)
+
+	o, ok = observe(fnPod("spec", "hello", "v2", fv1.ExecutorTypePoolmgr), since, now)
+	require.True(t, ok)
+	assert.Equal(t, KindFunction, o.kind)
+	assert.Equal(t, "hello", o.name)
+	assert.Equal(t, "v2", o.version)
+	assert.Equal(t, Usage{PodSeconds: 30, CPUCoreSeconds: 15, MemoryByteSeconds: 30 * 256 << 20}, o.usage(o.from, o.to))
+
+	pending := fnPod("pending", "hello", "", fv1.ExecutorTypeNewdeploy)
+	pending.Spec.NodeName = ""
+	_, ok = observe(pending, since, now)
+	assert.False(t, ok, "an unscheduled pod holds nothing")
+
+	done := fnPod("done", "hello", "", fv1.ExecutorTypeContainer)
+	done.Status.Phase = apiv1.PodSucceeded
+	_, ok = observe(done, since, now)
+	assert.False(t, ok, "a finished pod holds nothing")
+
+	late := fnPod("late", "hello", "", fv1.ExecutorTypeNewdeploy)
+	late.Status.StartTime = new(metav1.NewTime(since.Add(20 * time.Second)))
+	late.Spec.Overhead = apiv1.ResourceList{apiv1.ResourceCPU: resource.MustParse("250m")}
+	o, ok = observe(late, since, now)
+	require.True(t, ok)
+	assert.Equal(t, Usage{PodSeconds: 10, CPUCoreSeconds: 7.5, MemoryByteSeconds: 10 * 256 << 20}, o.usage(o.from, o.to),
+		"charged from its start, with the runtime overhead")
+}
+
+func TestAccountantRollupsAcrossReplicas(t *testing.T) {
+	caps, err := memory.New()
+	require.NoError(t, err)
+	kv, err := caps.KV()
+	require.NoError(t, err)
+
+	kubeClient := fake.NewClientset(
+		warmPod("warm-1", "nodejs"),
+		fnPod("hello-1", "hello", "", fv1.ExecutorTypePoolmgr),
+		fnPod("hello-2", "hello", "", fv1.ExecutorTypePoolmgr),
+		fnPod("orders-1", "orders", "v1", fv1.ExecutorTypeNewdeploy),
+	)
+	now := t0
+	newAccountant := func() *Accountant {
+		a := NewAccountant(logr.Discard(), kubeClient, kv, Config{Interval: DefaultInterval, Retention: DefaultRetention},
+			func() []string { return []string{podNS} }, func() []string { return []string{"default"} })
+		a.now = func() time.Time { return now }
+		return a
+	}
+
+	leader := newAccountant()
+	leader.running.Store(true)
+	leader.sample(t.Context()) // baseline
+	now = t0.Add(30 * time.Second)
+	leader.sample(t.Context()) // crosses 11:00: 10s in one hour, 20s in the next
+
+	resp, err := leader.Query(t.Context(), Query{Kind: KindFunction, Since: t0.Add(-time.Hour), Until: now})
+	require.NoError(t, err)
+	assert.Equal(t, []Row{
+		{Namespace: "default", Name: "hello", ExecutorType: "poolmgr", Usage: Usage{PodSeconds: 60, CPUCoreSeconds: 30, MemoryByteSeconds: 60 * 256 << 20}},
+		{Namespace: "default", Name: "orders", Version: "v1", ExecutorType: "newdeploy", Usage: Usage{PodSeconds: 30, CPUCoreSeconds: 15, MemoryByteSeconds: 30 * 256 << 20}},
+	}, resp.Rows)
+	assert.Equal(t, time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC), resp.Since)
+	assert.Equal(t, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), resp.Until)
+
+	resp, err = leader.Query(t.Context(), Query{Kind: KindEnvironment, Namespace: "default", Since: t0.Add(10 * time.Second), Until: now})
+	require.NoError(t, err)
+	assert.Equal(t, []Row{
+		{Namespace: "default", Name: "nodejs", ExecutorType: "poolmgr", Usage: Usage{PodSeconds: 20, CPUCoreSeconds: 2, MemoryByteSeconds: 20 * 128 << 20}},
+	}, resp.Rows, "only the 11:00 hour is queried")
+
+	// A standby reads what the leader flushed.
+	leader.flush(t.Context())
+	standby := newAccountant()
+	resp, err = standby.Query(t.Context(), Query{Kind: KindFunction, Name: "orders", Since: t0.Add(-time.Hour), Until: now})
+	require.NoError(t, err)
+	require.Len(t, resp.Rows, 1)
+	assert.InDelta(t, 30, resp.Rows[0].PodSeconds, 1e-9)
+
+	// A new leader continues the stored hour rather than restarting it.
+	standby.running.Store(true)
+	standby.sample(t.Context())
+	now = now.Add(30 * time.Second)
+	standby.sample(t.Context())
+	resp, err = standby.Query(t.Context(), Query{Kind: KindFunction, Name: "orders", Since: t0, Until: now})
+	require.NoError(t, err)
+	require.Len(t, resp.Rows, 1)
+	assert.InDelta(t, 60, resp.Rows[0].PodSeconds, 1e-9)
+}
+
+func TestQueryWithoutStoreOnStandby(t *testing.T) {
+	a := NewAccountant(logr.Discard(), fake.NewClientset(), nil, Config{Interval: DefaultInterval, Retention: DefaultRetention},
+		func() []string { return nil }, func() []string { return nil })
+	_, err := a.Query(t.Context(), Query{Kind: KindFunction, Since: t0, Until: t0.Add(time.Hour)})
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestParseQuery(t *testing.T) {
+	now := t0
+	q, err := ParseQuery(url.Values{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, Query{Kind: KindFunction, Since: now.Add(-DefaultQueryRange), Until: now}, q)
+
+	q, err = ParseQuery(url.Values{"kind": {"environment"}, "namespace": {"ns"}, "since": {"2h"}, "until": {"2026-10-19T10:30:00Z"}}, now)
+	require.NoError(t, err)
+	assert.Equal(t, Query{Kind: KindEnvironment, Namespace: "ns", Since: now.Add(-2 * time.Hour), Until: time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC)}, q)
+
+	for _, qs := range []url.Values{
+		{"kind": {"package"}},
+		{"since": {"yesterday"}},
+		{"since": {"1h"}, "until": {"2h"}},
+	} {
+		_, err := ParseQuery(qs, now)
+		assert.Error(t, err, "%v", qs)
+	}
+}
@@ -47,6 +47,23 @@ func ScaleDeployment(ctx context.Context, kubeClient kubernetes.Interface, logge
 	return err
 }
 
+// ScaleDeploymentUp raises the named deployment to at least replicas. It
+// never scales down, and leaves a deployment yet to be created to its
+// creator.
+func ScaleDeploymentUp(ctx context.Context, kubeClient kubernetes.Interface, logger logr.Logger, ns, name string, replicas int32) error {
+	depl, err := kubeClient.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if depl.Spec.Replicas != nil && *depl.Spec.Replicas >= replicas {
+		return nil
+	}
+	return ScaleDeployment(ctx, kubeClient, logger, ns, name, replicas)
+}
+
 // waitPollInitialDelay/waitPollMaxDelay shape WaitForDeployment's adaptive
 // poll: scale-from-zero deployments usually become available well under a
 // second, so polling starts fine-grained and backs off ×1.5 to the old 1s
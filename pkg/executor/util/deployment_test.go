@@ -170,6 +170,36 @@ func TestScaleDeployment(t *testing.T) {
 	assert.Equal(t, int32(3), captured.Spec.Replicas)
 }
 
+func TestScaleDeploymentUp(t *testing.T) {
+	t.Parallel()
+
+	const ns, name = "default", "dep1"
+	replicas := int32(2)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	client := fake.NewClientset(dep)
+	var scaled []int32
+	client.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		obj := action.(k8stesting.UpdateAction).GetObject()
+		scaled = append(scaled, obj.(*autoscalingv1.Scale).Spec.Replicas)
+		return true, obj, nil
+	})
+
+	logger := loggerfactory.GetLogger()
+	require.NoError(t, ScaleDeploymentUp(t.Context(), client, logger, ns, name, 1))
+	require.NoError(t, ScaleDeploymentUp(t.Context(), client, logger, ns, name, 2))
+	assert.Empty(t, scaled, "a deployment at or above replicas is left alone")
+	require.NoError(t, ScaleDeploymentUp(t.Context(), client, logger, ns, name, 5))
+	assert.Equal(t, []int32{5}, scaled)
+	require.NoError(t, ScaleDeploymentUp(t.Context(), client, logger, ns, "missing", 5),
+		"a deployment yet to be created is not an error")
+}
+
 func TestWaitForDeployment(t *testing.T) {
 	t.Parallel()
 
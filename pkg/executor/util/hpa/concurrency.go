@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hpa
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	asv2 "k8s.io/api/autoscaling/v2"
+	k8s_err "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/executor/metrics"
+)
+
+const (
+	// concurrencyReportTTL is how long a router's in-flight report counts.
+	// Routers flush taps every 5s, and only for the functions they served
+	// in the interval, so a router silent for longer has nothing in flight.
+	concurrencyReportTTL = 15 * time.Second
+
+	// concurrencyScaleInterval is how often RunConcurrencyScaling revisits
+	// the HPAs of functions with ConcurrencyScaling.
+	concurrencyScaleInterval = 15 * time.Second
+)
+
+type concurrencyReport struct {
+	inflight int
+	at       time.Time
+}
+
+type concurrencyRecommendation struct {
+	at       time.Time
+	replicas int32
+}
+
+// concurrencyTarget is the concurrency scaling state of one HPA.
+type concurrencyTarget struct {
+	uid                 types.UID
+	fnName, fnNamespace string
+	targetPerReplica    int
+	window              time.Duration
+	minReplicas         int32
+	maxReplicas         int32
+
+	// reports holds each router's last in-flight report, by source.
+	reports map[string]concurrencyReport
+	// recommendations of the last window, oldest first.
+	recommendations []concurrencyRecommendation
+	// applied is the MinReplicas last written to the HPA.
+	applied int32
+}
+
+// concurrencyScaler holds the concurrency scaling state of the HPAs of one
+// executor type, by HPA namespace/name. A function's versioned projections
+// share its UID and its routers' reports, each with its own HPA.
+type concurrencyScaler struct {
+	mu      sync.Mutex
+	targets map[types.NamespacedName]*concurrencyTarget
+}
+
+// replicaBounds returns the HPA replica bounds of an ExecutionStrategy.
+func replicaBounds(execStrategy *fv1.ExecutionStrategy) (minRepl, maxRepl int32) {
+	minRepl = int32(execStrategy.MinScale)
+	if minRepl == 0 {
+		minRepl = 1
+	}
+	maxRepl = int32(execStrategy.MaxScale)
+	if maxRepl == 0 {
+		maxRepl = minRepl
+	}
+	return minRepl, maxRepl
+}
+
+// recommendLocked records the replicas the current in-flight requests need
+// at now and returns the in-flight count and the highest recommendation of
+// the stabilization window.
+func (t *concurrencyTarget) recommendLocked(now time.Time) (inflight int, replicas int32) {
+	for source, r := range t.reports {
+		if now.Sub(r.at) > concurrencyReportTTL {
+			delete(t.reports, source)
+			continue
+		}
+		inflight += r.inflight
+	}
+	want := int32((inflight + t.targetPerReplica - 1) / t.targetPerReplica)
+	want = min(max(want, t.minReplicas), t.maxReplicas)
+	t.recommendations = append(t.recommendations, concurrencyRecommendation{at: now, replicas: want})
+
+	from := now.Add(-t.window)
+	i := 0
+	for i < len(t.recommendations)-1 && t.recommendations[i].at.Before(from) {
+		i++
+	}
+	t.recommendations = slices.Delete(t.recommendations, 0, i)
+	for _, r := range t.recommendations {
+		replicas = max(replicas, r.replicas)
+	}
+	return inflight, replicas
+}
+
+// ApplyConcurrencyScaling starts or stops concurrency scaling of hpa as fn's
+// ExecutionStrategy asks, and raises hpa's MinReplicas to what the
+// function's in-flight requests need. It reports whether it changed
+// MinReplicas; the caller writes hpa.
+func (hpaops *HpaOperations) ApplyConcurrencyScaling(fn *fv1.Function, hpa *asv2.HorizontalPodAutoscaler) bool {
+	key := types.NamespacedName{Namespace: hpa.Namespace, Name: hpa.Name}
+	s := hpaops.concurrency
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	execStrategy := &fn.Spec.InvokeStrategy.ExecutionStrategy
+	if execStrategy.ConcurrencyScaling == nil {
+		delete(s.targets, key)
+		return false
+	}
+	cfg := execStrategy.ConcurrencyScaling.Effective()
+	window, err := time.ParseDuration(cfg.StabilizationWindow)
+	if err != nil {
+		hpaops.logger.Error(err, "invalid concurrency stabilization window, using none",
+			"function", fn.Name, "namespace", fn.Namespace)
+	}
+	t, ok := s.targets[key]
+	if !ok || t.uid != fn.UID {
+		t = &concurrencyTarget{reports: make(map[string]concurrencyReport)}
+		s.targets[key] = t
+	}
+	t.uid, t.fnName, t.fnNamespace = fn.UID, fn.Name, fn.Namespace
+	t.targetPerReplica = max(cfg.TargetPerReplica, 1)
+	t.window = window
+	t.minReplicas, t.maxReplicas = replicaBounds(execStrategy)
+
+	_, replicas := t.recommendLocked(time.Now())
+	t.applied = replicas
+	if hpa.Spec.MinReplicas != nil && *hpa.Spec.MinReplicas == replicas {
+		return false
+	}
+	hpa.Spec.MinReplicas = &replicas
+	return true
+}
+
+// ObserveConcurrency records the in-flight requests a router reported for
+// the function in its taps. Reports for functions without
+// ConcurrencyScaling are dropped.
+func (hpaops *HpaOperations) ObserveConcurrency(uid types.UID, source string, inflight int) {
+	s := hpaops.concurrency
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, t := range s.targets {
+		if t.uid == uid {
+			t.reports[source] = concurrencyReport{inflight: inflight, at: now}
+		}
+	}
+}
+
+// RunConcurrencyScaling keeps the MinReplicas of the HPAs of functions with
+// ConcurrencyScaling at what their in-flight requests need, until ctx is
+// done.
+func (hpaops *HpaOperations) RunConcurrencyScaling(ctx context.Context) {
+	ticker := time.NewTicker(concurrencyScaleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			hpaops.scaleConcurrency(ctx, time.Now())
+		}
+	}
+}
+
+func (hpaops *HpaOperations) scaleConcurrency(ctx context.Context, now time.Time) {
+	type update struct {
+		key      types.NamespacedName
+		replicas int32
+	}
+	var updates []update
+	s := hpaops.concurrency
+	s.mu.Lock()
+	for key, t := range s.targets {
+		inflight, replicas := t.recommendLocked(now)
+		metrics.RecordConcurrencyScaling(ctx, t.fnName, t.fnNamespace, int64(inflight), int64(replicas))
+		if replicas != t.applied {
+			updates = append(updates, update{key, replicas})
+		}
+	}
+	s.mu.Unlock()
+
+	for _, u := range updates {
+		hpa, err := hpaops.GetHpa(ctx, u.key.Namespace, u.key.Name)
+		if err != nil {
+			if k8s_err.IsNotFound(err) {
+				s.forget(u.key)
+				continue
+			}
+			hpaops.logger.Error(err, "error getting HPA for concurrency scaling", "hpa", u.key.Name, "ns", u.key.Namespace)
+			continue
+		}
+		previous := hpa.Spec.MinReplicas
+		hpa.Spec.MinReplicas = &u.replicas
+		if err := hpaops.UpdateHpa(ctx, hpa); err != nil {
+			hpaops.logger.Error(err, "error updating HPA for concurrency scaling", "hpa", u.key.Name, "ns", u.key.Namespace)
+			continue
+		}
+		s.mu.Lock()
+		if t, ok := s.targets[u.key]; ok {
+			t.applied = u.replicas
+		}
+		s.mu.Unlock()
+		hpaops.logger.Info("scaled HPA minimum replicas on concurrency", "hpa", u.key.Name, "ns", u.key.Namespace,
+			"from", previous, "to", u.replicas)
+	}
+}
+
+func (s *concurrencyScaler) forget(key types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.targets, key)
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hpa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/utils/loggerfactory"
+)
+
+func TestConcurrencyTargetRecommend(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	ct := &concurrencyTarget{
+		targetPerReplica: 10,
+		window:           time.Minute,
+		minReplicas:      2,
+		maxReplicas:      8,
+		reports:          make(map[string]concurrencyReport),
+	}
+
+	inflight, replicas := ct.recommendLocked(now)
+	assert.Zero(t, inflight)
+	assert.Equal(t, int32(2), replicas, "MinScale bounds an idle function")
+
+	// Two routers' reports add up, and scale up is immediate.
+	ct.reports["10.0.0.1"] = concurrencyReport{inflight: 25, at: now}
+	ct.reports["10.0.0.2"] = concurrencyReport{inflight: 16, at: now}
+	inflight, replicas = ct.recommendLocked(now)
+	assert.Equal(t, 41, inflight)
+	assert.Equal(t, int32(5), replicas)
+
+	// MaxScale caps a burst.
+	ct.reports["10.0.0.2"] = concurrencyReport{inflight: 200, at: now}
+	_, replicas = ct.recommendLocked(now.Add(time.Second))
+	assert.Equal(t, int32(8), replicas)
+
+	// Stale reports drop out, but the window holds the highest
+	// recommendation until it passes.
+	later := now.Add(concurrencyReportTTL + time.Second)
+	inflight, replicas = ct.recommendLocked(later)
+	assert.Zero(t, inflight)
+	assert.Empty(t, ct.reports)
+	assert.Equal(t, int32(8), replicas)
+	_, replicas = ct.recommendLocked(now.Add(time.Minute + 2*time.Second))
+	assert.Equal(t, int32(2), replicas)
+}
+
+func TestConcurrencyScaling(t *testing.T) {
+	logger := loggerfactory.GetLogger()
+	ns := "test-namespace"
+	fn := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "test-fn", Namespace: "default", UID: uuid.NewUUID()}}
+	fn.Spec.InvokeStrategy.ExecutionStrategy = fv1.ExecutionStrategy{
+		ExecutorType:       fv1.ExecutorTypeNewdeploy,
+		MinScale:           1,
+		MaxScale:           10,
+		ConcurrencyScaling: &fv1.ConcurrencyScalingConfig{TargetPerReplica: 5, StabilizationWindow: "0s"},
+	}
+	depl := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "test-deployment", Namespace: ns}}
+
+	client := fake.NewClientset()
+	hpaops := NewHpaOperations(logger, client, "instance")
+	hpa, err := hpaops.CreateOrGetHpa(t.Context(), fn, "test-hpa", &fn.Spec.InvokeStrategy.ExecutionStrategy,
+		"fn-container", depl, nil, nil)
+	require.NoError(t, err)
+	require.NotNil(t, hpa.Spec.MinReplicas)
+	assert.Equal(t, int32(1), *hpa.Spec.MinReplicas)
+
+	// Reports for functions without ConcurrencyScaling are dropped.
+	hpaops.ObserveConcurrency("other-uid", "10.0.0.1", 50)
+	hpaops.ObserveConcurrency(fn.UID, "10.0.0.1", 23)
+
+	get := func() int32 {
+		t.Helper()
+		got, err := hpaops.GetHpa(t.Context(), ns, "test-hpa")
+		require.NoError(t, err)
+		return *got.Spec.MinReplicas
+	}
+	hpaops.scaleConcurrency(t.Context(), time.Now())
+	assert.Equal(t, int32(5), get())
+
+	// An update applies the current recommendation to the new HPA spec.
+	hpa, err = hpaops.GetHpa(t.Context(), ns, "test-hpa")
+	require.NoError(t, err)
+	hpa.Spec.MinReplicas = new(int32(1))
+	assert.True(t, hpaops.ApplyConcurrencyScaling(fn, hpa))
+	assert.Equal(t, int32(5), *hpa.Spec.MinReplicas)
+	assert.False(t, hpaops.ApplyConcurrencyScaling(fn, hpa))
+
+	// Once the reports expire the HPA falls back to MinScale.
+	hpaops.scaleConcurrency(t.Context(), time.Now().Add(concurrencyReportTTL+time.Second))
+	assert.Equal(t, int32(1), get())
+
+	// Turning concurrency scaling off stops tracking the HPA.
+	fn.Spec.InvokeStrategy.ExecutionStrategy.ConcurrencyScaling = nil
+	assert.False(t, hpaops.ApplyConcurrencyScaling(fn, hpa))
+	assert.NotContains(t, hpaops.concurrency.targets, types.NamespacedName{Namespace: ns, Name: "test-hpa"})
+
+	// A deleted HPA is forgotten.
+	fn.Spec.InvokeStrategy.ExecutionStrategy.ConcurrencyScaling = &fv1.ConcurrencyScalingConfig{TargetPerReplica: 5}
+	hpaops.ApplyConcurrencyScaling(fn, hpa)
+	require.NoError(t, client.AutoscalingV2().HorizontalPodAutoscalers(ns).Delete(t.Context(), "test-hpa", metav1.DeleteOptions{}))
+	hpaops.ObserveConcurrency(fn.UID, "10.0.0.1", 40)
+	hpaops.scaleConcurrency(t.Context(), time.Now())
+	assert.Empty(t, hpaops.concurrency.targets)
+}
@@ -14,6 +14,7 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	k8s_err "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/go-logr/logr"
@@ -34,6 +35,7 @@ type HpaOperations struct {
 	kubernetesClient      kubernetes.Interface
 	instanceID            string
 	enableOwnerReferences bool
+	concurrency           *concurrencyScaler
 }
 
 func NewHpaOperations(logger logr.Logger, kubernetesClient kubernetes.Interface, instanceID string) *HpaOperations {
@@ -42,6 +44,7 @@ func NewHpaOperations(logger logr.Logger, kubernetesClient kubernetes.Interface,
 		kubernetesClient:      kubernetesClient,
 		instanceID:            instanceID,
 		enableOwnerReferences: utils.IsOwnerReferencesEnabled(),
+		concurrency:           &concurrencyScaler{targets: make(map[types.NamespacedName]*concurrencyTarget)},
 	}
 }
 
@@ -142,14 +145,7 @@ func (hpaops *HpaOperations) CreateOrGetHpa(ctx context.Context, fn *fv1.Functio
 	}
 	logger := otelUtils.LoggerWithTraceID(ctx, hpaops.logger)
 
-	minRepl := int32(execStrategy.MinScale)
-	if minRepl == 0 {
-		minRepl = 1
-	}
-	maxRepl := int32(execStrategy.MaxScale)
-	if maxRepl == 0 {
-		maxRepl = minRepl
-	}
+	minRepl, maxRepl := replicaBounds(execStrategy)
 	targetCPU := int32(execStrategy.TargetCPUPercent) // nolint: staticcheck
 	var hpaMetrics []asv2.MetricSpec
 	if targetCPU > 0 && targetCPU < 100 {
@@ -172,6 +168,7 @@ func (hpaops *HpaOperations) CreateOrGetHpa(ctx context.Context, fn *fv1.Functio
 	hpa := &asv2.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            hpaName,
+			Namespace:       depl.Namespace,
 			Labels:          deployLabels,
 			Annotations:     deployAnnotations,
 			OwnerReferences: ownerReferences,
@@ -184,6 +181,7 @@ func (hpaops *HpaOperations) CreateOrGetHpa(ctx context.Context, fn *fv1.Functio
 			Behavior:       execStrategy.Behavior,
 		},
 	}
+	hpaops.ApplyConcurrencyScaling(fn, hpa)
 
 	existingHpa, err := hpaops.GetHpa(ctx, depl.Namespace, hpaName)
 	if err == nil {
@@ -255,5 +253,6 @@ func (hpaops *HpaOperations) UpdateHpa(ctx context.Context, hpa *asv2.Horizontal
 }
 
 func (hpaops *HpaOperations) DeleteHpa(ctx context.Context, ns string, name string) error {
+	hpaops.concurrency.forget(types.NamespacedName{Namespace: ns, Name: name})
 	return hpaops.kubernetesClient.AutoscalingV2().HorizontalPodAutoscalers(ns).Delete(ctx, name, metav1.DeleteOptions{})
 }
@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -42,8 +44,19 @@ type Config struct {
 	// fetcher container verbatim; empty (the default) means every registry
 	// must serve TLS.
 	insecureRegistries string
+
+	// packageCacheHostPath is the node directory poolmgr fetchers share as
+	// their package cache; empty (the default) disables the cache.
+	// packageCacheMaxBytes bounds its size, 0 meaning no eviction.
+	packageCacheHostPath string
+	packageCacheMaxBytes int64
 }
 
+const (
+	packageCacheVolume    = "fetcher-package-cache"
+	packageCacheMountPath = "/package-cache"
+)
+
 // internalAuthEnvVars returns the env-var entries that mount the
 // HMAC-shared-secret values from the internal-auth master Secret
 // onto the fetcher sidecar container. Every key is marked optional so a
@@ -151,6 +164,15 @@ func MakeFetcherConfig(sharedMountPath string) (*Config, error) {
 		fetcherImagePullPolicy = "IfNotPresent"
 	}
 
+	var packageCacheMaxBytes int64
+	if val := os.Getenv("FETCHER_PACKAGE_CACHE_MAX_SIZE"); len(val) > 0 {
+		quantity, err := resource.ParseQuantity(val)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing FETCHER_PACKAGE_CACHE_MAX_SIZE: %w", err)
+		}
+		packageCacheMaxBytes = quantity.Value()
+	}
+
 	return &Config{
 		resourceRequirements:   resources,
 		fetcherImage:           fetcherImage,
@@ -160,6 +182,8 @@ func MakeFetcherConfig(sharedMountPath string) (*Config, error) {
 		sharedCfgMapPath:       "/configs",
 		serviceAccount:         fv1.FissionFetcherSA,
 		insecureRegistries:     os.Getenv("FETCHER_ALLOW_INSECURE_REGISTRIES"),
+		packageCacheHostPath:   os.Getenv("FETCHER_PACKAGE_CACHE_DIR"),
+		packageCacheMaxBytes:   packageCacheMaxBytes,
 	}, nil
 }
 
@@ -218,6 +242,33 @@ func (cfg *Config) NewSpecializeRequest(fn *fv1.Function, env *fv1.Environment)
 	}
 }
 
+// NewWasmSpecializeRequest builds the specialize request that loads a
+// function of executor type wasm into its namespace's wasm host. Many
+// functions share one host, so each generation gets its own store path; the
+// host has no environment and runs without secrets, configmaps or state.
+func (cfg *Config) NewWasmSpecializeRequest(fn *fv1.Function) fetcher.FunctionSpecializeRequest {
+	targetFilename := fmt.Sprintf("%s-%d", fn.UID, fn.Generation)
+
+	return fetcher.FunctionSpecializeRequest{
+		FetchReq: fetcher.FunctionFetchRequest{
+			FetchType: fv1.FETCH_DEPLOYMENT,
+			Package: metav1.ObjectMeta{
+				Namespace:       fn.Spec.Package.PackageRef.Namespace,
+				Name:            fn.Spec.Package.PackageRef.Name,
+				ResourceVersion: fn.Spec.Package.PackageRef.ResourceVersion,
+			},
+			Filename: targetFilename,
+		},
+		LoadReq: fetcher.FunctionLoadRequest{
+			FilePath:         filepath.Join(cfg.sharedMountPath, targetFilename),
+			FunctionName:     fn.Spec.Package.FunctionName,
+			FunctionMetadata: &fn.ObjectMeta,
+			EnvVersion:       2,
+			Wasm:             fn.Spec.InvokeStrategy.ExecutionStrategy.Wasm,
+		},
+	}
+}
+
 // stateKeyspace resolves the RFC-0023 keyspace for a stateful function
 // ("" = no token is delivered). Only this non-secret name travels in the
 // specialize request; the fetcher derives the actual token pod-locally.
@@ -265,6 +316,68 @@ func (cfg *Config) AddSpecializingFetcherToPodSpec(podSpec *apiv1.PodSpec, mainC
 	)
 }
 
+// AddPackageCacheToPodSpec mounts the node-local package cache into the
+// fetcher sidecar that AddFetcherToPodSpec added to podSpec, so the fetcher
+// copies deployments other pods on the node already fetched instead of
+// downloading and unzipping them again. It is a no-op unless FETCHER_PACKAGE_CACHE_DIR is
+// set. Only poolmgr pods use it: they specialize on demand, so the fetch is
+// on the cold-start path.
+func (cfg *Config) AddPackageCacheToPodSpec(podSpec *apiv1.PodSpec) error {
+	if cfg.packageCacheHostPath == "" {
+		return nil
+	}
+
+	ix := slices.IndexFunc(podSpec.Containers, func(c apiv1.Container) bool { return c.Name == "fetcher" })
+	if ix < 0 {
+		return errors.New("could not find fetcher container in given PodSpec")
+	}
+	c := &podSpec.Containers[ix]
+	// The shared volume path stays the last argument.
+	c.Command = slices.Insert(c.Command, len(c.Command)-1,
+		"-package-cache-dir", packageCacheMountPath,
+		"-package-cache-max-bytes", strconv.FormatInt(cfg.packageCacheMaxBytes, 10),
+	)
+	c.VolumeMounts = append(c.VolumeMounts, apiv1.VolumeMount{
+		Name:      packageCacheVolume,
+		MountPath: packageCacheMountPath,
+	})
+
+	hostPathType := apiv1.HostPathDirectoryOrCreate
+	podSpec.Volumes = append(podSpec.Volumes, apiv1.Volume{
+		Name: packageCacheVolume,
+		VolumeSource: apiv1.VolumeSource{
+			HostPath: &apiv1.HostPathVolumeSource{
+				Path: cfg.packageCacheHostPath,
+				Type: &hostPathType,
+			},
+		},
+	})
+	return nil
+}
+
+// AddSignedSpecializeToPodSpec has the fetcher sidecar that
+// AddFetcherToPodSpec added to podSpec sign its specialize request, and
+// gives runtimeContainerName the internal-auth keys the fetcher holds so it
+// can verify the request with the fetcher's own key. It is for runtimes that
+// serve their specialization API to more than their own pod, like the wasm
+// host.
+func (cfg *Config) AddSignedSpecializeToPodSpec(podSpec *apiv1.PodSpec, runtimeContainerName, namespace string) error {
+	fetcherIx := slices.IndexFunc(podSpec.Containers, func(c apiv1.Container) bool { return c.Name == "fetcher" })
+	if fetcherIx < 0 {
+		return errors.New("could not find fetcher container in given PodSpec")
+	}
+	runtimeIx := slices.IndexFunc(podSpec.Containers, func(c apiv1.Container) bool { return c.Name == runtimeContainerName })
+	if runtimeIx < 0 {
+		return fmt.Errorf("could not find runtime container %s in given PodSpec", runtimeContainerName)
+	}
+	c := &podSpec.Containers[fetcherIx]
+	// The shared volume path stays the last argument.
+	c.Command = slices.Insert(c.Command, len(c.Command)-1, "-sign-specialize")
+	rc := &podSpec.Containers[runtimeIx]
+	rc.Env = append(rc.Env, internalAuthEnvVars(namespace)...)
+	return nil
+}
+
 func (cfg *Config) fetcherCommand(extraArgs ...string) []string {
 	command := []string{"/fetcher",
 		"-secret-dir", cfg.sharedSecretPath,
@@ -70,6 +70,27 @@ func TestNewSpecializeRequestStateKeyspace(t *testing.T) {
 // pre-implementation review demanded: the -specialize-request CLI arg is
 // visible to anyone who can read pods, so it must carry only the NON-SECRET
 // keyspace name — never a token or the master secret.
+func TestNewWasmSpecializeRequest(t *testing.T) {
+	t.Parallel()
+	cfg, err := MakeFetcherConfig("/userfunc")
+	require.NoError(t, err)
+	fn := stateTestFn(&fv1.StateConfig{})
+	fn.UID = "uid-1"
+	fn.Generation = 3
+	fn.Spec.Package.FunctionName = "main.wasm"
+	fn.Spec.Secrets = []fv1.SecretReference{{Name: "s", Namespace: "user-ns"}}
+	fn.Spec.InvokeStrategy.ExecutionStrategy.Wasm = &fv1.WasmConfig{MaxExecutionTime: "5s"}
+
+	req := cfg.NewWasmSpecializeRequest(fn)
+	assert.Equal(t, "uid-1-3", req.FetchReq.Filename, "each generation gets its own store path in the shared host")
+	assert.Equal(t, "/userfunc/uid-1-3", req.LoadReq.FilePath)
+	assert.Equal(t, "main.wasm", req.LoadReq.FunctionName)
+	assert.Equal(t, 2, req.LoadReq.EnvVersion)
+	assert.Equal(t, "5s", req.LoadReq.Wasm.MaxExecutionTime)
+	assert.Empty(t, req.FetchReq.Secrets, "the shared host gets no secrets")
+	assert.Empty(t, req.LoadReq.StateKeyspace, "nor a state token")
+}
+
 func TestSpecializePayloadCarriesNoToken(t *testing.T) {
 	t.Parallel()
 	cfg, err := MakeFetcherConfig("/userfunc")
@@ -63,7 +63,13 @@ type (
 		// boundary and would otherwise reject the extra headers (or be
 		// confused by the body buffering that signing requires).
 		storageHTTPClient *http.Client
-		Info              PodInfo
+		// specializeHTTPClient posts the load request to the runtime. It
+		// is httpClient unless SignSpecializeRequests made it sign.
+		specializeHTTPClient *http.Client
+		// pkgCache is the node-local package cache; nil unless enabled
+		// with EnablePackageCache.
+		pkgCache *packageCache
+		Info     PodInfo
 	}
 	PodInfo struct {
 		Name      string
@@ -160,11 +166,28 @@ func MakeFetcher(logger logr.Logger, clientGen crd.ClientGeneratorInterface, sha
 			Name:      string(name),
 			Namespace: string(namespace),
 		},
-		httpClient:        hc,
-		storageHTTPClient: storageHC,
+		httpClient:           hc,
+		storageHTTPClient:    storageHC,
+		specializeHTTPClient: hc,
 	}, nil
 }
 
+// SignSpecializeRequests makes SpecializePod sign its load request with the
+// key the fetcher verifies its own API with, for a runtime that verifies it
+// too: the wasm host, whose specialization API shares a port with the
+// functions of every tenant it serves. Environment runtimes do not verify
+// and keep getting unsigned requests.
+func (fetcher *Fetcher) SignSpecializeRequests() {
+	base := otelhttp.NewTransport(http.DefaultTransport)
+	var rt http.RoundTripper = base
+	if key := hmacauth.DecodeKeyFromEnv(os.Getenv("FISSION_FETCHER_KEY")); len(key) > 0 {
+		rt = hmacauth.NewSigner(key, base, time.Now)
+	} else if master := storageSvcClient.HMACSecretFromEnv(); len(master) > 0 {
+		rt = hmacauth.ServiceSigner(master, hmacauth.ServiceFetcher, base, time.Now)
+	}
+	fetcher.specializeHTTPClient = &http.Client{Transport: rt}
+}
+
 // httpClientForURL picks the right downloader for a URL: the signed
 // storageHTTPClient when the URL targets storagesvc (path prefix
 // /v1/archive), the unsigned httpClient otherwise. Without this guard
@@ -376,6 +399,10 @@ func (fetcher *Fetcher) Fetch(ctx context.Context, pkg *fv1.Package, req Functio
 		return http.StatusBadRequest, fmt.Errorf("%w, request: %v", err, req)
 	}
 
+	// cacheKey is set when the fetched package should be added to the
+	// node-local package cache.
+	var cacheKey string
+
 	if req.FetchType == fv1.FETCH_URL {
 		otelUtils.SpanTrackEvent(ctx, "fetch_url", otelUtils.MapToAttributes(map[string]string{
 			"package-name":      pkg.Name,
@@ -420,6 +447,28 @@ func (fetcher *Fetcher) Fetch(ctx context.Context, pkg *fv1.Package, req Functio
 			return fetcher.fetchOCI(ctx, pkg, archive.OCI, storePath)
 		}
 
+		// A literal carrying a checksum must match it, like a download does.
+		if len(archive.Literal) > 0 && len(archive.Checksum.Sum) > 0 {
+			checksum, err := utils.GetChecksum(bytes.NewReader(archive.Literal))
+			if err != nil {
+				e := "failed to get checksum"
+				logger.Error(err, e)
+				return http.StatusBadRequest, fmt.Errorf("%s: %w", e, err)
+			}
+			if err := verifyChecksum(checksum, &archive.Checksum); err != nil {
+				e := "failed to verify checksum"
+				logger.Error(err, e)
+				return http.StatusBadRequest, fmt.Errorf("%s: %w", e, err)
+			}
+		}
+
+		if key, ok := fetcher.cacheKey(pkg, req); ok {
+			cacheKey = key
+			if fetcher.restoreFromCache(ctx, pkg, key, storePath) {
+				return http.StatusOK, nil
+			}
+		}
+
 		// get package data as literal or by url
 		if len(archive.Literal) > 0 {
 			// write pkg.Literal into tmpPath
@@ -479,6 +528,12 @@ func (fetcher *Fetcher) Fetch(ctx context.Context, pkg *fv1.Package, req Functio
 		tmpPath = tmpUnarchivePath
 	}
 
+	if cacheKey != "" {
+		if err := fetcher.pkgCache.store(ctx, cacheKey, tmpPath); err != nil {
+			logger.Error(err, "error adding package to node cache", "key", cacheKey)
+		}
+	}
+
 	// move tmp file to requested filename
 	err = fetcher.rename(tmpPath, storePath)
 	if err != nil {
@@ -896,7 +951,12 @@ func (fetcher *Fetcher) SpecializePod(ctx context.Context, fetchReq FunctionFetc
 		otelUtils.SpanTrackEvent(ctx, "specializeCall", otelUtils.MapToAttributes(map[string]string{
 			"url": specializeURL,
 		})...)
-		resp, err := ctxhttp.Post(ctx, fetcher.httpClient, specializeURL, contentType, reader)
+		// A signing client reads the body before it dials, so a retry
+		// after a refused connection must start it over.
+		if _, err := reader.Seek(0, io.SeekStart); err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("error rewinding load request: %w", err)
+		}
+		resp, err := ctxhttp.Post(ctx, fetcher.specializeHTTPClient, specializeURL, contentType, reader)
 		if err == nil && resp.StatusCode < 300 {
 			// Success
 			resp.Body.Close()
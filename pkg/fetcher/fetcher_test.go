@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
 	"github.com/fission/fission/pkg/utils/loggerfactory"
 )
 
@@ -77,6 +78,29 @@ func TestHTTPClientForURL(t *testing.T) {
 	assert.Same(t, general, f.httpClientForURL("http://example.com/code.zip"), "external URL uses the general client")
 }
 
+func TestSignSpecializeRequests(t *testing.T) {
+	t.Setenv(fv1.InternalAuthSecretEnv, "master")
+	verifier := hmacauth.ServiceVerifier([]byte("master"), nil, hmacauth.ServiceFetcher, hmacauth.VerifierOpts{
+		SkewSec: 60, MaxBodyBytes: hmacauth.DefaultMaxBodyBytes,
+	})
+	runtime := httptest.NewServer(verifier(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+	defer runtime.Close()
+	post := func(c *http.Client) int {
+		t.Helper()
+		resp, err := c.Post(runtime.URL+"/v2/specialize", "application/json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	f := &Fetcher{httpClient: &http.Client{}}
+	f.specializeHTTPClient = f.httpClient
+	assert.Equal(t, http.StatusUnauthorized, post(f.specializeHTTPClient), "environment runtimes get unsigned requests")
+	f.SignSpecializeRequests()
+	assert.Equal(t, http.StatusOK, post(f.specializeHTTPClient))
+	assert.NotSame(t, f.httpClient, f.specializeHTTPClient, "only the specialize request is signed")
+}
+
 func TestVersionHandler(t *testing.T) {
 	t.Parallel()
 	f := &Fetcher{logger: loggerfactory.GetLogger()}
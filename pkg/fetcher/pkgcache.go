@@ -0,0 +1,388 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/utils/metrics"
+	otelUtils "github.com/fission/fission/pkg/utils/otel"
+	"github.com/fission/fission/pkg/versioning"
+)
+
+var (
+	pkgCacheHits = metrics.Int64Counter(
+		"fission_fetcher_package_cache_hits_total",
+		"Deployment packages restored from the node-local package cache instead of being downloaded.",
+	)
+	pkgCacheMisses = metrics.Int64Counter(
+		"fission_fetcher_package_cache_misses_total",
+		"Cacheable deployment packages not found in the node-local package cache.",
+	)
+	pkgCacheEvictions = metrics.Int64Counter(
+		"fission_fetcher_package_cache_evictions_total",
+		"Entries evicted from the node-local package cache to stay under its size limit.",
+	)
+	pkgCacheBytes = metrics.Int64Gauge(
+		"fission_fetcher_package_cache_bytes",
+		"Bytes used by the node-local package cache, as last measured by this fetcher.",
+	)
+	pkgCacheEntries = metrics.Int64Gauge(
+		"fission_fetcher_package_cache_entries",
+		"Entries in the node-local package cache, as last measured by this fetcher.",
+	)
+)
+
+const (
+	// pkgCacheContent is the name of an entry's payload: the extracted tree,
+	// or the archive itself when it is kept as-is.
+	pkgCacheContent = "content"
+	// pkgCacheStagingPrefix marks entries still being written. They are
+	// renamed into place once complete and never read or counted.
+	pkgCacheStagingPrefix = ".tmp-"
+)
+
+// pkgDigestRe matches the only digest form used as a cache key. The checksum
+// on a Package is user-supplied, so anything else — in particular a value
+// carrying a path separator — is treated as uncacheable rather than escaped.
+var pkgDigestRe = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// packageCache is the node-local cache of fetched deployment packages. It
+// lives on a hostPath volume shared by every poolmgr fetcher on the node, so
+// a pod specializing a package another pod on the node already fetched
+// copies the extracted deployment instead of downloading and unzipping it
+// again.
+//
+// Each entry is a directory named after its key holding the payload at
+// pkgCacheContent. Entries are staged under a pkgCacheStagingPrefix name and
+// published with a rename, so a fetcher in another pod sees a whole entry or
+// none. An entry's modification time records its last use; eviction removes
+// the least recently used entries once the cache outgrows maxBytes.
+//
+// The cache and a pod's shared volume are separate mounts, which link(2)
+// cannot cross, so entries are always copied in and out: a restored tree
+// belongs to its pod alone.
+type packageCache struct {
+	logger   logr.Logger
+	dir      string
+	maxBytes int64
+
+	// mu serializes this fetcher's stores and evictions. Fetchers in other
+	// pods only race on renames and removals, which leave a concurrent
+	// restore either complete or failed — never partial.
+	mu sync.Mutex
+}
+
+func newPackageCache(logger logr.Logger, dir string, maxBytes int64) (*packageCache, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("error creating package cache directory %s: %w", dir, err)
+	}
+	return &packageCache{
+		logger:   logger.WithName("pkgcache"),
+		dir:      dir,
+		maxBytes: maxBytes,
+	}, nil
+}
+
+// EnablePackageCache makes Fetch consult and fill the node-local package
+// cache at dir. maxBytes bounds the cache's total size; 0 disables eviction.
+func (fetcher *Fetcher) EnablePackageCache(dir string, maxBytes int64) error {
+	cache, err := newPackageCache(fetcher.logger, dir, maxBytes)
+	if err != nil {
+		return err
+	}
+	fetcher.pkgCache = cache
+	return nil
+}
+
+// cacheKey returns the node-local package cache key for req, and false when
+// the cache is disabled or cannot serve req.
+func (fetcher *Fetcher) cacheKey(pkg *fv1.Package, req FunctionFetchRequest) (string, bool) {
+	if fetcher.pkgCache == nil {
+		return "", false
+	}
+	return packageCacheKey(pkg, req)
+}
+
+// restoreFromCache places the cached package for key at storePath and
+// reports whether it did. Any failure is logged and left to the caller to
+// recover from by fetching the package normally.
+func (fetcher *Fetcher) restoreFromCache(ctx context.Context, pkg *fv1.Package, key, storePath string) bool {
+	logger := otelUtils.LoggerWithTraceID(ctx, fetcher.logger)
+
+	tmpPath := filepath.Join(fetcher.sharedVolumePath, uuid.NewString())
+	hit, err := fetcher.pkgCache.restore(ctx, key, tmpPath)
+	if err != nil {
+		logger.Error(err, "error restoring package from node cache, fetching it instead", "key", key)
+		return false
+	}
+	if !hit {
+		return false
+	}
+	if err := fetcher.rename(tmpPath, storePath); err != nil {
+		logger.Error(err, "error placing cached package, fetching it instead", "key", key)
+		_ = os.RemoveAll(tmpPath)
+		return false
+	}
+
+	otelUtils.SpanTrackEvent(ctx, "packageCacheHit", otelUtils.GetAttributesForPackage(pkg)...)
+	logger.Info("restored package from node cache", "key", key, "location", storePath)
+	return true
+}
+
+// packageCacheKey returns the cache key for req, and false when req cannot
+// be served from the cache. Only deployment archives pinned by a sha256
+// checksum or literal contents qualify: PackageDigest falls back to the
+// source archive otherwise, which does not identify the deployment. OCI
+// archives are excluded too; the kubelet's image cache already covers them.
+//
+// A literal is keyed by the digest of its bytes, never by the checksum
+// recorded next to it: the cache is shared by every namespace on the node, so
+// a key taken on trust would let one tenant's literal stand in for another
+// tenant's checksummed package.
+func packageCacheKey(pkg *fv1.Package, req FunctionFetchRequest) (string, bool) {
+	if req.FetchType != fv1.FETCH_DEPLOYMENT {
+		return "", false
+	}
+	archive := &pkg.Spec.Deployment
+	if archive.OCI != nil {
+		return "", false
+	}
+	var digest string
+	switch {
+	case len(archive.Literal) > 0:
+		digest = fmt.Sprintf("sha256:%x", sha256.Sum256(archive.Literal))
+	case archive.Checksum.Type == fv1.ChecksumTypeSHA256 && archive.Checksum.Sum != "":
+		d, err := versioning.PackageDigest(pkg)
+		if err != nil {
+			return "", false
+		}
+		digest = d
+	default:
+		return "", false
+	}
+	if !pkgDigestRe.MatchString(digest) {
+		return "", false
+	}
+	key := strings.Replace(digest, ":", "-", 1)
+	// A kept archive and its extracted tree are different payloads for the
+	// same digest.
+	if req.KeepArchive {
+		key += "-archive"
+	}
+	return key, true
+}
+
+// restore copies the entry for key to dst, which must not exist. It
+// returns false on a miss; on an error dst is removed and the caller fetches
+// the package as if it had missed.
+func (c *packageCache) restore(ctx context.Context, key, dst string) (bool, error) {
+	entry := filepath.Join(c.dir, key)
+	content := filepath.Join(entry, pkgCacheContent)
+	if _, err := os.Lstat(content); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			pkgCacheMisses.Add(ctx, 1)
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := copyTree(content, dst); err != nil {
+		// Most likely another fetcher evicted the entry mid-restore.
+		_ = os.RemoveAll(dst)
+		pkgCacheMisses.Add(ctx, 1)
+		return false, fmt.Errorf("error copying cached package %s: %w", key, err)
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(entry, now, now); err != nil {
+		c.logger.Error(err, "error recording package cache entry use", "key", key)
+	}
+	pkgCacheHits.Add(ctx, 1)
+	return true, nil
+}
+
+// store adds src, a freshly fetched package, to the cache under key and then
+// evicts least recently used entries if the cache is over its size limit.
+func (c *packageCache) store(ctx context.Context, key, src string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := filepath.Join(c.dir, key)
+	if _, err := os.Stat(entry); err == nil {
+		// Another pod on the node stored it first.
+		return nil
+	}
+
+	staging, err := os.MkdirTemp(c.dir, pkgCacheStagingPrefix)
+	if err != nil {
+		return fmt.Errorf("error creating package cache staging directory: %w", err)
+	}
+	if err := copyTree(src, filepath.Join(staging, pkgCacheContent)); err != nil {
+		_ = os.RemoveAll(staging)
+		return fmt.Errorf("error staging package cache entry %s: %w", key, err)
+	}
+	if err := os.Rename(staging, entry); err != nil {
+		_ = os.RemoveAll(staging)
+		if _, statErr := os.Stat(entry); statErr == nil {
+			// Lost the race to another pod; its entry is as good as ours.
+			return nil
+		}
+		return fmt.Errorf("error publishing package cache entry %s: %w", key, err)
+	}
+
+	return c.evict(ctx, key)
+}
+
+// pkgCacheEntry is one published entry as seen by evict.
+type pkgCacheEntry struct {
+	name    string
+	size    int64
+	lastUse time.Time
+}
+
+// evict removes least recently used entries, other than keep, until the
+// cache fits in maxBytes, and reports the resulting usage. Callers hold mu.
+func (c *packageCache) evict(ctx context.Context, keep string) error {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("error listing package cache: %w", err)
+	}
+
+	var entries []pkgCacheEntry
+	var total int64
+	for _, de := range dirEntries {
+		if !de.IsDir() || strings.HasPrefix(de.Name(), pkgCacheStagingPrefix) {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			// Removed by another fetcher since ReadDir.
+			continue
+		}
+		size, err := treeSize(filepath.Join(c.dir, de.Name()))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, pkgCacheEntry{name: de.Name(), size: size, lastUse: info.ModTime()})
+		total += size
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUse.Before(entries[j].lastUse)
+	})
+
+	count := len(entries)
+	for _, e := range entries {
+		if c.maxBytes <= 0 || total <= c.maxBytes {
+			break
+		}
+		if e.name == keep {
+			continue
+		}
+		// Pods that restored the entry have their own copies.
+		if err := os.RemoveAll(filepath.Join(c.dir, e.name)); err != nil {
+			c.logger.Error(err, "error evicting package cache entry", "key", e.name)
+			continue
+		}
+		pkgCacheEvictions.Add(ctx, 1)
+		total -= e.size
+		count--
+		c.logger.Info("evicted package cache entry", "key", e.name, "size", e.size, "last_use", e.lastUse)
+	}
+
+	pkgCacheBytes.Record(ctx, total)
+	pkgCacheEntries.Record(ctx, int64(count))
+	c.logger.Info("package cache usage", "dir", c.dir, "entries", count, "bytes", total, "max_bytes", c.maxBytes)
+	return nil
+}
+
+// treeSize sums the sizes of the regular files under path.
+func treeSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// copyTree recreates src at dst, which may be a single file or a directory
+// tree: directories are created, symlinks recreated and regular files
+// copied with their permission bits.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return fmt.Errorf("unsupported file type %v at %s", d.Type(), path)
+		}
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package fetcher
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/utils/loggerfactory"
+)
+
+func TestPackageCacheKey(t *testing.T) {
+	t.Parallel()
+	sum := strings.Repeat("ab", 32)
+	deploy := FunctionFetchRequest{FetchType: fv1.FETCH_DEPLOYMENT}
+
+	checksummed := &fv1.Package{}
+	checksummed.Spec.Deployment = fv1.Archive{URL: "http://storagesvc/v1/archive?id=1", Checksum: fv1.Checksum{Type: fv1.ChecksumTypeSHA256, Sum: sum}}
+	key, ok := packageCacheKey(checksummed, deploy)
+	require.True(t, ok)
+	assert.Equal(t, "sha256-"+sum, key)
+
+	key, ok = packageCacheKey(checksummed, FunctionFetchRequest{FetchType: fv1.FETCH_DEPLOYMENT, KeepArchive: true})
+	require.True(t, ok)
+	assert.Equal(t, "sha256-"+sum+"-archive", key, "a kept archive must not share the extracted tree's entry")
+
+	_, ok = packageCacheKey(checksummed, FunctionFetchRequest{FetchType: fv1.FETCH_SOURCE})
+	assert.False(t, ok, "source fetches are not cached")
+
+	literal := &fv1.Package{}
+	literal.Spec.Deployment = fv1.Archive{Literal: []byte("code")}
+	key, ok = packageCacheKey(literal, deploy)
+	require.True(t, ok, "literal deployments are pinned by their contents")
+	assert.Equal(t, fmt.Sprintf("sha256-%x", sha256.Sum256([]byte("code"))), key)
+
+	literal.Spec.Deployment.Checksum = fv1.Checksum{Type: fv1.ChecksumTypeSHA256, Sum: sum}
+	forged, ok := packageCacheKey(literal, deploy)
+	require.True(t, ok)
+	assert.Equal(t, key, forged, "a literal's recorded checksum must not pick its cache entry")
+
+	unpinned := &fv1.Package{}
+	unpinned.Spec.Deployment = fv1.Archive{URL: "http://example.com/code.zip"}
+	unpinned.Spec.Source = fv1.Archive{Literal: []byte("src")}
+	_, ok = packageCacheKey(unpinned, deploy)
+	assert.False(t, ok, "the source digest does not identify an unpinned deployment")
+
+	traversal := &fv1.Package{}
+	traversal.Spec.Deployment = fv1.Archive{Checksum: fv1.Checksum{Type: fv1.ChecksumTypeSHA256, Sum: "../../etc"}}
+	_, ok = packageCacheKey(traversal, deploy)
+	assert.False(t, ok, "a malformed checksum must not become a path")
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestFetchPackageCache(t *testing.T) {
+	t.Parallel()
+	cacheDir := t.TempDir()
+	newFetcher := func(t *testing.T) *Fetcher {
+		f := &Fetcher{logger: loggerfactory.GetLogger(), sharedVolumePath: t.TempDir()}
+		require.NoError(t, f.EnablePackageCache(cacheDir, 0))
+		return f
+	}
+
+	pkg := &fv1.Package{}
+	pkg.Spec.Deployment = fv1.Archive{Literal: zipArchive(t, map[string]string{"main.py": "print(1)", "lib/util.py": "x = 1"})}
+	pkg.Status.BuildStatus = fv1.BuildStatusSucceeded
+	req := FunctionFetchRequest{FetchType: fv1.FETCH_DEPLOYMENT, Filename: "deployarchive"}
+	key, ok := packageCacheKey(pkg, req)
+	require.True(t, ok)
+
+	first := newFetcher(t)
+	_, err := first.Fetch(t.Context(), pkg, req)
+	require.NoError(t, err)
+	cached, err := os.ReadFile(filepath.Join(cacheDir, key, pkgCacheContent, "lib", "util.py"))
+	require.NoError(t, err, "a miss must populate the cache")
+	assert.Equal(t, "x = 1", string(cached))
+
+	// A second pod on the node gets the tree without the archive: the
+	// deployment is no longer reachable, so only the cache can supply it.
+	pkg.Spec.Deployment.Literal = nil
+	pkg.Spec.Deployment.Checksum = fv1.Checksum{Type: fv1.ChecksumTypeSHA256, Sum: strings.TrimPrefix(key, "sha256-")}
+	second := newFetcher(t)
+	_, err = second.Fetch(t.Context(), pkg, req)
+	require.NoError(t, err)
+
+	restored := filepath.Join(second.sharedVolumePath, "deployarchive", "main.py")
+	got, err := os.ReadFile(restored)
+	require.NoError(t, err)
+	assert.Equal(t, "print(1)", string(got))
+
+	restoredInfo, err := os.Stat(restored)
+	require.NoError(t, err)
+	cachedInfo, err := os.Stat(filepath.Join(cacheDir, key, pkgCacheContent, "main.py"))
+	require.NoError(t, err)
+	assert.False(t, os.SameFile(restoredInfo, cachedInfo),
+		"the cache and the shared volume are separate mounts in a pod, so a hit must copy")
+}
+
+func TestFetchPackageCacheRejectsMismatchedLiteral(t *testing.T) {
+	t.Parallel()
+	cacheDir := t.TempDir()
+	f := &Fetcher{logger: loggerfactory.GetLogger(), sharedVolumePath: t.TempDir()}
+	require.NoError(t, f.EnablePackageCache(cacheDir, 0))
+
+	// A literal claiming another package's checksum.
+	victim := sha256.Sum256(zipArchive(t, map[string]string{"main.py": "print(1)"}))
+	pkg := &fv1.Package{}
+	pkg.Spec.Deployment = fv1.Archive{
+		Literal:  zipArchive(t, map[string]string{"main.py": "print('pwned')"}),
+		Checksum: fv1.Checksum{Type: fv1.ChecksumTypeSHA256, Sum: fmt.Sprintf("%x", victim)},
+	}
+	pkg.Status.BuildStatus = fv1.BuildStatusSucceeded
+	req := FunctionFetchRequest{FetchType: fv1.FETCH_DEPLOYMENT, Filename: "deployarchive"}
+
+	code, err := f.Fetch(t.Context(), pkg, req)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.NoFileExists(t, filepath.Join(f.sharedVolumePath, "deployarchive"))
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a rejected literal must not reach the cache")
+}
+
+func TestPackageCacheEviction(t *testing.T) {
+	t.Parallel()
+	cache, err := newPackageCache(loggerfactory.GetLogger(), t.TempDir(), 10)
+	require.NoError(t, err)
+
+	src := t.TempDir()
+	put := func(key string, lastUse time.Time) {
+		t.Helper()
+		path := filepath.Join(src, key)
+		require.NoError(t, os.WriteFile(path, []byte("12345"), 0600))
+		require.NoError(t, cache.store(t.Context(), key, path))
+		require.NoError(t, os.Chtimes(filepath.Join(cache.dir, key), lastUse, lastUse))
+	}
+
+	now := time.Now()
+	put("old", now.Add(-2*time.Hour))
+	put("recent", now.Add(-time.Hour))
+	assert.DirExists(t, filepath.Join(cache.dir, "old"))
+	assert.DirExists(t, filepath.Join(cache.dir, "recent"))
+
+	// A third 5-byte entry puts the cache over its 10-byte limit; the least
+	// recently used entry goes, the one just stored stays.
+	put("new", now)
+	assert.NoDirExists(t, filepath.Join(cache.dir, "old"))
+	assert.DirExists(t, filepath.Join(cache.dir, "recent"))
+	assert.DirExists(t, filepath.Join(cache.dir, "new"))
+
+	hit, err := cache.restore(t.Context(), "old", filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, err)
+	assert.False(t, hit, "an evicted entry must miss")
+}
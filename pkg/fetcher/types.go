@@ -61,6 +61,11 @@ type (
 		// (it appears in the pod-visible -specialize-request arg); the token
 		// itself never does.
 		StateKeyspace string `json:"stateKeyspace,omitempty"`
+
+		// Wasm carries the per-invocation limits of a function of executor
+		// type wasm; the fetcher passes it through to the wasm host, which
+		// loads the module at FilePath under them.
+		Wasm *fv1.WasmConfig `json:"wasm,omitempty"`
 	}
 
 	// ArchiveUploadRequest send from builder manager describes which
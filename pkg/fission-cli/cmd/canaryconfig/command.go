@@ -94,7 +94,31 @@ Example (alias mode):
 		Optional: []flag.Flag{flag.WaitTimeout},
 	})
 
-	command.AddCommand(createCmd, getCmd, updateCmd, deleteCmd, listCmd, waitCmd)
+	pauseCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "pause",
+		Short: "Hold a canary rollout at its current weights",
+	}, Pause, flag.FlagSet{
+		Required: []flag.Flag{flag.CanaryName},
+	})
+
+	resumeCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "resume",
+		Short: "Continue a paused canary rollout",
+	}, Resume, flag.FlagSet{
+		Required: []flag.Flag{flag.CanaryName},
+	})
+
+	promoteCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "promote",
+		Short: "Shift all traffic to the new target and complete the canary rollout",
+		Long: `Shift all traffic to the new target and complete the canary rollout at
+once, skipping its remaining steps, analysis and webhook gates. In alias mode
+the alias is repointed at --newfn.`,
+	}, Promote, flag.FlagSet{
+		Required: []flag.Flag{flag.CanaryName},
+	})
+
+	command.AddCommand(createCmd, getCmd, updateCmd, deleteCmd, listCmd, waitCmd, pauseCmd, resumeCmd, promoteCmd)
 
 	return command
 }
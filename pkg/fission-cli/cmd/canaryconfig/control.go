@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package canaryconfig
+
+import (
+	"fmt"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/fission-cli/cliwrapper/cli"
+	"github.com/fission/fission/pkg/fission-cli/cmd"
+	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
+	"github.com/fission/fission/pkg/fission-cli/util"
+)
+
+// ControlSubCommand flips one of a rollout's manual controls
+// (CanaryConfigSpec.Paused / .Promote). They are spec fields, not status, so
+// the change bumps the generation and the controller acts on it at once.
+type ControlSubCommand struct {
+	cmd.CommandActioner
+}
+
+// Pause holds a rollout at its current weights until it is resumed.
+func Pause(input cli.Input) error {
+	return (&ControlSubCommand{}).run(input, "paused", func(cur *fv1.CanaryConfig) { cur.Spec.Paused = true })
+}
+
+// Resume lets a paused rollout continue from its current weights.
+func Resume(input cli.Input) error {
+	return (&ControlSubCommand{}).run(input, "resumed", func(cur *fv1.CanaryConfig) { cur.Spec.Paused = false })
+}
+
+// Promote shifts all traffic to the new target and completes the rollout,
+// skipping the remaining steps, the analysis and the webhook gates.
+func Promote(input cli.Input) error {
+	return (&ControlSubCommand{}).run(input, "promoted", func(cur *fv1.CanaryConfig) { cur.Spec.Promote = true })
+}
+
+func (opts *ControlSubCommand) run(input cli.Input, verb string, mutate func(*fv1.CanaryConfig)) error {
+	_, ns, err := opts.GetResourceNamespace(input)
+	if err != nil {
+		return fmt.Errorf("error updating canary config: %w", err)
+	}
+	name := input.String(flagkey.CanaryName)
+	canaries := opts.Client().FissionClientSet.CoreV1().CanaryConfigs(ns)
+	if _, err := util.UpdateOnConflict(input.Context(), canaries, name, mutate); err != nil {
+		return fmt.Errorf("error updating canary config: %w", err)
+	}
+	fmt.Printf("canary config '%v' %v\n", name, verb)
+	return nil
+}
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/fission-cli/cliwrapper/cli"
 	"github.com/fission/fission/pkg/fission-cli/cliwrapper/driver/dummy"
 	"github.com/fission/fission/pkg/fission-cli/cmd"
 	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
@@ -121,3 +122,30 @@ func TestCanaryUpdateMissingConfigErrors(t *testing.T) {
 
 	require.Error(t, Update(in))
 }
+
+func TestCanaryManualControls(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		run   func(cli.Input) error
+		check func(t *testing.T, spec fv1.CanaryConfigSpec)
+	}{
+		{"pause", Pause, func(t *testing.T, spec fv1.CanaryConfigSpec) { assert.True(t, spec.Paused) }},
+		{"resume", Resume, func(t *testing.T, spec fv1.CanaryConfigSpec) { assert.False(t, spec.Paused) }},
+		{"promote", Promote, func(t *testing.T, spec fv1.CanaryConfigSpec) { assert.True(t, spec.Promote) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newCanary()
+			c.Spec.Paused = tc.name == "resume"
+			fc := setCanaryClient(c)
+
+			in := dummy.TestFlagSet()
+			in.Set(flagkey.CanaryName, "canary")
+			require.NoError(t, tc.run(in))
+
+			got, err := fc.CoreV1().CanaryConfigs("default").Get(t.Context(), "canary", metav1.GetOptions{})
+			require.NoError(t, err)
+			tc.check(t, got.Spec)
+			assert.Equal(t, 10, got.Spec.WeightIncrement, "the rest of the spec is untouched")
+		})
+	}
+}
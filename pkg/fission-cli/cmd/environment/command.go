@@ -84,13 +84,23 @@ func Commands() *cobra.Command {
 		Optional: []flag.Flag{flag.Output},
 	})
 
+	usageCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "usage",
+		Short: "Show the warm-pool overhead of environments",
+		Long: "Show, per environment, how long the poolmgr pods it kept warm (not yet specialized to a function) " +
+			"ran and their requested CPU and memory over that time, as accounted by the executor. Usage is " +
+			"rolled up hourly, so the range widens to whole hours. Requires usage accounting on the executor.",
+	}, Usage, flag.FlagSet{
+		Optional: []flag.Flag{flag.EnvName, flag.AllNamespaces, flag.UsageSince, flag.UsageUntil, flag.Output},
+	})
+
 	command := &cobra.Command{
 		Use:     "environment",
 		Aliases: []string{"env"},
 		Short:   "Create, update and manage environments",
 	}
 
-	command.AddCommand(createCmd, getCmd, updateCmd, deleteCmd, listCmd, listPodsCmd, impactCmd)
+	command.AddCommand(createCmd, getCmd, updateCmd, deleteCmd, listCmd, listPodsCmd, impactCmd, usageCmd)
 
 	return command
 }
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package environment
+
+import (
+	"fmt"
+
+	"github.com/fission/fission/pkg/executor/usage"
+	"github.com/fission/fission/pkg/fission-cli/cliwrapper/cli"
+	"github.com/fission/fission/pkg/fission-cli/cmd"
+	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
+	"github.com/fission/fission/pkg/fission-cli/util"
+)
+
+type UsageSubCommand struct {
+	cmd.CommandActioner
+}
+
+// Usage prints the warm-pool overhead of poolmgr environments: the pod time
+// and request-weighted CPU and memory time of the pods they kept warm, not
+// yet specialized to a function, over a time range.
+func Usage(input cli.Input) error {
+	return (&UsageSubCommand{}).do(input)
+}
+
+func (opts *UsageSubCommand) do(input cli.Input) error {
+	format, err := util.ParseOutputFormat(input.String(flagkey.Output))
+	if err != nil {
+		return err
+	}
+	namespace, err := opts.ResolveNamespace(input)
+	if err != nil {
+		return fmt.Errorf("error getting environment usage: %w", err)
+	}
+	resp, err := util.FetchUsage(input, opts.Client(), usage.KindEnvironment, namespace, input.String(flagkey.EnvName))
+	if err != nil {
+		return err
+	}
+
+	headers := append([]string{"NAME", "NAMESPACE"}, util.UsageHeaders...)
+	row := func(r usage.Row) []string {
+		return append([]string{r.Name, r.Namespace}, util.UsageColumns(r.Usage)...)
+	}
+	if err := util.PrintObjects(format, resp.Rows, headers, row, nil, func(usage.Row) []string { return nil }); err != nil {
+		return err
+	}
+	if format == util.OutputTable || format == util.OutputWide {
+		fmt.Printf("\nFrom %s to %s.\n", resp.Since.Format("2006-01-02T15:04Z"), resp.Until.Format("2006-01-02T15:04Z"))
+	}
+	return nil
+}
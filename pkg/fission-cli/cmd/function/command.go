@@ -34,10 +34,12 @@ func Commands() *cobra.Command {
 			flag.FnAsyncMaxAttempts, flag.FnAsyncMaxAge,
 			flag.FnAsyncOnSuccess, flag.FnAsyncOnFailure,
 			flag.FnAsyncOnSuccessTopic, flag.FnAsyncOnFailureTopic,
+			flag.FnAsyncPriority,
 			flag.FnOnceOnly, flag.Labels, flag.Annotation, flag.FnRetainPods,
 			flag.FnProvisionedConcurrency,
 			flag.FnVersioning, flag.FnRetainVersions,
-			flag.FnProvisionedSchedule,
+			flag.FnProvisionedSchedule, flag.FnProvisionedAuto,
+			flag.FnResourceClass,
 
 			// TODO retired pkg & trigger related flags from function cmd
 			flag.PkgCode, flag.PkgSrcArchive, flag.PkgDeployArchive,
@@ -106,10 +108,12 @@ func Commands() *cobra.Command {
 			flag.FnAsyncMaxAttempts, flag.FnAsyncMaxAge,
 			flag.FnAsyncOnSuccess, flag.FnAsyncOnFailure,
 			flag.FnAsyncOnSuccessTopic, flag.FnAsyncOnFailureTopic,
+			flag.FnAsyncPriority,
 			flag.FnOnceOnly, flag.Labels, flag.Annotation, flag.FnRetainPods,
 			flag.FnProvisionedConcurrency,
 			flag.FnVersioning, flag.FnRetainVersions,
-			flag.FnProvisionedSchedule,
+			flag.FnProvisionedSchedule, flag.FnProvisionedAuto,
+			flag.FnResourceClass,
 
 			flag.PkgCode, flag.PkgSrcArchive, flag.PkgDeployArchive,
 			flag.PkgSrcChecksum, flag.PkgDeployChecksum, flag.PkgInsecure,
@@ -321,6 +325,27 @@ func Commands() *cobra.Command {
 		Optional: []flag.Flag{flag.GCVersionsKeep},
 	})
 
+	jobStatusCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "job-status",
+		Short: "Show the state of one invocation of a function of executor type job",
+		Long: "Every invocation of a function of executor type job answers with an invocation id and runs " +
+			"as its own Kubernetes Job. This reports the invocation's phase, and once its Job has finished " +
+			"the function's response and the tail of its output.",
+	}, JobStatus, flag.FlagSet{
+		Required: []flag.Flag{flag.FnName, flag.FnJobID},
+		Optional: []flag.Flag{flag.Namespace},
+	})
+
+	usageCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "usage",
+		Short: "Show the pod and resource time the executor charged to functions",
+		Long: "Show, per function version, how long its pods ran and their requested CPU and memory over that " +
+			"time, as accounted by the executor (poolmgr, newdeploy and container functions). Usage is rolled " +
+			"up hourly, so the range widens to whole hours. Requires usage accounting on the executor.",
+	}, Usage, flag.FlagSet{
+		Optional: []flag.Flag{flag.FnName, flag.AllNamespaces, flag.UsageSince, flag.UsageUntil, flag.Output},
+	})
+
 	command := &cobra.Command{
 		Use:     "function",
 		Aliases: []string{"fn"},
@@ -328,7 +353,7 @@ func Commands() *cobra.Command {
 	}
 	command.AddCommand(createCmd, getCmd, getmetaCmd, describeCmd, updateCmd, deleteCmd, listCmd, logsCmd, testCmd,
 		runLocalCmd, runContainerCmd, updateContainerCmd, listPodsCmd, waitCmd, toolsCmd, publishCmd, versionsCmd,
-		rollbackCmd, gcVersionsCmd, DLQCommands(), StateCommands())
+		rollbackCmd, gcVersionsCmd, jobStatusCmd, usageCmd, DLQCommands(), StateCommands())
 
 	return command
 }
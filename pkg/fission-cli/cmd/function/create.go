@@ -55,6 +55,9 @@ func getProvisionedConcurrencyConfig(input cli.Input) (*fv1.ProvisionedConcurren
 		if input.IsSet(flagkey.FnProvisionedSchedule) {
 			return nil, fmt.Errorf("provisioned concurrency window is set but provisioned concurrency is not")
 		}
+		if input.IsSet(flagkey.FnProvisionedAuto) {
+			return nil, fmt.Errorf("--%s requires --%s, the target used until there is history", flagkey.FnProvisionedAuto, flagkey.FnProvisionedConcurrency)
+		}
 		return nil, nil
 	}
 	target := input.Int(flagkey.FnProvisionedConcurrency)
@@ -63,6 +66,9 @@ func getProvisionedConcurrencyConfig(input cli.Input) (*fv1.ProvisionedConcurren
 		if input.IsSet(flagkey.FnProvisionedSchedule) {
 			return nil, fmt.Errorf("--%s requires --%s >= 1, got %d", flagkey.FnProvisionedSchedule, flagkey.FnProvisionedConcurrency, target)
 		}
+		if input.IsSet(flagkey.FnProvisionedAuto) {
+			return nil, fmt.Errorf("--%s requires --%s >= 1, got %d", flagkey.FnProvisionedAuto, flagkey.FnProvisionedConcurrency, target)
+		}
 		return nil, nil
 	}
 	var windows []fv1.ProvisionedWindow
@@ -81,9 +87,59 @@ func getProvisionedConcurrencyConfig(input cli.Input) (*fv1.ProvisionedConcurren
 		}
 	}
 	cfg := &fv1.ProvisionedConcurrencyConfig{Target: target, Windows: windows}
+	if input.IsSet(flagkey.FnProvisionedAuto) {
+		auto, err := getProvisionedAuto(input.String(flagkey.FnProvisionedAuto))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Auto = auto
+	}
 	return cfg, nil
 }
 
+// getProvisionedAuto parses the --provisioned-auto value into a
+// ProvisionedAutoConfig: semicolon-separated key=value pairs, every key
+// optional. "off" returns nil, which disables auto mode on update.
+func getProvisionedAuto(value string) (*fv1.ProvisionedAutoConfig, error) {
+	if value == "off" {
+		return nil, nil
+	}
+	auto := &fv1.ProvisionedAutoConfig{}
+	if value == "" {
+		return auto, nil
+	}
+	ints := map[string]*int{
+		"percentile":  &auto.Percentile,
+		"headroom":    &auto.HeadroomPercent,
+		"min":         &auto.Min,
+		"max":         &auto.Max,
+		"seasonality": &auto.SeasonalityDays,
+	}
+	for pair := range strings.SplitSeq(value, ";") {
+		key, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid provisioned auto definition: %s, format is key=value", pair)
+		}
+		if key == "window" {
+			auto.Window = v
+			continue
+		}
+		dst, ok := ints[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid provisioned auto definition: %s is not a valid key", key)
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		*dst = n
+	}
+	if err := auto.Validate(); err != nil {
+		return nil, err
+	}
+	return auto, nil
+}
+
 // getProvisioinedWindow parses a provisioned window string into a ProvisionedWindow, or returns an error.
 func getProvisioinedWindow(window string) (fv1.ProvisionedWindow, error) {
 	var w fv1.ProvisionedWindow
@@ -238,13 +294,14 @@ func getToolConfig(input cli.Input, existing *fv1.ToolConfig) (*fv1.ToolConfig,
 // --async-* flags, merging onto existing (the function's current config, or nil on
 // create) so an `fn update` that sets only one field keeps the rest. It returns nil
 // when nothing is configured. An empty --async-on-success/--async-on-failure (or
-// their -topic variants) clears that destination. Field bounds and the destination
-// shape are validated server-side by the Function admission webhook, so the CLI
-// stays thin.
+// their -topic variants) clears that destination; an empty --async-priority resets
+// the default lane to normal. Field bounds and the destination shape are validated
+// server-side by the Function admission webhook, so the CLI stays thin.
 func getInvocationConfig(input cli.Input, existing *fv1.InvocationConfig) (*fv1.InvocationConfig, error) {
 	set := input.IsSet(flagkey.FnAsyncMaxAttempts) || input.IsSet(flagkey.FnAsyncMaxAge) ||
 		input.IsSet(flagkey.FnAsyncOnSuccess) || input.IsSet(flagkey.FnAsyncOnFailure) ||
-		input.IsSet(flagkey.FnAsyncOnSuccessTopic) || input.IsSet(flagkey.FnAsyncOnFailureTopic)
+		input.IsSet(flagkey.FnAsyncOnSuccessTopic) || input.IsSet(flagkey.FnAsyncOnFailureTopic) ||
+		input.IsSet(flagkey.FnAsyncPriority)
 	if !set {
 		return existing, nil
 	}
@@ -258,6 +315,9 @@ func getInvocationConfig(input cli.Input, existing *fv1.InvocationConfig) (*fv1.
 	if input.IsSet(flagkey.FnAsyncMaxAge) {
 		ic.MaxAge = &metav1.Duration{Duration: input.Duration(flagkey.FnAsyncMaxAge)}
 	}
+	if input.IsSet(flagkey.FnAsyncPriority) {
+		ic.Priority = fv1.InvocationPriority(input.String(flagkey.FnAsyncPriority))
+	}
 	var err error
 	if ic.OnSuccess, err = destinationFromFlags(input, flagkey.FnAsyncOnSuccess, flagkey.FnAsyncOnSuccessTopic, ic.OnSuccess); err != nil {
 		return nil, err
@@ -588,6 +648,7 @@ func (opts *CreateSubCommand) complete(input cli.Input) error {
 			RequestsPerPod:         requestsPerPod,
 			RetainPods:             retainPods,
 			OnceOnly:               fnOnceOnly,
+			ResourceClass:          input.String(flagkey.FnResourceClass),
 		},
 	}
 
@@ -758,8 +819,12 @@ func getExecutorType(input cli.Input) (executorType fv1.ExecutorType, err error)
 		executorType = fv1.ExecutorTypeNewdeploy
 	case string(fv1.ExecutorTypeContainer):
 		executorType = fv1.ExecutorTypeContainer
+	case string(fv1.ExecutorTypeJob):
+		executorType = fv1.ExecutorTypeJob
+	case string(fv1.ExecutorTypeWasm):
+		executorType = fv1.ExecutorTypeWasm
 	default:
-		err = fmt.Errorf("executor type must be one of '%v', '%v' or '%v'", fv1.ExecutorTypePoolmgr, fv1.ExecutorTypeNewdeploy, fv1.ExecutorTypeContainer)
+		err = fmt.Errorf("executor type must be one of '%v', '%v', '%v', '%v' or '%v'", fv1.ExecutorTypePoolmgr, fv1.ExecutorTypeNewdeploy, fv1.ExecutorTypeContainer, fv1.ExecutorTypeJob, fv1.ExecutorTypeWasm)
 	}
 	return executorType, err
 }
@@ -787,6 +852,22 @@ func getExecutionStrategy(fnExecutor fv1.ExecutorType, input cli.Input) (strateg
 			ExecutorType:          fv1.ExecutorTypePoolmgr,
 			SpecializationTimeout: specializationTimeout,
 		}
+	} else if fnExecutor == fv1.ExecutorTypeJob {
+		if input.IsSet(flagkey.RuntimeTargetcpu) || input.IsSet(flagkey.ReplicasMinscale) || input.IsSet(flagkey.ReplicasMaxscale) {
+			return nil, errors.New("a function of executor type \"job\" runs one Job per invocation and does not scale")
+		}
+		strategy = &fv1.ExecutionStrategy{
+			ExecutorType:          fv1.ExecutorTypeJob,
+			SpecializationTimeout: specializationTimeout,
+		}
+	} else if fnExecutor == fv1.ExecutorTypeWasm {
+		if input.IsSet(flagkey.RuntimeTargetcpu) || input.IsSet(flagkey.ReplicasMinscale) || input.IsSet(flagkey.ReplicasMaxscale) {
+			return nil, errors.New("a function of executor type \"wasm\" runs in its namespace's shared host pod and does not scale")
+		}
+		strategy = &fv1.ExecutionStrategy{
+			ExecutorType:          fv1.ExecutorTypeWasm,
+			SpecializationTimeout: specializationTimeout,
+		}
 	} else {
 
 		minScale := DEFAULT_MIN_SCALE
@@ -841,8 +922,12 @@ func updateExecutionStrategy(input cli.Input, existingExecutionStrategy *fv1.Exe
 			fnExecutor = fv1.ExecutorTypeNewdeploy
 		case string(fv1.ExecutorTypeContainer):
 			fnExecutor = fv1.ExecutorTypeContainer
+		case string(fv1.ExecutorTypeJob):
+			fnExecutor = fv1.ExecutorTypeJob
+		case string(fv1.ExecutorTypeWasm):
+			fnExecutor = fv1.ExecutorTypeWasm
 		default:
-			return nil, fmt.Errorf("executor type must be one of '%v', %v or '%v'", fv1.ExecutorTypePoolmgr, fv1.ExecutorTypeNewdeploy, fv1.ExecutorTypeContainer)
+			return nil, fmt.Errorf("executor type must be one of '%v', '%v', '%v', '%v' or '%v'", fv1.ExecutorTypePoolmgr, fv1.ExecutorTypeNewdeploy, fv1.ExecutorTypeContainer, fv1.ExecutorTypeJob, fv1.ExecutorTypeWasm)
 		}
 	}
 
@@ -871,6 +956,28 @@ func updateExecutionStrategy(input cli.Input, existingExecutionStrategy *fv1.Exe
 			ExecutorType:          fv1.ExecutorTypePoolmgr,
 			SpecializationTimeout: specializationTimeout,
 		}
+	} else if fnExecutor == fv1.ExecutorTypeJob {
+		if input.IsSet(flagkey.RuntimeTargetcpu) || input.IsSet(flagkey.ReplicasMinscale) || input.IsSet(flagkey.ReplicasMaxscale) {
+			return nil, errors.New("a function of executor type \"job\" runs one Job per invocation and does not scale")
+		}
+		strategy = &fv1.ExecutionStrategy{
+			ExecutorType:          fv1.ExecutorTypeJob,
+			SpecializationTimeout: specializationTimeout,
+		}
+		if oldExecutor == fv1.ExecutorTypeJob {
+			strategy.Job = existingExecutionStrategy.Job
+		}
+	} else if fnExecutor == fv1.ExecutorTypeWasm {
+		if input.IsSet(flagkey.RuntimeTargetcpu) || input.IsSet(flagkey.ReplicasMinscale) || input.IsSet(flagkey.ReplicasMaxscale) {
+			return nil, errors.New("a function of executor type \"wasm\" runs in its namespace's shared host pod and does not scale")
+		}
+		strategy = &fv1.ExecutionStrategy{
+			ExecutorType:          fv1.ExecutorTypeWasm,
+			SpecializationTimeout: specializationTimeout,
+		}
+		if oldExecutor == fv1.ExecutorTypeWasm {
+			strategy.Wasm = existingExecutionStrategy.Wasm
+		}
 	} else {
 		minScale := existingExecutionStrategy.MinScale
 		maxScale := existingExecutionStrategy.MaxScale
@@ -170,6 +170,9 @@ func describeFunctionTo(out io.Writer, fn *fv1.Function, pkg *fv1.Package, pods
 	fmt.Fprintf(w, "Package:\t%s\n", valueOr(fn.Spec.Package.PackageRef.Name))
 	fmt.Fprintf(w, "Invocable:\t%s\n", invocability(fn, active))
 	fmt.Fprintf(w, "Created:\t%s\n", util.AgeOf(fn.CreationTimestamp))
+	if fn.Spec.Invocation != nil && fn.Spec.Invocation.CircuitBreaker != nil {
+		fmt.Fprintf(w, "Circuit Breaker:\t%s\n", circuitBreakerLine(fn.Spec.Invocation.CircuitBreaker))
+	}
 	if line := kvLine(fn.Labels); line != "" {
 		fmt.Fprintf(w, "Labels:\t%s\n", line)
 	}
@@ -267,6 +270,19 @@ func versioningModeLine(cfg *fv1.VersioningConfig) string {
 	return fmt.Sprintf("mode=%s retain=%d", mode, retain)
 }
 
+// circuitBreakerLine summarizes the effective circuit breaker config. The
+// breaker's live state is the router-written CircuitClosed condition, printed
+// with the other conditions.
+func circuitBreakerLine(cfg *fv1.CircuitBreakerConfig) string {
+	e := cfg.Effective()
+	errorRate := "off"
+	if e.ErrorRatePercent > 0 {
+		errorRate = fmt.Sprintf("%d%% of >=%d in %ds", e.ErrorRatePercent, e.MinRequests, e.IntervalSeconds)
+	}
+	return fmt.Sprintf("consecutive=%d errorRate=%s ejection=%ds halfOpen=%d maxEjection=%d%%",
+		e.ConsecutiveFailures, errorRate, e.EjectionSeconds, e.HalfOpenRequests, e.MaxEjectionPercent)
+}
+
 // describeAliasesTableTo renders the NAME/TARGET/WEIGHT/ENVDRIFT mini table
 // shared by the VERSIONING section (all of a function's aliases) and the
 // --version SNAPSHOT inspector's ALIASED-BY table (aliases already filtered
@@ -120,6 +120,27 @@ func TestFunctionDescribe(t *testing.T) {
 		assert.Contains(t, out, "\nVERSIONING:\n  Versioning: disabled\n", "unversioned function's VERSIONING section is a single disabled line")
 	})
 
+	t.Run("a circuit breaker renders its effective config and condition", func(t *testing.T) {
+		fn := describeFunction()
+		fn.Spec.Invocation = &fv1.InvocationConfig{CircuitBreaker: &fv1.CircuitBreakerConfig{ErrorRatePercent: 50}}
+		fn.Status.Conditions = append(fn.Status.Conditions, metav1.Condition{
+			Type: fv1.FunctionConditionCircuitClosed, Status: metav1.ConditionFalse,
+			Reason: fv1.FunctionReasonEndpointsEjected, Message: "1 of 3 endpoints ejected (0 half-open)",
+		})
+		setDescribeClients(t, []runtime.Object{fn})
+
+		out := captureStdout(t, func() error { return Describe(describeInput("hello", "default")) })
+
+		assertFieldMatches(t, out, "Circuit Breaker:", "consecutive=5 errorRate=50% of >=20 in 30s ejection=30s halfOpen=1 maxEjection=50%", "defaults filled in")
+		assert.Contains(t, out, fv1.FunctionReasonEndpointsEjected, "breaker condition")
+	})
+
+	t.Run("no circuit breaker line without the config", func(t *testing.T) {
+		setDescribeClients(t, []runtime.Object{describeFunction()})
+		out := captureStdout(t, func() error { return Describe(describeInput("hello", "default")) })
+		assert.NotContains(t, out, "Circuit Breaker:")
+	})
+
 	t.Run("a not-Ready function reports not invocable", func(t *testing.T) {
 		fn := describeFunction()
 		fn.Status.Conditions = []metav1.Condition{
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
@@ -39,10 +40,12 @@ const (
 	dlqAPIShow    = "/v1/async/dlq/show"
 	dlqAPIRedrive = "/v1/async/dlq/redrive"
 	dlqAPIPurge   = "/v1/async/dlq/purge"
+	dlqAPIDiscard = "/v1/async/dlq/discard"
+	dlqAPIExport  = "/v1/async/dlq/export"
 
 	// dlqPageSize is the per-request page the CLI fetches while paging the list API
-	// (matches the router's max limit), so list/redrive --all see the whole DLQ,
-	// not just the first page.
+	// (matches the router's max limit), so list sees the whole DLQ, not just the
+	// first page.
 	dlqPageSize = 1000
 )
 
@@ -68,21 +71,44 @@ type dlqShowResp struct {
 	Envelope json.RawMessage `json:"envelope"`
 }
 
+// dlqSelection picks what redrive/discard act on: explicit ids, or All for every
+// dead letter matching the query-string filter (resolved server-side).
+type dlqSelection struct {
+	IDs []string `json:"ids,omitempty"`
+	All bool     `json:"all,omitempty"`
+}
+
 type dlqRedriveReq struct {
-	IDs []string `json:"ids"`
+	dlqSelection
+	Target *dlqRedriveTarget `json:"target,omitempty"`
+}
+
+type dlqRedriveTarget struct {
+	Function string `json:"function"`
+	Alias    string `json:"alias,omitempty"`
+	Version  string `json:"version,omitempty"`
 }
 
 type dlqMutateResp struct {
 	Count int64 `json:"count"`
+	// Skipped counts retarget candidates the router left dead-lettered (e.g. the
+	// target function does not exist in that namespace).
+	Skipped int64 `json:"skipped,omitempty"`
+	// Truncated reports the router stopped at its per-request scan cap; the CLI
+	// repeats the request until a pass makes no progress.
+	Truncated bool `json:"truncated,omitempty"`
 }
 
+// dlqFilterFlags are the filter flags list, export, redrive --all and purge share.
+var dlqFilterFlags = []flag.Flag{flag.Namespace, flag.DlqFunction, flag.DlqReason, flag.DlqError, flag.DlqSince, flag.DlqUntil}
+
 // DLQCommands builds the `fission function dlq` sub-group.
 func DLQCommands() *cobra.Command {
 	listCmd := wrapper.SubCommand(&cobra.Command{
 		Use:   "list",
 		Short: "List dead-lettered async invocations",
 	}, DLQList, flag.FlagSet{
-		Optional: []flag.Flag{flag.Namespace, flag.DlqLimit, flag.Output, flag.DlqQueue},
+		Optional: append([]flag.Flag{flag.DlqLimit, flag.Output, flag.DlqQueue}, dlqFilterFlags...),
 	})
 	showCmd := wrapper.SubCommand(&cobra.Command{
 		Use:   "show",
@@ -94,21 +120,30 @@ func DLQCommands() *cobra.Command {
 	redriveCmd := wrapper.SubCommand(&cobra.Command{
 		Use:   "redrive",
 		Short: "Re-enqueue dead-lettered async invocations for another delivery",
+		Long: "Re-enqueue dead-lettered async invocations for another delivery: one --id, or with --all every\n" +
+			"dead letter matching the filter flags. --to-function (with --to-alias or --to-version) sends them\n" +
+			"to a different function instead of the one that failed.",
 	}, DLQRedrive, flag.FlagSet{
-		Optional: []flag.Flag{flag.DlqID, flag.DlqAll, flag.DlqQueue},
+		Optional: append([]flag.Flag{flag.DlqID, flag.DlqAll, flag.DlqQueue, flag.DlqToFunction, flag.DlqToAlias, flag.DlqToVersion}, dlqFilterFlags...),
 	})
 	purgeCmd := wrapper.SubCommand(&cobra.Command{
 		Use:   "purge",
-		Short: "Permanently delete every dead-lettered async invocation",
+		Short: "Permanently delete dead-lettered async invocations (every one, or those matching the filter flags)",
 	}, DLQPurge, flag.FlagSet{
-		Optional: []flag.Flag{flag.DlqQueue},
+		Optional: append([]flag.Flag{flag.DlqQueue}, dlqFilterFlags...),
+	})
+	exportCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "export",
+		Short: "Export dead-lettered async invocations, with their envelopes, as NDJSON",
+	}, DLQExport, flag.FlagSet{
+		Optional: append([]flag.Flag{flag.DlqQueue, flag.DlqFile}, dlqFilterFlags...),
 	})
 
 	command := &cobra.Command{
 		Use:   "dlq",
 		Short: "Inspect and manage the async invocation dead-letter queue",
 	}
-	command.AddCommand(listCmd, showCmd, redriveCmd, purgeCmd)
+	command.AddCommand(listCmd, showCmd, redriveCmd, purgeCmd, exportCmd)
 	return command
 }
 
@@ -120,6 +155,25 @@ func DLQList(input cli.Input) error    { return (&dlqSubCommand{}).list(input) }
 func DLQShow(input cli.Input) error    { return (&dlqSubCommand{}).show(input) }
 func DLQRedrive(input cli.Input) error { return (&dlqSubCommand{}).redrive(input) }
 func DLQPurge(input cli.Input) error   { return (&dlqSubCommand{}).purge(input) }
+func DLQExport(input cli.Input) error  { return (&dlqSubCommand{}).export(input) }
+
+// dlqFilterQuery maps the filter flags onto the router's query parameters.
+func dlqFilterQuery(input cli.Input) url.Values {
+	q := url.Values{}
+	for param, key := range map[string]string{
+		"namespace": flagkey.Namespace,
+		"function":  flagkey.DlqFunction,
+		"reason":    flagkey.DlqReason,
+		"error":     flagkey.DlqError,
+		"since":     flagkey.DlqSince,
+		"until":     flagkey.DlqUntil,
+	} {
+		if v := input.String(key); v != "" {
+			q.Set(param, v)
+		}
+	}
+	return q
+}
 
 func (opts *dlqSubCommand) list(input cli.Input) error {
 	format, err := util.ParseOutputFormat(input.String(flagkey.Output))
@@ -127,7 +181,7 @@ func (opts *dlqSubCommand) list(input cli.Input) error {
 		return err
 	}
 	limit := input.Int(flagkey.DlqLimit)
-	msgs, more, err := opts.fetchDeadLetters(input, input.String(flagkey.Namespace), limit)
+	msgs, more, err := opts.fetchDeadLetters(input, dlqFilterQuery(input), limit)
 	if err != nil {
 		return err
 	}
@@ -149,10 +203,10 @@ func (opts *dlqSubCommand) list(input cli.Input) error {
 }
 
 // fetchDeadLetters pages the DLQ list API, accumulating up to max messages (max <= 0
-// means every one), filtered to namespace when set. It follows the API's nextToken
+// means every one), narrowed by the filter query. It follows the API's nextToken
 // so a large DLQ is fully traversed, not just its first page. The bool reports
 // whether more messages remain beyond what was returned (only when max capped it).
-func (opts *dlqSubCommand) fetchDeadLetters(input cli.Input, namespace string, max int) ([]dlqMessage, bool, error) {
+func (opts *dlqSubCommand) fetchDeadLetters(input cli.Input, filter url.Values, max int) ([]dlqMessage, bool, error) {
 	pageSize := dlqPageSize
 	if max > 0 && max < pageSize {
 		pageSize = max
@@ -161,9 +215,7 @@ func (opts *dlqSubCommand) fetchDeadLetters(input cli.Input, namespace string, m
 	token := ""
 	for {
 		q := url.Values{"limit": {strconv.Itoa(pageSize)}}
-		if namespace != "" {
-			q.Set("namespace", namespace)
-		}
+		maps.Copy(q, filter)
 		if token != "" {
 			q.Set("token", token)
 		}
@@ -198,47 +250,81 @@ func (opts *dlqSubCommand) show(input cli.Input) error {
 }
 
 func (opts *dlqSubCommand) redrive(input cli.Input) error {
-	ids, err := opts.redriveIDs(input)
+	sel, filter, err := dlqSelect(input)
 	if err != nil {
 		return err
 	}
-	if len(ids) == 0 {
-		return errors.New("nothing to redrive")
+	req := dlqRedriveReq{dlqSelection: sel}
+	if fn := input.String(flagkey.DlqToFunction); fn != "" {
+		req.Target = &dlqRedriveTarget{Function: fn, Alias: input.String(flagkey.DlqToAlias), Version: input.String(flagkey.DlqToVersion)}
+		if req.Target.Alias != "" && req.Target.Version != "" {
+			return errors.New("--to-alias and --to-version are mutually exclusive")
+		}
+	} else if input.String(flagkey.DlqToAlias) != "" || input.String(flagkey.DlqToVersion) != "" {
+		return errors.New("--to-alias and --to-version require --to-function")
 	}
-	var resp dlqMutateResp
-	if err := opts.call(input, http.MethodPost, dlqAPIRedrive, nil, dlqRedriveReq{IDs: ids}, &resp); err != nil {
+	resp, err := opts.mutate(input, dlqAPIRedrive, filter, req)
+	if err != nil {
 		return err
 	}
 	fmt.Printf("redrove %d dead-lettered invocation(s)\n", resp.Count)
+	if resp.Skipped > 0 {
+		fmt.Printf("skipped %d that could not be sent to the target; they remain dead-lettered\n", resp.Skipped)
+	}
 	return nil
 }
 
-// redriveIDs resolves the ids to redrive: a single --id, or every dead-lettered id
-// under --all (paged from the list API). The two are mutually exclusive.
-func (opts *dlqSubCommand) redriveIDs(input cli.Input) ([]string, error) {
+// dlqSelect resolves the selection: a single --id, or --all with the filter
+// flags (matched by the router). The two are mutually exclusive, and filters
+// only narrow --all.
+func dlqSelect(input cli.Input) (dlqSelection, url.Values, error) {
 	id := input.String(flagkey.DlqID)
 	all := input.Bool(flagkey.DlqAll)
+	filter := dlqFilterQuery(input)
 	switch {
 	case id != "" && all:
-		return nil, errors.New("--id and --all are mutually exclusive")
+		return dlqSelection{}, nil, errors.New("--id and --all are mutually exclusive")
+	case id != "" && len(filter) > 0:
+		return dlqSelection{}, nil, errors.New("filter flags apply only with --all")
 	case id != "":
-		return []string{id}, nil
+		return dlqSelection{IDs: []string{id}}, nil, nil
 	case all:
-		msgs, _, err := opts.fetchDeadLetters(input, "", 0) // 0 = every dead letter, all pages
-		if err != nil {
-			return nil, err
+		return dlqSelection{All: true}, filter, nil
+	default:
+		return dlqSelection{}, nil, errors.New("one of --id or --all is required")
+	}
+}
+
+// mutate posts a redrive/discard request, repeating an --all request while the
+// router reports it stopped at its scan cap and the last pass made progress
+// (matches that cannot be acted on stay put, so a pass of only those ends it).
+func (opts *dlqSubCommand) mutate(input cli.Input, path string, filter url.Values, req any) (dlqMutateResp, error) {
+	var total dlqMutateResp
+	for {
+		var resp dlqMutateResp
+		if err := opts.call(input, http.MethodPost, path, filter, req, &resp); err != nil {
+			return total, err
 		}
-		ids := make([]string, 0, len(msgs))
-		for _, m := range msgs {
-			ids = append(ids, m.ID)
+		total.Count += resp.Count
+		total.Skipped += resp.Skipped
+		if !resp.Truncated || resp.Count == 0 {
+			total.Truncated = resp.Truncated
+			return total, nil
 		}
-		return ids, nil
-	default:
-		return nil, errors.New("one of --id or --all is required")
 	}
 }
 
+// purge deletes every dead letter, or with filter flags only the matches (via
+// the router's discard API — purge itself is unconditional and refuses filters).
 func (opts *dlqSubCommand) purge(input cli.Input) error {
+	if filter := dlqFilterQuery(input); len(filter) > 0 {
+		resp, err := opts.mutate(input, dlqAPIDiscard, filter, dlqSelection{All: true})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("purged %d dead-lettered invocation(s)\n", resp.Count)
+		return nil
+	}
 	var resp dlqMutateResp
 	if err := opts.call(input, http.MethodPost, dlqAPIPurge, nil, nil, &resp); err != nil {
 		return err
@@ -247,23 +333,62 @@ func (opts *dlqSubCommand) purge(input cli.Input) error {
 	return nil
 }
 
+// export streams the router's NDJSON export — one show record per line — to
+// stdout or --file, without buffering the whole dead set.
+func (opts *dlqSubCommand) export(input cli.Input) error {
+	var out io.Writer = os.Stdout
+	if path := input.String(flagkey.DlqFile); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+	body, err := opts.do(input, http.MethodGet, dlqAPIExport, dlqFilterQuery(input), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+	if _, err := io.Copy(out, body); err != nil {
+		return fmt.Errorf("reading router DLQ export: %w", err)
+	}
+	return nil
+}
+
 // call performs one DLQ API request against the router INTERNAL listener,
 // HMAC-signing it with the ServiceRouterInternal key (from FISSION_INTERNAL_AUTH_SECRET,
 // empty → pass-through) the same way `test --async` does, and decoding a JSON
 // response into out (nil to ignore the body). The endpoints are on the internal
 // listener precisely so they are never an unauthenticated public surface.
 func (opts *dlqSubCommand) call(input cli.Input, method, path string, query url.Values, reqBody, out any) error {
+	body, err := opts.do(input, method, path, query, reqBody)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+	if out != nil {
+		if err := json.NewDecoder(body).Decode(out); err != nil {
+			return fmt.Errorf("decoding router DLQ response: %w", err)
+		}
+	}
+	return nil
+}
+
+// do sends the request and returns the body of a 200 response for the caller to
+// read and close; any other status is mapped to an error.
+func (opts *dlqSubCommand) do(input cli.Input, method, path string, query url.Values, reqBody any) (io.ReadCloser, error) {
 	// --queue targets an RFC-0027 broker egress DLQ instead of the async
 	// invocation queue; threaded here so every subcommand honors it.
 	if qn := input.String(flagkey.DlqQueue); qn != "" {
-		if query == nil {
-			query = url.Values{}
-		}
-		query.Set("queue", qn)
+		q := url.Values{}
+		maps.Copy(q, query)
+		q.Set("queue", qn)
+		query = q
 	}
 	internalURL, err := util.GetRouterInternalURL(input.Context(), opts.Client())
 	if err != nil {
-		return fmt.Errorf("connecting to the Fission router internal listener: %w", err)
+		return nil, fmt.Errorf("connecting to the Fission router internal listener: %w", err)
 	}
 	u := *internalURL
 	u.Path = path
@@ -274,13 +399,13 @@ func (opts *dlqSubCommand) call(input cli.Input, method, path string, query url.
 	if reqBody != nil {
 		b, err := json.Marshal(reqBody)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		body = bytes.NewReader(b)
 	}
 	req, err := http.NewRequestWithContext(input.Context(), method, u.String(), body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
@@ -288,27 +413,23 @@ func (opts *dlqSubCommand) call(input cli.Input, method, path string, query url.
 
 	transport := http.DefaultTransport
 	if secret := os.Getenv("FISSION_INTERNAL_AUTH_SECRET"); secret != "" {
-		transport = hmacauth.NewServiceSigningTransport([]byte(secret), hmacauth.ServiceRouterInternal, transport, "/v1/async/dlq/")
+		transport = hmacauth.NewServiceSigningTransport([]byte(secret), hmacauth.ServiceRouterInternal, transport, "/v1/async/")
 	}
 	resp, err := (&http.Client{Transport: transport}).Do(req)
 	if err != nil {
-		return fmt.Errorf("calling the router DLQ API: %w", err)
+		return nil, fmt.Errorf("calling the router DLQ API: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp.Body, nil
 	}
 	defer func() { _ = resp.Body.Close() }()
-
-	switch {
-	case resp.StatusCode == http.StatusNotImplemented:
-		return errors.New("async invocation is not enabled on this cluster")
-	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
-		return fmt.Errorf("router DLQ API rejected the request (%s); set FISSION_INTERNAL_AUTH_SECRET when authentication is enabled", resp.Status)
-	case resp.StatusCode != http.StatusOK:
+	switch resp.StatusCode {
+	case http.StatusNotImplemented:
+		return nil, errors.New("async invocation is not enabled on this cluster")
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("router DLQ API rejected the request (%s); set FISSION_INTERNAL_AUTH_SECRET when authentication is enabled", resp.Status)
+	default:
 		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return fmt.Errorf("router DLQ API returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+		return nil, fmt.Errorf("router DLQ API returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
 	}
-	if out != nil {
-		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
-			return fmt.Errorf("decoding router DLQ response: %w", err)
-		}
-	}
-	return nil
 }
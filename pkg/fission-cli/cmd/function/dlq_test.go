@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -96,7 +98,7 @@ func TestDLQCLIListPagesAllResults(t *testing.T) {
 		return http.StatusOK, `{"messages":[{"id":"c"}]}`
 	})
 	// max=0 (no --limit) → fetch every page.
-	msgs, more, err := (&dlqSubCommand{}).fetchDeadLetters(fakeDLQInput{}, "", 0)
+	msgs, more, err := (&dlqSubCommand{}).fetchDeadLetters(fakeDLQInput{}, nil, 0)
 	require.NoError(t, err)
 	assert.False(t, more)
 	assert.Len(t, msgs, 3, "both pages accumulated")
@@ -132,22 +134,52 @@ func TestDLQCLIRedriveByID(t *testing.T) {
 	assert.Equal(t, []string{"asyncinv/3"}, body.IDs)
 }
 
+// TestDLQCLIRedriveAll: --all hands the selection to the router (filters on the
+// query string) instead of listing ids client-side, and repeats while the router
+// reports it stopped at its scan cap.
 func TestDLQCLIRedriveAll(t *testing.T) {
-	got := mockRouter(t, func(r *http.Request) (int, string) {
-		if r.Method == http.MethodGet {
-			return http.StatusOK, `{"messages":[{"id":"a"},{"id":"b"}]}`
+	calls := 0
+	got := mockRouter(t, func(*http.Request) (int, string) {
+		calls++
+		if calls == 1 {
+			return http.StatusOK, `{"count":10000,"truncated":true}`
 		}
 		return http.StatusOK, `{"count":2}`
 	})
-	in := fakeDLQInput{b: map[string]bool{flagkey.DlqAll: true}}
+	in := fakeDLQInput{
+		s: map[string]string{flagkey.DlqFunction: "fn", flagkey.DlqSince: "2h"},
+		b: map[string]bool{flagkey.DlqAll: true},
+	}
+	require.NoError(t, (&dlqSubCommand{}).redrive(in))
+
+	require.Len(t, *got, 2, "a truncated pass is repeated")
+	for _, req := range *got {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, dlqAPIRedrive, req.Path)
+		assert.Contains(t, req.Query, "function=fn")
+		assert.Contains(t, req.Query, "since=2h")
+		var body dlqRedriveReq
+		require.NoError(t, json.Unmarshal([]byte(req.Body), &body))
+		assert.True(t, body.All)
+		assert.Empty(t, body.IDs)
+		assert.Nil(t, body.Target)
+	}
+}
+
+func TestDLQCLIRedriveTarget(t *testing.T) {
+	got := mockRouter(t, func(*http.Request) (int, string) { return http.StatusOK, `{"count":1,"skipped":1}` })
+	in := fakeDLQInput{
+		s: map[string]string{flagkey.DlqToFunction: "fixed", flagkey.DlqToAlias: "live", flagkey.DlqReason: "http_4xx"},
+		b: map[string]bool{flagkey.DlqAll: true},
+	}
 	require.NoError(t, (&dlqSubCommand{}).redrive(in))
 
-	require.Len(t, *got, 2, "redrive --all lists then redrives")
-	assert.Equal(t, http.MethodGet, (*got)[0].Method)
-	assert.Equal(t, http.MethodPost, (*got)[1].Method)
+	require.Len(t, *got, 1)
 	var body dlqRedriveReq
-	require.NoError(t, json.Unmarshal([]byte((*got)[1].Body), &body))
-	assert.Equal(t, []string{"a", "b"}, body.IDs)
+	require.NoError(t, json.Unmarshal([]byte((*got)[0].Body), &body))
+	require.NotNil(t, body.Target)
+	assert.Equal(t, dlqRedriveTarget{Function: "fixed", Alias: "live"}, *body.Target)
+	assert.Contains(t, (*got)[0].Query, "reason=http_4xx")
 }
 
 func TestDLQCLIRedriveArgErrors(t *testing.T) {
@@ -160,6 +192,16 @@ func TestDLQCLIRedriveArgErrors(t *testing.T) {
 		s: map[string]string{flagkey.DlqID: "x"}, b: map[string]bool{flagkey.DlqAll: true},
 	})
 	assert.ErrorContains(t, err, "mutually exclusive")
+	// Filters narrow --all only.
+	err = (&dlqSubCommand{}).redrive(fakeDLQInput{s: map[string]string{flagkey.DlqID: "x", flagkey.DlqReason: "max_age"}})
+	assert.ErrorContains(t, err, "only with --all")
+	// Target flags.
+	err = (&dlqSubCommand{}).redrive(fakeDLQInput{s: map[string]string{flagkey.DlqID: "x", flagkey.DlqToAlias: "live"}})
+	assert.ErrorContains(t, err, "require --to-function")
+	err = (&dlqSubCommand{}).redrive(fakeDLQInput{s: map[string]string{
+		flagkey.DlqID: "x", flagkey.DlqToFunction: "f", flagkey.DlqToAlias: "live", flagkey.DlqToVersion: "3",
+	}})
+	assert.ErrorContains(t, err, "mutually exclusive")
 }
 
 func TestDLQCLIPurge(t *testing.T) {
@@ -170,6 +212,37 @@ func TestDLQCLIPurge(t *testing.T) {
 	assert.Equal(t, dlqAPIPurge, (*got)[0].Path)
 }
 
+// TestDLQCLIPurgeFiltered: a filtered purge goes to discard with all, never to
+// purge — an older router would ignore the filter there and delete everything.
+func TestDLQCLIPurgeFiltered(t *testing.T) {
+	got := mockRouter(t, func(*http.Request) (int, string) { return http.StatusOK, `{"count":3}` })
+	in := fakeDLQInput{s: map[string]string{flagkey.Namespace: "ns", flagkey.DlqError: "timeout"}}
+	require.NoError(t, (&dlqSubCommand{}).purge(in))
+
+	require.Len(t, *got, 1)
+	req := (*got)[0]
+	assert.Equal(t, dlqAPIDiscard, req.Path)
+	assert.Contains(t, req.Query, "namespace=ns")
+	assert.Contains(t, req.Query, "error=timeout")
+	assert.JSONEq(t, `{"all":true}`, req.Body)
+}
+
+func TestDLQCLIExport(t *testing.T) {
+	const ndjson = `{"id":"asyncinv/1"}` + "\n" + `{"id":"asyncinv/2"}` + "\n"
+	got := mockRouter(t, func(*http.Request) (int, string) { return http.StatusOK, ndjson })
+	path := filepath.Join(t.TempDir(), "dlq.ndjson")
+	in := fakeDLQInput{s: map[string]string{flagkey.DlqFile: path, flagkey.DlqQueue: "asyncinv-low", flagkey.DlqFunction: "fn"}}
+	require.NoError(t, (&dlqSubCommand{}).export(in))
+
+	require.Len(t, *got, 1)
+	assert.Equal(t, dlqAPIExport, (*got)[0].Path)
+	assert.Contains(t, (*got)[0].Query, "queue=asyncinv-low")
+	assert.Contains(t, (*got)[0].Query, "function=fn")
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, ndjson, string(written))
+}
+
 func TestDLQCLIDisabled501(t *testing.T) {
 	mockRouter(t, func(*http.Request) (int, string) {
 		return http.StatusNotImplemented, "async invocation is not enabled on this cluster\n"
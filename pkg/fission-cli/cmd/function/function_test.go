@@ -107,6 +107,50 @@ func TestGetInvokeStrategy(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name:                   "executor type set to wasm",
+			testArgs:               map[string]any{flagkey.FnExecutorType: string(fv1.ExecutorTypeWasm)},
+			existingInvokeStrategy: nil,
+			expectedResult: &fv1.InvokeStrategy{
+				StrategyType: fv1.StrategyTypeExecution,
+				ExecutionStrategy: fv1.ExecutionStrategy{
+					ExecutorType:          fv1.ExecutorTypeWasm,
+					SpecializationTimeout: fv1.DefaultSpecializationTimeOut,
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "wasm does not scale",
+			testArgs: map[string]any{
+				flagkey.FnExecutorType:   string(fv1.ExecutorTypeWasm),
+				flagkey.ReplicasMaxscale: 3,
+			},
+			existingInvokeStrategy: nil,
+			expectedResult:         nil,
+			expectError:            true,
+		},
+		{
+			name:     "update of a wasm function keeps its wasm config",
+			testArgs: map[string]any{flagkey.FnSpecializationTimeout: 200},
+			existingInvokeStrategy: &fv1.InvokeStrategy{
+				StrategyType: fv1.StrategyTypeExecution,
+				ExecutionStrategy: fv1.ExecutionStrategy{
+					ExecutorType:          fv1.ExecutorTypeWasm,
+					SpecializationTimeout: fv1.DefaultSpecializationTimeOut,
+					Wasm:                  &fv1.WasmConfig{MaxExecutionTime: "5s"},
+				},
+			},
+			expectedResult: &fv1.InvokeStrategy{
+				StrategyType: fv1.StrategyTypeExecution,
+				ExecutionStrategy: fv1.ExecutionStrategy{
+					ExecutorType:          fv1.ExecutorTypeWasm,
+					SpecializationTimeout: 200,
+					Wasm:                  &fv1.WasmConfig{MaxExecutionTime: "5s"},
+				},
+			},
+			expectError: false,
+		},
 		{
 			name: "minscale < maxscale",
 			testArgs: map[string]any{
@@ -425,6 +469,41 @@ func TestGetProvisionedConcurrencyConfig(t *testing.T) {
 			errExpected: true,
 			errSubStrs:  []string{"duplicate provisioned window: w1"},
 		},
+		{
+			name:     "auto with defaults",
+			testArgs: map[string]any{flagkey.FnProvisionedConcurrency: 2, flagkey.FnProvisionedAuto: ""},
+			expected: &fv1.ProvisionedConcurrencyConfig{Target: 2, Auto: &fv1.ProvisionedAutoConfig{}},
+		},
+		{
+			name:     "auto",
+			testArgs: map[string]any{flagkey.FnProvisionedConcurrency: 2, flagkey.FnProvisionedAuto: "percentile=90;window=30m;headroom=20;min=1;max=10;seasonality=7"},
+			expected: &fv1.ProvisionedConcurrencyConfig{Target: 2, Auto: &fv1.ProvisionedAutoConfig{
+				Percentile: 90, Window: "30m", HeadroomPercent: 20, Min: 1, Max: 10, SeasonalityDays: 7,
+			}},
+		},
+		{
+			name:     "auto off",
+			testArgs: map[string]any{flagkey.FnProvisionedConcurrency: 2, flagkey.FnProvisionedAuto: "off"},
+			expected: &fv1.ProvisionedConcurrencyConfig{Target: 2},
+		},
+		{
+			name:        "auto, no concurrency",
+			testArgs:    map[string]any{flagkey.FnProvisionedAuto: "min=1"},
+			errExpected: true,
+			errSubStrs:  []string{"requires --provisioned-concurrency"},
+		},
+		{
+			name:        "auto, unknown key",
+			testArgs:    map[string]any{flagkey.FnProvisionedConcurrency: 2, flagkey.FnProvisionedAuto: "p=95"},
+			errExpected: true,
+			errSubStrs:  []string{"p is not a valid key"},
+		},
+		{
+			name:        "auto, out of range",
+			testArgs:    map[string]any{flagkey.FnProvisionedConcurrency: 2, flagkey.FnProvisionedAuto: "window=2h"},
+			errExpected: true,
+			errSubStrs:  []string{"must be between 1m0s and 1h0m0s"},
+		},
 	}
 
 	for _, c := range cases {
@@ -99,6 +99,22 @@ func TestGetInvocationConfig(t *testing.T) {
 		assert.Nil(t, ic.OnFailure, "empty --async-on-failure clears the destination")
 	})
 
+	t.Run("priority from flag, empty resets to normal", func(t *testing.T) {
+		in := fakeInvInput{
+			set: map[string]bool{flagkey.FnAsyncPriority: true},
+			s:   map[string]string{flagkey.FnAsyncPriority: "low"},
+		}
+		ic, err := getInvocationConfig(in, nil)
+		require.NoError(t, err)
+		require.NotNil(t, ic)
+		assert.Equal(t, fv1.InvocationPriorityLow, ic.Priority)
+
+		in.s[flagkey.FnAsyncPriority] = ""
+		ic, err = getInvocationConfig(in, ic)
+		require.NoError(t, err)
+		assert.Empty(t, ic.Priority)
+	})
+
 	t.Run("topic destination from flags (RFC-0027)", func(t *testing.T) {
 		in := fakeInvInput{
 			set: map[string]bool{flagkey.FnAsyncOnSuccessTopic: true},
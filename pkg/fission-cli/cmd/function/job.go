@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package function
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/fission/fission/pkg/fission-cli/cliwrapper/cli"
+	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
+)
+
+// jobAPIStatus is the router's job invocation status endpoint, on the
+// internal listener like the DLQ admin API.
+const jobAPIStatus = "/v1/async/jobs/status"
+
+// JobStatus prints the state of one invocation of a function of executor
+// type job: Running until its Job finishes, then its response and output.
+func JobStatus(input cli.Input) error {
+	opts := &dlqSubCommand{}
+	_, namespace, err := opts.GetResourceNamespace(input)
+	if err != nil {
+		return fmt.Errorf("error getting namespace: %w", err)
+	}
+	q := url.Values{}
+	q.Set("namespace", namespace)
+	q.Set("function", input.String(flagkey.FnName))
+	q.Set("id", input.String(flagkey.FnJobID))
+	var status json.RawMessage
+	if err := opts.call(input, http.MethodGet, jobAPIStatus, q, nil, &status); err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -55,14 +55,14 @@ func TestGetStreamingConfig(t *testing.T) {
 		t.Parallel()
 		in := fakeStreamInput{
 			b: map[string]bool{flagkey.FnStreaming: true},
-			s: map[string]string{flagkey.FnStreamingProtocol: "grpc"},
+			s: map[string]string{flagkey.FnStreamingProtocol: "quic"},
 			i: map[string]int{flagkey.FnStreamingIdleTimeout: 60, flagkey.FnStreamingMaxDuration: 0},
 		}
 		sc := getStreamingConfig(in)
 		require.NotNil(t, sc)
 		// The CLI does not validate; it passes the value through so StreamingConfig.Validate
 		// (CRD webhook / server-side) is the single rejection point.
-		assert.Equal(t, fv1.StreamingProtocol("grpc"), sc.Protocol)
+		assert.Equal(t, fv1.StreamingProtocol("quic"), sc.Protocol)
 	})
 
 	t.Run("streaming on with overrides", func(t *testing.T) {
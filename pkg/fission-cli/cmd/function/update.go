@@ -166,7 +166,7 @@ func (opts *UpdateSubCommand) complete(input cli.Input) error {
 		function.Spec.RetainPods = input.Int(flagkey.FnRetainPods)
 	}
 
-	if input.IsSet(flagkey.FnProvisionedConcurrency) || input.IsSet(flagkey.FnProvisionedSchedule) {
+	if input.IsSet(flagkey.FnProvisionedConcurrency) || input.IsSet(flagkey.FnProvisionedSchedule) || input.IsSet(flagkey.FnProvisionedAuto) {
 		existing := function.Spec.ProvisionedConcurrency
 		provisionedConcurrency, err := getProvisionedConcurrencyConfig(input)
 		if err != nil {
@@ -177,12 +177,20 @@ func (opts *UpdateSubCommand) complete(input cli.Input) error {
 		if provisionedConcurrency != nil && existing != nil && !input.IsSet(flagkey.FnProvisionedSchedule) {
 			provisionedConcurrency.Windows = existing.Windows
 		}
+		// Likewise auto mode, which only --provisioned-auto=off turns off.
+		if provisionedConcurrency != nil && existing != nil && !input.IsSet(flagkey.FnProvisionedAuto) {
+			provisionedConcurrency.Auto = existing.Auto
+		}
 		function.Spec.ProvisionedConcurrency = provisionedConcurrency
 	}
 
 	if input.IsSet(flagkey.FnOnceOnly) {
 		function.Spec.OnceOnly = input.Bool(flagkey.FnOnceOnly)
 	}
+
+	if input.IsSet(flagkey.FnResourceClass) {
+		function.Spec.ResourceClass = input.String(flagkey.FnResourceClass)
+	}
 	if len(pkgName) == 0 {
 		pkgName = function.Spec.Package.PackageRef.Name
 	}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package function
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fission/fission/pkg/executor/usage"
+	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
+)
+
+// mockExecutor stands in for the executor usage API, answering every request
+// with code and body, and points FISSION_EXECUTOR_URL at it.
+func mockExecutor(t *testing.T, code int, body string) *[]*http.Request {
+	t.Helper()
+	var got []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r)
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("FISSION_EXECUTOR_URL", srv.URL)
+	return &got
+}
+
+func TestUsageCLI(t *testing.T) {
+	got := mockExecutor(t, http.StatusOK, `{"since":"2026-10-12T10:00:00Z","until":"2026-10-19T11:00:00Z","rows":[
+		{"namespace":"ns","name":"hello","executorType":"poolmgr","podSeconds":7200,"cpuCoreSeconds":3600,"memoryByteSeconds":1932735283200},
+		{"namespace":"ns","name":"hello","version":"v2","executorType":"poolmgr","podSeconds":1800,"cpuCoreSeconds":900,"memoryByteSeconds":0}]}`)
+	in := fakeDLQInput{s: map[string]string{flagkey.Namespace: "ns", flagkey.FnName: "hello", flagkey.UsageSince: "168h"}}
+	out := captureStdout(t, func() error { return (&UsageSubCommand{}).do(in) })
+
+	require.Len(t, *got, 1)
+	req := (*got)[0]
+	assert.Equal(t, http.MethodGet, req.Method)
+	assert.Equal(t, usage.Path, req.URL.Path)
+	q := req.URL.Query()
+	assert.Equal(t, "function", q.Get("kind"))
+	assert.Equal(t, "ns", q.Get("namespace"))
+	assert.Equal(t, "hello", q.Get("name"))
+	assert.Equal(t, "168h", q.Get("since"))
+	assert.False(t, q.Has("until"))
+
+	assert.Contains(t, out, "POD-HOURS")
+	assert.Regexp(t, `hello\s+<none>\s+poolmgr\s+ns\s+2\.00\s+1\.00\s+0\.50`, out)
+	assert.Regexp(t, `hello\s+v2\s+poolmgr\s+ns\s+0\.50\s+0\.25\s+0\.00`, out)
+	assert.Contains(t, out, "From 2026-10-12T10:00Z to 2026-10-19T11:00Z.")
+}
+
+func TestUsageCLINotEnabled(t *testing.T) {
+	mockExecutor(t, http.StatusNotImplemented, "usage accounting is not enabled")
+	in := fakeDLQInput{s: map[string]string{flagkey.Namespace: "ns"}}
+	err := (&UsageSubCommand{}).do(in)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "usage accounting is not enabled on this cluster")
+}
@@ -78,7 +78,24 @@ func Commands() *cobra.Command {
 		Optional: []flag.Flag{flag.WaitTimeout},
 	})
 
-	command.AddCommand(createCmd, getCmd, updateCmd, deleteCmd, listCmd, waitCmd)
+	testCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "test",
+		Short: "Preview the request an HTTP trigger forwards to its function",
+		Long:  "Apply the trigger's transform to a sample request and print the request the function would receive. Nothing is sent to the cluster but the trigger lookup.",
+	}, Test, flag.FlagSet{
+		Required: []flag.Flag{flag.HtName, flag.HtTestPath},
+		Optional: []flag.Flag{flag.HtTestMethod, flag.FnTestHeader, flag.FnTestQuery, flag.FnTestBody},
+	})
+
+	openAPICmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "openapi",
+		Short: "Generate the OpenAPI document of a namespace's HTTP triggers",
+		Long:  "Generate an OpenAPI 3.1 document from the HTTP triggers of a namespace: their paths, methods and CORS settings, the functions' tool descriptions and input schemas, and each trigger's optional openapi block.",
+	}, OpenAPI, flag.FlagSet{
+		Optional: []flag.Flag{flag.HtOpenAPIOutput, flag.HtOpenAPIServer},
+	})
+
+	command.AddCommand(createCmd, getCmd, updateCmd, deleteCmd, listCmd, waitCmd, testCmd, openAPICmd)
 
 	return command
 }
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httptrigger
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fission/fission/pkg/fission-cli/cliwrapper/cli"
+	"github.com/fission/fission/pkg/fission-cli/cmd"
+	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
+	"github.com/fission/fission/pkg/fission-cli/util"
+	"github.com/fission/fission/pkg/router/openapi"
+)
+
+// OpenAPISubCommand prints the OpenAPI document of a namespace's HTTP
+// triggers. It is generated here, from the objects the API server returns,
+// by the same code the router's internal /v1/openapi endpoint runs.
+type OpenAPISubCommand struct {
+	cmd.CommandActioner
+}
+
+func OpenAPI(input cli.Input) error {
+	return (&OpenAPISubCommand{}).do(input)
+}
+
+func (opts *OpenAPISubCommand) do(input cli.Input) error {
+	format, err := util.ParseOutputFormat(input.String(flagkey.Output))
+	if err != nil {
+		return err
+	}
+	if format != util.OutputJSON && format != util.OutputYAML {
+		return fmt.Errorf("invalid output format %q: valid values are json, yaml", input.String(flagkey.Output))
+	}
+	_, namespace, err := opts.GetResourceNamespace(input)
+	if err != nil {
+		return fmt.Errorf("error generating OpenAPI document: %w", err)
+	}
+
+	client := opts.Client().FissionClientSet.CoreV1()
+	triggers, err := client.HTTPTriggers(namespace).List(input.Context(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing HTTP triggers: %w", err)
+	}
+	functions, err := client.Functions(namespace).List(input.Context(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing functions: %w", err)
+	}
+
+	doc := openapi.Generate(triggers.Items, functions.Items, openapi.Options{
+		Namespace: namespace,
+		ServerURL: input.String(flagkey.HtOpenAPIServer),
+	})
+	_, err = util.PrintStructured(format, doc)
+	return err
+}
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httptrigger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/fission-cli/cliwrapper/cli"
+	"github.com/fission/fission/pkg/fission-cli/cmd"
+	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
+	"github.com/fission/fission/pkg/router/transform"
+	"github.com/fission/fission/pkg/utils/httpmux"
+)
+
+// TestSubCommand previews an HTTPTrigger's transform: it builds the sample
+// request from the flags, routes it by the trigger's path the way the router
+// does (so path template variables resolve), applies the transform with the
+// router's own code and prints what the function would receive.
+type TestSubCommand struct {
+	cmd.CommandActioner
+}
+
+func Test(input cli.Input) error {
+	return (&TestSubCommand{}).do(input)
+}
+
+func (opts *TestSubCommand) do(input cli.Input) error {
+	_, namespace, err := opts.GetResourceNamespace(input)
+	if err != nil {
+		return fmt.Errorf("error testing HTTP trigger: %w", err)
+	}
+	ht, err := opts.Client().FissionClientSet.CoreV1().HTTPTriggers(namespace).Get(input.Context(), input.String(flagkey.HtName), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting http trigger: %w", err)
+	}
+
+	req, err := sampleRequest(input.Context(), input)
+	if err != nil {
+		return err
+	}
+	out, err := previewTransform(ht, req)
+	if err != nil {
+		return err
+	}
+	return printRequest(os.Stdout, out)
+}
+
+// sampleRequest builds the request to preview from --method, --path,
+// --query, --header and --body.
+func sampleRequest(ctx context.Context, input cli.Input) (*http.Request, error) {
+	u := &url.URL{Path: input.String(flagkey.HtTestPath)}
+	if !strings.HasPrefix(u.Path, "/") {
+		return nil, fmt.Errorf("--%s must start with /", flagkey.HtTestPath)
+	}
+	query := url.Values{}
+	for _, q := range input.StringSlice(flagkey.FnTestQuery) {
+		if key, value, _ := strings.Cut(q, "="); key != "" {
+			query.Add(key, value)
+		}
+	}
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, input.String(flagkey.HtTestMethod), u.String(), strings.NewReader(input.String(flagkey.FnTestBody)))
+	if err != nil {
+		return nil, fmt.Errorf("error building sample request: %w", err)
+	}
+	for _, h := range input.StringSlice(flagkey.FnTestHeader) {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, fmt.Errorf("header %q must be of the form 'Name: value'", h)
+		}
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return req, nil
+}
+
+// previewTransform returns req as the router would forward it through ht. A
+// trigger without a request transform forwards req with its path rewritten
+// as usual, which is still worth seeing for a prefix trigger.
+func previewTransform(ht *fv1.HTTPTrigger, req *http.Request) (*http.Request, error) {
+	if err := ht.Spec.ValidateTransform(); err != nil {
+		return nil, fmt.Errorf("http trigger %q has an invalid transform: %w", ht.Name, err)
+	}
+	var (
+		out      *http.Request
+		applyErr error
+	)
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		out = r.Clone(r.Context())
+		path := ht.Spec.ForwardedPath(r.URL.Path)
+		if ht.Spec.Transform == nil || ht.Spec.Transform.Request == nil {
+			out.URL.Path = path
+			return
+		}
+		applyErr = transform.Request(out, ht.Spec.Transform.Request, path, httpmux.Vars(r))
+	})
+
+	// The same exact and prefix routes the router registers for the
+	// trigger's path; methods, host and match rules are not the transform's
+	// concern and are left out.
+	var exact, prefix string
+	switch {
+	case ht.Spec.GRPC != nil:
+		prefix = ht.Spec.GRPC.PathPrefix()
+	case ht.Spec.Prefix != nil && *ht.Spec.Prefix != "":
+		prefix = *ht.Spec.Prefix
+		if !strings.HasSuffix(prefix, "/") {
+			exact, prefix = prefix, prefix+"/"
+		}
+	default:
+		exact = ht.Spec.RelativeURL
+	}
+	mux := httpmux.New()
+	if exact != "" {
+		if err := httpmux.CompilePattern(exact, httpmux.Exact); err != nil {
+			return nil, fmt.Errorf("http trigger %q has an invalid path: %w", ht.Name, err)
+		}
+		mux.Handle(exact, handler)
+	}
+	if prefix != "" {
+		if err := httpmux.CompilePattern(prefix, httpmux.Prefix); err != nil {
+			return nil, fmt.Errorf("http trigger %q has an invalid prefix: %w", ht.Name, err)
+		}
+		mux.HandlePrefix(prefix, handler)
+	}
+	mux.Handler().ServeHTTP(discardResponse{}, req)
+
+	if out == nil {
+		return nil, fmt.Errorf("path %q is not served by http trigger %q", req.URL.Path, ht.Name)
+	}
+	if applyErr != nil {
+		return nil, fmt.Errorf("error applying the transform: %w", applyErr)
+	}
+	return out, nil
+}
+
+// printRequest writes r as a request line, its headers sorted by name, and
+// its body.
+func printRequest(w io.Writer, r *http.Request) error {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return fmt.Errorf("error reading transformed body: %w", err)
+		}
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s %s\n", r.Method, r.URL.RequestURI())
+	for _, name := range slices.Sorted(maps.Keys(r.Header)) {
+		for _, v := range r.Header[name] {
+			fmt.Fprintf(&b, "%s: %s\n", name, v)
+		}
+	}
+	if len(body) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", body)
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// discardResponse is the ResponseWriter the preview routes through; only the
+// handler's side effect on the request matters.
+type discardResponse struct{}
+
+func (discardResponse) Header() http.Header         { return http.Header{} }
+func (discardResponse) Write(b []byte) (int, error) { return len(b), nil }
+func (discardResponse) WriteHeader(int)             {}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package httptrigger
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+func TestPreviewTransform(t *testing.T) {
+	ht := &fv1.HTTPTrigger{
+		ObjectMeta: metav1.ObjectMeta{Name: "users"},
+		Spec: fv1.HTTPTriggerSpec{
+			RelativeURL: "/users/{id}",
+			Transform: &fv1.HTTPTriggerTransform{Request: &fv1.HTTPRequestTransform{
+				Headers: &fv1.HTTPHeaderTransform{
+					Remove: []string{"Cookie"},
+					Set:    []fv1.HTTPHeaderValue{{Name: "X-Tenant", Value: "{{query.tenant}}"}},
+				},
+				Path: "/v2/users/{{param.id}}",
+				Body: `{"user": "{{form.user}}"}`,
+			}},
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "/users/42?tenant=acme", strings.NewReader("user=bob"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Cookie", "session=1")
+
+	out, err := previewTransform(ht, req)
+	require.NoError(t, err)
+	var b bytes.Buffer
+	require.NoError(t, printRequest(&b, out))
+	assert.Equal(t, `POST /v2/users/42?tenant=acme
+Content-Length: 15
+Content-Type: application/json
+X-Tenant: acme
+
+{"user": "bob"}
+`, b.String())
+
+	other, err := http.NewRequest(http.MethodGet, "/orders/1", nil)
+	require.NoError(t, err)
+	_, err = previewTransform(ht, other)
+	assert.ErrorContains(t, err, "is not served by http trigger")
+}
+
+func TestPreviewWithoutTransformShowsForwardedPath(t *testing.T) {
+	prefix := "/api"
+	ht := &fv1.HTTPTrigger{Spec: fv1.HTTPTriggerSpec{Prefix: &prefix}}
+	req, err := http.NewRequest(http.MethodGet, "/api/orders?x=1", nil)
+	require.NoError(t, err)
+
+	out, err := previewTransform(ht, req)
+	require.NoError(t, err)
+	assert.Equal(t, "/orders?x=1", out.URL.RequestURI())
+}
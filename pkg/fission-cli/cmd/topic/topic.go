@@ -2,13 +2,15 @@
 //
 // SPDX-License-Identifier: Apache-2.0
 
-// Package topic implements `fission topic publish|peek` — thin dev
-// conveniences over the router's RFC-0027 topic admin API (the INTERNAL
-// listener, HMAC-signed like `function dlq`), for exercising the zero-broker
-// eventing loop without writing a publisher function.
+// Package topic implements `fission topic` — thin conveniences over the
+// router's RFC-0027 topic admin API (the INTERNAL listener, HMAC-signed like
+// `function dlq`): publish|peek for exercising the zero-broker eventing loop
+// without writing a publisher function, and retention|groups|seek for
+// operating statestore topics and their subscriptions.
 package topic
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,6 +26,7 @@ import (
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
 	hmacauth "github.com/fission/fission/pkg/auth/hmac"
 	"github.com/fission/fission/pkg/fission-cli/cliwrapper/cli"
 	wrapper "github.com/fission/fission/pkg/fission-cli/cliwrapper/driver/cobra"
@@ -35,13 +38,17 @@ import (
 
 // Wire paths the router registers (pkg/router/async_topics.go).
 const (
-	topicAPIPublish = "/v1/eventing/topic/publish"
-	topicAPIPeek    = "/v1/eventing/topic/peek"
+	topicAPIPublish   = "/v1/eventing/topic/publish"
+	topicAPIPeek      = "/v1/eventing/topic/peek"
+	topicAPIRetention = "/v1/eventing/topic/retention"
+	topicAPIGroups    = "/v1/eventing/topic/groups"
+	topicAPISeek      = "/v1/eventing/topic/seek"
 )
 
 type topicEvent struct {
 	Seq     int64     `json:"seq"`
 	Type    string    `json:"type"`
+	Key     string    `json:"key"`
 	Payload []byte    `json:"payload"`
 	At      time.Time `json:"at"`
 }
@@ -51,6 +58,43 @@ type topicPeekResp struct {
 	Events []topicEvent `json:"events"`
 }
 
+// retentionPolicy mirrors mqpub.TopicRetention on the wire.
+type retentionPolicy struct {
+	MaxAge    *metav1.Duration `json:"maxAge,omitempty"`
+	MaxEvents int64            `json:"maxEvents,omitempty"`
+	MaxBytes  int64            `json:"maxBytes,omitempty"`
+	Compact   bool             `json:"compact,omitempty"`
+}
+
+type topicGroup struct {
+	Trigger     string `json:"trigger"`
+	Initialized bool   `json:"initialized"`
+	Cursor      int64  `json:"cursor"`
+	Lag         int64  `json:"lag"`
+}
+
+type topicGroupsResp struct {
+	Head      int64           `json:"head"`
+	Floor     int64           `json:"floor"`
+	Events    int64           `json:"events"`
+	Bytes     int64           `json:"bytes"`
+	Retention retentionPolicy `json:"retention"`
+	Groups    []topicGroup    `json:"groups"`
+}
+
+type topicSeekReq struct {
+	Namespace string `json:"namespace"`
+	Topic     string `json:"topic"`
+	Trigger   string `json:"trigger"`
+	To        string `json:"to"`
+}
+
+type topicSeekResp struct {
+	Trigger  string `json:"trigger"`
+	Previous int64  `json:"previous"`
+	Cursor   int64  `json:"cursor"`
+}
+
 // Commands builds the `fission topic` group.
 func Commands() *cobra.Command {
 	publishCmd := wrapper.SubCommand(&cobra.Command{
@@ -58,7 +102,7 @@ func Commands() *cobra.Command {
 		Short: "Publish an event to a topic (statestore direct, or a broker via egress)",
 	}, Publish, flag.FlagSet{
 		Required: []flag.Flag{flag.TopicName, flag.TopicData},
-		Optional: []flag.Flag{flag.Namespace, flag.TopicContentType, flag.TopicMQType},
+		Optional: []flag.Flag{flag.Namespace, flag.TopicContentType, flag.TopicMQType, flag.TopicKey},
 	})
 	peekCmd := wrapper.SubCommand(&cobra.Command{
 		Use:   "peek",
@@ -68,12 +112,54 @@ func Commands() *cobra.Command {
 		Optional: []flag.Flag{flag.Namespace, flag.TopicLimit},
 	})
 
+	retentionGetCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "get",
+		Short: "Show a statestore topic's retention policy",
+	}, RetentionGet, flag.FlagSet{
+		Required: []flag.Flag{flag.TopicName},
+		Optional: []flag.Flag{flag.Namespace},
+	})
+	retentionSetCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "set",
+		Short: "Replace a statestore topic's retention policy (unset limits fall back to the global backstops)",
+	}, RetentionSet, flag.FlagSet{
+		Required: []flag.Flag{flag.TopicName},
+		Optional: []flag.Flag{flag.Namespace, flag.TopicMaxAge, flag.TopicMaxEvents, flag.TopicMaxBytes, flag.TopicCompact},
+	})
+	retentionClearCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove a statestore topic's retention policy (back to trimming what every subscriber consumed)",
+	}, RetentionClear, flag.FlagSet{
+		Required: []flag.Flag{flag.TopicName},
+		Optional: []flag.Flag{flag.Namespace},
+	})
+	retentionCmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Manage a statestore topic's retention and compaction policy",
+	}
+	retentionCmd.AddCommand(retentionGetCmd, retentionSetCmd, retentionClearCmd)
+
+	groupsCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "groups",
+		Short: "Show a statestore topic's subscriptions with their cursors and lag",
+	}, Groups, flag.FlagSet{
+		Required: []flag.Flag{flag.TopicName},
+		Optional: []flag.Flag{flag.Namespace},
+	})
+	seekCmd := wrapper.SubCommand(&cobra.Command{
+		Use:   "seek",
+		Short: "Move a statestore trigger's subscription cursor to replay or skip events",
+	}, Seek, flag.FlagSet{
+		Required: []flag.Flag{flag.TopicTrigger, flag.TopicSeekTo},
+		Optional: []flag.Flag{flag.Namespace},
+	})
+
 	command := &cobra.Command{
 		Use:     "topic",
 		Aliases: []string{"topics"},
 		Short:   "Publish to and inspect RFC-0027 eventing topics",
 	}
-	command.AddCommand(publishCmd, peekCmd)
+	command.AddCommand(publishCmd, peekCmd, retentionCmd, groupsCmd, seekCmd)
 	return command
 }
 
@@ -83,6 +169,17 @@ type topicSubCommand struct {
 
 func Publish(input cli.Input) error { return (&topicSubCommand{}).publish(input) }
 func Peek(input cli.Input) error    { return (&topicSubCommand{}).peek(input) }
+func RetentionGet(input cli.Input) error {
+	return (&topicSubCommand{}).retention(input, http.MethodGet, nil)
+}
+func RetentionSet(input cli.Input) error {
+	return (&topicSubCommand{}).retention(input, http.MethodPut, retentionFromFlags(input))
+}
+func RetentionClear(input cli.Input) error {
+	return (&topicSubCommand{}).retention(input, http.MethodPut, &retentionPolicy{})
+}
+func Groups(input cli.Input) error { return (&topicSubCommand{}).groups(input) }
+func Seek(input cli.Input) error   { return (&topicSubCommand{}).seek(input) }
 
 func (opts *topicSubCommand) publish(input cli.Input) error {
 	namespace, err := opts.namespace(input)
@@ -94,6 +191,9 @@ func (opts *topicSubCommand) publish(input cli.Input) error {
 		"topic":     {input.String(flagkey.TopicName)},
 		"mqtype":    {input.String(flagkey.TopicMQType)},
 	}
+	if key := input.String(flagkey.TopicKey); key != "" {
+		q.Set("key", key)
+	}
 	resp, err := opts.call(input, http.MethodPost, topicAPIPublish, q,
 		strings.NewReader(input.String(flagkey.TopicData)), input.String(flagkey.TopicContentType))
 	if err != nil {
@@ -134,13 +234,168 @@ func (opts *topicSubCommand) peek(input cli.Input) error {
 	if peek.Head == 0 {
 		fmt.Println("(statestore topic is empty — events published with a broker --mqtype are not visible here; inspect the broker)")
 	}
-	headers := []string{"SEQ", "TYPE", "AGE", "PAYLOAD"}
+	headers := []string{"SEQ", "TYPE", "KEY", "AGE", "PAYLOAD"}
 	row := func(e topicEvent) []string {
-		return []string{strconv.FormatInt(e.Seq, 10), e.Type, util.AgeOf(metav1.NewTime(e.At)), renderPayload(e.Payload)}
+		return []string{strconv.FormatInt(e.Seq, 10), e.Type, e.Key, util.AgeOf(metav1.NewTime(e.At)), renderPayload(e.Payload)}
 	}
 	return util.PrintObjects(util.OutputTable, peek.Events, headers, row, nil, func(topicEvent) []string { return nil })
 }
 
+// retentionFromFlags builds the policy `retention set` stores. The policy is
+// replaced whole, so an omitted flag unsets that limit.
+func retentionFromFlags(input cli.Input) *retentionPolicy {
+	p := &retentionPolicy{
+		MaxEvents: input.Int64(flagkey.TopicMaxEvents),
+		MaxBytes:  input.Int64(flagkey.TopicMaxBytes),
+		Compact:   input.Bool(flagkey.TopicCompact),
+	}
+	if input.IsSet(flagkey.TopicMaxAge) {
+		p.MaxAge = &metav1.Duration{Duration: input.Duration(flagkey.TopicMaxAge)}
+	}
+	return p
+}
+
+// retention reads (policy nil) or replaces a topic's retention policy and
+// prints the result.
+func (opts *topicSubCommand) retention(input cli.Input, method string, policy *retentionPolicy) error {
+	namespace, err := opts.namespace(input)
+	if err != nil {
+		return err
+	}
+	q := url.Values{
+		"namespace": {namespace},
+		"topic":     {input.String(flagkey.TopicName)},
+	}
+	var body io.Reader
+	if policy != nil {
+		data, err := json.Marshal(policy)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	resp, err := opts.call(input, method, topicAPIRetention, q, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	var got retentionPolicy
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		return fmt.Errorf("decoding router topic response: %w", err)
+	}
+	fmt.Printf("topic %q in namespace %q: %s\n", input.String(flagkey.TopicName), namespace, describeRetention(got))
+	return nil
+}
+
+func describeRetention(p retentionPolicy) string {
+	var parts []string
+	if p.MaxAge != nil {
+		parts = append(parts, "max-age "+p.MaxAge.Duration.String())
+	}
+	if p.MaxEvents > 0 {
+		parts = append(parts, "max-events "+strconv.FormatInt(p.MaxEvents, 10))
+	}
+	if p.MaxBytes > 0 {
+		parts = append(parts, "max-bytes "+strconv.FormatInt(p.MaxBytes, 10))
+	}
+	if p.Compact {
+		parts = append(parts, "compacted")
+	}
+	if len(parts) == 0 {
+		return "no retention policy (events are trimmed once every subscriber has consumed them)"
+	}
+	return "retains consumed history: " + strings.Join(parts, ", ")
+}
+
+// groups lists the statestore triggers subscribed to the topic — the CLI
+// resolves them, the router has no access to MessageQueueTriggers — and
+// prints each subscription's cursor and lag.
+func (opts *topicSubCommand) groups(input cli.Input) error {
+	namespace, err := opts.namespace(input)
+	if err != nil {
+		return err
+	}
+	topic := input.String(flagkey.TopicName)
+	mqts, err := opts.Client().FissionClientSet.CoreV1().MessageQueueTriggers(namespace).List(input.Context(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing message queue triggers: %w", err)
+	}
+	q := url.Values{"namespace": {namespace}, "topic": {topic}}
+	functions := map[string]string{}
+	for _, mqt := range mqts.Items {
+		if mqt.Spec.MessageQueueType == fv1.MessageQueueTypeStatestore && mqt.Spec.Topic == topic {
+			q.Add("trigger", mqt.Name)
+			functions[mqt.Name] = mqt.Spec.FunctionReference.Name
+		}
+	}
+	resp, err := opts.call(input, http.MethodGet, topicAPIGroups, q, nil, "")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	var groups topicGroupsResp
+	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+		return fmt.Errorf("decoding router topic response: %w", err)
+	}
+	fmt.Printf("head: %d  retained: %d events (seq %d-%d), %d bytes\n",
+		groups.Head, groups.Events, groups.Floor+1, groups.Head, groups.Bytes)
+	fmt.Printf("retention: %s\n", describeRetention(groups.Retention))
+	if len(groups.Groups) == 0 {
+		fmt.Println("(no statestore message queue triggers subscribe to this topic)")
+		return nil
+	}
+	headers := []string{"TRIGGER", "FUNCTION", "CURSOR", "LAG"}
+	row := func(g topicGroup) []string {
+		if !g.Initialized {
+			return []string{g.Trigger, functions[g.Trigger], "-", "(not started)"}
+		}
+		return []string{g.Trigger, functions[g.Trigger], strconv.FormatInt(g.Cursor, 10), strconv.FormatInt(g.Lag, 10)}
+	}
+	return util.PrintObjects(util.OutputTable, groups.Groups, headers, row, nil, func(topicGroup) []string { return nil })
+}
+
+// seek moves a statestore trigger's cursor on the topic it subscribes to.
+func (opts *topicSubCommand) seek(input cli.Input) error {
+	namespace, err := opts.namespace(input)
+	if err != nil {
+		return err
+	}
+	name := input.String(flagkey.TopicTrigger)
+	mqt, err := opts.Client().FissionClientSet.CoreV1().MessageQueueTriggers(namespace).Get(input.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("getting message queue trigger %q: %w", name, err)
+	}
+	if mqt.Spec.MessageQueueType != fv1.MessageQueueTypeStatestore {
+		return fmt.Errorf("trigger %q uses %q: seek supports statestore triggers only (broker offsets are managed with the broker's tooling)",
+			name, mqt.Spec.MessageQueueType)
+	}
+	data, err := json.Marshal(topicSeekReq{Namespace: namespace, Topic: mqt.Spec.Topic, Trigger: name, To: input.String(flagkey.TopicSeekTo)})
+	if err != nil {
+		return err
+	}
+	resp, err := opts.call(input, http.MethodPost, topicAPISeek, nil, bytes.NewReader(data), "application/json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return err
+	}
+	var seek topicSeekResp
+	if err := json.NewDecoder(resp.Body).Decode(&seek); err != nil {
+		return fmt.Errorf("decoding router topic response: %w", err)
+	}
+	fmt.Printf("trigger %q on topic %q: cursor %d -> %d (delivery resumes after seq %d)\n",
+		name, mqt.Spec.Topic, seek.Previous, seek.Cursor, seek.Cursor)
+	return nil
+}
+
 // renderPayload shows a printable payload verbatim (truncated) and sizes-only
 // for binary ones.
 func renderPayload(p []byte) string {
@@ -107,7 +107,7 @@ var (
 	FnEnvVar           = Flag{Type: StringSlice, Name: flagkey.FnEnvVar, Short: "e", Usage: "Per-function environment variable as KEY=VALUE; repeatable. On fn update the provided list replaces the function's env vars. (--env keeps meaning the Environment name.)"}
 	FnEnvFromSecret    = Flag{Type: StringSlice, Name: flagkey.FnEnvFromSecret, Usage: "Project a same-namespace Secret into the function's environment: 'name' for the whole object, 'name/key' for one key (variable named after the key), 'name/key:ENV' to rename it; repeatable. On fn update the provided list replaces the previous one."}
 	FnEnvFromConfigMap = Flag{Type: StringSlice, Name: flagkey.FnEnvFromConfigMap, Usage: "Project a same-namespace ConfigMap into the function's environment: 'name' for the whole object, 'name/key' for one key (variable named after the key), 'name/key:ENV' to rename it; repeatable. On fn update the provided list replaces the previous one."}
-	FnExecutorType     = Flag{Type: String, Name: flagkey.FnExecutorType, Usage: "Executor type for execution; one of 'poolmgr', 'newdeploy'", DefaultValue: string(fv1.ExecutorTypePoolmgr)}
+	FnExecutorType     = Flag{Type: String, Name: flagkey.FnExecutorType, Usage: "Executor type for execution; one of 'poolmgr', 'newdeploy', 'container', 'job', 'wasm'", DefaultValue: string(fv1.ExecutorTypePoolmgr)}
 	FnExecutionTimeout = Flag{Type: Int, Name: flagkey.FnExecutionTimeout, Aliases: []string{"ft"}, Usage: "Maximum time for a request to wait for the response from the function", DefaultValue: 60}
 	FnLogPod           = Flag{Type: String, Name: flagkey.FnLogPod, Usage: "Function pod name (use the latest pod name if unspecified)"}
 	FnLogFollow        = Flag{Type: Bool, Name: flagkey.FnLogFollow, Short: "f", Usage: "Specify if the logs should be streamed"}
@@ -144,7 +144,7 @@ var (
 	FnGetVersion           = Flag{Type: String, Name: flagkey.FnTestVersion, Usage: "Get a specific pinned FunctionVersion's snapshot source instead of the live function"}
 	FnIdleTimeout          = Flag{Type: Int, Name: flagkey.FnIdleTimeout, Usage: "The length of time (in seconds) that a function is idle before pod(s) are eligible for recycling", DefaultValue: 120}
 	FnStreaming            = Flag{Type: Bool, Name: flagkey.FnStreaming, Usage: "Enable streaming (SSE/chunked/WebSocket) responses for this function; the response is flushed incrementally and not cut by the function timeout"}
-	FnStreamingProtocol    = Flag{Type: String, Name: flagkey.FnStreamingProtocol, Usage: "Streaming protocol when --streaming is set; one of 'auto', 'sse', 'chunked', 'websocket'", DefaultValue: "auto"}
+	FnStreamingProtocol    = Flag{Type: String, Name: flagkey.FnStreamingProtocol, Usage: "Streaming protocol when --streaming is set; one of 'auto', 'sse', 'chunked', 'websocket', 'grpc'", DefaultValue: "auto"}
 	FnStreamingIdleTimeout = Flag{Type: Int, Name: flagkey.FnStreamingIdleTimeout, Usage: "Idle timeout (seconds) for a streaming response before it is aborted; reset on each chunk", DefaultValue: 60}
 	FnStreamingMaxDuration = Flag{Type: Int, Name: flagkey.FnStreamingMaxDuration, Usage: "Hard ceiling (seconds) on total streaming response lifetime; 0 means no ceiling (the idle timeout governs)", DefaultValue: 0}
 	FnExposeAsMCP          = Flag{Type: Bool, Name: flagkey.FnExposeAsMCP, Usage: "Advertise this function as a Model Context Protocol (MCP) tool on the MCP server"}
@@ -174,8 +174,11 @@ var (
 	FnRetainPods             = Flag{Type: Int, Name: flagkey.FnRetainPods, Usage: "Number of pods to retain after pods specialization.", DefaultValue: 0}
 	FnProvisionedConcurrency = Flag{Type: Int, Name: flagkey.FnProvisionedConcurrency, Usage: "Number of warm specialized pods to maintain eagerly (poolmgr only). 0 (default)=no provisioned concurrency", DefaultValue: 0}
 	FnProvisionedSchedule    = Flag{Type: StringSlice, Name: flagkey.FnProvisionedSchedule, Usage: "name=<window-name>;start=<cron(0 9 * * *)>;duration=<10h(time.Duration)>;target=<number of pods>", DefaultValue: []string{}}
+	FnProvisionedAuto        = Flag{Type: String, Name: flagkey.FnProvisionedAuto, Usage: "Derive the provisioned target from observed concurrency: percentile=<95>;window=<15m>;headroom=<percent>;min=<pods>;max=<pods>;seasonality=<days>, every key optional. --provisioned-concurrency applies until there is enough history; 'off' disables"}
 	FnVersioning             = Flag{Type: String, Name: flagkey.FnVersioning, Usage: "Opt the function into immutable version snapshots and named aliases; one of 'auto' (mint a version on every runtime-affecting update), 'manual' (mint only on `fission fn publish`), or 'off' (disable, update only)"}
 	FnRetainVersions         = Flag{Type: Int, Name: flagkey.FnRetainVersions, Usage: "Number of unaliased versions to keep per function before older ones are garbage collected (requires --versioning auto|manual, or an existing versioning config); disambiguates from --retainpods, which retains specialized pods rather than function versions"}
+	FnJobID                  = Flag{Type: String, Name: flagkey.FnJobID, Usage: "Invocation id a job function answered with"}
+	FnResourceClass          = Flag{Type: String, Name: flagkey.FnResourceClass, Usage: "Resource class of the environment whose warm pool the function runs in (Only valid for executortype; `poolmgr`); an empty value on update returns it to the environment's default pool"}
 
 	// RFC-0027 `fission topic` dev commands.
 	TopicName        = Flag{Type: String, Name: flagkey.TopicName, Usage: "Topic name"}
@@ -183,12 +186,35 @@ var (
 	TopicContentType = Flag{Type: String, Name: flagkey.TopicContentType, Usage: "Content type the payload travels with (consuming triggers replay it)", DefaultValue: "application/json"}
 	TopicMQType      = Flag{Type: String, Name: flagkey.TopicMQType, Usage: "Message queue provider: statestore (built-in, namespace-scoped), or a broker type with an egress head (kafka — broker topics are cluster-flat, like kafka mqtriggers)", DefaultValue: "statestore"}
 	TopicLimit       = Flag{Type: Int, Name: flagkey.TopicLimit, Usage: "Maximum events to peek", DefaultValue: 10}
+	TopicKey         = Flag{Type: String, Name: flagkey.TopicKey, Usage: "Message key: the compaction identity (statestore topics only)"}
+	TopicMaxAge      = Flag{Type: Duration, Name: flagkey.TopicMaxAge, Usage: "Trim events older than this (unset: the 7d global backstop)"}
+	TopicMaxEvents   = Flag{Type: Int64, Name: flagkey.TopicMaxEvents, Usage: "Retain at most this many of the newest events (unset: half the topic backlog cap)"}
+	TopicMaxBytes    = Flag{Type: Int64, Name: flagkey.TopicMaxBytes, Usage: "Retain at most this many payload bytes (unset: no byte bound)"}
+	TopicCompact     = Flag{Type: Bool, Name: flagkey.TopicCompact, Usage: "Keep only the latest consumed event per message key"}
+	TopicTrigger     = Flag{Type: String, Name: flagkey.TopicTrigger, Usage: "Statestore MessageQueueTrigger whose subscription cursor to move"}
+	TopicSeekTo      = Flag{Type: String, Name: flagkey.TopicSeekTo, Usage: "Where delivery resumes: earliest, latest, a sequence number, or an RFC3339 time"}
 
 	// RFC-0024 async dead-letter-queue admin flags.
-	DlqQueue = Flag{Type: String, Name: flagkey.DlqQueue, Usage: "Dead-letter queue to operate on: empty for async invocations, or a broker egress queue (mq-egress-<type>, e.g. mq-egress-kafka)"}
+	DlqQueue = Flag{Type: String, Name: flagkey.DlqQueue, Usage: "Dead-letter queue to operate on: empty for async invocations (normal lane), asyncinv-high or asyncinv-low for the other priority lanes, or a broker egress queue (mq-egress-<type>, e.g. mq-egress-kafka)"}
 	DlqID    = Flag{Type: String, Name: flagkey.DlqID, Usage: "Durable invocation id of a dead-lettered async invocation"}
 	DlqAll   = Flag{Type: Bool, Name: flagkey.DlqAll, Usage: "Apply to every dead-lettered invocation"}
 	DlqLimit = Flag{Type: Int, Name: flagkey.DlqLimit, Usage: "Maximum number of dead-lettered invocations to list", DefaultValue: 100}
+	// Filters shared by list, export, redrive --all and purge.
+	DlqFunction = Flag{Type: String, Name: flagkey.DlqFunction, Usage: "Only dead letters for this function (any alias or version of it)"}
+	DlqReason   = Flag{Type: String, Name: flagkey.DlqReason, Usage: "Only dead letters with this exact reason (e.g. http_4xx, max_attempts, max_age)"}
+	DlqError    = Flag{Type: String, Name: flagkey.DlqError, Usage: "Only dead letters whose reason contains this text (case-insensitive)"}
+	DlqSince    = Flag{Type: String, Name: flagkey.DlqSince, Usage: "Only dead letters that died at or after this time: RFC 3339, or a duration ago (e.g. 2h)"}
+	DlqUntil    = Flag{Type: String, Name: flagkey.DlqUntil, Usage: "Only dead letters that died before this time: RFC 3339, or a duration ago (e.g. 30m)"}
+	// Redrive target: re-point dead letters at another function, alias or version.
+	DlqToFunction = Flag{Type: String, Name: flagkey.DlqToFunction, Usage: "Redrive to this function instead of the original (in the dead letter's namespace)"}
+	DlqToAlias    = Flag{Type: String, Name: flagkey.DlqToAlias, Usage: "Redrive to this alias of the target function; exclusive with --to-version"}
+	DlqToVersion  = Flag{Type: String, Name: flagkey.DlqToVersion, Usage: "Redrive to this pinned version of the target function; exclusive with --to-alias"}
+	DlqFile       = Flag{Type: String, Name: flagkey.DlqFile, Usage: "Write the export to this file instead of stdout"}
+
+	// Usage accounting range (fn usage / env usage). The executor rolls usage
+	// up hourly, so both ends widen to whole hours.
+	UsageSince = Flag{Type: String, Name: flagkey.UsageSince, Usage: "Start of the usage range: RFC 3339, or a duration ago (e.g. 168h)", DefaultValue: "24h"}
+	UsageUntil = Flag{Type: String, Name: flagkey.UsageUntil, Usage: "End of the usage range: RFC 3339, or a duration ago (default now)"}
 
 	// RFC-0023 keyed-state config (fn create/update).
 	FnState              = Flag{Type: Bool, Name: flagkey.FnState, Usage: "Opt the function into the keyed-state API"}
@@ -211,6 +237,7 @@ var (
 	FnAsyncMaxAge      = Flag{Type: Duration, Name: flagkey.FnAsyncMaxAge, Usage: "Max time an async invocation may wait for successful delivery before it is dead-lettered"}
 	FnAsyncOnSuccess   = Flag{Type: String, Name: flagkey.FnAsyncOnSuccess, Usage: "Same-namespace function to invoke with the result after a successful async delivery; empty clears it"}
 	FnAsyncOnFailure   = Flag{Type: String, Name: flagkey.FnAsyncOnFailure, Usage: "Same-namespace function to invoke with the result after a permanent async failure; empty clears it"}
+	FnAsyncPriority    = Flag{Type: String, Name: flagkey.FnAsyncPriority, Usage: "Default async queue lane: high, normal or low (a request's X-Fission-Invoke-Priority header overrides it); empty clears it"}
 	// RFC-0027 statestore topic destinations. Mutually exclusive per condition
 	// with the function-destination flag above.
 	FnAsyncOnSuccessTopic = Flag{Type: String, Name: flagkey.FnAsyncOnSuccessTopic, Usage: "Statestore topic to publish the result envelope to after a successful async delivery; empty clears it"}
@@ -240,6 +267,10 @@ var (
 	HtFnFilter          = Flag{Type: String, Name: flagkey.HtFilter, Usage: "Name of the function for trigger(s)"}
 	HtPrefix            = Flag{Type: String, Name: flagkey.HtPrefix, Usage: "Prefix with which functions are exposed. NOTE: Prefix takes precedence over URL/RelativeURL [DEPRECATED for 'fn create', use 'route create' instead]"}
 	HtKeepPrefix        = Flag{Type: Bool, Name: flagkey.HtKeepPrefix, Usage: "Keep the prefix in the URL while forwarding request to the function"}
+	HtTestPath          = Flag{Type: String, Name: flagkey.HtTestPath, Usage: "Path of the sample request, as a client would send it to the router, e.g. /users/42"}
+	HtTestMethod        = Flag{Type: String, Name: flagkey.HtTestMethod, Usage: "HTTP method of the sample request", DefaultValue: http.MethodGet}
+	HtOpenAPIOutput     = Flag{Type: String, Name: flagkey.Output, Short: "o", Usage: "Document format: yaml or json", DefaultValue: "yaml"}
+	HtOpenAPIServer     = Flag{Type: String, Name: flagkey.HtOpenAPIServer, Usage: "Public URL of the router, listed as the document's server, e.g. https://api.example.com"}
 
 	TokUsername = Flag{Type: String, Name: flagkey.TokUsername, Usage: "Username to generate token for function invocation"}
 	TokPassword = Flag{Type: String, Name: flagkey.TokPassword, Usage: "Password to generate token for function invocation"}
@@ -264,7 +295,7 @@ var (
 
 	MqtName            = Flag{Type: String, Name: flagkey.MqtName, Usage: "Message queue trigger name"}
 	MqtFnName          = Flag{Type: String, Name: flagkey.MqtFnName, Usage: "Function name"}
-	MqtMQType          = Flag{Type: String, Name: flagkey.MqtMQType, Usage: "For mqtkind \"fission\" => kafka, statestore (the built-in, no-broker option)\n\t\t\t\t\t For mqtkind \"keda\" => kafka, aws-sqs-queue, aws-kinesis-stream, gcp-pubsub, stan, nats-jetstream, rabbitmq, redis", DefaultValue: "kafka"}
+	MqtMQType          = Flag{Type: String, Name: flagkey.MqtMQType, Usage: "For mqtkind \"fission\" => kafka, nats-jetstream, rabbitmq, statestore (the built-in, no-broker option)\n\t\t\t\t\t For mqtkind \"keda\" => kafka, aws-sqs-queue, aws-kinesis-stream, gcp-pubsub, stan, nats-jetstream, rabbitmq, redis", DefaultValue: "kafka"}
 	MqtTopic           = Flag{Type: String, Name: flagkey.MqtTopic, Usage: "Message queue Topic the trigger listens on"}
 	MqtRespTopic       = Flag{Type: String, Name: flagkey.MqtRespTopic, Usage: "Topic that the function response is sent on (response discarded if unspecified)"}
 	MqtErrorTopic      = Flag{Type: String, Name: flagkey.MqtErrorTopic, Usage: "Topic that the function error messages are sent to (errors discarded if unspecified"}
@@ -96,14 +96,34 @@ const (
 	FnRetainPods             = "retainpods"
 	FnProvisionedConcurrency = "provisioned-concurrency"
 	FnProvisionedSchedule    = "provisioned-schedule"
+	FnProvisionedAuto        = "provisioned-auto"
+	FnResourceClass          = "resourceclass"
 
 	// RFC-0025 versioning opt-in (fn create/update).
 	FnVersioning     = "versioning"
 	FnRetainVersions = "retain-versions"
 
+	// Executor type job (fn job-status).
+	FnJobID = "id"
+
 	DlqID    = "id"
 	DlqAll   = "all"
 	DlqLimit = "limit"
+	// DLQ filters and redrive targets; "function"/"version"/"file" reuse strings
+	// from other subcommands (flag names are scoped per-subcommand).
+	DlqFunction   = "function"
+	DlqReason     = "reason"
+	DlqError      = "error"
+	DlqSince      = "since"
+	DlqUntil      = "until"
+	DlqToFunction = "to-function"
+	DlqToAlias    = "to-alias"
+	DlqToVersion  = "to-version"
+	DlqFile       = "file"
+
+	// Usage accounting (fn usage / env usage).
+	UsageSince = "since"
+	UsageUntil = "until"
 
 	FnTestAsync = "async"
 
@@ -127,6 +147,7 @@ const (
 	FnAsyncMaxAge      = "async-max-age"
 	FnAsyncOnSuccess   = "async-on-success"
 	FnAsyncOnFailure   = "async-on-failure"
+	FnAsyncPriority    = "async-priority"
 	DlqQueue           = "queue"
 	// RFC-0027 `fission topic` dev commands.
 	TopicName        = "topic"
@@ -135,6 +156,14 @@ const (
 
 	TopicMQType = "mqtype"
 	TopicLimit  = "limit"
+	TopicKey    = "key"
+	// RFC-0027 topic retention and consumer groups.
+	TopicMaxAge    = "max-age"
+	TopicMaxEvents = "max-events"
+	TopicMaxBytes  = "max-bytes"
+	TopicCompact   = "compact"
+	TopicTrigger   = "trigger"
+	TopicSeekTo    = "to"
 	// RFC-0027 topic destinations (statestore built-in eventing).
 	FnAsyncOnSuccessTopic = "async-on-success-topic"
 	FnAsyncOnFailureTopic = "async-on-failure-topic"
@@ -177,6 +206,9 @@ const (
 	HtFilter            = HtFnName
 	HtPrefix            = "prefix"
 	HtKeepPrefix        = "keepprefix"
+	HtTestPath          = "path"
+	HtTestMethod        = "method"
+	HtOpenAPIServer     = "server-url"
 
 	TokUsername = "username"
 	TokPassword = "password"
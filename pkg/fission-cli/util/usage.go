@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
+	"github.com/fission/fission/pkg/executor/usage"
+	"github.com/fission/fission/pkg/fission-cli/cliwrapper/cli"
+	"github.com/fission/fission/pkg/fission-cli/cmd"
+	flagkey "github.com/fission/fission/pkg/fission-cli/flag/key"
+)
+
+// UsageHeaders are the usage columns `fn usage` and `env usage` print after
+// their identifying ones.
+var UsageHeaders = []string{"POD-HOURS", "CPU-CORE-HOURS", "MEMORY-GIB-HOURS"}
+
+// FetchUsage queries the executor usage API for kind in namespace (""
+// for all), optionally narrowed to one name, over the --since/--until range.
+// Requests are HMAC-signed with the executor key derived from
+// FISSION_INTERNAL_AUTH_SECRET (empty → pass-through), like every other
+// executor API caller.
+func FetchUsage(input cli.Input, cmdClient cmd.Client, kind usage.Kind, namespace, name string) (*usage.Response, error) {
+	executorURL, err := GetExecutorURL(input.Context(), cmdClient)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the Fission executor: %w", err)
+	}
+	u := *executorURL
+	u.Path = usage.Path
+	q := u.Query()
+	q.Set("kind", string(kind))
+	for param, v := range map[string]string{
+		"namespace": namespace,
+		"name":      name,
+		"since":     input.String(flagkey.UsageSince),
+		"until":     input.String(flagkey.UsageUntil),
+	} {
+		if v != "" {
+			q.Set(param, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(input.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport
+	if secret := os.Getenv("FISSION_INTERNAL_AUTH_SECRET"); secret != "" {
+		transport = hmacauth.NewServiceSigningTransport([]byte(secret), hmacauth.ServiceExecutor, transport, usage.Path)
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling the executor usage API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotImplemented:
+		return nil, errors.New("usage accounting is not enabled on this cluster")
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("executor usage API rejected the request (%s); set FISSION_INTERNAL_AUTH_SECRET when authentication is enabled", resp.Status)
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return nil, fmt.Errorf("executor usage API returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var out usage.Response
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding executor usage response: %w", err)
+	}
+	return &out, nil
+}
+
+// UsageColumns renders u in the units of UsageHeaders.
+func UsageColumns(u usage.Usage) []string {
+	hours := func(v float64) string { return strconv.FormatFloat(v/3600, 'f', 2, 64) }
+	return []string{hours(u.PodSeconds), hours(u.CPUCoreSeconds), hours(u.MemoryByteSeconds / (1 << 30))}
+}
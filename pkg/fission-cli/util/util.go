@@ -253,6 +253,20 @@ func GetStateSvcURL(ctx context.Context, cmdClient cmd.Client) (*url.URL, error)
 	return url.Parse(fmt.Sprintf("%s%s", localhostURL, localPort))
 }
 
+// GetExecutorURL locates the executor API for the `usage` commands: env
+// override first (hand-managed forwards / tests), else an in-process
+// port-forward to an executor pod.
+func GetExecutorURL(ctx context.Context, cmdClient cmd.Client) (*url.URL, error) {
+	if u := os.Getenv("FISSION_EXECUTOR_URL"); u != "" {
+		return url.Parse(u)
+	}
+	localPort, err := SetupPortForwardToPort(ctx, cmdClient, GetFissionNamespace(), "svc=executor", svcinfo.PortExecutor)
+	if err != nil {
+		return nil, err
+	}
+	return url.Parse(fmt.Sprintf("%s%s", localhostURL, localPort))
+}
+
 func GetResourceReqs(input cli.Input, resReqs *v1.ResourceRequirements) (*v1.ResourceRequirements, error) {
 	r := &v1.ResourceRequirements{}
 
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jobrunner implements the fission-bundle --jobRunner subsystem: the
+// main container of a pod the job executor type runs one invocation in. The
+// pod's environment runtime and fetcher run beside it as sidecars; the
+// fetcher specializes the runtime on startup, as in a newdeploy pod. The
+// runner waits for that, posts the invocation's input to the runtime once,
+// and prints the response as a single Result line on stdout, where the
+// executor reads it back from the container log. A non-2xx response fails
+// the runner, so the Job's backoff limit retries it.
+package jobrunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	"github.com/fission/fission/pkg/svcinfo"
+)
+
+const (
+	// InputDir is where the invocation's input Secret is mounted in the
+	// runner container.
+	InputDir = "/jobinput"
+	// InputBodyKey and InputContentTypeKey are the input Secret's keys.
+	InputBodyKey        = "body"
+	InputContentTypeKey = "contentType"
+
+	// MaxResultBodyBytes bounds the response body a Result carries; the rest
+	// is cut and the Result marked Truncated.
+	MaxResultBodyBytes = 256 << 10
+
+	// ResultPrefix starts the Result line, which tells it apart from the
+	// runner's own log lines in the container log.
+	ResultPrefix = "fission-job-result: "
+
+	// readyPollInterval is how often the runner asks the fetcher whether the
+	// runtime is specialized.
+	readyPollInterval = 500 * time.Millisecond
+)
+
+// Result is the outcome of an invocation, printed by the runner as one JSON
+// line on stdout. Error is set, and StatusCode zero, when the runtime could
+// not be reached.
+type Result struct {
+	StatusCode int    `json:"statusCode,omitempty"`
+	Body       []byte `json:"body,omitempty"`
+	Truncated  bool   `json:"truncated,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ParseResult returns the Result in the runner's container log: its last
+// Result line. ok is false when there is none, e.g. the runner was
+// killed before it printed it.
+func ParseResult(out []byte) (res Result, ok bool) {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line, found := strings.CutPrefix(strings.TrimSpace(lines[i]), ResultPrefix)
+		if !found {
+			continue
+		}
+		if err := json.Unmarshal([]byte(line), &res); err == nil {
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+// Options configures Run. Zero values select the pod-local defaults.
+type Options struct {
+	FetcherURL string
+	RuntimeURL string
+	InputDir   string
+	Out        io.Writer
+}
+
+func (o *Options) defaults() {
+	if o.FetcherURL == "" {
+		o.FetcherURL = "http://127.0.0.1:" + strconv.Itoa(svcinfo.PortFetcher)
+	}
+	if o.RuntimeURL == "" {
+		o.RuntimeURL = "http://127.0.0.1:" + strconv.Itoa(svcinfo.PortEnvRuntime)
+	}
+	if o.InputDir == "" {
+		o.InputDir = InputDir
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+}
+
+// Run runs the invocation once and prints its Result. It returns an error
+// when the runtime could not be reached or answered with a non-2xx status.
+func Run(ctx context.Context, logger logr.Logger, opts Options) error {
+	logger = logger.WithName("jobrunner")
+	opts.defaults()
+
+	if err := waitSpecialized(ctx, opts.FetcherURL); err != nil {
+		return err
+	}
+	body, err := os.ReadFile(filepath.Join(opts.InputDir, InputBodyKey))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading job input: %w", err)
+	}
+	contentType, err := os.ReadFile(filepath.Join(opts.InputDir, InputContentTypeKey))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading job input: %w", err)
+	}
+
+	logger.Info("invoking function", "bodyBytes", len(body))
+	res, err := invoke(ctx, opts.RuntimeURL, body, string(contentType))
+	if err != nil {
+		res = Result{Error: err.Error()}
+	}
+	line, merr := json.Marshal(res)
+	if merr != nil {
+		return fmt.Errorf("error encoding job result: %w", merr)
+	}
+	if _, werr := fmt.Fprintf(opts.Out, "%s%s\n", ResultPrefix, line); werr != nil {
+		return fmt.Errorf("error writing job result: %w", werr)
+	}
+	if err != nil {
+		return fmt.Errorf("error invoking function: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("function returned status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// waitSpecialized waits until the fetcher reports the runtime specialized, or
+// ctx is done. The Job's active deadline bounds the wait.
+func waitSpecialized(ctx context.Context, fetcherURL string) error {
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fetcherURL+"/readiness-healthz", nil)
+		if err != nil {
+			return err
+		}
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the function to be specialized: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func invoke(ctx context.Context, runtimeURL string, body []byte, contentType string) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, runtimeURL+"/", bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(io.LimitReader(resp.Body, MaxResultBodyBytes+1))
+	if err != nil {
+		return Result{}, fmt.Errorf("error reading function response: %w", err)
+	}
+	res := Result{StatusCode: resp.StatusCode, Body: out}
+	if len(out) > MaxResultBodyBytes {
+		res.Body = out[:MaxResultBodyBytes]
+		res.Truncated = true
+	}
+	return res, nil
+}
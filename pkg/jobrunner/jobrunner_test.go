@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jobrunner
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeInput(t *testing.T, body, contentType string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, InputBodyKey), []byte(body), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, InputContentTypeKey), []byte(contentType), 0o600))
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	var polls atomic.Int32
+	fetcherSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Not specialized on the first poll.
+		if polls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer fetcherSrv.Close()
+
+	var gotBody, gotType string
+	runtimeSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody, gotType = string(b), r.Header.Get("Content-Type")
+		_, _ = w.Write([]byte("report ready"))
+	}))
+	defer runtimeSrv.Close()
+
+	var out bytes.Buffer
+	err := Run(t.Context(), logr.Discard(), Options{
+		FetcherURL: fetcherSrv.URL,
+		RuntimeURL: runtimeSrv.URL,
+		InputDir:   writeInput(t, `{"month":"2026-09"}`, "application/json"),
+		Out:        &out,
+	})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, polls.Load(), int32(2))
+	assert.Equal(t, `{"month":"2026-09"}`, gotBody)
+	assert.Equal(t, "application/json", gotType)
+
+	res, ok := ParseResult(append([]byte("runtime log line\n"), out.Bytes()...))
+	require.True(t, ok)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "report ready", string(res.Body))
+	assert.False(t, res.Truncated)
+}
+
+func TestRunFunctionError(t *testing.T) {
+	fetcherSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer fetcherSrv.Close()
+	runtimeSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer runtimeSrv.Close()
+
+	var out bytes.Buffer
+	err := Run(t.Context(), logr.Discard(), Options{
+		FetcherURL: fetcherSrv.URL,
+		RuntimeURL: runtimeSrv.URL,
+		InputDir:   writeInput(t, "", ""),
+		Out:        &out,
+	})
+	require.Error(t, err, "a failed invocation fails the pod")
+
+	res, ok := ParseResult(out.Bytes())
+	require.True(t, ok, "the result is printed even on failure")
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+}
+
+func TestParseResultMissing(t *testing.T) {
+	_, ok := ParseResult([]byte("starting\n" + ResultPrefix + "{not json\n{\"statusCode\":200}\n"))
+	assert.False(t, ok)
+	_, ok = ParseResult(nil)
+	assert.False(t, ok)
+}
@@ -116,7 +116,7 @@ func TestConsumerKillsMalformedJob(t *testing.T) {
 	// The poison job dead-letters immediately (retries cannot fix the bytes)
 	// and stays inspectable.
 	require.Eventually(t, func() bool {
-		dead, derr := q.DeadLetters(t.Context(), c.queue, statestore.Page{})
+		dead, derr := q.DeadLetters(t.Context(), c.queue, statestore.Page{}, statestore.DeadLetterFilter{})
 		return derr == nil && len(dead) == 1
 	}, 5*time.Second, 10*time.Millisecond)
 	assert.Empty(t, broker.published(), "nothing was published for the malformed job")
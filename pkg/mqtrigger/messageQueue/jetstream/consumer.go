@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jetstream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
+	"github.com/fission/fission/pkg/mqtrigger"
+	"github.com/fission/fission/pkg/utils"
+	"github.com/fission/fission/pkg/utils/httpx"
+)
+
+// natsIdleConnsPerHost sizes the idle pool to the single router-internal host;
+// each subscription delivers one message at a time, so the pool only needs to
+// cover the triggers a head hosts.
+const natsIdleConnsPerHost = 64
+
+// newHTTPClient builds the function-invocation client: /fission-function/...
+// lives only on the router's internal listener (GHSA-3g33-6vg6-27m8), so
+// requests are HMAC-signed with the ServiceRouterInternal key when
+// FISSION_INTERNAL_AUTH_SECRET is set (the kafka provider's client).
+func newHTTPClient() *http.Client {
+	var rt http.RoundTripper = httpx.PooledTransport(natsIdleConnsPerHost)
+	if master := os.Getenv("FISSION_INTERNAL_AUTH_SECRET"); master != "" {
+		rt = hmacauth.ServiceSigner([]byte(master), hmacauth.ServiceRouterInternal, rt, time.Now)
+	}
+	return &http.Client{Transport: rt}
+}
+
+// subscription is one trigger's consumer. The ConsumeContext calls handle one
+// message at a time, so per-subscription delivery is ordered and sequential,
+// like a kafka partition.
+type subscription struct {
+	j       *JetStream
+	trigger *fv1.MessageQueueTrigger
+	logger  logr.Logger
+	fnURL   string
+	cc      jetstream.ConsumeContext
+
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newSubscription(j *JetStream, trigger *fv1.MessageQueueTrigger) *subscription {
+	return &subscription{
+		j:       j,
+		trigger: trigger,
+		logger:  j.logger.WithValues("trigger", trigger.Name, "namespace", trigger.Namespace, "subject", trigger.Spec.Topic),
+		// RFC-0025: alias/version suffixes resolve router-side.
+		fnURL: j.routerURL + "/" + strings.TrimPrefix(utils.UrlForFunctionReference(trigger.Spec.FunctionReference, trigger.Namespace), "/"),
+		done:  make(chan struct{}),
+	}
+}
+
+// start marks the trigger live and ties the subscription to ctx.
+func (sub *subscription) start(ctx context.Context) {
+	mqtrigger.SetTriggerStatus(sub.trigger.Name, sub.trigger.Namespace)
+	mqtrigger.IncreaseInprocessCount()
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = sub.Stop()
+		case <-sub.done:
+		}
+	}()
+}
+
+// Stop ends consumption and waits for an in-flight message handler to return.
+// Unacked messages redeliver to the durable consumer's next subscription.
+func (sub *subscription) Stop() error {
+	sub.stopOnce.Do(func() {
+		sub.cc.Stop()
+		<-sub.cc.Closed()
+		mqtrigger.ResetTriggerStatus(sub.trigger.Name, sub.trigger.Namespace)
+		mqtrigger.DecreaseInprocessCount()
+		close(sub.done)
+	})
+	return nil
+}
+
+// Done returns a channel that is closed when the subscription is stopped.
+func (sub *subscription) Done() <-chan struct{} {
+	return sub.done
+}
+
+// handle makes ONE delivery attempt and settles the message: Ack on success,
+// Nak with backoff while deliveries remain within MaxRetries, and on the
+// delivery that exhausts them ErrorTopic-then-Term. An ErrorTopic publish
+// failure Naks instead of terminating, so the message is retried rather than
+// lost (the statestore provider's E5 rule) — each redelivery re-invokes the
+// function once more before retrying the publish.
+func (sub *subscription) handle(ctx context.Context, msg jetstream.Msg) {
+	meta, err := msg.Metadata()
+	if err != nil {
+		sub.logger.Error(err, "reading jetstream message metadata; terminating message")
+		_ = msg.Term()
+		return
+	}
+	mqtrigger.IncreaseMessageCount(sub.trigger.Name, sub.trigger.Namespace)
+	mqtrigger.SetMessageLagCount(sub.trigger.Name, sub.trigger.Namespace, sub.trigger.Spec.Topic, "0", int64(meta.NumPending))
+
+	delivered := int(meta.NumDelivered)
+	deliverErr := sub.deliverWithHeartbeat(ctx, msg)
+	if deliverErr == nil {
+		sub.ack(msg, meta)
+		return
+	}
+	if ctx.Err() != nil {
+		return // shutting down: the unacked message redelivers after AckWait
+	}
+	if delivered <= sub.trigger.Spec.MaxRetries {
+		sub.logger.Error(deliverErr, "function invocation failed; will redeliver",
+			"streamSeq", meta.Sequence.Stream, "delivery", delivered, "maxRetries", sub.trigger.Spec.MaxRetries)
+		_ = msg.NakWithDelay(sub.backoff(delivered))
+		return
+	}
+	if sub.trigger.Spec.ErrorTopic == "" {
+		sub.logger.Error(deliverErr, "message exhausted retries and no error topic is set; dropping",
+			"streamSeq", meta.Sequence.Stream, "maxRetries", sub.trigger.Spec.MaxRetries)
+		_ = msg.TermWithReason("retries exhausted")
+		return
+	}
+	header := copyHeader(msg.Headers())
+	header.Set("Fission-Error", deliverErr.Error())
+	header.Set("Fission-Source-Subject", msg.Subject())
+	header.Set("Fission-Deliveries", strconv.Itoa(delivered))
+	if err := sub.j.publish(ctx, sub.trigger.Spec.ErrorTopic, header, msg.Data()); err != nil {
+		sub.logger.Error(err, "publishing exhausted message to error topic; will redeliver",
+			"errorSubject", sub.trigger.Spec.ErrorTopic, "streamSeq", meta.Sequence.Stream)
+		_ = msg.NakWithDelay(sub.backoff(delivered))
+		return
+	}
+	sub.logger.Info("message exhausted retries; routed to error topic",
+		"streamSeq", meta.Sequence.Stream, "errorSubject", sub.trigger.Spec.ErrorTopic, "maxRetries", sub.trigger.Spec.MaxRetries)
+	_ = msg.TermWithReason("routed to error topic")
+}
+
+func (sub *subscription) ack(msg jetstream.Msg, meta *jetstream.MsgMetadata) {
+	if err := msg.Ack(); err != nil {
+		sub.logger.Error(err, "acking message (it will redeliver — possible duplicate invocation)",
+			"streamSeq", meta.Sequence.Stream)
+	}
+}
+
+// backoff is the Nak delay after the given delivery: retryBackoff doubling
+// per delivery, capped at maxRetryBackoff.
+func (sub *subscription) backoff(delivered int) time.Duration {
+	d := sub.j.retryBackoff
+	for range delivered - 1 {
+		if d >= maxRetryBackoff {
+			break
+		}
+		d *= 2
+	}
+	return min(d, maxRetryBackoff)
+}
+
+// deliverWithHeartbeat runs deliver while extending the ack deadline, so a
+// function slower than AckWait is not redelivered concurrently.
+func (sub *subscription) deliverWithHeartbeat(ctx context.Context, msg jetstream.Msg) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		t := time.NewTicker(ackWait / 3)
+		defer t.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-t.C:
+				_ = msg.InProgress()
+			}
+		}
+	}()
+	return sub.deliver(ctx, msg)
+}
+
+// deliver POSTs the message to the function via the router internal listener.
+// Success is any 2xx (the statestore provider's rule). On success the response
+// body is published to the ResponseTopic, best-effort: the function already
+// ran, and redelivering to re-publish would re-run it. A response over
+// maxResponseBytes is logged and not published.
+func (sub *subscription) deliver(ctx context.Context, msg jetstream.Msg) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.fnURL, bytes.NewReader(msg.Data()))
+	if err != nil {
+		return fmt.Errorf("building delivery request: %w", err)
+	}
+	for k, vs := range msg.Headers() {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if req.Header.Get("Content-Type") == "" && sub.trigger.Spec.ContentType != "" {
+		req.Header.Set("Content-Type", sub.trigger.Spec.ContentType)
+	}
+	req.Header.Set("X-Fission-MQTrigger-Topic", sub.trigger.Spec.Topic)
+	req.Header.Set("X-Fission-MQTrigger-RespTopic", sub.trigger.Spec.ResponseTopic)
+	req.Header.Set("X-Fission-MQTrigger-ErrorTopic", sub.trigger.Spec.ErrorTopic)
+
+	resp, err := sub.j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("invoking function: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, rerr := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("function returned %s", resp.Status)
+	}
+	if sub.trigger.Spec.ResponseTopic == "" {
+		return nil
+	}
+	if rerr != nil {
+		sub.logger.Error(rerr, "reading function response; response topic skipped")
+		return nil
+	}
+	if len(body) > maxResponseBytes {
+		sub.logger.Error(nil, "function response exceeds the response topic limit; response topic skipped",
+			"limitBytes", maxResponseBytes, "responseSubject", sub.trigger.Spec.ResponseTopic)
+		return nil
+	}
+	header := nats.Header{}
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			header.Add(k, v)
+		}
+	}
+	if err := sub.j.publish(ctx, sub.trigger.Spec.ResponseTopic, header, body); err != nil {
+		sub.logger.Error(err, "publishing response to response topic (best-effort)",
+			"responseSubject", sub.trigger.Spec.ResponseTopic)
+	}
+	return nil
+}
+
+func copyHeader(h nats.Header) nats.Header {
+	out := nats.Header{}
+	for k, vs := range h {
+		out[k] = append([]string(nil), vs...)
+	}
+	return out
+}
@@ -0,0 +1,358 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jetstream implements the NATS JetStream MessageQueue provider
+// (messageQueueType: nats-jetstream).
+//
+// The provider mirrors the kafka provider's shape — one classic mqt head per MQ
+// type, leader-only subscriptions, HMAC-signed delivery to the router internal
+// listener, ResponseTopic on success and ErrorTopic on exhaustion — with the
+// retries delegated to the broker: each trigger is a durable pull consumer
+// (named by the trigger UID, like the kafka consumer group) with explicit acks.
+// A failed delivery is Nak'd with a backoff and JetStream redelivers it; the
+// delivery count JetStream stamps on the message is checked against
+// MaxRetries, and the delivery that exhausts the budget is routed to the
+// ErrorTopic and terminated. The consumer's own MaxDeliver stays unlimited so
+// that a failing ErrorTopic publish can keep being retried instead of the
+// broker silently giving up on the message. A head crash mid-delivery simply
+// redelivers after AckWait — at-least-once, like every other provider.
+//
+// Topics are NATS subjects. A subject with no stream bound to it gets one
+// (FISSION-<subject, dots as underscores>, limits retention, file storage)
+// unless MESSAGE_QUEUE_NATS_AUTO_CREATE_STREAMS=false — parity with the
+// broker-side topic auto-creation kafka installs usually run with. Operators
+// who manage streams themselves bind the subjects and disable it.
+package jetstream
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nats-io/nkeys"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/mqtrigger/egress"
+	"github.com/fission/fission/pkg/mqtrigger/factory"
+	"github.com/fission/fission/pkg/mqtrigger/messageQueue"
+	"github.com/fission/fission/pkg/mqtrigger/mqpub"
+	"github.com/fission/fission/pkg/mqtrigger/validator"
+)
+
+func init() {
+	factory.Register(fv1.MessageQueueTypeNatsJetStream, &Factory{})
+	validator.Register(fv1.MessageQueueTypeNatsJetStream, IsTopicValid)
+}
+
+const (
+	// ackWait is the consumer's redelivery timer. A delivery in flight sends
+	// InProgress every ackWait/3, so a slow function never trips it; only a
+	// dead head does.
+	ackWait = 30 * time.Second
+	// maxRetryBackoff caps the Nak delay, which doubles per delivery from
+	// JetStream.retryBackoff.
+	maxRetryBackoff = 30 * time.Second
+	// streamOpTimeout bounds the JetStream API calls made at subscribe time
+	// and when an egress job first meets a subject.
+	streamOpTimeout = 10 * time.Second
+	// streamPrefix names auto-created streams.
+	streamPrefix = "FISSION-"
+	// maxResponseBytes is the largest function response published to the
+	// ResponseTopic; a larger one is skipped rather than cut short.
+	maxResponseBytes = 1 << 20
+	// consumerInactiveThreshold is how long the server keeps a durable
+	// consumer no head is pulling from: long enough to ride out a restart or
+	// rollout, and bounded so a deleted trigger's consumer is reaped — the
+	// kafka default for an idle group's offsets.
+	consumerInactiveThreshold = 7 * 24 * time.Hour
+)
+
+type (
+	// JetStream is the provider: one NATS connection shared by every
+	// subscription and the egress publisher.
+	JetStream struct {
+		logger    logr.Logger
+		routerURL string
+		nc        *nats.Conn
+		js        jetstream.JetStream
+		client    *http.Client
+
+		autoCreateStreams bool
+		// streams caches subject → stream name for subjects already bound.
+		streams sync.Map
+		// retryBackoff is the first Nak delay (a field so tests can tighten it).
+		retryBackoff time.Duration
+	}
+
+	Factory struct{}
+)
+
+func (factory *Factory) Create(logger logr.Logger, mqCfg messageQueue.Config, routerURL string) (messageQueue.MessageQueue, error) {
+	j, err := New(logger, mqCfg, routerURL)
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// New connects to the NATS servers in mqCfg.Url (comma-separated). The
+// connection retries in the background, so a NATS outage does not fail head
+// startup; subscriptions surface the error and the reconciler retries them.
+//
+// Authentication comes from the mounted secrets: "creds" (a decorated user
+// JWT + seed file), "token", or "username"/"password"; TLS_ENABLED adds mutual
+// TLS from "caCert"/"userCert"/"userKey", exactly as the kafka head reads them.
+func New(logger logr.Logger, mqCfg messageQueue.Config, routerURL string) (*JetStream, error) {
+	if len(routerURL) == 0 || len(mqCfg.Url) == 0 {
+		return nil, errors.New("the router URL or MQ URL is empty")
+	}
+	opts, err := connectOptions(logger, mqCfg.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	nc, err := nats.Connect(mqCfg.Url, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to nats: %w", err)
+	}
+	js, err := jetstream.New(nc)
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("creating jetstream context: %w", err)
+	}
+	autoCreate := true
+	if v := os.Getenv("MESSAGE_QUEUE_NATS_AUTO_CREATE_STREAMS"); v != "" {
+		if autoCreate, err = strconv.ParseBool(v); err != nil {
+			nc.Close()
+			return nil, fmt.Errorf("invalid MESSAGE_QUEUE_NATS_AUTO_CREATE_STREAMS=%q: %w", v, err)
+		}
+	}
+	logger.Info("created nats jetstream queue", "servers", mqCfg.Url, "autoCreateStreams", autoCreate)
+	return &JetStream{
+		logger:            logger.WithName("nats-jetstream"),
+		routerURL:         routerURL,
+		nc:                nc,
+		js:                js,
+		client:            newHTTPClient(),
+		autoCreateStreams: autoCreate,
+		retryBackoff:      time.Second,
+	}, nil
+}
+
+func connectOptions(logger logr.Logger, secrets map[string][]byte) ([]nats.Option, error) {
+	opts := []nats.Option{
+		nats.Name("fission-mqtrigger"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Error(err, "disconnected from nats")
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			logger.Info("reconnected to nats", "server", nc.ConnectedUrlRedacted())
+		}),
+	}
+	switch {
+	case len(secrets["creds"]) > 0:
+		userJWT, err := nkeys.ParseDecoratedJWT(secrets["creds"])
+		if err != nil {
+			return nil, fmt.Errorf("parsing nats creds: %w", err)
+		}
+		kp, err := nkeys.ParseDecoratedNKey(secrets["creds"])
+		if err != nil {
+			return nil, fmt.Errorf("parsing nats creds seed: %w", err)
+		}
+		seed, err := kp.Seed()
+		if err != nil {
+			return nil, fmt.Errorf("parsing nats creds seed: %w", err)
+		}
+		opts = append(opts, nats.UserJWTAndSeed(userJWT, string(seed)))
+	case len(secrets["token"]) > 0:
+		opts = append(opts, nats.Token(strings.TrimSpace(string(secrets["token"]))))
+	case len(secrets["username"]) > 0:
+		opts = append(opts, nats.UserInfo(strings.TrimSpace(string(secrets["username"])), strings.TrimSpace(string(secrets["password"]))))
+	}
+	if enabled, _ := strconv.ParseBool(os.Getenv("TLS_ENABLED")); enabled {
+		tlsConfig, err := tlsConfig(logger, secrets)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+	return opts, nil
+}
+
+func tlsConfig(logger logr.Logger, secrets map[string][]byte) (*tls.Config, error) {
+	if secrets == nil {
+		return nil, errors.New("no secrets were loaded")
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(secrets["userCert"]) > 0 {
+		cert, err := tls.X509KeyPair(secrets["userCert"], secrets["userKey"])
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if v := os.Getenv("INSECURE_SKIP_VERIFY"); v != "" {
+		skipVerify, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid INSECURE_SKIP_VERIFY=%q: %w", v, err)
+		}
+		if skipVerify {
+			logger.Info("WARNING: TLS certificate verification disabled for NATS (INSECURE_SKIP_VERIFY=true); use only for self-signed dev clusters")
+		}
+		cfg.InsecureSkipVerify = skipVerify
+	}
+	if len(secrets["caCert"]) > 0 {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(secrets["caCert"])
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// streamFor returns the stream bound to subject, creating one when none is and
+// auto-creation is on. A name clash with an existing auto-created stream (two
+// subjects that differ only in "." versus "_") surfaces as the create error.
+func (j *JetStream) streamFor(ctx context.Context, subject string) (string, error) {
+	if name, ok := j.streams.Load(subject); ok {
+		return name.(string), nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, streamOpTimeout)
+	defer cancel()
+	name, err := j.js.StreamNameBySubject(ctx, subject)
+	if errors.Is(err, jetstream.ErrStreamNotFound) {
+		if !j.autoCreateStreams {
+			return "", fmt.Errorf("no jetstream stream is bound to subject %q (stream auto-creation is disabled)", subject)
+		}
+		name = streamPrefix + strings.ReplaceAll(subject, ".", "_")
+		_, err = j.js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:        name,
+			Description: "created by fission for subject " + subject,
+			Subjects:    []string{subject},
+			Retention:   jetstream.LimitsPolicy,
+			Storage:     jetstream.FileStorage,
+		})
+		if err == nil {
+			j.logger.Info("created jetstream stream", "stream", name, "subject", subject)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolving jetstream stream for subject %q: %w", subject, err)
+	}
+	j.streams.Store(subject, name)
+	return name, nil
+}
+
+// publish writes one message to subject and waits for the stream ack — a nil
+// error is a broker ack.
+func (j *JetStream) publish(ctx context.Context, subject string, header nats.Header, data []byte) error {
+	if _, err := j.streamFor(ctx, subject); err != nil {
+		return err
+	}
+	_, err := j.js.PublishMsg(ctx, &nats.Msg{Subject: subject, Header: header, Data: data})
+	return err
+}
+
+// NewEgressPublisher implements egress.BrokerPublisherProvider: egress jobs are
+// published on the provider's connection, each awaiting its stream ack within
+// the consumer's publish timeout. The closer drains the connection on head
+// shutdown so in-flight acks land.
+func (j *JetStream) NewEgressPublisher() (egress.PublishFunc, io.Closer, error) {
+	publish := func(ctx context.Context, job mqpub.EgressJob) error {
+		// NATS subjects are flat, like kafka topics: the fission topic name is
+		// the subject as-is. The headers are provenance/metadata only —
+		// Fission-Namespace is caller-supplied and NOT an authenticated tenant
+		// identity; a downstream consumer must not authorize on it.
+		header := nats.Header{}
+		if job.ContentType != "" {
+			header.Set("Content-Type", job.ContentType)
+		}
+		header.Set("Fission-Namespace", job.Namespace)
+		return j.publish(ctx, job.Topic, header, job.Payload)
+	}
+	return publish, closerFunc(j.nc.Drain), nil
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// Subscribe creates (or updates) the trigger's durable consumer and starts
+// consuming. The consumer outlives the subscription — a restarted head resumes
+// where it left off, like a kafka consumer group — until it has been idle for
+// consumerInactiveThreshold, when the server deletes it. A new durable starts
+// at new messages, the kafka provider's default offset.
+func (j *JetStream) Subscribe(ctx context.Context, trigger *fv1.MessageQueueTrigger) (messageQueue.Subscription, error) {
+	if trigger.Spec.FunctionReference.Type != fv1.FunctionReferenceTypeFunctionName {
+		return nil, fmt.Errorf("nats-jetstream mq provider: unsupported function reference type %q for trigger %s",
+			trigger.Spec.FunctionReference.Type, trigger.Name)
+	}
+	if !IsTopicValid(trigger.Spec.Topic) {
+		return nil, fmt.Errorf("nats-jetstream mq provider: invalid subject %q", trigger.Spec.Topic)
+	}
+	stream, err := j.streamFor(ctx, trigger.Spec.Topic)
+	if err != nil {
+		return nil, err
+	}
+	opCtx, cancel := context.WithTimeout(ctx, streamOpTimeout)
+	defer cancel()
+	consumer, err := j.js.CreateOrUpdateConsumer(opCtx, stream, jetstream.ConsumerConfig{
+		Durable:           string(trigger.UID),
+		Description:       "fission messagequeuetrigger " + trigger.Namespace + "/" + trigger.Name,
+		FilterSubject:     trigger.Spec.Topic,
+		DeliverPolicy:     jetstream.DeliverNewPolicy,
+		AckPolicy:         jetstream.AckExplicitPolicy,
+		AckWait:           ackWait,
+		MaxDeliver:        -1,
+		InactiveThreshold: consumerInactiveThreshold,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("nats-jetstream mq provider: creating consumer for trigger %s: %w", trigger.Name, err)
+	}
+	sub := newSubscription(j, trigger)
+	// handle delivers one message at a time, so pull one at a time too: a
+	// prefetched batch would sit in the client with its AckWait running and
+	// redeliver behind a slow function.
+	cc, err := consumer.Consume(func(msg jetstream.Msg) { sub.handle(ctx, msg) },
+		jetstream.PullMaxMessages(1),
+		jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+			sub.logger.Error(err, "jetstream consume error")
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("nats-jetstream mq provider: consuming for trigger %s: %w", trigger.Name, err)
+	}
+	sub.cc = cc
+	sub.start(ctx)
+	j.logger.Info("subscribed", "trigger", trigger.Name, "namespace", trigger.Namespace,
+		"subject", trigger.Spec.Topic, "stream", stream, "responseSubject", trigger.Spec.ResponseTopic,
+		"errorSubject", trigger.Spec.ErrorTopic)
+	return sub, nil
+}
+
+// Unsubscribe stops consuming but keeps the durable consumer, so a resubscribe
+// resumes; the server reaps it after consumerInactiveThreshold.
+func (j *JetStream) Unsubscribe(sub messageQueue.Subscription) error {
+	return sub.Stop()
+}
+
+// IsTopicValid reports whether topic is a literal NATS subject fission can use
+// as a topic: the shared topic grammar (which already excludes the "*" and ">"
+// wildcards) with dot-separated, non-empty tokens.
+func IsTopicValid(topic string) bool {
+	return fv1.ValidateTopicNameForMQType("topic", fv1.MessageQueueTypeNatsJetStream, topic) == nil
+}
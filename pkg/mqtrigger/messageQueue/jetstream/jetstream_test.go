@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package jetstream
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/mqtrigger/messageQueue"
+	"github.com/fission/fission/pkg/mqtrigger/mqpub"
+)
+
+// runServer starts an embedded JetStream-enabled nats-server on a random port.
+func runServer(t *testing.T) string {
+	t.Helper()
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	require.NoError(t, err)
+	go s.Start()
+	require.True(t, s.ReadyForConnections(10*time.Second), "nats-server did not start")
+	t.Cleanup(s.Shutdown)
+	return s.ClientURL()
+}
+
+func newTestProvider(t *testing.T, routerURL string) *JetStream {
+	t.Helper()
+	j, err := New(logr.Discard(), messageQueue.Config{Url: runServer(t)}, routerURL)
+	require.NoError(t, err)
+	t.Cleanup(j.nc.Close)
+	j.retryBackoff = 10 * time.Millisecond
+	return j
+}
+
+func testTrigger(name, subject string, mutate func(*fv1.MessageQueueTrigger)) *fv1.MessageQueueTrigger {
+	tr := &fv1.MessageQueueTrigger{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", UID: types.UID("uid-" + name)},
+		Spec: fv1.MessageQueueTriggerSpec{
+			FunctionReference: fv1.FunctionReference{Type: fv1.FunctionReferenceTypeFunctionName, Name: "fn"},
+			MessageQueueType:  fv1.MessageQueueTypeNatsJetStream,
+			Topic:             subject,
+			ContentType:       "application/json",
+		},
+	}
+	if mutate != nil {
+		mutate(tr)
+	}
+	return tr
+}
+
+// fnEndpoint is a scripted function behind the router URL.
+type fnEndpoint struct {
+	mu     sync.Mutex
+	failN  int // fail the first N requests with 500
+	bodies []string
+	types  []string
+}
+
+func (f *fnEndpoint) handler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.bodies = append(f.bodies, string(body))
+	f.types = append(f.types, r.Header.Get("Content-Type"))
+	fail := f.failN > 0
+	if fail {
+		f.failN--
+	}
+	f.mu.Unlock()
+	if fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte("re:" + string(body)))
+}
+
+func (f *fnEndpoint) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.bodies)
+}
+
+// readSubject fetches the next message on subject through a throwaway
+// ephemeral consumer.
+func readSubject(t *testing.T, j *JetStream, subject string) jetstream.Msg {
+	t.Helper()
+	stream, err := j.streamFor(t.Context(), subject)
+	require.NoError(t, err)
+	cons, err := j.js.CreateOrUpdateConsumer(t.Context(), stream, jetstream.ConsumerConfig{FilterSubject: subject, AckPolicy: jetstream.AckNonePolicy})
+	require.NoError(t, err)
+	var msg jetstream.Msg
+	require.Eventually(t, func() bool {
+		batch, err := cons.Fetch(1, jetstream.FetchMaxWait(100*time.Millisecond))
+		if err != nil {
+			return false
+		}
+		for m := range batch.Messages() {
+			msg = m
+		}
+		return msg != nil
+	}, 10*time.Second, 10*time.Millisecond)
+	return msg
+}
+
+func subscribe(t *testing.T, j *JetStream, trigger *fv1.MessageQueueTrigger) messageQueue.Subscription {
+	t.Helper()
+	sub, err := j.Subscribe(t.Context(), trigger)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = j.Unsubscribe(sub) })
+	return sub
+}
+
+func TestIsTopicValid(t *testing.T) {
+	t.Parallel()
+	for topic, want := range map[string]bool{
+		"orders":         true,
+		"orders.created": true,
+		"a-b_c.d":        true,
+		"":               false,
+		".orders":        false,
+		"orders.":        false,
+		"orders..v2":     false,
+		"orders.*":       false,
+		"orders.>":       false,
+		"a b":            false,
+	} {
+		assert.Equal(t, want, IsTopicValid(topic), topic)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	_, err := New(logr.Discard(), messageQueue.Config{Url: "nats://127.0.0.1:4222"}, "")
+	require.Error(t, err, "router URL is required")
+	_, err = New(logr.Discard(), messageQueue.Config{}, "http://router")
+	require.Error(t, err, "MQ URL is required")
+}
+
+func TestSubscribeDeliversAndPublishesResponse(t *testing.T) {
+	t.Parallel()
+	fn := &fnEndpoint{}
+	srv := httptest.NewServer(http.HandlerFunc(fn.handler))
+	defer srv.Close()
+	j := newTestProvider(t, srv.URL)
+
+	subscribe(t, j, testTrigger("t1", "orders.created", func(tr *fv1.MessageQueueTrigger) {
+		tr.Spec.ResponseTopic = "orders.priced"
+	}))
+	require.NoError(t, j.publish(t.Context(), "orders.created", nil, []byte("o1")))
+
+	resp := readSubject(t, j, "orders.priced")
+	assert.Equal(t, "re:o1", string(resp.Data()))
+	assert.Equal(t, "text/plain", resp.Headers().Get("Content-Type"), "response headers travel")
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+	assert.Equal(t, "application/json", fn.types[0], "the trigger's content type applies when the message has none")
+}
+
+// TestRedeliveryExhaustsToErrorTopic: each failed delivery is Nak'd and
+// redelivered by JetStream; the (MaxRetries+1)th failure routes the original
+// payload to the error subject and stops.
+func TestRedeliveryExhaustsToErrorTopic(t *testing.T) {
+	t.Parallel()
+	fn := &fnEndpoint{failN: 100}
+	srv := httptest.NewServer(http.HandlerFunc(fn.handler))
+	defer srv.Close()
+	j := newTestProvider(t, srv.URL)
+
+	subscribe(t, j, testTrigger("t1", "orders", func(tr *fv1.MessageQueueTrigger) {
+		tr.Spec.MaxRetries = 2
+		tr.Spec.ErrorTopic = "orders-errors"
+	}))
+	require.NoError(t, j.publish(t.Context(), "orders", nil, []byte("bad")))
+
+	errMsg := readSubject(t, j, "orders-errors")
+	assert.Equal(t, "bad", string(errMsg.Data()))
+	assert.Equal(t, "3", errMsg.Headers().Get("Fission-Deliveries"))
+	assert.Equal(t, "orders", errMsg.Headers().Get("Fission-Source-Subject"))
+	assert.Contains(t, errMsg.Headers().Get("Fission-Error"), "500")
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 3, fn.calls(), "MaxRetries=2 is three deliveries, then no more")
+}
+
+func TestRedeliverySucceedsWithinBudget(t *testing.T) {
+	t.Parallel()
+	fn := &fnEndpoint{failN: 1}
+	srv := httptest.NewServer(http.HandlerFunc(fn.handler))
+	defer srv.Close()
+	j := newTestProvider(t, srv.URL)
+
+	subscribe(t, j, testTrigger("t1", "orders", func(tr *fv1.MessageQueueTrigger) {
+		tr.Spec.MaxRetries = 1
+		tr.Spec.ResponseTopic = "orders-out"
+	}))
+	require.NoError(t, j.publish(t.Context(), "orders", nil, []byte("o1")))
+	assert.Equal(t, "re:o1", string(readSubject(t, j, "orders-out").Data()))
+	assert.Equal(t, 2, fn.calls())
+}
+
+// TestOversizedResponseSkipsResponseTopic: a response over maxResponseBytes
+// is not published cut short; the message still acks and the next response
+// goes out.
+func TestOversizedResponseSkipsResponseTopic(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "big" {
+			_, _ = w.Write(make([]byte, maxResponseBytes+1))
+			return
+		}
+		_, _ = w.Write([]byte("re:" + string(body)))
+	}))
+	defer srv.Close()
+	j := newTestProvider(t, srv.URL)
+
+	subscribe(t, j, testTrigger("t1", "orders", func(tr *fv1.MessageQueueTrigger) {
+		tr.Spec.ResponseTopic = "orders-out"
+	}))
+	require.NoError(t, j.publish(t.Context(), "orders", nil, []byte("big")))
+	require.NoError(t, j.publish(t.Context(), "orders", nil, []byte("small")))
+	assert.Equal(t, "re:small", string(readSubject(t, j, "orders-out").Data()),
+		"the oversized response is skipped, not truncated")
+}
+
+// TestDurableConsumerResumes: the consumer is durable per trigger UID, so a
+// message published while the trigger is unsubscribed is delivered when it
+// subscribes again. The server reaps a consumer idle past
+// consumerInactiveThreshold, so a deleted trigger's durable is not orphaned.
+func TestDurableConsumerResumes(t *testing.T) {
+	t.Parallel()
+	fn := &fnEndpoint{}
+	srv := httptest.NewServer(http.HandlerFunc(fn.handler))
+	defer srv.Close()
+	j := newTestProvider(t, srv.URL)
+	trigger := testTrigger("t1", "orders", nil)
+
+	sub, err := j.Subscribe(t.Context(), trigger)
+	require.NoError(t, err)
+	require.NoError(t, j.Unsubscribe(sub))
+	<-sub.Done()
+	stream, err := j.streamFor(t.Context(), "orders")
+	require.NoError(t, err)
+	cons, err := j.js.Consumer(t.Context(), stream, string(trigger.UID))
+	require.NoError(t, err, "the durable survives unsubscribe")
+	assert.Equal(t, consumerInactiveThreshold, cons.CachedInfo().Config.InactiveThreshold)
+	require.NoError(t, j.publish(t.Context(), "orders", nil, []byte("while-away")))
+
+	subscribe(t, j, trigger)
+	require.Eventually(t, func() bool { return fn.calls() == 1 }, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestSubscribeStopsWithContext(t *testing.T) {
+	t.Parallel()
+	j := newTestProvider(t, "http://127.0.0.1:1")
+	ctx, cancel := context.WithCancel(t.Context())
+	sub, err := j.Subscribe(ctx, testTrigger("t1", "orders", nil))
+	require.NoError(t, err)
+	cancel()
+	select {
+	case <-sub.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("subscription did not stop with its context")
+	}
+}
+
+func TestStreamAutoCreateDisabled(t *testing.T) {
+	t.Parallel()
+	j := newTestProvider(t, "http://127.0.0.1:1")
+	j.autoCreateStreams = false
+	_, err := j.Subscribe(t.Context(), testTrigger("t1", "orders", nil))
+	require.ErrorContains(t, err, "auto-creation is disabled")
+}
+
+func TestEgressPublisher(t *testing.T) {
+	t.Parallel()
+	j := newTestProvider(t, "http://127.0.0.1:1")
+	publish, _, err := j.NewEgressPublisher()
+	require.NoError(t, err)
+	require.NoError(t, publish(t.Context(), mqpub.EgressJob{Namespace: "ns1", Topic: "orders.out", ContentType: "application/json", Payload: []byte(`{"a":1}`)}))
+
+	msg := readSubject(t, j, "orders.out")
+	assert.JSONEq(t, `{"a":1}`, string(msg.Data()))
+	assert.Equal(t, "application/json", msg.Headers().Get("Content-Type"))
+	assert.Equal(t, "ns1", msg.Headers().Get("Fission-Namespace"))
+	stream, err := j.streamFor(t.Context(), "orders.out")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stream, streamPrefix))
+}
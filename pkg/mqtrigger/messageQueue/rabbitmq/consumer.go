@@ -0,0 +1,332 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rabbitmq
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
+	"github.com/fission/fission/pkg/mqtrigger"
+	"github.com/fission/fission/pkg/utils"
+	"github.com/fission/fission/pkg/utils/httpx"
+)
+
+// rabbitIdleConnsPerHost sizes the idle pool to the single router-internal
+// host: up to prefetch deliveries per subscription are in flight at once.
+const rabbitIdleConnsPerHost = 64
+
+// newHTTPClient builds the function-invocation client: /fission-function/...
+// lives only on the router's internal listener (GHSA-3g33-6vg6-27m8), so
+// requests are HMAC-signed with the ServiceRouterInternal key when
+// FISSION_INTERNAL_AUTH_SECRET is set (the kafka provider's client).
+func newHTTPClient() *http.Client {
+	var rt http.RoundTripper = httpx.PooledTransport(rabbitIdleConnsPerHost)
+	if master := os.Getenv("FISSION_INTERNAL_AUTH_SECRET"); master != "" {
+		rt = hmacauth.ServiceSigner([]byte(master), hmacauth.ServiceRouterInternal, rt, time.Now)
+	}
+	return &http.Client{Transport: rt}
+}
+
+// replyPublisher publishes a function's reply; *confirmPublisher in production.
+type replyPublisher interface {
+	publish(ctx context.Context, exchange, key string, msg amqp.Publishing) error
+	Close() error
+}
+
+// subscription is one trigger's consumer: a channel with prefetch workers
+// ranging over its deliveries, re-opened when the broker closes it.
+type subscription struct {
+	r       *RabbitMQ
+	trigger *fv1.MessageQueueTrigger
+	binding binding
+	logger  logr.Logger
+	fnURL   string
+	replies replyPublisher
+
+	cancel   context.CancelFunc
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newSubscription(r *RabbitMQ, trigger *fv1.MessageQueueTrigger, b binding) *subscription {
+	return &subscription{
+		r:       r,
+		trigger: trigger,
+		binding: b,
+		logger:  r.logger.WithValues("trigger", trigger.Name, "namespace", trigger.Namespace, "queue", trigger.Spec.Topic),
+		// RFC-0025: alias/version suffixes resolve router-side.
+		fnURL:   r.routerURL + "/" + strings.TrimPrefix(utils.UrlForFunctionReference(trigger.Spec.FunctionReference, trigger.Namespace), "/"),
+		replies: newConfirmPublisher(r),
+		done:    make(chan struct{}),
+	}
+}
+
+// open declares the topology on a fresh channel and starts consuming. The
+// consumer is cancelled, and the deliveries channel closed, when ctx is.
+func (sub *subscription) open(ctx context.Context) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	ch, err := sub.r.channel()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sub.binding.apply(ch, sub.trigger); err != nil {
+		_ = ch.Close()
+		return nil, nil, err
+	}
+	if err := ch.Qos(sub.binding.prefetch, 0, false); err != nil {
+		_ = ch.Close()
+		return nil, nil, fmt.Errorf("setting prefetch: %w", err)
+	}
+	deliveries, err := ch.ConsumeWithContext(ctx, sub.trigger.Spec.Topic, "fission-"+string(sub.trigger.UID),
+		false, false, false, false, nil)
+	if err != nil {
+		_ = ch.Close()
+		return nil, nil, fmt.Errorf("consuming queue %q: %w", sub.trigger.Spec.Topic, err)
+	}
+	return ch, deliveries, nil
+}
+
+// start marks the trigger live and runs the consume loop until ctx is
+// cancelled; cancel is ctx's cancel, called by Stop.
+func (sub *subscription) start(ctx context.Context, cancel context.CancelFunc, ch *amqp.Channel, deliveries <-chan amqp.Delivery) {
+	sub.cancel = cancel
+	mqtrigger.SetTriggerStatus(sub.trigger.Name, sub.trigger.Namespace)
+	mqtrigger.IncreaseInprocessCount()
+	go sub.run(ctx, ch, deliveries)
+}
+
+// run serves deliveries until the channel closes, then re-opens it with a
+// capped backoff — the broker restarting, or the connection dropping, costs a
+// pause, not the subscription. The deliveries a closed channel had not acked
+// are requeued by the broker and redelivered on the new one.
+func (sub *subscription) run(ctx context.Context, ch *amqp.Channel, deliveries <-chan amqp.Delivery) {
+	defer func() {
+		_ = sub.replies.Close()
+		mqtrigger.ResetTriggerStatus(sub.trigger.Name, sub.trigger.Namespace)
+		mqtrigger.DecreaseInprocessCount()
+		close(sub.done)
+	}()
+	for {
+		sub.serve(ctx, deliveries)
+		_ = ch.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		sub.logger.Info("rabbitmq channel closed; re-subscribing")
+		backoff := time.Second
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			var err error
+			if ch, deliveries, err = sub.open(ctx); err == nil {
+				break
+			}
+			sub.logger.Error(err, "re-subscribing", "retryIn", backoff)
+			backoff = min(2*backoff, maxReconnectBackoff)
+		}
+	}
+}
+
+// serve runs prefetch workers over deliveries until it is closed.
+func (sub *subscription) serve(ctx context.Context, deliveries <-chan amqp.Delivery) {
+	var wg sync.WaitGroup
+	for range sub.binding.prefetch {
+		wg.Go(func() {
+			for d := range deliveries {
+				sub.handle(ctx, d)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// Stop cancels the consumer and waits for in-flight deliveries to return. A
+// delivery interrupted mid-invocation is left unacked, and the broker requeues
+// it when the channel closes.
+func (sub *subscription) Stop() error {
+	sub.stopOnce.Do(func() {
+		sub.cancel()
+		<-sub.done
+	})
+	return nil
+}
+
+// Done returns a channel that is closed when the subscription is stopped.
+func (sub *subscription) Done() <-chan struct{} {
+	return sub.done
+}
+
+// handle delivers one message with up to MaxRetries retries and settles it:
+// Ack on success; on exhaustion Nack without requeue, which the broker turns
+// into a dead-letter to ErrorTopic (or a drop, with no ErrorTopic).
+func (sub *subscription) handle(ctx context.Context, d amqp.Delivery) {
+	mqtrigger.IncreaseMessageCount(sub.trigger.Name, sub.trigger.Namespace)
+	var err error
+	for attempt := 0; attempt <= sub.trigger.Spec.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(sub.backoff(attempt)):
+			}
+		}
+		if err = sub.deliver(ctx, d); err == nil {
+			if err := d.Ack(false); err != nil {
+				sub.logger.Error(err, "acking message (it will redeliver — possible duplicate invocation)",
+					"deliveryTag", d.DeliveryTag)
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			return // shutting down: the unacked message is requeued
+		}
+		sub.logger.V(1).Info("function invocation failed", "attempt", attempt+1, "error", err.Error())
+	}
+	switch {
+	case sub.trigger.Spec.ErrorTopic != "" && sub.binding.declare:
+		sub.logger.Info("message exhausted retries; dead-lettering to error topic",
+			"errorTopic", sub.trigger.Spec.ErrorTopic, "maxRetries", sub.trigger.Spec.MaxRetries, "error", err.Error())
+	case sub.binding.declare:
+		sub.logger.Error(err, "message exhausted retries and no error topic is set; dropping",
+			"maxRetries", sub.trigger.Spec.MaxRetries)
+	default:
+		sub.logger.Error(err, "message exhausted retries; rejecting to the queue's own dead-letter configuration",
+			"maxRetries", sub.trigger.Spec.MaxRetries)
+	}
+	if err := d.Nack(false, false); err != nil {
+		sub.logger.Error(err, "rejecting exhausted message (it will redeliver)", "deliveryTag", d.DeliveryTag)
+	}
+}
+
+// backoff is the delay before the given retry: retryBackoff doubling per
+// attempt, capped at maxRetryBackoff.
+func (sub *subscription) backoff(attempt int) time.Duration {
+	d := sub.r.retryBackoff
+	for range attempt - 1 {
+		if d >= maxRetryBackoff {
+			break
+		}
+		d *= 2
+	}
+	return min(d, maxRetryBackoff)
+}
+
+// deliver POSTs the message to the function via the router internal listener.
+// Success is any 2xx (the statestore provider's rule). On success the response
+// is published as the reply, best-effort: the function already ran, and
+// retrying to re-publish would re-run it. A response over maxResponseBytes is
+// logged and not published.
+func (sub *subscription) deliver(ctx context.Context, d amqp.Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.fnURL, bytes.NewReader(d.Body))
+	if err != nil {
+		return fmt.Errorf("building delivery request: %w", err)
+	}
+	for k, v := range d.Headers {
+		if s, ok := headerValue(v); ok {
+			req.Header.Add(k, s)
+		}
+	}
+	switch {
+	case d.ContentType != "":
+		req.Header.Set("Content-Type", d.ContentType)
+	case sub.trigger.Spec.ContentType != "":
+		req.Header.Set("Content-Type", sub.trigger.Spec.ContentType)
+	}
+	correlationID := correlationID(d)
+	if correlationID != "" {
+		req.Header.Set("X-Fission-MQTrigger-CorrelationId", correlationID)
+	}
+	req.Header.Set("X-Fission-MQTrigger-Topic", sub.trigger.Spec.Topic)
+	req.Header.Set("X-Fission-MQTrigger-RespTopic", sub.trigger.Spec.ResponseTopic)
+	req.Header.Set("X-Fission-MQTrigger-ErrorTopic", sub.trigger.Spec.ErrorTopic)
+
+	resp, err := sub.r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("invoking function: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, rerr := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("function returned %s", resp.Status)
+	}
+	exchange, key := sub.replyTarget(d)
+	if key == "" {
+		return nil
+	}
+	if rerr != nil {
+		sub.logger.Error(rerr, "reading function response; reply skipped")
+		return nil
+	}
+	if len(body) > maxResponseBytes {
+		sub.logger.Error(nil, "function response exceeds the reply limit; reply skipped",
+			"limitBytes", maxResponseBytes, "exchange", exchange, "routingKey", key)
+		return nil
+	}
+	headers := amqp.Table{}
+	for k, vs := range resp.Header {
+		if k != "Content-Type" {
+			headers[k] = strings.Join(vs, ", ")
+		}
+	}
+	if err := sub.replies.publish(ctx, exchange, key, amqp.Publishing{
+		ContentType:   resp.Header.Get("Content-Type"),
+		CorrelationId: correlationID,
+		DeliveryMode:  amqp.Persistent,
+		Timestamp:     time.Now(),
+		Headers:       headers,
+		Body:          body,
+	}); err != nil {
+		sub.logger.Error(err, "publishing reply (best-effort)", "exchange", exchange, "routingKey", key)
+	}
+	return nil
+}
+
+// replyTarget is where a delivery's reply goes: ResponseTopic through the
+// response exchange, else the message's reply-to queue (RPC), else nowhere.
+func (sub *subscription) replyTarget(d amqp.Delivery) (exchange, key string) {
+	if sub.trigger.Spec.ResponseTopic != "" {
+		return sub.binding.responseExchange, sub.trigger.Spec.ResponseTopic
+	}
+	return "", d.ReplyTo
+}
+
+// correlationID is the id a reply is correlated by: the request's correlation
+// id, or its message id when the publisher set only that.
+func correlationID(d amqp.Delivery) string {
+	if d.CorrelationId != "" {
+		return d.CorrelationId
+	}
+	return d.MessageId
+}
+
+// headerValue renders a scalar AMQP header value as an HTTP header value;
+// nested tables and arrays have no faithful single-string form and are skipped.
+func headerValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case bool, int8, uint8, int16, uint16, int32, uint32, int64, uint64, int, float32, float64:
+		return fmt.Sprint(v), true
+	case time.Time:
+		return v.UTC().Format(time.RFC3339), true
+	}
+	return "", false
+}
@@ -0,0 +1,375 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rabbitmq implements the AMQP 0-9-1 MessageQueue provider
+// (messageQueueType: rabbitmq).
+//
+// The provider keeps the kafka provider's shape — one classic mqt head per MQ
+// type, leader-only subscriptions, HMAC-signed delivery to the router internal
+// listener, in-process retries up to MaxRetries — and maps the rest onto AMQP:
+//
+//   - Topic is the queue the trigger consumes. The queue, and optionally an
+//     exchange it is bound to, are declared from the trigger's Metadata (see
+//     parseBinding), so a trigger can tap an exchange legacy publishers already
+//     write to without any broker-side setup.
+//   - Metadata "prefetch" is both the channel's basic.qos prefetch and the
+//     number of workers handling deliveries; the default of 1 keeps deliveries
+//     ordered, like a kafka partition.
+//   - Acks are manual: a message is acked only after the function returned 2xx.
+//     A message that exhausts its retries is rejected without requeue, and the
+//     broker dead-letters it; ErrorTopic is wired as the queue's
+//     dead-letter routing key, so "routed to ErrorTopic" is the broker's own
+//     guarantee rather than a publish the head might lose. A head that dies
+//     mid-delivery leaves the message unacked and the broker redelivers it —
+//     at-least-once, like every other provider.
+//   - Replies go to ResponseTopic (through Metadata "responseExchange", the
+//     default exchange when unset), or to the message's reply-to when the
+//     trigger has no ResponseTopic — the RabbitMQ RPC pattern. Either way the
+//     request's correlation id is carried onto the reply.
+//
+// Connections are not self-healing in the AMQP client, so the provider redials
+// on demand and each subscription re-opens its channel with a capped backoff
+// when the broker closes it.
+package rabbitmq
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/mqtrigger/egress"
+	"github.com/fission/fission/pkg/mqtrigger/factory"
+	"github.com/fission/fission/pkg/mqtrigger/messageQueue"
+	"github.com/fission/fission/pkg/mqtrigger/mqpub"
+	"github.com/fission/fission/pkg/mqtrigger/validator"
+)
+
+func init() {
+	factory.Register(fv1.MessageQueueTypeRabbitMQ, &Factory{})
+	validator.Register(fv1.MessageQueueTypeRabbitMQ, IsTopicValid)
+}
+
+const (
+	// maxRetryBackoff caps the delay between delivery attempts, which doubles
+	// per attempt from RabbitMQ.retryBackoff.
+	maxRetryBackoff = 30 * time.Second
+	// maxReconnectBackoff caps the delay before a subscription re-opens a
+	// channel the broker closed.
+	maxReconnectBackoff = 30 * time.Second
+	// brokerOpTimeout bounds a confirmed publish waiting on the broker.
+	brokerOpTimeout = 10 * time.Second
+	// maxResponseBytes is the largest function response published as the
+	// reply; a larger one is skipped rather than cut short.
+	maxResponseBytes = 1 << 20
+)
+
+type (
+	// RabbitMQ is the provider: one AMQP connection, redialed on demand,
+	// carrying a channel per subscription plus the egress publisher's.
+	RabbitMQ struct {
+		logger    logr.Logger
+		routerURL string
+		url       string
+		config    amqp.Config
+		client    *http.Client
+
+		// egressExchange is the exchange egress jobs are published to, with
+		// the job's topic as the routing key; empty is the default exchange,
+		// which routes straight to the queue of that name.
+		egressExchange string
+		// retryBackoff is the first retry delay (a field so tests can tighten it).
+		retryBackoff time.Duration
+
+		mu   sync.Mutex
+		conn *amqp.Connection
+	}
+
+	Factory struct{}
+)
+
+func (factory *Factory) Create(logger logr.Logger, mqCfg messageQueue.Config, routerURL string) (messageQueue.MessageQueue, error) {
+	r, err := New(logger, mqCfg, routerURL)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// New configures the provider for the broker at mqCfg.Url
+// (amqp[s]://[user:password@]host[:port][/vhost]). It does not dial: the
+// connection is opened by the first subscription or egress publish, so a broker
+// outage does not fail head startup.
+//
+// Credentials in the mounted secrets ("username"/"password") take precedence
+// over any in the URL; TLS_ENABLED adds TLS from "caCert" and, for mutual TLS,
+// "userCert"/"userKey", exactly as the kafka head reads them.
+func New(logger logr.Logger, mqCfg messageQueue.Config, routerURL string) (*RabbitMQ, error) {
+	if len(routerURL) == 0 || len(mqCfg.Url) == 0 {
+		return nil, errors.New("the router URL or MQ URL is empty")
+	}
+	if _, err := amqp.ParseURI(mqCfg.Url); err != nil {
+		return nil, fmt.Errorf("invalid rabbitmq URL: %w", err)
+	}
+	config := amqp.Config{
+		Properties: amqp.Table{"connection_name": "fission-mqtrigger"},
+		Heartbeat:  10 * time.Second,
+		Locale:     "en_US",
+	}
+	if user := strings.TrimSpace(string(mqCfg.Secrets["username"])); user != "" {
+		config.SASL = []amqp.Authentication{&amqp.PlainAuth{
+			Username: user,
+			Password: strings.TrimSpace(string(mqCfg.Secrets["password"])),
+		}}
+	}
+	if enabled, _ := strconv.ParseBool(os.Getenv("TLS_ENABLED")); enabled {
+		tlsConfig, err := tlsConfig(logger, mqCfg.Secrets)
+		if err != nil {
+			return nil, err
+		}
+		config.TLSClientConfig = tlsConfig
+	}
+	logger.Info("created rabbitmq queue", "url", redactURL(mqCfg.Url))
+	return &RabbitMQ{
+		logger:         logger.WithName("rabbitmq"),
+		routerURL:      routerURL,
+		url:            mqCfg.Url,
+		config:         config,
+		client:         newHTTPClient(),
+		egressExchange: os.Getenv("MESSAGE_QUEUE_RABBITMQ_EGRESS_EXCHANGE"),
+		retryBackoff:   time.Second,
+	}, nil
+}
+
+func tlsConfig(logger logr.Logger, secrets map[string][]byte) (*tls.Config, error) {
+	if secrets == nil {
+		return nil, errors.New("no secrets were loaded")
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(secrets["userCert"]) > 0 {
+		cert, err := tls.X509KeyPair(secrets["userCert"], secrets["userKey"])
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if v := os.Getenv("INSECURE_SKIP_VERIFY"); v != "" {
+		skipVerify, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid INSECURE_SKIP_VERIFY=%q: %w", v, err)
+		}
+		if skipVerify {
+			logger.Info("WARNING: TLS certificate verification disabled for RabbitMQ (INSECURE_SKIP_VERIFY=true); use only for self-signed dev clusters")
+		}
+		cfg.InsecureSkipVerify = skipVerify
+	}
+	if len(secrets["caCert"]) > 0 {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(secrets["caCert"])
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// redactURL drops the password from an AMQP URL for logging.
+func redactURL(raw string) string {
+	uri, err := amqp.ParseURI(raw)
+	if err != nil {
+		return ""
+	}
+	uri.Password = ""
+	return uri.String()
+}
+
+// connection returns the live connection, dialing a new one when there is none
+// or the broker closed the last.
+func (r *RabbitMQ) connection() (*amqp.Connection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn != nil && !r.conn.IsClosed() {
+		return r.conn, nil
+	}
+	conn, err := amqp.DialConfig(r.url, r.config)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to rabbitmq: %w", err)
+	}
+	r.conn = conn
+	r.logger.Info("connected to rabbitmq", "url", redactURL(r.url))
+	return conn, nil
+}
+
+// channel opens a channel on the live connection.
+func (r *RabbitMQ) channel() (*amqp.Channel, error) {
+	conn, err := r.connection()
+	if err != nil {
+		return nil, err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("opening rabbitmq channel: %w", err)
+	}
+	return ch, nil
+}
+
+// Close closes the connection (and with it every channel).
+func (r *RabbitMQ) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn == nil || r.conn.IsClosed() {
+		return nil
+	}
+	return r.conn.Close()
+}
+
+// confirmPublisher publishes on a channel in confirm mode with the mandatory
+// flag, so a nil error means the broker routed the message to at least one
+// queue and took responsibility for it. Publishes are serialized: with one
+// outstanding message at a time, a basic.return — which the broker sends before
+// the ack — unambiguously belongs to the message being confirmed.
+type confirmPublisher struct {
+	r *RabbitMQ
+
+	mu      sync.Mutex
+	ch      *amqp.Channel
+	returns chan amqp.Return
+}
+
+func newConfirmPublisher(r *RabbitMQ) *confirmPublisher {
+	return &confirmPublisher{r: r}
+}
+
+func (p *confirmPublisher) open() error {
+	if p.ch != nil && !p.ch.IsClosed() {
+		return nil
+	}
+	ch, err := p.r.channel()
+	if err != nil {
+		return err
+	}
+	if err := ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		return fmt.Errorf("enabling publisher confirms: %w", err)
+	}
+	p.ch = ch
+	p.returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	return nil
+}
+
+func (p *confirmPublisher) publish(ctx context.Context, exchange, key string, msg amqp.Publishing) error {
+	ctx, cancel := context.WithTimeout(ctx, brokerOpTimeout)
+	defer cancel()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.open(); err != nil {
+		return err
+	}
+	dc, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, exchange, key, true, false, msg)
+	if err != nil {
+		return fmt.Errorf("publishing to exchange %q key %q: %w", exchange, key, err)
+	}
+	acked, err := dc.WaitContext(ctx)
+	if err != nil {
+		// The confirm may still arrive; a fresh channel keeps it from being
+		// read as the next message's.
+		_ = p.ch.Close()
+		return fmt.Errorf("awaiting publish confirm: %w", err)
+	}
+	select {
+	case ret := <-p.returns:
+		return fmt.Errorf("message returned unroutable from exchange %q key %q: %s", ret.Exchange, ret.RoutingKey, ret.ReplyText)
+	default:
+	}
+	if !acked {
+		return fmt.Errorf("broker nacked publish to exchange %q key %q", exchange, key)
+	}
+	return nil
+}
+
+func (p *confirmPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ch == nil || p.ch.IsClosed() {
+		return nil
+	}
+	return p.ch.Close()
+}
+
+// NewEgressPublisher implements egress.BrokerPublisherProvider: egress jobs are
+// published confirmed and mandatory on a dedicated channel, so a job for a
+// topic no queue is bound to fails — and retries into the egress DLQ, where it
+// is visible — rather than being acked and silently discarded by the broker.
+func (r *RabbitMQ) NewEgressPublisher() (egress.PublishFunc, io.Closer, error) {
+	pub := newConfirmPublisher(r)
+	publish := func(ctx context.Context, job mqpub.EgressJob) error {
+		// The headers are provenance/metadata only — Fission-Namespace is
+		// caller-supplied and NOT an authenticated tenant identity; a
+		// downstream consumer must not authorize on it.
+		return pub.publish(ctx, r.egressExchange, job.Topic, amqp.Publishing{
+			ContentType:  job.ContentType,
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    time.Now(),
+			Headers:      amqp.Table{"Fission-Namespace": job.Namespace},
+			Body:         job.Payload,
+		})
+	}
+	return publish, closerFunc(func() error {
+		return errors.Join(pub.Close(), r.Close())
+	}), nil
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// Subscribe declares the trigger's topology and starts consuming. Invalid
+// Metadata and topology the broker refuses (typically a queue that already
+// exists with different arguments) fail here, so the trigger reports them;
+// broker disconnects after that are retried by the subscription itself.
+func (r *RabbitMQ) Subscribe(ctx context.Context, trigger *fv1.MessageQueueTrigger) (messageQueue.Subscription, error) {
+	if trigger.Spec.FunctionReference.Type != fv1.FunctionReferenceTypeFunctionName {
+		return nil, fmt.Errorf("rabbitmq mq provider: unsupported function reference type %q for trigger %s",
+			trigger.Spec.FunctionReference.Type, trigger.Name)
+	}
+	if !IsTopicValid(trigger.Spec.Topic) {
+		return nil, fmt.Errorf("rabbitmq mq provider: invalid queue name %q", trigger.Spec.Topic)
+	}
+	b, err := parseBinding(trigger)
+	if err != nil {
+		return nil, fmt.Errorf("rabbitmq mq provider: trigger %s: %w", trigger.Name, err)
+	}
+	sub := newSubscription(r, trigger, b)
+	ctx, cancel := context.WithCancel(ctx)
+	ch, deliveries, err := sub.open(ctx)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("rabbitmq mq provider: subscribing trigger %s: %w", trigger.Name, err)
+	}
+	sub.start(ctx, cancel, ch, deliveries)
+	r.logger.Info("subscribed", "trigger", trigger.Name, "namespace", trigger.Namespace,
+		"queue", trigger.Spec.Topic, "exchange", b.exchange, "bindingKey", b.bindingKey,
+		"prefetch", b.prefetch, "responseTopic", trigger.Spec.ResponseTopic, "errorTopic", trigger.Spec.ErrorTopic)
+	return sub, nil
+}
+
+func (r *RabbitMQ) Unsubscribe(sub messageQueue.Subscription) error {
+	return sub.Stop()
+}
+
+// IsTopicValid reports whether topic is a queue name fission can use as a
+// topic: the shared topic grammar minus RabbitMQ's reserved amq. prefix.
+func IsTopicValid(topic string) bool {
+	return fv1.ValidateTopicNameForMQType("topic", fv1.MessageQueueTypeRabbitMQ, topic) == nil
+}
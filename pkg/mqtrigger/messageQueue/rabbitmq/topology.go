@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// Trigger Metadata keys read by the provider. MessageQueueTriggerSpec.Metadata
+// is shared with the KEDA scaler metadata of keda-kind triggers, so none of
+// these collide with the KEDA rabbitmq scaler's keys.
+const (
+	// MetadataExchange names an exchange to bind the trigger's queue to.
+	// Unset, the queue is consumed as-is (publishers target it through the
+	// default exchange).
+	MetadataExchange = "exchange"
+	// MetadataExchangeType is the exchange's type when the provider declares
+	// it: direct, fanout, topic (the default) or headers.
+	MetadataExchangeType = "exchangeType"
+	// MetadataBindingKey is the binding's routing key pattern; the default is
+	// "#" for a topic exchange and the queue name for a direct one.
+	MetadataBindingKey = "bindingKey"
+	// MetadataPrefetch is the basic.qos prefetch and the worker count, 1 to
+	// maxPrefetch; the default of 1 keeps deliveries ordered.
+	MetadataPrefetch = "prefetch"
+	// MetadataQueueType is the x-queue-type of a declared queue: classic or
+	// quorum. Unset, the broker's default applies.
+	MetadataQueueType = "queueType"
+	// MetadataDeclare set to "false" makes the provider use the broker's
+	// topology as-is: nothing is declared, and a missing queue fails the
+	// subscription. Dead-lettering then follows the queue's own arguments or
+	// policy, not ErrorTopic.
+	MetadataDeclare = "declare"
+	// MetadataDeadLetterExchange is the exchange exhausted messages are
+	// dead-lettered through with ErrorTopic as the routing key; the default
+	// exchange (routing straight to the ErrorTopic queue) when unset.
+	MetadataDeadLetterExchange = "deadLetterExchange"
+	// MetadataResponseExchange is the exchange ResponseTopic replies are
+	// published to with ResponseTopic as the routing key; the default
+	// exchange when unset.
+	MetadataResponseExchange = "responseExchange"
+)
+
+// maxPrefetch bounds MetadataPrefetch: each unit is a worker goroutine and a
+// concurrent function invocation.
+const maxPrefetch = 256
+
+// binding is a trigger's AMQP topology and consumption settings, parsed from
+// its Metadata.
+type binding struct {
+	exchange           string
+	exchangeType       string
+	bindingKey         string
+	prefetch           int
+	queueType          string
+	declare            bool
+	deadLetterExchange string
+	responseExchange   string
+}
+
+// parseBinding reads the trigger's Metadata, applying defaults and rejecting
+// values the broker would refuse, so a bad trigger fails at subscribe time with
+// the offending key named.
+func parseBinding(trigger *fv1.MessageQueueTrigger) (binding, error) {
+	md := trigger.Spec.Metadata
+	b := binding{
+		exchange:           md[MetadataExchange],
+		exchangeType:       md[MetadataExchangeType],
+		bindingKey:         md[MetadataBindingKey],
+		prefetch:           1,
+		queueType:          md[MetadataQueueType],
+		declare:            true,
+		deadLetterExchange: md[MetadataDeadLetterExchange],
+		responseExchange:   md[MetadataResponseExchange],
+	}
+	var errs []error
+	if b.exchangeType == "" {
+		b.exchangeType = amqp.ExchangeTopic
+	}
+	switch b.exchangeType {
+	case amqp.ExchangeDirect, amqp.ExchangeFanout, amqp.ExchangeTopic, amqp.ExchangeHeaders:
+	default:
+		errs = append(errs, fmt.Errorf("metadata %s: unknown exchange type %q", MetadataExchangeType, b.exchangeType))
+	}
+	if b.bindingKey == "" {
+		switch b.exchangeType {
+		case amqp.ExchangeTopic:
+			b.bindingKey = "#"
+		case amqp.ExchangeDirect:
+			b.bindingKey = trigger.Spec.Topic
+		}
+	}
+	if v, ok := md[MetadataPrefetch]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPrefetch {
+			errs = append(errs, fmt.Errorf("metadata %s: must be an integer between 1 and %d, got %q", MetadataPrefetch, maxPrefetch, v))
+		}
+		b.prefetch = n
+	}
+	switch b.queueType {
+	case "", amqp.QueueTypeClassic, amqp.QueueTypeQuorum:
+	default:
+		errs = append(errs, fmt.Errorf("metadata %s: must be %q or %q, got %q", MetadataQueueType, amqp.QueueTypeClassic, amqp.QueueTypeQuorum, b.queueType))
+	}
+	if v, ok := md[MetadataDeclare]; ok {
+		declare, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("metadata %s: %w", MetadataDeclare, err))
+		}
+		b.declare = declare
+	}
+	if b.exchange == "" && md[MetadataBindingKey] != "" {
+		errs = append(errs, fmt.Errorf("metadata %s requires %s", MetadataBindingKey, MetadataExchange))
+	}
+	return b, errors.Join(errs...)
+}
+
+// queueArgs are the arguments the trigger's queue is declared with: the queue
+// type, and — when the trigger has an ErrorTopic — the dead-letter exchange and
+// routing key that deliver exhausted messages to it.
+func (b binding) queueArgs(trigger *fv1.MessageQueueTrigger) amqp.Table {
+	args := amqp.Table{}
+	if b.queueType != "" {
+		args[amqp.QueueTypeArg] = b.queueType
+	}
+	if trigger.Spec.ErrorTopic != "" {
+		args["x-dead-letter-exchange"] = b.deadLetterExchange
+		args["x-dead-letter-routing-key"] = trigger.Spec.ErrorTopic
+	}
+	return args
+}
+
+// declarer is the slice of *amqp.Channel topology declaration uses.
+type declarer interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueDeclarePassive(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+}
+
+// apply makes the trigger's topology exist: a durable queue named by Topic,
+// the ErrorTopic queue its dead letters route to (when they route through the
+// default exchange), and the exchange binding. Every declaration is idempotent,
+// so re-subscribing after a reconnect re-runs it harmlessly. With declare off,
+// only the queue's existence is checked.
+func (b binding) apply(ch declarer, trigger *fv1.MessageQueueTrigger) error {
+	queue := trigger.Spec.Topic
+	if !b.declare {
+		if _, err := ch.QueueDeclarePassive(queue, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("queue %q does not exist (metadata %s=false): %w", queue, MetadataDeclare, err)
+		}
+		return nil
+	}
+	if _, err := ch.QueueDeclare(queue, true, false, false, false, b.queueArgs(trigger)); err != nil {
+		return fmt.Errorf("declaring queue %q (an existing queue with different arguments needs metadata %s=false): %w",
+			queue, MetadataDeclare, err)
+	}
+	if trigger.Spec.ErrorTopic != "" && b.deadLetterExchange == "" {
+		args := amqp.Table{}
+		if b.queueType != "" {
+			args[amqp.QueueTypeArg] = b.queueType
+		}
+		if _, err := ch.QueueDeclare(trigger.Spec.ErrorTopic, true, false, false, false, args); err != nil {
+			return fmt.Errorf("declaring error queue %q: %w", trigger.Spec.ErrorTopic, err)
+		}
+	}
+	if b.exchange == "" {
+		return nil
+	}
+	if err := ch.ExchangeDeclare(b.exchange, b.exchangeType, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declaring %s exchange %q: %w", b.exchangeType, b.exchange, err)
+	}
+	if err := ch.QueueBind(queue, b.bindingKey, b.exchange, false, nil); err != nil {
+		return fmt.Errorf("binding queue %q to exchange %q with key %q: %w", queue, b.exchange, b.bindingKey, err)
+	}
+	return nil
+}
@@ -24,7 +24,9 @@ var (
 	eventingErrorTopic = metrics.Int64Counter("fission_eventing_errortopic_total",
 		"Count of exhausted events routed to the error topic, labeled by outcome (published/error/dropped — dropped = no error topic configured)")
 	eventingTrimmed = metrics.Int64Counter("fission_eventing_trimmed_total",
-		"Count of topic events trimmed by retention, labeled by reason (mincursor/age/size)")
+		"Count of topic events trimmed by retention, labeled by reason (mincursor/age/size/bytes)")
+	eventingCompacted = metrics.Int64Counter("fission_eventing_compacted_total",
+		"Count of superseded keyed topic events dropped by compaction")
 	eventingResponseTopic = metrics.Int64Counter("fission_eventing_responsetopic_total",
 		"Count of best-effort response-topic publishes after successful deliveries, labeled by outcome (published/error)")
 	eventingGaps = metrics.Int64Counter("fission_eventing_gap_events_total",
@@ -49,6 +51,10 @@ func recordTrimmed(ctx context.Context, reason string, n int64) {
 	eventingTrimmed.Add(ctx, n, metric.WithAttributes(attribute.String("reason", reason)))
 }
 
+func recordCompacted(ctx context.Context, n int64) {
+	eventingCompacted.Add(ctx, n)
+}
+
 func recordResponseTopic(ctx context.Context, outcome string) {
 	eventingResponseTopic.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
 }
@@ -8,13 +8,18 @@ import (
 	"context"
 	"sync"
 	"time"
+
+	"github.com/fission/fission/pkg/mqtrigger/mqpub"
 )
 
 // Retention tuning (RFC-0027). The reaper trims each subscribed stream to the
 // minimum committed cursor (E3: no live subscriber loses an unconsumed event),
 // with two backstops that deliberately override a stalled subscriber's floor —
 // documented, bounded loss, identical in kind to a broker's retention evicting
-// a lagging consumer group:
+// a lagging consumer group. A topic with a retention policy
+// (mqpub.TopicRetention) instead keeps its consumed history within the policy's
+// age/count/bytes limits, each replacing the matching backstop, and may compact
+// by key below the minimum cursor:
 const (
 	// reaperInterval paces retention ticks.
 	reaperInterval = time.Minute
@@ -24,8 +29,9 @@ const (
 	// maxStreamEvents is the size backstop: a stream's retained backlog never
 	// exceeds this many events.
 	maxStreamEvents = 100_000
-	// ageScanPages bounds the age-backstop scan per stream per tick, so one huge
-	// backlog cannot monopolize a tick (the scan resumes next tick).
+	// ageScanPages bounds the age-backstop (and bytes-limit) scan per stream per
+	// tick, so one huge backlog cannot monopolize a tick (the scan resumes next
+	// tick).
 	ageScanPages = 10
 	// reaperTickTimeout deadlines one tick's store calls: the embedded client
 	// driver has its own HTTP timeout, but the external Postgres driver would
@@ -126,22 +132,35 @@ func (s *Statestore) runReaper() {
 }
 
 // reapTick trims every subscribed stream. Per stream, the trim point is the max
-// of three candidates: the min committed cursor (exact, loss-free; noMinCursor
-// disables it while a subscription's floor is unknown), the age backstop, and
-// the size backstop (both documented loss for stalled consumers). Streams whose
-// last trigger was deleted are no longer reaped — their residue is bounded by
-// the publisher's backlog cap; a subscription-free age sweep is the egress
-// phase's problem (needs stream listing).
+// of the candidates: the min committed cursor (exact, loss-free; noMinCursor
+// disables it while a subscription's floor is unknown; a retention policy
+// disables it to keep consumed history), the age and size limits, and a policy's
+// bytes limit (all documented loss for stalled consumers). Streams whose last
+// trigger was deleted are no longer reaped — their residue is bounded by the
+// publisher's backlog cap; a subscription-free age sweep is the egress phase's
+// problem (needs stream listing).
 func (s *Statestore) reapTick(ctx context.Context) {
 	for stream, minCursor := range s.subs.byStream() {
-		head := s.reapStream(ctx, stream, minCursor)
+		subs := s.subs.forStream(stream)
+		if len(subs) == 0 {
+			continue // unsubscribed since the snapshot
+		}
+		trigger := subs[0].trigger
+		policy, err := mqpub.LoadTopicRetention(ctx, s.kv, trigger.Namespace, trigger.Spec.Topic)
+		if err != nil {
+			// Skip rather than guess: the default trims consumed history a
+			// policy may exist to keep.
+			s.logger.Error(err, "reaper: reading topic retention policy; skipping this tick", "stream", stream)
+			continue
+		}
+		head := s.reapStream(ctx, stream, minCursor, policy)
 		if head < 0 {
 			continue
 		}
 		// The tick already paid the Head read — refresh the per-trigger lag
 		// gauge (head − committed cursor) from it, the RFC-0027 scaling and
 		// alerting signal, with reaperInterval freshness.
-		for _, sub := range s.subs.forStream(stream) {
+		for _, sub := range subs {
 			if sub.started.Load() {
 				recordLag(ctx, sub.trigger.Namespace, sub.trigger.Name, head-sub.committed.Load())
 			}
@@ -149,9 +168,9 @@ func (s *Statestore) reapTick(ctx context.Context) {
 	}
 }
 
-// reapStream trims one stream and returns its head (for the lag gauge), or -1
-// when the head could not be read.
-func (s *Statestore) reapStream(ctx context.Context, stream string, minCursor int64) int64 {
+// reapStream trims (and, under a compacting policy, compacts) one stream and
+// returns its head (for the lag gauge), or -1 when the head could not be read.
+func (s *Statestore) reapStream(ctx context.Context, stream string, minCursor int64, policy mqpub.TopicRetention) int64 {
 	// Current floor: the seq just below the first retained event (== head when
 	// the stream is fully trimmed or empty).
 	head, err := s.el.Head(ctx, stream)
@@ -170,18 +189,25 @@ func (s *Statestore) reapStream(ctx context.Context, stream string, minCursor in
 	}
 
 	// Candidate 1 — min-cursor (loss-free): everything at or below the slowest
-	// subscriber's committed cursor is consumed.
+	// subscriber's committed cursor is consumed. A policy topic keeps that
+	// history for replay, so only its limits trim.
 	trimTo := minCursor // trim events with Seq <= trimTo
 	reason := "mincursor"
+	maxEvents, maxAge := s.reaperMaxEvents, s.reaperMaxAge
+	if !policy.IsZero() {
+		trimTo = noMinCursor
+		maxEvents, maxAge = policy.RetainedEvents(), policy.MaxAgeOr(s.reaperMaxAge)
+	}
 
-	// Candidate 2 — size backstop.
-	if sizeTo := head - s.reaperMaxEvents; sizeTo > trimTo {
+	// Candidate 2 — size backstop (or the policy's count limit).
+	if sizeTo := head - maxEvents; sizeTo > trimTo {
 		trimTo, reason = sizeTo, "size"
 	}
 
-	// Candidate 3 — age backstop: bounded forward scan for events older than the
-	// cutoff (resumes next tick if the backlog is huge).
-	cutoff := time.Now().Add(-s.reaperMaxAge)
+	// Candidate 3 — age backstop (or the policy's age limit): bounded forward
+	// scan for events older than the cutoff (resumes next tick if the backlog
+	// is huge).
+	cutoff := time.Now().Add(-maxAge)
 	ageTo := floor
 	from := floor
 scan:
@@ -206,15 +232,71 @@ scan:
 		trimTo, reason = ageTo, "age"
 	}
 
-	if trimTo <= floor {
-		return head // nothing new to trim
+	// Candidate 4 — the policy's bytes limit.
+	if policy.MaxBytes > 0 {
+		if bytesTo := s.bytesTrimPoint(ctx, stream, floor, policy.MaxBytes); bytesTo > trimTo {
+			trimTo, reason = bytesTo, "bytes"
+		}
 	}
-	if err := s.el.Trim(ctx, stream, trimTo+1); err != nil {
-		s.logger.Error(err, "reaper: trimming stream", "stream", stream, "below", trimTo+1)
-		return head
+
+	if trimTo > floor {
+		if err := s.el.Trim(ctx, stream, trimTo+1); err != nil {
+			s.logger.Error(err, "reaper: trimming stream", "stream", stream, "below", trimTo+1)
+			return head
+		}
+		recordTrimmed(ctx, reason, trimTo-floor)
+		s.logger.V(1).Info("reaper: trimmed topic stream",
+			"stream", stream, "events", trimTo-floor, "reason", reason)
+	}
+
+	// Compaction runs strictly below the min cursor, so a live subscriber never
+	// meets a compacted hole — only a replay (seek) reads compacted history.
+	if policy.Compact && minCursor != noMinCursor {
+		n, err := s.el.Compact(ctx, stream, minCursor+1)
+		if err != nil {
+			s.logger.Error(err, "reaper: compacting stream", "stream", stream, "below", minCursor+1)
+			return head
+		}
+		if n > 0 {
+			recordCompacted(ctx, n)
+			s.logger.V(1).Info("reaper: compacted topic stream", "stream", stream, "events", n)
+		}
 	}
-	recordTrimmed(ctx, reason, trimTo-floor)
-	s.logger.V(1).Info("reaper: trimmed topic stream",
-		"stream", stream, "events", trimTo-floor, "reason", reason)
 	return head
 }
+
+// bytesTrimPoint returns the seq to trim through so the stream's retained
+// payload fits maxBytes, or floor when it already does: the excess is read
+// forward from the floor (bounded like the age scan; a partial scan trims what
+// it covered and the next tick continues).
+func (s *Statestore) bytesTrimPoint(ctx context.Context, stream string, floor, maxBytes int64) int64 {
+	st, err := s.el.StreamStats(ctx, stream)
+	if err != nil {
+		s.logger.Error(err, "reaper: reading stream size", "stream", stream)
+		return floor
+	}
+	excess := st.Bytes - maxBytes
+	to, from := floor, floor
+	for range ageScanPages {
+		if excess <= 0 {
+			break
+		}
+		evs, rerr := s.el.Read(ctx, stream, from, readBatch)
+		if rerr != nil {
+			s.logger.Error(rerr, "reaper: bytes scan", "stream", stream)
+			break
+		}
+		if len(evs) == 0 {
+			break
+		}
+		for _, ev := range evs {
+			if excess <= 0 {
+				break
+			}
+			excess -= int64(len(ev.Payload))
+			to = ev.Seq
+		}
+		from = evs[len(evs)-1].Seq
+	}
+	return to
+}
@@ -24,17 +24,22 @@ import (
 	"github.com/go-logr/logr"
 )
 
-// cursorScope returns the KV scope of a trigger's durable cursor, per the house
-// Scope convention (Owner = <kind>/<name>).
+// cursorScope returns the KV scope of a trigger's durable cursor (shared with
+// the router's topic admin API, which reports and seeks it).
 func cursorScope(trigger *fv1.MessageQueueTrigger) statestore.Scope {
-	return statestore.Scope{
-		Namespace: trigger.Namespace,
-		Owner:     "messagequeuetrigger/" + trigger.Name,
-		Keyspace:  "cursor",
-	}
+	return mqpub.CursorScope(trigger.Namespace, trigger.Name)
 }
 
-const cursorKey = "cursor"
+const cursorKey = mqpub.CursorKey
+
+// HeaderTopicKey, on a function's response, keys the event published to the
+// trigger's ResponseTopic — the compaction identity of a keyed pipeline stage.
+const HeaderTopicKey = "X-Fission-Topic-Key"
+
+// cursorRecheckInterval paces an idle subscription's check for a cursor moved
+// underneath it (`fission topic seek`). A busy subscription notices at its next
+// commit, whose CAS fails against the moved record.
+const cursorRecheckInterval = 10 * time.Second
 
 // subscription is one trigger's consumer loop over its topic stream: read from
 // the durable cursor, deliver in order, publish ResponseTopic/ErrorTopic, and
@@ -57,6 +62,9 @@ type subscription struct {
 	// PollingInterval (seconds) or defaultPollInterval (a field so tests can
 	// tighten it).
 	poll time.Duration
+	// recheck paces an idle subscription's check for a seeked cursor:
+	// cursorRecheckInterval, or poll when that is longer.
+	recheck time.Duration
 
 	cancel context.CancelFunc
 	done   chan struct{}
@@ -67,8 +75,9 @@ func newSubscription(s *Statestore, trigger *fv1.MessageQueueTrigger) *subscript
 	if p := trigger.Spec.PollingInterval; p != nil && *p > 0 {
 		poll = time.Duration(*p) * time.Second
 	}
+	recheck := max(cursorRecheckInterval, poll)
 	if s.pollOverride > 0 {
-		poll = s.pollOverride
+		poll, recheck = s.pollOverride, s.pollOverride
 	}
 	// RFC-0025: append the alias/version suffix when the reference carries
 	// one; resolution stays entirely router-side.
@@ -79,6 +88,7 @@ func newSubscription(s *Statestore, trigger *fv1.MessageQueueTrigger) *subscript
 		stream:  mqpub.StreamForTopic(trigger.Namespace, trigger.Spec.Topic),
 		fnURL:   s.routerURL + "/" + strings.TrimPrefix(utils.UrlForFunctionReference(trigger.Spec.FunctionReference, trigger.Namespace), "/"),
 		poll:    poll,
+		recheck: recheck,
 		done:    make(chan struct{}),
 	}
 }
@@ -111,6 +121,7 @@ func (sub *subscription) run(ctx context.Context) {
 	sub.started.Store(true)
 	sub.logger.Info("topic subscription started", "stream", sub.stream, "cursor", cursor)
 
+	lastRecheck := time.Now()
 	for {
 		if ctx.Err() != nil {
 			return
@@ -126,17 +137,22 @@ func (sub *subscription) run(ctx context.Context) {
 			continue
 		}
 		if len(events) == 0 {
+			if time.Since(lastRecheck) >= sub.recheck {
+				lastRecheck = time.Now()
+				cursor, version = sub.adoptMovedCursor(ctx, cursor, version)
+			}
 			if !sleepCtx(ctx, sub.poll) {
 				return
 			}
 			continue
 		}
 		// Gap detection: a first event above cursor+1 means retention trimmed
-		// events this subscription never delivered (an age/size backstop
-		// overriding a stalled floor). The loss already happened — surface it
-		// HERE, at the subscriber that suffered it, or "my function missed
-		// events" is undebuggable later.
-		if gap := events[0].Seq - cursor - 1; gap > 0 {
+		// events this subscription never delivered (an age/size backstop or a
+		// topic retention limit overriding a stalled floor). The loss already
+		// happened — surface it HERE, at the subscriber that suffered it, or "my
+		// function missed events" is undebuggable later. A compacted hole is not
+		// loss (a later event carries the key's latest value) and is not counted.
+		if gap := events[0].Seq - cursor - 1; gap > 0 && sub.trimmedBelow(ctx, cursor) {
 			recordGap(ctx, gap)
 			sub.logger.Error(nil, "topic events were trimmed before delivery to this subscription",
 				"stream", sub.stream, "cursor", cursor, "resumedAt", events[0].Seq, "missed", gap)
@@ -202,10 +218,38 @@ func (sub *subscription) loadOrInitCursor(ctx context.Context) (cursor, version
 	return c, val.Version, nil
 }
 
+// adoptMovedCursor re-reads the persisted cursor and adopts it when its version
+// moved past ours — an operator seek (RFC-0027 `fission topic seek`), the one
+// writer besides the subscription itself. Seek is an explicit override of
+// CursorMonotonic: the cursor may move backwards (replay) or forwards (skip).
+func (sub *subscription) adoptMovedCursor(ctx context.Context, cursor, version int64) (int64, int64) {
+	c, v, err := sub.loadOrInitCursor(ctx)
+	if err != nil || v == version {
+		return cursor, version
+	}
+	sub.logger.Info("topic cursor moved externally; resuming from the persisted cursor",
+		"stream", sub.stream, "local", cursor, "persisted", c)
+	sub.committed.Store(c)
+	return c, v
+}
+
+// trimmedBelow reports whether the events just above cursor are gone because
+// the stream's floor passed them (trim), as opposed to a compacted hole: the
+// stream still retaining an event at or below cursor means the floor did not
+// move past it. A failed read counts as trimmed — the alarm errs loud.
+func (sub *subscription) trimmedBelow(ctx context.Context, cursor int64) bool {
+	first, err := sub.s.el.Read(ctx, sub.stream, 0, 1)
+	if err != nil || len(first) == 0 {
+		return true
+	}
+	return first[0].Seq > cursor
+}
+
 // commit CAS-persists the cursor. On a version conflict (an overlapping
-// instance advanced it — leadership transition), it adopts the persisted record
-// and resumes from there: the cursor never regresses (CursorMonotonic), and any
-// re-read tail is at-least-once redelivery.
+// instance advanced it during a leadership transition, or an operator seek
+// moved it), it adopts the persisted record and resumes from there: between
+// instances the cursor never regresses (CursorMonotonic), and any re-read tail
+// is at-least-once redelivery.
 func (sub *subscription) commit(ctx context.Context, cursor, version int64) (int64, int64) {
 	err := sub.s.kv.Set(ctx, cursorScope(sub.trigger), cursorKey,
 		[]byte(strconv.FormatInt(cursor, 10)), statestore.SetOptions{IfVersion: new(version)})
@@ -329,8 +373,8 @@ func (sub *subscription) deliver(ctx context.Context, ev statestore.Event) bool
 		}
 		if sub.trigger.Spec.ResponseTopic != "" {
 			respCT := resp.Header.Get("Content-Type")
-			if err := sub.s.pub.Publish(ctx, sub.trigger.Namespace, fv1.MessageQueueTypeStatestore,
-				sub.trigger.Spec.ResponseTopic, respCT, body); err != nil {
+			if err := mqpub.PublishKeyed(ctx, sub.s.pub, sub.trigger.Namespace, fv1.MessageQueueTypeStatestore,
+				sub.trigger.Spec.ResponseTopic, resp.Header.Get(HeaderTopicKey), respCT, body); err != nil {
 				recordResponseTopic(ctx, "error")
 				sub.logger.Error(err, "publishing response to response topic (best-effort)",
 					"responseTopic", sub.trigger.Spec.ResponseTopic, "seq", ev.Seq)
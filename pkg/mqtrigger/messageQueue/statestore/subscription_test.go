@@ -38,6 +38,7 @@ type fnEndpoint struct {
 	got    []received
 	status int    // response status (default 200)
 	body   string // response body
+	key    string // X-Fission-Topic-Key response header
 	failN  int    // fail the first N requests with 500
 }
 
@@ -51,7 +52,7 @@ func (f *fnEndpoint) handler(w http.ResponseWriter, r *http.Request) {
 		RespTopic:   r.Header.Get("X-Fission-MQTrigger-RespTopic"),
 	})
 	n := len(f.got)
-	failN, status, body := f.failN, f.status, f.body
+	failN, status, body, key := f.failN, f.status, f.body, f.key
 	f.mu.Unlock()
 	if n <= failN {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -61,6 +62,9 @@ func (f *fnEndpoint) handler(w http.ResponseWriter, r *http.Request) {
 		status = http.StatusOK
 	}
 	w.Header().Set("Content-Type", "text/plain")
+	if key != "" {
+		w.Header().Set(HeaderTopicKey, key)
+	}
 	w.WriteHeader(status)
 	_, _ = io.WriteString(w, body)
 }
@@ -233,6 +237,50 @@ func TestSubscriptionResponseTopic(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte("fn-response"), evs[0].Payload)
 	assert.Equal(t, "text/plain", evs[0].Type, "the function's response Content-Type travels")
+	assert.Empty(t, evs[0].Key, "no key header, no key")
+}
+
+func TestSubscriptionResponseTopicKey(t *testing.T) {
+	t.Parallel()
+	fn := &fnEndpoint{body: "9.99", key: "sku-1"}
+	srv := httptest.NewServer(http.HandlerFunc(fn.handler))
+	defer srv.Close()
+	s := newTestProvider(t, srv.URL)
+
+	startSub(t, s, testTrigger("t1", "orders", func(tr *fv1.MessageQueueTrigger) {
+		tr.Spec.ResponseTopic = "prices"
+	}))
+	publish(t, s, "orders", "", "req")
+
+	respStream := mqpub.StreamForTopic("ns", "prices")
+	require.Eventually(t, func() bool {
+		evs, err := s.el.Read(t.Context(), respStream, 0, 10)
+		return err == nil && len(evs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	evs, err := s.el.Read(t.Context(), respStream, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, "sku-1", evs[0].Key, "the response key header keys the published event")
+}
+
+// TestSubscriptionAdoptsSeekedCursor: an idle subscription notices its cursor
+// record moved underneath it (an operator seek) and replays from there.
+func TestSubscriptionAdoptsSeekedCursor(t *testing.T) {
+	t.Parallel()
+	fn := &fnEndpoint{}
+	srv := httptest.NewServer(http.HandlerFunc(fn.handler))
+	defer srv.Close()
+	s := newTestProvider(t, srv.URL)
+	trigger := testTrigger("t1", "orders", nil)
+
+	sub := startSub(t, s, trigger)
+	publish(t, s, "orders", "", "one")
+	publish(t, s, "orders", "", "two")
+	require.Eventually(t, func() bool { return sub.committed.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, s.kv.Set(t.Context(), cursorScope(trigger), cursorKey, []byte("1"), statestore.SetOptions{}))
+	require.Eventually(t, func() bool { return len(fn.deliveries()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "two", fn.deliveries()[2].Body, "delivery resumes after the seeked cursor")
+	require.Eventually(t, func() bool { return sub.committed.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
 }
 
 func TestSubscriptionResumesFromDurableCursor(t *testing.T) {
@@ -272,7 +320,7 @@ func TestReapStreamMinCursorAndBackstops(t *testing.T) {
 	stream := mqpub.StreamForTopic("ns", "orders")
 
 	// Min-cursor trim: events 1..3 go, 4..5 stay.
-	s.reapStream(t.Context(), stream, 3)
+	s.reapStream(t.Context(), stream, 3, mqpub.TopicRetention{})
 	evs, err := s.el.Read(t.Context(), stream, 0, 10)
 	require.NoError(t, err)
 	require.Len(t, evs, 2)
@@ -280,7 +328,7 @@ func TestReapStreamMinCursorAndBackstops(t *testing.T) {
 
 	// Size backstop: cap of 1 retained event overrides a stalled cursor (0).
 	s.reaperMaxEvents = 1
-	s.reapStream(t.Context(), stream, 0)
+	s.reapStream(t.Context(), stream, 0, mqpub.TopicRetention{})
 	evs, err = s.el.Read(t.Context(), stream, 0, 10)
 	require.NoError(t, err)
 	require.Len(t, evs, 1)
@@ -288,13 +336,56 @@ func TestReapStreamMinCursorAndBackstops(t *testing.T) {
 
 	// Age backstop: everything is "old" with a zero max age.
 	s.reaperMaxAge = -time.Hour
-	s.reapStream(t.Context(), stream, 0)
+	s.reapStream(t.Context(), stream, 0, mqpub.TopicRetention{})
 	evs, err = s.el.Read(t.Context(), stream, 0, 10)
 	require.NoError(t, err)
 	assert.Empty(t, evs, "age backstop trims events older than the cutoff")
 
 	// Idempotent when nothing to trim.
-	s.reapStream(t.Context(), stream, 0)
+	s.reapStream(t.Context(), stream, 0, mqpub.TopicRetention{})
+}
+
+// TestReapStreamRetentionPolicy: a policy topic keeps consumed history (no
+// min-cursor trim) within its limits, and compacts only below the min cursor.
+func TestReapStreamRetentionPolicy(t *testing.T) {
+	t.Parallel()
+	s := newTestProvider(t, "http://unused")
+	stream := mqpub.StreamForTopic("ns", "prices")
+	pub := func(key, payload string) {
+		t.Helper()
+		require.NoError(t, mqpub.PublishKeyed(t.Context(), s.pub, "ns", fv1.MessageQueueTypeStatestore, "prices", key, "", []byte(payload)))
+	}
+	seqs := func() []int64 {
+		t.Helper()
+		evs, err := s.el.Read(t.Context(), stream, 0, 0)
+		require.NoError(t, err)
+		var out []int64
+		for _, ev := range evs {
+			out = append(out, ev.Seq)
+		}
+		return out
+	}
+	// seq 1..6: a, b, a, b, a, (unkeyed) — each payload 4 bytes.
+	for _, k := range []string{"a", "b", "a", "b", "a", ""} {
+		pub(k, "xxxx")
+	}
+
+	// Everything consumed, but the policy keeps the history.
+	s.reapStream(t.Context(), stream, 6, mqpub.TopicRetention{MaxEvents: 100})
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6}, seqs())
+
+	// Compaction up to the min cursor (4): 1, 2 and 3 each have a later event
+	// with the same key; 4 is b's latest and stays; 5 and 6 are unconsumed.
+	s.reapStream(t.Context(), stream, 4, mqpub.TopicRetention{Compact: true})
+	assert.Equal(t, []int64{4, 5, 6}, seqs())
+
+	// Count limit: the newest 2 sequence numbers.
+	s.reapStream(t.Context(), stream, 6, mqpub.TopicRetention{MaxEvents: 2})
+	assert.Equal(t, []int64{5, 6}, seqs())
+
+	// Bytes limit: 8 retained bytes over a 4-byte limit drops the oldest.
+	s.reapStream(t.Context(), stream, 6, mqpub.TopicRetention{MaxBytes: 4})
+	assert.Equal(t, []int64{6}, seqs())
 }
 
 // TestByStreamBlocksUnstartedSubscriptions: a subscription that has not
@@ -327,7 +418,7 @@ func TestByStreamBlocksUnstartedSubscriptions(t *testing.T) {
 		publish(t, s, "orders", "", "x")
 	}
 	stream := mqpub.StreamForTopic("ns", "orders")
-	s.reapStream(t.Context(), stream, noMinCursor)
+	s.reapStream(t.Context(), stream, noMinCursor, mqpub.TopicRetention{})
 	evs, err := s.el.Read(t.Context(), stream, 0, 10)
 	require.NoError(t, err)
 	assert.Len(t, evs, 3, "no loss-free trim while any floor is unknown")
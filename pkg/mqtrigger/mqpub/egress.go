@@ -107,3 +107,15 @@ func (p *multiPublisher) Publish(ctx context.Context, namespace, mqType, topic,
 	}
 	return p.egress.Publish(ctx, namespace, mqType, topic, contentType, payload)
 }
+
+// PublishKeyed implements KeyedPublisher for statestore topics; a keyed publish
+// to a broker type is ErrKeyUnsupported (egress jobs carry no key).
+func (p *multiPublisher) PublishKeyed(ctx context.Context, namespace, mqType, topic, key, contentType string, payload []byte) error {
+	if mqType == fv1.MessageQueueTypeStatestore {
+		return PublishKeyed(ctx, p.direct, namespace, mqType, topic, key, contentType, payload)
+	}
+	if key != "" {
+		return ErrKeyUnsupported
+	}
+	return p.egress.Publish(ctx, namespace, mqType, topic, contentType, payload)
+}
@@ -28,6 +28,14 @@ import (
 // provider exists, so broker types map to this error.
 var ErrUnsupportedMQType = errors.New("mqpub: unsupported message-queue type")
 
+// ErrKeyUnsupported is returned when a message key is given for a publish path
+// that cannot carry one — keys (the compaction identity) exist only on
+// statestore topics.
+var ErrKeyUnsupported = errors.New("mqpub: message keys are supported on statestore topics only")
+
+// MaxKeyLen bounds a message key; keys are identifiers, not payload.
+const MaxKeyLen = 256
+
 // ErrTopicBacklogCap is returned when a topic stream has reached the phase-1
 // backlog cap (see DefaultMaxTopicBacklog).
 var ErrTopicBacklogCap = errors.New("mqpub: topic backlog cap reached")
@@ -63,6 +71,28 @@ type TopicPublisher interface {
 	Publish(ctx context.Context, namespace, mqType, topic, contentType string, payload []byte) error
 }
 
+// KeyedPublisher is implemented by publishers that can attach a message key to
+// an event. The key is the statestore topic's compaction identity
+// (TopicRetention.Compact); it is optional, and Publish is PublishKeyed with an
+// empty key.
+type KeyedPublisher interface {
+	PublishKeyed(ctx context.Context, namespace, mqType, topic, key, contentType string, payload []byte) error
+}
+
+// PublishKeyed publishes through p with key, or plainly when key is empty. A
+// publisher that cannot carry keys gets ErrKeyUnsupported rather than a publish
+// that silently drops the key (and with it the event's compaction identity).
+func PublishKeyed(ctx context.Context, p TopicPublisher, namespace, mqType, topic, key, contentType string, payload []byte) error {
+	if key == "" {
+		return p.Publish(ctx, namespace, mqType, topic, contentType, payload)
+	}
+	kp, ok := p.(KeyedPublisher)
+	if !ok {
+		return ErrKeyUnsupported
+	}
+	return kp.PublishKeyed(ctx, namespace, mqType, topic, key, contentType, payload)
+}
+
 // StreamForTopic returns the EventLog stream name for a namespaced topic.
 // Topics are namespace-scoped (RFC-0027, mirroring RFC-0024's same-namespace
 // destination rule R6); topic names are admission-validated to exclude "/" so
@@ -83,14 +113,19 @@ func NewStatestorePublisher(el statestore.EventLog) TopicPublisher {
 	return &statestorePublisher{el: el, maxBacklog: DefaultMaxTopicBacklog}
 }
 
-// Publish implements TopicPublisher: one AppendAny of one event. AppendAny is a
+// Publish implements TopicPublisher as an unkeyed PublishKeyed.
+func (p *statestorePublisher) Publish(ctx context.Context, namespace, mqType, topic, contentType string, payload []byte) error {
+	return p.PublishKeyed(ctx, namespace, mqType, topic, "", contentType, payload)
+}
+
+// PublishKeyed implements KeyedPublisher: one AppendAny of one event. AppendAny is a
 // single atomic round-trip (no client CAS loop), and Append returns only after
 // the write is durable, so a nil error IS the durability guarantee (E1).
 //
 // Inputs are re-validated at this sink (defense in depth — admission is the
 // authoritative gate, but the stream-name injectivity that namespace isolation
 // rests on must not depend on a single distant layer).
-func (p *statestorePublisher) Publish(ctx context.Context, namespace, mqType, topic, contentType string, payload []byte) error {
+func (p *statestorePublisher) PublishKeyed(ctx context.Context, namespace, mqType, topic, key, contentType string, payload []byte) error {
 	if mqType != fv1.MessageQueueTypeStatestore {
 		// Fixed label value: mqType is caller-supplied, and raw strings in a
 		// metric label would mint unbounded time series (the error carries the
@@ -106,6 +141,10 @@ func (p *statestorePublisher) Publish(ctx context.Context, namespace, mqType, to
 		recordPublish(ctx, mqType, "invalid")
 		return fmt.Errorf("mqpub: invalid topic name: %w", err)
 	}
+	if len(key) > MaxKeyLen {
+		recordPublish(ctx, mqType, "invalid")
+		return fmt.Errorf("mqpub: message key exceeds %d bytes", MaxKeyLen)
+	}
 	stream := StreamForTopic(namespace, topic)
 	// Backlog cap (soft — see DefaultMaxTopicBacklog): reject loudly rather than
 	// grow the shared store unboundedly. backlog = head − trim floor, where the
@@ -133,7 +172,7 @@ func (p *statestorePublisher) Publish(ctx context.Context, namespace, mqType, to
 		}
 	}
 	if _, err := p.el.Append(ctx, stream, statestore.AppendAny,
-		[]statestore.Event{{Type: contentType, Payload: payload}}); err != nil {
+		[]statestore.Event{{Type: contentType, Key: key, Payload: payload}}); err != nil {
 		recordPublish(ctx, mqType, "error")
 		return fmt.Errorf("mqpub: publishing to topic %s/%s: %w", namespace, topic, err)
 	}
@@ -7,6 +7,7 @@ package mqpub
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,6 +58,25 @@ func TestStatestorePublisherPublish(t *testing.T) {
 	require.Len(t, evs, 1)
 }
 
+// TestPublishKeyed: a key rides the statestore event (its compaction identity);
+// a path that cannot carry one refuses rather than dropping it.
+func TestPublishKeyed(t *testing.T) {
+	t.Parallel()
+	el := memEventLog(t)
+	pub := NewMultiPublisher(NewStatestorePublisher(el), NewEgressPublisher(memQueue(t), fv1.MessageQueueTypeKafka))
+
+	require.NoError(t, PublishKeyed(t.Context(), pub, "ns", fv1.MessageQueueTypeStatestore, "prices", "sku-1", "text/plain", []byte("9.99")))
+	evs, err := el.Read(t.Context(), StreamForTopic("ns", "prices"), 0, 0)
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+	assert.Equal(t, "sku-1", evs[0].Key)
+
+	err = PublishKeyed(t.Context(), pub, "ns", fv1.MessageQueueTypeKafka, "prices", "sku-1", "text/plain", []byte("9.99"))
+	assert.ErrorIs(t, err, ErrKeyUnsupported)
+	err = PublishKeyed(t.Context(), pub, "ns", fv1.MessageQueueTypeStatestore, "prices", strings.Repeat("k", MaxKeyLen+1), "", nil)
+	assert.ErrorContains(t, err, "message key exceeds")
+}
+
 func TestStatestorePublisherUnsupportedType(t *testing.T) {
 	t.Parallel()
 	p := NewStatestorePublisher(memEventLog(t))
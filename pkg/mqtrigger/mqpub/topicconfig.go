@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mqpub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fission/fission/pkg/statestore"
+)
+
+// Per-topic state lives in the statestore KV next to the stream it describes,
+// shared by the router's topic admin API (which writes it) and the statestore
+// mqt head (which enforces it). Topics are not CRDs, so there is no spec to hang
+// a policy on; the KV record is the topic's configuration of record.
+
+// TopicRetention is a statestore topic's retention policy (RFC-0027). Without
+// one, a topic keeps only what its slowest subscriber has not consumed (the
+// min-cursor trim) up to the global backstops. With one, the topic keeps its
+// consumed history too — so a subscription can be rewound with seek — bounded
+// by whichever limits are set; an unset limit falls back to the matching global
+// backstop. Trimming by the policy is the documented, broker-style loss the
+// backstops already are: a subscriber lagging past a limit loses the excess.
+type TopicRetention struct {
+	// MaxAge trims events older than this.
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// MaxEvents bounds the retained event count (the newest MaxEvents
+	// sequence numbers). Unset, a policy topic keeps MaxRetainedEvents.
+	MaxEvents int64 `json:"maxEvents,omitempty"`
+	// MaxBytes bounds the retained payload bytes.
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+	// Compact drops a keyed event once every subscriber has consumed it and a
+	// later event with the same key exists, so replayed history holds the
+	// latest value per key. Live subscribers always see every event.
+	Compact bool `json:"compact,omitempty"`
+}
+
+// MaxRetainedEvents is the largest count a retention policy may keep, and the
+// count a policy topic keeps when MaxEvents is unset. Retained history counts
+// toward the publish-side backlog cap (DefaultMaxTopicBacklog), and the reaper
+// trims once per interval, so the bound leaves half the cap as headroom for the
+// publishes between trims — a larger bound would turn into rejected publishes
+// rather than retention.
+const MaxRetainedEvents = DefaultMaxTopicBacklog / 2
+
+// IsZero reports whether no policy is set (the min-cursor default).
+func (r TopicRetention) IsZero() bool {
+	return r.MaxAge == nil && r.MaxEvents == 0 && r.MaxBytes == 0 && !r.Compact
+}
+
+// Validate rejects non-positive ages, negative limits, and a MaxEvents above
+// MaxRetainedEvents.
+func (r TopicRetention) Validate() error {
+	var errs []error
+	if r.MaxAge != nil && r.MaxAge.Duration <= 0 {
+		errs = append(errs, errors.New("maxAge must be positive"))
+	}
+	if r.MaxEvents < 0 || r.MaxEvents > MaxRetainedEvents {
+		errs = append(errs, fmt.Errorf("maxEvents must be between 0 and %d (half the topic backlog cap)", MaxRetainedEvents))
+	}
+	if r.MaxBytes < 0 {
+		errs = append(errs, errors.New("maxBytes must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// MaxAgeOr returns the policy's age bound, or def when unset.
+func (r TopicRetention) MaxAgeOr(def time.Duration) time.Duration {
+	if r.MaxAge == nil {
+		return def
+	}
+	return r.MaxAge.Duration
+}
+
+// RetainedEvents returns the policy's count bound, MaxRetainedEvents when unset.
+func (r TopicRetention) RetainedEvents() int64 {
+	if r.MaxEvents == 0 {
+		return MaxRetainedEvents
+	}
+	return r.MaxEvents
+}
+
+// TopicConfigScope is the KV scope of a topic's configuration records, per the
+// house Scope convention (Owner = <kind>/<name>).
+func TopicConfigScope(namespace, topic string) statestore.Scope {
+	return statestore.Scope{Namespace: namespace, Owner: "topic/" + topic, Keyspace: "config"}
+}
+
+const topicRetentionKey = "retention"
+
+// LoadTopicRetention reads a topic's retention policy; an absent record is the
+// zero policy. A corrupt record is an error, not the default: silently reverting
+// a topic to min-cursor trimming would discard the history it was set to keep.
+func LoadTopicRetention(ctx context.Context, kv statestore.KVStore, namespace, topic string) (TopicRetention, error) {
+	val, err := kv.Get(ctx, TopicConfigScope(namespace, topic), topicRetentionKey)
+	if errors.Is(err, statestore.ErrNotFound) {
+		return TopicRetention{}, nil
+	}
+	if err != nil {
+		return TopicRetention{}, err
+	}
+	var r TopicRetention
+	if err := json.Unmarshal(val.Data, &r); err != nil {
+		return TopicRetention{}, fmt.Errorf("corrupt retention record for topic %s/%s: %w", namespace, topic, err)
+	}
+	return r, nil
+}
+
+// SaveTopicRetention validates and stores a topic's retention policy; the zero
+// policy deletes the record (back to the min-cursor default).
+func SaveTopicRetention(ctx context.Context, kv statestore.KVStore, namespace, topic string, r TopicRetention) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	scope := TopicConfigScope(namespace, topic)
+	if r.IsZero() {
+		return kv.Delete(ctx, scope, topicRetentionKey, 0)
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return kv.Set(ctx, scope, topicRetentionKey, data, statestore.SetOptions{})
+}
+
+// CursorScope is the KV scope of a statestore MessageQueueTrigger's durable
+// cursor — the subscription's committed offset (the last delivered Seq) under
+// CursorKey, a decimal string CAS-advanced by the consumer. Exported so the
+// topic admin API can report and seek it.
+func CursorScope(namespace, trigger string) statestore.Scope {
+	return statestore.Scope{Namespace: namespace, Owner: "messagequeuetrigger/" + trigger, Keyspace: "cursor"}
+}
+
+// CursorKey is the cursor record's key within CursorScope.
+const CursorKey = "cursor"
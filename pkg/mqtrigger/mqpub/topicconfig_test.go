@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mqpub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fission/fission/pkg/statestore"
+)
+
+func TestTopicRetentionValidate(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, TopicRetention{}.Validate())
+	assert.NoError(t, TopicRetention{MaxAge: &metav1.Duration{Duration: time.Hour}, MaxEvents: 500, MaxBytes: 1 << 20, Compact: true}.Validate())
+	assert.Error(t, TopicRetention{MaxAge: &metav1.Duration{}}.Validate())
+	assert.Error(t, TopicRetention{MaxEvents: -1}.Validate())
+	assert.Error(t, TopicRetention{MaxEvents: MaxRetainedEvents + 1}.Validate(), "a bound near the backlog cap would reject publishes instead")
+	assert.Error(t, TopicRetention{MaxBytes: -1}.Validate())
+}
+
+func TestTopicRetentionRoundTrip(t *testing.T) {
+	t.Parallel()
+	caps, err := statestore.Open(t.Context(), statestore.Config{Driver: "memory"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = caps.Close() })
+	kv, err := caps.KV()
+	require.NoError(t, err)
+
+	r, err := LoadTopicRetention(t.Context(), kv, "ns", "orders")
+	require.NoError(t, err)
+	assert.True(t, r.IsZero(), "no record is the min-cursor default")
+
+	want := TopicRetention{MaxAge: &metav1.Duration{Duration: 24 * time.Hour}, MaxEvents: 1000, Compact: true}
+	require.NoError(t, SaveTopicRetention(t.Context(), kv, "ns", "orders", want))
+	got, err := LoadTopicRetention(t.Context(), kv, "ns", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, 24*time.Hour, got.MaxAgeOr(time.Minute))
+	assert.EqualValues(t, 1000, got.RetainedEvents())
+	assert.EqualValues(t, MaxRetainedEvents, TopicRetention{Compact: true}.RetainedEvents())
+
+	other, err := LoadTopicRetention(t.Context(), kv, "other", "orders")
+	require.NoError(t, err)
+	assert.True(t, other.IsZero(), "policies are namespaced like the topics they describe")
+
+	require.Error(t, SaveTopicRetention(t.Context(), kv, "ns", "orders", TopicRetention{MaxBytes: -1}))
+	require.NoError(t, SaveTopicRetention(t.Context(), kv, "ns", "orders", TopicRetention{}))
+	got, err = LoadTopicRetention(t.Context(), kv, "ns", "orders")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero(), "saving the zero policy clears it")
+}
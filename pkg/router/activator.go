@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/crd"
+	ferror "github.com/fission/fission/pkg/error"
+)
+
+// ScaleFromZeroClient is the optional executor-client facet the activator
+// uses to size a scale from zero by the requests it buffers. Implemented by
+// the executor client against POST /v2/scaleFromZero.
+type ScaleFromZeroClient interface {
+	ScaleFromZero(ctx context.Context, fn *fv1.Function, queued int) (string, error)
+}
+
+const (
+	activatorQueueFull = "queue_full"
+	activatorTimeout   = "timeout"
+)
+
+// activator buffers the requests of functions with ScaleToZero while they
+// scale from zero (see fv1.ScaleToZeroConfig). The first buffered request
+// starts one wake per function; the requests that join it wait for its
+// address, at most QueueSize of them and each for at most QueueTimeout. As
+// the queue grows the activator asks the executor for more replicas, so a
+// burst scales up by its depth rather than one replica at a time.
+type activator struct {
+	logger   logr.Logger
+	executor *executorResolver
+	// scaler sizes the scale from zero by the queue; nil scales to one
+	// replica through GetServiceForFunction, as any cold start.
+	scaler ScaleFromZeroClient
+
+	mu    sync.Mutex
+	wakes map[string]*wake // by function UID and generation
+}
+
+// wake is one function's scale from zero, shared by the requests buffered
+// on it.
+type wake struct {
+	fn *fv1.Function
+	// queued is the number of requests waiting, and requested the replicas
+	// last asked of the executor; both guarded by activator.mu.
+	queued    int
+	requested int
+
+	done   chan struct{}
+	svcURL *url.URL
+	err    error
+}
+
+func newActivator(logger logr.Logger, executor *executorResolver) *activator {
+	return &activator{
+		logger:   logger.WithName("activator"),
+		executor: executor,
+		wakes:    make(map[string]*wake),
+	}
+}
+
+// activate buffers one request to fn until the function has a ready replica
+// and returns its address. A full queue is answered 429; a request that
+// waits out QueueTimeout fails like a cold start that did not finish.
+func (a *activator) activate(ctx context.Context, fn *fv1.Function) (*url.URL, error) {
+	es := &fn.Spec.InvokeStrategy.ExecutionStrategy
+	cfg := es.ScaleToZero.Effective()
+	timeout, err := time.ParseDuration(cfg.QueueTimeout)
+	if err != nil {
+		timeout, _ = time.ParseDuration(fv1.DefaultScaleToZeroQueueTimeout)
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("function_namespace", fn.Namespace),
+		attribute.String("function_name", fn.Name),
+	)
+
+	key := crd.CacheKeyUGFromMeta(&fn.ObjectMeta).String()
+	a.mu.Lock()
+	w, ok := a.wakes[key]
+	if !ok {
+		w = &wake{fn: fn, done: make(chan struct{})}
+		a.wakes[key] = w
+	}
+	if w.queued >= cfg.QueueSize {
+		a.mu.Unlock()
+		activatorRejected.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("function_namespace", fn.Namespace),
+			attribute.String("function_name", fn.Name),
+			attribute.String("reason", activatorQueueFull),
+		))
+		return nil, ferror.MakeError(ferror.ErrorTooManyRequests,
+			fmt.Sprintf("function %s/%s is scaling from zero and its queue of %d requests is full", fn.Namespace, fn.Name, cfg.QueueSize))
+	}
+	w.queued++
+	queued := w.queued
+	grow := false
+	if replicas := es.ScaleFromZeroReplicas(queued); !ok || replicas > w.requested {
+		w.requested = replicas
+		grow = ok
+	}
+	a.mu.Unlock()
+
+	activatorQueued.Add(ctx, 1, attrs)
+	defer func() {
+		a.mu.Lock()
+		w.queued--
+		a.mu.Unlock()
+		activatorQueued.Add(context.Background(), -1, attrs)
+	}()
+
+	switch {
+	case !ok:
+		go a.wake(key, w, queued, timeout)
+	case grow && a.scaler != nil:
+		go a.grow(fn, queued, timeout)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-w.done:
+		return w.svcURL, w.err
+	case <-timer.C:
+		activatorRejected.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("function_namespace", fn.Namespace),
+			attribute.String("function_name", fn.Name),
+			attribute.String("reason", activatorTimeout),
+		))
+		return nil, ferror.MakeError(ferror.ErrorRequestTimeout,
+			fmt.Sprintf("function %s/%s did not scale from zero within %v", fn.Namespace, fn.Name, timeout))
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// wake scales w's function from zero for the requests queued so far and
+// hands the address to every request buffered on it. It runs detached from
+// the request that started it, so that request leaving early does not fail
+// the others.
+func (a *activator) wake(key string, w *wake, queued int, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	svcURL, err := a.scaleFromZero(ctx, w.fn, queued)
+	if err == nil {
+		a.executor.fmap.assign(&w.fn.ObjectMeta, svcURL)
+	} else {
+		a.logger.Error(err, "error scaling function from zero", "function", w.fn.Name, "namespace", w.fn.Namespace)
+	}
+
+	a.mu.Lock()
+	delete(a.wakes, key)
+	w.svcURL, w.err = svcURL, err
+	a.mu.Unlock()
+	close(w.done)
+}
+
+// grow asks the executor for the replicas a longer queue needs while the
+// wake is still waiting for the first.
+func (a *activator) grow(fn *fv1.Function, queued int, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := a.scaler.ScaleFromZero(ctx, a.executor.currentFunction(ctx, fn), queued); err != nil {
+		a.logger.V(1).Info("error growing scale from zero", "function", fn.Name, "namespace", fn.Namespace,
+			"queued", queued, "error", err.Error())
+	}
+}
+
+func (a *activator) scaleFromZero(ctx context.Context, fn *fv1.Function, queued int) (*url.URL, error) {
+	if a.scaler != nil {
+		addr, err := a.scaler.ScaleFromZero(ctx, a.executor.currentFunction(ctx, fn), queued)
+		if err == nil {
+			svcURL, err := url.Parse("http://" + addr)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing scaleFromZero address %q: %w", addr, err)
+			}
+			return svcURL, nil
+		}
+		if !ferror.IsNotFound(err) {
+			return nil, err
+		}
+		// An executor without /v2/scaleFromZero (upgrade-order safety)
+		// still scales the function to one replica.
+	}
+	return a.executor.fromExecutor(ctx, fn)
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/crd"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/router/endpointcache"
+)
+
+// stubZeroScaler answers ScaleFromZero and records the queue depth of each
+// call. With gate set, the first call waits on it, holding the function at
+// zero.
+type stubZeroScaler struct {
+	addr string
+	err  error
+	gate chan struct{}
+
+	mu     sync.Mutex
+	queued []int
+}
+
+func (s *stubZeroScaler) ScaleFromZero(_ context.Context, _ *fv1.Function, queued int) (string, error) {
+	s.mu.Lock()
+	s.queued = append(s.queued, queued)
+	first := len(s.queued) == 1
+	s.mu.Unlock()
+	if first && s.gate != nil {
+		<-s.gate
+	}
+	return s.addr, s.err
+}
+
+func (s *stubZeroScaler) calls() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.queued...)
+}
+
+func scaleToZeroFn(name string, cfg fv1.ScaleToZeroConfig) *fv1.Function {
+	fn := poolFn(name)
+	fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType = fv1.ExecutorTypeNewdeploy
+	fn.Spec.InvokeStrategy.ExecutionStrategy.MaxScale = 10
+	fn.Spec.InvokeStrategy.ExecutionStrategy.ScaleToZero = &cfg
+	return fn
+}
+
+func queuedOn(a *activator, fn *fv1.Function) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if w, ok := a.wakes[crd.CacheKeyUGFromMeta(&fn.ObjectMeta).String()]; ok {
+		return w.queued
+	}
+	return 0
+}
+
+func TestActivatorBuffersBurst(t *testing.T) {
+	t.Parallel()
+	ix := endpointcache.NewIndex() // no slices: scaled to zero
+	exec := &stubExecutor{addr: "unused"}
+	f := newFallbackForTest(t, ix, exec, nil)
+	scaler := &stubZeroScaler{addr: "svc-fn-zero.default", gate: make(chan struct{})}
+	f.activator.scaler = scaler
+	fn := scaleToZeroFn("fn-zero", fv1.ScaleToZeroConfig{QueueSize: 5, RequestsPerReplica: 2})
+
+	var wg sync.WaitGroup
+	results := make(chan ResolvedEntry, 5)
+	for n := 1; n <= 5; n++ {
+		wg.Go(func() {
+			entry, err := f.Resolve(t.Context(), fn, "")
+			assert.NoError(t, err)
+			results <- entry
+		})
+		require.Eventually(t, func() bool { return queuedOn(f.activator, fn) == n }, 2*time.Second, 10*time.Millisecond)
+	}
+
+	// A sixth request finds the queue full.
+	_, err := f.Resolve(t.Context(), fn, "")
+	require.Error(t, err)
+	assert.True(t, ferror.IsTooManyRequests(err), "a full queue answers 429, got %v", err)
+
+	// The wake asked for one replica; the queue asked for more at 3 and 5
+	// requests, one replica per two.
+	require.Eventually(t, func() bool { return len(scaler.calls()) == 3 }, 2*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []int{1, 3, 5}, scaler.calls())
+
+	close(scaler.gate)
+	wg.Wait()
+	close(results)
+	for entry := range results {
+		assert.Equal(t, "svc-fn-zero.default", entry.SvcURL.Host)
+	}
+	assert.Zero(t, exec.calls, "the activator RPCs through the scale-from-zero facet")
+	assert.Zero(t, queuedOn(f.activator, fn))
+}
+
+func TestActivatorQueueTimeout(t *testing.T) {
+	t.Parallel()
+	f := newFallbackForTest(t, endpointcache.NewIndex(), &stubExecutor{}, nil)
+	scaler := &stubZeroScaler{addr: "svc-fn-slow.default", gate: make(chan struct{})}
+	t.Cleanup(func() { close(scaler.gate) })
+	f.activator.scaler = scaler
+
+	_, err := f.Resolve(t.Context(), scaleToZeroFn("fn-slow", fv1.ScaleToZeroConfig{QueueTimeout: "1s"}), "")
+	require.Error(t, err)
+	var fe ferror.Error
+	require.ErrorAs(t, err, &fe)
+	assert.EqualValues(t, ferror.ErrorRequestTimeout, fe.Code)
+}
+
+func TestActivatorDegradesWithoutScaleFromZero(t *testing.T) {
+	t.Parallel()
+	exec := &stubExecutor{addr: "svc-fn-old.default"}
+	f := newFallbackForTest(t, endpointcache.NewIndex(), exec, nil)
+	scaler := &stubZeroScaler{err: ferror.MakeError(ferror.ErrorNotFound, "404 page not found")}
+	f.activator.scaler = scaler
+
+	entry, err := f.Resolve(t.Context(), scaleToZeroFn("fn-old", fv1.ScaleToZeroConfig{}), "")
+	require.NoError(t, err)
+	assert.Equal(t, "svc-fn-old.default", entry.SvcURL.Host)
+	assert.Len(t, scaler.calls(), 1)
+	assert.Equal(t, 1, exec.calls, "an executor without /v2/scaleFromZero still scales the function up")
+}
@@ -15,6 +15,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/mqtrigger/mqpub"
 	"github.com/fission/fission/pkg/router/asyncinvoke"
 	"github.com/fission/fission/pkg/statestore"
 
@@ -35,9 +36,17 @@ type asyncInvoker struct {
 	logger logr.Logger
 	// eventLog and publishTopic power the RFC-0027 topic admin surface
 	// (/v1/eventing/topic/*): peek reads the topic stream, publish goes through
-	// the same MultiPublisher as async topic destinations.
-	eventLog     statestore.EventLog
-	publishTopic asyncinvoke.TopicPublishFunc
+	// the same MultiPublisher as async topic destinations. topicPublisher is
+	// that MultiPublisher itself, for keyed publishes; topicKV holds the
+	// per-topic retention records and the subscription cursors.
+	eventLog       statestore.EventLog
+	publishTopic   asyncinvoke.TopicPublishFunc
+	topicPublisher mqpub.TopicPublisher
+	topicKV        statestore.KVStore
+	// jobs starts and polls job invocations (executor type job) and jobKV
+	// holds their records; see async_jobs.go. Either nil disables them.
+	jobs  JobClient
+	jobKV statestore.KVStore
 }
 
 func (a *asyncInvoker) enabled() bool { return a != nil && a.queue != nil }
@@ -52,6 +61,17 @@ func (a *asyncInvoker) handle(w http.ResponseWriter, r *http.Request, fn *fv1.Fu
 		return
 	}
 	cfg := funcConfigFromSpec(fn)
+	// The header overrides the function's default lane for this one request; a
+	// garbage value is the caller's mistake, so it is rejected rather than
+	// silently queued on the normal lane.
+	priority, ok := asyncinvoke.ParsePriority(r.Header.Get(asyncinvoke.HeaderInvokePriority))
+	if !ok {
+		http.Error(w, "invalid "+asyncinvoke.HeaderInvokePriority+": must be one of high, normal, low", http.StatusBadRequest)
+		return
+	}
+	if priority == "" {
+		priority = cfg.Priority
+	}
 	p := asyncinvoke.Params{
 		Namespace:       fn.Namespace,
 		Function:        fn.Name,
@@ -73,6 +93,7 @@ func (a *asyncInvoker) handle(w http.ResponseWriter, r *http.Request, fn *fv1.Fu
 		Policy:    cfg.Policy,
 		OnSuccess: cfg.OnSuccess,
 		OnFailure: cfg.OnFailure,
+		Priority:  priority,
 	}
 	id, err := asyncinvoke.Enqueue(r.Context(), a.queue, w, r, p)
 	if err != nil {
@@ -91,17 +112,34 @@ func (a *asyncInvoker) handle(w http.ResponseWriter, r *http.Request, fn *fv1.Fu
 }
 
 // funcConfigFromSpec is the single mapper from a Function to its resolved async
-// config (policy + destinations + timeout). Both the initial enqueue (handle) and
+// config (policy + destinations + timeout + lane). Both the initial enqueue (handle) and
 // each destination-chain hop (newFunctionConfigResolver) go through it, so the
 // fv1↔asyncinvoke translation lives in one place and cannot drift between them.
+//
+// A job function answers every delivery with 202 once its Job has started, so
+// its destinations are not the dispatcher's to fire: the job watcher fires
+// them when the Job finishes (see async_jobs.go).
 func funcConfigFromSpec(fn *fv1.Function) asyncinvoke.FunctionConfig {
-	onSuccess, onFailure := destinationsFromSpec(fn.Spec.Invocation, fn.Namespace)
+	var onSuccess, onFailure *asyncinvoke.Destination
+	if fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType != fv1.ExecutorTypeJob {
+		onSuccess, onFailure = destinationsFromSpec(fn.Spec.Invocation, fn.Namespace)
+	}
 	return asyncinvoke.FunctionConfig{
 		Policy:          policyFromSpec(fn.Spec.Invocation),
 		OnSuccess:       onSuccess,
 		OnFailure:       onFailure,
 		FunctionTimeout: fn.Spec.FunctionTimeout,
+		Priority:        priorityFromSpec(fn.Spec.Invocation),
+	}
+}
+
+// priorityFromSpec is the function's default lane; nil config or an empty
+// Priority is "" (the normal lane).
+func priorityFromSpec(ic *fv1.InvocationConfig) string {
+	if ic == nil {
+		return ""
 	}
+	return string(ic.Priority)
 }
 
 // newFunctionConfigResolver resolves a destination function's async config from the
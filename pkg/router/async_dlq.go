@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fission/fission/pkg/mqtrigger/mqpub"
@@ -27,13 +28,22 @@ import (
 // cross-namespace read/redrive/purge surface. `fission function dlq` signs its
 // requests with FISSION_INTERNAL_AUTH_SECRET the same way `test --async` does. When
 // async invocation is disabled the handlers return 501 rather than 404, so the
-// surface is discoverable. All operate on the single global asyncinvoke.DefaultQueue;
-// per-namespace scoping of access is a follow-up.
+// surface is discoverable. All operate on the single global asyncinvoke.DefaultQueue
+// unless ?queue= names another lane or an egress queue; per-namespace scoping of
+// access is a follow-up.
+//
+// list, export, and redrive/discard with all share one filter grammar on the query
+// string (dlqParseFilter): namespace, function, reason, error, since, until. purge
+// stays unconditional and rejects filters — selective deletion is discard, a
+// separate path so a filtered request can never reach an older router's purge
+// and empty the whole dead set.
 const (
 	dlqPathList    = "/v1/async/dlq/list"
 	dlqPathShow    = "/v1/async/dlq/show"
 	dlqPathRedrive = "/v1/async/dlq/redrive"
 	dlqPathPurge   = "/v1/async/dlq/purge"
+	dlqPathDiscard = "/v1/async/dlq/discard"
+	dlqPathExport  = "/v1/async/dlq/export"
 
 	// dlqDefaultLimit / dlqMaxLimit bound a list page; dlqShowScanCap bounds the
 	// scan a show performs looking for one id (the Queue has no get-by-id), so a
@@ -42,6 +52,12 @@ const (
 	dlqMaxLimit     = 1000
 	dlqShowScanCap  = 10000
 	dlqMaxBodyBytes = 1 << 20
+
+	// dlqBulkCap bounds how many dead letters one bulk redrive/discard scans, so a
+	// filter over a huge dead set is a bounded request; the response reports
+	// Truncated and the caller runs it again (the acted-on messages have left the
+	// dead set, so the next run makes progress).
+	dlqBulkCap = 10000
 )
 
 // dlqMessage is the list/show summary of one dead-lettered async invocation. The
@@ -75,12 +91,64 @@ type dlqShowResp struct {
 	EgressJob *mqpub.EgressJob `json:"egressJob,omitempty"`
 }
 
-type dlqRedriveReq struct {
+// dlqSelection picks the dead letters a redrive or discard acts on: explicit IDs,
+// or All for every match of the query-string filter. Exactly one is required.
+type dlqSelection struct {
 	IDs []string `json:"ids"`
+	All bool     `json:"all,omitempty"`
+}
+
+// dlqRedriveReq is a selection plus an optional Target. Without Target the
+// messages are redriven in place (the store's Redrive: same id, attempts reset).
+type dlqRedriveReq struct {
+	dlqSelection
+	Target *dlqRedriveTarget `json:"target,omitempty"`
+}
+
+// dlqRedriveTarget re-points a redrive at a different function in the same
+// namespace, or at an alias/version of one — e.g. a patched alias during an
+// incident. Alias and Version are mutually exclusive, as on FunctionReference.
+type dlqRedriveTarget struct {
+	Function string `json:"function"`
+	Alias    string `json:"alias,omitempty"`
+	Version  string `json:"version,omitempty"`
 }
 
 type dlqMutateResp struct {
 	Count int64 `json:"count"`
+	// Skipped counts retarget candidates that could not be re-enqueued (an
+	// undecodable envelope, or a target function that does not exist in the
+	// message's namespace); they stay dead-lettered.
+	Skipped int64 `json:"skipped,omitempty"`
+	// Truncated reports that the scan stopped at dlqBulkCap; more matches may
+	// remain.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// dlqFilter is a parsed filter: the store-side part (pushed into DeadLetters) and
+// the envelope-side part the store cannot see (namespace and function live in the
+// opaque body).
+type dlqFilter struct {
+	store     statestore.DeadLetterFilter
+	namespace string
+	function  string
+}
+
+func (f dlqFilter) isZero() bool {
+	return f.store.IsZero() && f.namespace == "" && f.function == ""
+}
+
+// match applies the envelope-side part. A function filter matches the bare name
+// and any alias-suffixed route of it (an alias destination's envelope carries
+// "name:alias").
+func (f dlqFilter) match(m dlqMessage) bool {
+	if f.namespace != "" && m.Namespace != f.namespace {
+		return false
+	}
+	if f.function != "" && m.Function != f.function && !strings.HasPrefix(m.Function, f.function+":") {
+		return false
+	}
+	return true
 }
 
 // registerAsyncDLQRoutes adds the DLQ admin endpoints to the INTERNAL mux, where
@@ -92,6 +160,8 @@ func (ts *HTTPTriggerSet) registerAsyncDLQRoutes(internal *httpmux.Mux) {
 	internal.HandleFunc(dlqPathShow, ts.dlqShow).Methods(http.MethodGet)
 	internal.HandleFunc(dlqPathRedrive, ts.dlqRedrive).Methods(http.MethodPost)
 	internal.HandleFunc(dlqPathPurge, ts.dlqPurge).Methods(http.MethodPost)
+	internal.HandleFunc(dlqPathDiscard, ts.dlqDiscard).Methods(http.MethodPost)
+	internal.HandleFunc(dlqPathExport, ts.dlqExport).Methods(http.MethodGet)
 }
 
 // dlqQueue returns the async DLQ queue, or writes 501 and returns false when async
@@ -110,7 +180,8 @@ func (ts *HTTPTriggerSet) dlqQueue(w http.ResponseWriter) (statestore.Queue, boo
 var dlqEgressQueueRegexp = regexp.MustCompile(`^mq-egress-[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
 
 // dlqQueueName resolves the ?queue= parameter: empty means the async invocation
-// queue; otherwise it must be an RFC-0027 broker egress queue (mq-egress-<type>).
+// queue (the normal lane); otherwise it must be one of the other priority lane
+// queues or an RFC-0027 broker egress queue (mq-egress-<type>).
 // Allowlisted by shape, not free-form — the DLQ surface must not become a
 // read/redrive/purge primitive over arbitrary statestore queues.
 func dlqQueueName(w http.ResponseWriter, r *http.Request) (string, bool) {
@@ -118,17 +189,19 @@ func dlqQueueName(w http.ResponseWriter, r *http.Request) (string, bool) {
 	if name == "" || name == asyncinvoke.DefaultQueue {
 		return asyncinvoke.DefaultQueue, true
 	}
-	if dlqEgressQueueRegexp.MatchString(name) {
+	if asyncinvoke.IsLaneQueue(name) || dlqEgressQueueRegexp.MatchString(name) {
 		return name, true
 	}
-	http.Error(w, "queue must be empty (async invocations) or an mq-egress-<type> egress queue", http.StatusBadRequest)
+	http.Error(w, "queue must be empty (async invocations), a priority lane queue ("+
+		asyncinvoke.QueueForPriority(asyncinvoke.PriorityHigh)+", "+
+		asyncinvoke.QueueForPriority(asyncinvoke.PriorityLow)+") or an mq-egress-<type> egress queue", http.StatusBadRequest)
 	return "", false
 }
 
-// dlqList returns a page of dead-lettered invocations, optionally filtered to one
-// namespace (a display convenience, not an authorization boundary — the internal
-// listener's HMAC gate is coarse in phase 3). ?limit bounds the page; ?token
-// continues from a prior page's nextToken.
+// dlqList returns a page of dead-lettered invocations matching the filter (the
+// namespace filter is a display convenience, not an authorization boundary — the
+// internal listener's HMAC gate is coarse in phase 3). ?limit bounds the page;
+// ?token continues from a prior page's nextToken.
 func (ts *HTTPTriggerSet) dlqList(w http.ResponseWriter, r *http.Request) {
 	q, ok := ts.dlqQueue(w)
 	if !ok {
@@ -138,12 +211,15 @@ func (ts *HTTPTriggerSet) dlqList(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
+	filter, ok := dlqParseFilter(w, r)
+	if !ok {
+		return
+	}
 	limit := dlqParseLimit(r.URL.Query().Get("limit"))
-	nsFilter := r.URL.Query().Get("namespace")
 	dead, err := q.DeadLetters(r.Context(), queueName, statestore.Page{
 		Token: r.URL.Query().Get("token"),
 		Limit: limit,
-	})
+	}, filter.store)
 	if err != nil {
 		ts.logger.Error(err, "listing async dead letters")
 		http.Error(w, "listing dead letters", http.StatusInternalServerError)
@@ -152,13 +228,13 @@ func (ts *HTTPTriggerSet) dlqList(w http.ResponseWriter, r *http.Request) {
 	resp := dlqListResp{}
 	for _, d := range dead {
 		m := dlqSummary(d)
-		if nsFilter != "" && m.Namespace != nsFilter {
+		if !filter.match(m) {
 			continue
 		}
 		resp.Messages = append(resp.Messages, m)
 	}
 	// A full page implies there may be more; the last raw id is the continuation
-	// token (paging keys on the id regardless of the namespace filter).
+	// token (paging keys on the id regardless of the envelope-side filter).
 	if len(dead) == limit && len(dead) > 0 {
 		resp.NextToken = dead[len(dead)-1].ID
 	}
@@ -185,7 +261,7 @@ func (ts *HTTPTriggerSet) dlqShow(w http.ResponseWriter, r *http.Request) {
 	token := ""
 	scanned := 0
 	for scanned < dlqShowScanCap {
-		dead, err := q.DeadLetters(r.Context(), queueName, statestore.Page{Token: token, Limit: dlqDefaultLimit})
+		dead, err := q.DeadLetters(r.Context(), queueName, statestore.Page{Token: token, Limit: dlqDefaultLimit}, statestore.DeadLetterFilter{})
 		if err != nil {
 			ts.logger.Error(err, "reading async dead letters")
 			http.Error(w, "reading dead letters", http.StatusInternalServerError)
@@ -215,9 +291,11 @@ func (ts *HTTPTriggerSet) dlqShow(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "dead-lettered message not found", http.StatusNotFound)
 }
 
-// dlqRedrive re-enqueues the given dead-lettered invocations (attempts reset) so
-// they are delivered again. Ids that are not currently dead are skipped by the
-// store; Count reports the number actually re-enqueued (may be < len(ids)).
+// dlqRedrive re-enqueues dead-lettered invocations (attempts reset) so they are
+// delivered again: the given ids, or with all every match of the query-string
+// filter (up to dlqBulkCap). Ids that are not currently dead are skipped by the
+// store; Count reports the number actually re-enqueued (may be < len(ids)). With
+// a target the invocations are re-pointed instead (dlqRetarget).
 func (ts *HTTPTriggerSet) dlqRedrive(w http.ResponseWriter, r *http.Request) {
 	q, ok := ts.dlqQueue(w)
 	if !ok {
@@ -231,21 +309,179 @@ func (ts *HTTPTriggerSet) dlqRedrive(w http.ResponseWriter, r *http.Request) {
 	if !dlqDecodeJSON(w, r, &req) {
 		return
 	}
-	if len(req.IDs) == 0 {
-		http.Error(w, "ids must not be empty", http.StatusBadRequest)
+	if req.Target != nil {
+		if msg := req.Target.validate(); msg != "" {
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		if !asyncinvoke.IsLaneQueue(queueName) {
+			http.Error(w, "a redrive target applies only to async invocation queues, not egress queues", http.StatusBadRequest)
+			return
+		}
+	}
+	// Retargeting needs the bodies, so even an id list is resolved by scan.
+	dead, ids, truncated, ok := ts.dlqSelect(w, r, q, queueName, req.dlqSelection, req.Target != nil)
+	if !ok {
 		return
 	}
-	n, err := q.Redrive(r.Context(), queueName, req.IDs)
-	if err != nil {
-		ts.logger.Error(err, "redriving async dead letters")
-		http.Error(w, "redriving dead letters", http.StatusInternalServerError)
+	resp := dlqMutateResp{Truncated: truncated}
+	if req.Target != nil {
+		resp.Count, resp.Skipped = ts.dlqRetarget(r, q, queueName, dead, *req.Target)
+		dlqWriteJSON(w, ts, resp)
 		return
 	}
-	dlqWriteJSON(w, ts, dlqMutateResp{Count: n})
+	if len(ids) > 0 {
+		n, err := q.Redrive(r.Context(), queueName, ids)
+		if err != nil {
+			ts.logger.Error(err, "redriving async dead letters")
+			http.Error(w, "redriving dead letters", http.StatusInternalServerError)
+			return
+		}
+		resp.Count = n
+	}
+	dlqWriteJSON(w, ts, resp)
+}
+
+// dlqDiscard permanently deletes the selected dead letters — the given ids, or
+// with all every match of the query-string filter (up to dlqBulkCap) — and
+// reports the count removed.
+func (ts *HTTPTriggerSet) dlqDiscard(w http.ResponseWriter, r *http.Request) {
+	q, ok := ts.dlqQueue(w)
+	if !ok {
+		return
+	}
+	queueName, ok := dlqQueueName(w, r)
+	if !ok {
+		return
+	}
+	var req dlqSelection
+	if !dlqDecodeJSON(w, r, &req) {
+		return
+	}
+	_, ids, truncated, ok := ts.dlqSelect(w, r, q, queueName, req, false)
+	if !ok {
+		return
+	}
+	resp := dlqMutateResp{Truncated: truncated}
+	if len(ids) > 0 {
+		n, err := q.Discard(r.Context(), queueName, ids)
+		if err != nil {
+			ts.logger.Error(err, "discarding async dead letters")
+			http.Error(w, "discarding dead letters", http.StatusInternalServerError)
+			return
+		}
+		resp.Count = n
+	}
+	dlqWriteJSON(w, ts, resp)
+}
+
+// dlqSelect validates a selection against the query-string filter and resolves
+// it to ids (and, when bodies is set or All is, the dead messages themselves).
+// An explicit id list needs no scan unless bodies are wanted. It writes the 4xx/
+// 5xx itself and returns ok=false on failure.
+func (ts *HTTPTriggerSet) dlqSelect(w http.ResponseWriter, r *http.Request, q statestore.Queue, queueName string, sel dlqSelection, bodies bool) ([]statestore.DeadMessage, []string, bool, bool) {
+	filter, ok := dlqParseFilter(w, r)
+	if !ok {
+		return nil, nil, false, false
+	}
+	switch {
+	case len(sel.IDs) > 0 && sel.All:
+		http.Error(w, "ids and all are mutually exclusive", http.StatusBadRequest)
+		return nil, nil, false, false
+	case len(sel.IDs) == 0 && !sel.All:
+		http.Error(w, "ids must not be empty", http.StatusBadRequest)
+		return nil, nil, false, false
+	case len(sel.IDs) > 0 && !filter.isZero():
+		http.Error(w, "filter parameters apply only with all", http.StatusBadRequest)
+		return nil, nil, false, false
+	}
+	if !sel.All && !bodies {
+		return nil, sel.IDs, false, true
+	}
+	dead, truncated, err := dlqCollect(r, q, queueName, filter, sel.IDs)
+	if err != nil {
+		ts.logger.Error(err, "reading async dead letters")
+		http.Error(w, "reading dead letters", http.StatusInternalServerError)
+		return nil, nil, false, false
+	}
+	return dead, dlqIDs(dead), truncated, true
+}
+
+func (t dlqRedriveTarget) validate() string {
+	switch {
+	case t.Function == "":
+		return "target.function is required"
+	case strings.Contains(t.Function, ":"):
+		return "target.function must be a bare function name; use target.alias or target.version"
+	case t.Alias != "" && t.Version != "":
+		return "target.alias and target.version are mutually exclusive"
+	}
+	return ""
+}
+
+// dlqRetarget re-enqueues each dead invocation at target and discards the
+// original, returning (re-enqueued, skipped). The target is same-namespace (the
+// message's own), mirroring destinations. When the Function cache is available
+// the target's own async config is stamped — policy, destinations, timeout and
+// lane — exactly as fireDestination stamps a destination hop; a target missing
+// from a message's namespace skips that message rather than enqueueing a
+// delivery that can only 404 into the DLQ again.
+//
+// The new invocation gets a fresh id and EnqueueTime (a fresh MaxAge window —
+// an expired invocation redriven at its original age would expire on the spot).
+// Enqueue-then-discard is not atomic: the enqueue carries a dedup key derived
+// from the dead id, so if the discard fails and the operator retries, the
+// re-enqueue collapses onto the still-pending first one instead of doubling it.
+func (ts *HTTPTriggerSet) dlqRetarget(r *http.Request, q statestore.Queue, queueName string, dead []statestore.DeadMessage, target dlqRedriveTarget) (int64, int64) {
+	ctx := r.Context()
+	var resolve asyncinvoke.FunctionConfigResolver
+	if ts.client != nil {
+		resolve = newFunctionConfigResolver(ts.client, ts.logger)
+	}
+	dest := asyncinvoke.Destination{FunctionName: target.Function, Alias: target.Alias, Version: target.Version}
+	var count, skipped int64
+	for _, d := range dead {
+		env, err := asyncinvoke.Decode(d.Body)
+		if err != nil || env.Function == "" {
+			skipped++
+			continue
+		}
+		if resolve != nil {
+			cfg, found := resolve(ctx, env.Namespace, target.Function)
+			if !found {
+				skipped++
+				continue
+			}
+			env.FunctionTimeout = cfg.FunctionTimeout
+			env.Policy = cfg.Policy
+			env.OnSuccess, env.OnFailure = cfg.OnSuccess, cfg.OnFailure
+			env.Priority = cfg.Priority
+		}
+		env = env.Retarget(dest, time.Now())
+		body, err := env.Encode()
+		if err != nil {
+			skipped++
+			continue
+		}
+		lane := asyncinvoke.QueueForPriority(env.Priority)
+		if _, err := q.Enqueue(ctx, lane, statestore.Message{Body: body}, statestore.EnqueueOptions{DedupKey: "dlq-retarget:" + d.ID}); err != nil {
+			ts.logger.Error(err, "re-enqueuing retargeted dead letter", "id", d.ID, "function", target.Function)
+			skipped++
+			continue
+		}
+		if _, err := q.Discard(ctx, queueName, []string{d.ID}); err != nil {
+			// The new invocation is queued; the original stays dead and a retry
+			// collapses onto the pending copy (see above).
+			ts.logger.Error(err, "discarding retargeted dead letter", "id", d.ID)
+		}
+		count++
+	}
+	return count, skipped
 }
 
 // dlqPurge permanently deletes every dead-lettered invocation and reports the
-// count removed.
+// count removed. Filters are rejected rather than ignored: a filtered delete is
+// discard.
 func (ts *HTTPTriggerSet) dlqPurge(w http.ResponseWriter, r *http.Request) {
 	q, ok := ts.dlqQueue(w)
 	if !ok {
@@ -255,6 +491,14 @@ func (ts *HTTPTriggerSet) dlqPurge(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
+	filter, ok := dlqParseFilter(w, r)
+	if !ok {
+		return
+	}
+	if !filter.isZero() {
+		http.Error(w, "purge deletes every dead letter and takes no filter; use "+dlqPathDiscard+" with all", http.StatusBadRequest)
+		return
+	}
 	n, err := q.Purge(r.Context(), queueName)
 	if err != nil {
 		ts.logger.Error(err, "purging async dead letters")
@@ -264,6 +508,142 @@ func (ts *HTTPTriggerSet) dlqPurge(w http.ResponseWriter, r *http.Request) {
 	dlqWriteJSON(w, ts, dlqMutateResp{Count: n})
 }
 
+// dlqExport streams every dead letter matching the filter as NDJSON, one show
+// record (summary plus decoded envelope or egress job) per line, for offline
+// analysis or an incident ticket. It pages the store as it writes, so the export
+// is not bounded by dlqBulkCap and does not buffer the dead set.
+func (ts *HTTPTriggerSet) dlqExport(w http.ResponseWriter, r *http.Request) {
+	q, ok := ts.dlqQueue(w)
+	if !ok {
+		return
+	}
+	queueName, ok := dlqQueueName(w, r)
+	if !ok {
+		return
+	}
+	filter, ok := dlqParseFilter(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	enc := json.NewEncoder(w)
+	token := ""
+	for {
+		dead, err := q.DeadLetters(r.Context(), queueName, statestore.Page{Token: token, Limit: dlqMaxLimit}, filter.store)
+		if err != nil {
+			// Headers (and possibly lines) are already out; a truncated stream is
+			// all that can signal the failure.
+			ts.logger.Error(err, "exporting async dead letters")
+			return
+		}
+		for _, d := range dead {
+			rec := dlqShowRecord(d)
+			if !filter.match(rec.dlqMessage) {
+				continue
+			}
+			if err := enc.Encode(rec); err != nil {
+				return // client went away
+			}
+		}
+		if len(dead) < dlqMaxLimit {
+			return
+		}
+		token = dead[len(dead)-1].ID
+	}
+}
+
+// dlqCollect gathers the dead letters matching filter — or, with ids set, those
+// ids — scanning at most dlqBulkCap messages. truncated reports that the scan
+// stopped at the cap with more dead letters possibly remaining.
+func dlqCollect(r *http.Request, q statestore.Queue, queueName string, filter dlqFilter, ids []string) ([]statestore.DeadMessage, bool, error) {
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	var out []statestore.DeadMessage
+	token := ""
+	for scanned := 0; scanned < dlqBulkCap; {
+		limit := min(dlqMaxLimit, dlqBulkCap-scanned)
+		dead, err := q.DeadLetters(r.Context(), queueName, statestore.Page{Token: token, Limit: limit}, filter.store)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, d := range dead {
+			switch {
+			case len(want) > 0:
+				if want[d.ID] {
+					out = append(out, d)
+				}
+			case filter.match(dlqSummary(d)):
+				out = append(out, d)
+			}
+		}
+		scanned += len(dead)
+		if len(dead) < limit || (len(want) > 0 && len(out) == len(want)) {
+			return out, false, nil
+		}
+		token = dead[len(dead)-1].ID
+	}
+	return out, true, nil
+}
+
+func dlqIDs(dead []statestore.DeadMessage) []string {
+	ids := make([]string, 0, len(dead))
+	for _, d := range dead {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
+
+// dlqParseFilter reads the shared filter grammar: namespace and function match
+// the decoded envelope; reason is the exact dead-letter reason and error a
+// case-insensitive substring of it; since/until bound the time of death, each an
+// RFC 3339 timestamp or a Go duration meaning that long ago (since=1h). A bad
+// value is a 400, never an unfiltered bulk operation.
+func dlqParseFilter(w http.ResponseWriter, r *http.Request) (dlqFilter, bool) {
+	qs := r.URL.Query()
+	f := dlqFilter{
+		namespace: qs.Get("namespace"),
+		function:  qs.Get("function"),
+		store: statestore.DeadLetterFilter{
+			Reason:         qs.Get("reason"),
+			ReasonContains: qs.Get("error"),
+		},
+	}
+	now := time.Now()
+	for _, b := range []struct {
+		param string
+		dst   *time.Time
+	}{{"since", &f.store.DiedAfter}, {"until", &f.store.DiedBefore}} {
+		raw := qs.Get(b.param)
+		if raw == "" {
+			continue
+		}
+		t, err := dlqParseTime(raw, now)
+		if err != nil {
+			http.Error(w, b.param+" must be an RFC 3339 timestamp or a duration (e.g. 30m)", http.StatusBadRequest)
+			return dlqFilter{}, false
+		}
+		*b.dst = t
+	}
+	if !f.store.DiedAfter.IsZero() && !f.store.DiedBefore.IsZero() && !f.store.DiedAfter.Before(f.store.DiedBefore) {
+		http.Error(w, "since must be before until", http.StatusBadRequest)
+		return dlqFilter{}, false
+	}
+	return f, true
+}
+
+func dlqParseTime(raw string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return time.Time{}, errors.New("invalid time")
+	}
+	return now.Add(-d), nil
+}
+
 // dlqSummary maps a DeadMessage to the list summary, decoding the body for
 // display fields (best-effort — a corrupt record still lists): an async
 // invocation envelope yields namespace/function, a broker egress job yields
@@ -288,6 +668,12 @@ func dlqSummary(d statestore.DeadMessage) dlqMessage {
 }
 
 func dlqWriteShow(w http.ResponseWriter, ts *HTTPTriggerSet, d statestore.DeadMessage) {
+	dlqWriteJSON(w, ts, dlqShowRecord(d))
+}
+
+// dlqShowRecord is the full record for one dead letter: show's response and one
+// export line.
+func dlqShowRecord(d statestore.DeadMessage) dlqShowResp {
 	resp := dlqShowResp{dlqMessage: dlqSummary(d)}
 	// The gates mirror dlqSummary's classification: a lenient json.Unmarshal
 	// happily decodes an EgressJob body into a half-empty Envelope, so decode
@@ -297,7 +683,7 @@ func dlqWriteShow(w http.ResponseWriter, ts *HTTPTriggerSet, d statestore.DeadMe
 	} else if job := new(mqpub.EgressJob); json.Unmarshal(d.Body, job) == nil && job.Topic != "" {
 		resp.EgressJob = job
 	}
-	dlqWriteJSON(w, ts, resp)
+	return resp
 }
 
 func dlqParseLimit(raw string) int {
@@ -127,7 +127,7 @@ func TestDLQRedrive(t *testing.T) {
 	assert.EqualValues(t, 1, resp.Count)
 
 	// The redriven message left the dead set (and is leasable again); the other stays.
-	dead, err := q.DeadLetters(t.Context(), asyncinvoke.DefaultQueue, statestore.Page{})
+	dead, err := q.DeadLetters(t.Context(), asyncinvoke.DefaultQueue, statestore.Page{}, statestore.DeadLetterFilter{})
 	require.NoError(t, err)
 	require.Len(t, dead, 1)
 	assert.Equal(t, ids[1], dead[0].ID)
@@ -168,7 +168,7 @@ func TestDLQPurge(t *testing.T) {
 	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
 	assert.EqualValues(t, 2, resp.Count)
 
-	dead, err := q.DeadLetters(t.Context(), asyncinvoke.DefaultQueue, statestore.Page{})
+	dead, err := q.DeadLetters(t.Context(), asyncinvoke.DefaultQueue, statestore.Page{}, statestore.DeadLetterFilter{})
 	require.NoError(t, err)
 	assert.Empty(t, dead, "purge emptied the dead set")
 }
@@ -183,7 +183,7 @@ func TestDLQDisabledReturns501(t *testing.T) {
 	}
 	for name, ts := range cases {
 		t.Run(name, func(t *testing.T) {
-			for _, h := range []http.HandlerFunc{ts.dlqList, ts.dlqShow, ts.dlqRedrive, ts.dlqPurge} {
+			for _, h := range []http.HandlerFunc{ts.dlqList, ts.dlqShow, ts.dlqRedrive, ts.dlqPurge, ts.dlqDiscard, ts.dlqExport} {
 				rr := httptest.NewRecorder()
 				h(rr, httptest.NewRequest(http.MethodGet, "/", strings.NewReader(`{"ids":["x"]}`)))
 				assert.Equal(t, http.StatusNotImplemented, rr.Code)
@@ -192,7 +192,7 @@ func TestDLQDisabledReturns501(t *testing.T) {
 	}
 }
 
-// TestDLQRoutesOnInternalListenerNotPublic asserts the four DLQ admin paths are
+// TestDLQRoutesOnInternalListenerNotPublic asserts the DLQ admin paths are
 // registered on the INTERNAL mux (where the listener-level HMAC verifier gates
 // them) and are absent from the PUBLIC mux — so an operator running with the
 // default authentication.enabled=false does not expose an unauthenticated
@@ -210,6 +210,8 @@ func TestDLQRoutesOnInternalListenerNotPublic(t *testing.T) {
 		{http.MethodGet, dlqPathShow},
 		{http.MethodPost, dlqPathRedrive},
 		{http.MethodPost, dlqPathPurge},
+		{http.MethodPost, dlqPathDiscard},
+		{http.MethodGet, dlqPathExport},
 	} {
 		assert.Truef(t, muxMatches(internal, tc.method, tc.path), "%s %s must be on the internal mux", tc.method, tc.path)
 		assert.Falsef(t, muxMatches(public, tc.method, tc.path), "%s %s must NOT be on the public mux", tc.method, tc.path)
@@ -252,11 +254,18 @@ func TestDLQQueueParam(t *testing.T) {
 	assert.Empty(t, resp.Messages[0].Function)
 
 	// A malformed queue name is a 400, not an arbitrary-queue read.
-	for _, bad := range []string{"mq-egress-", "mq-egress-Kafka", "mq-egress-a/b", "state_queue", "asyncinv2"} {
+	for _, bad := range []string{"mq-egress-", "mq-egress-Kafka", "mq-egress-a/b", "state_queue", "asyncinv2", "asyncinv-urgent"} {
 		rr := httptest.NewRecorder()
 		ts.dlqList(rr, httptest.NewRequest(http.MethodGet, dlqPathList+"?queue="+bad, nil))
 		assert.Equalf(t, http.StatusBadRequest, rr.Code, "queue %q must be rejected", bad)
 	}
+
+	// The other priority lanes are allowlisted by name.
+	for _, lane := range []string{"asyncinv-high", "asyncinv-low"} {
+		rr := httptest.NewRecorder()
+		ts.dlqList(rr, httptest.NewRequest(http.MethodGet, dlqPathList+"?queue="+lane, nil))
+		assert.Equalf(t, http.StatusOK, rr.Code, "lane queue %q must be accepted", lane)
+	}
 }
 
 // TestDLQShowEgressJob: show on an egress dead letter returns the decoded
@@ -277,3 +286,185 @@ func TestDLQShowEgressJob(t *testing.T) {
 	assert.Equal(t, "orders", resp.EgressJob.Topic)
 	assert.Equal(t, []byte("ev-1"), resp.EgressJob.Payload, "the failed event's payload is inspectable")
 }
+
+// dlqKill enqueues an envelope for ns/fn and dead-letters it with reason.
+func dlqKill(t *testing.T, q statestore.Queue, ns, fn, reason string) string {
+	t.Helper()
+	body, err := asyncinvoke.Envelope{
+		Version: asyncinvoke.EnvelopeVersion, Namespace: ns, Function: fn,
+		Body: []byte("payload-" + fn), EnqueueTime: time.Unix(1_000_000, 0),
+		Policy: asyncinvoke.Policy{MaxAttempts: 7},
+	}.Encode()
+	require.NoError(t, err)
+	id, err := q.Enqueue(t.Context(), asyncinvoke.DefaultQueue, statestore.Message{Body: body}, statestore.EnqueueOptions{})
+	require.NoError(t, err)
+	l, err := q.Lease(t.Context(), asyncinvoke.DefaultQueue, 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, l, 1)
+	require.NoError(t, q.Kill(t.Context(), l[0].Receipt, reason))
+	return id
+}
+
+func dlqListIDs(t *testing.T, ts *HTTPTriggerSet, query string) []string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	ts.dlqList(rr, httptest.NewRequest(http.MethodGet, dlqPathList+"?"+query, nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp dlqListResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	var ids []string
+	for _, m := range resp.Messages {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+// TestDLQListFilters: function (bare name and its alias routes), reason, error
+// substring and time-of-death filters combine with AND; a bad bound is a 400.
+func TestDLQListFilters(t *testing.T) {
+	t.Parallel()
+	ts, q, _ := dlqTestSet(t)
+	a4 := dlqKill(t, q, "ns1", "fn-a", asyncinvoke.ReasonHTTP4xx)
+	aAlias := dlqKill(t, q, "ns1", "fn-a:live", statestore.ReasonRetriesExhausted)
+	b := dlqKill(t, q, "ns1", "fn-b", "Upstream TIMEOUT after 30s")
+
+	assert.ElementsMatch(t, []string{a4, aAlias}, dlqListIDs(t, ts, "function=fn-a"))
+	assert.Equal(t, []string{a4}, dlqListIDs(t, ts, "function=fn-a&reason=http_4xx"))
+	assert.Equal(t, []string{b}, dlqListIDs(t, ts, "error=timeout"))
+	assert.Len(t, dlqListIDs(t, ts, "since=1h"), 3)
+	assert.Empty(t, dlqListIDs(t, ts, "until=1h"))
+	assert.Len(t, dlqListIDs(t, ts, "since="+time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)), 3)
+
+	for _, bad := range []string{"since=yesterday", "until=-5m", "since=1m&until=1h"} {
+		rr := httptest.NewRecorder()
+		ts.dlqList(rr, httptest.NewRequest(http.MethodGet, dlqPathList+"?"+bad, nil))
+		assert.Equalf(t, http.StatusBadRequest, rr.Code, "%s must be rejected", bad)
+	}
+}
+
+// TestDLQRedriveAllMatching: all redrives every match of the filter and nothing
+// else; ids with all, or ids with a filter, are ambiguous and rejected.
+func TestDLQRedriveAllMatching(t *testing.T) {
+	t.Parallel()
+	ts, q, _ := dlqTestSet(t)
+	a := dlqKill(t, q, "ns1", "fn-a", asyncinvoke.ReasonHTTP4xx)
+	b := dlqKill(t, q, "ns1", "fn-b", asyncinvoke.ReasonHTTP4xx)
+
+	rr := httptest.NewRecorder()
+	ts.dlqRedrive(rr, httptest.NewRequest(http.MethodPost, dlqPathRedrive+"?function=fn-a", strings.NewReader(`{"all":true}`)))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp dlqMutateResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.EqualValues(t, 1, resp.Count)
+	assert.False(t, resp.Truncated)
+	assert.Equal(t, []string{b}, dlqListIDs(t, ts, ""), "only the match left the dead set")
+
+	for _, tc := range []struct{ query, body string }{
+		{"", `{"ids":["` + a + `"],"all":true}`},
+		{"?reason=x", `{"ids":["` + a + `"]}`},
+		{"", `{"all":false}`},
+	} {
+		rr := httptest.NewRecorder()
+		ts.dlqRedrive(rr, httptest.NewRequest(http.MethodPost, dlqPathRedrive+tc.query, strings.NewReader(tc.body)))
+		assert.Equalf(t, http.StatusBadRequest, rr.Code, "%s %s", tc.query, tc.body)
+	}
+}
+
+// TestDLQRedriveRetarget: a target re-enqueues the original request at the new
+// function/alias with a fresh id and MaxAge window, keeps the lane, and removes
+// the dead original.
+func TestDLQRedriveRetarget(t *testing.T) {
+	t.Parallel()
+	ts, q, _ := dlqTestSet(t)
+	id := dlqKill(t, q, "ns1", "fn-a", asyncinvoke.ReasonHTTP4xx)
+
+	rr := httptest.NewRecorder()
+	body := `{"ids":["` + id + `"],"target":{"function":"fn-a","alias":"patched"}}`
+	ts.dlqRedrive(rr, httptest.NewRequest(http.MethodPost, dlqPathRedrive, strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp dlqMutateResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.EqualValues(t, 1, resp.Count)
+	assert.Empty(t, dlqListIDs(t, ts, ""), "the original left the dead set")
+
+	l, err := q.Lease(t.Context(), asyncinvoke.DefaultQueue, 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, l, 1)
+	assert.NotEqual(t, id, l[0].ID, "a retargeted invocation is a new one")
+	env, err := asyncinvoke.Decode(l[0].Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ns1", env.Namespace, "a target is always same-namespace")
+	assert.Equal(t, "fn-a:patched", env.Function)
+	assert.Equal(t, []byte("payload-fn-a"), env.Body, "the original request is replayed")
+	assert.Equal(t, 7, env.Policy.MaxAttempts, "without a Function cache the original policy is kept")
+	assert.WithinDuration(t, time.Now(), env.EnqueueTime, time.Minute, "fresh MaxAge window")
+
+	for _, bad := range []string{
+		`{"all":true,"target":{}}`,
+		`{"all":true,"target":{"function":"fn:alias"}}`,
+		`{"all":true,"target":{"function":"fn","alias":"a","version":"v"}}`,
+	} {
+		rr := httptest.NewRecorder()
+		ts.dlqRedrive(rr, httptest.NewRequest(http.MethodPost, dlqPathRedrive, strings.NewReader(bad)))
+		assert.Equalf(t, http.StatusBadRequest, rr.Code, "%s", bad)
+	}
+	// Egress jobs have no function to re-point.
+	rr = httptest.NewRecorder()
+	ts.dlqRedrive(rr, httptest.NewRequest(http.MethodPost, dlqPathRedrive+"?queue=mq-egress-kafka",
+		strings.NewReader(`{"all":true,"target":{"function":"fn"}}`)))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// TestDLQDiscard: discard deletes the selected dead letters — by id, or every
+// match of a filter — leaving the rest; purge refuses a filter outright.
+func TestDLQDiscard(t *testing.T) {
+	t.Parallel()
+	ts, q, _ := dlqTestSet(t)
+	byID := dlqKill(t, q, "ns1", "fn-a", asyncinvoke.ReasonHTTP4xx)
+	dlqKill(t, q, "ns1", "fn-b", asyncinvoke.ReasonHTTP4xx)
+	keep := dlqKill(t, q, "ns2", "fn-b", asyncinvoke.ReasonHTTP4xx)
+
+	discard := func(query, body string) dlqMutateResp {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		ts.dlqDiscard(rr, httptest.NewRequest(http.MethodPost, dlqPathDiscard+query, strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var resp dlqMutateResp
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp
+	}
+	assert.EqualValues(t, 1, discard("", `{"ids":["`+byID+`"]}`).Count)
+	assert.EqualValues(t, 1, discard("?namespace=ns1", `{"all":true}`).Count)
+	assert.Equal(t, []string{keep}, dlqListIDs(t, ts, ""))
+
+	rr := httptest.NewRecorder()
+	ts.dlqPurge(rr, httptest.NewRequest(http.MethodPost, dlqPathPurge+"?namespace=ns2", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code, "a filtered purge must not empty the dead set")
+	assert.Equal(t, []string{keep}, dlqListIDs(t, ts, ""))
+}
+
+// TestDLQExportNDJSON: export streams one full show record per matching dead
+// letter, one JSON object per line.
+func TestDLQExportNDJSON(t *testing.T) {
+	t.Parallel()
+	ts, q, _ := dlqTestSet(t)
+	a := dlqKill(t, q, "ns1", "fn-a", asyncinvoke.ReasonHTTP4xx)
+	dlqKill(t, q, "ns1", "fn-b", asyncinvoke.ReasonHTTP4xx)
+	a2 := dlqKill(t, q, "ns1", "fn-a", asyncinvoke.ReasonExpired)
+
+	rr := httptest.NewRecorder()
+	ts.dlqExport(rr, httptest.NewRequest(http.MethodGet, dlqPathExport+"?function=fn-a", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	var got []string
+	for _, line := range lines {
+		var rec dlqShowResp
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		require.NotNil(t, rec.Envelope, "each line carries the decoded envelope")
+		assert.Equal(t, []byte("payload-fn-a"), rec.Envelope.Body)
+		got = append(got, rec.ID)
+	}
+	assert.Equal(t, []string{a, a2}, got)
+}
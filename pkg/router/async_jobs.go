@@ -0,0 +1,428 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	eclient "github.com/fission/fission/pkg/executor/client"
+	"github.com/fission/fission/pkg/router/asyncinvoke"
+	"github.com/fission/fission/pkg/statestore"
+	"github.com/fission/fission/pkg/utils/httpmux"
+)
+
+// Job invocations (executor type job). Every invocation of a job function runs
+// as its own Kubernetes Job, started through the executor, and answers 202
+// {invocationId} at once, sync or async mode alike. The router keeps one
+// record per invocation in the statestore KV and a durable watch message per
+// running invocation on jobWatchQueue; the jobWatcher polls the Job through
+// the executor until it finishes, stores the outcome on the record for
+// ResultTTL, and fires the function's OnSuccess/OnFailure destination. The
+// record is what `fission function job-status` reads, on the internal
+// listener like the DLQ admin API.
+const (
+	jobWatchQueue = "asyncinv-jobs"
+	jobKeyspace   = "jobs"
+
+	jobPathStatus = "/v1/async/jobs/status"
+
+	// jobPollInterval is how often a running Job is polled; jobIdleWait how
+	// long the watcher sleeps when no watch is due.
+	jobPollInterval = 10 * time.Second
+	jobIdleWait     = 2 * time.Second
+	jobLeaseFor     = time.Minute
+	jobLeaseBatch   = 16
+)
+
+// JobClient is the executor client facet that runs job invocations. The
+// executor client implements it; a client that does not (a test double, an
+// older executor) leaves executor type job unsupported on this router.
+type JobClient interface {
+	RunJob(ctx context.Context, fn *fv1.Function, invocationID string, body []byte, contentType string) error
+	JobStatus(ctx context.Context, fn *fv1.Function, invocationID string) (*eclient.JobStatus, error)
+}
+
+// jobState is a job invocation's state, as the status API returns it.
+type jobState struct {
+	InvocationID string           `json:"invocationId"`
+	Namespace    string           `json:"namespace"`
+	Function     string           `json:"function"`
+	Phase        eclient.JobPhase `json:"phase"`
+	StartTime    time.Time        `json:"startTime"`
+	EndTime      *time.Time       `json:"endTime,omitempty"`
+
+	StatusCode int    `json:"statusCode,omitempty"`
+	Body       []byte `json:"body,omitempty"`
+	Truncated  bool   `json:"truncated,omitempty"`
+	Output     string `json:"output,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// jobRecord is an invocation's statestore record: its state, plus what the
+// watcher needs to finish it.
+type jobRecord struct {
+	jobState
+	Depth                 int                      `json:"depth,omitempty"`
+	ResultTTL             string                   `json:"resultTTL,omitempty"`
+	OnSuccess             *asyncinvoke.Destination `json:"onSuccess,omitempty"`
+	OnFailure             *asyncinvoke.Destination `json:"onFailure,omitempty"`
+	RequestPayload        []byte                   `json:"requestPayload,omitempty"`
+	RequestPayloadOmitted bool                     `json:"requestPayloadOmitted,omitempty"`
+}
+
+// jobWatch is the body of a watch message.
+type jobWatch struct {
+	Namespace    string `json:"namespace"`
+	Function     string `json:"function"`
+	InvocationID string `json:"invocationId"`
+}
+
+func jobScope(namespace, function string) statestore.Scope {
+	return statestore.Scope{Namespace: namespace, Owner: "function/" + function, Keyspace: jobKeyspace}
+}
+
+func (a *asyncInvoker) jobsEnabled() bool {
+	return a.enabled() && a.jobs != nil && a.jobKV != nil
+}
+
+// startJob starts one invocation of the job function fn and writes the HTTP
+// response: 202 {invocationId} once its Job is created, 413 on an oversized
+// body, 503 when the store or the executor fails, and 501 when job
+// invocations are not enabled on this router. internal is the internal
+// direct-function path, where a dispatcher delivery's invocation id and
+// destination-chain depth are honored, so a redelivery starts no second Job.
+func (a *asyncInvoker) startJob(w http.ResponseWriter, r *http.Request, fn *fv1.Function, internal bool) {
+	if !a.jobsEnabled() {
+		http.Error(w, "job invocation is not enabled on this cluster", http.StatusNotImplemented)
+		return
+	}
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, asyncinvoke.DefaultMaxBodyBytes))
+		if err != nil {
+			if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+				http.Error(w, "request body exceeds the job invocation limit", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "reading request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	id, depth := "", 0
+	if internal {
+		id = r.Header.Get(asyncinvoke.HeaderInvocationID)
+		depth, _ = strconv.Atoi(r.Header.Get(asyncinvoke.HeaderInvocationDepth))
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	cfg := fn.Spec.InvokeStrategy.ExecutionStrategy.Job.Effective()
+	rec := jobRecord{
+		jobState: jobState{
+			InvocationID: id,
+			Namespace:    fn.Namespace,
+			Function:     fn.Name,
+			Phase:        eclient.JobRunning,
+			StartTime:    time.Now().UTC(),
+		},
+		Depth:     depth,
+		ResultTTL: cfg.ResultTTL,
+	}
+	rec.OnSuccess, rec.OnFailure = destinationsFromSpec(fn.Spec.Invocation, fn.Namespace)
+	if len(body) <= asyncinvoke.MaxPayloadBytes {
+		rec.RequestPayload = body
+	} else {
+		rec.RequestPayloadOmitted = true
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		a.logger.Error(err, "encoding job record", "namespace", fn.Namespace, "function", fn.Name)
+		http.Error(w, "job invocation store unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	// A Running record has no TTL: the durable watch message finishes it. A
+	// redelivered invocation finds its record already there.
+	scope := jobScope(fn.Namespace, fn.Name)
+	created := true
+	if err := a.jobKV.Set(r.Context(), scope, id, data, statestore.SetOptions{IfVersion: new(int64(0))}); err != nil {
+		if !errors.Is(err, statestore.ErrVersionConflict) {
+			a.logger.Error(err, "storing job record", "namespace", fn.Namespace, "function", fn.Name)
+			http.Error(w, "job invocation store unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		created = false
+	}
+
+	// RunJob is idempotent per invocation id, so a redelivery still (re)starts
+	// a Job whose first start failed.
+	if err := a.jobs.RunJob(r.Context(), fn, id, body, r.Header.Get("Content-Type")); err != nil {
+		a.logger.Error(err, "starting job", "namespace", fn.Namespace, "function", fn.Name, "invocationId", id)
+		if created {
+			// Forget the record so a retry of this invocation starts over.
+			_ = a.jobKV.Delete(context.WithoutCancel(r.Context()), scope, id, 0)
+		}
+		status, msg := ferror.GetHTTPError(err)
+		if status < http.StatusBadRequest || status == http.StatusInternalServerError {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, msg, status)
+		return
+	}
+	// A redelivery watches again, in case its first watch was never
+	// enqueued; the watcher finishes an invocation once however many watches
+	// it has.
+	if err := a.watchJob(r.Context(), jobWatch{Namespace: fn.Namespace, Function: fn.Name, InvocationID: id}, 0); err != nil {
+		a.logger.Error(err, "enqueueing job watch", "namespace", fn.Namespace, "function", fn.Name, "invocationId", id)
+		http.Error(w, "job invocation store unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(asyncinvoke.HeaderInvocationID, id)
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]string{"invocationId": id})
+}
+
+// watchJob enqueues a watch message for an invocation, due after delay.
+func (a *asyncInvoker) watchJob(ctx context.Context, watch jobWatch, delay time.Duration) error {
+	data, err := json.Marshal(watch)
+	if err != nil {
+		return err
+	}
+	_, err = a.queue.Enqueue(ctx, jobWatchQueue, statestore.Message{Body: data}, statestore.EnqueueOptions{Delay: delay})
+	return err
+}
+
+// jobWatcher finishes job invocations: it leases watch messages, polls each
+// one's Job, and re-enqueues the watch until the Job is done.
+type jobWatcher struct {
+	invoker *asyncInvoker
+	// client reads the functions from the Manager's cache.
+	client client.Client
+	// fire invokes a finished invocation's destination; the dispatcher's
+	// FireDestination.
+	fire   func(ctx context.Context, dest *asyncinvoke.Destination, depth int, result asyncinvoke.ResultEnvelope)
+	logger logr.Logger
+	now    func() time.Time
+}
+
+// Run polls until ctx is cancelled. Several router replicas run it safely: a
+// watch is leased by one at a time, and an invocation is finished once.
+func (jw *jobWatcher) Run(ctx context.Context) error {
+	jw.logger.Info("job watcher started", "queue", jobWatchQueue)
+	for {
+		msgs, err := jw.invoker.queue.Lease(ctx, jobWatchQueue, jobLeaseBatch, jobLeaseFor)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			jw.logger.Error(err, "lease failed", "queue", jobWatchQueue)
+		}
+		for _, msg := range msgs {
+			jw.process(ctx, msg)
+		}
+		if len(msgs) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(jobIdleWait):
+			}
+		}
+	}
+}
+
+// process polls one watched invocation. Its watch is acked once the
+// invocation is finished or re-enqueued, never before, so a crash polls it
+// again rather than losing it.
+func (jw *jobWatcher) process(ctx context.Context, msg statestore.LeasedMessage) {
+	var watch jobWatch
+	if err := json.Unmarshal(msg.Body, &watch); err != nil {
+		jw.logger.Error(err, "job watch will not decode; dropping", "id", msg.ID)
+		jw.ack(ctx, msg)
+		return
+	}
+	logger := jw.logger.WithValues("namespace", watch.Namespace, "function", watch.Function, "invocationId", watch.InvocationID)
+
+	var fn fv1.Function
+	if err := jw.client.Get(ctx, client.ObjectKey{Namespace: watch.Namespace, Name: watch.Function}, &fn); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "getting job function")
+			jw.again(ctx, msg, watch)
+			return
+		}
+		jw.finish(ctx, msg, watch, &eclient.JobStatus{Phase: eclient.JobFailed, Message: "function was deleted"})
+		return
+	}
+	status, err := jw.invoker.jobs.JobStatus(ctx, &fn, watch.InvocationID)
+	switch {
+	case ferror.IsNotFound(err):
+		jw.finish(ctx, msg, watch, &eclient.JobStatus{Phase: eclient.JobFailed, Message: "job was deleted before it finished"})
+	case err != nil:
+		logger.Error(err, "getting job status")
+		jw.again(ctx, msg, watch)
+	case !status.Done():
+		jw.again(ctx, msg, watch)
+	default:
+		jw.finish(ctx, msg, watch, status)
+	}
+}
+
+// again re-enqueues a watch for the next poll, then acks its message.
+func (jw *jobWatcher) again(ctx context.Context, msg statestore.LeasedMessage, watch jobWatch) {
+	if err := jw.invoker.watchJob(ctx, watch, jobPollInterval); err != nil {
+		// Leave the lease to expire; the watch is polled again then.
+		jw.logger.Error(err, "re-enqueueing job watch", "invocationId", watch.InvocationID)
+		return
+	}
+	jw.ack(ctx, msg)
+}
+
+// finish stores an invocation's outcome, kept for its ResultTTL, and fires
+// its destination. The record is updated by compare-and-swap, so an
+// invocation two replicas finish at once fires its destination once.
+func (jw *jobWatcher) finish(ctx context.Context, msg statestore.LeasedMessage, watch jobWatch, status *eclient.JobStatus) {
+	logger := jw.logger.WithValues("namespace", watch.Namespace, "function", watch.Function, "invocationId", watch.InvocationID)
+	kv, scope := jw.invoker.jobKV, jobScope(watch.Namespace, watch.Function)
+	val, err := kv.Get(ctx, scope, watch.InvocationID)
+	if errors.Is(err, statestore.ErrNotFound) {
+		jw.ack(ctx, msg)
+		return
+	}
+	if err != nil {
+		logger.Error(err, "reading job record")
+		jw.again(ctx, msg, watch)
+		return
+	}
+	var rec jobRecord
+	if err := json.Unmarshal(val.Data, &rec); err != nil {
+		logger.Error(err, "job record will not decode; dropping its watch")
+		jw.ack(ctx, msg)
+		return
+	}
+	if rec.Phase != eclient.JobRunning {
+		jw.ack(ctx, msg)
+		return
+	}
+
+	end := jw.now().UTC()
+	rec.Phase = status.Phase
+	rec.EndTime = &end
+	rec.StatusCode = status.StatusCode
+	rec.Body = status.Body
+	rec.Truncated = status.Truncated
+	rec.Output = status.Output
+	rec.Message = status.Message
+	data, err := json.Marshal(rec)
+	if err != nil {
+		logger.Error(err, "encoding job record")
+		jw.ack(ctx, msg)
+		return
+	}
+	ttl, err := time.ParseDuration(rec.ResultTTL)
+	if err != nil || ttl <= 0 {
+		ttl, _ = time.ParseDuration(fv1.DefaultJobResultTTL)
+	}
+	if err := kv.Set(ctx, scope, watch.InvocationID, data, statestore.SetOptions{IfVersion: new(val.Version), TTL: ttl}); err != nil {
+		if errors.Is(err, statestore.ErrVersionConflict) {
+			jw.ack(ctx, msg)
+			return
+		}
+		logger.Error(err, "storing job result")
+		jw.again(ctx, msg, watch)
+		return
+	}
+	logger.Info("job finished", "phase", rec.Phase, "statusCode", rec.StatusCode)
+
+	dest, condition := rec.OnSuccess, asyncinvoke.ConditionSuccess
+	if rec.Phase != eclient.JobSucceeded {
+		dest, condition = rec.OnFailure, asyncinvoke.ConditionRetriesExhausted
+	}
+	if dest != nil && jw.fire != nil {
+		body := rec.Body
+		if len(body) > asyncinvoke.MaxPayloadBytes {
+			body = body[:asyncinvoke.MaxPayloadBytes]
+		}
+		jw.fire(ctx, dest, rec.Depth, asyncinvoke.ResultEnvelope{
+			Version: asyncinvoke.EnvelopeVersion,
+			RequestContext: asyncinvoke.RequestContext{
+				InvocationID: rec.InvocationID,
+				FunctionRef:  rec.Namespace + "/" + rec.Function,
+				Condition:    condition,
+				Attempts:     1,
+				Depth:        rec.Depth,
+			},
+			RequestPayload:        rec.RequestPayload,
+			RequestPayloadOmitted: rec.RequestPayloadOmitted,
+			ResponseContext: asyncinvoke.ResponseContext{
+				StatusCode: rec.StatusCode,
+				Truncated:  rec.Truncated || len(body) < len(rec.Body),
+			},
+			ResponsePayload: body,
+		})
+	}
+	jw.ack(ctx, msg)
+}
+
+func (jw *jobWatcher) ack(ctx context.Context, msg statestore.LeasedMessage) {
+	if err := jw.invoker.queue.Ack(context.WithoutCancel(ctx), msg.Receipt); err != nil {
+		jw.logger.Error(err, "acking job watch", "id", msg.ID)
+	}
+}
+
+// registerAsyncJobRoutes adds the job status endpoint to the INTERNAL mux;
+// see registerAsyncDLQRoutes.
+func (ts *HTTPTriggerSet) registerAsyncJobRoutes(internal *httpmux.Mux) {
+	internal.HandleFunc(jobPathStatus, ts.jobStatus).Methods(http.MethodGet)
+}
+
+// jobStatus returns the record of one job invocation, named by the
+// namespace, function and id query parameters.
+func (ts *HTTPTriggerSet) jobStatus(w http.ResponseWriter, r *http.Request) {
+	if !ts.asyncInvoker.jobsEnabled() {
+		http.Error(w, "job invocation is not enabled on this cluster", http.StatusNotImplemented)
+		return
+	}
+	q := r.URL.Query()
+	ns, fn, id := q.Get("namespace"), q.Get("function"), q.Get("id")
+	if ns == "" || fn == "" || id == "" {
+		http.Error(w, "namespace, function and id are required", http.StatusBadRequest)
+		return
+	}
+	val, err := ts.asyncInvoker.jobKV.Get(r.Context(), jobScope(ns, fn), id)
+	if errors.Is(err, statestore.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("no job invocation %s of function %s/%s", id, ns, fn), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		ts.logger.Error(err, "reading job record", "namespace", ns, "function", fn, "invocationId", id)
+		http.Error(w, "reading job invocation", http.StatusInternalServerError)
+		return
+	}
+	var rec jobRecord
+	if err := json.Unmarshal(val.Data, &rec); err != nil {
+		http.Error(w, "job record will not decode", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(rec.jobState)
+}
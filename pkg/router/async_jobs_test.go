@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	eclient "github.com/fission/fission/pkg/executor/client"
+	"github.com/fission/fission/pkg/generated/clientset/versioned/scheme"
+	"github.com/fission/fission/pkg/router/asyncinvoke"
+	"github.com/fission/fission/pkg/statestore"
+	"github.com/fission/fission/pkg/utils/httpmux"
+)
+
+// fakeJobClient records started invocations and reports status for them.
+type fakeJobClient struct {
+	mu      sync.Mutex
+	started map[string]string
+	status  *eclient.JobStatus
+}
+
+func (f *fakeJobClient) RunJob(_ context.Context, _ *fv1.Function, invocationID string, body []byte, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.started[invocationID] = string(body)
+	return nil
+}
+
+func (f *fakeJobClient) JobStatus(_ context.Context, _ *fv1.Function, invocationID string) (*eclient.JobStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.started[invocationID]; !ok {
+		return nil, ferror.MakeError(ferror.ErrorNotFound, "no such job")
+	}
+	return f.status, nil
+}
+
+func jobTestFunction() *fv1.Function {
+	return &fv1.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "ns"},
+		Spec: fv1.FunctionSpec{
+			InvokeStrategy: fv1.InvokeStrategy{
+				ExecutionStrategy: fv1.ExecutionStrategy{ExecutorType: fv1.ExecutorTypeJob},
+			},
+			Invocation: &fv1.InvocationConfig{
+				OnSuccess: &fv1.DestinationRef{Function: &fv1.FunctionReference{Name: "done"}},
+				OnFailure: &fv1.DestinationRef{Function: &fv1.FunctionReference{Name: "failed"}},
+			},
+		},
+	}
+}
+
+func newJobInvoker(t *testing.T, jobs JobClient) *asyncInvoker {
+	t.Helper()
+	caps, err := statestore.Open(t.Context(), statestore.Config{Driver: "memory"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = caps.Close() })
+	q, err := caps.Queue()
+	require.NoError(t, err)
+	kv, err := caps.KV()
+	require.NoError(t, err)
+	return &asyncInvoker{queue: q, logger: logr.Discard(), jobs: jobs, jobKV: kv}
+}
+
+func jobStatusOf(t *testing.T, inv *asyncInvoker, id string) (int, jobState) {
+	t.Helper()
+	ts := &HTTPTriggerSet{logger: logr.Discard(), asyncInvoker: inv}
+	mux := httpmux.New()
+	ts.registerAsyncJobRoutes(mux)
+	w := httptest.NewRecorder()
+	mux.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, jobPathStatus+"?namespace=ns&function=report&id="+id, nil))
+	var st jobState
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &st))
+	}
+	return w.Code, st
+}
+
+// TestHandlerJobBranch: a job function answers 202 with an invocation id in
+// sync mode too, and its invocation is started, recorded and watched.
+func TestHandlerJobBranch(t *testing.T) {
+	jobs := &fakeJobClient{started: map[string]string{}}
+	inv := newJobInvoker(t, jobs)
+	fh := functionHandler{
+		logger:       logr.Discard(),
+		function:     jobTestFunction(),
+		httpTrigger:  &fv1.HTTPTrigger{},
+		asyncInvoker: inv,
+	}
+	w := httptest.NewRecorder()
+	fh.handler(w, httptest.NewRequest(http.MethodPost, "/report", strings.NewReader("input")))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var resp map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	id := resp["invocationId"]
+	require.NotEmpty(t, id)
+	assert.Equal(t, id, w.Header().Get(asyncinvoke.HeaderInvocationID))
+	assert.Equal(t, map[string]string{id: "input"}, jobs.started)
+
+	code, st := jobStatusOf(t, inv, id)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, eclient.JobRunning, st.Phase)
+
+	l, err := inv.queue.Lease(t.Context(), jobWatchQueue, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, l, 1)
+
+	// Nothing went to the async queue.
+	l, err = inv.queue.Lease(t.Context(), asyncinvoke.DefaultQueue, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, l)
+}
+
+// TestStartJobRedelivery: on the internal path a dispatcher delivery's
+// invocation id is kept, so a redelivery restarts the same invocation.
+func TestStartJobRedelivery(t *testing.T) {
+	jobs := &fakeJobClient{started: map[string]string{}}
+	inv := newJobInvoker(t, jobs)
+	for range 2 {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("input"))
+		r.Header.Set(asyncinvoke.HeaderInvocationID, "inv-1")
+		w := httptest.NewRecorder()
+		inv.startJob(w, r, jobTestFunction(), true)
+		require.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "inv-1", w.Header().Get(asyncinvoke.HeaderInvocationID))
+	}
+	assert.Equal(t, map[string]string{"inv-1": "input"}, jobs.started)
+
+	// The public path never takes the caller's id.
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set(asyncinvoke.HeaderInvocationID, "inv-1")
+	w := httptest.NewRecorder()
+	inv.startJob(w, r, jobTestFunction(), false)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.NotEqual(t, "inv-1", w.Header().Get(asyncinvoke.HeaderInvocationID))
+}
+
+func TestStartJobDisabled501(t *testing.T) {
+	var inv *asyncInvoker
+	w := httptest.NewRecorder()
+	inv.startJob(w, httptest.NewRequest(http.MethodPost, "/", nil), jobTestFunction(), false)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	inv = newJobInvoker(t, nil)
+	w = httptest.NewRecorder()
+	inv.startJob(w, httptest.NewRequest(http.MethodPost, "/", nil), jobTestFunction(), false)
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+
+	code, _ := jobStatusOf(t, nil, "inv-1")
+	assert.Equal(t, http.StatusNotImplemented, code)
+}
+
+// TestJobWatcher: a running invocation's watch is re-enqueued; a finished
+// one's outcome is stored and its destination fired, once.
+func TestJobWatcher(t *testing.T) {
+	fn := jobTestFunction()
+	jobs := &fakeJobClient{started: map[string]string{}, status: &eclient.JobStatus{Phase: eclient.JobRunning}}
+	inv := newJobInvoker(t, jobs)
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("input"))
+	r.Header.Set(asyncinvoke.HeaderInvocationID, "inv-1")
+	r.Header.Set(asyncinvoke.HeaderInvocationDepth, "2")
+	w := httptest.NewRecorder()
+	inv.startJob(w, r, fn, true)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	type fired struct {
+		dest   *asyncinvoke.Destination
+		depth  int
+		result asyncinvoke.ResultEnvelope
+	}
+	var got []fired
+	jw := &jobWatcher{
+		invoker: inv,
+		client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(fn).Build(),
+		fire: func(_ context.Context, dest *asyncinvoke.Destination, depth int, result asyncinvoke.ResultEnvelope) {
+			got = append(got, fired{dest, depth, result})
+		},
+		logger: logr.Discard(),
+		now:    time.Now,
+	}
+	lease := func() []statestore.LeasedMessage {
+		l, err := inv.queue.Lease(t.Context(), jobWatchQueue, 10, time.Minute)
+		require.NoError(t, err)
+		return l
+	}
+
+	msgs := lease()
+	require.Len(t, msgs, 1)
+	jw.process(t.Context(), msgs[0])
+	assert.Empty(t, got)
+	assert.Empty(t, lease(), "the watch is re-enqueued for later")
+
+	jobs.status = &eclient.JobStatus{Phase: eclient.JobFailed, StatusCode: 500, Body: []byte("boom"), Message: "function returned 500"}
+	jw.process(t.Context(), msgs[0])
+	jw.process(t.Context(), msgs[0])
+	require.Len(t, got, 1, "a finished invocation fires once")
+	assert.Equal(t, "failed", got[0].dest.FunctionName)
+	assert.Equal(t, 2, got[0].depth)
+	assert.Equal(t, asyncinvoke.ConditionRetriesExhausted, got[0].result.RequestContext.Condition)
+	assert.Equal(t, "ns/report", got[0].result.RequestContext.FunctionRef)
+	assert.Equal(t, 500, got[0].result.ResponseContext.StatusCode)
+	assert.Equal(t, "input", string(got[0].result.RequestPayload))
+	assert.Equal(t, "boom", string(got[0].result.ResponsePayload))
+
+	code, st := jobStatusOf(t, inv, "inv-1")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, eclient.JobFailed, st.Phase)
+	assert.Equal(t, "function returned 500", st.Message)
+	assert.NotNil(t, st.EndTime)
+}
+
+// TestJobWatcherJobGone: a Job deleted before it finished fails its
+// invocation.
+func TestJobWatcherJobGone(t *testing.T) {
+	fn := jobTestFunction()
+	jobs := &fakeJobClient{started: map[string]string{}}
+	inv := newJobInvoker(t, jobs)
+	w := httptest.NewRecorder()
+	inv.startJob(w, httptest.NewRequest(http.MethodPost, "/", nil), fn, false)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	id := w.Header().Get(asyncinvoke.HeaderInvocationID)
+	clear(jobs.started)
+
+	jw := &jobWatcher{
+		invoker: inv,
+		client:  fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(fn).Build(),
+		logger:  logr.Discard(),
+		now:     time.Now,
+	}
+	msgs, err := inv.queue.Lease(t.Context(), jobWatchQueue, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	jw.process(t.Context(), msgs[0])
+
+	code, st := jobStatusOf(t, inv, id)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, eclient.JobFailed, st.Phase)
+	assert.NotEmpty(t, st.Message)
+}
+
+// TestFuncConfigFromSpecJob: the dispatcher fires no destinations for a job
+// function; the job watcher does.
+func TestFuncConfigFromSpecJob(t *testing.T) {
+	cfg := funcConfigFromSpec(jobTestFunction())
+	assert.Nil(t, cfg.OnSuccess)
+	assert.Nil(t, cfg.OnFailure)
+}
@@ -62,6 +62,54 @@ func TestAsyncInvokerHandleAccepted(t *testing.T) {
 	assert.Equal(t, []byte("payload"), env.Body)
 }
 
+// TestAsyncInvokerHandlePriority: the X-Fission-Invoke-Priority header picks the
+// lane, the function's InvocationConfig.Priority is the default, and an unknown
+// header value is a 400 rather than a silent normal-lane enqueue.
+func TestAsyncInvokerHandlePriority(t *testing.T) {
+	t.Parallel()
+	lowDefault := &fv1.InvocationConfig{Priority: fv1.InvocationPriorityLow}
+	for name, tc := range map[string]struct {
+		header    string
+		ic        *fv1.InvocationConfig
+		wantQueue string
+	}{
+		"no header, no default": {wantQueue: asyncinvoke.DefaultQueue},
+		"function default":      {ic: lowDefault, wantQueue: "asyncinv-low"},
+		"header overrides":      {header: "HIGH", ic: lowDefault, wantQueue: "asyncinv-high"},
+		"header normal":         {header: "normal", ic: lowDefault, wantQueue: asyncinvoke.DefaultQueue},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			q := routerMemQueue(t)
+			inv := &asyncInvoker{queue: q, logger: logr.Discard()}
+			fn := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "ns"}, Spec: fv1.FunctionSpec{Invocation: tc.ic}}
+			r := httptest.NewRequest("POST", "/x", strings.NewReader("p"))
+			if tc.header != "" {
+				r.Header.Set(asyncinvoke.HeaderInvokePriority, tc.header)
+			}
+			w := httptest.NewRecorder()
+			inv.handle(w, r, fn)
+			require.Equal(t, 202, w.Code)
+
+			l, err := q.Lease(t.Context(), tc.wantQueue, 1, time.Minute)
+			require.NoError(t, err)
+			require.Len(t, l, 1, "enqueued on %s", tc.wantQueue)
+		})
+	}
+
+	t.Run("unknown header is 400", func(t *testing.T) {
+		t.Parallel()
+		q := routerMemQueue(t)
+		inv := &asyncInvoker{queue: q, logger: logr.Discard()}
+		fn := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "ns"}}
+		r := httptest.NewRequest("POST", "/x", strings.NewReader("p"))
+		r.Header.Set(asyncinvoke.HeaderInvokePriority, "urgent")
+		w := httptest.NewRecorder()
+		inv.handle(w, r, fn)
+		assert.Equal(t, 400, w.Code)
+	})
+}
+
 // TestAsyncInvokerHandleStampsFunctionVersion pins the RFC-0025 Task 5
 // enqueue-side stamp: when the resolved backend fn carries
 // fv1.FUNCTION_VERSION (versioning.VersionedFunction's label, set for any
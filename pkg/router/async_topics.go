@@ -5,34 +5,57 @@
 package router
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
 	"github.com/fission/fission/pkg/mqtrigger/mqpub"
 	"github.com/fission/fission/pkg/router/asyncinvoke"
+	"github.com/fission/fission/pkg/statestore"
 	"github.com/fission/fission/pkg/utils/httpmux"
 )
 
 // RFC-0027 topic admin API — the thin surface behind `fission topic
-// publish|peek`. Same posture as the async DLQ API above it: INTERNAL listener
+// publish|peek|retention|groups|seek`. Same posture as the async DLQ API above it: INTERNAL listener
 // only (HMAC-verified, NetworkPolicy-gated), 501 when the statestore is not
 // wired, and deliberately dev-tool-sized (peek is a bounded tail read, publish
 // goes through the exact MultiPublisher async destinations use, so a
 // dev-published event is indistinguishable from a destination-published one).
+//
+// Retention, groups and seek address statestore topics only — a broker topic's
+// retention and consumer offsets live in the broker and are managed with its
+// own tooling. The router has no RBAC on MessageQueueTriggers, so groups and
+// seek take trigger NAMES from the caller (the CLI resolves a topic's triggers
+// from the cluster) and never enumerate triggers themselves.
 const (
-	topicPathPublish = "/v1/eventing/topic/publish"
-	topicPathPeek    = "/v1/eventing/topic/peek"
+	topicPathPublish   = "/v1/eventing/topic/publish"
+	topicPathPeek      = "/v1/eventing/topic/peek"
+	topicPathRetention = "/v1/eventing/topic/retention"
+	topicPathGroups    = "/v1/eventing/topic/groups"
+	topicPathSeek      = "/v1/eventing/topic/seek"
 
 	// topicPeekDefault / topicPeekMax bound a peek; topicPublishMaxBody matches
 	// the async body cap (a topic event is the same class of payload).
 	topicPeekDefault    = 10
 	topicPeekMax        = 100
 	topicPublishMaxBody = 256 << 10
+
+	// topicGroupsMax bounds the triggers one groups request reports;
+	// topicSeekScanPage is the page size of a seek-by-time scan.
+	topicGroupsMax    = 100
+	topicSeekScanPage = 500
+	// topicSeekCASRetries bounds seek's read-modify-write against a live
+	// subscription committing concurrently.
+	topicSeekCASRetries = 5
 )
 
 // topicEvent is one peeked event. Payload is raw bytes (base64 on the JSON
@@ -40,6 +63,7 @@ const (
 type topicEvent struct {
 	Seq     int64     `json:"seq"`
 	Type    string    `json:"type,omitempty"`
+	Key     string    `json:"key,omitempty"`
 	Payload []byte    `json:"payload"`
 	At      time.Time `json:"at"`
 }
@@ -53,9 +77,48 @@ type topicPublishResp struct {
 	Published bool `json:"published"`
 }
 
+// topicGroup is one subscription's position on a topic. A trigger whose
+// subscription has never started has no cursor record (Initialized false); it
+// will begin at the head when it does.
+type topicGroup struct {
+	Trigger     string `json:"trigger"`
+	Initialized bool   `json:"initialized"`
+	Cursor      int64  `json:"cursor"`
+	Lag         int64  `json:"lag"`
+}
+
+type topicGroupsResp struct {
+	Head      int64                `json:"head"`
+	Floor     int64                `json:"floor"`
+	Events    int64                `json:"events"`
+	Bytes     int64                `json:"bytes"`
+	Retention mqpub.TopicRetention `json:"retention"`
+	Groups    []topicGroup         `json:"groups"`
+}
+
+type topicSeekReq struct {
+	Namespace string `json:"namespace"`
+	Topic     string `json:"topic"`
+	Trigger   string `json:"trigger"`
+	// To is "earliest", "latest", a sequence number (delivery resumes AT that
+	// event), or an RFC3339 time (delivery resumes at the first event at or
+	// after it).
+	To string `json:"to"`
+}
+
+type topicSeekResp struct {
+	Trigger  string `json:"trigger"`
+	Previous int64  `json:"previous"`
+	Cursor   int64  `json:"cursor"`
+}
+
 func (ts *HTTPTriggerSet) registerTopicRoutes(internal *httpmux.Mux) {
 	internal.HandleFunc(topicPathPublish, ts.topicPublish).Methods(http.MethodPost)
 	internal.HandleFunc(topicPathPeek, ts.topicPeek).Methods(http.MethodGet)
+	internal.HandleFunc(topicPathRetention, ts.topicRetentionGet).Methods(http.MethodGet)
+	internal.HandleFunc(topicPathRetention, ts.topicRetentionSet).Methods(http.MethodPut)
+	internal.HandleFunc(topicPathGroups, ts.topicGroups).Methods(http.MethodGet)
+	internal.HandleFunc(topicPathSeek, ts.topicSeek).Methods(http.MethodPost)
 }
 
 // topicStore returns the topic surface handles, or writes 501 when the router
@@ -63,7 +126,8 @@ func (ts *HTTPTriggerSet) registerTopicRoutes(internal *httpmux.Mux) {
 // — they are wired together, but a 501 beats a panic if that ever drifts.
 func (ts *HTTPTriggerSet) topicStore(w http.ResponseWriter) bool {
 	if ts.asyncInvoker == nil || !ts.asyncInvoker.enabled() ||
-		ts.asyncInvoker.eventLog == nil || ts.asyncInvoker.publishTopic == nil {
+		ts.asyncInvoker.eventLog == nil || ts.asyncInvoker.publishTopic == nil ||
+		ts.asyncInvoker.topicPublisher == nil || ts.asyncInvoker.topicKV == nil {
 		http.Error(w, "eventing is not enabled on this cluster (requires the statestore)", http.StatusNotImplemented)
 		return false
 	}
@@ -89,7 +153,8 @@ func topicParams(w http.ResponseWriter, r *http.Request) (namespace, topic strin
 
 // topicPublish publishes the request body to the topic. ?mqtype selects the
 // provider (default statestore); the Content-Type header travels as the event
-// type, exactly as a consuming trigger will replay it.
+// type, exactly as a consuming trigger will replay it. ?key sets the event's
+// compaction key (statestore topics only — a key on a broker type is a 400).
 func (ts *HTTPTriggerSet) topicPublish(w http.ResponseWriter, r *http.Request) {
 	if !ts.topicStore(w) {
 		return
@@ -120,14 +185,28 @@ func (ts *HTTPTriggerSet) topicPublish(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "reading request body", http.StatusBadRequest)
 		return
 	}
-	if err := ts.asyncInvoker.publishTopic(r.Context(), namespace, mqType, topic, r.Header.Get("Content-Type"), payload); err != nil {
+	key := r.URL.Query().Get("key")
+	if len(key) > mqpub.MaxKeyLen {
+		http.Error(w, "key exceeds "+strconv.Itoa(mqpub.MaxKeyLen)+" bytes", http.StatusBadRequest)
+		return
+	}
+	if key == "" {
+		err = ts.asyncInvoker.publishTopic(r.Context(), namespace, mqType, topic, r.Header.Get("Content-Type"), payload)
+	} else {
+		err = mqpub.PublishKeyed(r.Context(), ts.asyncInvoker.topicPublisher, namespace, mqType, topic, key, r.Header.Get("Content-Type"), payload)
+	}
+	if err != nil {
 		// Caller mistakes (a typo'd ?mqtype, an invalid input the sink rejects)
 		// are 400s, not operational failures — a user typo must not land in the
 		// router's Error log or read as a gateway fault.
-		if errors.Is(err, asyncinvoke.ErrTopicUnsupported) {
+		if errors.Is(err, asyncinvoke.ErrTopicUnsupported) || errors.Is(err, mqpub.ErrUnsupportedMQType) {
 			http.Error(w, "unsupported mqtype: no publish path for "+mqType+" on this install", http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, mqpub.ErrKeyUnsupported) {
+			http.Error(w, "message keys are supported on statestore topics only", http.StatusBadRequest)
+			return
+		}
 		// Detail stays in the router log (the DLQ API's posture) — the caller
 		// gets the one actionable distinction: the backlog cap versus a store
 		// fault.
@@ -180,7 +259,268 @@ func (ts *HTTPTriggerSet) topicPeek(w http.ResponseWriter, r *http.Request) {
 	}
 	resp := topicPeekResp{Head: head, Events: make([]topicEvent, 0, len(events))}
 	for _, ev := range events {
-		resp.Events = append(resp.Events, topicEvent{Seq: ev.Seq, Type: ev.Type, Payload: ev.Payload, At: ev.At})
+		resp.Events = append(resp.Events, topicEvent{Seq: ev.Seq, Type: ev.Type, Key: ev.Key, Payload: ev.Payload, At: ev.At})
 	}
 	dlqWriteJSON(w, ts, resp)
 }
+
+// topicFloor returns the stream's trim floor: one below the first retained
+// event, or the head when nothing is retained (the mqpub backlog-cap formula).
+func (ts *HTTPTriggerSet) topicFloor(ctx context.Context, stream string, head int64) (int64, error) {
+	first, err := ts.asyncInvoker.eventLog.Read(ctx, stream, 0, 1)
+	if err != nil {
+		return 0, err
+	}
+	if len(first) == 0 {
+		return head, nil
+	}
+	return first[0].Seq - 1, nil
+}
+
+// topicRetentionGet returns the topic's retention policy; the zero policy (an
+// empty object) is the min-cursor default.
+func (ts *HTTPTriggerSet) topicRetentionGet(w http.ResponseWriter, r *http.Request) {
+	if !ts.topicStore(w) {
+		return
+	}
+	namespace, topic, ok := topicParams(w, r)
+	if !ok {
+		return
+	}
+	policy, err := mqpub.LoadTopicRetention(r.Context(), ts.asyncInvoker.topicKV, namespace, topic)
+	if err != nil {
+		ts.logger.Error(err, "topic admin retention: loading policy", "namespace", namespace, "topic", topic)
+		http.Error(w, "reading topic retention", http.StatusInternalServerError)
+		return
+	}
+	dlqWriteJSON(w, ts, policy)
+}
+
+// topicRetentionSet replaces the topic's retention policy with the request
+// body; an empty object clears it. The reaper picks the change up on its next
+// tick — a tightened limit trims then, not at the write.
+func (ts *HTTPTriggerSet) topicRetentionSet(w http.ResponseWriter, r *http.Request) {
+	if !ts.topicStore(w) {
+		return
+	}
+	namespace, topic, ok := topicParams(w, r)
+	if !ok {
+		return
+	}
+	var policy mqpub.TopicRetention
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&policy); err != nil {
+		http.Error(w, "invalid retention policy: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := policy.Validate(); err != nil {
+		http.Error(w, "invalid retention policy: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := mqpub.SaveTopicRetention(r.Context(), ts.asyncInvoker.topicKV, namespace, topic, policy); err != nil {
+		ts.logger.Error(err, "topic admin retention: saving policy", "namespace", namespace, "topic", topic)
+		http.Error(w, "saving topic retention", http.StatusInternalServerError)
+		return
+	}
+	ts.logger.Info("topic retention policy updated", "namespace", namespace, "topic", topic, "policy", policy)
+	dlqWriteJSON(w, ts, policy)
+}
+
+// topicGroups reports the topic's retained range and, for each ?trigger, that
+// subscription's cursor and lag (head minus cursor). Triggers are named by the
+// caller; a name that never subscribed simply reports uninitialized.
+func (ts *HTTPTriggerSet) topicGroups(w http.ResponseWriter, r *http.Request) {
+	if !ts.topicStore(w) {
+		return
+	}
+	namespace, topic, ok := topicParams(w, r)
+	if !ok {
+		return
+	}
+	triggers := r.URL.Query()["trigger"]
+	if len(triggers) > topicGroupsMax {
+		http.Error(w, "at most "+strconv.Itoa(topicGroupsMax)+" triggers per request", http.StatusBadRequest)
+		return
+	}
+	for _, name := range triggers {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			http.Error(w, "invalid trigger name "+strconv.Quote(name)+": "+strings.Join(errs, "; "), http.StatusBadRequest)
+			return
+		}
+	}
+	ctx := r.Context()
+	stream := mqpub.StreamForTopic(namespace, topic)
+	head, err := ts.asyncInvoker.eventLog.Head(ctx, stream)
+	if err != nil {
+		ts.logger.Error(err, "topic admin groups: reading head", "stream", stream)
+		http.Error(w, "reading topic", http.StatusInternalServerError)
+		return
+	}
+	floor, err := ts.topicFloor(ctx, stream, head)
+	if err != nil {
+		ts.logger.Error(err, "topic admin groups: reading floor", "stream", stream)
+		http.Error(w, "reading topic", http.StatusInternalServerError)
+		return
+	}
+	stats, err := ts.asyncInvoker.eventLog.StreamStats(ctx, stream)
+	if err != nil {
+		ts.logger.Error(err, "topic admin groups: reading stats", "stream", stream)
+		http.Error(w, "reading topic", http.StatusInternalServerError)
+		return
+	}
+	policy, err := mqpub.LoadTopicRetention(ctx, ts.asyncInvoker.topicKV, namespace, topic)
+	if err != nil {
+		ts.logger.Error(err, "topic admin groups: loading policy", "namespace", namespace, "topic", topic)
+		http.Error(w, "reading topic retention", http.StatusInternalServerError)
+		return
+	}
+	resp := topicGroupsResp{Head: head, Floor: floor, Events: stats.Events, Bytes: stats.Bytes, Retention: policy, Groups: make([]topicGroup, 0, len(triggers))}
+	for _, name := range triggers {
+		cursor, _, found, err := ts.topicCursor(ctx, namespace, name)
+		if err != nil {
+			ts.logger.Error(err, "topic admin groups: reading cursor", "namespace", namespace, "trigger", name)
+			http.Error(w, "reading subscription cursor", http.StatusInternalServerError)
+			return
+		}
+		g := topicGroup{Trigger: name, Initialized: found}
+		if found {
+			g.Cursor, g.Lag = cursor, max(head-cursor, 0)
+		}
+		resp.Groups = append(resp.Groups, g)
+	}
+	dlqWriteJSON(w, ts, resp)
+}
+
+// topicCursor reads a trigger's cursor record: found is false when the record
+// is absent (version 0, the create-only CAS precondition).
+func (ts *HTTPTriggerSet) topicCursor(ctx context.Context, namespace, trigger string) (cursor, version int64, found bool, err error) {
+	val, err := ts.asyncInvoker.topicKV.Get(ctx, mqpub.CursorScope(namespace, trigger), mqpub.CursorKey)
+	if errors.Is(err, statestore.ErrNotFound) {
+		return 0, 0, false, nil
+	}
+	if err != nil {
+		return 0, 0, false, err
+	}
+	cursor, err = strconv.ParseInt(string(val.Data), 10, 64)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("corrupt cursor record %q: %w", string(val.Data), err)
+	}
+	return cursor, val.Version, true, nil
+}
+
+// errSeekTarget marks a seek target the caller got wrong (400).
+var errSeekTarget = errors.New("invalid seek target")
+
+// seekCursor resolves a seek target to the cursor to store — the last
+// delivered Seq, so delivery resumes at cursor+1. A target below the floor
+// clamps to it (those events are gone) and one above the head clamps to the
+// head.
+func (ts *HTTPTriggerSet) seekCursor(ctx context.Context, stream, to string) (int64, error) {
+	head, err := ts.asyncInvoker.eventLog.Head(ctx, stream)
+	if err != nil {
+		return 0, err
+	}
+	floor, err := ts.topicFloor(ctx, stream, head)
+	if err != nil {
+		return 0, err
+	}
+	switch to {
+	case "earliest":
+		return floor, nil
+	case "latest":
+		return head, nil
+	}
+	if seq, perr := strconv.ParseInt(to, 10, 64); perr == nil {
+		if seq < 1 {
+			return 0, fmt.Errorf("%w: sequence numbers start at 1", errSeekTarget)
+		}
+		return min(max(seq-1, floor), head), nil
+	}
+	at, perr := time.Parse(time.RFC3339, to)
+	if perr != nil {
+		return 0, fmt.Errorf("%w: %q is not earliest, latest, a sequence number, or an RFC3339 time", errSeekTarget, to)
+	}
+	// A forward scan of the retained range: retention bounds it, and seek is
+	// an operator action, not a hot path.
+	for after := floor; after < head; {
+		events, err := ts.asyncInvoker.eventLog.Read(ctx, stream, after, topicSeekScanPage)
+		if err != nil {
+			return 0, err
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, ev := range events {
+			if !ev.At.Before(at) {
+				return ev.Seq - 1, nil
+			}
+		}
+		after = events[len(events)-1].Seq
+	}
+	return head, nil
+}
+
+// topicSeek moves a subscription's cursor — replaying retained history (a
+// backwards seek) or skipping a backlog (forwards). The write is a CAS against
+// the live subscription's own commits, retried on conflict; the subscription
+// adopts the moved cursor on its next commit or idle recheck, so events it
+// already has in flight may still complete first (at-least-once, as always).
+// Seeking a trigger that has not started creates its cursor, and it begins
+// there instead of at the head.
+func (ts *HTTPTriggerSet) topicSeek(w http.ResponseWriter, r *http.Request) {
+	if !ts.topicStore(w) {
+		return
+	}
+	var req topicSeekReq
+	if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&req); err != nil {
+		http.Error(w, "invalid seek request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Namespace == "" || strings.Contains(req.Namespace, "/") || len(req.Namespace) > 253 {
+		http.Error(w, "namespace is required and must be a plain namespace name", http.StatusBadRequest)
+		return
+	}
+	if err := fv1.ValidateTopicName("topic", req.Topic); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errs := validation.IsDNS1123Subdomain(req.Trigger); len(errs) > 0 {
+		http.Error(w, "invalid trigger name: "+strings.Join(errs, "; "), http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	stream := mqpub.StreamForTopic(req.Namespace, req.Topic)
+	target, err := ts.seekCursor(ctx, stream, req.To)
+	if err != nil {
+		if errors.Is(err, errSeekTarget) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ts.logger.Error(err, "topic admin seek: resolving target", "stream", stream, "to", req.To)
+		http.Error(w, "reading topic", http.StatusInternalServerError)
+		return
+	}
+	scope := mqpub.CursorScope(req.Namespace, req.Trigger)
+	for range topicSeekCASRetries {
+		previous, version, _, err := ts.topicCursor(ctx, req.Namespace, req.Trigger)
+		if err != nil {
+			ts.logger.Error(err, "topic admin seek: reading cursor", "namespace", req.Namespace, "trigger", req.Trigger)
+			http.Error(w, "reading subscription cursor", http.StatusInternalServerError)
+			return
+		}
+		err = ts.asyncInvoker.topicKV.Set(ctx, scope, mqpub.CursorKey,
+			[]byte(strconv.FormatInt(target, 10)), statestore.SetOptions{IfVersion: new(version)})
+		if errors.Is(err, statestore.ErrVersionConflict) {
+			continue
+		}
+		if err != nil {
+			ts.logger.Error(err, "topic admin seek: writing cursor", "namespace", req.Namespace, "trigger", req.Trigger)
+			http.Error(w, "writing subscription cursor", http.StatusInternalServerError)
+			return
+		}
+		ts.logger.Info("topic subscription cursor moved", "stream", stream, "trigger", req.Trigger,
+			"previous", previous, "cursor", target, "to", req.To)
+		dlqWriteJSON(w, ts, topicSeekResp{Trigger: req.Trigger, Previous: previous, Cursor: target})
+		return
+	}
+	http.Error(w, "the subscription kept committing; retry the seek", http.StatusConflict)
+}
@@ -13,6 +13,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
@@ -37,11 +38,15 @@ func topicTestSet(t *testing.T) (*HTTPTriggerSet, statestore.Capabilities) {
 	require.NoError(t, err)
 	el, err := caps.EventLog()
 	require.NoError(t, err)
+	kv, err := caps.KV()
+	require.NoError(t, err)
 	pub := mqpub.NewMultiPublisher(mqpub.NewStatestorePublisher(el), mqpub.NewEgressPublisher(q, fv1.MessageQueueTypeKafka))
 	ts := &HTTPTriggerSet{logger: logr.Discard(), asyncInvoker: &asyncInvoker{
-		queue:    q,
-		logger:   logr.Discard(),
-		eventLog: el,
+		queue:          q,
+		logger:         logr.Discard(),
+		eventLog:       el,
+		topicPublisher: pub,
+		topicKV:        kv,
 		// The router's exact wiring: translate mqpub's sentinel to the
 		// dispatcher's at the boundary.
 		publishTopic: func(ctx context.Context, ns, mqType, topic, contentType string, payload []byte) error {
@@ -142,9 +147,141 @@ func TestTopicPeekBounds(t *testing.T) {
 func TestTopicHandlers501WithoutStatestore(t *testing.T) {
 	t.Parallel()
 	ts := &HTTPTriggerSet{logger: logr.Discard()}
-	for _, h := range []func(http.ResponseWriter, *http.Request){ts.topicPublish, ts.topicPeek} {
+	for _, h := range []func(http.ResponseWriter, *http.Request){
+		ts.topicPublish, ts.topicPeek, ts.topicRetentionGet, ts.topicRetentionSet, ts.topicGroups, ts.topicSeek,
+	} {
 		rr := httptest.NewRecorder()
 		h(rr, httptest.NewRequest(http.MethodGet, topicPathPeek+"?namespace=ns&topic=t", nil))
 		assert.Equal(t, http.StatusNotImplemented, rr.Code)
 	}
 }
+
+// publishN publishes n unkeyed events to ns1/orders.
+func publishN(t *testing.T, ts *HTTPTriggerSet, n int) {
+	t.Helper()
+	for range n {
+		rr := httptest.NewRecorder()
+		ts.topicPublish(rr, httptest.NewRequest(http.MethodPost, topicPathPublish+"?namespace=ns1&topic=orders", strings.NewReader("e")))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	}
+}
+
+func TestTopicPublishKeyed(t *testing.T) {
+	t.Parallel()
+	ts, _ := topicTestSet(t)
+	post := func(pathAndQuery string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		ts.topicPublish(rr, httptest.NewRequest(http.MethodPost, pathAndQuery, strings.NewReader("v")))
+		return rr
+	}
+
+	require.Equal(t, http.StatusOK, post(topicPathPublish+"?namespace=ns1&topic=orders&key=sku-1").Code)
+	assert.Equal(t, http.StatusBadRequest, post(topicPathPublish+"?namespace=ns1&topic=orders&mqtype=kafka&key=sku-1").Code,
+		"a key on a broker topic is a caller mistake, not a silently dropped key")
+	assert.Equal(t, http.StatusBadRequest, post(topicPathPublish+"?namespace=ns1&topic=orders&key="+strings.Repeat("k", mqpub.MaxKeyLen+1)).Code)
+
+	rr := httptest.NewRecorder()
+	ts.topicPeek(rr, httptest.NewRequest(http.MethodGet, topicPathPeek+"?namespace=ns1&topic=orders", nil))
+	var peek topicPeekResp
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &peek))
+	require.Len(t, peek.Events, 1)
+	assert.Equal(t, "sku-1", peek.Events[0].Key)
+}
+
+func TestTopicRetentionRoundTrip(t *testing.T) {
+	t.Parallel()
+	ts, _ := topicTestSet(t)
+	put := func(body string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		ts.topicRetentionSet(rr, httptest.NewRequest(http.MethodPut, topicPathRetention+"?namespace=ns1&topic=orders", strings.NewReader(body)))
+		return rr
+	}
+	get := func() mqpub.TopicRetention {
+		rr := httptest.NewRecorder()
+		ts.topicRetentionGet(rr, httptest.NewRequest(http.MethodGet, topicPathRetention+"?namespace=ns1&topic=orders", nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var got mqpub.TopicRetention
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+		return got
+	}
+
+	assert.True(t, get().IsZero(), "no record is the min-cursor default")
+	require.Equal(t, http.StatusOK, put(`{"maxAge":"1h","maxEvents":100,"compact":true}`).Code)
+	got := get()
+	assert.Equal(t, time.Hour, got.MaxAgeOr(0))
+	assert.EqualValues(t, 100, got.MaxEvents)
+	assert.True(t, got.Compact)
+
+	assert.Equal(t, http.StatusBadRequest, put(`{"maxEvents":-1}`).Code)
+	assert.Equal(t, http.StatusBadRequest, put(`{"maxEvents":1000000}`).Code, "above half the backlog cap")
+	assert.Equal(t, http.StatusBadRequest, put(`not json`).Code)
+
+	require.Equal(t, http.StatusOK, put(`{}`).Code)
+	assert.True(t, get().IsZero(), "an empty policy clears the record")
+}
+
+func TestTopicGroupsAndSeek(t *testing.T) {
+	t.Parallel()
+	ts, caps := topicTestSet(t)
+	publishN(t, ts, 5)
+	el, err := caps.EventLog()
+	require.NoError(t, err)
+	require.NoError(t, el.Trim(t.Context(), mqpub.StreamForTopic("ns1", "orders"), 2)) // floor 1
+
+	groups := func() topicGroupsResp {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		ts.topicGroups(rr, httptest.NewRequest(http.MethodGet, topicPathGroups+"?namespace=ns1&topic=orders&trigger=a&trigger=b", nil))
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var resp topicGroupsResp
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp
+	}
+	seek := func(trigger, to string) (int, topicSeekResp) {
+		t.Helper()
+		body, err := json.Marshal(topicSeekReq{Namespace: "ns1", Topic: "orders", Trigger: trigger, To: to})
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		ts.topicSeek(rr, httptest.NewRequest(http.MethodPost, topicPathSeek, strings.NewReader(string(body))))
+		var resp topicSeekResp
+		if rr.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		}
+		return rr.Code, resp
+	}
+
+	g := groups()
+	assert.EqualValues(t, 5, g.Head)
+	assert.EqualValues(t, 1, g.Floor)
+	assert.EqualValues(t, 4, g.Events)
+	assert.EqualValues(t, 4, g.Bytes)
+	require.Len(t, g.Groups, 2)
+	assert.False(t, g.Groups[0].Initialized, "a trigger that never subscribed has no cursor")
+
+	code, resp := seek("a", "earliest")
+	require.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 1, resp.Cursor, "earliest resumes at the first retained event")
+	code, resp = seek("a", "4")
+	require.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 1, resp.Previous)
+	assert.EqualValues(t, 3, resp.Cursor, "a sequence target resumes delivery AT that event")
+	_, resp = seek("a", "1")
+	assert.EqualValues(t, 1, resp.Cursor, "a trimmed sequence clamps to the floor")
+	_, resp = seek("b", "latest")
+	assert.EqualValues(t, 5, resp.Cursor)
+	_, resp = seek("b", time.Now().Add(-time.Hour).Format(time.RFC3339))
+	assert.EqualValues(t, 1, resp.Cursor, "a time before every event resumes at the first retained")
+	_, resp = seek("b", time.Now().Add(time.Hour).Format(time.RFC3339))
+	assert.EqualValues(t, 5, resp.Cursor, "a time after every event resumes at the head")
+
+	g = groups()
+	assert.Equal(t, topicGroup{Trigger: "a", Initialized: true, Cursor: 1, Lag: 4}, g.Groups[0])
+	assert.Equal(t, topicGroup{Trigger: "b", Initialized: true, Cursor: 5, Lag: 0}, g.Groups[1])
+
+	code, _ = seek("a", "yesterday")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = seek("a", "0")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = seek("Bad_Name", "latest")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -95,6 +95,9 @@ type FunctionConfig struct {
 	OnSuccess       *Destination
 	OnFailure       *Destination
 	FunctionTimeout int
+	// Priority is the function's default lane; a fired function destination is
+	// enqueued on it (empty = normal).
+	Priority string
 }
 
 // FunctionConfigResolver resolves a function's async config at destination-fire
@@ -130,7 +133,12 @@ type Options struct {
 	Deliverer Deliverer
 	Logger    logr.Logger
 
-	QueueName     string        // "" → DefaultQueue
+	// Lanes are the priority lanes polled weighted-fair. Empty → a single lane
+	// on QueueName when that is set (tests, a dedicated queue), else
+	// DefaultLanes(). A lane with a non-positive weight gets weight 1 so it
+	// cannot starve.
+	Lanes         []Lane
+	QueueName     string
 	BatchSize     int           // 0 → DefaultBatchSize
 	PollInterval  time.Duration // 0 → DefaultPollInterval
 	LeaseDuration time.Duration // 0 → DefaultLeaseDuration
@@ -157,7 +165,8 @@ type Dispatcher struct {
 	deliverer Deliverer
 	logger    logr.Logger
 
-	queueName     string
+	lanes         []Lane
+	sched         *laneScheduler
 	batchSize     int
 	pollInterval  time.Duration
 	leaseDuration time.Duration
@@ -173,7 +182,6 @@ func New(opts Options) *Dispatcher {
 		q:             opts.Queue,
 		deliverer:     opts.Deliverer,
 		logger:        opts.Logger,
-		queueName:     opts.QueueName,
 		batchSize:     opts.BatchSize,
 		pollInterval:  opts.PollInterval,
 		leaseDuration: opts.LeaseDuration,
@@ -182,9 +190,20 @@ func New(opts Options) *Dispatcher {
 		now:           opts.Now,
 		rand:          opts.Rand,
 	}
-	if d.queueName == "" {
-		d.queueName = DefaultQueue
+	switch {
+	case len(opts.Lanes) > 0:
+		d.lanes = append([]Lane(nil), opts.Lanes...)
+	case opts.QueueName != "":
+		d.lanes = []Lane{{Priority: PriorityNormal, Queue: opts.QueueName, Weight: 1}}
+	default:
+		d.lanes = DefaultLanes()
 	}
+	for i := range d.lanes {
+		if d.lanes[i].Weight <= 0 {
+			d.lanes[i].Weight = 1
+		}
+	}
+	d.sched = newLaneScheduler(d.lanes)
 	if d.batchSize <= 0 {
 		d.batchSize = DefaultBatchSize
 	}
@@ -204,11 +223,16 @@ func New(opts Options) *Dispatcher {
 }
 
 // Run leases and settles until ctx is cancelled: it leases a batch, delivers the
-// batch concurrently, waits, and leases again; an empty lease sleeps pollInterval
-// (interruptibly). Returns ctx.Err() on cancellation. Multiple router replicas
-// call Run against the same queue safely — statestore leases are SKIP LOCKED.
+// batch concurrently, waits, and leases again; a poll that finds every lane
+// empty sleeps pollInterval (interruptibly). Returns ctx.Err() on cancellation.
+// Multiple router replicas call Run against the same queues safely — statestore
+// leases are SKIP LOCKED.
 func (d *Dispatcher) Run(ctx context.Context) error {
-	d.logger.Info("async dispatcher started", "queue", d.queueName)
+	queues := make([]string, 0, len(d.lanes))
+	for _, l := range d.lanes {
+		queues = append(queues, l.Queue)
+	}
+	d.logger.Info("async dispatcher started", "queues", queues)
 	for {
 		if ctx.Err() != nil {
 			return ctx.Err()
@@ -221,21 +245,56 @@ func (d *Dispatcher) Run(ctx context.Context) error {
 	}
 }
 
-// pollOnce leases one batch and delivers it concurrently, returning the count.
+// pollOnce leases one batch from the next lane with work and delivers it
+// concurrently, returning the count. The weighted pick goes first; when it is
+// empty the other lanes are tried highest-priority first, so a poll is only
+// idle when every lane is.
 func (d *Dispatcher) pollOnce(ctx context.Context) int {
-	msgs, err := d.q.Lease(ctx, d.queueName, d.batchSize, d.leaseDuration)
-	if err != nil {
-		if ctx.Err() == nil {
-			d.logger.Error(err, "lease failed", "queue", d.queueName)
+	for _, i := range d.sched.order() {
+		lane := d.lanes[i]
+		msgs, err := d.q.Lease(ctx, lane.Queue, d.batchSize, d.leaseDuration)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0
+			}
+			d.logger.Error(err, "lease failed", "queue", lane.Queue)
+			continue
+		}
+		if len(msgs) > 0 {
+			d.deliverBatch(ctx, msgs)
+			return len(msgs)
 		}
-		return 0
 	}
+	return 0
+}
+
+// deliverBatch processes one leased batch concurrently and waits for it.
+func (d *Dispatcher) deliverBatch(ctx context.Context, msgs []statestore.LeasedMessage) {
 	var wg sync.WaitGroup
 	for _, msg := range msgs {
 		wg.Go(func() { d.process(ctx, msg) })
 	}
 	wg.Wait()
-	return len(msgs)
+}
+
+// queueFor returns the queue of the lane serving priority, falling back to the
+// normal lane, then to the first configured lane (a single-queue dispatcher
+// serves every priority on its one queue).
+func (d *Dispatcher) queueFor(priority string) string {
+	if priority == "" {
+		priority = PriorityNormal
+	}
+	for _, l := range d.lanes {
+		if l.Priority == priority {
+			return l.Queue
+		}
+	}
+	for _, l := range d.lanes {
+		if l.Priority == PriorityNormal {
+			return l.Queue
+		}
+	}
+	return d.lanes[0].Queue
 }
 
 // process delivers one leased invocation and settles it per the settle matrix.
@@ -374,6 +433,13 @@ func (d *Dispatcher) logSettle(op, id string, err error) {
 	d.logger.Error(err, op+" failed", "id", id)
 }
 
+// FireDestination invokes dest with result for an invocation settled outside
+// the dispatcher — a job invocation, whose outcome the router learns by
+// polling its Job — with the same depth cap and metrics as a queued one.
+func (d *Dispatcher) FireDestination(ctx context.Context, dest *Destination, depth int, result ResultEnvelope) {
+	d.fireDestination(ctx, dest, depth, result)
+}
+
 // fireDestination invokes a settled invocation's destination. It is best-effort —
 // the primary is already settled, so a failure here cannot un-settle it — but
 // always observable via the destinations metric. depth is the SOURCE invocation's
@@ -437,8 +503,11 @@ func (d *Dispatcher) fireDestination(ctx context.Context, dest *Destination, dep
 		Policy:          cfg.Policy,
 		OnSuccess:       cfg.OnSuccess,
 		OnFailure:       cfg.OnFailure,
+		// A destination runs on its own function's lane, not the source's: the
+		// destination owner decides how urgent its work is.
+		Priority: cfg.Priority,
 	}
-	if _, err := encodeAndEnqueue(ctx, d.q, d.queueName, env, statestore.EnqueueOptions{}); err != nil {
+	if _, err := encodeAndEnqueue(ctx, d.q, d.queueFor(cfg.Priority), env, statestore.EnqueueOptions{}); err != nil {
 		// The wrapped error already says encode-vs-enqueue; the function names the hop.
 		recordDestination(ctx, "enqueue_error")
 		d.logger.Error(err, "enqueuing destination invocation", "function", dest.FunctionName)
@@ -83,12 +83,12 @@ func TestRunDeadLettersRepeatedFailure(t *testing.T) {
 	go func() { _ = d.Run(ctx) }()
 
 	require.Eventually(t, func() bool {
-		dead, err := q.DeadLetters(t.Context(), DefaultQueue, statestore.Page{})
+		dead, err := q.DeadLetters(t.Context(), DefaultQueue, statestore.Page{}, statestore.DeadLetterFilter{})
 		require.NoError(t, err)
 		return len(dead) == 1
 	}, 3*time.Second, 5*time.Millisecond)
 
-	dead, err := q.DeadLetters(t.Context(), DefaultQueue, statestore.Page{})
+	dead, err := q.DeadLetters(t.Context(), DefaultQueue, statestore.Page{}, statestore.DeadLetterFilter{})
 	require.NoError(t, err)
 	require.Len(t, dead, 1)
 	assert.Equal(t, id, dead[0].ID)
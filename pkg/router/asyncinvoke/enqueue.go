@@ -41,8 +41,11 @@ type Params struct {
 	// (nil = none), so the dispatcher fires them without re-reading the Function.
 	OnSuccess *Destination
 	OnFailure *Destination
-	// QueueName defaults to DefaultQueue when empty. MaxBodyBytes defaults to
-	// DefaultMaxBodyBytes when <= 0.
+	// Priority is the resolved lane (header override, else the function's
+	// default; empty = normal). It picks the queue when QueueName is empty.
+	Priority string
+	// QueueName defaults to the Priority lane's queue when empty. MaxBodyBytes
+	// defaults to DefaultMaxBodyBytes when <= 0.
 	QueueName    string
 	MaxBodyBytes int64
 }
@@ -76,10 +79,11 @@ func Enqueue(ctx context.Context, q statestore.Queue, w http.ResponseWriter, r *
 		Policy:          p.Policy,
 		OnSuccess:       p.OnSuccess,
 		OnFailure:       p.OnFailure,
+		Priority:        p.Priority,
 	}
 	queue := p.QueueName
 	if queue == "" {
-		queue = DefaultQueue
+		queue = QueueForPriority(p.Priority)
 	}
 	return encodeAndEnqueue(ctx, q, queue, env, statestore.EnqueueOptions{DedupKey: p.DedupKey})
 }
@@ -119,6 +119,10 @@ type Envelope struct {
 	// stamped from the function's InvocationConfig at enqueue. nil = no destination.
 	OnSuccess *Destination `json:"onSuccess,omitempty"`
 	OnFailure *Destination `json:"onFailure,omitempty"`
+	// Priority is the lane the invocation was enqueued on (empty = normal). The
+	// lane is decided by which queue holds the message, not by this field; it is
+	// carried for DLQ display and so a redrive-to-another-target keeps the lane.
+	Priority string `json:"priority,omitempty"`
 }
 
 // Destination is a settled-invocation destination stamped into the envelope: a
@@ -178,6 +182,18 @@ func (d Destination) functionRouteName() string {
 // IsTopic reports whether the destination targets a topic.
 func (d Destination) IsTopic() bool { return d.Topic != "" }
 
+// Retarget returns a copy of the envelope re-pointed at dest's function in the
+// envelope's own namespace (DLQ redrive to a different target), using the same
+// alias-suffix / version-field split as a fired destination. The request itself
+// (method, path, headers, body) and the chain depth are kept; EnqueueTime is
+// reset to now so the invocation gets a fresh MaxAge window.
+func (e Envelope) Retarget(dest Destination, now time.Time) Envelope {
+	e.Function = dest.functionRouteName()
+	e.FunctionVersion = dest.Version
+	e.EnqueueTime = now
+	return e
+}
+
 // Encode marshals the envelope for a statestore Queue message body.
 func (e Envelope) Encode() ([]byte, error) { return json.Marshal(e) }
 
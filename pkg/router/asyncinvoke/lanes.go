@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asyncinvoke
+
+import "strings"
+
+// Priority lanes. Each lane is its own statestore queue, so a lane's backlog is
+// invisible to the others' leases: a bulk backfill on the low lane cannot sit in
+// front of latency-sensitive work on the high lane. The normal lane keeps the
+// pre-lane queue name, so messages enqueued before lanes existed (and a router
+// still running the old code during a roll) share it unchanged.
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+
+	// HeaderInvokePriority selects the lane for one async request, overriding
+	// the function's InvocationConfig.Priority default.
+	HeaderInvokePriority = "X-Fission-Invoke-Priority"
+)
+
+// Default lane weights: out of every ten polls with all three lanes backlogged,
+// high gets six, normal three, low one. Every weight is positive, so no lane
+// starves — the point of weighting rather than strict priority.
+const (
+	DefaultHighWeight   = 6
+	DefaultNormalWeight = 3
+	DefaultLowWeight    = 1
+)
+
+// Lane is one priority lane the dispatcher polls: its priority name, the
+// statestore queue backing it, and its weighted-fair share of polls.
+type Lane struct {
+	Priority string
+	Queue    string
+	Weight   int
+}
+
+// DefaultLanes is the router's lane set, highest priority first.
+func DefaultLanes() []Lane {
+	return []Lane{
+		{Priority: PriorityHigh, Queue: QueueForPriority(PriorityHigh), Weight: DefaultHighWeight},
+		{Priority: PriorityNormal, Queue: QueueForPriority(PriorityNormal), Weight: DefaultNormalWeight},
+		{Priority: PriorityLow, Queue: QueueForPriority(PriorityLow), Weight: DefaultLowWeight},
+	}
+}
+
+// QueueForPriority maps a lane to its statestore queue name. The normal lane
+// (and the empty or an unknown priority) is DefaultQueue; the others suffix it.
+func QueueForPriority(priority string) string {
+	switch priority {
+	case PriorityHigh, PriorityLow:
+		return DefaultQueue + "-" + priority
+	default:
+		return DefaultQueue
+	}
+}
+
+// IsLaneQueue reports whether name is one of the priority lane queues.
+func IsLaneQueue(name string) bool {
+	for _, l := range DefaultLanes() {
+		if l.Queue == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePriority normalizes a caller-supplied priority (case-insensitive,
+// surrounding space ignored). The empty string parses to "" (no preference);
+// anything other than high/normal/low is rejected.
+func ParsePriority(raw string) (string, bool) {
+	p := strings.ToLower(strings.TrimSpace(raw))
+	switch p {
+	case "", PriorityHigh, PriorityNormal, PriorityLow:
+		return p, true
+	default:
+		return "", false
+	}
+}
+
+// laneScheduler picks the next lane to poll by smooth weighted round-robin
+// (the nginx upstream algorithm): every pick adds each lane's weight to its
+// running credit, takes the lane with the most credit, and charges it the total
+// weight. Picks interleave instead of bursting (6:3:1 comes out as H N H H N H
+// L H N H, not six highs in a row), and over any window of total-weight picks
+// each lane gets exactly its share. Not safe for concurrent use; the
+// dispatcher's poll loop is its only caller.
+type laneScheduler struct {
+	lanes  []Lane
+	credit []int
+	total  int
+}
+
+func newLaneScheduler(lanes []Lane) *laneScheduler {
+	s := &laneScheduler{lanes: lanes, credit: make([]int, len(lanes))}
+	for _, l := range lanes {
+		s.total += l.Weight
+	}
+	return s
+}
+
+// order returns lane indices for one poll: the weighted pick first, then the
+// remaining lanes highest-priority first. The dispatcher leases from the first
+// lane that has work, so an idle picked lane hands its turn on instead of
+// wasting a poll — the fairness bound holds only while lanes are backlogged,
+// which is exactly when it matters.
+func (s *laneScheduler) order() []int {
+	best := 0
+	for i, l := range s.lanes {
+		s.credit[i] += l.Weight
+		if s.credit[i] > s.credit[best] {
+			best = i
+		}
+	}
+	s.credit[best] -= s.total
+	out := make([]int, 0, len(s.lanes))
+	out = append(out, best)
+	for i := range s.lanes {
+		if i != best {
+			out = append(out, i)
+		}
+	}
+	return out
+}
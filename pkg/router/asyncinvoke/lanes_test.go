@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package asyncinvoke
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rapid"
+
+	"github.com/fission/fission/pkg/statestore"
+)
+
+func TestQueueForPriority(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "asyncinv-high", QueueForPriority(PriorityHigh))
+	assert.Equal(t, DefaultQueue, QueueForPriority(PriorityNormal))
+	assert.Equal(t, DefaultQueue, QueueForPriority(""), "no preference is the normal lane")
+	assert.Equal(t, "asyncinv-low", QueueForPriority(PriorityLow))
+	assert.Equal(t, DefaultQueue, QueueForPriority("urgent"), "an unknown priority never mints a queue")
+
+	assert.True(t, IsLaneQueue("asyncinv-high"))
+	assert.True(t, IsLaneQueue(DefaultQueue))
+	assert.False(t, IsLaneQueue("asyncinv-urgent"))
+}
+
+func TestParsePriority(t *testing.T) {
+	t.Parallel()
+	for raw, want := range map[string]string{"": "", "high": PriorityHigh, " LOW ": PriorityLow, "Normal": PriorityNormal} {
+		got, ok := ParsePriority(raw)
+		assert.True(t, ok, raw)
+		assert.Equal(t, want, got, raw)
+	}
+	_, ok := ParsePriority("urgent")
+	assert.False(t, ok)
+}
+
+// TestLaneSchedulerShares: over any window of total-weight polls each lane is
+// picked exactly its weight, so no lane starves and none bursts.
+func TestLaneSchedulerShares(t *testing.T) {
+	t.Parallel()
+	rapid.Check(t, func(t *rapid.T) {
+		n := rapid.IntRange(1, 4).Draw(t, "lanes")
+		lanes := make([]Lane, n)
+		total := 0
+		for i := range lanes {
+			lanes[i].Weight = rapid.IntRange(1, 10).Draw(t, "weight")
+			total += lanes[i].Weight
+		}
+		s := newLaneScheduler(lanes)
+		rounds := rapid.IntRange(1, 5).Draw(t, "rounds")
+		for range rounds {
+			picks := make([]int, n)
+			for range total {
+				order := s.order()
+				if len(order) != n {
+					t.Fatalf("order has %d lanes, want %d", len(order), n)
+				}
+				picks[order[0]]++
+			}
+			for i, l := range lanes {
+				if picks[i] != l.Weight {
+					t.Fatalf("lane %d picked %d times in a window of %d, want %d", i, picks[i], total, l.Weight)
+				}
+			}
+		}
+	})
+}
+
+// TestLaneSchedulerInterleaves: the default 6:3:1 weights give the low lane a
+// turn within every ten polls and never hand the high lane more than two in a
+// row, rather than six highs then three normals then one low.
+func TestLaneSchedulerInterleaves(t *testing.T) {
+	t.Parallel()
+	s := newLaneScheduler(DefaultLanes())
+	var seq []int
+	for range 10 {
+		seq = append(seq, s.order()[0])
+	}
+	assert.Equal(t, []int{0, 1, 0, 0, 1, 0, 2, 0, 1, 0}, seq)
+}
+
+// TestPollOnceLowLaneDrainsUnderHighBacklog: with the high lane permanently
+// backlogged, the low lane still gets its weighted turn — a bulk backfill on low
+// is slowed, never starved.
+func TestPollOnceLowLaneDrainsUnderHighBacklog(t *testing.T) {
+	t.Parallel()
+	q := memQueue(t)
+	enqueueOn := func(queue string) {
+		body, err := Envelope{Version: EnvelopeVersion, Namespace: "ns", Function: "fn", EnqueueTime: time.Now()}.Encode()
+		require.NoError(t, err)
+		_, err = q.Enqueue(t.Context(), queue, statestore.Message{Body: body}, statestore.EnqueueOptions{})
+		require.NoError(t, err)
+	}
+	for range 100 {
+		enqueueOn(QueueForPriority(PriorityHigh))
+	}
+	enqueueOn(QueueForPriority(PriorityLow))
+
+	d := New(Options{
+		Queue: q, BatchSize: 1, Logger: logr.Discard(),
+		Deliverer: delivererFunc(func(context.Context, Envelope, string, int) DeliveryResult {
+			return DeliveryResult{StatusCode: 200}
+		}),
+	})
+	for range DefaultHighWeight + DefaultNormalWeight + DefaultLowWeight {
+		d.pollOnce(t.Context())
+	}
+	low, err := q.Stats(t.Context(), QueueForPriority(PriorityLow))
+	require.NoError(t, err)
+	assert.Zero(t, low.Visible, "the low lane is served within one weight window")
+	high, err := q.Stats(t.Context(), QueueForPriority(PriorityHigh))
+	require.NoError(t, err)
+	assert.Equal(t, int64(91), high.Visible, "the idle normal lane's turns fall through to high")
+}
+
+// TestFireDestinationUsesDestinationLane: a function destination is enqueued on
+// the destination function's own lane.
+func TestFireDestinationUsesDestinationLane(t *testing.T) {
+	t.Parallel()
+	q := memQueue(t)
+	d := New(Options{
+		Queue: q, Deliverer: scriptedDeliverer{DeliveryResult{StatusCode: 200}}, Logger: logr.Discard(),
+		ResolveFunctionConfig: func(context.Context, string, string) (FunctionConfig, bool) {
+			return FunctionConfig{Priority: PriorityLow}, true
+		},
+	})
+	d.fireDestination(t.Context(), &Destination{FunctionNamespace: "ns", FunctionName: "next"}, 0, ResultEnvelope{})
+
+	low, err := q.Stats(t.Context(), QueueForPriority(PriorityLow))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), low.Visible)
+}
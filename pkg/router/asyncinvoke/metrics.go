@@ -6,6 +6,7 @@ package asyncinvoke
 
 import (
 	"context"
+	"errors"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -75,15 +76,16 @@ func deliveryCondition(res DeliveryResult) string {
 }
 
 // RegisterQueueGauges registers the async depth and oldest-age observable gauges,
-// read from q.Stats(queueName) on each metrics collection. Call it once at router
-// start (only when async invocation is enabled).
-func RegisterQueueGauges(q statestore.Queue, queueName string) {
+// one series per priority lane (labeled priority), read from each lane queue's
+// Stats on every metrics collection. Call it once at router start (only when
+// async invocation is enabled) with the lanes the dispatcher polls.
+func RegisterQueueGauges(q statestore.Queue, lanes []Lane) {
 	metrics.Int64ObservableGauge("fission_async_queue_depth",
-		"Async invocation queue depth: visible messages awaiting delivery",
-		observeStat(q, queueName, func(st statestore.QueueStats) int64 { return st.Visible }))
+		"Async invocation queue depth: visible messages awaiting delivery, labeled by priority lane",
+		observeLanes(q, lanes, func(st statestore.QueueStats) int64 { return st.Visible }))
 	metrics.Int64ObservableGauge("fission_async_oldest_age_seconds",
-		"Age in seconds of the oldest visible async invocation (0 when none)",
-		observeStat(q, queueName, func(st statestore.QueueStats) int64 {
+		"Age in seconds of the oldest visible async invocation (0 when none), labeled by priority lane",
+		observeLanes(q, lanes, func(st statestore.QueueStats) int64 {
 			return int64(st.OldestVisibleAge.Seconds())
 		}))
 }
@@ -117,18 +119,23 @@ func RegisterEgressQueueGauges(q statestore.Queue, mqType, queueName string) {
 		})
 }
 
-// observeStat builds an observable-gauge callback that reads q.Stats(queueName)
-// and observes one field. A Stats read failure is RETURNED (routed to
-// otel.Handle) rather than swallowed: the OTel SDK skips only this instrument's
-// observation and continues the collection, so the scrape still succeeds AND the
-// failure produces a signal instead of the gauge silently freezing at zero.
-func observeStat(q statestore.Queue, queueName string, pick func(statestore.QueueStats) int64) metric.Int64Callback {
+// observeLanes builds an observable-gauge callback that reads each lane queue's
+// Stats and observes one field, labeled by the lane's priority. A Stats read
+// failure is RETURNED (routed to otel.Handle) rather than swallowed: the OTel
+// SDK skips only this instrument's observation and continues the collection, so
+// the scrape still succeeds AND the failure produces a signal instead of the
+// gauge silently freezing at zero. The lanes that did read are still observed.
+func observeLanes(q statestore.Queue, lanes []Lane, pick func(statestore.QueueStats) int64) metric.Int64Callback {
 	return func(ctx context.Context, o metric.Int64Observer) error {
-		st, err := q.Stats(ctx, queueName)
-		if err != nil {
-			return err
+		var errs error
+		for _, l := range lanes {
+			st, err := q.Stats(ctx, l.Queue)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				continue
+			}
+			o.Observe(pick(st), metric.WithAttributes(attribute.String("priority", l.Priority)))
 		}
-		o.Observe(pick(st))
-		return nil
+		return errs
 	}
 }
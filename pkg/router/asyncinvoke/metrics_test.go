@@ -41,5 +41,5 @@ func TestDeliveryCondition(t *testing.T) {
 // this a wiring smoke test, not an emission assertion).
 func TestRegisterQueueGaugesSmoke(t *testing.T) {
 	q := memQueue(t)
-	require.NotPanics(t, func() { RegisterQueueGauges(q, DefaultQueue) })
+	require.NotPanics(t, func() { RegisterQueueGauges(q, DefaultLanes()) })
 }
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/conditions"
+	"github.com/fission/fission/pkg/generated/clientset/versioned"
+	"github.com/fission/fission/pkg/router/endpointcache"
+)
+
+// breakerStatusInterval coalesces a function's circuit breaker transitions
+// into at most one status write per interval: an incident ejecting pods one by
+// one (or flapping half-open probes) would otherwise write per transition.
+const breakerStatusInterval = 5 * time.Second
+
+// breakerStatusWriter mirrors the endpoint index's circuit breaker state onto
+// the CircuitClosed condition of the functions whose breakers moved. Each
+// router replica keeps its own breakers, so the condition is the view of the
+// replica that wrote it last; the transitions metric carries every replica's.
+type breakerStatusWriter struct {
+	logger        logr.Logger
+	fissionClient versioned.Interface
+	index         *endpointcache.Index
+
+	mu      sync.Mutex
+	pending map[types.NamespacedName]string // function -> pool version
+}
+
+func newBreakerStatusWriter(logger logr.Logger, fissionClient versioned.Interface, ix *endpointcache.Index) *breakerStatusWriter {
+	return &breakerStatusWriter{
+		logger:        logger.WithName("breaker_status"),
+		fissionClient: fissionClient,
+		index:         ix,
+		pending:       map[types.NamespacedName]string{},
+	}
+}
+
+// notify queues fn's condition for the next flush.
+func (w *breakerStatusWriter) notify(fn *fv1.Function) {
+	w.mu.Lock()
+	w.pending[types.NamespacedName{Namespace: fn.Namespace, Name: fn.Name}] = backendVersion(fn)
+	w.mu.Unlock()
+}
+
+// run flushes the queued conditions every breakerStatusInterval until ctx is
+// done.
+func (w *breakerStatusWriter) run(ctx context.Context) {
+	ticker := time.NewTicker(breakerStatusInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.flush(ctx)
+		}
+	}
+}
+
+func (w *breakerStatusWriter) flush(ctx context.Context) {
+	w.mu.Lock()
+	pending := w.pending
+	w.pending = map[types.NamespacedName]string{}
+	w.mu.Unlock()
+	for key, version := range pending {
+		w.write(ctx, key, w.condition(key, version))
+	}
+}
+
+// condition computes the CircuitClosed condition from the index's current
+// view of the function's pool.
+func (w *breakerStatusWriter) condition(key types.NamespacedName, version string) metav1.Condition {
+	states := w.index.Breakers(key.Namespace, key.Name, version)
+	if len(states) == 0 {
+		return metav1.Condition{
+			Type:    fv1.FunctionConditionCircuitClosed,
+			Status:  metav1.ConditionTrue,
+			Reason:  fv1.FunctionReasonCircuitClosed,
+			Message: "every endpoint is in rotation",
+		}
+	}
+	halfOpen := 0
+	for _, st := range states {
+		if st.HalfOpen {
+			halfOpen++
+		}
+	}
+	msg := fmt.Sprintf("%d of %d endpoints ejected (%d half-open)",
+		len(states), w.index.ReadyCount(key.Namespace, key.Name, version), halfOpen)
+	if version != "" {
+		msg += " in version " + version
+	}
+	return metav1.Condition{
+		Type:    fv1.FunctionConditionCircuitClosed,
+		Status:  metav1.ConditionFalse,
+		Reason:  fv1.FunctionReasonEndpointsEjected,
+		Message: msg,
+	}
+}
+
+// write persists want on the function, skipping the update when the stored
+// condition already matches it exactly. Best-effort like every router status
+// write.
+func (w *breakerStatusWriter) write(ctx context.Context, key types.NamespacedName, want metav1.Condition) {
+	if w.fissionClient == nil {
+		return // unit-test wiring without a real client
+	}
+	cur, err := w.fissionClient.CoreV1().Functions(key.Namespace).Get(ctx, key.Name, metav1.GetOptions{})
+	if err != nil {
+		w.logger.V(1).Info("function status: get failed", "name", key.Name, "namespace", key.Namespace, "error", err)
+		return
+	}
+	want.ObservedGeneration = cur.Generation
+	// Set, not IsAt: the message carries the ejected count, and a change in
+	// it is worth a write.
+	if !conditions.Set(&cur.Status.Conditions, want) {
+		return
+	}
+	if _, err := w.fissionClient.CoreV1().Functions(key.Namespace).UpdateStatus(ctx, cur, metav1.UpdateOptions{}); err != nil {
+		w.logger.V(1).Info("function status: update failed", "name", key.Name, "namespace", key.Namespace, "error", err)
+	}
+}
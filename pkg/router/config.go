@@ -60,6 +60,10 @@ type routerConfig struct {
 	// pool per function address (ROUTER_ROUND_TRIP_MAX_IDLE_CONNS_PER_HOST;
 	// optional, 0 = the built-in default, soft-fail).
 	maxIdleConnsPerHost int
+	// responseCacheMaxBytes bounds the bytes of HTTPTrigger responses the
+	// replica caches (ROUTER_RESPONSE_CACHE_MAX_BYTES; optional, default
+	// defaultResponseCacheMaxBytes, soft-fail).
+	responseCacheMaxBytes int64
 	// structuredErrors selects the JSON failure-attribution error body
 	// (RFC-0015). Default true; ROUTER_STRUCTURED_ERRORS=false is the escape
 	// hatch restoring the legacy plain-text error body for callers that scrape
@@ -201,6 +205,21 @@ func loadRouterConfig(logger logr.Logger) (routerConfig, error) {
 		}
 	}
 
+	// Optional response-cache budget; like the pool size above, a sizing knob
+	// that keeps its default when unset or unparsable.
+	cfg.responseCacheMaxBytes = defaultResponseCacheMaxBytes
+	if raw := os.Getenv("ROUTER_RESPONSE_CACHE_MAX_BYTES"); raw != "" {
+		maxBytes, err := strconv.ParseInt(raw, 10, 64)
+		switch {
+		case err != nil:
+			logger.Error(err, "failed to parse 'ROUTER_RESPONSE_CACHE_MAX_BYTES' - using the default", "value", raw)
+		case maxBytes <= 0:
+			logger.Error(nil, "'ROUTER_RESPONSE_CACHE_MAX_BYTES' must be positive - using the default", "value", raw)
+		default:
+			cfg.responseCacheMaxBytes = maxBytes
+		}
+	}
+
 	// Optional; unset or unparsable means off — the flag is an optimization
 	// (per-endpoint dialing for newdeploy/container), not a correctness gate
 	// like the cache mode, so it soft-fails.
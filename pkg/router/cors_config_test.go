@@ -43,6 +43,15 @@ func TestTriggerConfigError(t *testing.T) {
 		{"ingress path not absolute", fv1.HTTPTriggerSpec{
 			IngressConfig: fv1.IngressConfig{Path: "no-leading-slash"},
 		}, fv1.HTTPTriggerReasonInvalidIngressConfig},
+		{"valid auth policy", fv1.HTTPTriggerSpec{
+			Auth: &fv1.HTTPTriggerAuth{Type: fv1.HTTPTriggerAuthAPIKey, APIKey: &fv1.HTTPTriggerAPIKeyAuth{SecretName: "keys"}},
+		}, ""},
+		{"auth issuer not a URL", fv1.HTTPTriggerSpec{
+			Auth: &fv1.HTTPTriggerAuth{Type: fv1.HTTPTriggerAuthJWT, JWT: &fv1.HTTPTriggerJWTAuth{Issuer: "accounts.example.com"}},
+		}, fv1.HTTPTriggerReasonInvalidAuthConfig},
+		{"claim-keyed rate limit without jwt auth", fv1.HTTPTriggerSpec{
+			RateLimit: &fv1.HTTPTriggerRateLimit{Requests: 10, Key: fv1.HTTPTriggerRateLimitKey{Source: fv1.RateLimitKeyClaim, Name: "sub"}},
+		}, fv1.HTTPTriggerReasonInvalidRateLimit},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+
+	edrain "github.com/fission/fission/pkg/executor/drain"
+	"github.com/fission/fission/pkg/utils/httpmux"
+)
+
+// drainMaxBodyBytes bounds a drain request; the body is four short fields.
+const drainMaxBodyBytes = 4 << 10
+
+// registerDrainRoutes adds the pod drain endpoint to the INTERNAL mux; see
+// registerAsyncDLQRoutes. The executor polls it on every replica (through the
+// router-peers headless Service) before deleting a function pod.
+func (ts *HTTPTriggerSet) registerDrainRoutes(internal *httpmux.Mux) {
+	internal.HandleFunc(edrain.Path, ts.drainEndpoint).Methods(http.MethodPost)
+}
+
+// drainEndpoint marks a pod draining in the endpoint index, so this replica
+// stops admitting requests to it, and reports how many of the replica's
+// requests are still in flight on it. With the slice-fed data plane off there
+// is no per-pod accounting, so it reports the function's in-flight count
+// from the Tapper instead: every request could be on the pod, so the
+// executor waits for all of them (or its bound).
+func (ts *HTTPTriggerSet) drainEndpoint(w http.ResponseWriter, r *http.Request) {
+	var req edrain.Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, drainMaxBodyBytes)).Decode(&req); err != nil {
+		http.Error(w, "invalid drain request", http.StatusBadRequest)
+		return
+	}
+	if req.Namespace == "" || req.Function == "" || req.PodUID == "" {
+		http.Error(w, "namespace, function and podUID are required", http.StatusBadRequest)
+		return
+	}
+	var resp edrain.Response
+	switch {
+	case ts.endpointIndex != nil:
+		resp.InFlight = ts.endpointIndex.Drain(req.Namespace, req.Function, req.Version, req.PodUID)
+	case ts.tapper != nil && req.FunctionUID != "":
+		resp.InFlight = ts.tapper.InFlight(req.FunctionUID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		ts.logger.Error(err, "encoding drain response")
+	}
+}
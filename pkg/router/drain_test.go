@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	edrain "github.com/fission/fission/pkg/executor/drain"
+	"github.com/fission/fission/pkg/router/endpointcache"
+	"github.com/fission/fission/pkg/utils/httpmux"
+)
+
+func TestDrainEndpoint(t *testing.T) {
+	t.Parallel()
+	port, ready := int32(8888), true
+	index := endpointcache.NewIndex()
+	index.ApplySlice(&discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn-a-abcde", Namespace: "default", Labels: map[string]string{
+			fv1.FUNCTION_NAME:      "fn-a",
+			fv1.FUNCTION_NAMESPACE: "default",
+		}},
+		Ports: []discoveryv1.EndpointPort{{Port: &port}},
+		Endpoints: []discoveryv1.Endpoint{{
+			Addresses:  []string{"10.0.0.1"},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			TargetRef:  &apiv1.ObjectReference{Kind: "Pod", UID: "pod-1"},
+		}},
+	})
+	_, release, result := index.Admit("default", "fn-a", "", 1, "")
+	require.Equal(t, endpointcache.Admitted, result)
+
+	drainOnce := func(t *testing.T, ts *HTTPTriggerSet, body string) (int, edrain.Response) {
+		t.Helper()
+		mux := httpmux.New()
+		ts.registerDrainRoutes(mux)
+		w := httptest.NewRecorder()
+		mux.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, edrain.Path, strings.NewReader(body)))
+		var resp edrain.Response
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp
+	}
+
+	ts := &HTTPTriggerSet{logger: logr.Discard(), endpointIndex: index}
+	req := `{"namespace":"default","function":"fn-a","podUID":"pod-1"}`
+	code, resp := drainOnce(t, ts, req)
+	require.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 1, resp.InFlight)
+
+	release()
+	_, resp = drainOnce(t, ts, req)
+	assert.Zero(t, resp.InFlight)
+	_, _, result = index.Admit("default", "fn-a", "", 1, "")
+	assert.Equal(t, endpointcache.NoCountedReady, result, "a drained pod must not be admitted")
+
+	code, _ = drainOnce(t, ts, `{"namespace":"default","function":"fn-a"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Without an index the router cannot count per pod: it reports every
+	// request it has in flight to the function.
+	tapper := &executorTapper{logger: logr.Discard()}
+	fn := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "fn-a", Namespace: "default", UID: "fn-uid"}}
+	end := tapper.Begin(fn)
+	noIndex := &HTTPTriggerSet{logger: logr.Discard(), tapper: tapper}
+	code, resp = drainOnce(t, noIndex, `{"namespace":"default","function":"fn-a","functionUID":"fn-uid","podUID":"pod-1"}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.EqualValues(t, 1, resp.InFlight)
+	end()
+	_, resp = drainOnce(t, noIndex, `{"namespace":"default","function":"fn-a","functionUID":"fn-uid","podUID":"pod-1"}`)
+	assert.Zero(t, resp.InFlight)
+}
@@ -0,0 +1,283 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpointcache
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// Circuit breaking (FunctionSpec.Invocation.CircuitBreaker). Quarantine covers
+// endpoints the router cannot reach; the breaker covers endpoints it reaches
+// that keep failing — a pod whose function is wedged, out of memory, or whose
+// dependency is down for it alone. The transport reports the outcome of every
+// index-admitted request of an opted-in function (ReportOutcome); a breaker
+// that trips ejects the endpoint from Admit for EjectionSeconds, after which
+// it is half-open and admits at most HalfOpenRequests requests at a time until
+// that many consecutive probes succeed.
+//
+// Unlike quarantines, ejections survive slice events: a pod that answers 5xx
+// is still Ready as far as the kubelet is concerned, so the next slice update
+// proves nothing about it. An ejection ends on its own (half-open probing) or
+// when the endpoint leaves the function's slices (rebuildLocked prunes it).
+
+// BreakerTransition names the breaker state change ReportOutcome caused.
+type BreakerTransition string
+
+const (
+	// BreakerUnchanged is every outcome that moved no breaker.
+	BreakerUnchanged BreakerTransition = ""
+	// BreakerOpened ejected a closed endpoint.
+	BreakerOpened BreakerTransition = "opened"
+	// BreakerReopened ejected a half-open endpoint again after a failed probe.
+	BreakerReopened BreakerTransition = "reopened"
+	// BreakerClosed returned a half-open endpoint to full rotation.
+	BreakerClosed BreakerTransition = "closed"
+	// BreakerCapped is a trip MaxEjectionPercent refused: the endpoint stays
+	// in rotation and its counters start over.
+	BreakerCapped BreakerTransition = "capped"
+)
+
+// breaker is one endpoint's closed-state bookkeeping. Only touched on outcome
+// reports (never by Admit), so it lives in a plain mu-guarded map like strikes.
+type breaker struct {
+	consecutive int32
+	windowStart time.Time
+	requests    int32
+	failures    int32
+	// probeSuccesses counts consecutive successful half-open probes.
+	probeSuccesses int32
+}
+
+// breakerGate is one ejected endpoint as Admit sees it: skipped until until,
+// then admitted up to probes requests at a time (half-open).
+type breakerGate struct {
+	until  time.Time
+	probes int64
+}
+
+// BreakerState is one ejected endpoint, for status reporting.
+type BreakerState struct {
+	Address string
+	// HalfOpen reports whether the ejection has lapsed and the endpoint is
+	// taking probe requests.
+	HalfOpen bool
+	// Until is when the ejection lapses (or lapsed, when HalfOpen).
+	Until time.Time
+}
+
+// ReportOutcome records the outcome of one request dialed to address from the
+// function's index entry, under the function's effective breaker config (see
+// fv1.CircuitBreakerConfig.Effective), and returns the transition it caused.
+// Outcomes for addresses the entry no longer holds are dropped: their pod is
+// gone and a breaker for it would only leak.
+//
+// version selects the warm pool, mirroring Admit (see its doc comment).
+func (ix *Index) ReportOutcome(namespace, name, version, address string, cfg fv1.CircuitBreakerConfig, failed bool) BreakerTransition {
+	key := FnKey{Namespace: namespace, Name: name, Version: version}
+	s := ix.shard(key)
+	s.mu.RLock()
+	e, ok := s.m[key]
+	s.mu.RUnlock()
+	if !ok {
+		return BreakerUnchanged
+	}
+	now := ix.clock()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	b, ok := e.breakers[address]
+	if !ok {
+		if !e.holdsLocked(address) {
+			return BreakerUnchanged
+		}
+		if e.breakers == nil {
+			e.breakers = make(map[string]*breaker)
+		}
+		b = &breaker{windowStart: now}
+		e.breakers[address] = b
+	}
+
+	if gates := e.gates.Load(); gates != nil {
+		if gate, ejected := (*gates)[address]; ejected {
+			if now.Before(gate.until) {
+				// Open: these are requests admitted before the ejection
+				// finishing late; they say nothing new about the endpoint.
+				return BreakerUnchanged
+			}
+			if failed {
+				b.probeSuccesses = 0
+				e.ejectLocked(address, now, cfg)
+				return BreakerReopened
+			}
+			b.probeSuccesses++
+			if b.probeSuccesses < cfg.HalfOpenRequests {
+				return BreakerUnchanged
+			}
+			*b = breaker{windowStart: now}
+			e.setGateLocked(address, nil)
+			return BreakerClosed
+		}
+	}
+
+	if now.Sub(b.windowStart) >= time.Duration(cfg.IntervalSeconds)*time.Second {
+		b.windowStart, b.requests, b.failures = now, 0, 0
+	}
+	b.requests++
+	if !failed {
+		b.consecutive = 0
+		return BreakerUnchanged
+	}
+	b.failures++
+	b.consecutive++
+	trip := b.consecutive >= cfg.ConsecutiveFailures ||
+		(cfg.ErrorRatePercent > 0 && b.requests >= cfg.MinRequests &&
+			int64(b.failures)*100 >= int64(cfg.ErrorRatePercent)*int64(b.requests))
+	if !trip {
+		return BreakerUnchanged
+	}
+	// Start over either way: an opened breaker is judged afresh once it
+	// closes, and a capped one must not re-trip (and re-count as capped) on
+	// every following failure.
+	*b = breaker{windowStart: now}
+	if !e.ejectionAllowedLocked(address, cfg) {
+		return BreakerCapped
+	}
+	e.ejectLocked(address, now, cfg)
+	return BreakerOpened
+}
+
+// holdsLocked reports whether address is one of the entry's endpoints. Caller
+// holds e.mu.
+func (e *fnEntry) holdsLocked(address string) bool {
+	if eps := e.eps.Load(); eps != nil {
+		for i := range *eps {
+			if (*eps)[i].Address == address {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ejectionAllowedLocked applies MaxEjectionPercent: ejecting address must keep
+// the ejected share of the counted-ready endpoints (those Admit considers) at
+// or under the cap. Half-open endpoints still count as ejected: they take a
+// trickle of probes, not their share of the load. Caller holds e.mu.
+func (e *fnEntry) ejectionAllowedLocked(address string, cfg fv1.CircuitBreakerConfig) bool {
+	gates := e.gates.Load()
+	ready, ejected := 0, 1
+	if eps := e.eps.Load(); eps != nil {
+		for i := range *eps {
+			ep := &(*eps)[i]
+			if !ep.Ready || ep.inflight == nil {
+				continue
+			}
+			ready++
+			if gates == nil || ep.Address == address {
+				continue
+			}
+			if _, ok := (*gates)[ep.Address]; ok {
+				ejected++
+			}
+		}
+	}
+	return ejected*100 <= int(cfg.MaxEjectionPercent)*ready
+}
+
+// ejectLocked gates address for EjectionSeconds. Caller holds e.mu.
+func (e *fnEntry) ejectLocked(address string, now time.Time, cfg fv1.CircuitBreakerConfig) {
+	e.setGateLocked(address, &breakerGate{
+		until:  now.Add(time.Duration(cfg.EjectionSeconds) * time.Second),
+		probes: int64(max(cfg.HalfOpenRequests, 1)),
+	})
+}
+
+// setGateLocked writes (or, with a nil gate, removes) address in the
+// copy-on-write gate map Admit reads lock-free. Caller holds e.mu.
+func (e *fnEntry) setGateLocked(address string, gate *breakerGate) {
+	cur := e.gates.Load()
+	next := make(map[string]breakerGate)
+	if cur != nil {
+		for a, g := range *cur {
+			next[a] = g
+		}
+	}
+	if gate != nil {
+		next[address] = *gate
+	} else {
+		delete(next, address)
+	}
+	if len(next) == 0 {
+		e.gates.Store(nil)
+		return
+	}
+	e.gates.Store(&next)
+}
+
+// pruneBreakersLocked forgets the breakers and gates of addresses that left
+// the entry's slices. Caller holds e.mu.
+func (e *fnEntry) pruneBreakersLocked(live map[string]struct{}) {
+	for a := range e.breakers {
+		if _, ok := live[a]; !ok {
+			delete(e.breakers, a)
+		}
+	}
+	cur := e.gates.Load()
+	if cur == nil {
+		return
+	}
+	for a := range *cur {
+		if _, ok := live[a]; !ok {
+			e.setGateLocked(a, nil)
+		}
+	}
+}
+
+// Breakers returns the function's ejected (open or half-open) endpoints,
+// sorted by address; nil when none is.
+//
+// version selects the warm pool, mirroring Lookup (see its doc comment).
+func (ix *Index) Breakers(namespace, name, version string) []BreakerState {
+	key := FnKey{Namespace: namespace, Name: name, Version: version}
+	s := ix.shard(key)
+	s.mu.RLock()
+	e, ok := s.m[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	gates := e.gates.Load()
+	if gates == nil {
+		return nil
+	}
+	now := ix.clock()
+	states := make([]BreakerState, 0, len(*gates))
+	for a, g := range *gates {
+		states = append(states, BreakerState{Address: a, HalfOpen: !now.Before(g.until), Until: g.until})
+	}
+	slices.SortFunc(states, func(a, b BreakerState) int { return strings.Compare(a.Address, b.Address) })
+	return states
+}
+
+// Ejected returns the number of ejected (open or half-open) endpoints across
+// the index, for the ejected-endpoints gauge.
+func (ix *Index) Ejected() int {
+	n := 0
+	for i := range ix.shards {
+		s := &ix.shards[i]
+		s.mu.RLock()
+		for _, e := range s.m {
+			if gates := e.gates.Load(); gates != nil {
+				n += len(*gates)
+			}
+		}
+		s.mu.RUnlock()
+	}
+	return n
+}
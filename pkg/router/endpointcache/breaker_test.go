@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package endpointcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// breakerIndex is an index holding fn-a with addrs on port 8888, driven by a
+// settable clock.
+func breakerIndex(t *testing.T, addrs ...string) (*Index, *time.Time) {
+	t.Helper()
+	ix := NewIndex()
+	now := time.Unix(1_700_000_000, 0)
+	ix.now = func() time.Time { return now }
+	ix.ApplySlice(slice("s1", "fn-a", "default", 8888, addrs...))
+	return ix, &now
+}
+
+func breakerConfig(c fv1.CircuitBreakerConfig) fv1.CircuitBreakerConfig {
+	return c.Effective()
+}
+
+// admitAll admits n concurrent requests (so least-outstanding spreads them)
+// and returns the addresses they went to, releasing them all at the end.
+func admitAll(ix *Index, n int) []string {
+	var got []string
+	var releases []func()
+	for range n {
+		ep, release, res := ix.Admit("default", "fn-a", "", 10, "")
+		if res != Admitted {
+			continue
+		}
+		got = append(got, ep.Address)
+		releases = append(releases, release)
+	}
+	for _, release := range releases {
+		release()
+	}
+	return got
+}
+
+func TestBreakerConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+	ix, now := breakerIndex(t, "10.0.0.1", "10.0.0.2")
+	cfg := breakerConfig(fv1.CircuitBreakerConfig{ConsecutiveFailures: 3, EjectionSeconds: 10})
+	bad := "10.0.0.1:8888"
+
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", bad, cfg, true))
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", bad, cfg, true))
+	// A success in between resets the run.
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", bad, cfg, false))
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", bad, cfg, true))
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", bad, cfg, true))
+	require.Equal(t, BreakerOpened, ix.ReportOutcome("default", "fn-a", "", bad, cfg, true))
+
+	assert.NotContains(t, admitAll(ix, 10), bad, "an open endpoint is ejected")
+	states := ix.Breakers("default", "fn-a", "")
+	require.Len(t, states, 1)
+	assert.Equal(t, bad, states[0].Address)
+	assert.False(t, states[0].HalfOpen)
+	assert.Equal(t, 1, ix.Ejected())
+
+	// Late results from requests admitted before the ejection change nothing.
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", bad, cfg, true))
+
+	*now = now.Add(10 * time.Second)
+	assert.Contains(t, admitAll(ix, 10), bad, "a half-open endpoint takes probes")
+	assert.True(t, ix.Breakers("default", "fn-a", "")[0].HalfOpen)
+
+	require.Equal(t, BreakerClosed, ix.ReportOutcome("default", "fn-a", "", bad, cfg, false))
+	assert.Empty(t, ix.Breakers("default", "fn-a", ""))
+	assert.Equal(t, 0, ix.Ejected())
+}
+
+func TestBreakerHalfOpenFailureReopens(t *testing.T) {
+	t.Parallel()
+	ix, now := breakerIndex(t, "10.0.0.1", "10.0.0.2")
+	cfg := breakerConfig(fv1.CircuitBreakerConfig{ConsecutiveFailures: 1, EjectionSeconds: 10, HalfOpenRequests: 2})
+	bad := "10.0.0.1:8888"
+
+	require.Equal(t, BreakerOpened, ix.ReportOutcome("default", "fn-a", "", bad, cfg, true))
+	*now = now.Add(10 * time.Second)
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", bad, cfg, false), "one of two probes")
+	require.Equal(t, BreakerReopened, ix.ReportOutcome("default", "fn-a", "", bad, cfg, true))
+	assert.NotContains(t, admitAll(ix, 10), bad)
+
+	*now = now.Add(10 * time.Second)
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", bad, cfg, false), "the probe count restarted")
+	assert.Equal(t, BreakerClosed, ix.ReportOutcome("default", "fn-a", "", bad, cfg, false))
+}
+
+func TestBreakerHalfOpenCapsConcurrency(t *testing.T) {
+	t.Parallel()
+	ix, now := breakerIndex(t, "10.0.0.1")
+	cfg := breakerConfig(fv1.CircuitBreakerConfig{ConsecutiveFailures: 1, EjectionSeconds: 10, MaxEjectionPercent: 100})
+
+	require.Equal(t, BreakerOpened, ix.ReportOutcome("default", "fn-a", "", "10.0.0.1:8888", cfg, true))
+	_, _, res := ix.Admit("default", "fn-a", "", 10, "")
+	assert.Equal(t, AllEjected, res)
+
+	*now = now.Add(10 * time.Second)
+	_, release, res := ix.Admit("default", "fn-a", "", 10, "")
+	require.Equal(t, Admitted, res)
+	_, _, res = ix.Admit("default", "fn-a", "", 10, "")
+	assert.Equal(t, AllBusy, res, "one probe at a time")
+	release()
+}
+
+func TestBreakerErrorRate(t *testing.T) {
+	t.Parallel()
+	ix, now := breakerIndex(t, "10.0.0.1", "10.0.0.2")
+	cfg := breakerConfig(fv1.CircuitBreakerConfig{ConsecutiveFailures: 100, ErrorRatePercent: 50, MinRequests: 4, IntervalSeconds: 10})
+	addr := "10.0.0.1:8888"
+	report := func(failed bool) BreakerTransition {
+		return ix.ReportOutcome("default", "fn-a", "", addr, cfg, failed)
+	}
+
+	// 2 of 3 failed, but below MinRequests.
+	report(true)
+	report(false)
+	assert.Equal(t, BreakerUnchanged, report(true))
+
+	// A new window starts the count over.
+	*now = now.Add(10 * time.Second)
+	report(false)
+	report(false)
+	report(true)
+	assert.Equal(t, BreakerUnchanged, report(false), "1 of 4")
+	assert.Equal(t, BreakerUnchanged, report(true), "2 of 5")
+	assert.Equal(t, BreakerOpened, report(true), "3 of 6 reaches 50%")
+}
+
+func TestBreakerMaxEjectionPercent(t *testing.T) {
+	t.Parallel()
+	ix, _ := breakerIndex(t, "10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4")
+	cfg := breakerConfig(fv1.CircuitBreakerConfig{ConsecutiveFailures: 1, MaxEjectionPercent: 50})
+
+	assert.Equal(t, BreakerOpened, ix.ReportOutcome("default", "fn-a", "", "10.0.0.1:8888", cfg, true))
+	assert.Equal(t, BreakerOpened, ix.ReportOutcome("default", "fn-a", "", "10.0.0.2:8888", cfg, true))
+	assert.Equal(t, BreakerCapped, ix.ReportOutcome("default", "fn-a", "", "10.0.0.3:8888", cfg, true), "a third would eject 75%")
+	assert.ElementsMatch(t, []string{"10.0.0.3:8888", "10.0.0.4:8888"}, uniq(admitAll(ix, 10)))
+}
+
+func TestBreakerSurvivesSliceEventsUntilEndpointLeaves(t *testing.T) {
+	t.Parallel()
+	ix, _ := breakerIndex(t, "10.0.0.1", "10.0.0.2")
+	cfg := breakerConfig(fv1.CircuitBreakerConfig{ConsecutiveFailures: 1})
+
+	require.Equal(t, BreakerOpened, ix.ReportOutcome("default", "fn-a", "", "10.0.0.1:8888", cfg, true))
+	ix.ApplySlice(slice("s1", "fn-a", "default", 8888, "10.0.0.1", "10.0.0.2", "10.0.0.3"))
+	assert.Len(t, ix.Breakers("default", "fn-a", ""), 1, "a scale-up does not vouch for a failing pod")
+
+	ix.ApplySlice(slice("s1", "fn-a", "default", 8888, "10.0.0.2", "10.0.0.3"))
+	assert.Empty(t, ix.Breakers("default", "fn-a", ""), "the pod left: its breaker goes with it")
+
+	assert.Equal(t, BreakerUnchanged, ix.ReportOutcome("default", "fn-a", "", "10.0.0.1:8888", cfg, true),
+		"outcomes for departed endpoints are dropped")
+	assert.Empty(t, ix.Breakers("default", "fn-a", ""))
+}
+
+func uniq(in []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -11,7 +11,9 @@ package endpointcache
 
 import (
 	"fmt"
+	"maps"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -92,6 +94,8 @@ type (
 		size atomic.Int64
 		// quarantineTTL is DefaultQuarantineTTL unless narrowed in tests.
 		quarantineTTL time.Duration
+		// now is the circuit breakers' clock; time.Now unless faked in tests.
+		now func() time.Time
 	}
 
 	indexShard struct {
@@ -107,8 +111,15 @@ type (
 		// a lister query.
 		slices map[string][]Endpoint
 		// counters holds the per-pod in-flight counters, keyed by pod UID so
-		// they survive slice-event rebuilds; pruned with the endpoints.
+		// they survive slice-event rebuilds; pruned once the pod has left
+		// every slice and its last admitted request has been released, so
+		// Drain can still report on a pod the executor already unlabeled.
 		counters map[types.UID]*atomic.Int64
+		// draining holds the pods the executor is draining ahead of deletion
+		// (see Drain); skipped by Admit so no new requests land on them.
+		// Copy-on-write like quarantined; writes happen under mu, and marks
+		// are pruned along with the pod's counter.
+		draining atomic.Pointer[map[types.UID]struct{}]
 		// quarantined maps addresses the transport reported dial failures for
 		// to their quarantine expiry; skipped by Admit until the next slice
 		// event for this function clears them (the slice controller removes
@@ -122,6 +133,14 @@ type (
 		// reports (never by Admit), so a plain mu-guarded map suffices;
 		// cleared alongside quarantined on slice events.
 		strikes map[string]dialStrike
+		// breakers holds the circuit breaker bookkeeping per address of a
+		// function that opted in (see breaker.go); mu-guarded, pruned with
+		// the endpoints.
+		breakers map[string]*breaker
+		// gates maps the addresses whose breaker is open or half-open to
+		// their ejection; read by Admit lock-free like quarantined, but NOT
+		// cleared by slice events (see breaker.go).
+		gates atomic.Pointer[map[string]breakerGate]
 		// eps is the merged endpoint list, swapped copy-on-write. Hot-path
 		// readers load it without taking mu.
 		eps atomic.Pointer[[]Endpoint]
@@ -130,13 +149,20 @@ type (
 
 // NewIndex returns an empty endpoint index.
 func NewIndex() *Index {
-	ix := &Index{quarantineTTL: DefaultQuarantineTTL}
+	ix := &Index{quarantineTTL: DefaultQuarantineTTL, now: time.Now}
 	for i := range ix.shards {
 		ix.shards[i].m = make(map[FnKey]*fnEntry)
 	}
 	return ix
 }
 
+func (ix *Index) clock() time.Time {
+	if ix.now != nil {
+		return ix.now()
+	}
+	return time.Now()
+}
+
 func (ix *Index) shard(key FnKey) *indexShard {
 	// Inline FNV-1a over the key strings: hash.Hash32 via fnv.New32a would
 	// heap-allocate on every call of this per-request path.
@@ -385,7 +411,8 @@ func (ix *Index) RemoveNamespace(ns string) {
 
 // rebuildLocked re-merges the per-slice endpoint lists into the copy-on-write
 // snapshot, attaching each pod's shared in-flight counter (created on first
-// sight, pruned when the pod leaves every slice). Caller holds e.mu.
+// sight, pruned when the pod leaves every slice with nothing in flight) and
+// pruning the breakers of departed addresses. Caller holds e.mu.
 func (e *fnEntry) rebuildLocked() {
 	n := 0
 	for _, eps := range e.slices {
@@ -393,8 +420,10 @@ func (e *fnEntry) rebuildLocked() {
 	}
 	merged := make([]Endpoint, 0, n)
 	live := make(map[types.UID]struct{}, n)
+	addresses := make(map[string]struct{}, n)
 	for _, eps := range e.slices {
 		for _, ep := range eps {
+			addresses[ep.Address] = struct{}{}
 			if e.counters != nil && ep.PodUID != "" {
 				c, ok := e.counters[ep.PodUID]
 				if !ok {
@@ -407,14 +436,100 @@ func (e *fnEntry) rebuildLocked() {
 			merged = append(merged, ep)
 		}
 	}
-	for uid := range e.counters {
-		if _, ok := live[uid]; !ok {
+	for uid, c := range e.counters {
+		if _, ok := live[uid]; !ok && c.Load() <= 0 {
 			delete(e.counters, uid)
 		}
 	}
+	e.pruneDrainingLocked()
+	e.pruneBreakersLocked(addresses)
 	e.eps.Store(&merged)
 }
 
+// pruneDrainingLocked drops the draining marks of pods whose counter is gone.
+// Caller holds e.mu.
+func (e *fnEntry) pruneDrainingLocked() {
+	cur := e.draining.Load()
+	if cur == nil {
+		return
+	}
+	next := make(map[types.UID]struct{}, len(*cur))
+	for uid := range *cur {
+		if _, ok := e.counters[uid]; ok {
+			next[uid] = struct{}{}
+		}
+	}
+	if len(next) == len(*cur) {
+		return
+	}
+	if len(next) == 0 {
+		e.draining.Store(nil)
+		return
+	}
+	e.draining.Store(&next)
+}
+
+// Drain marks a pod as draining, so Admit stops picking it, and returns the
+// number of requests this replica still has in flight on it. The executor
+// calls it (through the router's internal drain endpoint) repeatedly before
+// deleting an idle or outdated pod, and deletes once every replica reports
+// zero. Marking is idempotent; a pod the index does not know about — never
+// admitted through it, or already gone with nothing in flight — reports 0.
+//
+// version selects the warm pool, mirroring Admit (see its doc comment).
+func (ix *Index) Drain(namespace, name, version string, podUID types.UID) int64 {
+	if podUID == "" {
+		return 0
+	}
+	key := FnKey{Namespace: namespace, Name: name, Version: version}
+	s := ix.shard(key)
+	s.mu.RLock()
+	e, ok := s.m[key]
+	s.mu.RUnlock()
+	if !ok {
+		return 0
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.counters[podUID]
+	if !ok {
+		return 0
+	}
+	n := c.Load()
+	if n <= 0 && !e.liveLocked(podUID) {
+		// The pod left every slice and its last request has been released:
+		// nothing will look it up again, so forget it now rather than at
+		// the next slice event.
+		delete(e.counters, podUID)
+		e.pruneDrainingLocked()
+		return 0
+	}
+	if cur := e.draining.Load(); cur == nil || !containsUID(*cur, podUID) {
+		next := make(map[types.UID]struct{})
+		if cur != nil {
+			maps.Copy(next, *cur)
+		}
+		next[podUID] = struct{}{}
+		e.draining.Store(&next)
+	}
+	return max(n, 0)
+}
+
+// liveLocked reports whether the pod is in the current endpoint snapshot.
+// Caller holds e.mu.
+func (e *fnEntry) liveLocked(podUID types.UID) bool {
+	eps := e.eps.Load()
+	if eps == nil {
+		return false
+	}
+	return slices.ContainsFunc(*eps, func(ep Endpoint) bool { return ep.PodUID == podUID })
+}
+
+func containsUID(m map[types.UID]struct{}, uid types.UID) bool {
+	_, ok := m[uid]
+	return ok
+}
+
 // AdmitResult explains an Admit outcome, so the resolver can label its
 // fallback metric and logs with the real refusal reason instead of a generic
 // "saturated".
@@ -425,6 +540,7 @@ const (
 	NoEntry         AdmitResult = "no_entry"
 	AllBusy         AdmitResult = "all_busy"
 	AllQuarantined  AdmitResult = "all_quarantined"
+	AllEjected      AdmitResult = "all_ejected"
 	NoCountedReady  AdmitResult = "no_counted_ready"
 	AdmitContention AdmitResult = "cas_contention"
 )
@@ -461,9 +577,10 @@ func hrwScore(key, address string) uint64 {
 	return h
 }
 
-// Admit picks a ready, non-quarantined endpoint with free capacity (below
-// requestsPerPod), increments its in-flight counter, and returns it with a
-// release func that the caller MUST invoke when the request completes
+// Admit picks a ready, non-draining, non-quarantined, non-ejected endpoint
+// with free capacity (below requestsPerPod, or the probe allowance of a
+// half-open circuit breaker), increments its in-flight counter, and returns
+// it with a release func that the caller MUST invoke when the request completes
 // (response done / stream drained). A non-Admitted result names why no
 // endpoint was admissible.
 //
@@ -504,9 +621,11 @@ func (ix *Index) Admit(namespace, name, version string, requestsPerPod int, stic
 	}
 
 	quarantined := e.quarantined.Load()
+	gates := e.gates.Load()
+	draining := e.draining.Load()
 	var now time.Time
-	if quarantined != nil {
-		now = time.Now()
+	if quarantined != nil || gates != nil {
+		now = ix.clock()
 	}
 
 	// Endpoint selection with a bounded CAS retry: the snapshot is
@@ -516,12 +635,15 @@ func (ix *Index) Admit(namespace, name, version string, requestsPerPod int, stic
 		var best *Endpoint
 		var bestLoad int64
 		var bestScore uint64
-		counted, quarantinedN, busy := 0, 0, 0
+		counted, quarantinedN, ejectedN, busy := 0, 0, 0, 0
 		for i := range *epsp {
 			ep := &(*epsp)[i]
 			if !ep.Ready || ep.inflight == nil {
 				continue
 			}
+			if draining != nil && containsUID(*draining, ep.PodUID) {
+				continue
+			}
 			counted++
 			if quarantined != nil {
 				if expiry, bad := (*quarantined)[ep.Address]; bad && now.Before(expiry) {
@@ -529,8 +651,18 @@ func (ix *Index) Admit(namespace, name, version string, requestsPerPod int, stic
 					continue
 				}
 			}
+			limit := int64(requestsPerPod)
+			if gates != nil {
+				if gate, ejected := (*gates)[ep.Address]; ejected {
+					if now.Before(gate.until) {
+						ejectedN++
+						continue
+					}
+					limit = min(limit, gate.probes)
+				}
+			}
 			load := ep.inflight.Load()
-			if load >= int64(requestsPerPod) {
+			if load >= limit {
 				busy++
 				continue
 			}
@@ -550,6 +682,8 @@ func (ix *Index) Admit(namespace, name, version string, requestsPerPod int, stic
 				return Endpoint{}, nil, NoCountedReady
 			case quarantinedN == counted:
 				return Endpoint{}, nil, AllQuarantined
+			case quarantinedN+ejectedN == counted:
+				return Endpoint{}, nil, AllEjected
 			default:
 				return Endpoint{}, nil, AllBusy
 			}
@@ -534,3 +534,55 @@ func TestRemoveNamespace(t *testing.T) {
 		assert.Equal(t, 0, ix.Size())
 	})
 }
+
+func TestDrain(t *testing.T) {
+	t.Parallel()
+	ix := NewIndex()
+	ix.ApplySlice(slice("s1", "fn-a", "default", 8888, "10.0.0.1", "10.0.0.2"))
+	uid := types.UID("pod-10.0.0.1-0")
+
+	assert.Zero(t, ix.Drain("default", "ghost", "", uid), "an unknown function has nothing in flight")
+	assert.Zero(t, ix.Drain("default", "fn-a", "", "pod-unknown"), "an unknown pod has nothing in flight")
+
+	// Fill both pods, then drain the first: it reports its request and is no
+	// longer admissible once released.
+	var releases []func()
+	for range 2 {
+		_, release, result := ix.Admit("default", "fn-a", "", 1, "")
+		require.Equal(t, Admitted, result)
+		releases = append(releases, release)
+	}
+	assert.EqualValues(t, 1, ix.Drain("default", "fn-a", "", uid))
+	for _, release := range releases {
+		release()
+	}
+	assert.Zero(t, ix.Drain("default", "fn-a", "", uid))
+	for range 3 {
+		ep, release, result := ix.Admit("default", "fn-a", "", 1, "")
+		require.Equal(t, Admitted, result)
+		assert.Equal(t, "10.0.0.2:8888", ep.Address, "a draining pod must not be admitted")
+		release()
+	}
+
+	t.Run("counter outlives the slice while requests are in flight", func(t *testing.T) {
+		t.Parallel()
+		ix := NewIndex()
+		ix.ApplySlice(slice("s1", "fn-b", "default", 8888, "10.0.0.1"))
+		_, release, result := ix.Admit("default", "fn-b", "", 1, "")
+		require.Equal(t, Admitted, result)
+
+		// The executor unlabels the pod, so it leaves the slice while its
+		// request is still running; Drain must keep reporting it.
+		ix.ApplySlice(slice("s1", "fn-b", "default", 8888))
+		assert.EqualValues(t, 1, ix.Drain("default", "fn-b", "", "pod-10.0.0.1-0"))
+		release()
+		assert.Zero(t, ix.Drain("default", "fn-b", "", "pod-10.0.0.1-0"))
+
+		// A pod that returns under the same UID starts admissible again: the
+		// departed counter and its draining mark were forgotten.
+		ix.ApplySlice(slice("s1", "fn-b", "default", 8888, "10.0.0.1"))
+		_, release, result = ix.Admit("default", "fn-b", "", 1, "")
+		require.Equal(t, Admitted, result)
+		release()
+	})
+}
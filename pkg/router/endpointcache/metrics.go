@@ -59,6 +59,13 @@ var (
 		"fission_router_endpointcache_dial_timeout_strikes_total",
 		"Soft dial failures (timeouts) recorded against endpoints; quarantine requires several within one TTL window.",
 	)
+	// breakerTransitions counts circuit breaker state changes per function
+	// (opened, reopened, closed, and trips refused by MaxEjectionPercent).
+	// Only functions that opted into circuit breaking produce series.
+	breakerTransitions = metrics.Int64Counter(
+		"fission_router_circuit_breaker_transitions_total",
+		"Circuit breaker state changes of function endpoints in the router's endpoint index, by function and transition.",
+	)
 	// fallbacks counts warm-path requests routed to the executor for a
 	// specific reason (strict-mode annotation, no endpoints, all endpoints
 	// saturated, or the executor not supporting ensureCapacity).
@@ -94,6 +101,15 @@ func RecordQuarantine() { quarantines.Add(context.Background(), 1) }
 // RecordDialTimeoutStrike counts one soft (timeout) dial-failure strike.
 func RecordDialTimeoutStrike() { dialTimeoutStrikes.Add(context.Background(), 1) }
 
+// RecordBreakerTransition counts one circuit breaker state change.
+func RecordBreakerTransition(namespace, name string, t BreakerTransition) {
+	breakerTransitions.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("namespace", namespace),
+		attribute.String("function", name),
+		attribute.String("transition", string(t)),
+	))
+}
+
 // RegisterModeInfo publishes the constant info gauge exposing the requested and
 // effective endpointslice cache modes. Recorded for EVERY mode (including off)
 // so a fail-soft degrade (e.g. missing RBAC flipping on->off at startup) is
@@ -113,11 +129,12 @@ var (
 	informersGaugeOnce sync.Once
 )
 
-// RegisterSizeGauge publishes an observable gauge reporting the number of
-// functions in the index. It is idempotent: the observable instrument is
+// RegisterSizeGauge publishes observable gauges reporting the number of
+// functions in the index and of endpoints ejected by circuit breakers. It is
+// idempotent: the observable instrument is
 // registered exactly once and always reports the most recently registered
 // Index, so a repeat call (e.g. an in-process router restart in tests)
-// re-points the gauge at the live Index instead of stacking a second callback
+// re-points the gauges at the live Index instead of stacking a second callback
 // on a now-dead one.
 func RegisterSizeGauge(ix *Index) {
 	sizeIndex.Store(ix)
@@ -132,6 +149,16 @@ func RegisterSizeGauge(ix *Index) {
 				return nil
 			},
 		)
+		metrics.Int64ObservableGauge(
+			"fission_router_circuit_breaker_ejected_endpoints",
+			"Endpoints currently ejected (open or half-open) by circuit breakers in the router's endpoint index.",
+			func(_ context.Context, o metric.Int64Observer) error {
+				if ix := sizeIndex.Load(); ix != nil {
+					o.Observe(int64(ix.Ejected()))
+				}
+				return nil
+			},
+		)
 	})
 }
 
@@ -24,6 +24,7 @@ import (
 	"github.com/fission/fission/pkg/error/network"
 	"github.com/fission/fission/pkg/router/asyncinvoke"
 	"github.com/fission/fission/pkg/router/streaming"
+	"github.com/fission/fission/pkg/router/transform"
 	"github.com/fission/fission/pkg/utils"
 	"github.com/fission/fission/pkg/utils/correlation"
 	otelUtils "github.com/fission/fission/pkg/utils/otel"
@@ -85,12 +86,25 @@ type functionHandler struct {
 	rtLogger    logr.Logger
 	policyByUID map[crd.CacheKeyUG]proxyPolicy
 	basesByUID  map[crd.CacheKeyUG][]string
+	// rateLimiter enforces the trigger's RateLimit (ratelimit.go). Unlike the
+	// state above it is owned by the HTTPTriggerSet and shared across handler
+	// rebuilds, so a function or canary change does not refill the buckets.
+	// nil for triggers without one and for internal routes.
+	rateLimiter *rateLimiter
 	// asyncInvoker enqueues RFC-0024 async invocations. Set on both the public
 	// HTTPTrigger handlers and the internal direct-function handlers, so a signed
 	// direct caller can go async; the dispatcher's own deliveries are excluded by
 	// the X-Fission-Invocation-Id guard in handler(), not by a nil invoker. May be
 	// nil (or hold a nil queue) when the feature is off.
 	asyncInvoker *asyncInvoker
+	// mirror copies a share of the route's synchronous requests to a shadow
+	// FunctionVersion (mirror.go). nil when the route has no resolved
+	// TrafficMirror, and always nil on the shadow's own handler.
+	mirror *trafficMirror
+	// cache serves and stores the trigger's responses (responsecache.go).
+	// Shared across handler rebuilds like rateLimiter; nil for triggers
+	// without a Cache and for internal routes.
+	cache *responseCache
 }
 
 // stickyMode names which of the two ways handler() derives its sticky key,
@@ -249,6 +263,29 @@ func (fh functionHandler) handler(responseWriter http.ResponseWriter, request *h
 	// system params
 	setFunctionMetadataToHeader(&fh.function.ObjectMeta, request)
 
+	// Rate limit before the request costs anything: an async enqueue is as
+	// much a load on the function as a proxied call.
+	if fh.rateLimiter != nil && !fh.rateLimiter.allow(responseWriter, request) {
+		return
+	}
+
+	// The trigger's transform runs before anything hands the request on, so
+	// the async queue, the cache and the mirror all see what the function
+	// will.
+	if t := fh.transform(); t != nil && t.Request != nil {
+		var ok bool
+		if request, ok = fh.transformRequest(responseWriter, request, t.Request); !ok {
+			return
+		}
+	}
+
+	// A job function runs each invocation as a Kubernetes Job and answers
+	// 202 with its invocation id, whatever the invocation mode.
+	if fh.function.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType == fv1.ExecutorTypeJob {
+		fh.asyncInvoker.startJob(responseWriter, request, fh.function, fh.httpTrigger == nil)
+		return
+	}
+
 	// RFC-0024: async invocation. handle() writes 501 when the feature is off (nil
 	// invoker/queue), answering an async-mode request honestly.
 	if fh.asyncRequested(request) {
@@ -256,6 +293,31 @@ func (fh functionHandler) handler(responseWriter http.ResponseWriter, request *h
 		return
 	}
 
+	// The cache answers after the rate limit (a hit is still a request the
+	// trigger admitted) and before the mirror (a hit never reaches the
+	// function, so there is nothing to compare).
+	if fh.cache != nil {
+		var finish func()
+		var served bool
+		responseWriter, finish, served = fh.cache.serve(responseWriter, request, fh.function, fh.handler)
+		if served {
+			return
+		}
+		if finish != nil {
+			defer finish()
+		}
+	}
+
+	// Mirror after the async and rate-limit decisions, so only requests the
+	// function actually serves synchronously are copied.
+	if fh.mirror != nil {
+		var finish func()
+		responseWriter, request, finish = fh.mirror.fork(responseWriter, request)
+		if finish != nil {
+			defer finish()
+		}
+	}
+
 	director := func(req *http.Request) {
 		if _, ok := req.Header["User-Agent"]; !ok {
 			// explicitly disable User-Agent so it's not set to default value
@@ -311,6 +373,9 @@ func (fh functionHandler) handler(responseWriter http.ResponseWriter, request *h
 			// fallback) or any other marker consumer. Strip it from every
 			// proxied response.
 			resp.Header.Del(utils.HeaderRouteMiss)
+			if t := fh.transform(); t != nil {
+				transform.Response(resp.Header, t.Response)
+			}
 			// One goroutine for metric collection + the cached-URL tap (the
 			// historical pairing — the tap is a buffered channel send and does
 			// not warrant a spawn of its own).
@@ -361,6 +426,9 @@ func (fh functionHandler) handler(responseWriter http.ResponseWriter, request *h
 	if otelUtils.SpanIsRecording(request.Context()) {
 		otelUtils.SpanTrackEvent(request.Context(), "functionRequestProxy", otelUtils.GetAttributesForFunction(fh.function)...)
 	}
+	if fh.tapper != nil {
+		defer fh.tapper.Begin(fh.function)()
+	}
 	proxy.ServeHTTP(responseWriter, request)
 }
 
@@ -478,8 +546,14 @@ func (fh functionHandler) getProxyErrorHandler(start time.Time, rrt *RetryingRou
 // ROUTER_STRUCTURED_ERRORS is off — the legacy plain-text body verbatim. The
 // raw error detail is included only when the caller opted in via X-Fission-Debug
 // AND the router runs in debug mode, so internal detail never leaks by default.
-// Issue #693 (a traceable id in the response) is resolved here.
+// Issue #693 (a traceable id in the response) is resolved here. A gRPC call
+// gets a trailers-only gRPC status instead (see writeGRPCError).
 func (fh functionHandler) writeInvocationError(rw http.ResponseWriter, req *http.Request, status int, component ferror.Component, reason, legacyMsg string, cause error) {
+	if isGRPCRequest(req) {
+		// A gRPC client reads the outcome from grpc-status, not the body.
+		writeGRPCError(rw, status, component, reason)
+		return
+	}
 	if !fh.structuredErrors {
 		rw.WriteHeader(status)
 		if _, werr := rw.Write([]byte(legacyMsg)); werr != nil {
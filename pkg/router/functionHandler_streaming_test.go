@@ -37,7 +37,7 @@ type fixedURLExecutor struct{ hostPort string }
 func (e *fixedURLExecutor) GetServiceForFunction(_ context.Context, _ *fv1.Function) (string, error) {
 	return e.hostPort, nil
 }
-func (e *fixedURLExecutor) TapService(metav1.ObjectMeta, fv1.ExecutorType, url.URL) {}
+func (e *fixedURLExecutor) TapService(metav1.ObjectMeta, fv1.ExecutorType, url.URL, int) {}
 func (e *fixedURLExecutor) UnTapService(context.Context, metav1.ObjectMeta, fv1.ExecutorType, *url.URL) error {
 	return nil
 }
@@ -77,12 +77,16 @@ type countingExecutor struct {
 	hostPort string
 	taps     atomic.Int64
 	untaps   atomic.Int64
+	inflight atomic.Int64 // last tap's in-flight count
 }
 
 func (e *countingExecutor) GetServiceForFunction(_ context.Context, _ *fv1.Function) (string, error) {
 	return e.hostPort, nil
 }
-func (e *countingExecutor) TapService(metav1.ObjectMeta, fv1.ExecutorType, url.URL) { e.taps.Add(1) }
+func (e *countingExecutor) TapService(_ metav1.ObjectMeta, _ fv1.ExecutorType, _ url.URL, inflight int) {
+	e.taps.Add(1)
+	e.inflight.Store(int64(inflight))
+}
 func (e *countingExecutor) UnTapService(context.Context, metav1.ObjectMeta, fv1.ExecutorType, *url.URL) error {
 	e.untaps.Add(1)
 	return nil
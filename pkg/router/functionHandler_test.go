@@ -173,7 +173,7 @@ func (r *recordingExecutor) GetServiceForFunction(_ context.Context, fn *fv1.Fun
 	r.gotFn = fn
 	return "10.0.0.1:8888", nil
 }
-func (r *recordingExecutor) TapService(metav1.ObjectMeta, fv1.ExecutorType, url.URL) {}
+func (r *recordingExecutor) TapService(metav1.ObjectMeta, fv1.ExecutorType, url.URL, int) {}
 func (r *recordingExecutor) UnTapService(context.Context, metav1.ObjectMeta, fv1.ExecutorType, *url.URL) error {
 	return nil
 }
@@ -94,6 +94,19 @@ type (
 		// recomputing the key from whichever backend the (unkeyed, random)
 		// pick lands on.
 		stickySource *fv1.Function
+		// mirror is the shadow backend requests are copied to
+		// (fv1.TrafficMirror), from the trigger's own Mirror or else the
+		// FunctionAlias it resolves through; nil when neither sets one, or
+		// when the mirror did not resolve (see resolveMirror).
+		mirror *resolvedMirror
+	}
+
+	// resolvedMirror is a TrafficMirror resolved to its versioned Function
+	// projection, keyed by BackendKey like the route's primary backends.
+	resolvedMirror struct {
+		key     string
+		fn      *fv1.Function
+		percent int32
 	}
 )
 
@@ -117,7 +130,16 @@ func makeFunctionReferenceResolver(logger logr.Logger, reader client.Reader) *fu
 func (frr *functionReferenceResolver) resolve(ctx context.Context, trigger fv1.HTTPTrigger) (*resolveResult, error) {
 	switch trigger.Spec.FunctionReference.Type {
 	case fv1.FunctionReferenceTypeFunctionName:
-		return frr.resolveByName(ctx, trigger.Namespace, trigger.Spec.FunctionReference)
+		rr, err := frr.resolveByName(ctx, trigger.Namespace, trigger.Spec.FunctionReference)
+		if err != nil {
+			return nil, err
+		}
+		// The trigger's own mirror wins over its alias's: the trigger is
+		// the more specific of the two.
+		if trigger.Spec.Mirror != nil {
+			rr.mirror = frr.resolveMirror(ctx, trigger.Namespace, trigger.Spec.FunctionReference.Name, trigger.Spec.Mirror, rr, nil)
+		}
+		return rr, nil
 	case fv1.FunctionReferenceTypeFunctionWeights:
 		return frr.resolveByFunctionWeights(ctx, trigger.Namespace, &trigger.Spec.FunctionReference)
 	default:
@@ -305,6 +327,7 @@ func (frr *functionReferenceResolver) resolveByAlias(ctx context.Context, namesp
 		rr := singleFunctionResult(primaryKey, primary)
 		rr.AliasGens = map[string]int64{ref.Alias: alias.Generation}
 		rr.stickySource = live
+		rr.mirror = frr.resolveMirror(ctx, namespace, ref.Name, alias.Spec.Mirror, rr, live)
 		return rr, nil
 	}
 
@@ -336,9 +359,46 @@ func (frr *functionReferenceResolver) resolveByAlias(ctx context.Context, namesp
 		AliasGens:    map[string]int64{ref.Alias: alias.Generation},
 		stickySource: live,
 	}
+	rr.mirror = frr.resolveMirror(ctx, namespace, ref.Name, alias.Spec.Mirror, &rr, live)
 	return &rr, nil
 }
 
+// resolveMirror resolves a TrafficMirror's version against function name the
+// way a Version pin resolves, reusing live when the caller already holds it.
+// A mirror never fails the route it rides on: an unresolvable one (a version
+// not yet published, or belonging to another function) is logged and
+// dropped, and the route serves unmirrored. A FunctionVersion create event
+// does not cascade to routes, so a mirror naming a version published later
+// is picked up by the periodic resync. A mirror naming a version the route
+// already serves is dropped too: it would double that version's load and
+// compare it with itself.
+func (frr *functionReferenceResolver) resolveMirror(ctx context.Context, namespace, name string, m *fv1.TrafficMirror, rr *resolveResult, live *fv1.Function) *resolvedMirror {
+	if m == nil {
+		return nil
+	}
+	key := routetable.BackendKey(name, m.Version)
+	if _, served := rr.functionMap[key]; served {
+		frr.logger.Info("traffic mirror targets a version the route already serves; not mirroring",
+			"namespace", namespace, "function", name, "version", m.Version)
+		return nil
+	}
+	var (
+		fn  *fv1.Function
+		err error
+	)
+	if live != nil {
+		fn, err = frr.resolveVersionWithLive(ctx, namespace, name, m.Version, live)
+	} else {
+		fn, err = frr.resolveVersion(ctx, namespace, name, m.Version)
+	}
+	if err != nil {
+		frr.logger.Info("traffic mirror did not resolve; not mirroring",
+			"namespace", namespace, "function", name, "version", m.Version, "error", err.Error())
+		return nil
+	}
+	return &resolvedMirror{key: key, fn: fn, percent: m.EffectivePercent()}
+}
+
 func (frr *functionReferenceResolver) resolveByFunctionWeights(ctx context.Context, namespace string, fr *fv1.FunctionReference) (*resolveResult, error) {
 	functionMap := make(map[string]*fv1.Function)
 	fnWtDistrList := make([]functionWeightDistribution, 0)
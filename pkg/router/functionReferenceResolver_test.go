@@ -440,3 +440,58 @@ func TestResolveByName_AliasAndVersionMutuallyExclusive(t *testing.T) {
 	key := routetable.BackendKey("hello", "hello-v1")
 	require.Contains(t, rr.functionMap, key)
 }
+
+// TestResolve_Mirror: a trigger's mirror resolves to the version projection
+// and wins over its alias's; an alias's mirror applies when the trigger sets
+// none; and a mirror that does not resolve is dropped without failing the
+// route.
+func TestResolve_Mirror(t *testing.T) {
+	fn := resolverFn("hello", "default", "fn-uid", 3, 60)
+	v1 := resolverVersion("hello-v1", "default", "hello", "fn-uid", 1, 1, 11)
+	v2 := resolverVersion("hello-v2", "default", "hello", "fn-uid", 2, 2, 22)
+	v3 := resolverVersion("hello-v3", "default", "hello", "fn-uid", 3, 3, 33)
+	alias := resolverAlias("prod", "default", "hello", func(a *fv1.FunctionAlias) {
+		a.Spec.Version = "hello-v1"
+		a.Spec.Mirror = &fv1.TrafficMirror{Version: "hello-v2", Percent: 10}
+	})
+	frr := newResolver(t, fn, v1, v2, v3, alias)
+
+	trigger := func(ref fv1.FunctionReference, m *fv1.TrafficMirror) fv1.HTTPTrigger {
+		return fv1.HTTPTrigger{
+			ObjectMeta: metav1.ObjectMeta{Name: "t", Namespace: "default"},
+			Spec:       fv1.HTTPTriggerSpec{FunctionReference: ref, Mirror: m},
+		}
+	}
+	viaAlias := fv1.FunctionReference{Type: fv1.FunctionReferenceTypeFunctionName, Name: "hello", Alias: "prod"}
+	plain := fv1.FunctionReference{Type: fv1.FunctionReferenceTypeFunctionName, Name: "hello"}
+
+	t.Run("alias mirror", func(t *testing.T) {
+		rr, err := frr.resolve(t.Context(), trigger(viaAlias, nil))
+		require.NoError(t, err)
+		require.NotNil(t, rr.mirror)
+		assert.Equal(t, routetable.BackendKey("hello", "hello-v2"), rr.mirror.key)
+		assert.Equal(t, 22, rr.mirror.fn.Spec.FunctionTimeout, "the version's snapshot")
+		assert.Equal(t, int32(10), rr.mirror.percent)
+	})
+
+	t.Run("trigger mirror wins", func(t *testing.T) {
+		rr, err := frr.resolve(t.Context(), trigger(viaAlias, &fv1.TrafficMirror{Version: "hello-v3"}))
+		require.NoError(t, err)
+		require.NotNil(t, rr.mirror)
+		assert.Equal(t, routetable.BackendKey("hello", "hello-v3"), rr.mirror.key)
+		assert.Equal(t, fv1.DefaultTrafficMirrorPercent, rr.mirror.percent)
+	})
+
+	t.Run("unresolvable mirror is dropped", func(t *testing.T) {
+		rr, err := frr.resolve(t.Context(), trigger(plain, &fv1.TrafficMirror{Version: "hello-v9"}))
+		require.NoError(t, err)
+		assert.Contains(t, rr.functionMap, "hello")
+		assert.Nil(t, rr.mirror)
+	})
+
+	t.Run("mirror of a served version is dropped", func(t *testing.T) {
+		rr, err := frr.resolve(t.Context(), trigger(viaAlias, &fv1.TrafficMirror{Version: "hello-v1"}))
+		require.NoError(t, err)
+		assert.Nil(t, rr.mirror)
+	})
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/utils/correlation"
+)
+
+// gRPC invocation (fv1.StreamingGRPC, fv1.HTTPTriggerGRPC). The proxying
+// itself is the streaming path on the h2c transport: ReverseProxy already
+// carries full-duplex bodies and copies the upstream's trailers. What gRPC
+// adds is how the router reports its own failures — a gRPC client reads
+// grpc-status, not the HTTP status, so an error the router answers for the
+// function is a trailers-only response with the status mapped to a code.
+
+// gRPC status codes the router answers with
+// (https://grpc.github.io/grpc/core/md_doc_statuscodes.html).
+const (
+	grpcCanceled         = 1
+	grpcUnknown          = 2
+	grpcDeadlineExceeded = 4
+	grpcPermissionDenied = 7
+	grpcUnimplemented    = 12
+	grpcInternal         = 13
+	grpcUnavailable      = 14
+	grpcUnauthenticated  = 16
+)
+
+// isGRPCRequest reports whether r is a gRPC call: content type
+// application/grpc, alone or with a +codec or parameters. gRPC-Web
+// (application/grpc-web) is excluded; it is plain HTTP/1 to the router.
+func isGRPCRequest(r *http.Request) bool {
+	rest, ok := strings.CutPrefix(r.Header.Get("Content-Type"), "application/grpc")
+	return ok && (rest == "" || rest[0] == '+' || rest[0] == ';')
+}
+
+// grpcStatusFor maps the HTTP status the router would have answered with to
+// a gRPC code, per gRPC's HTTP-to-gRPC status mapping, with the router's own
+// 499 and 504 kept distinct as CANCELLED and DEADLINE_EXCEEDED.
+func grpcStatusFor(status int) int {
+	switch status {
+	case 499:
+		return grpcCanceled
+	case http.StatusGatewayTimeout:
+		return grpcDeadlineExceeded
+	case http.StatusBadRequest:
+		return grpcInternal
+	case http.StatusUnauthorized:
+		return grpcUnauthenticated
+	case http.StatusForbidden:
+		return grpcPermissionDenied
+	case http.StatusNotFound:
+		return grpcUnimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return grpcUnavailable
+	}
+	return grpcUnknown
+}
+
+// writeGRPCError answers a gRPC call the router failed with a trailers-only
+// response: HTTP 200 carrying grpc-status and grpc-message in the headers.
+func writeGRPCError(rw http.ResponseWriter, status int, component ferror.Component, reason string) {
+	h := rw.Header()
+	h.Set("Content-Type", "application/grpc")
+	h.Set(correlation.HeaderComponent, string(component))
+	h.Set("Grpc-Status", strconv.Itoa(grpcStatusFor(status)))
+	h.Set("Grpc-Message", grpcEncodeMessage(fmt.Sprintf("fission %s: %s", component, reason)))
+	rw.WriteHeader(http.StatusOK)
+}
+
+// grpcEncodeMessage percent-encodes a grpc-message value: every byte outside
+// printable ASCII, and '%' itself.
+func grpcEncodeMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c < 0x20 || c > 0x7e || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
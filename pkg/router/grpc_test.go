@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bep/debounce"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/generated/clientset/versioned/scheme"
+	"github.com/fission/fission/pkg/throttler"
+	"github.com/fission/fission/pkg/utils/loggerfactory"
+)
+
+// h2cServer starts a test server that speaks HTTP/2 cleartext only, the way a
+// gRPC server in a function pod does.
+func h2cServer(h http.Handler) *httptest.Server {
+	srv := httptest.NewUnstartedServer(h)
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	return srv
+}
+
+func h2cClient() *http.Client {
+	tr := &http.Transport{Protocols: new(http.Protocols)}
+	tr.Protocols.SetUnencryptedHTTP2(true)
+	return &http.Client{Transport: tr, Timeout: 10 * time.Second}
+}
+
+// newGRPCTriggerSet builds a trigger set serving fn behind a gRPC trigger
+// that also rate-limits, caches and mirrors, so a call crosses every response
+// writer wrap the public route has. The executor answers with upstream.
+func newGRPCTriggerSet(t *testing.T, fn *fv1.Function, upstream *httptest.Server) *HTTPTriggerSet {
+	t.Helper()
+	version := &fv1.FunctionVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "fn-v2", Namespace: fn.Namespace},
+		Spec:       fv1.FunctionVersionSpec{FunctionName: fn.Name, FunctionUID: fn.UID, Sequence: 2, Snapshot: fn.Spec},
+	}
+	trigger := fv1.HTTPTrigger{
+		ObjectMeta: metav1.ObjectMeta{Name: "greeter", Namespace: fn.Namespace},
+		Spec: fv1.HTTPTriggerSpec{
+			FunctionReference: fv1.FunctionReference{Type: fv1.FunctionReferenceTypeFunctionName, Name: fn.Name},
+			GRPC:              &fv1.HTTPTriggerGRPC{Service: "pkg.Greeter"},
+			RateLimit:         &fv1.HTTPTriggerRateLimit{Requests: 100},
+			Cache:             &fv1.HTTPTriggerCache{TTLSeconds: 60},
+			Mirror:            &fv1.TrafficMirror{Version: version.Name, Percent: 100},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(fn, version).Build()
+	u, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	exec := &fixedURLExecutor{hostPort: u.Host}
+	logger := loggerfactory.GetLogger()
+	ts := &HTTPTriggerSet{
+		logger:                     logger.WithName("grpc_test"),
+		triggers:                   []fv1.HTTPTrigger{trigger},
+		functions:                  []fv1.Function{*fn},
+		client:                     cl,
+		updateRouterRequestChannel: make(chan struct{}, 1),
+		syncDebouncer:              debounce.New(time.Millisecond),
+		resolver:                   makeFunctionReferenceResolver(logger, cl),
+		addressResolver: &executorResolver{
+			logger:    logger,
+			fmap:      makeFunctionServiceMap(logger, time.Minute),
+			reader:    cl,
+			executor:  exec,
+			throttler: throttler.MakeThrottler(30 * time.Second),
+		},
+		tapper: &executorTapper{logger: logger, executor: exec, unTapTimeout: time.Hour},
+		tsRoundTripperParams: &tsRoundTripperParams{
+			timeout:           5 * time.Second,
+			timeoutExponent:   2,
+			keepAliveTime:     30 * time.Second,
+			maxRetries:        2,
+			svcAddrRetryCount: 2,
+			streamIdleDefault: time.Minute,
+		},
+	}
+	return ts
+}
+
+// TestGRPCProxiesH2CEndToEnd drives a gRPC-shaped call through the router's
+// own public listener and route: the client hop is h2c, the pod hop must be
+// HTTP/2 (an h2c-only upstream accepts nothing else), the request body
+// streams through, a frame the function flushes reaches the client before
+// the call ends, and the function's trailers come back after it.
+func TestGRPCProxiesH2CEndToEnd(t *testing.T) {
+	t.Parallel()
+	firstFrameRead := make(chan struct{})
+	upstream := h2cServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, 2, r.ProtoMajor, "the pod hop must be HTTP/2")
+		assert.Equal(t, "/pkg.Greeter/SayHello", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+		// The second frame waits for the client to read the first, so the
+		// call hangs unless every wrap on the way passes the flush on.
+		assert.NoError(t, http.NewResponseController(w).Flush())
+		select {
+		case <-firstFrameRead:
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte("-done"))
+		w.Header().Set("Grpc-Status", "0")
+		w.Header().Set("Grpc-Message", "ok")
+	}))
+	defer upstream.Close()
+
+	fn := streamingFn("grpc-uid", &fv1.StreamingConfig{Protocol: fv1.StreamingGRPC})
+	ts := newGRPCTriggerSet(t, fn, upstream)
+	public, _, err := ts.buildMuxes(t.Context(), nil)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(t.Context())
+	mgr := &errgroup.Group{}
+	mgr.Go(func() error {
+		servePublic(ctx, loggerfactory.GetLogger(), mgr, Options{Listener: listener}, newMutableRouter(ts.logger, public.Handler()))
+		return nil
+	})
+	t.Cleanup(func() {
+		cancel()
+		_ = mgr.Wait()
+	})
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "http://"+listener.Addr().String()+"/pkg.Greeter/SayHello", strings.NewReader("frame"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+	resp, err := h2cClient().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, resp.ProtoMajor, "the client hop must be h2c")
+	assert.Equal(t, "BYPASS", resp.Header.Get(HeaderCache), "the call crosses the trigger's response cache")
+
+	first := make([]byte, len("frame"))
+	_, err = io.ReadFull(resp.Body, first)
+	require.NoError(t, err, "the flushed frame must arrive while the call is open")
+	assert.Equal(t, "frame", string(first))
+	close(firstFrameRead)
+	rest, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "-done", string(rest))
+
+	assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"), "the function's trailers must reach the client")
+	assert.Equal(t, "ok", resp.Trailer.Get("Grpc-Message"))
+}
+
+// failingExecutor cannot produce a service for any function.
+type failingExecutor struct{ fixedURLExecutor }
+
+func (e *failingExecutor) GetServiceForFunction(context.Context, *fv1.Function) (string, error) {
+	return "", ferror.MakeError(ferror.ErrorNotFound, "no such function")
+}
+
+// TestGRPCRouterErrorIsTrailersOnly: a failure the router answers for itself
+// reaches a gRPC client as grpc-status, not as an HTTP error body it cannot
+// parse.
+func TestGRPCRouterErrorIsTrailersOnly(t *testing.T) {
+	t.Parallel()
+	fn := streamingFn("grpc-err-uid", &fv1.StreamingConfig{Protocol: fv1.StreamingGRPC})
+	fh := newStreamingHandler(t, fn, &failingExecutor{}, 0, time.Minute)
+	fh.structuredErrors = true
+
+	req := httptest.NewRequest(http.MethodPost, "/pkg.Greeter/SayHello", strings.NewReader("frame"))
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	rec := httptest.NewRecorder()
+	fh.handler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/grpc", rec.Header().Get("Content-Type"))
+	assert.NotEmpty(t, rec.Header().Get("Grpc-Status"))
+	assert.NotEqual(t, "0", rec.Header().Get("Grpc-Status"))
+	assert.Empty(t, rec.Body.String(), "a trailers-only response has no body")
+}
+
+func TestIsGRPCRequest(t *testing.T) {
+	for ct, want := range map[string]bool{
+		"application/grpc":               true,
+		"application/grpc+proto":         true,
+		"application/grpc;charset=utf-8": true,
+		"application/grpc-web":           false,
+		"application/grpc-web+proto":     false,
+		"application/json":               false,
+		"":                               false,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set("Content-Type", ct)
+		assert.Equalf(t, want, isGRPCRequest(r), "content type %q", ct)
+	}
+}
+
+func TestGRPCStatusFor(t *testing.T) {
+	for status, want := range map[int]int{
+		499:                            grpcCanceled,
+		http.StatusGatewayTimeout:      grpcDeadlineExceeded,
+		http.StatusServiceUnavailable:  grpcUnavailable,
+		http.StatusTooManyRequests:     grpcUnavailable,
+		http.StatusBadGateway:          grpcUnavailable,
+		http.StatusNotFound:            grpcUnimplemented,
+		http.StatusUnauthorized:        grpcUnauthenticated,
+		http.StatusForbidden:           grpcPermissionDenied,
+		http.StatusInternalServerError: grpcUnknown,
+	} {
+		assert.Equalf(t, want, grpcStatusFor(status), "HTTP %d", status)
+	}
+}
+
+func TestGRPCEncodeMessage(t *testing.T) {
+	assert.Equal(t, "plain text", grpcEncodeMessage("plain text"))
+	assert.Equal(t, "100%25 caf%C3%A9%0A", grpcEncodeMessage("100% café\n"))
+}
@@ -30,7 +30,9 @@ import (
 	config "github.com/fission/fission/pkg/featureconfig"
 	"github.com/fission/fission/pkg/generated/clientset/versioned"
 	"github.com/fission/fission/pkg/info"
+	"github.com/fission/fission/pkg/router/endpointcache"
 	"github.com/fission/fission/pkg/router/routetable"
+	"github.com/fission/fission/pkg/statestore"
 	"github.com/fission/fission/pkg/throttler"
 	"github.com/fission/fission/pkg/utils/httpmux"
 	"github.com/fission/fission/pkg/utils/httpsecurity"
@@ -81,6 +83,9 @@ type HTTPTriggerSet struct {
 	// but whose index is still filling serves warm traffic through the executor
 	// fallback — correct, but a latency cliff during a rolling upgrade.
 	endpointsSynced cache.InformerSynced
+	// endpointIndex is the RFC-0002 endpoint index, nil when the slice-fed
+	// data plane is off; the drain endpoint marks pods draining in it.
+	endpointIndex *endpointcache.Index
 
 	// Incremental route updates (RFC-0013) are the only production path: the
 	// reconcilers feed per-event diffs into routeTable and the materializer
@@ -108,6 +113,31 @@ type HTTPTriggerSet struct {
 	// makeHTTPTriggerSet (like addressResolver) when ASYNC_INVOCATION_ENABLED, and
 	// copied into public function handlers by buildTriggerHandler. nil = feature off.
 	asyncInvoker *asyncInvoker
+
+	// jwks caches the key sets of the issuers trigger Auth policies trust,
+	// shared across routes so one IdP's keys are fetched once. nil in unit
+	// tests that build the set by hand (each policy then gets its own).
+	jwks *jwksCache
+
+	// rateLimiters holds each trigger's RateLimit state by trigger name. It
+	// outlives the route's handler (rebuilt on every function or canary
+	// change) so a handler swap does not refill every client's bucket; an
+	// entry is replaced only when the trigger's RateLimit spec changes and
+	// dropped when the trigger is deleted. rateLimitKV backs Global limits;
+	// nil when the statestore is off.
+	rateLimitMu  sync.Mutex
+	rateLimiters map[types.NamespacedName]*rateLimiter
+	rateLimitKV  statestore.KVStore
+
+	// responseCaches holds each trigger's Cache state by trigger name,
+	// kept across handler rebuilds like rateLimiters. Every trigger's
+	// entries share responseCacheLRU, the replica's byte-bounded memory
+	// store; responseCacheKV backs Shared caches and is nil when the
+	// statestore is off.
+	responseCacheMu  sync.Mutex
+	responseCaches   map[types.NamespacedName]*responseCache
+	responseCacheLRU *responseCacheLRU
+	responseCacheKV  statestore.KVStore
 }
 
 // initIncrementalRoutes wires the route table and feature-config source for the
@@ -136,6 +166,7 @@ func makeHTTPTriggerSet(logger logr.Logger, fmap *functionServiceMap, fissionCli
 		isDebugEnv:                 isDebugEnv,
 		useEncodedPath:             useEncodedPath,
 		syncDebouncer:              debounce.New(time.Millisecond * 20),
+		jwks:                       newJWKSCache(),
 	}
 	httpTriggerSet.resolver = makeFunctionReferenceResolver(logger, cl)
 	// The address resolver and tapper are the proxy path's injected seams
@@ -379,6 +410,9 @@ func (ts *HTTPTriggerSet) buildMuxes(ctx context.Context, fnTimeoutMap map[crd.C
 
 	ts.registerRouterOwnedRoutes(publicMux, featureConfig, homeHandled)
 	ts.registerAsyncDLQRoutes(internalMux)
+	ts.registerAsyncJobRoutes(internalMux)
+	ts.registerDrainRoutes(internalMux)
+	ts.registerOpenAPIRoutes(internalMux)
 	ts.registerTopicRoutes(internalMux)
 
 	return publicMux, internalMux, nil
@@ -429,6 +463,31 @@ func triggerConfigError(trigger *fv1.HTTPTrigger) (reason string, err error) {
 	if e := trigger.Spec.IngressConfig.Validate(); e != nil {
 		return fv1.HTTPTriggerReasonInvalidIngressConfig, e
 	}
+	// An invalid auth policy must not serve at all: installing the route
+	// without its policy would expose the function unauthenticated.
+	if e := trigger.Spec.Auth.Validate(); e != nil {
+		return fv1.HTTPTriggerReasonInvalidAuthConfig, e
+	}
+	if e := trigger.Spec.ValidateRateLimit(); e != nil {
+		return fv1.HTTPTriggerReasonInvalidRateLimit, e
+	}
+	if e := trigger.Spec.ValidateMirror(); e != nil {
+		return fv1.HTTPTriggerReasonInvalidMirror, e
+	}
+	// A match that does not compile must not serve at all: dropping the
+	// condition would route every request on the path to this trigger.
+	if e := trigger.Spec.ValidateMatch(); e != nil {
+		return fv1.HTTPTriggerReasonInvalidMatch, e
+	}
+	if e := trigger.Spec.Cache.Validate(); e != nil {
+		return fv1.HTTPTriggerReasonInvalidCache, e
+	}
+	if e := trigger.Spec.ValidateGRPC(); e != nil {
+		return fv1.HTTPTriggerReasonInvalidGRPC, e
+	}
+	if e := trigger.Spec.ValidateTransform(); e != nil {
+		return fv1.HTTPTriggerReasonInvalidTransform, e
+	}
 	// httpmux template compile check: a malformed template (unbalanced braces,
 	// empty var name, or an uncompilable regexp class) would register a
 	// silently-dead route — and would panic httpmux.Handler() at build time if
@@ -161,6 +161,11 @@ func (ts *HTTPTriggerSet) applyTriggerIncremental(ctx context.Context, trigger *
 	for name, fn := range rr.functionMap {
 		fnGens[name] = fn.Generation
 	}
+	// The mirror is part of the route identity like any backend: a mirror
+	// that starts (or stops) resolving must swap the handler.
+	if rr.mirror != nil {
+		fnGens[rr.mirror.key] = rr.mirror.fn.Generation
+	}
 	fnTimeout := fnTimeoutMap(rr.functionMap)
 	// StickyGen folds the sticky-config source function's Generation into the
 	// route identity. For an alias-resolved trigger the sticky source is the
@@ -185,6 +190,7 @@ func (ts *HTTPTriggerSet) applyTriggerIncremental(ctx context.Context, trigger *
 		PrefixPath: shape.prefixPath,
 		Host:       shape.host,
 		Methods:    shape.methods,
+		Match:      shape.match,
 		Created:    trigger.CreationTimestamp,
 	}
 	res := ts.routeTable.ApplyTrigger(spec, func() http.Handler {
@@ -222,6 +228,8 @@ func (ts *HTTPTriggerSet) applyTriggerIncremental(ctx context.Context, trigger *
 // object, and with it the UID, is gone).
 func (ts *HTTPTriggerSet) deleteTriggerIncremental(key types.NamespacedName) routetable.ApplyResult {
 	res := ts.routeTable.DeleteTriggerByName(key)
+	ts.dropRateLimiter(key)
+	ts.dropResponseCache(key)
 	if res == routetable.ShapeChanged {
 		ts.signalMaterialize()
 		ts.updateRoutesGauge()
@@ -252,6 +260,7 @@ func (ts *HTTPTriggerSet) applyFunctionIncremental(ctx context.Context, fn *fv1.
 // cascades to its triggers (whose resolve now fails NotFound, dropping their
 // routes and marking FunctionNotFound).
 func (ts *HTTPTriggerSet) deleteFunctionIncremental(ctx context.Context, key types.NamespacedName) error {
+	ts.tapper.Forget(key)
 	if ts.routeTable.DeleteFunction(routetable.InternalKey{NamespacedName: key}) == routetable.ShapeChanged {
 		ts.signalMaterialize()
 		ts.updateRoutesGauge()
@@ -357,6 +366,13 @@ func aliasRouteGeneration(alias *fv1.FunctionAlias, rr *resolveResult) int64 {
 		binary.BigEndian.PutUint64(buf[:], uint64(rr.functionMap[key].Generation))
 		_, _ = h.Write(buf[:])
 	}
+	// A mirror resolving late (its version published after the alias) moves
+	// neither of the above.
+	if rr.mirror != nil {
+		_, _ = h.Write([]byte("mirror:" + rr.mirror.key))
+		binary.BigEndian.PutUint64(buf[:], uint64(rr.mirror.fn.Generation))
+		_, _ = h.Write(buf[:])
+	}
 	return int64(h.Sum64())
 }
 
@@ -627,11 +643,12 @@ func (ts *HTTPTriggerSet) buildIncrementalMuxes(featureConfig *config.FeatureCon
 	publicMux, internalMux := ts.newListenerMuxes(featureConfig)
 
 	// Precedence-ordered registration (phase 2): hosted before host-less,
-	// exact before prefix, longest prefix first, creation-time tiebreak.
+	// exact before prefix, longest prefix first, narrowest match first,
+	// creation-time tiebreak.
 	// httpmux dispatches to the first matching registration, so the order IS
 	// the precedence.
 	for _, r := range m.Routes {
-		shape := routeShape{host: r.Host, methods: r.Methods}
+		shape := routeShape{host: r.Host, methods: r.Methods, match: r.Match}
 		if r.Exact {
 			shape.exactPath = r.Path
 		} else {
@@ -646,6 +663,9 @@ func (ts *HTTPTriggerSet) buildIncrementalMuxes(featureConfig *config.FeatureCon
 
 	ts.registerRouterOwnedRoutes(publicMux, featureConfig, m.HomeClaimed)
 	ts.registerAsyncDLQRoutes(internalMux)
+	ts.registerAsyncJobRoutes(internalMux)
+	ts.registerDrainRoutes(internalMux)
+	ts.registerOpenAPIRoutes(internalMux)
 	ts.registerTopicRoutes(internalMux)
 	return publicMux, internalMux
 }
@@ -874,6 +894,7 @@ func (ts *HTTPTriggerSet) resync(ctx context.Context, initial bool) (int, error)
 			if err := ts.client.Get(ctx, key.NamespacedName, &fv1.Function{}); err == nil || !apierrors.IsNotFound(err) {
 				continue
 			}
+			ts.tapper.Forget(key.NamespacedName)
 			if ts.routeTable.DeleteFunction(key) == routetable.ShapeChanged {
 				drift++
 				ts.signalMaterialize()
@@ -952,6 +973,8 @@ func (ts *HTTPTriggerSet) resync(ctx context.Context, initial bool) (int, error)
 		case err != nil && !apierrors.IsNotFound(err):
 			continue
 		}
+		ts.dropRateLimiter(key)
+		ts.dropResponseCache(key)
 		if ts.routeTable.DeleteTrigger(uid) == routetable.ShapeChanged {
 			drift++
 			ts.signalMaterialize()
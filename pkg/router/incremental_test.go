@@ -51,6 +51,7 @@ func newIncrementalTSWithClient(t testing.TB, cl client.Client) *HTTPTriggerSet
 		updateRouterRequestChannel: make(chan struct{}, 10),
 		syncDebouncer:              debounce.New(time.Millisecond),
 		resolver:                   makeFunctionReferenceResolver(logger, cl),
+		tapper:                     &executorTapper{logger: logger},
 	}
 	ts.initIncrementalRoutes()
 	ts.mutableRouter = newMutableRouter(logger, httpmux.New().Handler())
@@ -46,6 +46,13 @@ var (
 		"The function call delay caused by fission.",
 		prometheus.DefBuckets,
 	)
+	// In-flight requests per function on this router, labelled by
+	// function_namespace and function_name: the count the taps carry to the
+	// executor for auto provisioned concurrency and concurrency scaling.
+	functionInflight = metrics.Int64UpDownCounter(
+		"fission_function_inflight_requests",
+		"Requests in flight to a function on this router",
+	)
 
 	// Sticky routing observability (RFC-0023 phase 3), labelled by
 	// function_namespace/function_name. A "hit" is a request that carried a
@@ -103,6 +110,71 @@ var (
 		"fission_invocation_failures_total",
 		"Count of failed function invocations attributed by component and reason (RFC-0015).",
 	)
+	// Per-trigger authentication (HTTPTriggerSpec.Auth), labelled by policy
+	// type and result (allowed|denied|forbidden|unavailable). "unavailable"
+	// is the alertable one: the router could not load a JWKS or an API-key
+	// Secret and is refusing requests it cannot check.
+	triggerAuthDecisions = metrics.Int64Counter(
+		"fission_router_trigger_auth_total",
+		"Requests checked against an HTTPTrigger auth policy, by policy type and result.",
+	)
+	// Requests refused 429 by an HTTPTrigger's RateLimit, labelled by
+	// trigger and scope (local|global). A global limit counted under
+	// "local" was enforced per replica because the statestore did not
+	// answer in time.
+	rateLimited = metrics.Int64Counter(
+		"fission_router_rate_limited_total",
+		"Requests refused by an HTTPTrigger rate limit, by trigger and enforcement scope.",
+	)
+	// Traffic mirroring (fv1.TrafficMirror), labelled by function and
+	// mirror_version. A comparison is one mirrored pair, by the status class
+	// each side answered with ("error" for a side that failed without a
+	// response); the duration histogram holds both sides' latency under
+	// side=primary|shadow, so the two distributions compare directly.
+	// Copies never made are counted by reason (saturated, body_too_large,
+	// body_read_error, upgrade, grpc): a mirror that mostly drops compares
+	// little.
+	mirrorComparisons = metrics.Int64Counter(
+		"fission_router_mirror_comparisons_total",
+		"Mirrored requests by function, shadow version, and the primary's and shadow's status classes.",
+	)
+	mirrorDuration = metrics.Float64Histogram(
+		"fission_router_mirror_duration_seconds",
+		"Latency of mirrored requests, for the primary and the shadow side.",
+		prometheus.DefBuckets,
+	)
+	mirrorDropped = metrics.Int64Counter(
+		"fission_router_mirror_dropped_total",
+		"Sampled requests the router did not mirror, by reason.",
+	)
+	// HTTPTrigger response caching (fv1.HTTPTriggerCache), labelled by
+	// trigger and result: hit, stale (served while refreshed), coalesced (a
+	// concurrent miss that got another request's response), miss or bypass
+	// (a request that could not be cached). Evictions count entries the
+	// replica's LRU pushed out to stay within its byte budget; a steady
+	// eviction rate means ROUTER_RESPONSE_CACHE_MAX_BYTES is too small for
+	// the working set.
+	responseCacheRequests = metrics.Int64Counter(
+		"fission_router_response_cache_requests_total",
+		"Requests to HTTPTriggers with a response cache, by trigger and cache result.",
+	)
+	responseCacheEvictions = metrics.Int64Counter(
+		"fission_router_response_cache_evictions_total",
+		"Response cache entries evicted to stay within the router's cache budget.",
+	)
+	// Scale-to-zero buffering (fv1.ScaleToZeroConfig), labelled by
+	// function_namespace and function_name: the requests buffered while a
+	// function scales from zero, and those refused by reason (queue_full,
+	// timeout). Timeouts mean the function takes longer to start than its
+	// QueueTimeout.
+	activatorQueued = metrics.Int64UpDownCounter(
+		"fission_router_activator_queued_requests",
+		"Requests buffered while their function scales from zero",
+	)
+	activatorRejected = metrics.Int64Counter(
+		"fission_router_activator_rejected_total",
+		"Buffered requests refused while their function scales from zero, by reason.",
+	)
 )
 
 // functionCallAttrsCache memoizes the metric.MeasurementOption (which wraps a
@@ -144,7 +216,9 @@ func (fh functionHandler) collectFunctionMetric(start time.Time, rrt *RetryingRo
 	var path string
 
 	if fh.httpTrigger != nil {
-		if fh.httpTrigger.Spec.Prefix != nil && *fh.httpTrigger.Spec.Prefix != "" {
+		if g := fh.httpTrigger.Spec.GRPC; g != nil {
+			path = g.PathPrefix()
+		} else if fh.httpTrigger.Spec.Prefix != nil && *fh.httpTrigger.Spec.Prefix != "" {
 			path = *fh.httpTrigger.Spec.Prefix
 		} else {
 			path = fh.httpTrigger.Spec.RelativeURL
@@ -0,0 +1,321 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/crd"
+	"github.com/fission/fission/pkg/router/asyncinvoke"
+	"github.com/fission/fission/pkg/utils/httpmux"
+)
+
+// Traffic mirroring (fv1.TrafficMirror). A mirrored route copies a sampled
+// share of its synchronous requests to a shadow FunctionVersion: the copy is
+// proxied in the background through an ordinary functionHandler for the
+// shadow (so it is admitted, retried and timed out exactly like a real
+// invocation of that version), its response is read and discarded, and once
+// both sides have finished the pair's status classes and latencies are
+// recorded. Nothing about the shadow can reach the client or hold up the
+// primary: the copy runs on a context detached from the client's, and when
+// mirrorMaxInFlight copies are already running new ones are dropped rather
+// than queued.
+
+const (
+	// mirrorMaxBody is the largest request body copied. The body has to be
+	// buffered to be sent twice; larger requests are served unmirrored.
+	mirrorMaxBody = 1 << 20
+
+	// mirrorMaxInFlight caps shadow requests in flight across the router, so
+	// a slow shadow version cannot pile up goroutines and buffered bodies.
+	mirrorMaxInFlight = 256
+
+	// HeaderMirrored marks a shadow request, so a function (or its logs) can
+	// tell mirrored traffic from real traffic.
+	HeaderMirrored = "X-Fission-Mirrored"
+)
+
+// mirrorSlots is the router-wide shadow concurrency budget (see
+// mirrorMaxInFlight).
+var mirrorSlots = make(chan struct{}, mirrorMaxInFlight)
+
+// trafficMirror is one route's resolved mirror.
+type trafficMirror struct {
+	// shadow proxies the copies. It is built like an internal function
+	// route for the shadow version: no trigger, so no auth, CORS or rate
+	// limit, and no async invoker or mirror of its own.
+	shadow  *functionHandler
+	version string
+	percent int32
+	// timeout bounds a copy end to end, on top of the shadow handler's own
+	// function timeout, so a shadow stuck past it frees its slot.
+	timeout time.Duration
+}
+
+// newTrafficMirror builds the mirror for a route from its resolved mirror;
+// nil when the route has none.
+func (ts *HTTPTriggerSet) newTrafficMirror(routeName string, m *resolvedMirror) *trafficMirror {
+	if m == nil {
+		return nil
+	}
+	fns := map[string]*fv1.Function{m.key: m.fn}
+	timeouts := fnTimeoutMap(fns)
+	shadow := ts.newFunctionHandlerBase(routeName+"-mirror", fns, nil, timeouts, m.fn)
+	shadow.function = m.fn
+	shadow.asyncInvoker = nil
+
+	fnTimeout := timeouts[crd.CacheKeyUGFromMeta(&m.fn.ObjectMeta)]
+	if fnTimeout == 0 {
+		fnTimeout = fv1.DEFAULT_FUNCTION_TIMEOUT
+	}
+	return &trafficMirror{
+		shadow:  shadow,
+		version: m.fn.Labels[fv1.FUNCTION_VERSION],
+		percent: m.percent,
+		// Twice the function timeout leaves room for the shadow's
+		// specialization and retries before the ceiling cuts it off.
+		timeout: 2 * time.Duration(fnTimeout) * time.Second,
+	}
+}
+
+// mirrorOutcome is one side's result: the status written (0 when the side
+// failed without writing one) and how long it took.
+type mirrorOutcome struct {
+	status   int
+	duration time.Duration
+}
+
+// fork samples request and, when it is mirrored, starts its copy and returns
+// the writer and request the primary must be served with (the body buffered
+// for both) and a finish func the caller defers to report the primary's
+// outcome. finish is nil when request is not mirrored; request may still
+// come back with its body rewound after a copy that could not start.
+func (m *trafficMirror) fork(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request, func()) {
+	if rand.Int31n(100) >= m.percent {
+		return w, r, nil
+	}
+	// A hijacked connection has no response to compare, and its stream
+	// cannot be replayed.
+	if httpmux.IsWebSocketUpgrade(r) || r.Header.Get("Upgrade") != "" {
+		m.drop(r.Context(), "upgrade")
+		return w, r, nil
+	}
+	// A gRPC call's body is a stream the client may keep open until it has
+	// read replies; buffering it first would deadlock a bidirectional call.
+	if isGRPCRequest(r) {
+		m.drop(r.Context(), "grpc")
+		return w, r, nil
+	}
+
+	body, ok := m.bufferBody(r)
+	if !ok {
+		return w, r, nil
+	}
+
+	select {
+	case mirrorSlots <- struct{}{}:
+	default:
+		m.drop(r.Context(), "saturated")
+		return w, r, nil
+	}
+
+	// WithoutCancel keeps the request's values (route vars, trace span,
+	// correlation id) while detaching the copy from the client: a primary
+	// that answers fast must not cancel its shadow.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), m.timeout)
+	shadowReq := r.Clone(ctx)
+	shadowReq.Body = http.NoBody
+	if len(body) > 0 {
+		shadowReq.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	shadowReq.ContentLength = int64(len(body))
+	shadowReq.Header.Set(HeaderMirrored, "true")
+	// The primary was served synchronously; so is its copy.
+	shadowReq.Header.Del(asyncinvoke.HeaderInvokeMode)
+
+	primary := make(chan mirrorOutcome, 1)
+	rec := &mirrorRecorder{ResponseWriter: w}
+	start := time.Now()
+
+	go func() {
+		defer func() {
+			cancel()
+			<-mirrorSlots
+		}()
+		shadow := m.serveShadow(shadowReq)
+		m.record(ctx, <-primary, shadow)
+	}()
+
+	return rec, r, func() {
+		primary <- mirrorOutcome{status: rec.statusCode(), duration: time.Since(start)}
+	}
+}
+
+// bufferBody reads r's body so it can be sent twice, rewinding r.Body to
+// replay what was read. It reports false, counting the drop, for a body over
+// mirrorMaxBody or one that fails to read; the primary then gets the body
+// unchanged.
+func (m *trafficMirror) bufferBody(r *http.Request) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	if r.ContentLength > mirrorMaxBody {
+		m.drop(r.Context(), "body_too_large")
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, mirrorMaxBody+1))
+	r.Body = replayBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+	switch {
+	case err != nil:
+		m.drop(r.Context(), "body_read_error")
+		return nil, false
+	case len(body) > mirrorMaxBody:
+		m.drop(r.Context(), "body_too_large")
+		return nil, false
+	}
+	return body, true
+}
+
+// serveShadow proxies the copy and reports its outcome. ReverseProxy aborts a
+// failed response copy with a panic (http.ErrAbortHandler), which on the
+// primary the server recovers; nothing does on this goroutine, so recover
+// here and count the shadow as failed.
+func (m *trafficMirror) serveShadow(r *http.Request) (out mirrorOutcome) {
+	w := &discardResponseWriter{header: make(http.Header)}
+	start := time.Now()
+	defer func() {
+		out.duration = time.Since(start)
+		if p := recover(); p != nil {
+			if p != http.ErrAbortHandler {
+				m.shadow.logger.Error(nil, "traffic mirror: shadow request panicked", "panic", p)
+			}
+			out.status = 0
+		}
+	}()
+	m.shadow.handler(w, r)
+	out.status = w.statusCode()
+	return out
+}
+
+// record writes the comparison of a mirrored pair.
+func (m *trafficMirror) record(ctx context.Context, primary, shadow mirrorOutcome) {
+	fn := m.shadow.function
+	mirrorComparisons.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("function_namespace", fn.Namespace),
+		attribute.String("function_name", fn.Name),
+		attribute.String("mirror_version", m.version),
+		attribute.String("primary_status_class", statusClass(primary.status)),
+		attribute.String("shadow_status_class", statusClass(shadow.status)),
+	))
+	for _, side := range []struct {
+		name string
+		out  mirrorOutcome
+	}{{"primary", primary}, {"shadow", shadow}} {
+		mirrorDuration.Record(ctx, side.out.duration.Seconds(), metric.WithAttributes(
+			attribute.String("function_namespace", fn.Namespace),
+			attribute.String("function_name", fn.Name),
+			attribute.String("mirror_version", m.version),
+			attribute.String("side", side.name),
+		))
+	}
+}
+
+func (m *trafficMirror) drop(ctx context.Context, reason string) {
+	fn := m.shadow.function
+	mirrorDropped.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("function_namespace", fn.Namespace),
+		attribute.String("function_name", fn.Name),
+		attribute.String("mirror_version", m.version),
+		attribute.String("reason", reason),
+	))
+}
+
+// statusClass buckets a status code for the comparison metric ("2xx" ..
+// "5xx"); 0, a side that failed without a response, is "error".
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "error"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// replayBody serves the buffered prefix of a request body followed by the
+// rest of the original, closing the original.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// mirrorRecorder captures the status the primary writes. Unwrap lets
+// http.ResponseController (which ReverseProxy flushes streaming responses
+// through) reach the server's writer.
+type mirrorRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *mirrorRecorder) WriteHeader(code int) {
+	if w.status == 0 && code >= 200 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *mirrorRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *mirrorRecorder) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// statusCode is the status the client saw: net/http answers 200 for a
+// handler that wrote nothing.
+func (w *mirrorRecorder) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// discardResponseWriter is the shadow's ResponseWriter: it keeps the status
+// and throws the body away.
+type discardResponseWriter struct {
+	header http.Header
+	status int
+}
+
+func (w *discardResponseWriter) Header() http.Header { return w.header }
+
+func (w *discardResponseWriter) WriteHeader(code int) {
+	if w.status == 0 && code >= 200 {
+		w.status = code
+	}
+}
+
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return len(b), nil
+}
+
+func (w *discardResponseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// shadowRequest is what the shadow upstream saw.
+type shadowRequest struct {
+	body     string
+	mirrored string
+}
+
+// mirroredHandler builds a handler proxying to a primary upstream answering
+// 200 "primary", mirroring percent of its requests to a shadow upstream that
+// answers 500 and reports each request it gets on the returned channel.
+func mirroredHandler(t *testing.T, percent int32) (functionHandler, <-chan string, <-chan shadowRequest) {
+	t.Helper()
+	primaryBodies := make(chan string, 1)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		primaryBodies <- string(b)
+		_, _ = w.Write([]byte("primary"))
+	}))
+	t.Cleanup(primary.Close)
+
+	shadowReqs := make(chan shadowRequest, 1)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		shadowReqs <- shadowRequest{body: string(b), mirrored: r.Header.Get(HeaderMirrored)}
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("shadow"))
+	}))
+	t.Cleanup(shadow.Close)
+
+	fn := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "default", UID: "fn-uid"}}
+	fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType = fv1.ExecutorTypePoolmgr
+	shadowFn := fn.DeepCopy()
+	shadowFn.UID = k8stypes.UID("fn-uid-v2")
+	shadowFn.Labels = map[string]string{fv1.FUNCTION_VERSION: "fn-v2"}
+
+	fh := newHandlerForUpstream(t, fn, primary, 5)
+	shadowFH := newHandlerForUpstream(t, shadowFn, shadow, 5)
+	fh.mirror = &trafficMirror{shadow: &shadowFH, version: "fn-v2", percent: percent, timeout: 10 * time.Second}
+	return fh, primaryBodies, shadowReqs
+}
+
+func TestMirrorCopiesRequestAndServesPrimary(t *testing.T) {
+	t.Parallel()
+	fh, primaryBodies, shadowReqs := mirroredHandler(t, 100)
+
+	rec := httptest.NewRecorder()
+	fh.handler(rec, httptest.NewRequest(http.MethodPost, "/fn", strings.NewReader("hello")))
+
+	assert.Equal(t, http.StatusOK, rec.Code, "the shadow's 500 never reaches the client")
+	assert.Equal(t, "primary", rec.Body.String())
+	assert.Equal(t, "hello", <-primaryBodies, "buffering the body for the copy leaves the primary's intact")
+	select {
+	case got := <-shadowReqs:
+		assert.Equal(t, "hello", got.body)
+		assert.Equal(t, "true", got.mirrored)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the shadow never received the copy")
+	}
+}
+
+func TestMirrorSkipsOversizedBody(t *testing.T) {
+	t.Parallel()
+	fh, primaryBodies, shadowReqs := mirroredHandler(t, 100)
+
+	body := strings.Repeat("x", mirrorMaxBody+1)
+	req := httptest.NewRequest(http.MethodPost, "/fn", strings.NewReader(body))
+	req.ContentLength = -1 // unknown length: the cap is found by reading
+	rec := httptest.NewRecorder()
+	fh.handler(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, <-primaryBodies, len(body), "the primary gets the whole body back")
+	select {
+	case <-shadowReqs:
+		t.Fatal("an oversized body was mirrored")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestMirrorUnsampledRequestPassesThrough(t *testing.T) {
+	t.Parallel()
+	fh, _, _ := mirroredHandler(t, 0)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/fn", nil)
+	w, r, finish := fh.mirror.fork(rec, req)
+	assert.Nil(t, finish)
+	assert.Same(t, req, r)
+	assert.Equal(t, http.ResponseWriter(rec), w)
+}
+
+func TestMirrorRecorderUnwraps(t *testing.T) {
+	t.Parallel()
+	rec := httptest.NewRecorder()
+	w := &mirrorRecorder{ResponseWriter: rec}
+	require.NoError(t, http.NewResponseController(w).Flush(), "streaming primaries still flush through the recorder")
+	assert.Equal(t, http.StatusOK, w.statusCode())
+	w.WriteHeader(http.StatusTeapot)
+	assert.Equal(t, http.StatusTeapot, w.statusCode())
+}
+
+func TestStatusClass(t *testing.T) {
+	t.Parallel()
+	for status, want := range map[int]string{0: "error", 200: "2xx", 302: "3xx", 404: "4xx", 503: "5xx", 600: "error"} {
+		assert.Equal(t, want, statusClass(status), "status %d", status)
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/router/openapi"
+	"github.com/fission/fission/pkg/utils/httpmux"
+)
+
+// openAPIPath serves the OpenAPI document of a namespace's HTTPTriggers on
+// the internal listener: ?namespace= is required, ?server= sets the
+// document's server URL.
+const openAPIPath = "/v1/openapi"
+
+// registerOpenAPIRoutes adds the OpenAPI endpoint to the INTERNAL mux. The
+// document lists every trigger of a namespace, so it stays behind the
+// internal listener's HMAC verifier like the other admin endpoints. Called
+// from both the full and incremental mux builders.
+func (ts *HTTPTriggerSet) registerOpenAPIRoutes(internal *httpmux.Mux) {
+	internal.HandleFunc(openAPIPath, ts.openAPIDocument).Methods(http.MethodGet)
+}
+
+// openAPIDocument generates the document from the Manager's cache, the
+// same objects the routes are built from.
+func (ts *HTTPTriggerSet) openAPIDocument(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" || strings.Contains(namespace, "/") || len(namespace) > 253 {
+		http.Error(w, "namespace query parameter is required and must be a plain namespace name", http.StatusBadRequest)
+		return
+	}
+	var triggers fv1.HTTPTriggerList
+	if err := ts.client.List(r.Context(), &triggers, client.InNamespace(namespace)); err != nil {
+		ts.logger.Error(err, "listing http triggers for the openapi document", "namespace", namespace)
+		http.Error(w, "listing http triggers", http.StatusInternalServerError)
+		return
+	}
+	var functions fv1.FunctionList
+	if err := ts.client.List(r.Context(), &functions, client.InNamespace(namespace)); err != nil {
+		ts.logger.Error(err, "listing functions for the openapi document", "namespace", namespace)
+		http.Error(w, "listing functions", http.StatusInternalServerError)
+		return
+	}
+	doc := openapi.Generate(triggers.Items, functions.Items, openapi.Options{
+		Namespace: namespace,
+		ServerURL: r.URL.Query().Get("server"),
+	})
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		ts.logger.Error(err, "encoding openapi document")
+	}
+}
@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package openapi generates the OpenAPI 3.1 document of the functions a
+// namespace exposes through its HTTPTriggers. It works from the trigger and
+// function objects alone, so the router's internal endpoint and `fission
+// httptrigger openapi`, which reads them from the API server, produce the
+// same document.
+package openapi
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// Version is the OpenAPI version the document conforms to.
+const Version = "3.1.0"
+
+// Options are what the document cannot learn from the objects.
+type Options struct {
+	// Namespace names the document.
+	Namespace string
+	// ServerURL is the router's public URL, e.g. https://api.example.com.
+	// Empty leaves the document's paths relative.
+	ServerURL string
+}
+
+// Document is an OpenAPI 3.1 document, trimmed to what a trigger can
+// describe.
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Servers []Server            `json:"servers,omitempty"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem holds a path's operations by lowercase method.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+
+	// Trigger and Function name what serves the operation; Function is
+	// empty for a trigger that splits traffic across functions by weight.
+	Trigger  string `json:"x-fission-trigger"`
+	Function string `json:"x-fission-function,omitempty"`
+	// Prefix marks an operation that also serves every path under its own:
+	// OpenAPI has no way to say so.
+	Prefix bool `json:"x-fission-prefix,omitempty"`
+	// Host is the only Host header the operation answers.
+	Host string                     `json:"x-fission-host,omitempty"`
+	CORS *fv1.HTTPTriggerCorsConfig `json:"x-fission-cors,omitempty"`
+}
+
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+// Schema is the schema of a parameter; request and response bodies carry
+// the JSON Schema their trigger or function declares, verbatim.
+type Schema struct {
+	Type    string   `json:"type"`
+	Pattern string   `json:"pattern,omitempty"`
+	Enum    []string `json:"enum,omitempty"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema json.RawMessage `json:"schema"`
+}
+
+// Generate builds the document for triggers and the functions they
+// reference. Triggers are taken in name order; gRPC triggers, triggers with
+// no methods and triggers that opt out (OpenAPI.Exclude) are left out. When
+// two triggers share a path and method, differing only by host or match
+// rules, the first one describes the operation.
+func Generate(triggers []fv1.HTTPTrigger, functions []fv1.Function, opts Options) *Document {
+	fns := make(map[types.NamespacedName]*fv1.Function, len(functions))
+	for i := range functions {
+		fns[types.NamespacedName{Namespace: functions[i].Namespace, Name: functions[i].Name}] = &functions[i]
+	}
+	sorted := slices.SortedFunc(slices.Values(triggers), func(a, b fv1.HTTPTrigger) int {
+		return cmp.Or(cmp.Compare(a.Namespace, b.Namespace), cmp.Compare(a.Name, b.Name))
+	})
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: fmt.Sprintf("Fission functions in namespace %s", opts.Namespace)},
+		Paths:   map[string]PathItem{},
+	}
+	if opts.ServerURL != "" {
+		doc.Servers = []Server{{URL: strings.TrimSuffix(opts.ServerURL, "/")}}
+	}
+	for i := range sorted {
+		t := &sorted[i]
+		path, params, prefix, ok := triggerPath(&t.Spec)
+		if !ok {
+			continue
+		}
+		var fn *fv1.Function
+		if t.Spec.FunctionReference.Type == fv1.FunctionReferenceTypeFunctionName {
+			fn = fns[types.NamespacedName{Namespace: t.Namespace, Name: t.Spec.FunctionReference.Name}]
+		}
+		item := doc.Paths[path]
+		if item == nil {
+			item = PathItem{}
+		}
+		for _, method := range methods(&t.Spec) {
+			key := strings.ToLower(method)
+			if _, taken := item[key]; taken {
+				continue
+			}
+			op := operation(t, fn, method, params)
+			op.Prefix = prefix
+			item[key] = op
+		}
+		if len(item) > 0 {
+			doc.Paths[path] = item
+		}
+	}
+	doc.Info.Version = contentVersion(doc.Paths)
+	return doc
+}
+
+// triggerPath converts the trigger's path template to an OpenAPI path and
+// its variables to path parameters. A prefix is described by the prefix
+// itself, without the trailing slash.
+func triggerPath(spec *fv1.HTTPTriggerSpec) (path string, params []Parameter, prefix, ok bool) {
+	if spec.GRPC != nil || (spec.OpenAPI != nil && spec.OpenAPI.Exclude) {
+		return "", nil, false, false
+	}
+	path = spec.RelativeURL
+	if spec.Prefix != nil && *spec.Prefix != "" {
+		path, prefix = *spec.Prefix, true
+		if path != "/" {
+			path = strings.TrimSuffix(path, "/")
+		}
+	}
+	if path == "" {
+		return "", nil, false, false
+	}
+
+	var b strings.Builder
+	rest := path
+	for {
+		i := strings.IndexByte(rest, '{')
+		if i < 0 {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:i])
+		rest = rest[i+1:]
+		// The same brace matching httpmux does, so a quantifier inside
+		// {id:[0-9]{3}} does not end the variable.
+		depth, j := 1, 0
+		for ; j < len(rest) && depth > 0; j++ {
+			switch rest[j] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+		if depth != 0 {
+			// Validation rejects the trigger; the router never serves it.
+			return "", nil, false, false
+		}
+		name, expr, hasExpr := strings.Cut(rest[:j-1], ":")
+		rest = rest[j:]
+		p := Parameter{Name: name, In: "path", Required: true, Schema: Schema{Type: "string"}}
+		if hasExpr {
+			p.Schema.Pattern = "^(?:" + expr + ")$"
+		}
+		params = append(params, p)
+		fmt.Fprintf(&b, "{%s}", name)
+	}
+	return b.String(), params, prefix, true
+}
+
+// methods returns the trigger's methods, Method merged into Methods, in a
+// stable order.
+func methods(spec *fv1.HTTPTriggerSpec) []string {
+	ms := slices.Clone(spec.Methods)
+	if spec.Method != "" && !slices.Contains(ms, spec.Method) {
+		ms = append(ms, spec.Method)
+	}
+	slices.Sort(ms)
+	return ms
+}
+
+func operation(t *fv1.HTTPTrigger, fn *fv1.Function, method string, pathParams []Parameter) *Operation {
+	op := &Operation{
+		OperationID: t.Name + "_" + strings.ToLower(method),
+		Parameters:  slices.Clone(pathParams),
+		Responses:   map[string]Response{"200": {Description: "The function's response."}},
+		Trigger:     t.Name,
+		Host:        t.Spec.Host,
+		CORS:        t.Spec.CorsConfig,
+	}
+	if t.Spec.FunctionReference.Type == fv1.FunctionReferenceTypeFunctionName {
+		op.Function = t.Spec.FunctionReference.Name
+	}
+
+	var tool *fv1.ToolConfig
+	if fn != nil {
+		tool = fn.Spec.Tool
+	}
+	if tool != nil {
+		op.Description = tool.Description
+	}
+	requestSchema := toolInputSchema(tool)
+	if o := t.Spec.OpenAPI; o != nil {
+		op.Summary = o.Summary
+		op.Description = cmp.Or(o.Description, op.Description)
+		op.Tags = o.Tags
+		if o.RequestSchema != nil && len(o.RequestSchema.Raw) > 0 {
+			requestSchema = o.RequestSchema.Raw
+		}
+		if o.ResponseSchema != nil && len(o.ResponseSchema.Raw) > 0 {
+			op.Responses["200"] = Response{
+				Description: "The function's response.",
+				Content:     map[string]MediaType{"application/json": {Schema: o.ResponseSchema.Raw}},
+			}
+		}
+	}
+	if requestSchema != nil && hasBody(method) {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: requestSchema}},
+		}
+	}
+
+	if m := t.Spec.Match; m != nil {
+		op.Parameters = append(op.Parameters, matchParameters("header", m.Headers)...)
+		op.Parameters = append(op.Parameters, matchParameters("query", m.QueryParams)...)
+	}
+	if t.Spec.Auth != nil {
+		op.Responses["401"] = Response{Description: "The request did not satisfy the trigger's authentication policy."}
+	}
+	if t.Spec.RateLimit != nil {
+		op.Responses["429"] = Response{Description: "The trigger's rate limit was exceeded."}
+	}
+	return op
+}
+
+func toolInputSchema(tool *fv1.ToolConfig) json.RawMessage {
+	if tool == nil || tool.InputSchema == nil || len(tool.InputSchema.Raw) == 0 {
+		return nil
+	}
+	return tool.InputSchema.Raw
+}
+
+// hasBody reports whether a request with method carries a body the
+// function reads.
+func hasBody(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	}
+	return false
+}
+
+// matchParameters describes the trigger's match rules as required
+// parameters: a request without them does not reach the operation.
+func matchParameters(in string, conds []fv1.HTTPMatchCondition) []Parameter {
+	params := make([]Parameter, 0, len(conds))
+	for _, c := range conds {
+		p := Parameter{Name: c.Name, In: in, Required: true, Schema: Schema{Type: "string"}}
+		switch c.Type {
+		case fv1.HTTPMatchRegex:
+			p.Schema.Pattern = "^(?:" + c.Value + ")$"
+		case fv1.HTTPMatchPresent:
+		default:
+			p.Schema.Enum = []string{c.Value}
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// contentVersion derives info.version from the paths, so the version
+// changes exactly when the described API does.
+func contentVersion(paths map[string]PathItem) string {
+	b, err := json.Marshal(paths)
+	if err != nil {
+		return "0"
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+func trigger(name string, spec fv1.HTTPTriggerSpec) fv1.HTTPTrigger {
+	if spec.FunctionReference.Name == "" {
+		spec.FunctionReference = fv1.FunctionReference{Type: fv1.FunctionReferenceTypeFunctionName, Name: "orders"}
+	}
+	return fv1.HTTPTrigger{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"}, Spec: spec}
+}
+
+var ordersFn = fv1.Function{
+	ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"},
+	Spec: fv1.FunctionSpec{Tool: &fv1.ToolConfig{
+		Description: "Creates an order.",
+		InputSchema: &apiextensionsv1.JSON{Raw: []byte(`{"type":"object","required":["sku"]}`)},
+	}},
+}
+
+func TestGenerate(t *testing.T) {
+	doc := Generate([]fv1.HTTPTrigger{
+		trigger("create-order", fv1.HTTPTriggerSpec{
+			RelativeURL: "/orders/{tenant}/{id:[0-9]+}",
+			Methods:     []string{http.MethodPost, http.MethodGet},
+			CorsConfig:  &fv1.HTTPTriggerCorsConfig{AllowOrigins: []string{"https://shop.example.com"}},
+		}),
+	}, []fv1.Function{ordersFn}, Options{Namespace: "shop", ServerURL: "https://api.example.com/"})
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Equal(t, []Server{{URL: "https://api.example.com"}}, doc.Servers)
+	require.Contains(t, doc.Paths, "/orders/{tenant}/{id}")
+	item := doc.Paths["/orders/{tenant}/{id}"]
+	require.Len(t, item, 2)
+
+	post := item["post"]
+	assert.Equal(t, "create-order_post", post.OperationID)
+	assert.Equal(t, "Creates an order.", post.Description)
+	assert.Equal(t, "orders", post.Function)
+	assert.Equal(t, []Parameter{
+		{Name: "tenant", In: "path", Required: true, Schema: Schema{Type: "string"}},
+		{Name: "id", In: "path", Required: true, Schema: Schema{Type: "string", Pattern: "^(?:[0-9]+)$"}},
+	}, post.Parameters)
+	require.NotNil(t, post.RequestBody)
+	assert.JSONEq(t, `{"type":"object","required":["sku"]}`, string(post.RequestBody.Content["application/json"].Schema))
+	assert.Equal(t, []string{"https://shop.example.com"}, post.CORS.AllowOrigins)
+
+	assert.Nil(t, item["get"].RequestBody, "a GET has no body to describe")
+}
+
+func TestGenerateTriggerOpenAPIOverridesTool(t *testing.T) {
+	doc := Generate([]fv1.HTTPTrigger{
+		trigger("create-order", fv1.HTTPTriggerSpec{
+			RelativeURL: "/orders",
+			Method:      http.MethodPost,
+			Auth:        &fv1.HTTPTriggerAuth{},
+			OpenAPI: &fv1.HTTPTriggerOpenAPI{
+				Summary:        "Create an order",
+				Tags:           []string{"orders"},
+				RequestSchema:  &apiextensionsv1.JSON{Raw: []byte(`{"type":"object","properties":{"sku":{"type":"string"}}}`)},
+				ResponseSchema: &apiextensionsv1.JSON{Raw: []byte(`{"type":"object"}`)},
+			},
+		}),
+	}, []fv1.Function{ordersFn}, Options{Namespace: "shop"})
+
+	op := doc.Paths["/orders"]["post"]
+	require.NotNil(t, op)
+	assert.Equal(t, "Create an order", op.Summary)
+	assert.Equal(t, "Creates an order.", op.Description, "the tool description stays when the trigger sets none")
+	assert.Equal(t, []string{"orders"}, op.Tags)
+	assert.JSONEq(t, `{"type":"object","properties":{"sku":{"type":"string"}}}`, string(op.RequestBody.Content["application/json"].Schema))
+	assert.JSONEq(t, `{"type":"object"}`, string(op.Responses["200"].Content["application/json"].Schema))
+	assert.Contains(t, op.Responses, "401")
+}
+
+func TestGeneratePrefixMatchAndSkipped(t *testing.T) {
+	doc := Generate([]fv1.HTTPTrigger{
+		trigger("b-v2", fv1.HTTPTriggerSpec{
+			RelativeURL: "/items",
+			Methods:     []string{http.MethodGet},
+			Match: &fv1.HTTPTriggerMatch{
+				Headers:     []fv1.HTTPMatchCondition{{Name: "X-Api-Version", Value: "2"}},
+				QueryParams: []fv1.HTTPMatchCondition{{Name: "debug", Type: fv1.HTTPMatchPresent}},
+			},
+		}),
+		trigger("a-v1", fv1.HTTPTriggerSpec{RelativeURL: "/items", Methods: []string{http.MethodGet}}),
+		trigger("static", fv1.HTTPTriggerSpec{Prefix: new("/static/"), Methods: []string{http.MethodGet}}),
+		trigger("grpc", fv1.HTTPTriggerSpec{GRPC: &fv1.HTTPTriggerGRPC{Service: "shop.Orders"}}),
+		trigger("hidden", fv1.HTTPTriggerSpec{RelativeURL: "/hook", Methods: []string{http.MethodPost},
+			OpenAPI: &fv1.HTTPTriggerOpenAPI{Exclude: true}}),
+		trigger("no-methods", fv1.HTTPTriggerSpec{RelativeURL: "/never"}),
+	}, nil, Options{Namespace: "shop"})
+
+	assert.Len(t, doc.Paths, 2)
+	assert.Equal(t, "a-v1", doc.Paths["/items"]["get"].Trigger, "the first trigger by name describes a shared path and method")
+	assert.Empty(t, doc.Paths["/items"]["get"].Description, "no function, no description")
+	assert.True(t, doc.Paths["/static"]["get"].Prefix)
+
+	// The match rules become required parameters when the trigger stands alone.
+	doc = Generate([]fv1.HTTPTrigger{trigger("b-v2", fv1.HTTPTriggerSpec{
+		RelativeURL: "/items",
+		Methods:     []string{http.MethodGet},
+		Match: &fv1.HTTPTriggerMatch{
+			Headers:     []fv1.HTTPMatchCondition{{Name: "X-Api-Version", Value: "2"}},
+			QueryParams: []fv1.HTTPMatchCondition{{Name: "debug", Type: fv1.HTTPMatchPresent}},
+		},
+	})}, nil, Options{Namespace: "shop"})
+	assert.Equal(t, []Parameter{
+		{Name: "X-Api-Version", In: "header", Required: true, Schema: Schema{Type: "string", Enum: []string{"2"}}},
+		{Name: "debug", In: "query", Required: true, Schema: Schema{Type: "string"}},
+	}, doc.Paths["/items"]["get"].Parameters)
+}
+
+func TestGenerateVersionFollowsContent(t *testing.T) {
+	triggers := []fv1.HTTPTrigger{trigger("list", fv1.HTTPTriggerSpec{RelativeURL: "/orders", Methods: []string{http.MethodGet}})}
+	a := Generate(triggers, nil, Options{Namespace: "shop"})
+	b := Generate(triggers, nil, Options{Namespace: "shop", ServerURL: "https://api.example.com"})
+	assert.Equal(t, a.Info.Version, b.Info.Version, "the server URL is not part of the API")
+
+	triggers[0].Spec.Methods = append(triggers[0].Spec.Methods, http.MethodDelete)
+	c := Generate(triggers, nil, Options{Namespace: "shop"})
+	assert.NotEqual(t, a.Info.Version, c.Info.Version)
+
+	out, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"openapi":"3.1.0"`)
+	assert.Contains(t, string(out), `"x-fission-trigger":"list"`)
+}
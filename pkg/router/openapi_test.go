@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/generated/clientset/versioned/scheme"
+	"github.com/fission/fission/pkg/router/openapi"
+	"github.com/fission/fission/pkg/utils/httpmux"
+	"github.com/fission/fission/pkg/utils/loggerfactory"
+)
+
+func TestOpenAPIEndpoint(t *testing.T) {
+	ref := fv1.FunctionReference{Type: fv1.FunctionReferenceTypeFunctionName, Name: "orders"}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		&fv1.HTTPTrigger{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"},
+			Spec: fv1.HTTPTriggerSpec{RelativeURL: "/orders", Methods: []string{http.MethodGet}, FunctionReference: ref}},
+		&fv1.HTTPTrigger{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "elsewhere"},
+			Spec: fv1.HTTPTriggerSpec{RelativeURL: "/other", Methods: []string{http.MethodGet}, FunctionReference: ref}},
+		&fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "shop"},
+			Spec: fv1.FunctionSpec{Tool: &fv1.ToolConfig{Description: "Lists orders."}}},
+	).Build()
+	ts := &HTTPTriggerSet{logger: loggerfactory.GetLogger(), client: cl}
+	mux := httpmux.New()
+	ts.registerOpenAPIRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, openAPIPath+"?namespace=shop&server=https://api.example.com", nil))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var doc openapi.Document
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+	assert.Equal(t, []openapi.Server{{URL: "https://api.example.com"}}, doc.Servers)
+	require.Len(t, doc.Paths, 1, "only the requested namespace is described")
+	assert.Equal(t, "Lists orders.", doc.Paths["/orders"]["get"].Description)
+
+	rr = httptest.NewRecorder()
+	mux.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, openAPIPath, nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
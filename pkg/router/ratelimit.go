@@ -0,0 +1,324 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/statestore"
+)
+
+// Per-trigger rate limiting (HTTPTriggerSpec.RateLimit). The check runs in
+// functionHandler.handler next to the per-route proxy policy, i.e. after the
+// trigger's auth wrap (so a claim key reads verified claims) and before the
+// async enqueue or the proxy spend anything on the request. A limited request
+// is answered 429 with Retry-After and never reaches the function.
+//
+// Buckets are per router replica by default. A Global limit keeps one bucket
+// per key in the statestore KV, updated by compare-and-swap; it trades a
+// statestore round trip per request for a cluster-wide limit, and falls back
+// to the replica's local bucket whenever the statestore cannot answer — a
+// rate limit is a protection, not a dependency a statestore outage should turn
+// into an outage of every limited route.
+
+const (
+	// maxRateLimitKeys caps the local buckets one trigger holds, so a client
+	// cycling header values cannot grow the router's memory without bound.
+	// Past the cap (after a sweep of idle buckets) new keys share the
+	// overflow bucket, which degrades per-client fairness, not the limit.
+	maxRateLimitKeys = 10000
+	// rateLimitSweepInterval is how often the local buckets are swept of
+	// keys that have refilled completely (indistinguishable from a new key).
+	rateLimitSweepInterval = time.Minute
+	// rateLimitCASAttempts bounds the compare-and-swap retries of one global
+	// check under contention before it settles for the local bucket.
+	rateLimitCASAttempts = 3
+	// rateLimitKVTimeout bounds one global check's statestore round trips.
+	rateLimitKVTimeout = 250 * time.Millisecond
+	// rateLimitKeyspace is the statestore keyspace of the global buckets,
+	// scoped per trigger (Owner "httptrigger/<name>").
+	rateLimitKeyspace = "ratelimit"
+)
+
+// tokenBucket is one key's bucket. Tokens is the level at Last; the refill
+// since then is computed on the next take rather than by a timer.
+type tokenBucket struct {
+	Tokens float64   `json:"t"`
+	Last   time.Time `json:"at"`
+}
+
+// take refills b to now and takes one token. When the bucket is empty it
+// reports how long until a token is available instead.
+func (b *tokenBucket) take(now time.Time, rate, burst float64) (bool, time.Duration) {
+	b.refill(now, rate, burst)
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.Tokens) / rate * float64(time.Second))
+}
+
+func (b *tokenBucket) refill(now time.Time, rate, burst float64) {
+	// A bucket written by another replica with a clock ahead of ours must
+	// not refill backwards.
+	if elapsed := now.Sub(b.Last); elapsed > 0 {
+		b.Tokens = min(burst, b.Tokens+elapsed.Seconds()*rate)
+	}
+	b.Last = now
+}
+
+// rateLimiter enforces one trigger's RateLimit.
+type rateLimiter struct {
+	logger logr.Logger
+	// spec is the RateLimit the limiter was built from; rateLimiterFor
+	// reuses the limiter while the trigger's spec is unchanged.
+	spec       fv1.HTTPTriggerRateLimit
+	rate       float64 // tokens per second
+	burst      float64
+	kv         statestore.KVStore // nil => local buckets only
+	scope      statestore.Scope
+	attrsLocal metric.MeasurementOption
+	attrsKV    metric.MeasurementOption
+	now        func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimiter(logger logr.Logger, trigger *fv1.HTTPTrigger, kv statestore.KVStore) *rateLimiter {
+	spec := *trigger.Spec.RateLimit
+	period := max(spec.PeriodSeconds, 1)
+	burst := spec.Burst
+	if burst == 0 {
+		burst = spec.Requests
+	}
+	rl := &rateLimiter{
+		logger: logger,
+		spec:   spec,
+		rate:   float64(spec.Requests) / float64(period),
+		burst:  float64(burst),
+		scope: statestore.Scope{
+			Namespace: trigger.Namespace,
+			Owner:     "httptrigger/" + trigger.Name,
+			Keyspace:  rateLimitKeyspace,
+		},
+		now:     time.Now,
+		buckets: map[string]*tokenBucket{},
+	}
+	if spec.Global {
+		if kv == nil {
+			logger.Info("global rate limit needs the statestore (async invocation); enforcing it per router replica")
+		} else {
+			rl.kv = kv
+		}
+	}
+	attrs := func(scope string) metric.MeasurementOption {
+		return metric.WithAttributes(
+			attribute.String("namespace", trigger.Namespace),
+			attribute.String("trigger", trigger.Name),
+			attribute.String("scope", scope))
+	}
+	rl.attrsLocal, rl.attrsKV = attrs("local"), attrs("global")
+	return rl
+}
+
+// allow admits the request or answers it 429. It reports whether the caller
+// should go on serving the request.
+func (rl *rateLimiter) allow(w http.ResponseWriter, r *http.Request) bool {
+	key := rl.key(r)
+	ok, wait, global := false, time.Duration(0), false
+	if rl.kv != nil {
+		var err error
+		ok, wait, err = rl.takeGlobal(r.Context(), key)
+		if err != nil {
+			rl.logger.V(1).Info("global rate limit check failed; using the local bucket", "error", err)
+		} else {
+			global = true
+		}
+	}
+	if !global {
+		ok, wait = rl.takeLocal(key)
+	}
+	if ok {
+		return true
+	}
+	if global {
+		rateLimited.Add(r.Context(), 1, rl.attrsKV)
+	} else {
+		rateLimited.Add(r.Context(), 1, rl.attrsLocal)
+	}
+	// Retry-After is whole seconds (RFC 9110 §10.2.3): round up, so a client
+	// that honours it finds a token waiting.
+	w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(wait.Seconds())))))
+	http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+	return false
+}
+
+// key extracts the request's bucket key. A request without the key (no such
+// header or claim) yields "", the bucket shared by every keyless request.
+func (rl *rateLimiter) key(r *http.Request) string {
+	k := rl.spec.Key
+	switch k.Source {
+	case fv1.RateLimitKeyIP:
+		return clientIP(r, int(k.TrustedProxyHops))
+	case fv1.RateLimitKeyHeader:
+		return r.Header.Get(k.Name)
+	case fv1.RateLimitKeyClaim:
+		v, _ := claimHeaderValue(verifiedClaims(r.Context())[k.Name])
+		return v
+	}
+	return ""
+}
+
+// clientIP is the address of the request's client: the entry hops from the
+// right of X-Forwarded-For when hops proxies in front of the router append to
+// it, else the connection's peer. Entries to the left of the trusted ones are
+// client-supplied and never used.
+func clientIP(r *http.Request, hops int) string {
+	if hops > 0 {
+		var entries []string
+		for _, v := range r.Header.Values("X-Forwarded-For") {
+			for e := range strings.SplitSeq(v, ",") {
+				entries = append(entries, strings.TrimSpace(e))
+			}
+		}
+		if len(entries) >= hops {
+			if ip := net.ParseIP(entries[len(entries)-hops]); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (rl *rateLimiter) takeLocal(key string) (bool, time.Duration) {
+	now := rl.now()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	b, ok := rl.buckets[key]
+	if !ok {
+		if len(rl.buckets) >= maxRateLimitKeys || now.Sub(rl.lastSweep) >= rateLimitSweepInterval {
+			rl.sweepLocked(now)
+		}
+		if len(rl.buckets) >= maxRateLimitKeys {
+			key = ""
+			b = rl.buckets[key]
+		}
+		if b == nil {
+			b = &tokenBucket{Tokens: rl.burst, Last: now}
+			rl.buckets[key] = b
+		}
+	}
+	return b.take(now, rl.rate, rl.burst)
+}
+
+// sweepLocked drops the buckets that have refilled completely: a new bucket
+// for the same key would start in exactly that state.
+func (rl *rateLimiter) sweepLocked(now time.Time) {
+	rl.lastSweep = now
+	for k, b := range rl.buckets {
+		if b.Tokens+now.Sub(b.Last).Seconds()*rl.rate >= rl.burst {
+			delete(rl.buckets, k)
+		}
+	}
+}
+
+// takeGlobal runs take against the key's bucket in the statestore, retrying
+// the compare-and-swap when another replica wrote the bucket in between. A
+// denied request writes nothing: the refill is recomputed from Last on the
+// next take anyway. The bucket is written with a TTL of one full refill, by
+// which time it would be indistinguishable from a new one, so idle keys clean
+// up after themselves.
+func (rl *rateLimiter) takeGlobal(ctx context.Context, key string) (bool, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, rateLimitKVTimeout)
+	defer cancel()
+	// Hashed: keys are client-controlled (and may be addresses or user
+	// identities), and must not end up verbatim in the statestore.
+	sum := sha256.Sum256([]byte(key))
+	kvKey := hex.EncodeToString(sum[:])
+	ttl := time.Duration(rl.burst/rl.rate*float64(time.Second)) + time.Second
+	for range rateLimitCASAttempts {
+		now := rl.now()
+		b := tokenBucket{Tokens: rl.burst, Last: now}
+		var version int64
+		switch v, err := rl.kv.Get(ctx, rl.scope, kvKey); {
+		case errors.Is(err, statestore.ErrNotFound):
+		case err != nil:
+			return false, 0, err
+		default:
+			if err := json.Unmarshal(v.Data, &b); err != nil {
+				return false, 0, err
+			}
+			version = v.Version
+		}
+		ok, wait := b.take(now, rl.rate, rl.burst)
+		if !ok {
+			return false, wait, nil
+		}
+		data, err := json.Marshal(b)
+		if err != nil {
+			return false, 0, err
+		}
+		err = rl.kv.Set(ctx, rl.scope, kvKey, data, statestore.SetOptions{IfVersion: &version, TTL: ttl})
+		if err == nil {
+			return true, 0, nil
+		}
+		if !errors.Is(err, statestore.ErrVersionConflict) {
+			return false, 0, err
+		}
+	}
+	return false, 0, errors.New("rate limit bucket contended")
+}
+
+// rateLimiterFor returns the trigger's limiter, or nil when it has no
+// RateLimit. The limiter is reused across handler rebuilds while the spec is
+// unchanged so buckets survive them.
+func (ts *HTTPTriggerSet) rateLimiterFor(trigger *fv1.HTTPTrigger) *rateLimiter {
+	key := types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Name}
+	ts.rateLimitMu.Lock()
+	defer ts.rateLimitMu.Unlock()
+	if trigger.Spec.RateLimit == nil {
+		delete(ts.rateLimiters, key)
+		return nil
+	}
+	if rl, ok := ts.rateLimiters[key]; ok && rl.spec == *trigger.Spec.RateLimit {
+		return rl
+	}
+	if ts.rateLimiters == nil {
+		ts.rateLimiters = map[types.NamespacedName]*rateLimiter{}
+	}
+	rl := newRateLimiter(ts.logger.WithName("rate_limiter").WithValues("trigger", key), trigger, ts.rateLimitKV)
+	ts.rateLimiters[key] = rl
+	return rl
+}
+
+// dropRateLimiter forgets a deleted trigger's buckets.
+func (ts *HTTPTriggerSet) dropRateLimiter(key types.NamespacedName) {
+	ts.rateLimitMu.Lock()
+	defer ts.rateLimitMu.Unlock()
+	delete(ts.rateLimiters, key)
+}
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/statestore"
+	"github.com/fission/fission/pkg/statestore/memory"
+)
+
+func rateLimitedTrigger(rl fv1.HTTPTriggerRateLimit) *fv1.HTTPTrigger {
+	return &fv1.HTTPTrigger{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "limited"},
+		Spec:       fv1.HTTPTriggerSpec{RelativeURL: "/limited", RateLimit: &rl},
+	}
+}
+
+// fakeClock is a settable rateLimiter.now.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func limiterWithClock(rl fv1.HTTPTriggerRateLimit, kv statestore.KVStore, clock *fakeClock) *rateLimiter {
+	l := newRateLimiter(logr.Discard(), rateLimitedTrigger(rl), kv)
+	l.now = clock.now
+	return l
+}
+
+// check runs one request through the limiter and returns the response
+// (200 when admitted).
+func check(l *rateLimiter, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	if l.allow(w, r) {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w
+}
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	l := limiterWithClock(fv1.HTTPTriggerRateLimit{Requests: 2, PeriodSeconds: 10, Burst: 3}, nil, clock)
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+
+	for i := range 3 {
+		assert.Equal(t, http.StatusOK, check(l, req).Code, "burst request %d", i)
+	}
+	w := check(l, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	// One token refills every 5s.
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+
+	clock.advance(4 * time.Second)
+	w = check(l, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"), "rounded up, never 0")
+
+	clock.advance(time.Second)
+	assert.Equal(t, http.StatusOK, check(l, req).Code)
+	assert.Equal(t, http.StatusTooManyRequests, check(l, req).Code)
+}
+
+func TestRateLimiterKeys(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name      string
+		key       fv1.HTTPTriggerRateLimitKey
+		a, b      func(*http.Request) *http.Request
+		separated bool
+	}{
+		{
+			name: "trigger shares one bucket",
+			a:    func(r *http.Request) *http.Request { r.RemoteAddr = "10.0.0.1:1"; return r },
+			b:    func(r *http.Request) *http.Request { r.RemoteAddr = "10.0.0.2:1"; return r },
+		},
+		{
+			name:      "ip separates peers",
+			key:       fv1.HTTPTriggerRateLimitKey{Source: fv1.RateLimitKeyIP},
+			a:         func(r *http.Request) *http.Request { r.RemoteAddr = "10.0.0.1:1"; return r },
+			b:         func(r *http.Request) *http.Request { r.RemoteAddr = "10.0.0.2:1"; return r },
+			separated: true,
+		},
+		{
+			name: "ip ignores a forged X-Forwarded-For without trusted hops",
+			key:  fv1.HTTPTriggerRateLimitKey{Source: fv1.RateLimitKeyIP},
+			a:    func(r *http.Request) *http.Request { r.Header.Set("X-Forwarded-For", "1.1.1.1"); return r },
+			b:    func(r *http.Request) *http.Request { r.Header.Set("X-Forwarded-For", "2.2.2.2"); return r },
+		},
+		{
+			name: "ip behind one proxy uses the entry it appended",
+			key:  fv1.HTTPTriggerRateLimitKey{Source: fv1.RateLimitKeyIP, TrustedProxyHops: 1},
+			// The client forges the left entry; the proxy appends the real one.
+			a: func(r *http.Request) *http.Request { r.Header.Set("X-Forwarded-For", "9.9.9.9, 1.1.1.1"); return r },
+			b: func(r *http.Request) *http.Request { r.Header.Set("X-Forwarded-For", "8.8.8.8, 1.1.1.1"); return r },
+		},
+		{
+			name:      "header",
+			key:       fv1.HTTPTriggerRateLimitKey{Source: fv1.RateLimitKeyHeader, Name: "X-Tenant"},
+			a:         func(r *http.Request) *http.Request { r.Header.Set("X-Tenant", "a"); return r },
+			b:         func(r *http.Request) *http.Request { r.Header.Set("X-Tenant", "b"); return r },
+			separated: true,
+		},
+		{
+			name: "claim",
+			key:  fv1.HTTPTriggerRateLimitKey{Source: fv1.RateLimitKeyClaim, Name: "sub"},
+			a: func(r *http.Request) *http.Request {
+				return r.WithContext(context.WithValue(r.Context(), verifiedClaimsKey{}, map[string]any{"sub": "alice"}))
+			},
+			b: func(r *http.Request) *http.Request {
+				return r.WithContext(context.WithValue(r.Context(), verifiedClaimsKey{}, map[string]any{"sub": "bob"}))
+			},
+			separated: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+			l := limiterWithClock(fv1.HTTPTriggerRateLimit{Requests: 1, PeriodSeconds: 60, Key: tc.key}, nil, clock)
+			req := func(f func(*http.Request) *http.Request) *http.Request {
+				return f(httptest.NewRequest(http.MethodGet, "/limited", nil))
+			}
+			require.Equal(t, http.StatusOK, check(l, req(tc.a)).Code)
+			want := http.StatusTooManyRequests
+			if tc.separated {
+				want = http.StatusOK
+			}
+			assert.Equal(t, want, check(l, req(tc.b)).Code)
+		})
+	}
+}
+
+func TestRateLimiterGlobalAcrossReplicas(t *testing.T) {
+	t.Parallel()
+	caps, err := memory.New()
+	require.NoError(t, err)
+	kv, err := caps.KV()
+	require.NoError(t, err)
+
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	spec := fv1.HTTPTriggerRateLimit{Requests: 3, PeriodSeconds: 60, Global: true}
+	replicas := []*rateLimiter{limiterWithClock(spec, kv, clock), limiterWithClock(spec, kv, clock)}
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+
+	admitted := 0
+	for i := range 6 {
+		if check(replicas[i%2], req).Code == http.StatusOK {
+			admitted++
+		}
+	}
+	assert.Equal(t, 3, admitted, "the two replicas share one bucket")
+
+	clock.advance(20 * time.Second)
+	assert.Equal(t, http.StatusOK, check(replicas[1], req).Code)
+	assert.Equal(t, http.StatusTooManyRequests, check(replicas[0], req).Code)
+}
+
+// failingKV is a statestore that is down.
+type failingKV struct{ statestore.KVStore }
+
+func (failingKV) Get(context.Context, statestore.Scope, string) (statestore.Value, error) {
+	return statestore.Value{}, errors.New("statestore unavailable")
+}
+
+func TestRateLimiterGlobalFallsBackToLocal(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	l := limiterWithClock(fv1.HTTPTriggerRateLimit{Requests: 1, PeriodSeconds: 60, Global: true}, failingKV{}, clock)
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+
+	assert.Equal(t, http.StatusOK, check(l, req).Code, "an unreachable statestore must not fail requests")
+	assert.Equal(t, http.StatusTooManyRequests, check(l, req).Code, "but the limit still holds per replica")
+}
+
+func TestRateLimiterLocalKeyCap(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	l := limiterWithClock(fv1.HTTPTriggerRateLimit{Requests: 1, PeriodSeconds: 60}, nil, clock)
+	for i := range maxRateLimitKeys + 10 {
+		l.takeLocal(string(rune(i)))
+	}
+	assert.LessOrEqual(t, len(l.buckets), maxRateLimitKeys+1, "the cap plus the shared overflow bucket")
+
+	// Once the buckets have refilled a sweep reclaims them.
+	clock.advance(time.Hour)
+	l.takeLocal("new")
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestRateLimiterForSurvivesHandlerRebuilds(t *testing.T) {
+	t.Parallel()
+	ts := &HTTPTriggerSet{}
+	trigger := rateLimitedTrigger(fv1.HTTPTriggerRateLimit{Requests: 5})
+	key := types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Name}
+
+	first := ts.rateLimiterFor(trigger)
+	require.NotNil(t, first)
+	assert.Same(t, first, ts.rateLimiterFor(trigger.DeepCopy()), "an unchanged spec keeps its buckets")
+
+	trigger.Spec.RateLimit.Requests = 10
+	changed := ts.rateLimiterFor(trigger)
+	assert.NotSame(t, first, changed, "a spec change starts fresh")
+
+	trigger.Spec.RateLimit = nil
+	assert.Nil(t, ts.rateLimiterFor(trigger))
+	assert.NotContains(t, ts.rateLimiters, key)
+
+	trigger.Spec.RateLimit = &fv1.HTTPTriggerRateLimit{Requests: 5}
+	ts.rateLimiterFor(trigger)
+	ts.dropRateLimiter(key)
+	assert.NotContains(t, ts.rateLimiters, key)
+}
@@ -37,6 +37,7 @@ func newReconcilerTS(t *testing.T, crObjs ...client.Object) (*HTTPTriggerSet, cl
 		updateRouterRequestChannel: make(chan struct{}, 10),
 		syncDebouncer:              debounce.New(time.Millisecond),
 		resolver:                   makeFunctionReferenceResolver(logger, cl),
+		tapper:                     &executorTapper{logger: logger},
 	}
 	// The reconcilers drive the incremental route path (RFC-0013); wire its
 	// route table so applyTriggerIncremental / applyFunctionIncremental have a
@@ -6,6 +6,7 @@ package router
 
 import (
 	"net/http"
+	"strconv"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -23,6 +24,7 @@ const (
 	headerFissionFunctionName            = "X-" + HEADERS_FISSION_FUNCTION_PREFIX + "-Name"
 	headerFissionFunctionNamespace       = "X-" + HEADERS_FISSION_FUNCTION_PREFIX + "-Namespace"
 	headerFissionFunctionResourceVersion = "X-" + HEADERS_FISSION_FUNCTION_PREFIX + "-ResourceVersion"
+	headerFissionFunctionGeneration      = "X-" + HEADERS_FISSION_FUNCTION_PREFIX + "-Generation"
 )
 
 // setFunctionMetadataToHeaders set function metadata to request header
@@ -31,6 +33,8 @@ func setFunctionMetadataToHeader(meta *metav1.ObjectMeta, request *http.Request)
 	request.Header.Set(headerFissionFunctionName, meta.Name)
 	request.Header.Set(headerFissionFunctionNamespace, meta.Namespace)
 	request.Header.Set(headerFissionFunctionResourceVersion, meta.ResourceVersion)
+	// The wasm host selects the function's module by UID and generation.
+	request.Header.Set(headerFissionFunctionGeneration, strconv.FormatInt(meta.Generation, 10))
 }
 
 // setPathInfoToHeaders set URL path params and full URL path to request header
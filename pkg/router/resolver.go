@@ -76,3 +76,13 @@ type AddressResolver interface {
 	// address had been resolved.
 	Invalidate(fn *fv1.Function, addr *url.URL, reason InvalidateReason)
 }
+
+// OutcomeReporter is implemented by resolvers that keep per-endpoint circuit
+// breakers (the index-fed resolver). The transport reports the outcome of each
+// request to an index-admitted endpoint of a function that opted into circuit
+// breaking (FunctionSpec.Invocation.CircuitBreaker); failed means a 5xx
+// response or a connection that failed after it was established. Dial
+// failures go through Invalidate instead.
+type OutcomeReporter interface {
+	ReportOutcome(fn *fv1.Function, addr *url.URL, failed bool)
+}
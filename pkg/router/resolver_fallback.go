@@ -38,7 +38,8 @@ type CapacityClient interface {
 //   - newdeploy/container: ≥1 ready endpoint → the legacy resolver (cached
 //     Service DNS); zero ready endpoints → bypass the address cache and RPC the
 //     executor proactively, replacing the dial-fail backoff ladder on
-//     scale-from-zero.
+//     scale-from-zero. Functions with ScaleToZero are buffered by the
+//     activator instead, which sizes the scale up by its queue.
 type fallbackResolver struct {
 	logger   logr.Logger
 	index    *endpointcache.Index
@@ -48,6 +49,12 @@ type fallbackResolver struct {
 	// outstanding across ready endpoints) instead of the Service VIP.
 	// Default off (ROUTER_ENDPOINTSLICE_ENDPOINT_LB).
 	endpointLB bool
+	// breakerStatus mirrors circuit breaker transitions onto the Function's
+	// CircuitClosed condition; nil leaves conditions alone (tests).
+	breakerStatus *breakerStatusWriter
+	// activator buffers the requests of scale-to-zero functions without a
+	// ready endpoint.
+	activator *activator
 }
 
 func newFallbackResolver(logger logr.Logger, ix *endpointcache.Index, executor *executorResolver, capacity CapacityClient, endpointLB bool) *fallbackResolver {
@@ -57,6 +64,7 @@ func newFallbackResolver(logger logr.Logger, ix *endpointcache.Index, executor *
 		executor:   executor,
 		capacity:   capacity,
 		endpointLB: endpointLB,
+		activator:  newActivator(logger, executor),
 	}
 }
 
@@ -71,7 +79,14 @@ func backendVersion(fn *fv1.Function) string {
 }
 
 func (f *fallbackResolver) Resolve(ctx context.Context, fn *fv1.Function, stickyKey string) (ResolvedEntry, error) {
-	if fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType != fv1.ExecutorTypePoolmgr {
+	switch fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType {
+	case fv1.ExecutorTypePoolmgr:
+	case fv1.ExecutorTypeWasm:
+		// A wasm function has no Service or EndpointSlice of its own: it
+		// lives in its namespace's shared host, whose address the executor
+		// hands out.
+		return f.executor.Resolve(ctx, fn, stickyKey)
+	default:
 		return f.resolveDeployBacked(ctx, fn, stickyKey)
 	}
 	// Strict-mode and OnceOnly functions take the legacy RPC path: strict for
@@ -186,6 +201,13 @@ func (f *fallbackResolver) resolveDeployBacked(ctx context.Context, fn *fv1.Func
 	// climb the retry ladder — and RPC the executor (coalesced through the
 	// throttler), which scales the Deployment up and waits for readiness.
 	endpointcache.RecordFallback(endpointcache.FallbackNoEndpoints)
+	if fn.Spec.InvokeStrategy.ExecutionStrategy.ScaleToZero != nil {
+		svcURL, err := f.activator.activate(ctx, fn)
+		if err != nil {
+			return ResolvedEntry{}, err
+		}
+		return ResolvedEntry{SvcURL: svcURL}, nil
+	}
 	svcURL, err := f.executor.resolveUncached(ctx, fn)
 	if err != nil {
 		return ResolvedEntry{}, err
@@ -218,3 +240,29 @@ func (f *fallbackResolver) Invalidate(fn *fv1.Function, addr *url.URL, reason In
 	}
 	f.executor.Invalidate(fn, addr, reason)
 }
+
+// ReportOutcome feeds one request's outcome to the endpoint's circuit breaker
+// (see endpointcache.ReportOutcome). Transitions are logged at Info, counted,
+// and queued for the function's CircuitClosed condition; a refused trip
+// (MaxEjectionPercent) is only counted and logged, since nothing changed.
+func (f *fallbackResolver) ReportOutcome(fn *fv1.Function, addr *url.URL, failed bool) {
+	if addr == nil || fn.Spec.Invocation == nil || fn.Spec.Invocation.CircuitBreaker == nil {
+		return
+	}
+	version := backendVersion(fn)
+	t := f.index.ReportOutcome(fn.Namespace, fn.Name, version, addr.Host, fn.Spec.Invocation.CircuitBreaker.Effective(), failed)
+	if t == endpointcache.BreakerUnchanged {
+		return
+	}
+	endpointcache.RecordBreakerTransition(fn.Namespace, fn.Name, t)
+	if t == endpointcache.BreakerCapped {
+		f.logger.Info("circuit breaker tripped but max ejection percent reached; endpoint stays in rotation",
+			"function", fn.Name, "namespace", fn.Namespace, "version", version, "address", addr.Host)
+		return
+	}
+	f.logger.Info("circuit breaker "+string(t),
+		"function", fn.Name, "namespace", fn.Namespace, "version", version, "address", addr.Host)
+	if f.breakerStatus != nil {
+		f.breakerStatus.notify(fn)
+	}
+}
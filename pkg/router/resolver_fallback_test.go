@@ -61,7 +61,7 @@ func (s *stubExecutor) GetServiceForFunction(_ context.Context, _ *fv1.Function)
 	s.calls++
 	return s.addr, nil
 }
-func (s *stubExecutor) TapService(metav1.ObjectMeta, fv1.ExecutorType, url.URL) {}
+func (s *stubExecutor) TapService(metav1.ObjectMeta, fv1.ExecutorType, url.URL, int) {}
 func (s *stubExecutor) UnTapService(context.Context, metav1.ObjectMeta, fv1.ExecutorType, *url.URL) error {
 	return nil
 }
@@ -278,6 +278,39 @@ func TestFallbackInvalidateQuarantinesEndpoint(t *testing.T) {
 	entry2.Release()
 }
 
+func TestFallbackReportOutcomeEjectsOptedInFunctions(t *testing.T) {
+	t.Parallel()
+	ix := endpointcache.NewIndex()
+	ix.ApplySlice(fnSlice("s1", "fn-cb", "default", "10.0.0.1", "10.0.0.2"))
+	f := newFallbackForTest(t, ix, &stubExecutor{addr: "10.9.9.9:8888"}, nil)
+	f.breakerStatus = newBreakerStatusWriter(f.logger, nil, ix)
+	bad := mustParseURL(t, "http://10.0.0.1:8888")
+
+	fn := poolFn("fn-cb")
+	for range 10 {
+		f.ReportOutcome(fn, bad, true)
+	}
+	assert.Empty(t, ix.Breakers("default", "fn-cb", ""), "no breaker without the opt-in")
+
+	fn.Spec.Invocation = &fv1.InvocationConfig{CircuitBreaker: &fv1.CircuitBreakerConfig{ConsecutiveFailures: 2}}
+	f.ReportOutcome(fn, bad, true)
+	f.ReportOutcome(fn, bad, true)
+	require.Len(t, ix.Breakers("default", "fn-cb", ""), 1)
+	assert.Contains(t, f.breakerStatus.pending, types.NamespacedName{Namespace: "default", Name: "fn-cb"}, "the condition write is queued")
+
+	cond := f.breakerStatus.condition(types.NamespacedName{Namespace: "default", Name: "fn-cb"}, "")
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, fv1.FunctionReasonEndpointsEjected, cond.Reason)
+	assert.Equal(t, "1 of 2 endpoints ejected (0 half-open)", cond.Message)
+
+	for range 5 {
+		entry, err := f.Resolve(t.Context(), fn, "")
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.2:8888", entry.SvcURL.Host, "the ejected endpoint is skipped")
+		entry.Release()
+	}
+}
+
 // TestFallbackOnceOnlyBypassesIndex: OnceOnly pods serve exactly one request —
 // even when (stale) slices list them, the resolver must take the executor path.
 func TestFallbackOnceOnlyBypassesIndex(t *testing.T) {
@@ -0,0 +1,695 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/statestore"
+)
+
+// Response caching (HTTPTriggerSpec.Cache). The lookup runs in
+// functionHandler.handler after the rate limit and async decisions, so a hit
+// is still a request the trigger admitted, and before the mirror, so a hit
+// is never copied to a shadow. Only GETs are cached, keyed by the backend
+// function version that answered (a function update misses at once), the
+// host and request URI, and the values of the trigger's Vary headers.
+//
+// Entries live in one memory LRU per router replica shared by every cached
+// trigger, bounded in bytes, and optionally in the statestore KV so one
+// replica's miss fills every replica. Concurrent misses for a key wait for
+// the first one's response instead of each calling the function, and a stale
+// entry inside its stale-while-revalidate window is served while one
+// background request refreshes it.
+
+const (
+	// defaultResponseCacheMaxBytes is the replica's cache budget when
+	// ROUTER_RESPONSE_CACHE_MAX_BYTES is unset.
+	defaultResponseCacheMaxBytes = 64 << 20
+	// responseCacheKVTimeout bounds one shared-cache statestore round trip;
+	// past it the request goes on as a local miss.
+	responseCacheKVTimeout = 250 * time.Millisecond
+	// responseCacheKeyspace is the statestore keyspace of shared entries,
+	// scoped per trigger (Owner "httptrigger/<name>").
+	responseCacheKeyspace = "responsecache"
+	// responseCacheRefreshTimeout bounds a background revalidation.
+	responseCacheRefreshTimeout = time.Minute
+
+	// HeaderCache tells the client how a cached trigger answered: HIT (a
+	// fresh entry), STALE (an entry past its freshness, being refreshed),
+	// MISS (the function answered) or BYPASS (the request was not
+	// cacheable).
+	HeaderCache = "X-Fission-Cache"
+)
+
+// cachedResponse is one stored response. Fresh and Stale are absolute so an
+// entry read from the shared KV ages the same on every replica.
+type cachedResponse struct {
+	Status int         `json:"s"`
+	Header http.Header `json:"h"`
+	Body   []byte      `json:"b"`
+	Stored time.Time   `json:"at"`
+	Fresh  time.Time   `json:"f"`  // served as a HIT until
+	Stale  time.Time   `json:"st"` // served as STALE until (>= Fresh)
+	// VaryNames are the request headers the response's own Vary header
+	// named, and VaryValues their values on the request that produced it;
+	// a request with other values misses.
+	VaryNames  []string `json:"vn,omitempty"`
+	VaryValues string   `json:"vv,omitempty"`
+}
+
+// size approximates the entry's memory for the LRU budget.
+func (e *cachedResponse) size() int64 {
+	n := int64(len(e.Body)) + int64(len(e.VaryValues))
+	for k, vs := range e.Header {
+		n += int64(len(k))
+		for _, v := range vs {
+			n += int64(len(v))
+		}
+	}
+	return n
+}
+
+// matchesVary reports whether r carries the values e was stored for.
+func (e *cachedResponse) matchesVary(r *http.Request) bool {
+	return len(e.VaryNames) == 0 || varyValues(e.VaryNames, r) == e.VaryValues
+}
+
+// varyValues renders the values of names on r, in order.
+func varyValues(names []string, r *http.Request) string {
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteByte('=')
+		sb.WriteString(strings.Join(r.Header.Values(name), ","))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// responseCacheLRU is the replica's memory store, shared by every cached
+// trigger so the replica's budget holds however many triggers cache.
+type responseCacheLRU struct {
+	maxBytes int64
+
+	mu    sync.Mutex
+	bytes int64
+	ll    *list.List // front = most recently used
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key  string
+	resp *cachedResponse
+}
+
+func newResponseCacheLRU(maxBytes int64) *responseCacheLRU {
+	return &responseCacheLRU{maxBytes: maxBytes, ll: list.New(), items: map[string]*list.Element{}}
+}
+
+func (c *responseCacheLRU) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*lruEntry).resp
+}
+
+// add stores resp under key, evicting the least recently used entries to
+// stay within the budget. An entry larger than the whole budget is not
+// stored.
+func (c *responseCacheLRU) add(ctx context.Context, key string, resp *cachedResponse) {
+	size := resp.size()
+	if size > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.removeLocked(el)
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, resp: resp})
+	c.bytes += size
+	for c.bytes > c.maxBytes {
+		c.removeLocked(c.ll.Back())
+		responseCacheEvictions.Add(ctx, 1)
+	}
+}
+
+// removePrefix drops every entry whose key starts with prefix: a trigger's
+// entries when it is deleted or its cache spec changes. Linear, like the
+// rare events that call it.
+func (c *responseCacheLRU) removePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.removeLocked(el)
+		}
+	}
+}
+
+func (c *responseCacheLRU) removeLocked(el *list.Element) {
+	e := el.Value.(*lruEntry)
+	c.ll.Remove(el)
+	delete(c.items, e.key)
+	c.bytes -= e.resp.size()
+}
+
+// cacheFlight is one in-progress upstream call for a key. resp is set, and
+// nil when the response was not cacheable, before done is closed.
+type cacheFlight struct {
+	done chan struct{}
+	resp *cachedResponse
+}
+
+// cacheRefreshKey marks a background revalidation request; its value is the
+// flight the refresh fills.
+type cacheRefreshKey struct{}
+
+// responseCache is one trigger's cache.
+type responseCache struct {
+	logger logr.Logger
+	// spec is the Cache the cache was built from; responseCacheFor reuses
+	// the cache while the trigger's spec is unchanged.
+	spec      fv1.HTTPTriggerCache
+	prefix    string // every key of this trigger starts with it
+	vary      []string
+	maxObject int64
+	ttl       time.Duration
+	swr       time.Duration
+	lru       *responseCacheLRU
+	kv        statestore.KVStore // nil => this replica only
+	scope     statestore.Scope
+	attrs     map[string]metric.MeasurementOption // by result
+	now       func() time.Time
+
+	mu      sync.Mutex
+	flights map[string]*cacheFlight
+}
+
+func newResponseCache(logger logr.Logger, trigger *fv1.HTTPTrigger, lru *responseCacheLRU, kv statestore.KVStore) *responseCache {
+	spec := *trigger.Spec.Cache.DeepCopy()
+	c := &responseCache{
+		logger:    logger,
+		spec:      spec,
+		prefix:    trigger.Namespace + "/" + trigger.Name + "\n",
+		maxObject: spec.EffectiveMaxObjectBytes(),
+		ttl:       time.Duration(spec.TTLSeconds) * time.Second,
+		swr:       time.Duration(spec.StaleWhileRevalidateSeconds) * time.Second,
+		lru:       lru,
+		scope: statestore.Scope{
+			Namespace: trigger.Namespace,
+			Owner:     "httptrigger/" + trigger.Name,
+			Keyspace:  responseCacheKeyspace,
+		},
+		attrs:   map[string]metric.MeasurementOption{},
+		now:     time.Now,
+		flights: map[string]*cacheFlight{},
+	}
+	for _, h := range spec.Vary {
+		c.vary = append(c.vary, http.CanonicalHeaderKey(h))
+	}
+	slices.Sort(c.vary)
+	c.vary = slices.Compact(c.vary)
+	if spec.Shared {
+		if kv == nil {
+			logger.Info("shared response cache needs the statestore (async invocation); caching per router replica")
+		} else {
+			c.kv = kv
+		}
+	}
+	for _, result := range []string{"hit", "stale", "miss", "coalesced", "bypass"} {
+		c.attrs[result] = metric.WithAttributes(
+			attribute.String("namespace", trigger.Namespace),
+			attribute.String("trigger", trigger.Name),
+			attribute.String("result", result))
+	}
+	return c
+}
+
+// serve answers r from the cache when it can, reporting served=true once it
+// has written the response. Otherwise the caller proxies r through the
+// returned writer and calls finish (when non-nil) after the response is
+// written, which stores it. refresh re-runs the caller's handler for a
+// background revalidation.
+func (c *responseCache) serve(w http.ResponseWriter, r *http.Request, fn *fv1.Function, refresh http.HandlerFunc) (_ http.ResponseWriter, finish func(), served bool) {
+	if flight, ok := r.Context().Value(cacheRefreshKey{}).(*cacheFlight); ok {
+		rec := newCacheRecorder(w, c.maxObject)
+		return rec, c.finisher(c.key(r, fn), flight, rec, r), false
+	}
+	cc := parseCacheControl(r.Header.Values("Cache-Control"))
+	if r.Method != http.MethodGet || cc.has("no-store") {
+		c.count(r.Context(), "bypass")
+		w.Header().Set(HeaderCache, "BYPASS")
+		return w, nil, false
+	}
+	key := c.key(r, fn)
+
+	// A client asking for a fresh response (no-cache, max-age=0) skips the
+	// lookup and the coalescing, but its response still refills the entry.
+	if !cc.has("no-cache") && cc["max-age"] != "0" {
+		if resp := c.lookup(r.Context(), key); resp != nil && resp.matchesVary(r) {
+			now := c.now()
+			switch {
+			case now.Before(resp.Fresh):
+				c.count(r.Context(), "hit")
+				writeCachedResponse(w, resp, "HIT", now)
+				return w, nil, true
+			case now.Before(resp.Stale):
+				c.count(r.Context(), "stale")
+				c.revalidate(r, key, refresh)
+				writeCachedResponse(w, resp, "STALE", now)
+				return w, nil, true
+			}
+		}
+
+		c.mu.Lock()
+		if flight, ok := c.flights[key]; ok {
+			c.mu.Unlock()
+			select {
+			case <-flight.done:
+				if resp := flight.resp; resp != nil && resp.matchesVary(r) {
+					c.count(r.Context(), "coalesced")
+					writeCachedResponse(w, resp, "HIT", c.now())
+					return w, nil, true
+				}
+			case <-r.Context().Done():
+				return w, nil, false // the proxy answers the canceled request
+			}
+			// The leader's response was not cacheable (or varied away from
+			// this request): call the function like any miss.
+		} else {
+			flight := &cacheFlight{done: make(chan struct{})}
+			c.flights[key] = flight
+			c.mu.Unlock()
+			c.count(r.Context(), "miss")
+			w.Header().Set(HeaderCache, "MISS")
+			rec := newCacheRecorder(w, c.maxObject)
+			return rec, c.finisher(key, flight, rec, r), false
+		}
+	}
+
+	c.count(r.Context(), "miss")
+	w.Header().Set(HeaderCache, "MISS")
+	rec := newCacheRecorder(w, c.maxObject)
+	return rec, c.finisher(key, nil, rec, r), false
+}
+
+// key is r's cache key: the trigger, the backend function version that
+// serves it, the host and request URI, the trigger's Vary values, and the
+// caller's identity. A trigger's Auth policy replaces any client-supplied
+// X-Fission-Auth-* headers with the verified identity before the cache runs,
+// so keying on them keeps one caller's response from another; on a route
+// without a policy they only split the entries.
+func (c *responseCache) key(r *http.Request, fn *fv1.Function) string {
+	var sb strings.Builder
+	sb.WriteString(c.prefix)
+	sb.WriteString(string(fn.UID))
+	sb.WriteByte('@')
+	sb.WriteString(strconv.FormatInt(fn.Generation, 10))
+	sb.WriteByte('\n')
+	sb.WriteString(r.Host)
+	sb.WriteString(r.URL.RequestURI())
+	sb.WriteByte('\n')
+	sb.WriteString(varyValues(c.vary, r))
+	var identity []string
+	for name := range r.Header {
+		if strings.HasPrefix(name, fv1.HTTPTriggerAuthHeaderPrefix) {
+			identity = append(identity, name)
+		}
+	}
+	slices.Sort(identity)
+	sb.WriteString(varyValues(identity, r))
+	return sb.String()
+}
+
+// lookup returns the entry for key from memory, else from the shared KV
+// (filling memory); nil on a miss.
+func (c *responseCache) lookup(ctx context.Context, key string) *cachedResponse {
+	if resp := c.lru.get(key); resp != nil {
+		return resp
+	}
+	if c.kv == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, responseCacheKVTimeout)
+	defer cancel()
+	v, err := c.kv.Get(ctx, c.scope, kvCacheKey(key))
+	if err != nil {
+		if !errors.Is(err, statestore.ErrNotFound) {
+			c.logger.V(1).Info("shared response cache read failed; treating as a miss", "error", err)
+		}
+		return nil
+	}
+	resp := &cachedResponse{}
+	if err := json.Unmarshal(v.Data, resp); err != nil {
+		c.logger.V(1).Info("shared response cache entry unreadable; treating as a miss", "error", err)
+		return nil
+	}
+	c.lru.add(ctx, key, resp)
+	return resp
+}
+
+// finisher returns the func the caller defers once the response is written.
+// ReverseProxy aborts a response copy that fails partway with a panic
+// (http.ErrAbortHandler); the recorder then holds a truncated body, so the
+// finisher recovers it, releases the flight without storing, and re-panics
+// for the server to handle as it always has.
+func (c *responseCache) finisher(key string, flight *cacheFlight, rec *cacheRecorder, r *http.Request) func() {
+	return func() {
+		if p := recover(); p != nil {
+			rec.overflow = true
+			c.land(r.Context(), key, flight, rec, r)
+			panic(p)
+		}
+		c.land(r.Context(), key, flight, rec, r)
+	}
+}
+
+// land stores the recorded response when it is cacheable, then releases
+// flight's waiters (flight may be nil for an uncoalesced miss).
+func (c *responseCache) land(ctx context.Context, key string, flight *cacheFlight, rec *cacheRecorder, r *http.Request) {
+	resp := c.cacheable(rec, r)
+	if resp != nil {
+		c.lru.add(ctx, key, resp)
+		if c.kv != nil {
+			c.storeShared(ctx, key, resp)
+		}
+	}
+	if flight == nil {
+		return
+	}
+	flight.resp = resp
+	c.mu.Lock()
+	delete(c.flights, key)
+	c.mu.Unlock()
+	close(flight.done)
+}
+
+// storeShared writes resp to the KV with a TTL of its servable lifetime. The
+// write is detached from the request, which may already be finishing.
+func (c *responseCache) storeShared(ctx context.Context, key string, resp *cachedResponse) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), responseCacheKVTimeout)
+	defer cancel()
+	ttl := resp.Stale.Sub(c.now())
+	if err := c.kv.Set(ctx, c.scope, kvCacheKey(key), data, statestore.SetOptions{TTL: ttl}); err != nil {
+		c.logger.V(1).Info("shared response cache write failed", "error", err)
+	}
+}
+
+// revalidate refreshes key in the background through refresh, unless a call
+// for it is already in flight. The refresh is detached from r's client.
+func (c *responseCache) revalidate(r *http.Request, key string, refresh http.HandlerFunc) {
+	c.mu.Lock()
+	if _, busy := c.flights[key]; busy {
+		c.mu.Unlock()
+		return
+	}
+	flight := &cacheFlight{done: make(chan struct{})}
+	c.flights[key] = flight
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), responseCacheRefreshTimeout)
+	req := r.Clone(context.WithValue(ctx, cacheRefreshKey{}, flight))
+	req.Body = http.NoBody
+	go func() {
+		defer cancel()
+		defer func() {
+			// A refresh that never reached serve (refused before the cache,
+			// or panicked) must still release its waiters.
+			select {
+			case <-flight.done:
+			default:
+				c.mu.Lock()
+				delete(c.flights, key)
+				c.mu.Unlock()
+				close(flight.done)
+			}
+			if p := recover(); p != nil && p != http.ErrAbortHandler {
+				c.logger.Error(nil, "response cache: revalidation panicked", "panic", p)
+			}
+		}()
+		refresh(&discardResponseWriter{header: make(http.Header)}, req)
+	}()
+}
+
+// cacheable builds the entry for a recorded response, or returns nil when
+// the response must not be stored: an uncacheable status, a body over the
+// size limit, Set-Cookie, Vary: *, or a Cache-Control (RFC 9111 §3) that
+// forbids a shared cache to store it.
+func (c *responseCache) cacheable(rec *cacheRecorder, r *http.Request) *cachedResponse {
+	if rec.overflow || rec.header == nil || !cacheableStatus(rec.status) {
+		return nil
+	}
+	h := rec.header
+	if _, ok := h["Set-Cookie"]; ok {
+		return nil
+	}
+	cc := parseCacheControl(h.Values("Cache-Control"))
+	if cc.has("no-store") || cc.has("no-cache") || cc.has("private") {
+		return nil
+	}
+	// RFC 9111 §3.5: a response to an authenticated request is stored only
+	// when the origin said a shared cache may.
+	if r.Header.Get("Authorization") != "" && !cc.has("public") && !cc.has("s-maxage") && !cc.has("must-revalidate") {
+		return nil
+	}
+	now := c.now()
+	fresh, ok := freshness(cc, h, now, c.ttl)
+	if !ok {
+		return nil
+	}
+	swr := c.swr
+	if v, ok := cc["stale-while-revalidate"]; ok {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			swr = time.Duration(secs) * time.Second
+		}
+	}
+
+	var varyNames []string
+	for _, v := range h.Values("Vary") {
+		for name := range strings.SplitSeq(v, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return nil
+			}
+			if name != "" {
+				varyNames = append(varyNames, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	slices.Sort(varyNames)
+	varyNames = slices.Compact(varyNames)
+
+	header := h.Clone()
+	header.Del(HeaderCache)
+	resp := &cachedResponse{
+		Status:    rec.status,
+		Header:    header,
+		Body:      bytes.Clone(rec.body.Bytes()),
+		Stored:    now,
+		Fresh:     now.Add(fresh),
+		Stale:     now.Add(fresh + swr),
+		VaryNames: varyNames,
+	}
+	if len(varyNames) > 0 {
+		resp.VaryValues = varyValues(varyNames, r)
+	}
+	return resp
+}
+
+// freshness is the response's freshness lifetime: s-maxage, else max-age,
+// else Expires relative to Date, else ttl. ok is false when the lifetime is
+// not positive.
+func freshness(cc cacheControl, h http.Header, now time.Time, ttl time.Duration) (time.Duration, bool) {
+	for _, directive := range []string{"s-maxage", "max-age"} {
+		if v, ok := cc[directive]; ok {
+			secs, err := strconv.Atoi(v)
+			return time.Duration(secs) * time.Second, err == nil && secs > 0
+		}
+	}
+	if v := h.Get("Expires"); v != "" {
+		expires, err := http.ParseTime(v)
+		if err != nil {
+			return 0, false // RFC 9111 §5.3: an invalid Expires is already expired
+		}
+		date := now
+		if d, err := http.ParseTime(h.Get("Date")); err == nil {
+			date = d
+		}
+		fresh := expires.Sub(date)
+		return fresh, fresh > 0
+	}
+	return ttl, true
+}
+
+// cacheableStatus reports whether status is cacheable by default (RFC 9110
+// §15.1).
+func cacheableStatus(status int) bool {
+	switch status {
+	case http.StatusOK, http.StatusNonAuthoritativeInfo, http.StatusNoContent,
+		http.StatusMultipleChoices, http.StatusMovedPermanently, http.StatusPermanentRedirect,
+		http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusGone,
+		http.StatusRequestURITooLong, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
+
+// cacheControl is a parsed Cache-Control header: directive name (lower
+// case) to its value, "" for a directive without one.
+type cacheControl map[string]string
+
+func parseCacheControl(values []string) cacheControl {
+	cc := cacheControl{}
+	for _, v := range values {
+		for part := range strings.SplitSeq(v, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if name == "" {
+				continue
+			}
+			cc[strings.ToLower(name)] = strings.Trim(value, `"`)
+		}
+	}
+	return cc
+}
+
+func (cc cacheControl) has(directive string) bool {
+	_, ok := cc[directive]
+	return ok
+}
+
+// writeCachedResponse answers w from resp.
+func writeCachedResponse(w http.ResponseWriter, resp *cachedResponse, status string, now time.Time) {
+	h := w.Header()
+	for k, vs := range resp.Header {
+		h[k] = slices.Clone(vs)
+	}
+	h.Set("Age", strconv.FormatInt(int64(max(0, now.Sub(resp.Stored).Seconds())), 10))
+	h.Set(HeaderCache, status)
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body)
+}
+
+func (c *responseCache) count(ctx context.Context, result string) {
+	responseCacheRequests.Add(ctx, 1, c.attrs[result])
+}
+
+// kvCacheKey hashes a cache key for the KV: keys carry client-controlled
+// URIs and header values, which must not end up verbatim in the statestore.
+func kvCacheKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// cacheRecorder tees the response to the client while keeping a copy for the
+// cache: the status, the header as written, and the body up to max bytes
+// (overflow marks a body that outgrew it, which is then not stored).
+type cacheRecorder struct {
+	http.ResponseWriter
+	max      int64
+	status   int
+	header   http.Header
+	body     bytes.Buffer
+	overflow bool
+}
+
+func newCacheRecorder(w http.ResponseWriter, maxBytes int64) *cacheRecorder {
+	return &cacheRecorder{ResponseWriter: w, max: maxBytes}
+}
+
+func (w *cacheRecorder) WriteHeader(code int) {
+	if w.status == 0 && code >= 200 {
+		w.status = code
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.overflow {
+		if int64(w.body.Len()+len(b)) > w.max {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheRecorder) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// responseCacheFor returns the trigger's cache, or nil when it has none. Like
+// rateLimiterFor, it keeps the cache across handler rebuilds while the spec
+// is unchanged, and drops the old cache's entries when the spec changes.
+func (ts *HTTPTriggerSet) responseCacheFor(trigger *fv1.HTTPTrigger) *responseCache {
+	key := types.NamespacedName{Namespace: trigger.Namespace, Name: trigger.Name}
+	ts.responseCacheMu.Lock()
+	defer ts.responseCacheMu.Unlock()
+	old, ok := ts.responseCaches[key]
+	if trigger.Spec.Cache == nil {
+		if ok {
+			delete(ts.responseCaches, key)
+			old.lru.removePrefix(old.prefix)
+		}
+		return nil
+	}
+	if ok && reflect.DeepEqual(old.spec, *trigger.Spec.Cache) {
+		return old
+	}
+	if ok {
+		old.lru.removePrefix(old.prefix)
+	}
+	if ts.responseCacheLRU == nil {
+		ts.responseCacheLRU = newResponseCacheLRU(defaultResponseCacheMaxBytes)
+	}
+	if ts.responseCaches == nil {
+		ts.responseCaches = map[types.NamespacedName]*responseCache{}
+	}
+	c := newResponseCache(ts.logger.WithName("response_cache").WithValues("trigger", key), trigger, ts.responseCacheLRU, ts.responseCacheKV)
+	ts.responseCaches[key] = c
+	return c
+}
+
+// dropResponseCache forgets a deleted trigger's cache and its entries.
+func (ts *HTTPTriggerSet) dropResponseCache(key types.NamespacedName) {
+	ts.responseCacheMu.Lock()
+	defer ts.responseCacheMu.Unlock()
+	if c, ok := ts.responseCaches[key]; ok {
+		delete(ts.responseCaches, key)
+		c.lru.removePrefix(c.prefix)
+	}
+}
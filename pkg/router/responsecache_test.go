@@ -0,0 +1,364 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/statestore"
+	"github.com/fission/fission/pkg/statestore/memory"
+)
+
+func cachedTrigger(spec fv1.HTTPTriggerCache) *fv1.HTTPTrigger {
+	return &fv1.HTTPTrigger{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cached"},
+		Spec:       fv1.HTTPTriggerSpec{RelativeURL: "/cached", Cache: &spec},
+	}
+}
+
+var cachedFn = &fv1.Function{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "fn", UID: "fn-uid", Generation: 1}}
+
+func cacheWithClock(spec fv1.HTTPTriggerCache, lru *responseCacheLRU, kv statestore.KVStore, clock *fakeClock) *responseCache {
+	c := newResponseCache(logr.Discard(), cachedTrigger(spec), lru, kv)
+	c.now = clock.now
+	return c
+}
+
+// origin is a function that answers with its call count, with the headers
+// set by header.
+type origin struct {
+	calls  atomic.Int32
+	header http.Header
+}
+
+func (o *origin) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	n := o.calls.Add(1)
+	for k, vs := range o.header {
+		w.Header()[k] = vs
+	}
+	_, _ = w.Write([]byte("call " + strconv.Itoa(int(n))))
+}
+
+// cachedHandler puts c in front of next the way functionHandler.handler
+// does, serving fn.
+func cachedHandler(c *responseCache, fn *fv1.Function, next http.Handler) http.HandlerFunc {
+	var h http.HandlerFunc
+	h = func(w http.ResponseWriter, r *http.Request) {
+		w, finish, served := c.serve(w, r, fn, h)
+		if served {
+			return
+		}
+		if finish != nil {
+			defer finish()
+		}
+		next.ServeHTTP(w, r)
+	}
+	return h
+}
+
+func get(h http.Handler, target string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestResponseCacheHitAfterMiss(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	c := cacheWithClock(fv1.HTTPTriggerCache{TTLSeconds: 60}, newResponseCacheLRU(1<<20), nil, clock)
+	o := &origin{}
+	h := cachedHandler(c, cachedFn, o)
+
+	first := get(h, "/cached?q=1")
+	assert.Equal(t, "MISS", first.Header().Get(HeaderCache))
+	assert.Equal(t, "call 1", first.Body.String())
+
+	clock.advance(5 * time.Second)
+	second := get(h, "/cached?q=1")
+	assert.Equal(t, "HIT", second.Header().Get(HeaderCache))
+	assert.Equal(t, "call 1", second.Body.String())
+	assert.Equal(t, "5", second.Header().Get("Age"))
+
+	assert.Equal(t, "call 2", get(h, "/cached?q=2").Body.String(), "the query string is part of the key")
+
+	updated := cachedFn.DeepCopy()
+	updated.Generation = 2
+	assert.Equal(t, "call 3", get(cachedHandler(c, updated, o), "/cached?q=1").Body.String(),
+		"a function update misses at once")
+
+	clock.advance(time.Minute)
+	assert.Equal(t, "MISS", get(h, "/cached?q=1").Header().Get(HeaderCache), "expired without a stale window")
+}
+
+// TestResponseCacheKeysByAuthIdentity: behind an API-key policy, which strips
+// the key header before the cache runs, two callers of the same URL get their
+// own entries rather than each other's responses.
+func TestResponseCacheKeysByAuthIdentity(t *testing.T) {
+	t.Parallel()
+	kube := kubefake.NewClientset(&apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-keys"},
+		Data:       map[string][]byte{"alice": []byte("k-alice"), "bob": []byte("k-bob")},
+	})
+	ts := &HTTPTriggerSet{logger: logr.Discard(), kubeClient: kube}
+	trigger := cachedTrigger(fv1.HTTPTriggerCache{TTLSeconds: 60})
+	trigger.Spec.Auth = &fv1.HTTPTriggerAuth{
+		Type:   fv1.HTTPTriggerAuthAPIKey,
+		APIKey: &fv1.HTTPTriggerAPIKeyAuth{SecretName: "api-keys"},
+	}
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	c := cacheWithClock(*trigger.Spec.Cache, newResponseCacheLRU(1<<20), nil, clock)
+	h := ts.newTriggerAuth(trigger)(cachedHandler(c, cachedFn, &origin{}))
+
+	alice := get(h, "/cached", defaultAPIKeyHeader, "k-alice")
+	assert.Equal(t, "MISS", alice.Header().Get(HeaderCache))
+	assert.Equal(t, "call 1", alice.Body.String())
+
+	bob := get(h, "/cached", defaultAPIKeyHeader, "k-bob")
+	assert.Equal(t, "MISS", bob.Header().Get(HeaderCache), "another subject must not get alice's entry")
+	assert.Equal(t, "call 2", bob.Body.String())
+
+	again := get(h, "/cached", defaultAPIKeyHeader, "k-alice")
+	assert.Equal(t, "HIT", again.Header().Get(HeaderCache))
+	assert.Equal(t, "call 1", again.Body.String())
+}
+
+func TestResponseCacheHonorsCacheControl(t *testing.T) {
+	t.Parallel()
+	for name, tc := range map[string]struct {
+		respHeader http.Header
+		reqHeader  []string
+		method     string
+		stored     bool
+	}{
+		"plain":                  {stored: true},
+		"no-store response":      {respHeader: http.Header{"Cache-Control": {"no-store"}}},
+		"private response":       {respHeader: http.Header{"Cache-Control": {"private, max-age=60"}}},
+		"no-cache response":      {respHeader: http.Header{"Cache-Control": {"no-cache"}}},
+		"max-age=0 response":     {respHeader: http.Header{"Cache-Control": {"max-age=0"}}},
+		"set-cookie":             {respHeader: http.Header{"Set-Cookie": {"a=b"}}},
+		"vary star":              {respHeader: http.Header{"Vary": {"*"}}},
+		"no-store request":       {reqHeader: []string{"Cache-Control", "no-store"}},
+		"authorized":             {reqHeader: []string{"Authorization", "Bearer x"}},
+		"authorized, public":     {reqHeader: []string{"Authorization", "Bearer x"}, respHeader: http.Header{"Cache-Control": {"public"}}, stored: true},
+		"post":                   {method: http.MethodPost},
+		"expired Expires header": {respHeader: http.Header{"Expires": {"Thu, 01 Jan 1970 00:00:00 GMT"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := cacheWithClock(fv1.HTTPTriggerCache{TTLSeconds: 60}, newResponseCacheLRU(1<<20), nil, &fakeClock{t: time.Unix(1_700_000_000, 0)})
+			o := &origin{header: tc.respHeader}
+			h := cachedHandler(c, cachedFn, o)
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			for range 2 {
+				r := httptest.NewRequest(method, "/cached", nil)
+				for i := 0; i+1 < len(tc.reqHeader); i += 2 {
+					r.Header.Set(tc.reqHeader[i], tc.reqHeader[i+1])
+				}
+				h.ServeHTTP(httptest.NewRecorder(), r)
+			}
+			want := int32(2)
+			if tc.stored {
+				want = 1
+			}
+			assert.Equal(t, want, o.calls.Load())
+		})
+	}
+}
+
+func TestResponseCacheVary(t *testing.T) {
+	t.Parallel()
+	c := cacheWithClock(fv1.HTTPTriggerCache{TTLSeconds: 60, Vary: []string{"accept-language"}},
+		newResponseCacheLRU(1<<20), nil, &fakeClock{t: time.Unix(1_700_000_000, 0)})
+	o := &origin{header: http.Header{"Vary": {"Accept"}}}
+	h := cachedHandler(c, cachedFn, o)
+
+	assert.Equal(t, "call 1", get(h, "/cached", "Accept-Language", "en", "Accept", "text/plain").Body.String())
+	assert.Equal(t, "call 1", get(h, "/cached", "Accept-Language", "en", "Accept", "text/plain").Body.String())
+	assert.Equal(t, "call 2", get(h, "/cached", "Accept-Language", "de", "Accept", "text/plain").Body.String(),
+		"the trigger's Vary header is part of the key")
+	assert.Equal(t, "call 3", get(h, "/cached", "Accept-Language", "en", "Accept", "text/html").Body.String(),
+		"the response's own Vary header is honored")
+}
+
+func TestResponseCacheCoalescesMisses(t *testing.T) {
+	t.Parallel()
+	c := cacheWithClock(fv1.HTTPTriggerCache{TTLSeconds: 60}, newResponseCacheLRU(1<<20), nil, &fakeClock{t: time.Unix(1_700_000_000, 0)})
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	o := &origin{}
+	h := cachedHandler(c, cachedFn, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		o.ServeHTTP(w, r)
+	}))
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 5)
+	wg.Go(func() { bodies[0] = get(h, "/cached").Body.String() })
+	<-entered
+	for i := 1; i < len(bodies); i++ {
+		wg.Go(func() { bodies[i] = get(h, "/cached").Body.String() })
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), o.calls.Load(), "concurrent misses make one upstream call")
+	for _, b := range bodies {
+		assert.Equal(t, "call 1", b)
+	}
+}
+
+func TestResponseCacheStaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	c := cacheWithClock(fv1.HTTPTriggerCache{TTLSeconds: 10, StaleWhileRevalidateSeconds: 30}, newResponseCacheLRU(1<<20), nil, clock)
+	refreshed := make(chan struct{}, 1)
+	o := &origin{}
+	h := cachedHandler(c, cachedFn, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o.ServeHTTP(w, r)
+		if o.calls.Load() > 1 {
+			refreshed <- struct{}{}
+		}
+	}))
+
+	assert.Equal(t, "call 1", get(h, "/cached").Body.String())
+	clock.advance(20 * time.Second)
+	stale := get(h, "/cached")
+	assert.Equal(t, "STALE", stale.Header().Get(HeaderCache))
+	assert.Equal(t, "call 1", stale.Body.String(), "the stale entry is served without waiting")
+
+	select {
+	case <-refreshed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the stale entry was never revalidated")
+	}
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.flights) == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	fresh := get(h, "/cached")
+	assert.Equal(t, "HIT", fresh.Header().Get(HeaderCache))
+	assert.Equal(t, "call 2", fresh.Body.String())
+}
+
+func TestResponseCacheMaxObjectBytes(t *testing.T) {
+	t.Parallel()
+	c := cacheWithClock(fv1.HTTPTriggerCache{TTLSeconds: 60, MaxObjectBytes: 4}, newResponseCacheLRU(1<<20), nil, &fakeClock{t: time.Unix(1_700_000_000, 0)})
+	o := &origin{}
+	h := cachedHandler(c, cachedFn, o)
+	assert.Equal(t, "call 1", get(h, "/cached").Body.String(), "an oversized body still reaches the client whole")
+	assert.Equal(t, "call 2", get(h, "/cached").Body.String(), "but is not stored")
+}
+
+func TestResponseCacheSharedAcrossReplicas(t *testing.T) {
+	t.Parallel()
+	caps, err := memory.New()
+	require.NoError(t, err)
+	kv, err := caps.KV()
+	require.NoError(t, err)
+
+	clock := &fakeClock{t: time.Unix(1_700_000_000, 0)}
+	spec := fv1.HTTPTriggerCache{TTLSeconds: 60, Shared: true}
+	o := &origin{}
+	replicaA := cachedHandler(cacheWithClock(spec, newResponseCacheLRU(1<<20), kv, clock), cachedFn, o)
+	replicaB := cachedHandler(cacheWithClock(spec, newResponseCacheLRU(1<<20), kv, clock), cachedFn, o)
+
+	assert.Equal(t, "call 1", get(replicaA, "/cached").Body.String())
+	hit := get(replicaB, "/cached")
+	assert.Equal(t, "HIT", hit.Header().Get(HeaderCache), "one replica's miss fills the other")
+	assert.Equal(t, "call 1", hit.Body.String())
+}
+
+func TestResponseCacheLRUEviction(t *testing.T) {
+	t.Parallel()
+	entry := func(body string) *cachedResponse { return &cachedResponse{Body: []byte(body)} }
+	lru := newResponseCacheLRU(10)
+	ctx := t.Context()
+
+	lru.add(ctx, "a", entry("aaaa"))
+	lru.add(ctx, "b", entry("bbbb"))
+	require.NotNil(t, lru.get("a"), "a is now the most recently used")
+	lru.add(ctx, "c", entry("cccc"))
+	assert.Nil(t, lru.get("b"), "the least recently used entry is evicted")
+	assert.NotNil(t, lru.get("a"))
+	assert.NotNil(t, lru.get("c"))
+
+	lru.add(ctx, "huge", entry("0123456789x"))
+	assert.Nil(t, lru.get("huge"), "an entry over the whole budget is not stored")
+	assert.NotNil(t, lru.get("a"), "and evicts nothing")
+
+	lru.removePrefix("a")
+	assert.Nil(t, lru.get("a"))
+	assert.Equal(t, int64(4), lru.bytes)
+}
+
+func TestResponseCacheFreshness(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1_700_000_000, 0).UTC()
+	for name, tc := range map[string]struct {
+		header http.Header
+		want   time.Duration
+		ok     bool
+	}{
+		"default ttl":          {header: http.Header{}, want: time.Minute, ok: true},
+		"max-age":              {header: http.Header{"Cache-Control": {"max-age=30"}}, want: 30 * time.Second, ok: true},
+		"s-maxage wins":        {header: http.Header{"Cache-Control": {"max-age=30, s-maxage=5"}}, want: 5 * time.Second, ok: true},
+		"expires":              {header: http.Header{"Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, want: time.Hour, ok: true},
+		"expires against date": {header: http.Header{"Date": {now.Add(-time.Hour).Format(http.TimeFormat)}, "Expires": {now.Format(http.TimeFormat)}}, want: time.Hour, ok: true},
+		"invalid expires":      {header: http.Header{"Expires": {"0"}}},
+		"zero max-age":         {header: http.Header{"Cache-Control": {"max-age=0"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := freshness(parseCacheControl(tc.header.Values("Cache-Control")), tc.header, now, time.Minute)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestResponseCacheForReusesUntilSpecChanges(t *testing.T) {
+	t.Parallel()
+	ts := &HTTPTriggerSet{logger: logr.Discard()}
+	trigger := cachedTrigger(fv1.HTTPTriggerCache{TTLSeconds: 60})
+	c := ts.responseCacheFor(trigger)
+	require.NotNil(t, c)
+	assert.Same(t, c, ts.responseCacheFor(trigger.DeepCopy()))
+
+	ts.responseCacheLRU.add(t.Context(), c.prefix+"k", &cachedResponse{Body: []byte("x")})
+	changed := cachedTrigger(fv1.HTTPTriggerCache{TTLSeconds: 30})
+	assert.NotSame(t, c, ts.responseCacheFor(changed))
+	assert.Nil(t, ts.responseCacheLRU.get(c.prefix+"k"), "a spec change drops the old entries")
+
+	ts.dropResponseCache(types.NamespacedName{Namespace: "default", Name: "cached"})
+	assert.Empty(t, ts.responseCaches)
+	assert.Nil(t, ts.responseCacheFor(&fv1.HTTPTrigger{ObjectMeta: trigger.ObjectMeta}))
+}
@@ -5,6 +5,7 @@
 package router
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -16,7 +17,10 @@ import (
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
 	"github.com/fission/fission/pkg/crd"
+	ferror "github.com/fission/fission/pkg/error"
+	"github.com/fission/fission/pkg/router/transform"
 	"github.com/fission/fission/pkg/utils"
+	"github.com/fission/fission/pkg/utils/httpmux"
 )
 
 const (
@@ -107,8 +111,11 @@ func trimFunctionPrefix(path string, bases []string) (trimmed string, ok bool) {
 
 // rewriteFunctionURL points the request at the resolved service URL and
 // rewrites its path per the HTTPTrigger specification:
-//  1. if the trigger declares a prefix, we trim it (unless KeepPrefix) and
-//     forward the request;
+//  1. if the trigger routes a gRPC service, the path is forwarded as-is
+//     (the function dispatches on /<service>/<method>), as it is when the
+//     trigger's transform already rewrote it (see transformRequest); if it
+//     declares a prefix, we trim it (unless KeepPrefix) and forward the
+//     request;
 //  2. otherwise, if the path carries the internal-listener
 //     /fission-function/[<ns>/]<name>[:<suffix>] form, that whole prefix
 //     (including any `:<alias>`/`:<version>` tag) is trimmed — see
@@ -132,15 +139,12 @@ func rewriteFunctionURL(logger logr.Logger, req *http.Request, trigger *fv1.HTTP
 	prefixTrim := ""
 	keepPrefix := false
 	switch {
+	case trigger != nil && (trigger.Spec.GRPC != nil || trigger.Spec.Transform.RewritesPath()):
+		keepPrefix = true
 	case trigger != nil && trigger.Spec.Prefix != nil && *trigger.Spec.Prefix != "":
 		prefixTrim = *trigger.Spec.Prefix
 		keepPrefix = trigger.Spec.KeepPrefix
-		if !keepPrefix {
-			req.URL.Path = strings.TrimPrefix(req.URL.Path, prefixTrim)
-		}
-		if !strings.HasPrefix(req.URL.Path, "/") {
-			req.URL.Path = "/" + req.URL.Path
-		}
+		req.URL.Path = trigger.Spec.ForwardedPath(req.URL.Path)
 	default:
 		if trimmed, ok := trimFunctionPrefix(req.URL.Path, bases); ok {
 			prefixTrim = req.URL.Path[:len(req.URL.Path)-len(trimmed)]
@@ -161,6 +165,36 @@ func rewriteFunctionURL(logger logr.Logger, req *http.Request, trigger *fv1.HTTP
 	req.Host = serviceURL.Host
 }
 
+// transform returns the trigger's transform, nil for a handler with no
+// trigger or a trigger without one.
+func (fh functionHandler) transform() *fv1.HTTPTriggerTransform {
+	if fh.httpTrigger == nil {
+		return nil
+	}
+	return fh.httpTrigger.Spec.Transform
+}
+
+// transformRequest applies a request transform to a copy of req. Unlike the
+// rest of the rewrite it runs once per request, ahead of the round tripper:
+// a body can only be read once, and a path template applied on every
+// re-resolve would compound. A rewritten path is then forwarded as-is by
+// rewriteFunctionURL. ok is false when the transform failed and the error
+// response is already written.
+func (fh functionHandler) transformRequest(rw http.ResponseWriter, req *http.Request, t *fv1.HTTPRequestTransform) (_ *http.Request, ok bool) {
+	out := req.Clone(req.Context())
+	err := transform.Request(out, t, fh.httpTrigger.Spec.ForwardedPath(req.URL.Path), httpmux.Vars(req))
+	if err == nil {
+		return out, true
+	}
+	status := http.StatusBadRequest
+	if errors.Is(err, transform.ErrBodyTooLarge) {
+		status = http.StatusRequestEntityTooLarge
+	}
+	fh.logger.V(1).Info("request transform failed", "trigger", fh.httpTrigger.Name, "error", err.Error())
+	fh.writeInvocationError(rw, req, status, ferror.ComponentRouter, ferror.ReasonRequestTransform, "request transform failed: "+err.Error(), err)
+	return nil, false
+}
+
 // addForwardedHostHeader add "forwarded host" to request header
 // (see https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Forwarded).
 // It runs on every proxied request, so the hostname is extracted with
@@ -5,8 +5,11 @@
 package router
 
 import (
+	"io"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -15,6 +18,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	ferror "github.com/fission/fission/pkg/error"
 )
 
 // triggerWithPrefix builds an HTTPTrigger with the given prefix/keepPrefix,
@@ -61,6 +65,15 @@ func TestRewriteFunctionURL(t *testing.T) {
 			reqURL:   "http://router.example/api/users",
 			wantPath: "/api/users",
 		},
+		{
+			name: "grpc trigger keeps the service/method path",
+			trigger: &fv1.HTTPTrigger{Spec: fv1.HTTPTriggerSpec{
+				GRPC: &fv1.HTTPTriggerGRPC{Service: "pkg.Greeter"},
+			}},
+			fnMeta:   metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+			reqURL:   "http://router.example/pkg.Greeter/SayHello",
+			wantPath: "/pkg.Greeter/SayHello",
+		},
 		{
 			name:     "trigger prefix equals full path normalizes to root",
 			trigger:  triggerWithPrefix("/api", false),
@@ -256,3 +269,82 @@ func TestAddForwardedHostHeader(t *testing.T) {
 		})
 	}
 }
+
+// TestTriggerTransform drives a request through the handler of a prefix
+// trigger with a transform: the function must see the rewritten path, the
+// form body as JSON and the edited headers, the client the edited response
+// headers, and the path must not be trimmed again by rewriteFunctionURL.
+func TestTriggerTransform(t *testing.T) {
+	t.Parallel()
+	var gotPath, gotUser, gotCookie, gotType, gotBody string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(b)
+		gotUser, gotCookie, gotType = r.Header.Get("X-User"), r.Header.Get("Cookie"), r.Header.Get("Content-Type")
+		w.Header().Set("Server", "werkzeug")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	prefix := "/api"
+	fh := newHandlerForUpstream(t, streamingFn("transform-uid", nil), upstream, 0)
+	fh.httpTrigger = &fv1.HTTPTrigger{
+		ObjectMeta: metav1.ObjectMeta{Name: "hook", Namespace: "default"},
+		Spec: fv1.HTTPTriggerSpec{
+			Prefix: &prefix,
+			Transform: &fv1.HTTPTriggerTransform{
+				Request: &fv1.HTTPRequestTransform{
+					Headers: &fv1.HTTPHeaderTransform{
+						Remove: []string{"Cookie"},
+						Set:    []fv1.HTTPHeaderValue{{Name: "X-User", Value: "{{query.src}}"}},
+					},
+					Path: "/v2{{path}}",
+					Body: `{"user": "{{form.user}}"}`,
+				},
+				Response: &fv1.HTTPResponseTransform{Headers: &fv1.HTTPHeaderTransform{
+					Remove: []string{"Server"},
+					Set:    []fv1.HTTPHeaderValue{{Name: "X-Transformed", Value: "yes"}},
+				}},
+			},
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/orders?src=legacy", strings.NewReader("user=bob"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Cookie", "session=1")
+	rec := httptest.NewRecorder()
+	fh.handler(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "/v2/orders", gotPath)
+	assert.JSONEq(t, `{"user": "bob"}`, gotBody)
+	assert.Equal(t, "application/json", gotType)
+	assert.Equal(t, "legacy", gotUser)
+	assert.Empty(t, gotCookie)
+	assert.Empty(t, rec.Header().Get("Server"))
+	assert.Equal(t, "yes", rec.Header().Get("X-Transformed"))
+}
+
+// TestTriggerTransformBodyTooLarge: a body template over a body past the
+// limit is answered 413 by the router, attributed to the transform.
+func TestTriggerTransformBodyTooLarge(t *testing.T) {
+	t.Parallel()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("the function must not be invoked")
+	}))
+	defer upstream.Close()
+
+	fh := newHandlerForUpstream(t, streamingFn("transform-413-uid", nil), upstream, 0)
+	fh.structuredErrors = true
+	fh.httpTrigger = &fv1.HTTPTrigger{Spec: fv1.HTTPTriggerSpec{
+		RelativeURL: "/hook",
+		Transform:   &fv1.HTTPTriggerTransform{Request: &fv1.HTTPRequestTransform{Body: `{}`}},
+	}}
+
+	big := strings.Repeat("a", int(fv1.MaxHTTPTransformRequestBytes)+1)
+	rec := httptest.NewRecorder()
+	fh.handler(rec, httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(big)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Contains(t, rec.Body.String(), ferror.ReasonRequestTransform)
+}
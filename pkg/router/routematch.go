@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/router/routetable"
+)
+
+// HTTPTrigger match rules (fv1.HTTPTriggerMatch). A trigger's Match block is
+// compiled once per shape into a routetable.RequestMatch: a canonical Key the
+// route table compares for shape changes and conflicts, the condition counts
+// precedence ranks by, and the predicate registerRouteShape hands to the mux
+// as the route's MatcherFunc. A request the predicate rejects falls through
+// to the next route registered for its path, which precedence has made the
+// next-broader match.
+
+// matchCondition is one compiled header or query-parameter condition.
+type matchCondition struct {
+	name  string // canonical header key for headers; verbatim for query parameters
+	typ   fv1.HTTPMatchType
+	value string
+	re    *regexp.Regexp // regex conditions only
+}
+
+// matches reports whether any of values satisfies the condition.
+func (c *matchCondition) matches(values []string) bool {
+	if c.typ == fv1.HTTPMatchPresent {
+		return len(values) > 0
+	}
+	if c.re != nil {
+		return slices.ContainsFunc(values, c.re.MatchString)
+	}
+	return slices.Contains(values, c.value)
+}
+
+// key is the condition's canonical form. The value is quoted so no value can
+// forge a separator.
+func (c *matchCondition) key(kind string) string {
+	return fmt.Sprintf("%s:%s=%s:%q", kind, c.name, c.typ, c.value)
+}
+
+// compileConditions compiles conditions sorted by name, so the Key does not
+// depend on the order they were written in. canonical is set for headers,
+// whose names are compared case-insensitively.
+func compileConditions(conds []fv1.HTTPMatchCondition, canonical bool) ([]matchCondition, error) {
+	out := make([]matchCondition, 0, len(conds))
+	for i := range conds {
+		c := &conds[i]
+		mc := matchCondition{name: c.Name, typ: c.Type, value: c.Value}
+		if canonical {
+			mc.name = http.CanonicalHeaderKey(c.Name)
+		}
+		if mc.typ == "" {
+			mc.typ = fv1.HTTPMatchExact
+		}
+		if mc.typ == fv1.HTTPMatchRegex {
+			re, err := c.Regexp()
+			if err != nil {
+				return nil, fmt.Errorf("match condition %q: %w", c.Name, err)
+			}
+			mc.re = re
+		}
+		out = append(out, mc)
+	}
+	slices.SortFunc(out, func(a, b matchCondition) int { return strings.Compare(a.name, b.name) })
+	return out, nil
+}
+
+// newRequestMatch compiles a trigger's Match block; nil when it has none or
+// when every list in it is empty.
+func newRequestMatch(m *fv1.HTTPTriggerMatch) (*routetable.RequestMatch, error) {
+	if m == nil || len(m.Headers) == 0 && len(m.QueryParams) == 0 && len(m.Hosts) == 0 {
+		return nil, nil
+	}
+	headers, err := compileConditions(m.Headers, true)
+	if err != nil {
+		return nil, err
+	}
+	query, err := compileConditions(m.QueryParams, false)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, 0, len(m.Hosts))
+	for _, h := range m.Hosts {
+		hosts = append(hosts, strings.ToLower(h))
+	}
+	slices.Sort(hosts)
+	hosts = slices.Compact(hosts)
+
+	keys := make([]string, 0, 1+len(headers)+len(query))
+	if len(hosts) > 0 {
+		keys = append(keys, "host:"+strings.Join(hosts, ","))
+	}
+	for i := range headers {
+		keys = append(keys, headers[i].key("header"))
+	}
+	for i := range query {
+		keys = append(keys, query[i].key("query"))
+	}
+
+	return &routetable.RequestMatch{
+		Key:          strings.Join(keys, ";"),
+		Hosts:        len(hosts) > 0,
+		WildcardHost: slices.ContainsFunc(hosts, func(h string) bool { return strings.HasPrefix(h, "*.") }),
+		Headers:      len(headers),
+		QueryParams:  len(query),
+		Matches: func(r *http.Request) bool {
+			if len(hosts) > 0 {
+				host := requestHost(r)
+				if !slices.ContainsFunc(hosts, func(h string) bool { return hostMatches(h, host) }) {
+					return false
+				}
+			}
+			for i := range headers {
+				if !headers[i].matches(r.Header.Values(headers[i].name)) {
+					return false
+				}
+			}
+			if len(query) > 0 {
+				q := r.URL.Query()
+				for i := range query {
+					if !query[i].matches(q[query[i].name]) {
+						return false
+					}
+				}
+			}
+			return true
+		},
+	}, nil
+}
+
+// requestHost is the request's host, lower-cased, without port or a trailing
+// dot.
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// hostMatches reports whether host is pattern, or for a "*.example.com"
+// pattern, whether it is a name with at least one label before example.com.
+func hostMatches(pattern, host string) bool {
+	if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+		return len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+	}
+	return host == pattern
+}
+
+// grpcMethodMatch narrows a gRPC trigger's service route (prefix, the
+// service's path prefix) to the listed methods, on top of m, its Match block
+// when it has one. The method list ranks like one header condition, so a
+// trigger serving some of a service's methods gets the first look ahead of
+// one serving all of them.
+func grpcMethodMatch(prefix string, methods []string, m *routetable.RequestMatch) *routetable.RequestMatch {
+	methods = slices.Compact(slices.Sorted(slices.Values(methods)))
+	key := "grpc:" + strings.Join(methods, ",")
+	out := &routetable.RequestMatch{Key: key, Headers: 1}
+	inner := func(*http.Request) bool { return true }
+	if m != nil {
+		*out = *m
+		out.Key = m.Key + ";" + key
+		out.Headers++
+		inner = m.Matches
+	}
+	out.Matches = func(r *http.Request) bool {
+		method, ok := strings.CutPrefix(r.URL.Path, prefix)
+		return ok && slices.Contains(methods, method) && inner(r)
+	}
+	return out
+}
@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+func TestNewRequestMatchKeyIsCanonical(t *testing.T) {
+	t.Parallel()
+	a, err := newRequestMatch(&fv1.HTTPTriggerMatch{
+		Headers: []fv1.HTTPMatchCondition{{Name: "x-api-version", Value: "2"}, {Name: "X-Tenant", Type: fv1.HTTPMatchPresent}},
+		Hosts:   []string{"B.example.com", "a.example.com"},
+	})
+	require.NoError(t, err)
+	b, err := newRequestMatch(&fv1.HTTPTriggerMatch{
+		Headers: []fv1.HTTPMatchCondition{{Name: "X-Tenant", Type: fv1.HTTPMatchPresent}, {Name: "X-Api-Version", Type: fv1.HTTPMatchExact, Value: "2"}},
+		Hosts:   []string{"a.example.com", "b.example.com"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, a.Key, b.Key, "order, header-name case and the default type do not change the key")
+	assert.Equal(t, 2, a.Headers)
+	assert.True(t, a.Hosts)
+	assert.False(t, a.WildcardHost)
+
+	c, err := newRequestMatch(&fv1.HTTPTriggerMatch{Headers: []fv1.HTTPMatchCondition{{Name: "X-Api-Version", Value: "3"}}})
+	require.NoError(t, err)
+	assert.NotEqual(t, a.Key, c.Key)
+
+	none, err := newRequestMatch(&fv1.HTTPTriggerMatch{})
+	require.NoError(t, err)
+	assert.Nil(t, none, "an empty block matches every request, like no block")
+}
+
+func TestRequestMatchMatches(t *testing.T) {
+	t.Parallel()
+	m, err := newRequestMatch(&fv1.HTTPTriggerMatch{
+		Headers:     []fv1.HTTPMatchCondition{{Name: "X-Api-Version", Type: fv1.HTTPMatchRegex, Value: "2|3"}},
+		QueryParams: []fv1.HTTPMatchCondition{{Name: "beta", Type: fv1.HTTPMatchPresent}},
+		Hosts:       []string{"*.example.com"},
+	})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name, host, target, version string
+		want                        bool
+	}{
+		{"all conditions met", "api.example.com", "/?beta", "3", true},
+		{"host with port and case", "API.Example.com:8443", "/?beta=", "2", true},
+		{"regex is anchored", "api.example.com", "/?beta", "23", false},
+		{"query parameter missing", "api.example.com", "/?Beta", "2", false},
+		{"bare wildcard suffix", "example.com", "/?beta", "2", false},
+		{"header missing", "api.example.com", "/?beta", "", false},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+		req.Host = tc.host
+		if tc.version != "" {
+			req.Header.Set("X-Api-Version", tc.version)
+		}
+		assert.Equal(t, tc.want, m.Matches(req), tc.name)
+	}
+
+	_, err = newRequestMatch(&fv1.HTTPTriggerMatch{Headers: []fv1.HTTPMatchCondition{{Name: "X-A", Type: fv1.HTTPMatchRegex, Value: "(("}}})
+	assert.Error(t, err)
+}
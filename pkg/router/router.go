@@ -471,14 +471,8 @@ func router(ctx context.Context, logger logr.Logger, mgr *errgroup.Group, httpTr
 	return publicMR, internalMR, nil
 }
 
-func serve(ctx context.Context, logger logr.Logger, mgr *errgroup.Group, opts Options,
-	httpTriggerSet *HTTPTriggerSet,
-) error {
-	publicMR, internalMR, err := router(ctx, logger, mgr, httpTriggerSet)
-	if err != nil {
-		return fmt.Errorf("error making router: %w", err)
-	}
-
+// servePublic runs the public listener for publicMR until ctx is cancelled.
+func servePublic(ctx context.Context, logger logr.Logger, mgr *errgroup.Group, opts Options, publicMR *mutableRouter) {
 	// SecurityHeaders wraps the entire public listener so every
 	// response — router-owned routes (healthz / version / auth) and
 	// user-trigger proxies alike — carries X-Content-Type-Options:
@@ -492,10 +486,24 @@ func serve(ctx context.Context, logger logr.Logger, mgr *errgroup.Group, opts Op
 	publicHandler := httpsecurity.SecurityHeaders(
 		otelUtils.GetHandlerWithOTEL(correlation.Middleware(publicMR), "fission-router", otelUtils.UrlsToIgnore("/router-healthz")),
 	)
+	// h2c next to HTTP/1 so gRPC clients reach HTTPTrigger GRPC routes
+	// without a TLS-terminating gateway in front.
+	httpserver.Serve(ctx, logger, mgr, httpserver.ServerOptions{
+		Name: "router", Addr: strconv.Itoa(opts.Port), Listener: opts.Listener, Handler: publicHandler,
+		UnencryptedHTTP2: true,
+	})
+}
+
+func serve(ctx context.Context, logger logr.Logger, mgr *errgroup.Group, opts Options,
+	httpTriggerSet *HTTPTriggerSet,
+) error {
+	publicMR, internalMR, err := router(ctx, logger, mgr, httpTriggerSet)
+	if err != nil {
+		return fmt.Errorf("error making router: %w", err)
+	}
+
 	mgr.Go(func() error {
-		httpserver.Serve(ctx, logger, mgr, httpserver.ServerOptions{
-			Name: "router", Addr: strconv.Itoa(opts.Port), Listener: opts.Listener, Handler: publicHandler,
-		})
+		servePublic(ctx, logger, mgr, opts, publicMR)
 		return nil
 	})
 
@@ -733,12 +741,19 @@ func Start(ctx context.Context, clientGen crd.ClientGeneratorInterface, logger l
 		// fallback instead of the fast path — a latency cliff precisely during
 		// a rolling upgrade (RFC-0028 §2).
 		triggers.endpointsSynced = endpointsSynced
+		triggers.endpointIndex = index
 		switch cfg.endpointSliceCacheMode {
 		case endpointSliceCacheOn:
 			// The client interface carries EnsureCapacity since phase 4; an
 			// OLD executor (predating /v2/ensureCapacity) still degrades at
 			// runtime via the 404 → legacy-RPC fallback in the resolver.
-			triggers.addressResolver = newFallbackResolver(logger, index, executorResolver, executor, cfg.endpointSliceEndpointLB)
+			fallback := newFallbackResolver(logger, index, executorResolver, executor, cfg.endpointSliceEndpointLB)
+			// Like EnsureCapacity, an old executor without /v2/scaleFromZero
+			// degrades at runtime: its 404 falls back to GetServiceForFunction.
+			fallback.activator.scaler, _ = executor.(ScaleFromZeroClient)
+			fallback.breakerStatus = newBreakerStatusWriter(logger, fissionClient, index)
+			go fallback.breakerStatus.run(ctx)
+			triggers.addressResolver = fallback
 		default:
 			// Unreachable: loadRouterConfig validates the mode. The guard
 			// keeps a future refactor from silently paying for the informer
@@ -832,7 +847,7 @@ func Start(ctx context.Context, clientGen crd.ClientGeneratorInterface, logger l
 		// statestore topics append directly; broker topics become durable egress
 		// jobs on mq-egress-<type>, executed by that broker head's egress loop —
 		// SDKs and credentials never enter the router. The accepted broker types
-		// come from the chart (EVENTING_EGRESS_TYPES, set from kafka.enabled): a
+		// come from the chart (EVENTING_EGRESS_TYPES, set from the enabled broker heads): a
 		// type is enqueued ONLY when a head exists to drain its queue, because a
 		// job on a consumer-less queue never leases, never dead-letters, and is
 		// invisible to every DLQ surface — the one loss mode the queue protocol
@@ -861,17 +876,30 @@ func Start(ctx context.Context, clientGen crd.ClientGeneratorInterface, logger l
 			}
 			return err
 		}
-		// The topic admin surface (fission topic publish|peek) shares the same
-		// publisher and store handles.
+		// The topic admin surface (fission topic publish|peek|retention|groups|
+		// seek) shares the same publisher and store handles.
+		topicKV, kverr := caps.KV()
+		if kverr != nil {
+			return fmt.Errorf("async invocation: statestore kv capability: %w", kverr)
+		}
 		triggers.asyncInvoker.eventLog = eventLog
 		triggers.asyncInvoker.publishTopic = publishTopic
+		triggers.asyncInvoker.topicPublisher = topicPublisher
+		triggers.asyncInvoker.topicKV = topicKV
+		// Global HTTPTrigger rate limits keep their buckets in the same KV.
+		triggers.rateLimitKV = topicKV
+		// So do Shared response caches.
+		triggers.responseCacheKV = topicKV
 
 		internalURL := svcinfo.NewEnvResolver(svcinfo.FlagValues{}).RouterInternalURL()
 		deliverer := asyncinvoke.NewHTTPDeliverer(internalURL, []byte(os.Getenv("FISSION_INTERNAL_AUTH_SECRET")), nil, logger.WithName("async_deliverer"))
 		// The dispatcher resolves each destination-chain hop's config from the
 		// Manager's Function cache (the fv1↔asyncinvoke mapping lives in async.go).
+		// One lane per priority, polled weighted-fair (see asyncinvoke/lanes.go).
+		lanes := asyncinvoke.DefaultLanes()
 		dispatcher := asyncinvoke.New(asyncinvoke.Options{
 			Queue:                 queue,
+			Lanes:                 lanes,
 			Deliverer:             deliverer,
 			Logger:                logger.WithName("async_dispatcher"),
 			ResolveFunctionConfig: newFunctionConfigResolver(crMgr.GetClient(), logger),
@@ -883,8 +911,32 @@ func Start(ctx context.Context, clientGen crd.ClientGeneratorInterface, logger l
 		})); aerr != nil {
 			return fmt.Errorf("async invocation: adding dispatcher runnable: %w", aerr)
 		}
-		asyncinvoke.RegisterQueueGauges(queue, asyncinvoke.DefaultQueue)
-		logger.Info("async invocation enabled", "queue", asyncinvoke.DefaultQueue, "deliveryURL", internalURL, "driver", cfg.statestoreDriver)
+		// Job invocations (executor type job) keep their records in the same KV
+		// and are finished by the job watcher, which fires their destinations
+		// through the dispatcher.
+		if jobs, ok := executor.(JobClient); ok {
+			triggers.asyncInvoker.jobs = jobs
+			triggers.asyncInvoker.jobKV = topicKV
+			watcher := &jobWatcher{
+				invoker: triggers.asyncInvoker,
+				client:  crMgr.GetClient(),
+				fire:    dispatcher.FireDestination,
+				logger:  logger.WithName("job_watcher"),
+				now:     time.Now,
+			}
+			if aerr := crMgr.Add(runnableFunc(func(rctx context.Context) error {
+				_ = watcher.Run(rctx) // returns only on ctx cancellation
+				return nil
+			})); aerr != nil {
+				return fmt.Errorf("async invocation: adding job watcher runnable: %w", aerr)
+			}
+		}
+		asyncinvoke.RegisterQueueGauges(queue, lanes)
+		laneQueues := make([]string, 0, len(lanes))
+		for _, l := range lanes {
+			laneQueues = append(laneQueues, l.Queue)
+		}
+		logger.Info("async invocation enabled", "queues", laneQueues, "deliveryURL", internalURL, "driver", cfg.statestoreDriver)
 	}
 
 	logger.Info("starting router", "port", opts.Port, "internalPort", opts.InternalPort)
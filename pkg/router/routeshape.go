@@ -41,6 +41,8 @@ type routeShape struct {
 	prefixPath string
 	host       string
 	methods    []string
+	// match is the trigger's compiled Match block; nil when it has none.
+	match *routetable.RequestMatch
 }
 
 // triggerMethods merges the trigger's method set with the deprecated
@@ -65,9 +67,35 @@ func deriveRouteShape(trigger *fv1.HTTPTrigger) routeShape {
 		host:    trigger.Spec.Host,
 		methods: triggerMethods(trigger),
 	}
+	match, err := newRequestMatch(trigger.Spec.Match)
+	if err != nil {
+		// Unreachable past triggerConfigError, which gates the route on the
+		// same compile; fail closed rather than widen the route to every
+		// request.
+		match = &routetable.RequestMatch{Key: "invalid", Matches: func(*http.Request) bool { return false }}
+	}
+	shape.match = match
+	if trigger.Spec.GRPC != nil {
+		// Every gRPC call is a POST.
+		shape.methods = []string{http.MethodPost}
+	}
 	if trigger.Spec.CorsConfig != nil && !slices.Contains(shape.methods, http.MethodOptions) {
 		shape.methods = append(slices.Clone(shape.methods), http.MethodOptions)
 	}
+	if g := trigger.Spec.GRPC; g != nil {
+		// /<service>/<method>: one method is an exact path, several are the
+		// service prefix narrowed by a method match, none the whole service.
+		switch len(g.Methods) {
+		case 0:
+			shape.prefixPath = g.PathPrefix()
+		case 1:
+			shape.exactPath = g.PathPrefix() + g.Methods[0]
+		default:
+			shape.prefixPath = g.PathPrefix()
+			shape.match = grpcMethodMatch(shape.prefixPath, g.Methods, shape.match)
+		}
+		return shape
+	}
 	if trigger.Spec.Prefix != nil && *trigger.Spec.Prefix != "" {
 		prefix := *trigger.Spec.Prefix
 		if prefix[len(prefix)-1] == '/' {
@@ -85,13 +113,13 @@ func deriveRouteShape(trigger *fv1.HTTPTrigger) routeShape {
 // claimsHome reports whether this shape claims "GET /" exactly, which
 // suppresses the router-owned GKE-ingress health fallback on "/".
 func (s routeShape) claimsHome() bool {
-	return s.prefixPath == "" && s.exactPath == "/" &&
+	return s.prefixPath == "" && s.exactPath == "/" && s.match == nil &&
 		len(s.methods) == 1 && s.methods[0] == http.MethodGet
 }
 
 // registerRouteShape registers a shape onto a mux with the given handler:
 // up to two httpmux routes (exact and/or prefix), each gated by the shape's
-// methods and optional host.
+// methods, optional host and optional match rules.
 //
 // The method slice is cloned per registration. httpmux's Route.Methods does
 // not mutate its argument (it compares case-insensitively at match time), but
@@ -106,17 +134,19 @@ func (s routeShape) claimsHome() bool {
 // empty set would silently widen the route to match every method — see
 // TestRouteShapeEmptyMethods / httpmux TestEmptyMethodsMatchesNothing.
 func registerRouteShape(m *httpmux.Mux, shape routeShape, handler http.Handler) {
-	if shape.exactPath != "" {
-		route := m.Handle(shape.exactPath, handler).Methods(slices.Clone(shape.methods)...)
+	restrict := func(route *httpmux.Route) {
 		if shape.host != "" {
 			route.Host(shape.host)
 		}
+		if shape.match != nil {
+			route.MatcherFunc(shape.match.Matches)
+		}
+	}
+	if shape.exactPath != "" {
+		restrict(m.Handle(shape.exactPath, handler).Methods(slices.Clone(shape.methods)...))
 	}
 	if shape.prefixPath != "" {
-		route := m.HandlePrefix(shape.prefixPath, handler).Methods(slices.Clone(shape.methods)...)
-		if shape.host != "" {
-			route.Host(shape.host)
-		}
+		restrict(m.HandlePrefix(shape.prefixPath, handler).Methods(slices.Clone(shape.methods)...))
 	}
 }
 
@@ -230,12 +260,16 @@ func (ts *HTTPTriggerSet) newFunctionHandlerBase(routeName string, functionMap m
 
 // buildTriggerHandler constructs the proxy handler for one trigger from its
 // resolve result: the functionHandler with hoisted per-route state
-// (RFC-0014) plus the per-trigger CORS wrap. fnTimeoutMap may be the global
-// map (one-shot buildMuxes) or a per-trigger map derived from the resolved
-// functions (incremental path) — the handler only ever looks up its own backends.
+// (RFC-0014) plus the per-trigger auth and CORS wraps. fnTimeoutMap may be
+// the global map (one-shot buildMuxes) or a per-trigger map derived from the
+// resolved functions (incremental path) — the handler only ever looks up its
+// own backends.
 func (ts *HTTPTriggerSet) buildTriggerHandler(trigger *fv1.HTTPTrigger, rr *resolveResult, fnTimeoutMap map[crd.CacheKeyUG]int) http.Handler {
 	fh := ts.newFunctionHandlerBase(trigger.Name, rr.functionMap, rr.functionWtDistributionList, fnTimeoutMap, rr.stickySource)
 	fh.httpTrigger = trigger
+	fh.rateLimiter = ts.rateLimiterFor(trigger)
+	fh.cache = ts.responseCacheFor(trigger)
+	fh.mirror = ts.newTrafficMirror(trigger.Name, rr.mirror)
 
 	// For FunctionReferenceTypeFunctionName the backend is fixed at build
 	// time; for FunctionReferenceTypeFunctionWeights (canary) the handler
@@ -247,6 +281,12 @@ func (ts *HTTPTriggerSet) buildTriggerHandler(trigger *fv1.HTTPTrigger, rr *reso
 	}
 
 	var handler http.Handler = http.HandlerFunc(fh.handler)
+	if authMW := ts.newTriggerAuth(trigger); authMW != nil {
+		// Inside the CORS wrap: a preflight carries no credentials and must
+		// be answered without them, and a 401/403 still needs the CORS
+		// headers for the browser to let the page read it.
+		handler = authMW(handler)
+	}
 	if trigger.Spec.CorsConfig != nil {
 		// The allowlist's method fallback uses the trigger's methods
 		// WITHOUT the OPTIONS the shape derivation appends for routing.
@@ -276,6 +316,7 @@ func (ts *HTTPTriggerSet) buildInternalFunctionHandler(fn *fv1.Function, fnTimeo
 // signed direct caller — not just HTTPTrigger routes.
 func (ts *HTTPTriggerSet) buildInternalAliasHandler(routeName string, rr *resolveResult, fnTimeoutMap map[crd.CacheKeyUG]int) http.Handler {
 	fh := ts.newFunctionHandlerBase(routeName, rr.functionMap, rr.functionWtDistributionList, fnTimeoutMap, rr.stickySource)
+	fh.mirror = ts.newTrafficMirror(routeName, rr.mirror)
 	if rr.resolveResultType == resolveResultSingleFunction {
 		for _, fn := range fh.functionMap {
 			fh.function = fn
@@ -415,3 +415,87 @@ func TestRouteShapeInvalidTemplatesRejected(t *testing.T) {
 		assert.Equal(t, fv1.HTTPTriggerReasonInvalidRouteTemplate, reason)
 	}
 }
+
+// TestRouteShapeMatchRules pins the Match block's registration: the route
+// serves only requests meeting its conditions, a broader trigger on the same
+// path keeps the rest, and a match that does not compile is rejected by
+// config validation instead of widening the route.
+func TestRouteShapeMatchRules(t *testing.T) {
+	ts := newShapeTS(t, []fv1.Function{shapeFn("fn")}, []fv1.HTTPTrigger{
+		shapeTrigger("v2", func(tr *fv1.HTTPTrigger) {
+			tr.Spec.RelativeURL = "/versioned"
+			tr.Spec.Match = &fv1.HTTPTriggerMatch{
+				Headers: []fv1.HTTPMatchCondition{{Name: "X-Api-Version", Value: "2"}},
+				Hosts:   []string{"*.example.com"},
+			}
+		}),
+		shapeTrigger("bad-regex", func(tr *fv1.HTTPTrigger) {
+			tr.Spec.RelativeURL = "/bad-regex"
+			tr.Spec.Match = &fv1.HTTPTriggerMatch{
+				QueryParams: []fv1.HTTPMatchCondition{{Name: "v", Type: fv1.HTTPMatchRegex, Value: "(("}},
+			}
+		}),
+	})
+	public, _, err := ts.buildMuxes(t.Context(), nil)
+	require.NoError(t, err)
+
+	reqMatches := func(host, version string) bool {
+		req := httptest.NewRequest(http.MethodGet, "/versioned", nil)
+		req.Host = host
+		if version != "" {
+			req.Header.Set("X-Api-Version", version)
+		}
+		_, ok := public.Match(req)
+		return ok
+	}
+	assert.True(t, reqMatches("api.example.com:8080", "2"), "matching host and header must route")
+	assert.False(t, reqMatches("api.example.com", "1"), "a header mismatch must not route")
+	assert.False(t, reqMatches("example.com", "2"), "a wildcard host needs a label before the suffix")
+	assert.False(t, muxMatches(public, http.MethodGet, "/bad-regex"), "an uncompilable match must skip the route")
+
+	bad := shapeTrigger("bad-regex", func(tr *fv1.HTTPTrigger) {
+		tr.Spec.RelativeURL = "/bad-regex"
+		tr.Spec.Match = &fv1.HTTPTriggerMatch{
+			QueryParams: []fv1.HTTPMatchCondition{{Name: "v", Type: fv1.HTTPMatchRegex, Value: "(("}},
+		}
+	})
+	reason, err := triggerConfigError(&bad)
+	require.Error(t, err)
+	assert.Equal(t, fv1.HTTPTriggerReasonInvalidMatch, reason)
+}
+
+// TestRouteShapeGRPC pins the gRPC form: the route is the service's
+// "/pkg.Service/" path, POST only, narrowed to one method by an exact path and
+// to several by a method condition on the prefix. A gRPC trigger that asks
+// for any other HTTP method is rejected by config validation.
+func TestRouteShapeGRPC(t *testing.T) {
+	ts := newShapeTS(t, []fv1.Function{shapeFn("fn")}, []fv1.HTTPTrigger{
+		shapeTrigger("all", func(tr *fv1.HTTPTrigger) {
+			tr.Spec.Methods = nil
+			tr.Spec.GRPC = &fv1.HTTPTriggerGRPC{Service: "pkg.All"}
+		}),
+		shapeTrigger("one", func(tr *fv1.HTTPTrigger) {
+			tr.Spec.Methods = nil
+			tr.Spec.GRPC = &fv1.HTTPTriggerGRPC{Service: "pkg.One", Methods: []string{"Get"}}
+		}),
+		shapeTrigger("get", func(tr *fv1.HTTPTrigger) {
+			tr.Spec.GRPC = &fv1.HTTPTriggerGRPC{Service: "pkg.Get"}
+		}),
+		shapeTrigger("some", func(tr *fv1.HTTPTrigger) {
+			tr.Spec.Methods = nil
+			tr.Spec.GRPC = &fv1.HTTPTriggerGRPC{Service: "pkg.Some", Methods: []string{"Put", "Get"}}
+		}),
+	})
+	public, _, err := ts.buildMuxes(t.Context(), nil)
+	require.NoError(t, err)
+
+	assert.True(t, muxMatches(public, http.MethodPost, "/pkg.All/Anything"))
+	assert.False(t, muxMatches(public, http.MethodGet, "/pkg.All/Anything"), "gRPC is POST only")
+	assert.False(t, muxMatches(public, http.MethodPost, "/pkg.Get/Anything"), "a non-POST gRPC trigger must skip the route")
+	assert.True(t, muxMatches(public, http.MethodPost, "/pkg.One/Get"))
+	assert.False(t, muxMatches(public, http.MethodPost, "/pkg.One/Put"))
+	assert.True(t, muxMatches(public, http.MethodPost, "/pkg.Some/Get"))
+	assert.True(t, muxMatches(public, http.MethodPost, "/pkg.Some/Put"))
+	assert.False(t, muxMatches(public, http.MethodPost, "/pkg.Some/Delete"))
+	assert.False(t, muxMatches(public, http.MethodPost, "/pkg.Some/Get/extra"))
+}
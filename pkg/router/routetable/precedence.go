@@ -18,16 +18,26 @@ import (
 // materializer controls precedence purely through registration order. Until
 // this phase the order was an accident of cache list order; it is now:
 //
-//  1. Host-qualified routes before host-less routes.
+//  1. Host-qualified routes before host-less routes. A route is
+//     host-qualified by Host or by Match hosts; within the class, routes
+//     whose hosts are all exact come before routes with a wildcard host.
 //  2. Exact paths before prefixes.
 //  3. Among prefixes, longest prefix first.
-//  4. Method sets filter rather than rank (a non-matching method falls
+//  4. Among routes left tied by 1-3, more header conditions first, then
+//     more query-parameter conditions: the narrower match gets the first
+//     look, and a request it rejects falls through to the broader one (a
+//     header-matched X-Api-Version: 2 trigger ahead of the plain trigger on
+//     the same path). Match conditions, like methods, filter: they never
+//     outrank the path rules above.
+//  5. Method sets filter rather than rank (a non-matching method falls
 //     through to the next route / 405, exactly as the mux already behaves).
-//  5. Exact-duplicate shapes (same host + same path/prefix, overlapping
-//     methods): oldest creationTimestamp first, then lexicographic
-//     namespace/name. The loser stays registered (shadowed — it starts
-//     serving the moment the winner is deleted) and is reported as a
-//     Conflict so the router can set RouteAdmitted=False/RouteConflict.
+//  6. Exact-duplicate shapes (same host + same path/prefix + same match
+//     conditions, overlapping methods): oldest creationTimestamp first, then
+//     lexicographic namespace/name. The loser stays registered (shadowed —
+//     it starts serving the moment the winner is deleted) and is reported as
+//     a Conflict so the router can set RouteAdmitted=False/RouteConflict.
+//     Routes whose match conditions differ are not duplicates even when
+//     both accept some request; rule 4 decides which serves it.
 //
 // For non-overlapping route sets — the overwhelmingly common case — this
 // ordering is behavior-identical to any other, since order only matters when
@@ -40,10 +50,33 @@ type Route struct {
 	Path    string
 	Host    string
 	Methods []string
+	Match   *RequestMatch
 	Handler *HandlerRef
 	Owner   types.NamespacedName // owning trigger, for logs
 }
 
+// hostRank orders rule 1's host classes: 2 for an exact host (Host, or Match
+// hosts that are all exact), 1 for a wildcard Match host, 0 for none.
+func (r Route) hostRank() int {
+	switch {
+	case r.Host != "" || (r.Match != nil && r.Match.Hosts && !r.Match.WildcardHost):
+		return 2
+	case r.Match != nil && r.Match.Hosts:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// conditions returns the header and query-parameter condition counts rule 4
+// ranks by.
+func (r Route) conditions() (headers, query int) {
+	if r.Match == nil {
+		return 0, 0
+	}
+	return r.Match.Headers, r.Match.QueryParams
+}
+
 // Conflict reports a shadowed route: Loser registered the same shape as
 // Winner (with overlapping methods) and lost the precedence tiebreak.
 type Conflict struct {
@@ -77,16 +110,18 @@ func (t *Table) Materialization() Materialization {
 		if spec.ExactPath != "" {
 			exact = append(exact, Route{
 				Exact: true, Path: spec.ExactPath, Host: spec.Host,
-				Methods: spec.Methods, Handler: spec.Handler, Owner: owner,
+				Methods: spec.Methods, Match: spec.Match, Handler: spec.Handler, Owner: owner,
 			})
 		}
 		if spec.PrefixPath != "" {
 			prefix = append(prefix, Route{
 				Path: spec.PrefixPath, Host: spec.Host,
-				Methods: spec.Methods, Handler: spec.Handler, Owner: owner,
+				Methods: spec.Methods, Match: spec.Match, Handler: spec.Handler, Owner: owner,
 			})
 		}
-		if spec.PrefixPath == "" && spec.ExactPath == "/" &&
+		// A matched GET / serves only some requests for "/"; the fallback
+		// must keep answering the rest.
+		if spec.PrefixPath == "" && spec.ExactPath == "/" && spec.Match == nil &&
 			len(spec.Methods) == 1 && spec.Methods[0] == http.MethodGet {
 			m.HomeClaimed = true
 		}
@@ -110,19 +145,25 @@ func (t *Table) Materialization() Materialization {
 		return cmpNamespacedName(a.Owner, b.Owner)
 	}
 	hostedFirst := func(a, b Route) int {
-		switch {
-		case a.Host != "" && b.Host == "":
-			return -1
-		case a.Host == "" && b.Host != "":
-			return 1
-		default:
-			return 0
+		return b.hostRank() - a.hostRank()
+	}
+	// narrowestFirst is rule 4: more header conditions, then more query
+	// conditions.
+	narrowestFirst := func(a, b Route) int {
+		ah, aq := a.conditions()
+		bh, bq := b.conditions()
+		if ah != bh {
+			return bh - ah
 		}
+		return bq - aq
 	}
 	slices.SortFunc(exact, func(a, b Route) int {
 		if c := hostedFirst(a, b); c != 0 {
 			return c
 		}
+		if c := narrowestFirst(a, b); c != 0 {
+			return c
+		}
 		return byCreation(a, b)
 	})
 	slices.SortFunc(prefix, func(a, b Route) int {
@@ -133,6 +174,9 @@ func (t *Table) Materialization() Materialization {
 		if len(a.Path) != len(b.Path) {
 			return len(b.Path) - len(a.Path)
 		}
+		if c := narrowestFirst(a, b); c != 0 {
+			return c
+		}
 		return byCreation(a, b)
 	})
 
@@ -141,7 +185,7 @@ func (t *Table) Materialization() Materialization {
 	// a host class.
 	split := func(rs []Route) (hosted, hostless []Route) {
 		for _, r := range rs {
-			if r.Host != "" {
+			if r.hostRank() > 0 {
 				hosted = append(hosted, r)
 			} else {
 				hostless = append(hostless, r)
@@ -162,8 +206,8 @@ func (t *Table) Materialization() Materialization {
 }
 
 // findConflicts scans the precedence-ordered routes for exact-duplicate
-// shapes: same kind (exact/prefix), host, and path, with overlapping method
-// sets. The first occurrence (highest precedence) is the winner; later ones
+// shapes: same kind (exact/prefix), host, path and match conditions, with
+// overlapping method sets. The first occurrence (highest precedence) is the winner; later ones
 // are shadowed. A trigger is reported at most once even if both halves of
 // its dual registration are shadowed.
 func findConflicts(routes []Route) []Conflict {
@@ -171,12 +215,13 @@ func findConflicts(routes []Route) []Conflict {
 		exact bool
 		host  string
 		path  string
+		match string
 	}
 	winners := make(map[key]Route)
 	var conflicts []Conflict
 	seenLoser := make(map[types.NamespacedName]struct{})
 	for _, r := range routes {
-		k := key{exact: r.Exact, host: r.Host, path: r.Path}
+		k := key{exact: r.Exact, host: r.Host, path: r.Path, match: r.Match.key()}
 		w, taken := winners[k]
 		if !taken {
 			winners[k] = r
@@ -225,5 +270,10 @@ func (r Route) String() string {
 	if !r.Exact {
 		sb.WriteString("*")
 	}
+	if r.Match != nil {
+		sb.WriteString(" [")
+		sb.WriteString(r.Match.Key)
+		sb.WriteString("]")
+	}
 	return sb.String()
 }
@@ -167,3 +167,74 @@ func TestMaterializationHomeClaim(t *testing.T) {
 	applySpec(t, tbl, pSpec("home", t0, func(s *RouteSpec) { s.ExactPath = "/" }))
 	assert.True(t, tbl.Materialization().HomeClaimed)
 }
+
+// TestMaterializationMatchPrecedence pins rule 4 and the Match side of rules
+// 1 and 6: narrower match conditions come first without outranking the path
+// rules, Match hosts rank with Host, and only identical conditions conflict.
+func TestMaterializationMatchPrecedence(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	v2 := &RequestMatch{Key: "header:X-Api-Version=exact:2", Headers: 1}
+
+	t.Run("order", func(t *testing.T) {
+		tbl := New()
+		applySpec(t, tbl, pSpec("plain", t0, func(s *RouteSpec) { s.ExactPath = "/api" }))
+		applySpec(t, tbl, pSpec("query", t0, func(s *RouteSpec) {
+			s.ExactPath = "/api"
+			s.Match = &RequestMatch{Key: "query:beta=present:", QueryParams: 1}
+		}))
+		applySpec(t, tbl, pSpec("header", t0.Add(time.Hour), func(s *RouteSpec) {
+			s.ExactPath = "/api"
+			s.Match = v2
+		}))
+		applySpec(t, tbl, pSpec("wildcard-host", t0, func(s *RouteSpec) {
+			s.ExactPath = "/api"
+			s.Match = &RequestMatch{Key: "host:*.example.com", Hosts: true, WildcardHost: true}
+		}))
+		applySpec(t, tbl, pSpec("exact-host", t0, func(s *RouteSpec) {
+			s.ExactPath = "/api"
+			s.Match = &RequestMatch{Key: "host:api.example.com", Hosts: true}
+		}))
+		applySpec(t, tbl, pSpec("long-prefix", t0, func(s *RouteSpec) { s.PrefixPath = "/api/v1/" }))
+		applySpec(t, tbl, pSpec("short-prefix-header", t0, func(s *RouteSpec) {
+			s.PrefixPath = "/api/"
+			s.Match = v2
+		}))
+
+		m := tbl.Materialization()
+		assert.Equal(t, []string{
+			"exact-host:exact",
+			"wildcard-host:exact",
+			"header:exact", // younger, but narrower than query and plain
+			"query:exact",
+			"plain:exact",
+			"long-prefix:prefix", // prefix length outranks match conditions
+			"short-prefix-header:prefix",
+		}, routeOrder(m))
+		assert.Empty(t, m.Conflicts, "differing match conditions are distinct shapes")
+	})
+
+	t.Run("identical conditions conflict", func(t *testing.T) {
+		tbl := New()
+		applySpec(t, tbl, pSpec("younger", t0.Add(time.Hour), func(s *RouteSpec) {
+			s.ExactPath = "/api"
+			s.Match = v2
+		}))
+		applySpec(t, tbl, pSpec("older", t0, func(s *RouteSpec) {
+			s.ExactPath = "/api"
+			s.Match = &RequestMatch{Key: v2.Key, Headers: 1}
+		}))
+		m := tbl.Materialization()
+		require.Len(t, m.Conflicts, 1)
+		assert.Equal(t, "younger", m.Conflicts[0].Loser.Name)
+		assert.Equal(t, "older", m.Conflicts[0].Winner.Name)
+	})
+
+	t.Run("matched home does not claim the fallback", func(t *testing.T) {
+		tbl := New()
+		applySpec(t, tbl, pSpec("home", t0, func(s *RouteSpec) {
+			s.ExactPath = "/"
+			s.Match = v2
+		}))
+		assert.False(t, tbl.Materialization().HomeClaimed)
+	})
+}
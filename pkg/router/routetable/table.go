@@ -4,7 +4,7 @@
 
 // Package routetable holds the router's incremental route state (RFC-0013).
 //
-// It splits a route's SHAPE (path/prefix/methods/host — changes rarely,
+// It splits a route's SHAPE (path/prefix/methods/host/match — changes rarely,
 // human-driven) from its HANDLER (function snapshots, canary weights —
 // changes constantly). Handlers live behind a stable HandlerRef registered
 // into the httpmux mux once per shape; the steady-churn class (canary weight
@@ -91,6 +91,9 @@ type RouteSpec struct {
 	PrefixPath string
 	Host       string
 	Methods    []string // sorted by the caller
+	// Match is the trigger's request conditions beyond path, methods and
+	// Host (its Match block); nil when it has none.
+	Match *RequestMatch
 
 	// Created is the trigger's creationTimestamp — the phase-2 precedence
 	// tiebreak for exact-duplicate shapes.
@@ -131,12 +134,45 @@ type RouteSpec struct {
 	Handler *HandlerRef
 }
 
+// RequestMatch is a route's request conditions beyond path, methods and
+// Host: headers, query parameters and router-matched hosts. The table does
+// not interpret them — the caller compiles them into Matches — and reads only
+// Key, which shape equality and conflict detection compare, and the counts
+// precedence ranks by.
+type RequestMatch struct {
+	// Key is the conditions' canonical form: two matches with equal keys
+	// accept exactly the same requests.
+	Key string
+	// Hosts reports whether the conditions restrict the host, and
+	// WildcardHost whether any of those hosts is a wildcard. A route with
+	// only exact hosts ranks with Host-qualified routes, one with a
+	// wildcard just below them.
+	Hosts        bool
+	WildcardHost bool
+	// Headers and QueryParams count the conditions of each kind.
+	Headers     int
+	QueryParams int
+	// Matches reports whether a request meets every condition.
+	Matches func(*http.Request) bool
+}
+
+// key is m's Key; "" for a nil match.
+func (m *RequestMatch) key() string {
+	if m == nil {
+		return ""
+	}
+	return m.Key
+}
+
 // shapeEqual reports whether two specs register identical mux routes.
+// Matches funcs are not compared: equal keys make them equivalent, and the
+// spec kept on a NoChange or HandlerSwapped apply carries its own.
 func (s *RouteSpec) shapeEqual(o *RouteSpec) bool {
 	return s.ExactPath == o.ExactPath &&
 		s.PrefixPath == o.PrefixPath &&
 		s.Host == o.Host &&
-		slices.Equal(s.Methods, o.Methods)
+		slices.Equal(s.Methods, o.Methods) &&
+		s.Match.key() == o.Match.key()
 }
 
 // InternalKey identifies one internal-listener route
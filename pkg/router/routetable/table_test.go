@@ -172,6 +172,17 @@ func TestApplyTriggerDecisionTable(t *testing.T) {
 			s.Host = "api.example.com"
 		}), func() http.Handler { return tagHandler("v3") })
 		assert.Equal(t, ShapeChanged, res)
+		withMatch := func(gen int64, key string) *RouteSpec {
+			return spec("u1", gen, nil, func(s *RouteSpec) {
+				s.Methods = []string{http.MethodGet, http.MethodPost}
+				s.Host = "api.example.com"
+				s.Match = &RequestMatch{Key: key, Headers: 1}
+			})
+		}
+		res = tbl.ApplyTrigger(withMatch(4, "header:X-Api-Version=exact:2"), func() http.Handler { return tagHandler("v4") })
+		assert.Equal(t, ShapeChanged, res, "a match change is a shape change")
+		res = tbl.ApplyTrigger(withMatch(5, "header:X-Api-Version=exact:2"), func() http.Handler { return tagHandler("v5") })
+		assert.Equal(t, HandlerSwapped, res, "an equal match key is the same shape")
 	})
 
 	t.Run("delete is ShapeChanged once, NoChange after", func(t *testing.T) {
@@ -8,9 +8,14 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"k8s.io/apimachinery/pkg/types"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
 	ferror "github.com/fission/fission/pkg/error"
@@ -24,9 +29,20 @@ import (
 // Tapper: their accounting is router-local via ResolvedEntry.Release, and the
 // two must never mix (see the resolver docs) — UnTap here is only for
 // executor-resolved poolmgr entries. Tap (atime liveness) applies to both.
+//
+// Begin counts a request to fn as in flight until the returned end is
+// called; taps carry the count, which is how the executor's auto
+// provisioned concurrency sees the demand the router serves from its own
+// endpoint index. InFlight reads that count back for the function with the
+// given UID; the drain endpoint falls back to it when there is no endpoint
+// index to count per pod. Forget drops the counts of a function that left
+// the route table.
 type Tapper interface {
 	Tap(fn *fv1.Function, serviceURL *url.URL)
 	UnTap(ctx context.Context, fn *fv1.Function, serviceURL *url.URL) error
+	Begin(fn *fv1.Function) (end func())
+	InFlight(fnUID types.UID) int64
+	Forget(fn types.NamespacedName)
 }
 
 // executorTapper taps/untaps through the executor client (today's behavior).
@@ -34,6 +50,21 @@ type executorTapper struct {
 	logger       logr.Logger
 	executor     eclient.ClientInterface
 	unTapTimeout time.Duration
+
+	// inflight holds each function's in-flight request count by UID. An
+	// entry lives until its function is deleted (Forget) and its last
+	// request ends.
+	inflight sync.Map // types.UID -> *inflightCounter
+}
+
+// inflightCounter is one function's in-flight request count, with the
+// attributes of its fission_function_inflight_requests series. gone marks a
+// deleted function's counter, removed when the count drops to zero.
+type inflightCounter struct {
+	atomic.Int64
+	fn    types.NamespacedName
+	attrs metric.MeasurementOption
+	gone  atomic.Bool
 }
 
 // Tap enqueues a batched tap for the service address.
@@ -41,7 +72,61 @@ func (t *executorTapper) Tap(fn *fv1.Function, serviceURL *url.URL) {
 	if t.executor == nil || serviceURL == nil {
 		return
 	}
-	t.executor.TapService(fn.ObjectMeta, fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType, *serviceURL)
+	t.executor.TapService(fn.ObjectMeta, fn.Spec.InvokeStrategy.ExecutionStrategy.ExecutorType, *serviceURL,
+		int(t.counter(fn).Load()))
+}
+
+// Begin counts a request to fn as in flight until end is called.
+func (t *executorTapper) Begin(fn *fv1.Function) (end func()) {
+	c := t.counter(fn)
+	c.Add(1)
+	functionInflight.Add(context.Background(), 1, c.attrs)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if c.Add(-1) == 0 && c.gone.Load() {
+				t.inflight.CompareAndDelete(fn.UID, c)
+			}
+			functionInflight.Add(context.Background(), -1, c.attrs)
+		})
+	}
+}
+
+// InFlight returns the function's in-flight request count, 0 for a function
+// this router has not served.
+func (t *executorTapper) InFlight(fnUID types.UID) int64 {
+	if c, ok := t.inflight.Load(fnUID); ok {
+		return c.(*inflightCounter).Load()
+	}
+	return 0
+}
+
+// Forget drops the deleted function's counters. One with requests still in
+// flight is removed by the last of them to end.
+func (t *executorTapper) Forget(fn types.NamespacedName) {
+	t.inflight.Range(func(uid, v any) bool {
+		if c := v.(*inflightCounter); c.fn == fn {
+			c.gone.Store(true)
+			if c.Load() == 0 {
+				t.inflight.CompareAndDelete(uid, c)
+			}
+		}
+		return true
+	})
+}
+
+func (t *executorTapper) counter(fn *fv1.Function) *inflightCounter {
+	if c, ok := t.inflight.Load(fn.UID); ok {
+		return c.(*inflightCounter)
+	}
+	c, _ := t.inflight.LoadOrStore(fn.UID, &inflightCounter{
+		fn: types.NamespacedName{Namespace: fn.Namespace, Name: fn.Name},
+		attrs: metric.WithAttributes(
+			attribute.String("function_namespace", fn.Namespace),
+			attribute.String("function_name", fn.Name),
+		),
+	})
+	return c.(*inflightCounter)
 }
 
 // UnTap marks the serviceURL in executor's cache as inactive, so that it can be reused.
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/utils/loggerfactory"
+)
+
+func TestExecutorTapperReportsInflight(t *testing.T) {
+	exec := &countingExecutor{}
+	tapper := &executorTapper{logger: loggerfactory.GetLogger(), executor: exec}
+	fn := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "default", UID: "uid-fn"}}
+	other := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "uid-other"}}
+	u := &url.URL{Scheme: "http", Host: "10.0.0.1:8888"}
+
+	end1 := tapper.Begin(fn)
+	end2 := tapper.Begin(fn)
+	tapper.Begin(other)
+	tapper.Tap(fn, u)
+	assert.EqualValues(t, 2, exec.inflight.Load(), "requests to other functions are not counted")
+
+	end1()
+	end1()
+	tapper.Tap(fn, u)
+	assert.EqualValues(t, 1, exec.inflight.Load(), "end is idempotent")
+
+	end2()
+	tapper.Tap(fn, u)
+	assert.Zero(t, exec.inflight.Load())
+}
+
+func TestExecutorTapperForget(t *testing.T) {
+	tapper := &executorTapper{logger: loggerfactory.GetLogger()}
+	idle := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default", UID: "uid-idle"}}
+	busy := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "busy", Namespace: "default", UID: "uid-busy"}}
+	kept := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "kept", Namespace: "default", UID: "uid-kept"}}
+	tapper.Begin(idle)()
+	end := tapper.Begin(busy)
+	tapper.Begin(kept)()
+
+	tapper.Forget(types.NamespacedName{Namespace: "default", Name: "idle"})
+	tapper.Forget(types.NamespacedName{Namespace: "default", Name: "busy"})
+	_, ok := tapper.inflight.Load(idle.UID)
+	assert.False(t, ok, "an idle deleted function's counter is removed")
+	assert.EqualValues(t, 1, tapper.InFlight(busy.UID), "a request in flight keeps its counter")
+	_, ok = tapper.inflight.Load(kept.UID)
+	assert.True(t, ok, "other functions' counters are kept")
+
+	end()
+	_, ok = tapper.inflight.Load(busy.UID)
+	assert.False(t, ok, "the last request to end removes a deleted function's counter")
+}
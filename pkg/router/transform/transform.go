@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package transform applies an HTTPTrigger's declarative transform
+// (fv1.HTTPTriggerTransform) to a request or response. It has no router
+// state, so the router and `fission httptrigger test`, which previews a
+// transform without a cluster round trip, share one implementation.
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// ErrBodyTooLarge is returned by Request when a body template has to read a
+// request body larger than fv1.MaxHTTPTransformRequestBytes.
+var ErrBodyTooLarge = errors.New("request body exceeds the transform limit")
+
+// Request applies t to req in place. path is the path the function would
+// receive without the transform (fv1.HTTPTriggerSpec.ForwardedPath) and
+// params the variables of the trigger's path template; both feed the
+// {{path}} and {{param.*}} placeholders.
+//
+// Every template is expanded against the request as it arrived before
+// anything is changed, so a header template reads the client's header even
+// when the same transform removes or sets it.
+func Request(req *http.Request, t *fv1.HTTPRequestTransform, path string, params map[string]string) error {
+	if t == nil {
+		return nil
+	}
+	v := values{path: path, params: params, query: req.URL.Query(), header: req.Header.Clone()}
+
+	var body []byte
+	if t.Body != "" {
+		raw, err := io.ReadAll(io.LimitReader(req.Body, fv1.MaxHTTPTransformRequestBytes+1))
+		if err != nil {
+			return fmt.Errorf("reading request body: %w", err)
+		}
+		if int64(len(raw)) > fv1.MaxHTTPTransformRequestBytes {
+			return ErrBodyTooLarge
+		}
+		if mt, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mt == "application/x-www-form-urlencoded" {
+			// A malformed pair is dropped; the rest of the form still counts.
+			v.form, _ = url.ParseQuery(string(raw))
+		}
+		body = []byte(v.expand(t.Body, jsonString))
+	}
+
+	var rawPath string
+	if t.Path != "" {
+		escapedBase := (&url.URL{Path: path}).EscapedPath()
+		rawPath = fv1.HTTPTransformPlaceholder.ReplaceAllStringFunc(t.Path, func(m string) string {
+			if m[2:len(m)-2] == "path" {
+				return escapedBase
+			}
+			return url.PathEscape(v.lookup(m[2 : len(m)-2]))
+		})
+	}
+
+	if body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		req.ContentLength = int64(len(body))
+		req.TransferEncoding = nil
+		req.Header.Del("Content-Encoding")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	if h := t.Headers; h != nil {
+		apply(req.Header, h, func(s string) string { return v.expand(s, headerValue) })
+	}
+	if rawPath != "" {
+		decoded, err := url.PathUnescape(rawPath)
+		if err != nil {
+			return fmt.Errorf("path template %q: %w", t.Path, err)
+		}
+		req.URL.Path, req.URL.RawPath = decoded, rawPath
+	}
+	return nil
+}
+
+// Response applies t to a response's headers. Its values are literal.
+func Response(h http.Header, t *fv1.HTTPResponseTransform) {
+	if t == nil || t.Headers == nil {
+		return
+	}
+	apply(h, t.Headers, func(s string) string { return s })
+}
+
+func apply(h http.Header, t *fv1.HTTPHeaderTransform, value func(string) string) {
+	for _, name := range t.Remove {
+		h.Del(name)
+	}
+	for _, hv := range t.Set {
+		h.Set(hv.Name, value(hv.Value))
+	}
+	for _, hv := range t.Add {
+		h.Add(hv.Name, value(hv.Value))
+	}
+}
+
+// values are what placeholders read: the request as it arrived.
+type values struct {
+	path   string
+	params map[string]string
+	query  url.Values
+	header http.Header
+	form   url.Values
+}
+
+// lookup resolves a placeholder (what is between the braces). Validation
+// has rejected unknown sources; a source or name with no value is "".
+func (v *values) lookup(placeholder string) string {
+	source, name, _ := strings.Cut(placeholder, ".")
+	switch source {
+	case "path":
+		return v.path
+	case "param":
+		return v.params[name]
+	case "query":
+		return v.query.Get(name)
+	case "header":
+		return v.header.Get(name)
+	case "form":
+		return v.form.Get(name)
+	}
+	return ""
+}
+
+func (v *values) expand(tmpl string, escape func(string) string) string {
+	return fv1.HTTPTransformPlaceholder.ReplaceAllStringFunc(tmpl, func(m string) string {
+		return escape(v.lookup(m[2 : len(m)-2]))
+	})
+}
+
+// jsonString escapes s for the inside of a JSON string.
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b[1 : len(b)-1])
+}
+
+// headerValue keeps a value copied into a header on one line: a CR or LF
+// from the client would otherwise make the transport reject the request.
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+func TestRequestHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/hook?tenant=acme", nil)
+	req.Header.Set("Cookie", "session=1")
+	req.Header.Set("X-Legacy-User", "bob")
+	req.Header.Add("X-Tag", "a")
+
+	err := Request(req, &fv1.HTTPRequestTransform{Headers: &fv1.HTTPHeaderTransform{
+		Remove: []string{"cookie", "X-Legacy-User"},
+		Set: []fv1.HTTPHeaderValue{
+			{Name: "X-User", Value: "{{header.X-Legacy-User}}"},
+			{Name: "X-Tenant", Value: "{{query.tenant}}"},
+			{Name: "X-Missing", Value: "<{{query.nope}}>"},
+		},
+		Add: []fv1.HTTPHeaderValue{{Name: "X-Tag", Value: "b"}},
+	}}, "/", nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, req.Header.Get("Cookie"))
+	assert.Empty(t, req.Header.Get("X-Legacy-User"))
+	assert.Equal(t, "bob", req.Header.Get("X-User"), "templates read the request as it arrived, before Remove")
+	assert.Equal(t, "acme", req.Header.Get("X-Tenant"))
+	assert.Equal(t, "<>", req.Header.Get("X-Missing"))
+	assert.Equal(t, []string{"a", "b"}, req.Header.Values("X-Tag"))
+}
+
+func TestRequestHeaderValueStaysOnOneLine(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?v=a%0D%0AX-Injected:%201", nil)
+	require.NoError(t, Request(req, &fv1.HTTPRequestTransform{Headers: &fv1.HTTPHeaderTransform{
+		Set: []fv1.HTTPHeaderValue{{Name: "X-V", Value: "{{query.v}}"}},
+	}}, "/", nil))
+	assert.Equal(t, "a  X-Injected: 1", req.Header.Get("X-V"))
+}
+
+func TestRequestPath(t *testing.T) {
+	for _, tc := range []struct {
+		name, tmpl, base, want, wantRaw string
+		params                          map[string]string
+	}{
+		{name: "param", tmpl: "/v2/users/{{param.id}}", base: "/", params: map[string]string{"id": "42"}, want: "/v2/users/42"},
+		{name: "forwarded path", tmpl: "/api{{path}}", base: "/orders/7", want: "/api/orders/7"},
+		{name: "value escaped as one segment", tmpl: "/files/{{param.name}}", base: "/", params: map[string]string{"name": "a/b c"},
+			want: "/files/a/b c", wantRaw: "/files/a%2Fb%20c"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/anything", nil)
+			require.NoError(t, Request(req, &fv1.HTTPRequestTransform{Path: tc.tmpl}, tc.base, tc.params))
+			assert.Equal(t, tc.want, req.URL.Path)
+			if tc.wantRaw != "" {
+				assert.Equal(t, tc.wantRaw, req.URL.EscapedPath())
+			}
+		})
+	}
+}
+
+func TestRequestBodyFromForm(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/hook?src=legacy", strings.NewReader(`user=bob&note=say+"hi"%0A`))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	req.Header.Set("Content-Encoding", "identity")
+
+	require.NoError(t, Request(req, &fv1.HTTPRequestTransform{
+		Body: `{"user": "{{form.user}}", "note": "{{form.note}}", "source": "{{query.src}}"}`,
+	}, "/", nil))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"user": "bob", "note": "say \"hi\"\n", "source": "legacy"}`, string(body))
+	assert.Equal(t, int64(len(body)), req.ContentLength)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Empty(t, req.Header.Get("Content-Encoding"))
+
+	again, err := req.GetBody()
+	require.NoError(t, err)
+	replay, _ := io.ReadAll(again)
+	assert.Equal(t, body, replay, "GetBody must replay the new body")
+}
+
+func TestRequestBodyNotFormLeavesFormEmpty(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`user=bob`))
+	req.Header.Set("Content-Type", "text/plain")
+	require.NoError(t, Request(req, &fv1.HTTPRequestTransform{Body: `{"user": "{{form.user}}"}`}, "/", nil))
+	body, _ := io.ReadAll(req.Body)
+	assert.JSONEq(t, `{"user": ""}`, string(body))
+}
+
+func TestRequestBodyTooLarge(t *testing.T) {
+	big := strings.Repeat("a", int(fv1.MaxHTTPTransformRequestBytes)+1)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(big))
+	err := Request(req, &fv1.HTTPRequestTransform{Body: `{}`}, "/", nil)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+}
+
+func TestResponse(t *testing.T) {
+	h := http.Header{}
+	h.Set("Server", "werkzeug")
+	h.Set("Cache-Control", "no-cache")
+	Response(h, &fv1.HTTPResponseTransform{Headers: &fv1.HTTPHeaderTransform{
+		Remove: []string{"Server"},
+		Set:    []fv1.HTTPHeaderValue{{Name: "Cache-Control", Value: "max-age=60"}},
+		Add:    []fv1.HTTPHeaderValue{{Name: "X-Frame-Options", Value: "{{literal}}"}},
+	}})
+	assert.Empty(t, h.Get("Server"))
+	assert.Equal(t, "max-age=60", h.Get("Cache-Control"))
+	assert.Equal(t, "{{literal}}", h.Get("X-Frame-Options"))
+
+	Response(h, nil) // no transform is a no-op
+}
@@ -53,6 +53,14 @@ type (
 		transport     *http.Transport
 		otelTransport http.RoundTripper
 
+		// The gRPC counterpart of transport, built on first use: HTTP/2 in
+		// cleartext with prior knowledge, for functions whose
+		// Streaming.Protocol is grpc. Calls to one pod multiplex onto a
+		// single connection, so it keeps no per-host idle pool.
+		h2cOnce          sync.Once
+		h2cTransport     *http.Transport
+		h2cOtelTransport http.RoundTripper
+
 		// streamIdleDefault is the idle timeout applied to streaming functions
 		// when StreamingConfig.IdleTimeoutSeconds is unset (from the router's
 		// ROUTER_STREAM_IDLE_TIMEOUT env, defaulting to DefaultStreamIdleSeconds).
@@ -175,7 +183,7 @@ func (roundTripper *RetryingRoundTripper) RoundTrip(req *http.Request) (*http.Re
 
 	// set the timeout for transport context
 	addForwardedHostHeader(req)
-	transport, otelTransport := roundTripper.params.sharedTransport()
+	transport, otelTransport := roundTripper.params.transportFor(roundTripper.policy)
 
 	executingTimeout := roundTripper.params.timeout
 
@@ -347,6 +355,7 @@ func (roundTripper *RetryingRoundTripper) RoundTrip(req *http.Request) (*http.Re
 			debugDumpResponse(logger, resp)
 		}
 		if err == nil {
+			roundTripper.reportOutcome(resp.StatusCode >= http.StatusInternalServerError)
 			// return response back to user
 			return resp, nil
 		}
@@ -389,6 +398,9 @@ func (roundTripper *RetryingRoundTripper) RoundTrip(req *http.Request) (*http.Re
 					"url", req.URL.Host, "error", err.Error())
 			default:
 				logger.Error(err, "encountered non-network dial error")
+				// The connection was up: the endpoint took the request and
+				// failed it (reset, timed out mid-response).
+				roundTripper.reportOutcome(true)
 			}
 			return resp, err
 		}
@@ -459,6 +471,17 @@ func (roundTripper *RetryingRoundTripper) RoundTrip(req *http.Request) (*http.Re
 	return nil, ferror.NewInvocationError(ferror.ComponentExecutor, ferror.ReasonExecutorUnavailable, e)
 }
 
+// reportOutcome feeds the circuit breaker of an index-admitted endpoint
+// (release != nil); executor-resolved addresses are not the index's to eject.
+func (roundTripper *RetryingRoundTripper) reportOutcome(failed bool) {
+	if roundTripper.release == nil {
+		return
+	}
+	if r, ok := roundTripper.resolver.(OutcomeReporter); ok {
+		r.ReportOutcome(roundTripper.fn, roundTripper.serviceURL, failed)
+	}
+}
+
 // jitter adds up to 20% positive random jitter to a backoff duration so that
 // many concurrent retriers (and multiple router replicas) don't retry in
 // lockstep and stampede a function pod as it recovers.
@@ -516,6 +539,11 @@ const (
 	// request (whose per-attempt context has no deadline) could hang a dial
 	// against a blackholed address indefinitely.
 	defaultDialTimeout = 30 * time.Second
+	// h2cPingInterval and h2cPingTimeout probe an idle h2c connection: a pod
+	// that vanished without a FIN would otherwise stall every call
+	// multiplexed onto its connection until the streams time out.
+	h2cPingInterval = 15 * time.Second
+	h2cPingTimeout  = 10 * time.Second
 )
 
 // sharedTransport returns the process-wide pooled transport (and its
@@ -532,22 +560,9 @@ func (p *tsRoundTripperParams) sharedTransport() (*http.Transport, http.RoundTri
 		if perHost <= 0 {
 			perHost = defaultMaxIdleConnsPerHost
 		}
-		// No Dialer.Timeout: the per-attempt deadline comes from the context
-		// (cancelling the derived ctx after a successful dial does not affect
-		// the established connection, per net.Dialer docs).
-		dialer := &net.Dialer{KeepAlive: p.keepAliveTime}
 		p.transport = &http.Transport{
-			Proxy: http.ProxyFromEnvironment,
-			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-				d, ok := ctx.Value(dialTimeoutKey{}).(time.Duration)
-				if !ok || d <= 0 {
-					d = defaultDialTimeout
-				}
-				var cancel context.CancelFunc
-				ctx, cancel = context.WithTimeout(ctx, d)
-				defer cancel()
-				return dialer.DialContext(ctx, network, addr)
-			},
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           p.dialContext(),
 			MaxIdleConns:          1024,
 			MaxIdleConnsPerHost:   perHost,
 			IdleConnTimeout:       transportIdleConnTimeout,
@@ -567,6 +582,57 @@ func (p *tsRoundTripperParams) sharedTransport() (*http.Transport, http.RoundTri
 	return p.transport, p.otelTransport
 }
 
+// sharedH2CTransport is sharedTransport for gRPC functions: HTTP/2 without
+// TLS to the pod, which is what carries gRPC's full-duplex streams and its
+// trailers. There is no proxy hop (h2c cannot traverse an HTTP proxy), and
+// the dial and its per-attempt deadline are the HTTP/1 pool's, so the
+// retry ladder classifies failures identically.
+func (p *tsRoundTripperParams) sharedH2CTransport() (*http.Transport, http.RoundTripper) {
+	p.h2cOnce.Do(func() {
+		protocols := new(http.Protocols)
+		protocols.SetUnencryptedHTTP2(true)
+		p.h2cTransport = &http.Transport{
+			DialContext:     p.dialContext(),
+			Protocols:       protocols,
+			IdleConnTimeout: transportIdleConnTimeout,
+			HTTP2: &http.HTTP2Config{
+				SendPingTimeout: h2cPingInterval,
+				PingTimeout:     h2cPingTimeout,
+			},
+			DisableKeepAlives: p.disableKeepAlive,
+		}
+		p.h2cOtelTransport = otelhttp.NewTransport(p.h2cTransport)
+	})
+	return p.h2cTransport, p.h2cOtelTransport
+}
+
+// transportFor returns the shared transport (and its otel wrapper) a
+// policy's requests go out on.
+func (p *tsRoundTripperParams) transportFor(policy proxyPolicy) (*http.Transport, http.RoundTripper) {
+	if policy.protocol == fv1.StreamingGRPC {
+		return p.sharedH2CTransport()
+	}
+	return p.sharedTransport()
+}
+
+// dialContext dials with the per-attempt deadline dialTimeoutKey carries.
+// No Dialer.Timeout: the deadline comes from the context (cancelling the
+// derived ctx after a successful dial does not affect the established
+// connection, per net.Dialer docs).
+func (p *tsRoundTripperParams) dialContext() func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{KeepAlive: p.keepAliveTime}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		d, ok := ctx.Value(dialTimeoutKey{}).(time.Duration)
+		if !ok || d <= 0 {
+			d = defaultDialTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
+
 // setContext returns a shallow copy of request with a new timeout context.
 func (roundTripper *RetryingRoundTripper) setContext(req *http.Request) *http.Request {
 	if roundTripper.closeContextFunc != nil {
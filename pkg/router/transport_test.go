@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 
 	fv1 "github.com/fission/fission/pkg/apis/core/v1"
 	"github.com/fission/fission/pkg/error/network"
@@ -82,6 +83,9 @@ func (n *nopTapper) UnTap(_ context.Context, _ *fv1.Function, u *url.URL) error
 	n.lastUntap.Store(u)
 	return nil
 }
+func (n *nopTapper) Begin(*fv1.Function) func()  { return func() {} }
+func (n *nopTapper) InFlight(types.UID) int64    { return 0 }
+func (n *nopTapper) Forget(types.NamespacedName) {}
 
 func poolmgrFnForTransport() *fv1.Function {
 	fn := &fv1.Function{ObjectMeta: metav1.ObjectMeta{Name: "fn", Namespace: "default"}}
@@ -0,0 +1,636 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/golang-jwt/jwt/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+)
+
+// Per-trigger authentication (HTTPTriggerSpec.Auth). The policy is built into
+// the trigger's handler by buildTriggerHandler, so it is applied per route by
+// the incremental materializer like every other per-trigger wrap: a policy
+// edit bumps the trigger's Generation and lands as a HandlerSwapped, never a
+// mux rebuild. It runs AFTER route matching and AFTER the cluster-wide
+// authMiddleware (a pre-match middleware): a trigger policy narrows who may
+// call a route, it can never open a route the cluster switch closes.
+//
+// Both key sources are cached and refreshed out of band of the policy object:
+// the JWKS per issuer across every route that trusts it (jwksCache on the
+// HTTPTriggerSet), the API keys per route. A fetch failure keeps serving the
+// last good keys; only a route that has never loaded its keys answers 503.
+
+const (
+	// defaultAPIKeyHeader is HTTPTriggerAPIKeyAuth.Header's default.
+	defaultAPIKeyHeader = "X-API-Key"
+	// apiKeyRefreshInterval bounds how long a rotated or revoked key keeps
+	// (or fails to start) working: the Secret is re-read at most this often.
+	apiKeyRefreshInterval = 30 * time.Second
+	// jwksRefreshInterval is how long a fetched key set is used before the
+	// next verification refetches it. Issuers publish a new key well before
+	// signing with it, and an unknown kid forces a refetch anyway.
+	jwksRefreshInterval = 10 * time.Minute
+	// jwksMinRefetchInterval rate-limits the unknown-kid refetch, so a
+	// stream of tokens with made-up key IDs cannot turn the router into a
+	// request amplifier against the issuer.
+	jwksMinRefetchInterval = 30 * time.Second
+	// jwksFetchTimeout bounds one discovery or JWKS fetch; it runs on the
+	// request path of whichever request found the cache stale.
+	jwksFetchTimeout = 10 * time.Second
+	// maxJWKSBytes bounds a discovery document or key set body.
+	maxJWKSBytes = 1 << 20
+)
+
+// jwtSigningAlgs are the algorithms a trigger JWT may be signed with: the
+// asymmetric ones a JWKS can carry. HS* is excluded on purpose — accepting it
+// would let a token "signed" with a public key pass as an HMAC token (the
+// algorithm-confusion attack) — and "none" is never valid.
+var jwtSigningAlgs = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+var (
+	errMissingBearer     = errors.New("unauthorized: missing bearer token")
+	errMissingAPIKey     = errors.New("unauthorized: missing API key")
+	errInvalidAPIKey     = errors.New("unauthorized: invalid API key")
+	errAuthUnavailable   = errors.New("authentication is temporarily unavailable")
+	errUnknownSigningKey = errors.New("token is signed by an unknown key")
+)
+
+// authDecision is the outcome of one policy check, and the result label of
+// fission_router_trigger_auth_total.
+type authDecision string
+
+const (
+	authAllowed     authDecision = "allowed"
+	authDenied      authDecision = "denied"      // 401: no or bad credentials
+	authForbidden   authDecision = "forbidden"   // 403: valid token missing a required claim
+	authUnavailable authDecision = "unavailable" // 503: the key source could not be read
+)
+
+// authIdentity is what a successful check verified: the headers to forward
+// and, for a JWT, the full claim set (carried on the request context for the
+// rate limiter's claim key).
+type authIdentity struct {
+	headers http.Header
+	claims  map[string]any
+}
+
+// triggerAuthenticator checks one request against a trigger's policy. On
+// success it returns the verified identity; on failure the decision and an
+// error whose text is the response body.
+type triggerAuthenticator interface {
+	authenticate(r *http.Request) (identity authIdentity, decision authDecision, err error)
+}
+
+type verifiedClaimsKey struct{}
+
+// verifiedClaims returns the JWT claims the trigger's auth policy verified for
+// this request, or nil when the route has no JWT policy.
+func verifiedClaims(ctx context.Context) map[string]any {
+	claims, _ := ctx.Value(verifiedClaimsKey{}).(map[string]any)
+	return claims
+}
+
+// triggerAuthMiddleware wraps a trigger's handler with its Auth policy. Every
+// policy, anonymous included, first strips client-supplied X-Fission-Auth-*
+// headers: on a route with a policy those headers are router-owned, and a
+// function must be able to trust them. API-key requests also lose the key
+// header — the function never needs the credential itself.
+func triggerAuthMiddleware(auth *fv1.HTTPTriggerAuth, authn triggerAuthenticator, stripHeader string) func(http.Handler) http.Handler {
+	authType := attribute.String("type", string(auth.Type))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for name := range r.Header {
+				if strings.HasPrefix(name, fv1.HTTPTriggerAuthHeaderPrefix) {
+					r.Header.Del(name)
+				}
+			}
+			if authn == nil {
+				triggerAuthDecisions.Add(r.Context(), 1, metric.WithAttributes(authType, attribute.String("result", string(authAllowed))))
+				next.ServeHTTP(w, r)
+				return
+			}
+			identity, decision, err := authn.authenticate(r)
+			triggerAuthDecisions.Add(r.Context(), 1, metric.WithAttributes(authType, attribute.String("result", string(decision))))
+			switch decision {
+			case authAllowed:
+			case authForbidden:
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			case authUnavailable:
+				http.Error(w, errAuthUnavailable.Error(), http.StatusServiceUnavailable)
+				return
+			default:
+				if auth.Type == fv1.HTTPTriggerAuthJWT {
+					// RFC 6750 section 3: a bearer-protected resource names
+					// the scheme it expects on a 401.
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				}
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			if stripHeader != "" {
+				r.Header.Del(stripHeader)
+			}
+			for name, values := range identity.headers {
+				r.Header[name] = values
+			}
+			if identity.claims != nil {
+				r = r.WithContext(context.WithValue(r.Context(), verifiedClaimsKey{}, identity.claims))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// newTriggerAuth builds a trigger's policy wrap, or nil when the trigger has
+// none. The policy must already have passed HTTPTriggerAuth.Validate
+// (triggerConfigError gates the route on it).
+func (ts *HTTPTriggerSet) newTriggerAuth(trigger *fv1.HTTPTrigger) func(http.Handler) http.Handler {
+	auth := trigger.Spec.Auth
+	if auth == nil {
+		return nil
+	}
+	switch auth.Type {
+	case fv1.HTTPTriggerAuthJWT:
+		return triggerAuthMiddleware(auth, &jwtAuthenticator{
+			spec: auth.JWT,
+			keys: ts.jwks.keySet(auth.JWT.Issuer, auth.JWT.JWKSURI),
+		}, "")
+	case fv1.HTTPTriggerAuthAPIKey:
+		header := auth.APIKey.Header
+		if header == "" {
+			header = defaultAPIKeyHeader
+		}
+		return triggerAuthMiddleware(auth, &apiKeyAuthenticator{
+			logger:     ts.logger.WithName("apikey").WithValues("trigger", trigger.Namespace+"/"+trigger.Name),
+			kubeClient: ts.kubeClient,
+			namespace:  trigger.Namespace,
+			secretName: auth.APIKey.SecretName,
+			header:     header,
+		}, header)
+	default:
+		return triggerAuthMiddleware(auth, nil, "")
+	}
+}
+
+// apiKeyAuthenticator checks a request header against the entries of a
+// Secret in the trigger's namespace.
+type apiKeyAuthenticator struct {
+	logger     logr.Logger
+	kubeClient kubernetes.Interface
+	namespace  string
+	secretName string
+	header     string
+
+	mu      sync.Mutex
+	keys    map[string][]byte // Secret entry name -> key
+	fetched time.Time         // last read attempt
+	lastErr error             // its error while no keys are loaded
+}
+
+func (a *apiKeyAuthenticator) authenticate(r *http.Request) (authIdentity, authDecision, error) {
+	presented := r.Header.Get(a.header)
+	if presented == "" {
+		return authIdentity{}, authDenied, errMissingAPIKey
+	}
+	keys, err := a.currentKeys(r.Context())
+	if err != nil {
+		return authIdentity{}, authUnavailable, err
+	}
+	// Compare against every entry so the response time does not reveal
+	// which (or whether an earlier) entry matched.
+	var subject string
+	for name, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(presented), key) == 1 {
+			subject = name
+		}
+	}
+	if subject == "" {
+		return authIdentity{}, authDenied, errInvalidAPIKey
+	}
+	return authIdentity{headers: http.Header{fv1.HTTPTriggerAuthSubjectHeader: {subject}}}, authAllowed, nil
+}
+
+// currentKeys returns the Secret's entries, re-reading it at most every
+// apiKeyRefreshInterval. A failed re-read keeps the previous keys; with none
+// loaded yet the error is returned (and cached for the same interval, so a
+// missing Secret does not cost the API server a GET per request).
+func (a *apiKeyAuthenticator) currentKeys(ctx context.Context) (map[string][]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if time.Since(a.fetched) < apiKeyRefreshInterval {
+		return a.keys, a.lastErr
+	}
+	a.fetched = time.Now()
+	if a.kubeClient == nil {
+		a.lastErr = errors.New("no kubernetes client to read the API key secret")
+		return a.keys, a.lastErr
+	}
+	secret, err := a.kubeClient.CoreV1().Secrets(a.namespace).Get(ctx, a.secretName, metav1.GetOptions{})
+	if err != nil {
+		// A deleted Secret revokes every key; only a transient read error
+		// keeps the previously loaded ones.
+		if a.keys != nil && !apierrors.IsNotFound(err) {
+			a.logger.Error(err, "failed to refresh API keys; serving the previously loaded keys", "secret", a.secretName)
+			return a.keys, nil
+		}
+		a.logger.Error(err, "failed to load API keys", "secret", a.secretName)
+		a.keys, a.lastErr = nil, err
+		return nil, err
+	}
+	keys := make(map[string][]byte, len(secret.Data))
+	for name, key := range secret.Data {
+		// An empty value would match an empty header, which authenticate
+		// already rejects, but never let it be a valid credential.
+		if len(key) > 0 {
+			keys[name] = key
+		}
+	}
+	a.keys, a.lastErr = keys, nil
+	return keys, nil
+}
+
+// jwtAuthenticator verifies a bearer JWT against an issuer's key set.
+type jwtAuthenticator struct {
+	spec *fv1.HTTPTriggerJWTAuth
+	keys *jwksKeySet
+}
+
+func (a *jwtAuthenticator) authenticate(r *http.Request) (authIdentity, authDecision, error) {
+	raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || raw == "" {
+		return authIdentity{}, authDenied, errMissingBearer
+	}
+
+	claims := jwt.MapClaims{}
+	var keyErr error
+	parser := jwt.NewParser(jwt.WithValidMethods(jwtSigningAlgs), jwt.WithJSONNumber())
+	_, err := parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := a.keys.key(r.Context(), kid)
+		keyErr = err
+		return key, err
+	})
+	if err != nil {
+		if keyErr != nil && !errors.Is(keyErr, errUnknownSigningKey) {
+			// The key set itself could not be loaded: not the caller's
+			// fault, and retrying later may succeed.
+			return authIdentity{}, authUnavailable, keyErr
+		}
+		if ve, ok := errors.AsType[*jwt.ValidationError](err); ok && ve.Errors&(jwt.ValidationErrorExpired|jwt.ValidationErrorNotValidYet) != 0 {
+			return authIdentity{}, authDenied, errExpiredToken
+		}
+		return authIdentity{}, authDenied, fmt.Errorf("unauthorized: %w", err)
+	}
+
+	// The parser checks exp only when the token carries one; a token without
+	// it would never expire, so it is refused.
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return authIdentity{}, authDenied, errors.New("unauthorized: token has no expiry")
+	}
+	if !claims.VerifyIssuer(a.spec.Issuer, true) {
+		return authIdentity{}, authDenied, errors.New("unauthorized: token issuer does not match")
+	}
+	if len(a.spec.Audiences) > 0 && !slices.ContainsFunc(a.spec.Audiences, func(aud string) bool { return claims.VerifyAudience(aud, true) }) {
+		return authIdentity{}, authDenied, errors.New("unauthorized: token audience does not match")
+	}
+	for name, want := range a.spec.RequiredClaims {
+		if !claimMatches(claims[name], want) {
+			return authIdentity{}, authForbidden, fmt.Errorf("forbidden: token claim %q does not match", name)
+		}
+	}
+
+	identity := authIdentity{headers: http.Header{}, claims: claims}
+	if sub, ok := claims["sub"].(string); ok && sub != "" {
+		identity.headers.Set(fv1.HTTPTriggerAuthSubjectHeader, sub)
+	}
+	for name, header := range a.spec.ForwardClaims {
+		if v, ok := claimHeaderValue(claims[name]); ok {
+			identity.headers.Set(header, v)
+		}
+	}
+	return identity, authAllowed, nil
+}
+
+// claimMatches reports whether a claim equals want, or — for a list claim
+// such as "groups" or "roles" — contains it.
+func claimMatches(claim any, want string) bool {
+	switch v := claim.(type) {
+	case []any:
+		for _, e := range v {
+			if s, ok := claimHeaderValue(e); ok && s == want {
+				return true
+			}
+		}
+		return false
+	default:
+		s, ok := claimHeaderValue(v)
+		return ok && s == want
+	}
+}
+
+// claimHeaderValue renders a claim as a header value: strings verbatim,
+// numbers and booleans in their JSON form, lists comma-joined, objects as
+// compact JSON. A value that cannot be a header (it contains a line break)
+// is not forwarded.
+func claimHeaderValue(claim any) (string, bool) {
+	var s string
+	switch v := claim.(type) {
+	case nil:
+		return "", false
+	case string:
+		s = v
+	case json.Number:
+		s = v.String()
+	case bool:
+		s = fmt.Sprint(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, e := range v {
+			if p, ok := claimHeaderValue(e); ok {
+				parts = append(parts, p)
+			}
+		}
+		s = strings.Join(parts, ",")
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		s = string(b)
+	}
+	if strings.ContainsAny(s, "\r\n") {
+		return "", false
+	}
+	return s, true
+}
+
+// jwksCache shares one key set per (issuer, JWKS URI) across every route that
+// trusts the issuer, so a hundred triggers behind one IdP fetch its keys once.
+// Entries are never evicted: their number is bounded by the distinct issuers
+// configured on triggers, and an entry is a handful of public keys.
+type jwksCache struct {
+	client *http.Client
+	mu     sync.Mutex
+	sets   map[string]*jwksKeySet
+}
+
+func newJWKSCache() *jwksCache {
+	return &jwksCache{
+		client: &http.Client{Timeout: jwksFetchTimeout},
+		sets:   make(map[string]*jwksKeySet),
+	}
+}
+
+// keySet returns the shared key set for an issuer. A nil cache (unit tests
+// that build an HTTPTriggerSet by hand) hands out an unshared set.
+func (c *jwksCache) keySet(issuer, jwksURI string) *jwksKeySet {
+	if c == nil {
+		return &jwksKeySet{client: &http.Client{Timeout: jwksFetchTimeout}, issuer: issuer, jwksURI: jwksURI}
+	}
+	id := issuer + "\x00" + jwksURI
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ks, ok := c.sets[id]; ok {
+		return ks
+	}
+	ks := &jwksKeySet{client: c.client, issuer: issuer, jwksURI: jwksURI}
+	c.sets[id] = ks
+	return ks
+}
+
+// jwksKeySet is one issuer's cached public keys. The mutex is held across a
+// fetch on purpose: concurrent requests that find the set stale wait for the
+// one fetch instead of each issuing their own.
+type jwksKeySet struct {
+	client  *http.Client
+	issuer  string
+	jwksURI string // configured; empty = OpenID discovery
+
+	mu          sync.Mutex
+	resolvedURI string
+	keys        map[string]crypto.PublicKey // kid -> key
+	fetched     time.Time
+	lastAttempt time.Time
+	lastErr     error
+}
+
+// key returns the verification key for kid, (re)fetching the set when it is
+// stale or does not know kid. A token without a kid is accepted only when
+// the set holds exactly one key.
+func (ks *jwksKeySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	stale := time.Since(ks.fetched) >= jwksRefreshInterval
+	_, known := ks.keys[kid]
+	if kid == "" {
+		known = len(ks.keys) == 1
+	}
+	// Every fetch, the first one included, is rate-limited: an issuer that is
+	// down must not cost each request a fetch timeout.
+	if (ks.keys == nil || stale || !known) && time.Since(ks.lastAttempt) >= jwksMinRefetchInterval {
+		ks.lastErr = ks.refresh(ctx)
+	}
+	if ks.keys == nil {
+		return nil, ks.lastErr
+	}
+
+	if kid == "" {
+		if len(ks.keys) != 1 {
+			return nil, fmt.Errorf("%w: the token has no kid and the issuer publishes %d keys", errUnknownSigningKey, len(ks.keys))
+		}
+		for _, k := range ks.keys {
+			return k, nil
+		}
+	}
+	k, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errUnknownSigningKey, kid)
+	}
+	return k, nil
+}
+
+// refresh fetches the key set, resolving its URI through OpenID discovery on
+// first use when none is configured. On failure the previous keys are kept.
+func (ks *jwksKeySet) refresh(ctx context.Context) error {
+	ks.lastAttempt = time.Now()
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jwksFetchTimeout)
+	defer cancel()
+
+	uri := ks.jwksURI
+	if uri == "" {
+		uri = ks.resolvedURI
+	}
+	if uri == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := ks.getJSON(ctx, strings.TrimSuffix(ks.issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return fmt.Errorf("OpenID discovery for issuer %q: %w", ks.issuer, err)
+		}
+		// OpenID Connect Discovery 1.0 section 4.3: the document must name
+		// the issuer it was fetched for.
+		if discovery.Issuer != ks.issuer {
+			return fmt.Errorf("OpenID discovery for issuer %q returned issuer %q", ks.issuer, discovery.Issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return fmt.Errorf("OpenID discovery for issuer %q has no jwks_uri", ks.issuer)
+		}
+		ks.resolvedURI = discovery.JWKSURI
+		uri = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := ks.getJSON(ctx, uri, &set); err != nil {
+		return fmt.Errorf("fetching JWKS %q: %w", uri, err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Keys of an unsupported type or curve are skipped, not fatal: an
+		// issuer may publish keys for algorithms the router does not verify.
+		if k, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = k
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("JWKS %q has no usable signing keys", uri)
+	}
+	ks.keys, ks.fetched = keys, time.Now()
+	return nil
+}
+
+func (ks *jwksKeySet) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBytes)).Decode(out)
+}
+
+// jsonWebKey is the subset of an RFC 7517 JSON Web Key needed to rebuild an
+// RSA, EC or Ed25519 public key.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64URLInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("RSA modulus: %w", err)
+		}
+		e, err := base64URLInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("RSA exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("EC x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("EC y: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.New("EC coordinates have the wrong length for the curve")
+		}
+		// ParseUncompressedPublicKey rejects points not on the curve.
+		return ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func base64URLInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
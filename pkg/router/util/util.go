@@ -19,6 +19,9 @@ func GetIngressSpec(namespace string, trigger *fv1.HTTPTrigger) *v1.Ingress {
 	if trigger.Spec.Prefix != nil && *trigger.Spec.Prefix != "" {
 		path = *trigger.Spec.Prefix
 	}
+	if trigger.Spec.GRPC != nil {
+		path = trigger.Spec.GRPC.PathPrefix()
+	}
 	if len(trigger.Spec.IngressConfig.Host) > 0 && len(trigger.Spec.IngressConfig.Path) > 0 {
 		host, path = trigger.Spec.IngressConfig.Host, trigger.Spec.IngressConfig.Path
 	}
@@ -227,6 +227,22 @@ func (c *Client) Head(ctx context.Context, stream string) (int64, error) {
 	return resp.Head, nil
 }
 
+func (c *Client) Compact(ctx context.Context, stream string, belowSeq int64) (int64, error) {
+	var resp httpapi.EventCompactResp
+	if err := c.post(ctx, httpapi.PathEventCompact, httpapi.EventCompactReq{Stream: stream, BelowSeq: belowSeq}, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Compacted, nil
+}
+
+func (c *Client) StreamStats(ctx context.Context, stream string) (statestore.StreamStats, error) {
+	var resp httpapi.EventStatsResp
+	if err := c.post(ctx, httpapi.PathEventStats, httpapi.EventStatsReq{Stream: stream}, &resp); err != nil {
+		return statestore.StreamStats{}, err
+	}
+	return statestore.StreamStats{Events: resp.Events, Bytes: resp.Bytes}, nil
+}
+
 // --- Queue ---
 
 func (c *Client) Enqueue(ctx context.Context, queue string, msg statestore.Message, o statestore.EnqueueOptions) (string, error) {
@@ -259,9 +275,9 @@ func (c *Client) Kill(ctx context.Context, receipt string, reason string) error
 	return c.post(ctx, httpapi.PathQueueKill, httpapi.QueueKillReq{Receipt: receipt, Reason: reason}, nil)
 }
 
-func (c *Client) DeadLetters(ctx context.Context, queue string, page statestore.Page) ([]statestore.DeadMessage, error) {
+func (c *Client) DeadLetters(ctx context.Context, queue string, page statestore.Page, filter statestore.DeadLetterFilter) ([]statestore.DeadMessage, error) {
 	var resp httpapi.QueueDeadLettersResp
-	if err := c.post(ctx, httpapi.PathQueueDeadLetter, httpapi.QueueDeadLettersReq{Queue: queue, Page: page}, &resp); err != nil {
+	if err := c.post(ctx, httpapi.PathQueueDeadLetter, httpapi.QueueDeadLettersReq{Queue: queue, Page: page, Filter: filter}, &resp); err != nil {
 		return nil, err
 	}
 	return resp.Messages, nil
@@ -283,6 +299,14 @@ func (c *Client) Purge(ctx context.Context, queue string) (int64, error) {
 	return resp.Purged, nil
 }
 
+func (c *Client) Discard(ctx context.Context, queue string, ids []string) (int64, error) {
+	var resp httpapi.QueueDiscardResp
+	if err := c.post(ctx, httpapi.PathQueueDiscard, httpapi.QueueDiscardReq{Queue: queue, IDs: ids}, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Discarded, nil
+}
+
 func (c *Client) Stats(ctx context.Context, queue string) (statestore.QueueStats, error) {
 	var resp httpapi.QueueStatsResp
 	if err := c.post(ctx, httpapi.PathQueueStats, httpapi.QueueStatsReq{Queue: queue}, &resp); err != nil {
@@ -34,6 +34,8 @@ const (
 	PathEventRead       = "/v1/eventlog/read"
 	PathEventTrim       = "/v1/eventlog/trim"
 	PathEventHead       = "/v1/eventlog/head"
+	PathEventCompact    = "/v1/eventlog/compact"
+	PathEventStats      = "/v1/eventlog/stats"
 	PathQueueEnqueue    = "/v1/queue/enqueue"
 	PathQueueLease      = "/v1/queue/lease"
 	PathQueueAck        = "/v1/queue/ack"
@@ -42,6 +44,7 @@ const (
 	PathQueueDeadLetter = "/v1/queue/deadletters"
 	PathQueueRedrive    = "/v1/queue/redrive"
 	PathQueuePurge      = "/v1/queue/purge"
+	PathQueueDiscard    = "/v1/queue/discard"
 	PathQueueStats      = "/v1/queue/stats"
 )
 
@@ -178,6 +181,20 @@ type EventHeadReq struct {
 type EventHeadResp struct {
 	Head int64 `json:"head"`
 }
+type EventCompactReq struct {
+	Stream   string `json:"stream"`
+	BelowSeq int64  `json:"belowSeq"`
+}
+type EventCompactResp struct {
+	Compacted int64 `json:"compacted"`
+}
+type EventStatsReq struct {
+	Stream string `json:"stream"`
+}
+type EventStatsResp struct {
+	Events int64 `json:"events"`
+	Bytes  int64 `json:"bytes"`
+}
 
 // --- Queue ---
 
@@ -212,6 +229,9 @@ type QueueKillReq struct {
 type QueueDeadLettersReq struct {
 	Queue string          `json:"queue"`
 	Page  statestore.Page `json:"page"`
+	// Filter is omitted for the zero filter, so a request from a pre-filter
+	// client is byte-identical and still lists everything.
+	Filter statestore.DeadLetterFilter `json:"filter,omitzero"`
 }
 type QueueDeadLettersResp struct {
 	Messages []statestore.DeadMessage `json:"messages"`
@@ -229,6 +249,13 @@ type QueuePurgeReq struct {
 type QueuePurgeResp struct {
 	Purged int64 `json:"purged"`
 }
+type QueueDiscardReq struct {
+	Queue string   `json:"queue"`
+	IDs   []string `json:"ids"`
+}
+type QueueDiscardResp struct {
+	Discarded int64 `json:"discarded"`
+}
 type QueueStatsReq struct {
 	Queue string `json:"queue"`
 }
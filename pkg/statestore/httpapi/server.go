@@ -29,6 +29,8 @@ func NewHandler(caps statestore.Capabilities) http.Handler {
 	mux.HandleFunc("POST "+PathEventRead, h.eventRead)
 	mux.HandleFunc("POST "+PathEventTrim, h.eventTrim)
 	mux.HandleFunc("POST "+PathEventHead, h.eventHead)
+	mux.HandleFunc("POST "+PathEventCompact, h.eventCompact)
+	mux.HandleFunc("POST "+PathEventStats, h.eventStats)
 	mux.HandleFunc("POST "+PathQueueEnqueue, h.queueEnqueue)
 	mux.HandleFunc("POST "+PathQueueLease, h.queueLease)
 	mux.HandleFunc("POST "+PathQueueAck, h.queueAck)
@@ -37,6 +39,7 @@ func NewHandler(caps statestore.Capabilities) http.Handler {
 	mux.HandleFunc("POST "+PathQueueDeadLetter, h.queueDeadLetters)
 	mux.HandleFunc("POST "+PathQueueRedrive, h.queueRedrive)
 	mux.HandleFunc("POST "+PathQueuePurge, h.queuePurge)
+	mux.HandleFunc("POST "+PathQueueDiscard, h.queueDiscard)
 	mux.HandleFunc("POST "+PathQueueStats, h.queueStats)
 	return mux
 }
@@ -261,6 +264,40 @@ func (h *handler) eventHead(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, EventHeadResp{Head: head})
 }
 
+func (h *handler) eventCompact(w http.ResponseWriter, r *http.Request) {
+	req, ok := decode[EventCompactReq](w, r)
+	if !ok {
+		return
+	}
+	el, ok := h.el(w)
+	if !ok {
+		return
+	}
+	n, err := el.Compact(r.Context(), req.Stream, req.BelowSeq)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeJSON(w, EventCompactResp{Compacted: n})
+}
+
+func (h *handler) eventStats(w http.ResponseWriter, r *http.Request) {
+	req, ok := decode[EventStatsReq](w, r)
+	if !ok {
+		return
+	}
+	el, ok := h.el(w)
+	if !ok {
+		return
+	}
+	st, err := el.StreamStats(r.Context(), req.Stream)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeJSON(w, EventStatsResp{Events: st.Events, Bytes: st.Bytes})
+}
+
 func (h *handler) queueEnqueue(w http.ResponseWriter, r *http.Request) {
 	req, ok := decode[QueueEnqueueReq](w, r)
 	if !ok {
@@ -355,7 +392,7 @@ func (h *handler) queueDeadLetters(w http.ResponseWriter, r *http.Request) {
 	if !ok {
 		return
 	}
-	msgs, err := q.DeadLetters(r.Context(), req.Queue, req.Page)
+	msgs, err := q.DeadLetters(r.Context(), req.Queue, req.Page, req.Filter)
 	if err != nil {
 		writeErr(w, err)
 		return
@@ -397,6 +434,23 @@ func (h *handler) queuePurge(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, QueuePurgeResp{Purged: n})
 }
 
+func (h *handler) queueDiscard(w http.ResponseWriter, r *http.Request) {
+	req, ok := decode[QueueDiscardReq](w, r)
+	if !ok {
+		return
+	}
+	q, ok := h.q(w)
+	if !ok {
+		return
+	}
+	n, err := q.Discard(r.Context(), req.Queue, req.IDs)
+	if err != nil {
+		writeErr(w, err)
+		return
+	}
+	writeJSON(w, QueueDiscardResp{Discarded: n})
+}
+
 func (h *handler) queueStats(w http.ResponseWriter, r *http.Request) {
 	req, ok := decode[QueueStatsReq](w, r)
 	if !ok {
@@ -73,6 +73,15 @@ type EventLog interface {
 	Head(ctx context.Context, stream string) (int64, error)
 	// Trim drops events with Seq < belowSeq (history GC).
 	Trim(ctx context.Context, stream string, belowSeq int64) error
+	// Compact drops every keyed event with Seq < belowSeq that a later event
+	// with the same Key supersedes (anywhere up to the head), returning the
+	// number dropped. Unkeyed events and the latest event per key survive, and
+	// the head is unchanged — compaction leaves holes, never renumbers, so a
+	// reader at any cursor still sees a strictly increasing Seq.
+	Compact(ctx context.Context, stream string, belowSeq int64) (int64, error)
+	// StreamStats reports the stream's retained event count and payload bytes (zero
+	// for an absent stream), with no side effects.
+	StreamStats(ctx context.Context, stream string) (StreamStats, error)
 }
 
 // Queue is an at-least-once work queue with visibility-timeout leases and a
@@ -104,8 +113,10 @@ type Queue interface {
 	// message on a permanent 4xx without burning retries. It is deliberately
 	// outside the retry protocol modeled in queue.tla.
 	Kill(ctx context.Context, receipt string, reason string) error
-	// DeadLetters returns a page of dead-lettered messages for queue.
-	DeadLetters(ctx context.Context, queue string, page Page) ([]DeadMessage, error)
+	// DeadLetters returns a page of dead-lettered messages for queue that match
+	// filter (the zero filter matches all). The page token is the last id of the
+	// previous page, so a filtered listing pages over the matches only.
+	DeadLetters(ctx context.Context, queue string, page Page, filter DeadLetterFilter) ([]DeadMessage, error)
 	// Redrive re-enqueues dead-lettered messages by durable id with attempts
 	// reset, returning the number actually re-enqueued — ids that are not currently
 	// dead-lettered are skipped, so the count can be less than len(ids).
@@ -115,6 +126,12 @@ type Queue interface {
 	// work), so it drops the conservation Enqueued and Dead counts equally and the
 	// T1 drift invariant is preserved by construction.
 	Purge(ctx context.Context, queue string) (int64, error)
+	// Discard permanently deletes the given dead-lettered messages by durable id,
+	// returning the number removed. Like Redrive, ids that are not currently
+	// dead-lettered are skipped; like Purge, it touches only the dead set, so T1
+	// holds by construction. It is the selective counterpart of Purge, for
+	// deleting just the matches of a filter.
+	Discard(ctx context.Context, queue string, ids []string) (int64, error)
 	// Stats returns a point-in-time snapshot of queue's backlog (visible / leased
 	// / dead counts and the oldest visible message's age), for the RFC-0024 async
 	// depth/oldest-age metrics and DLQ dashboards. It does not reap expired leases.
@@ -118,6 +118,54 @@ func (s *Store) Trim(_ context.Context, stream string, belowSeq int64) error {
 	return nil
 }
 
+// Compact implements statestore.EventLog: drop keyed events with Seq < belowSeq
+// that a later same-key event supersedes. The head is unchanged.
+func (s *Store) Compact(_ context.Context, stream string, belowSeq int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, statestore.ErrClosed
+	}
+	st := s.streams[stream]
+	if st == nil {
+		return 0, nil
+	}
+	latest := map[string]int64{}
+	for _, e := range st.events {
+		if e.Key != "" {
+			latest[e.Key] = e.Seq
+		}
+	}
+	kept := st.events[:0:0]
+	for _, e := range st.events {
+		if e.Key != "" && e.Seq < belowSeq && e.Seq < latest[e.Key] {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	dropped := int64(len(st.events) - len(kept))
+	st.events = kept
+	return dropped, nil
+}
+
+// StreamStats implements statestore.EventLog.
+func (s *Store) StreamStats(_ context.Context, stream string) (statestore.StreamStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return statestore.StreamStats{}, statestore.ErrClosed
+	}
+	st := s.streams[stream]
+	if st == nil {
+		return statestore.StreamStats{}, nil
+	}
+	stats := statestore.StreamStats{Events: int64(len(st.events))}
+	for _, e := range st.events {
+		stats.Bytes += int64(len(e.Payload))
+	}
+	return stats, nil
+}
+
 func cloneEvent(e statestore.Event) statestore.Event {
 	if e.Payload != nil {
 		p := make([]byte, len(e.Payload))
@@ -240,9 +240,10 @@ func (s *Store) Kill(_ context.Context, receipt string, reason string) error {
 	return nil
 }
 
-// DeadLetters implements statestore.Queue: a page of dead-lettered messages,
-// ordered by id, paginated by page.Token (the last id of the previous page).
-func (s *Store) DeadLetters(_ context.Context, queue string, page statestore.Page) ([]statestore.DeadMessage, error) {
+// DeadLetters implements statestore.Queue: a page of dead-lettered messages
+// matching filter, ordered by id, paginated by page.Token (the last id of the
+// previous page).
+func (s *Store) DeadLetters(_ context.Context, queue string, page statestore.Page, filter statestore.DeadLetterFilter) ([]statestore.DeadMessage, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	if s.closed {
@@ -257,14 +258,18 @@ func (s *Store) DeadLetters(_ context.Context, queue string, page statestore.Pag
 		if m.state != qDead || (page.Token != "" && m.id <= page.Token) {
 			continue
 		}
-		dead = append(dead, statestore.DeadMessage{
+		dm := statestore.DeadMessage{
 			ID:         m.id,
-			Body:       append([]byte(nil), m.body...),
 			Reason:     m.reason,
 			Attempts:   m.attempts,
 			EnqueuedAt: m.enqueuedAt,
 			DiedAt:     m.diedAt,
-		})
+		}
+		if !filter.Match(dm) {
+			continue
+		}
+		dm.Body = append([]byte(nil), m.body...)
+		dead = append(dead, dm)
 	}
 	sort.Slice(dead, func(i, j int) bool { return dead[i].ID < dead[j].ID })
 	if page.Limit > 0 && len(dead) > page.Limit {
@@ -331,6 +336,36 @@ func (s *Store) Purge(_ context.Context, queue string) (int64, error) {
 	return removed, nil
 }
 
+// Discard implements statestore.Queue: permanently drop the given dead-lettered
+// messages, skipping ids that are not dead. Conservation holds exactly as for
+// Purge (invariant T1).
+func (s *Store) Discard(_ context.Context, queue string, ids []string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, statestore.ErrClosed
+	}
+	q := s.queue(queue)
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+	kept := q.msgs[:0]
+	var removed int64
+	for _, m := range q.msgs {
+		if m.state == qDead && want[m.id] {
+			removed++
+			continue
+		}
+		kept = append(kept, m)
+	}
+	for i := len(kept); i < len(q.msgs); i++ {
+		q.msgs[i] = nil
+	}
+	q.msgs = kept
+	return removed, nil
+}
+
 // Stats implements statestore.Queue: a read-only snapshot of the queue's backlog.
 // It does not reap expired leases (see statestore.QueueStats), and it does not
 // create the queue if absent — an unknown queue reports a zero snapshot.
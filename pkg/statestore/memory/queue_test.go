@@ -110,7 +110,7 @@ func TestMemoryQueue_Q3_DeadImpliesExhausted(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, l[0].Attempts)
 	require.NoError(t, q.Nack(ctx, l[0].Receipt, 0))
-	dl, err := q.DeadLetters(ctx, qn, statestore.Page{})
+	dl, err := q.DeadLetters(ctx, qn, statestore.Page{}, statestore.DeadLetterFilter{})
 	require.NoError(t, err)
 	require.Empty(t, dl, "not dead before the budget is spent")
 
@@ -119,7 +119,7 @@ func TestMemoryQueue_Q3_DeadImpliesExhausted(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 2, l[0].Attempts)
 	require.NoError(t, q.Nack(ctx, l[0].Receipt, 0))
-	dl, err = q.DeadLetters(ctx, qn, statestore.Page{})
+	dl, err = q.DeadLetters(ctx, qn, statestore.Page{}, statestore.DeadLetterFilter{})
 	require.NoError(t, err)
 	require.Len(t, dl, 1)
 	require.GreaterOrEqual(t, dl[0].Attempts, s.maxAttempts) // dead ⇒ exhausted
@@ -228,7 +228,7 @@ func TestMemoryQueue_ExhaustedByExpiry_DeadLettered(t *testing.T) {
 			time.Sleep(2 * time.Minute) // lease expires
 		}
 
-		dl, err := q.DeadLetters(ctx, qn, statestore.Page{})
+		dl, err := q.DeadLetters(ctx, qn, statestore.Page{}, statestore.DeadLetterFilter{})
 		require.NoError(t, err)
 		require.Len(t, dl, 1)
 		require.Equal(t, id, dl[0].ID)
@@ -297,7 +297,7 @@ func TestMemoryQueue_KillImmediateAndRedrive(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, q.Kill(ctx, l[0].Receipt, "http_4xx")) // permanent, before budget spent
 
-	dl, err := q.DeadLetters(ctx, qn, statestore.Page{})
+	dl, err := q.DeadLetters(ctx, qn, statestore.Page{}, statestore.DeadLetterFilter{})
 	require.NoError(t, err)
 	require.Len(t, dl, 1)
 	require.Equal(t, id, dl[0].ID)
@@ -307,7 +307,7 @@ func TestMemoryQueue_KillImmediateAndRedrive(t *testing.T) {
 	n, err := q.Redrive(ctx, qn, []string{id})
 	require.NoError(t, err)
 	require.EqualValues(t, 1, n)
-	dl, err = q.DeadLetters(ctx, qn, statestore.Page{})
+	dl, err = q.DeadLetters(ctx, qn, statestore.Page{}, statestore.DeadLetterFilter{})
 	require.NoError(t, err)
 	require.Empty(t, dl)
 	l, err = q.Lease(ctx, qn, 1, time.Minute)
@@ -180,6 +180,18 @@ func (e *meteredEventLog) Trim(ctx context.Context, stream string, belowSeq int6
 	return err
 }
 
+func (e *meteredEventLog) Compact(ctx context.Context, stream string, belowSeq int64) (int64, error) {
+	n, err := e.inner.Compact(ctx, stream, belowSeq)
+	observe(ctx, "eventlog", "compact", err)
+	return n, err
+}
+
+func (e *meteredEventLog) StreamStats(ctx context.Context, stream string) (StreamStats, error) {
+	st, err := e.inner.StreamStats(ctx, stream)
+	observe(ctx, "eventlog", "stats", err)
+	return st, err
+}
+
 // meteredQueue adds metrics to a Queue.
 type meteredQueue struct{ inner Queue }
 
@@ -213,8 +225,8 @@ func (q *meteredQueue) Kill(ctx context.Context, receipt string, reason string)
 	return err
 }
 
-func (q *meteredQueue) DeadLetters(ctx context.Context, queue string, page Page) ([]DeadMessage, error) {
-	dl, err := q.inner.DeadLetters(ctx, queue, page)
+func (q *meteredQueue) DeadLetters(ctx context.Context, queue string, page Page, filter DeadLetterFilter) ([]DeadMessage, error) {
+	dl, err := q.inner.DeadLetters(ctx, queue, page, filter)
 	observe(ctx, "queue", "deadletters", err)
 	return dl, err
 }
@@ -231,6 +243,12 @@ func (q *meteredQueue) Purge(ctx context.Context, queue string) (int64, error) {
 	return n, err
 }
 
+func (q *meteredQueue) Discard(ctx context.Context, queue string, ids []string) (int64, error) {
+	n, err := q.inner.Discard(ctx, queue, ids)
+	observe(ctx, "queue", "discard", err)
+	return n, err
+}
+
 func (q *meteredQueue) Stats(ctx context.Context, queue string) (QueueStats, error) {
 	st, err := q.inner.Stats(ctx, queue)
 	observe(ctx, "queue", "stats", err)
@@ -78,10 +78,13 @@ func (e *eventLog) Append(ctx context.Context, stream string, expectedSeq int64,
 		}
 		now := nowNanos()
 		seq := base
-		insertSQL := e.s.rebind(`INSERT INTO state_events (stream, seq, type, payload, at) VALUES (?, ?, ?, ?, ?)`)
+		insertSQL := e.s.rebind(`INSERT INTO state_events (stream, seq, type, payload, at, event_key) VALUES (?, ?, ?, ?, ?, ?)`)
 		for _, ev := range events {
 			seq++
-			if _, ierr := tx.ExecContext(ctx, insertSQL, stream, seq, ev.Type, ev.Payload, now); ierr != nil {
+			// An unkeyed event stores NULL, which the compaction DELETE never
+			// matches (NULL = NULL is not true).
+			key := sql.NullString{String: ev.Key, Valid: ev.Key != ""}
+			if _, ierr := tx.ExecContext(ctx, insertSQL, stream, seq, ev.Type, ev.Payload, now, key); ierr != nil {
 				return ierr
 			}
 		}
@@ -113,7 +116,7 @@ func (e *eventLog) Head(ctx context.Context, stream string) (int64, error) {
 // Read implements statestore.EventLog: up to limit events with seq > fromSeq, in
 // order. limit <= 0 returns all matching events (parity with the memory driver).
 func (e *eventLog) Read(ctx context.Context, stream string, fromSeq int64, limit int) ([]statestore.Event, error) {
-	query := `SELECT seq, type, payload, at FROM state_events WHERE stream = ? AND seq > ? ORDER BY seq`
+	query := `SELECT seq, type, COALESCE(event_key, ''), payload, at FROM state_events WHERE stream = ? AND seq > ? ORDER BY seq`
 	args := []any{stream, fromSeq}
 	if limit > 0 {
 		query += ` LIMIT ?`
@@ -132,7 +135,7 @@ func (e *eventLog) Read(ctx context.Context, stream string, fromSeq int64, limit
 			at  int64
 			pay []byte
 		)
-		if err := rows.Scan(&ev.Seq, &ev.Type, &pay, &at); err != nil {
+		if err := rows.Scan(&ev.Seq, &ev.Type, &ev.Key, &pay, &at); err != nil {
 			return nil, err
 		}
 		ev.Payload = pay
@@ -147,3 +150,25 @@ func (e *eventLog) Trim(ctx context.Context, stream string, belowSeq int64) erro
 	_, err := e.s.exec(ctx, `DELETE FROM state_events WHERE stream = ? AND seq < ?`, stream, belowSeq)
 	return err
 }
+
+// Compact implements statestore.EventLog: one DELETE of the keyed events below
+// belowSeq that a later same-key row supersedes. The correlated EXISTS is served
+// by idx_state_events_key (stream, event_key).
+func (e *eventLog) Compact(ctx context.Context, stream string, belowSeq int64) (int64, error) {
+	res, err := e.s.exec(ctx, `DELETE FROM state_events WHERE stream = ? AND seq < ? AND event_key IS NOT NULL
+		AND EXISTS (SELECT 1 FROM state_events later
+			WHERE later.stream = state_events.stream AND later.event_key = state_events.event_key AND later.seq > state_events.seq)`,
+		stream, belowSeq)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// StreamStats implements statestore.EventLog.
+func (e *eventLog) StreamStats(ctx context.Context, stream string) (statestore.StreamStats, error) {
+	var st statestore.StreamStats
+	err := e.s.queryRow(ctx, `SELECT COUNT(*), COALESCE(SUM(LENGTH(payload)), 0) FROM state_events WHERE stream = ?`, stream).
+		Scan(&st.Events, &st.Bytes)
+	return st, err
+}
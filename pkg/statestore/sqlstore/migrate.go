@@ -85,6 +85,16 @@ func (d Dialect) migrations() []migration {
 				)`,
 			},
 		},
+		{
+			// event_key is the optional compaction key (RFC-0027 topic
+			// compaction). Nullable, so an N-1 driver's keyless inserts stay
+			// valid and simply never compact.
+			version: 3,
+			stmts: []string{
+				`ALTER TABLE state_events ADD COLUMN event_key TEXT`,
+				`CREATE INDEX IF NOT EXISTS idx_state_events_key ON state_events (stream, event_key)`,
+			},
+		},
 	}
 }
 
@@ -119,11 +119,14 @@ func TestMigrationsAreAppendOnly(t *testing.T) {
 		"sqlite": {
 			1: "sha256:2e10ff688eb25ac73abba0094027304608f6524d6272f54d19d7d7f63b53e6a0",
 			2: "sha256:6afe6f1cc5f6aac71cc82657e8962d0a2e92a408abbb896e9e939f7a0f5fc43d",
+			3: "sha256:41754fdf311df369df2e58c41ffd594804e8fc9ca745496bbbae935be4098971",
 		},
 		"postgres": {
 			1: "sha256:4c3072401bd6d5d60aa52941edae910fe82a7ebba8ca2ceee526a78e37cfa840",
 			// Identical to sqlite's: migration 2 uses no dialect-specific types.
 			2: "sha256:6afe6f1cc5f6aac71cc82657e8962d0a2e92a408abbb896e9e939f7a0f5fc43d",
+			// Likewise dialect-neutral (event_key is plain TEXT).
+			3: "sha256:41754fdf311df369df2e58c41ffd594804e8fc9ca745496bbbae935be4098971",
 		},
 	}
 
@@ -243,15 +243,36 @@ func settleResult(res sql.Result, err error) error {
 	return nil
 }
 
-// DeadLetters implements statestore.Queue.
-func (q *queueStore) DeadLetters(ctx context.Context, queue string, page statestore.Page) ([]statestore.DeadMessage, error) {
+// DeadLetters implements statestore.Queue. The filter is pushed into the WHERE
+// clause so a narrow filter over a large dead set still pages by LIMIT. Reason
+// substring matching lowers both sides (LOWER + LIKE ... ESCAPE) because SQLite's
+// LIKE is ASCII-case-insensitive while Postgres's is not; the memory driver's
+// DeadLetterFilter.Match is the reference.
+func (q *queueStore) DeadLetters(ctx context.Context, queue string, page statestore.Page, filter statestore.DeadLetterFilter) ([]statestore.DeadMessage, error) {
 	if err := q.reap(ctx, q.s.db, queue, nowNanos()); err != nil {
 		return nil, err
 	}
 	col := q.s.dialect.Collate
 	query := `SELECT id, body, reason, attempts, enqueued_at, died_at FROM state_queue
-		 WHERE queue = ? AND state = ? AND id > ?` + col + ` ORDER BY id` + col
+		 WHERE queue = ? AND state = ? AND id > ?` + col
 	args := []any{queue, stDead, page.Token}
+	if filter.Reason != "" {
+		query += ` AND reason = ?`
+		args = append(args, filter.Reason)
+	}
+	if filter.ReasonContains != "" {
+		query += ` AND LOWER(reason) LIKE ? ESCAPE '\'`
+		args = append(args, "%"+escapeLikePrefix(strings.ToLower(filter.ReasonContains)))
+	}
+	if !filter.DiedAfter.IsZero() {
+		query += ` AND died_at >= ?`
+		args = append(args, filter.DiedAfter.UnixNano())
+	}
+	if !filter.DiedBefore.IsZero() {
+		query += ` AND died_at < ?`
+		args = append(args, filter.DiedBefore.UnixNano())
+	}
+	query += ` ORDER BY id` + col
 	if page.Limit > 0 {
 		query += ` LIMIT ?`
 		args = append(args, page.Limit)
@@ -303,6 +324,28 @@ func (q *queueStore) Redrive(ctx context.Context, queue string, ids []string) (i
 	return res.RowsAffected()
 }
 
+// Discard implements statestore.Queue: delete the given dead-lettered rows,
+// skipping ids that are not dead. As with Purge, only dead rows go, so
+// conservation drift stays zero (invariant T1).
+func (q *queueStore) Discard(ctx context.Context, queue string, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := []any{queue, stDead}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	res, err := q.s.exec(ctx,
+		`DELETE FROM state_queue WHERE queue = ? AND state = ? AND id IN (`+placeholders+`)`,
+		args...,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // Purge implements statestore.Queue: delete every dead-lettered row for queue and
 // return the count removed. Deleting only dead rows lowers the live-row total
 // (Enqueued) and Dead equally, so conservation drift stays zero (invariant T1).
@@ -394,6 +394,62 @@ func runEventLog(t *testing.T, newCaps Factory) {
 			require.True(t, ev.Seq >= 1 && ev.Seq <= writers, "seq %d out of the gapless range", ev.Seq)
 		}
 	})
+
+	t.Run("CompactKeepsLatestPerKey", func(t *testing.T) {
+		el := eventLogOrSkip(t, newCaps)
+		ctx := t.Context()
+		// seq: 1 a=k1, 2 unkeyed, 3 a=k2, 4 a=k1, 5 a=k2, 6 a=k1
+		_, err := el.Append(ctx, "compact", statestore.AppendAny, []statestore.Event{
+			{Type: "t", Key: "k1", Payload: []byte("1")},
+			{Type: "t", Payload: []byte("2")},
+			{Type: "t", Key: "k2", Payload: []byte("3")},
+			{Type: "t", Key: "k1", Payload: []byte("4")},
+			{Type: "t", Key: "k2", Payload: []byte("5")},
+			{Type: "t", Key: "k1", Payload: []byte("6")},
+		})
+		require.NoError(t, err)
+		evs, err := el.Read(ctx, "compact", 0, 1)
+		require.NoError(t, err)
+		require.Equal(t, "k1", evs[0].Key, "the key round-trips")
+
+		// Below 5: seq 1 and 3 are superseded (by 4/6 and 5); seq 4 is
+		// superseded too (by 6) — a superseding event above the bound still
+		// counts. The unkeyed event survives.
+		n, err := el.Compact(ctx, "compact", 5)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, n)
+		evs, err = el.Read(ctx, "compact", 0, 0)
+		require.NoError(t, err)
+		var seqs []int64
+		for _, ev := range evs {
+			seqs = append(seqs, ev.Seq)
+		}
+		require.Equal(t, []int64{2, 5, 6}, seqs)
+		head, err := el.Head(ctx, "compact")
+		require.NoError(t, err)
+		require.EqualValues(t, 6, head, "compaction never moves the head")
+
+		// Idempotent: nothing left to drop.
+		n, err = el.Compact(ctx, "compact", 7)
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("StreamStats", func(t *testing.T) {
+		el := eventLogOrSkip(t, newCaps)
+		ctx := t.Context()
+		st, err := el.StreamStats(ctx, "stats")
+		require.NoError(t, err)
+		require.Zero(t, st, "an absent stream is empty")
+		_, err = el.Append(ctx, "stats", statestore.AppendAny, []statestore.Event{
+			{Type: "t", Payload: []byte("abc")}, {Type: "t", Payload: []byte("de")}, {Type: "t"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, el.Trim(ctx, "stats", 2))
+		st, err = el.StreamStats(ctx, "stats")
+		require.NoError(t, err)
+		require.Equal(t, statestore.StreamStats{Events: 2, Bytes: 2}, st)
+	})
 }
 
 func runQueue(t *testing.T, newCaps Factory) {
@@ -437,7 +493,7 @@ func runQueue(t *testing.T, newCaps Factory) {
 				break
 			}
 			require.NoError(t, q.Nack(ctx, l[0].Receipt, 0))
-			dead, err = q.DeadLetters(ctx, "cq", statestore.Page{})
+			dead, err = q.DeadLetters(ctx, "cq", statestore.Page{}, statestore.DeadLetterFilter{})
 			require.NoError(t, err)
 			if len(dead) > 0 {
 				break
@@ -453,7 +509,7 @@ func runQueue(t *testing.T, newCaps Factory) {
 		n, err = q.Redrive(ctx, "cq", []string{id})
 		require.NoError(t, err)
 		assert.Zero(t, n, "an id that is not dead-lettered is not redriven")
-		dead, err = q.DeadLetters(ctx, "cq", statestore.Page{})
+		dead, err = q.DeadLetters(ctx, "cq", statestore.Page{}, statestore.DeadLetterFilter{})
 		require.NoError(t, err)
 		require.Empty(t, dead)
 		l, err := q.Lease(ctx, "cq", 1, time.Minute)
@@ -478,7 +534,7 @@ func runQueue(t *testing.T, newCaps Factory) {
 		liveID, err := q.Enqueue(ctx, pq, statestore.Message{Body: []byte("live")}, statestore.EnqueueOptions{})
 		require.NoError(t, err)
 
-		dead, err := q.DeadLetters(ctx, pq, statestore.Page{})
+		dead, err := q.DeadLetters(ctx, pq, statestore.Page{}, statestore.DeadLetterFilter{})
 		require.NoError(t, err)
 		require.Len(t, dead, 2)
 
@@ -486,7 +542,7 @@ func runQueue(t *testing.T, newCaps Factory) {
 		require.NoError(t, err)
 		assert.EqualValues(t, 2, n, "Purge returns the count removed")
 
-		dead, err = q.DeadLetters(ctx, pq, statestore.Page{})
+		dead, err = q.DeadLetters(ctx, pq, statestore.Page{}, statestore.DeadLetterFilter{})
 		require.NoError(t, err)
 		assert.Empty(t, dead, "dead set is empty after Purge")
 		st, err := q.Stats(ctx, pq)
@@ -506,6 +562,89 @@ func runQueue(t *testing.T, newCaps Factory) {
 		assert.Zero(t, n)
 	})
 
+	t.Run("DeadLettersFilter", func(t *testing.T) {
+		q := queueOrSkip(t, newCaps)
+		ctx := t.Context()
+		const fq = "filterq"
+		kill := func(reason string) string {
+			t.Helper()
+			id, err := q.Enqueue(ctx, fq, statestore.Message{Body: []byte(reason)}, statestore.EnqueueOptions{})
+			require.NoError(t, err)
+			l, err := q.Lease(ctx, fq, 1, time.Minute)
+			require.NoError(t, err)
+			require.Len(t, l, 1)
+			require.NoError(t, q.Kill(ctx, l[0].Receipt, reason))
+			return id
+		}
+		before := time.Now().Add(-time.Second)
+		brokerID := kill("Broker down: 50%_load")
+		fourID := kill("http_4xx")
+		after := time.Now().Add(time.Second)
+
+		ids := func(f statestore.DeadLetterFilter) []string {
+			t.Helper()
+			dead, err := q.DeadLetters(ctx, fq, statestore.Page{}, f)
+			require.NoError(t, err)
+			var out []string
+			for _, d := range dead {
+				require.True(t, f.Match(d), "a driver must agree with DeadLetterFilter.Match")
+				out = append(out, d.ID)
+			}
+			return out
+		}
+		assert.Len(t, ids(statestore.DeadLetterFilter{}), 2, "the zero filter matches everything")
+		assert.Equal(t, []string{fourID}, ids(statestore.DeadLetterFilter{Reason: "http_4xx"}))
+		assert.Empty(t, ids(statestore.DeadLetterFilter{Reason: "http"}), "Reason is exact, not a prefix")
+		assert.Equal(t, []string{brokerID}, ids(statestore.DeadLetterFilter{ReasonContains: "BROKER"}), "substring is case-insensitive")
+		assert.Equal(t, []string{brokerID}, ids(statestore.DeadLetterFilter{ReasonContains: "50%_"}), "LIKE metacharacters match literally")
+		assert.Empty(t, ids(statestore.DeadLetterFilter{ReasonContains: "5_%"}))
+		assert.Len(t, ids(statestore.DeadLetterFilter{DiedAfter: before, DiedBefore: after}), 2)
+		assert.Empty(t, ids(statestore.DeadLetterFilter{DiedAfter: after}))
+		assert.Empty(t, ids(statestore.DeadLetterFilter{DiedBefore: before}))
+
+		// Paging walks the matches only.
+		dead, err := q.DeadLetters(ctx, fq, statestore.Page{Limit: 1}, statestore.DeadLetterFilter{DiedAfter: before})
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		dead, err = q.DeadLetters(ctx, fq, statestore.Page{Token: dead[0].ID, Limit: 1}, statestore.DeadLetterFilter{DiedAfter: before})
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+	})
+
+	t.Run("DiscardDeadLetters", func(t *testing.T) {
+		q := queueOrSkip(t, newCaps)
+		ctx := t.Context()
+		const dq = "discardq"
+		var deadIDs []string
+		for range 2 {
+			id, err := q.Enqueue(ctx, dq, statestore.Message{Body: []byte("d")}, statestore.EnqueueOptions{})
+			require.NoError(t, err)
+			l, err := q.Lease(ctx, dq, 1, time.Minute)
+			require.NoError(t, err)
+			require.Len(t, l, 1)
+			require.NoError(t, q.Kill(ctx, l[0].Receipt, "permanent"))
+			deadIDs = append(deadIDs, id)
+		}
+		liveID, err := q.Enqueue(ctx, dq, statestore.Message{Body: []byte("live")}, statestore.EnqueueOptions{})
+		require.NoError(t, err)
+
+		// Only the named dead message goes; a live id is skipped, not deleted.
+		n, err := q.Discard(ctx, dq, []string{deadIDs[0], liveID, "no-such-id"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, n, "Discard counts only dead messages removed")
+		dead, err := q.DeadLetters(ctx, dq, statestore.Page{}, statestore.DeadLetterFilter{})
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, deadIDs[1], dead[0].ID)
+		st, err := q.Stats(ctx, dq)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, st.Visible, "Discard leaves live work untouched")
+
+		n, err = q.Discard(ctx, dq, nil)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
 	t.Run("Stats", func(t *testing.T) {
 		q := queueOrSkip(t, newCaps)
 		ctx := t.Context()
@@ -609,7 +748,7 @@ func runExhaustedByExpiry(t *testing.T, newCaps Factory) {
 			if len(l) > 0 {
 				time.Sleep(2 * time.Minute) // let the lease expire
 			}
-			dead, err = q.DeadLetters(ctx, "cq", statestore.Page{})
+			dead, err = q.DeadLetters(ctx, "cq", statestore.Page{}, statestore.DeadLetterFilter{})
 			require.NoError(t, err)
 			if len(dead) > 0 || len(l) == 0 {
 				break
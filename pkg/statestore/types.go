@@ -6,6 +6,7 @@ package statestore
 
 import (
 	"context"
+	"strings"
 	"time"
 )
 
@@ -71,14 +72,27 @@ type KeyPage struct {
 
 // Event is one entry in an EventLog stream. On Append, Seq and At are assigned by
 // the store (callers leave them zero). Payload is opaque bytes (JSON for the
-// jsonb-backed drivers); Type is a short domain discriminator.
+// jsonb-backed drivers); Type is a short domain discriminator. Key is an optional
+// compaction key: EventLog.Compact drops a keyed event once a later event with
+// the same Key exists; unkeyed events are never compacted.
 type Event struct {
 	Seq     int64
 	Type    string
+	Key     string
 	Payload []byte
 	At      time.Time
 }
 
+// StreamStats is a point-in-time snapshot of what one EventLog stream retains,
+// powering per-topic retention by size (RFC-0027) and the topic admin views.
+type StreamStats struct {
+	// Events is the number of retained events (head minus floor, less any
+	// compacted holes).
+	Events int64
+	// Bytes is the total payload size of the retained events.
+	Bytes int64
+}
+
 // Message is an item to enqueue. Body is opaque; the consumer encodes its own
 // envelope (function reference, headers, depth, ...) into it.
 type Message struct {
@@ -109,6 +123,47 @@ type DeadMessage struct {
 	DiedAt     time.Time
 }
 
+// DeadLetterFilter narrows DeadLetters to the dead messages an operator is
+// looking for during an incident. Every set field must match (AND); the zero
+// filter matches everything. It deliberately covers only the driver-visible
+// columns — the body is opaque to the store, so filters that need a decoded
+// envelope (function, namespace) are applied by the consumer above it.
+//
+//   - Reason: exact match on the dead-letter reason.
+//   - ReasonContains: case-insensitive substring of the reason (the free-text
+//     "error" of a Kill).
+//   - DiedAfter / DiedBefore: DiedAt in [DiedAfter, DiedBefore); a zero bound
+//     is open.
+type DeadLetterFilter struct {
+	Reason         string    `json:"reason,omitempty"`
+	ReasonContains string    `json:"reasonContains,omitempty"`
+	DiedAfter      time.Time `json:"diedAfter,omitzero"`
+	DiedBefore     time.Time `json:"diedBefore,omitzero"`
+}
+
+// IsZero reports whether the filter matches every dead message.
+func (f DeadLetterFilter) IsZero() bool {
+	return f.Reason == "" && f.ReasonContains == "" && f.DiedAfter.IsZero() && f.DiedBefore.IsZero()
+}
+
+// Match reports whether d passes the filter. It is the reference semantics every
+// driver's native filtering must agree with (the memory driver uses it directly).
+func (f DeadLetterFilter) Match(d DeadMessage) bool {
+	if f.Reason != "" && d.Reason != f.Reason {
+		return false
+	}
+	if f.ReasonContains != "" && !strings.Contains(strings.ToLower(d.Reason), strings.ToLower(f.ReasonContains)) {
+		return false
+	}
+	if !f.DiedAfter.IsZero() && d.DiedAt.Before(f.DiedAfter) {
+		return false
+	}
+	if !f.DiedBefore.IsZero() && !d.DiedAt.Before(f.DiedBefore) {
+		return false
+	}
+	return true
+}
+
 // EnqueueOptions controls an enqueue.
 //
 //   - Delay: the earliest lease time is now+Delay (0 means immediately leasable).
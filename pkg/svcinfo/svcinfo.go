@@ -69,6 +69,7 @@ const (
 const (
 	SvcRouter         = "router"
 	SvcRouterInternal = "router-internal"
+	SvcRouterPeers    = "router-peers"
 	SvcExecutor       = "executor"
 	SvcStorage        = "storagesvc"
 	SvcWebhook        = "webhook-service"
@@ -90,6 +91,12 @@ func RouterInternalURL(namespace string) string {
 	return fmt.Sprintf("http://%s.%s:%d", SvcRouterInternal, namespace, PortRouterInternal)
 }
 
+// RouterPeersHost returns the DNS name of the headless Service over the router
+// replicas' internal listeners; it resolves to one address per replica.
+func RouterPeersHost(namespace string) string {
+	return fmt.Sprintf("%s.%s", SvcRouterPeers, namespace)
+}
+
 // ExecutorURL returns the in-cluster URL of the executor API (Service port
 // 80 in the chart, so no explicit port).
 func ExecutorURL(namespace string) string {
@@ -123,3 +123,36 @@ func TestTemplateWithEncodedPath(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, "a%20b", gotVars["name"], "matches EscapedPath; var keeps the raw encoding")
 }
+
+// TestMatcherFuncFallsThrough: a route whose MatcherFunc rejects the request
+// is skipped in favour of a later registration at the same path, on both the
+// exact fast path and the scan (prefix) path.
+func TestMatcherFuncFallsThrough(t *testing.T) {
+	t.Parallel()
+	v2 := func(r *http.Request) bool { return r.Header.Get("X-Api-Version") == "2" }
+	m := New()
+	m.Handle("/api", ok("exact-v2")).Methods("GET").MatcherFunc(v2)
+	m.Handle("/api", ok("exact")).Methods("GET")
+	m.HandlePrefix("/pfx/", ok("prefix-v2")).Methods("GET").MatcherFunc(v2)
+	m.HandlePrefix("/pfx/", ok("prefix")).Methods("GET")
+	h := m.Handler()
+
+	for _, path := range []string{"/api", "/pfx/x"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Api-Version", "2")
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		assert.Contains(t, rr.Body.String(), "v2", "%s: matching request takes the matcher route", path)
+		assert.NotContains(t, do(t, h, http.MethodGet, path).Body.String(), "v2",
+			"%s: other requests fall through", path)
+	}
+}
+
+// TestMatcherFuncMismatchIs404Not405: like a host mismatch, a rejected
+// request must not poison the 405 flag.
+func TestMatcherFuncMismatchIs404Not405(t *testing.T) {
+	t.Parallel()
+	m := New()
+	m.Handle("/x", ok("")).Methods("GET").MatcherFunc(func(*http.Request) bool { return false })
+	assert.Equal(t, http.StatusNotFound, do(t, m.Handler(), http.MethodPost, "/x").Code)
+}
@@ -42,14 +42,15 @@ const (
 )
 
 // Route is one registration. Handle/HandlePrefix return it so callers can
-// fluently restrict the method set and host (gorilla-style, keeping method and
-// path separate for readability).
+// fluently restrict the method set, host and any other request property
+// (gorilla-style, keeping method and path separate for readability).
 type Route struct {
 	pattern    string
 	kind       MatchKind
 	methods    []string
 	methodsSet bool   // true once Methods() is called; an explicit empty set matches NO method
 	host       string // "" = any host
+	matcher    func(*http.Request) bool
 	handler    http.Handler
 }
 
@@ -70,6 +71,21 @@ func (r *Route) Host(host string) *Route {
 	return r
 }
 
+// MatcherFunc restricts the route to requests fn accepts — the router's
+// header/query/host match rules. fn runs only for requests the route's host
+// and path already match. A request it rejects is treated like one for
+// another host: the scan moves on to later routes and, when nothing else
+// matches, answers 404 rather than 405 (the route did not match, whatever
+// its methods). fn must be safe for concurrent use.
+func (r *Route) MatcherFunc(fn func(*http.Request) bool) *Route {
+	r.matcher = fn
+	return r
+}
+
+func (r *Route) matchesRequest(req *http.Request) bool {
+	return r.matcher == nil || r.matcher(req)
+}
+
 func (r *Route) matchesMethod(method string) bool {
 	if !r.methodsSet {
 		return true // no method restriction → any method
@@ -211,7 +227,7 @@ func (m *Mux) Handler() http.Handler {
 }
 
 // Match reports the pattern of the first route that would handle req — matched
-// by host, path, and method, in registration order, exactly as ServeHTTP does
+// by host, path, matcher func and method, in registration order, exactly as ServeHTTP does
 // — and whether any route matched.
 //
 // It is for TESTS AND DIAGNOSTICS, not the request path: it recompiles every
@@ -289,6 +305,9 @@ type dispatcher struct {
 // entirely within that bucket (no other route can match the path), replicating
 // the scan's host/method/405 behaviour; every other request falls through to
 // the scan. Excluding a route from the map never changes behaviour, only speed.
+// A MatcherFunc does not affect eligibility: it is checked in bucket order
+// like the host, so a rejected request falls through to the next route in the
+// bucket exactly as the scan would fall through to it.
 func buildExactIndex(routes []*compiledRoute) map[string][]*compiledRoute {
 	// Matchers split two ways. A literal prefix Q shadows path P iff
 	// P[:len(Q)] == Q, so prefixes go into a set probed by length — O(distinct
@@ -365,6 +384,9 @@ func (d *dispatcher) match(r *http.Request) (matched *compiledRoute, vars map[st
 			if rt.host != "" && rt.host != r.Host {
 				continue
 			}
+			if !rt.matchesRequest(r) {
+				continue
+			}
 			if !rt.matchesMethod(r.Method) {
 				methodNotAllowed = true
 				continue
@@ -381,7 +403,7 @@ func (d *dispatcher) match(r *http.Request) (matched *compiledRoute, vars map[st
 			continue
 		}
 		ok, v := cr.matchPath(path)
-		if !ok {
+		if !ok || !rt.matchesRequest(r) {
 			continue
 		}
 		if !rt.matchesMethod(r.Method) {
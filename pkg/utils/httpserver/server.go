@@ -63,6 +63,10 @@ type ServerOptions struct {
 	Listener net.Listener
 	// Handler is the HTTP handler to serve.
 	Handler http.Handler
+	// UnencryptedHTTP2 also accepts HTTP/2 without TLS (h2c, prior
+	// knowledge) next to HTTP/1, for gRPC clients talking to a cleartext
+	// listener.
+	UnencryptedHTTP2 bool
 }
 
 // Serve runs an HTTP server until ctx is cancelled, then drains in-flight
@@ -87,6 +91,11 @@ func Serve(ctx context.Context, log logr.Logger, mgr *errgroup.Group, opts Serve
 		// failures on non-idempotent internal POSTs.
 		IdleTimeout: 120 * time.Second,
 	}
+	if opts.UnencryptedHTTP2 {
+		server.Protocols = new(http.Protocols)
+		server.Protocols.SetHTTP1(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+	}
 	displayAddr := server.Addr
 	if opts.Listener != nil {
 		displayAddr = opts.Listener.Addr().String()
@@ -44,6 +44,16 @@ func Int64Counter(name, help string) metric.Int64Counter {
 	return c
 }
 
+// Float64Counter creates a monotonic counter of fractional amounts, such as
+// resource-seconds. Naming follows Int64Counter.
+func Float64Counter(name, help string) metric.Float64Counter {
+	c, err := Meter().Float64Counter(name, metric.WithDescription(help))
+	if err != nil {
+		panic(fmt.Sprintf("metrics: create counter %q: %v", name, err))
+	}
+	return c
+}
+
 // Float64Histogram creates a histogram with explicit bucket boundaries. The
 // boundaries are reproduced exactly (e.g. prometheus.DefBuckets) so the exposed
 // _bucket le series match the pre-migration layout; the exporter appends the
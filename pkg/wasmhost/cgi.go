@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmhost
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cgiEnv returns the environment a module instance runs a request with, in
+// the manner of CGI (RFC 3875): the request line and body metadata as
+// REQUEST_METHOD, PATH_INFO, QUERY_STRING, SERVER_PROTOCOL, CONTENT_TYPE,
+// CONTENT_LENGTH and REMOTE_ADDR, and every other header as HTTP_<NAME>.
+// Sorted, so a module sees the same environment for the same request.
+func cgiEnv(r *http.Request) [][2]string {
+	env := [][2]string{
+		{"REQUEST_METHOD", r.Method},
+		{"PATH_INFO", r.URL.Path},
+		{"QUERY_STRING", r.URL.RawQuery},
+		{"SERVER_PROTOCOL", r.Proto},
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		env = append(env, [2]string{"REMOTE_ADDR", host})
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		env = append(env, [2]string{"CONTENT_TYPE", ct})
+	}
+	if r.ContentLength >= 0 {
+		env = append(env, [2]string{"CONTENT_LENGTH", strconv.FormatInt(r.ContentLength, 10)})
+	}
+	for name, values := range r.Header {
+		if name == "Content-Type" || name == "Content-Length" {
+			continue
+		}
+		key := "HTTP_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		env = append(env, [2]string{key, strings.Join(values, ", ")})
+	}
+	sort.Slice(env, func(i, j int) bool { return env[i][0] < env[j][0] })
+	return env
+}
+
+// writeCGIResponse writes a module's output as the response: header lines, a
+// blank line, then the body. A Status header sets the status code, else a
+// Location header redirects with 302, else the status is 200. A module that
+// writes nothing answers 200 with an empty body.
+func writeCGIResponse(w http.ResponseWriter, out []byte) error {
+	if len(out) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+	br := bufio.NewReader(bytes.NewReader(out))
+	header, err := textproto.NewReader(br).ReadMIMEHeader()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("no blank line after the response headers")
+		}
+		return fmt.Errorf("invalid response headers: %w", err)
+	}
+
+	status := http.StatusOK
+	if s := header.Get("Status"); s != "" {
+		code, err := strconv.Atoi(strings.Fields(s)[0])
+		if err != nil || code < 100 || code > 999 {
+			return fmt.Errorf("invalid status %q", s)
+		}
+		status = code
+	} else if header.Get("Location") != "" {
+		status = http.StatusFound
+	}
+	header.Del("Status")
+	// The body is already complete; let the server frame it.
+	header.Del("Content-Length")
+
+	for name, values := range header {
+		for _, v := range values {
+			w.Header().Add(name, v)
+		}
+	}
+	w.WriteHeader(status)
+	_, err = io.Copy(w, br)
+	return err
+}
+
+// cappedBuffer collects up to max bytes of a module's output and fails the
+// writes past it, which the module sees as an I/O error on the stream.
+type cappedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); len(p) > room {
+		b.truncated = true
+		n, _ := b.Buffer.Write(p[:max(room, 0)])
+		return n, io.ErrShortWrite
+	}
+	return b.Buffer.Write(p)
+}
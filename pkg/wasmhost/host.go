@@ -0,0 +1,445 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmhost
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	"github.com/fission/fission/pkg/fetcher"
+)
+
+// Request headers the host selects the module by. The router sets both on
+// every request it proxies to a function.
+const (
+	HeaderFunctionUID        = "X-Fission-Function-Uid"
+	HeaderFunctionGeneration = "X-Fission-Function-Generation"
+)
+
+const (
+	// maxStdout bounds the response a module may write; a module writing
+	// more is answered 502.
+	maxStdout = 64 << 20
+	// maxStderr bounds how much of a module's stderr is kept for the log.
+	maxStderr = 4 << 10
+)
+
+// Host runs the WASI modules of the functions it has been specialized with.
+// Each module gets its own runtime, so its memory limit is its own; compiled
+// code is shared through one compilation cache. A module evicted when its
+// function goes idle is compiled again on its next request.
+type Host struct {
+	logger logr.Logger
+	root   string
+	cache  wazero.CompilationCache
+	// control serves the specialization API behind the verifier.
+	control http.Handler
+
+	mu      sync.RWMutex
+	modules map[string]*module // by function UID and generation
+}
+
+// module is one function generation loaded into the host.
+type module struct {
+	meta metav1.ObjectMeta
+	// archive is the package the fetcher deployed, removed when the function
+	// is purged; path is the module file in it.
+	archive string
+	path    string
+	pages   uint32
+	timeout time.Duration
+
+	// mu is held for reading by every running instance, so evict waits for
+	// them to finish before closing the runtime.
+	mu       sync.RWMutex
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// NewHost returns a host loading modules from under root. verifier wraps the
+// specialization API: the host shares its port with the functions of the
+// whole namespace, so only the fetcher and the executor may load or unload
+// modules.
+func NewHost(logger logr.Logger, root string, verifier func(http.Handler) http.Handler) *Host {
+	h := &Host{
+		logger:  logger,
+		root:    filepath.Clean(root),
+		cache:   wazero.NewCompilationCache(),
+		modules: make(map[string]*module),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/specialize", h.specializeHandler)
+	mux.HandleFunc("GET /v2/loaded", h.loadedHandler)
+	mux.HandleFunc("POST /v2/unload", h.unloadHandler)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h.control = verifier(mux)
+	return h
+}
+
+// Close releases every loaded module and the compilation cache.
+func (h *Host) Close(ctx context.Context) error {
+	h.mu.Lock()
+	modules := h.modules
+	h.modules = make(map[string]*module)
+	h.mu.Unlock()
+	for _, m := range modules {
+		m.evict(ctx)
+	}
+	return h.cache.Close(ctx)
+}
+
+// ServeHTTP runs the function a request is addressed to, or serves the
+// specialization API for requests that carry no function UID.
+func (h *Host) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(HeaderFunctionUID) != "" {
+		h.invoke(w, r)
+		return
+	}
+	h.control.ServeHTTP(w, r)
+}
+
+func moduleKey(uid string, generation int64) string {
+	return uid + "_" + strconv.FormatInt(generation, 10)
+}
+
+// Load compiles the module a fetcher deployed for a function and makes it
+// servable, replacing a module loaded earlier for the same generation.
+func (h *Host) Load(ctx context.Context, req fetcher.FunctionLoadRequest) error {
+	if req.FunctionMetadata == nil || req.FunctionMetadata.UID == "" {
+		return errors.New("function metadata is required")
+	}
+	archive := filepath.Clean(req.FilePath)
+	if !within(h.root, archive) {
+		return fmt.Errorf("module path %q is outside %s", req.FilePath, h.root)
+	}
+	path, err := modulePath(archive, req.FunctionName)
+	if err != nil {
+		return err
+	}
+	cfg := req.Wasm.Effective()
+	timeout, err := time.ParseDuration(cfg.MaxExecutionTime)
+	if err != nil {
+		return fmt.Errorf("max execution time is invalid: %w", err)
+	}
+	m := &module{
+		meta:    *req.FunctionMetadata,
+		archive: archive,
+		path:    path,
+		pages:   uint32(cfg.MaxMemory.Value() / fv1.WasmPageSize),
+		timeout: timeout,
+	}
+	// Compile now, so a module that does not compile fails specialization
+	// rather than its first request.
+	if _, _, err := m.acquire(ctx, h.cache); err != nil {
+		return err
+	}
+	m.mu.RUnlock()
+
+	key := moduleKey(string(m.meta.UID), m.meta.Generation)
+	h.mu.Lock()
+	old := h.modules[key]
+	h.modules[key] = m
+	h.mu.Unlock()
+	if old != nil {
+		old.evict(ctx)
+	}
+	h.logger.Info("loaded module", "function", m.meta.Name, "namespace", m.meta.Namespace,
+		"generation", m.meta.Generation, "path", path, "maxMemory", cfg.MaxMemory.String(), "maxExecutionTime", timeout)
+	return nil
+}
+
+// Loaded reports whether the host can serve the function generation.
+func (h *Host) Loaded(uid string, generation int64) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.modules[moduleKey(uid, generation)]
+	return ok
+}
+
+// Unload evicts the compiled modules of a function, of one generation or of
+// all when generation is zero, and returns how many it evicted. An evicted
+// module is compiled again on its next request; with purge the host forgets
+// the module instead and removes its package from the shared volume.
+func (h *Host) Unload(ctx context.Context, uid string, generation int64, purge bool) int {
+	var unloaded []*module
+	h.mu.Lock()
+	for key, m := range h.modules {
+		if string(m.meta.UID) != uid || (generation != 0 && m.meta.Generation != generation) {
+			continue
+		}
+		unloaded = append(unloaded, m)
+		if purge {
+			delete(h.modules, key)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, m := range unloaded {
+		m.evict(ctx)
+		if purge {
+			if err := os.RemoveAll(m.archive); err != nil {
+				h.logger.Error(err, "error removing module package", "function", m.meta.Name,
+					"namespace", m.meta.Namespace, "path", m.archive)
+			}
+		}
+	}
+	return len(unloaded)
+}
+
+// lookup returns the module of a function generation, or of the newest
+// generation loaded when generation is empty.
+func (h *Host) lookup(uid, generation string) (*module, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if generation != "" {
+		gen, err := strconv.ParseInt(generation, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s header %q", HeaderFunctionGeneration, generation)
+		}
+		return h.modules[moduleKey(uid, gen)], nil
+	}
+	var newest *module
+	for _, m := range h.modules {
+		if string(m.meta.UID) == uid && (newest == nil || m.meta.Generation > newest.meta.Generation) {
+			newest = m
+		}
+	}
+	return newest, nil
+}
+
+// acquire returns the module's runtime and compiled code, compiling it
+// first if it was evicted. On success the caller holds m.mu for reading and
+// releases it once its instance is done.
+func (m *module) acquire(ctx context.Context, cache wazero.CompilationCache) (wazero.Runtime, wazero.CompiledModule, error) {
+	for {
+		m.mu.RLock()
+		if m.compiled != nil {
+			return m.runtime, m.compiled, nil
+		}
+		m.mu.RUnlock()
+
+		m.mu.Lock()
+		if m.compiled == nil {
+			if err := m.compile(ctx, cache); err != nil {
+				m.mu.Unlock()
+				return nil, nil, err
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// compile builds the module's runtime; the caller holds m.mu.
+func (m *module) compile(ctx context.Context, cache wazero.CompilationCache) error {
+	bin, err := os.ReadFile(m.path)
+	if err != nil {
+		return fmt.Errorf("error reading module: %w", err)
+	}
+	rt := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(m.pages).
+		WithCloseOnContextDone(true).
+		WithCompilationCache(cache))
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, rt); err != nil {
+		_ = rt.Close(ctx)
+		return fmt.Errorf("error instantiating WASI: %w", err)
+	}
+	compiled, err := rt.CompileModule(ctx, bin)
+	if err != nil {
+		_ = rt.Close(ctx)
+		return fmt.Errorf("error compiling module: %w", err)
+	}
+	m.runtime, m.compiled = rt, compiled
+	return nil
+}
+
+// evict closes the module's runtime once its running instances are done.
+func (m *module) evict(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.runtime != nil {
+		_ = m.runtime.Close(ctx)
+	}
+	m.runtime, m.compiled = nil, nil
+}
+
+// invoke runs one instance of the function's module for the request.
+func (h *Host) invoke(w http.ResponseWriter, r *http.Request) {
+	uid := r.Header.Get(HeaderFunctionUID)
+	m, err := h.lookup(uid, r.Header.Get(HeaderFunctionGeneration))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if m == nil {
+		// Not loaded yet, or lost with a host restart: the executor
+		// specializes the host again once the router re-resolves.
+		http.Error(w, fmt.Sprintf("function %s is not loaded", uid), http.StatusServiceUnavailable)
+		return
+	}
+	logger := h.logger.WithValues("function", m.meta.Name, "namespace", m.meta.Namespace)
+
+	rt, compiled, err := m.acquire(r.Context(), h.cache)
+	if err != nil {
+		logger.Error(err, "error loading module")
+		http.Error(w, "error loading module", http.StatusInternalServerError)
+		return
+	}
+	defer m.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), m.timeout)
+	defer cancel()
+	stdout := &cappedBuffer{max: maxStdout}
+	stderr := &cappedBuffer{max: maxStderr}
+	cfg := wazero.NewModuleConfig().
+		// Anonymous, so concurrent instances of the module do not collide.
+		WithName("").
+		WithArgs(m.meta.Name).
+		WithStdin(r.Body).
+		WithStdout(stdout).
+		WithStderr(stderr).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader)
+	for _, kv := range cgiEnv(r) {
+		cfg = cfg.WithEnv(kv[0], kv[1])
+	}
+
+	start := time.Now()
+	mod, err := rt.InstantiateModule(ctx, compiled, cfg)
+	if mod != nil {
+		_ = mod.Close(context.Background())
+	}
+	if stderr.Len() > 0 {
+		logger.Info("module stderr", "output", strings.TrimSpace(stderr.String()))
+	}
+	if exitErr, ok := errors.AsType[*sys.ExitError](err); ok && exitErr.ExitCode() == 0 {
+		err = nil
+	}
+	if err != nil {
+		switch {
+		case ctx.Err() != nil && r.Context().Err() == nil:
+			logger.Info("module exceeded its execution time", "maxExecutionTime", m.timeout)
+			http.Error(w, fmt.Sprintf("function exceeded its execution time of %v", m.timeout), http.StatusGatewayTimeout)
+		case r.Context().Err() != nil:
+			logger.V(1).Info("request cancelled while the module ran", "elapsed", time.Since(start))
+		default:
+			logger.Info("module failed", "error", err.Error(), "elapsed", time.Since(start))
+			http.Error(w, "function failed", http.StatusInternalServerError)
+		}
+		return
+	}
+	if stdout.truncated {
+		logger.Info("module response exceeds the limit", "limit", maxStdout)
+		http.Error(w, "function response too large", http.StatusBadGateway)
+		return
+	}
+	if err := writeCGIResponse(w, stdout.Bytes()); err != nil {
+		logger.Info("malformed module response", "error", err.Error())
+		http.Error(w, "malformed function response", http.StatusBadGateway)
+	}
+}
+
+func (h *Host) specializeHandler(w http.ResponseWriter, r *http.Request) {
+	var req fetcher.FunctionLoadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "failed to parse request", http.StatusBadRequest)
+		return
+	}
+	if err := h.Load(r.Context(), req); err != nil {
+		h.logger.Error(err, "error loading module", "path", req.FilePath)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Host) loadedHandler(w http.ResponseWriter, r *http.Request) {
+	uid := r.URL.Query().Get("uid")
+	gen, err := strconv.ParseInt(r.URL.Query().Get("generation"), 10, 64)
+	if uid == "" || err != nil {
+		http.Error(w, "uid and generation are required", http.StatusBadRequest)
+		return
+	}
+	if !h.Loaded(uid, gen) {
+		http.Error(w, "not loaded", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Host) unloadHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	uid := q.Get("uid")
+	if uid == "" {
+		http.Error(w, "uid is required", http.StatusBadRequest)
+		return
+	}
+	var gen int64
+	if g := q.Get("generation"); g != "" {
+		var err error
+		if gen, err = strconv.ParseInt(g, 10, 64); err != nil {
+			http.Error(w, "invalid generation", http.StatusBadRequest)
+			return
+		}
+	}
+	purge := q.Get("purge") == "true"
+	n := h.Unload(r.Context(), uid, gen, purge)
+	h.logger.V(1).Info("unloaded modules", "uid", uid, "generation", gen, "purge", purge, "count", n)
+	w.WriteHeader(http.StatusOK)
+}
+
+// modulePath resolves the module file of a deployed package: the package
+// itself when it is a file, else the file named by the function's entrypoint
+// or the package's only .wasm file.
+func modulePath(archive, entrypoint string) (string, error) {
+	fi, err := os.Stat(archive)
+	if err != nil {
+		return "", fmt.Errorf("error reading package: %w", err)
+	}
+	if !fi.IsDir() {
+		return archive, nil
+	}
+	if entrypoint != "" {
+		path := filepath.Join(archive, entrypoint)
+		if !within(archive, path) {
+			return "", fmt.Errorf("entrypoint %q is outside the package", entrypoint)
+		}
+		return path, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(archive, "*.wasm"))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) != 1 {
+		return "", fmt.Errorf("package holds %d .wasm files; set the function's entrypoint to the module to run", len(matches))
+	}
+	return matches[0], nil
+}
+
+// within reports whether path lies strictly under dir.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
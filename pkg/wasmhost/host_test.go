@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmhost
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
+	"github.com/fission/fission/pkg/fetcher"
+)
+
+// The modules under testdata are built from the .wat files next to them.
+
+func newTestHost(t *testing.T) *Host {
+	t.Helper()
+	return newTestHostWithVerifier(t, func(next http.Handler) http.Handler { return next })
+}
+
+func newTestHostWithVerifier(t *testing.T, verifier func(http.Handler) http.Handler) *Host {
+	t.Helper()
+	h := NewHost(logr.Discard(), t.TempDir(), verifier)
+	t.Cleanup(func() { _ = h.Close(t.Context()) })
+	return h
+}
+
+// deploy copies a testdata module into a package directory under the host's
+// root, as the fetcher would, and returns the load request for it.
+func deploy(t *testing.T, h *Host, fixture, uid string, generation int64, cfg *fv1.WasmConfig) fetcher.FunctionLoadRequest {
+	t.Helper()
+	bin, err := os.ReadFile(filepath.Join("testdata", fixture+".wasm"))
+	require.NoError(t, err)
+	dir := filepath.Join(h.root, uid+"-"+strconv.FormatInt(generation, 10))
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, fixture+".wasm"), bin, 0o644))
+	return fetcher.FunctionLoadRequest{
+		FilePath: dir,
+		FunctionMetadata: &metav1.ObjectMeta{
+			Name: fixture, Namespace: "default", UID: types.UID(uid), Generation: generation,
+		},
+		EnvVersion: 2,
+		Wasm:       cfg,
+	}
+}
+
+func call(h *Host, uid, generation, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/fn?x=1", strings.NewReader(body))
+	req.Header.Set(HeaderFunctionUID, uid)
+	if generation != "" {
+		req.Header.Set(HeaderFunctionGeneration, generation)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestInvoke(t *testing.T) {
+	h := newTestHost(t)
+	require.NoError(t, h.Load(t.Context(), deploy(t, h, "echo", "uid-echo", 1, nil)))
+
+	rec := call(h, "uid-echo", "1", "hello wasm")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "hello wasm", rec.Body.String())
+
+	// Instances are independent, so concurrent requests do not collide.
+	done := make(chan string, 8)
+	for i := range 8 {
+		go func() { done <- call(h, "uid-echo", "1", strconv.Itoa(i)).Body.String() }()
+	}
+	var got []string
+	for range 8 {
+		got = append(got, <-done)
+	}
+	assert.ElementsMatch(t, []string{"0", "1", "2", "3", "4", "5", "6", "7"}, got)
+
+	assert.Equal(t, http.StatusServiceUnavailable, call(h, "uid-unknown", "1", "").Code)
+	assert.Equal(t, http.StatusBadRequest, call(h, "uid-echo", "one", "").Code)
+}
+
+func TestInvokeLimits(t *testing.T) {
+	h := newTestHost(t)
+	require.NoError(t, h.Load(t.Context(), deploy(t, h, "spin", "uid-spin", 1,
+		&fv1.WasmConfig{MaxExecutionTime: "50ms"})))
+	require.NoError(t, h.Load(t.Context(), deploy(t, h, "grow", "uid-grow-small", 1,
+		&fv1.WasmConfig{MaxMemory: new(resource.MustParse("1Mi"))})))
+	require.NoError(t, h.Load(t.Context(), deploy(t, h, "grow", "uid-grow", 1, nil)))
+
+	assert.Equal(t, http.StatusGatewayTimeout, call(h, "uid-spin", "1", "").Code,
+		"a module running past its execution time is stopped")
+	assert.Equal(t, http.StatusInternalServerError, call(h, "uid-grow-small", "1", "").Code,
+		"a module growing past its memory limit traps")
+	rec := call(h, "uid-grow", "1", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "the default limit admits the growth")
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestSpecializationAPI(t *testing.T) {
+	h := newTestHost(t)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	specialize := func(req fetcher.FunctionLoadRequest) int {
+		t.Helper()
+		body, err := json.Marshal(req)
+		require.NoError(t, err)
+		resp, err := http.Post(srv.URL+"/v2/specialize", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	status := func(method, path string) int {
+		t.Helper()
+		req, err := http.NewRequestWithContext(t.Context(), method, srv.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	gen1 := deploy(t, h, "grow", "uid-fn", 1, nil)
+	require.Equal(t, http.StatusOK, specialize(gen1))
+	require.Equal(t, http.StatusOK, specialize(deploy(t, h, "echo", "uid-fn", 2, nil)))
+	assert.Equal(t, http.StatusOK, status(http.MethodGet, "/v2/loaded?uid=uid-fn&generation=1"))
+	assert.Equal(t, http.StatusNotFound, status(http.MethodGet, "/v2/loaded?uid=uid-fn&generation=3"))
+
+	// Without a generation header the newest generation serves.
+	assert.Equal(t, http.StatusCreated, call(h, "uid-fn", "", "").Code)
+	assert.Equal(t, http.StatusOK, call(h, "uid-fn", "1", "").Code)
+
+	// An evicted module stays loaded and compiles again on its next request.
+	require.Equal(t, http.StatusOK, status(http.MethodPost, "/v2/unload?uid=uid-fn&generation=1"))
+	assert.Equal(t, http.StatusOK, status(http.MethodGet, "/v2/loaded?uid=uid-fn&generation=1"))
+	assert.Equal(t, http.StatusOK, call(h, "uid-fn", "1", "").Code)
+
+	// A purged function is forgotten and its packages removed.
+	require.Equal(t, http.StatusOK, status(http.MethodPost, "/v2/unload?uid=uid-fn&purge=true"))
+	assert.Equal(t, http.StatusNotFound, status(http.MethodGet, "/v2/loaded?uid=uid-fn&generation=2"))
+	assert.Equal(t, http.StatusServiceUnavailable, call(h, "uid-fn", "", "").Code)
+	assert.NoDirExists(t, gen1.FilePath)
+
+	// Packages outside the shared volume and modules that do not compile
+	// fail specialization.
+	outside := deploy(t, h, "echo", "uid-outside", 1, nil)
+	outside.FilePath = t.TempDir()
+	assert.Equal(t, http.StatusInternalServerError, specialize(outside))
+	bad := deploy(t, h, "echo", "uid-bad", 1, nil)
+	require.NoError(t, os.WriteFile(filepath.Join(bad.FilePath, "echo.wasm"), []byte("not wasm"), 0o644))
+	assert.Equal(t, http.StatusInternalServerError, specialize(bad))
+	assert.Equal(t, http.StatusBadRequest, status(http.MethodPost, "/v2/specialize"))
+}
+
+func TestSpecializationAPIRequiresSignature(t *testing.T) {
+	master := []byte("master")
+	h := newTestHostWithVerifier(t, hmacauth.ServiceVerifier(master, nil, hmacauth.ServiceFetcher, hmacauth.VerifierOpts{
+		SkewSec: 60, Bypass: []string{"/healthz"}, MaxBodyBytes: hmacauth.DefaultMaxBodyBytes,
+	}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	signed := &http.Client{Transport: hmacauth.ServiceSigner(master, hmacauth.ServiceFetcher, http.DefaultTransport, time.Now)}
+
+	do := func(client *http.Client, method, path string, body []byte) int {
+		t.Helper()
+		req, err := http.NewRequestWithContext(t.Context(), method, srv.URL+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	load := deploy(t, h, "echo", "uid-echo", 1, nil)
+	body, err := json.Marshal(load)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, do(http.DefaultClient, http.MethodPost, "/v2/specialize", body))
+	assert.Equal(t, http.StatusOK, do(signed, http.MethodPost, "/v2/specialize", body))
+
+	assert.Equal(t, http.StatusUnauthorized, do(http.DefaultClient, http.MethodGet, "/v2/loaded?uid=uid-echo&generation=1", nil))
+	assert.Equal(t, http.StatusUnauthorized, do(http.DefaultClient, http.MethodPost, "/v2/unload?uid=uid-echo&purge=true", nil))
+	assert.DirExists(t, load.FilePath, "an unsigned purge must not remove the package")
+	assert.Equal(t, http.StatusOK, do(signed, http.MethodGet, "/v2/loaded?uid=uid-echo&generation=1", nil))
+
+	// Probes and invocations are not signed.
+	assert.Equal(t, http.StatusOK, do(http.DefaultClient, http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusCreated, call(h, "uid-echo", "1", "hi").Code)
+}
+
+func TestCGI(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/a/b?q=1", strings.NewReader("body"))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Add("X-Trace", "a")
+	req.Header.Add("X-Trace", "b")
+	env := make(map[string]string)
+	for _, kv := range cgiEnv(req) {
+		env[kv[0]] = kv[1]
+	}
+	assert.Equal(t, map[string]string{
+		"REQUEST_METHOD":  "PUT",
+		"PATH_INFO":       "/a/b",
+		"QUERY_STRING":    "q=1",
+		"SERVER_PROTOCOL": "HTTP/1.1",
+		"REMOTE_ADDR":     "192.0.2.1",
+		"CONTENT_TYPE":    "application/json",
+		"CONTENT_LENGTH":  "4",
+		"HTTP_X_TRACE":    "a, b",
+	}, env)
+
+	tests := []struct {
+		name      string
+		out       string
+		status    int
+		header    http.Header
+		body      string
+		malformed bool
+	}{
+		{name: "empty", out: "", status: http.StatusOK},
+		{name: "headers and body", out: "Content-Type: text/html\r\nX-A: 1\r\n\r\n<p>hi</p>", status: http.StatusOK,
+			header: http.Header{"Content-Type": {"text/html"}, "X-A": {"1"}}, body: "<p>hi</p>"},
+		{name: "status", out: "Status: 404 Not Found\n\nmissing", status: http.StatusNotFound, body: "missing"},
+		{name: "location redirects", out: "Location: /elsewhere\n\n", status: http.StatusFound,
+			header: http.Header{"Location": {"/elsewhere"}}},
+		{name: "content length is recomputed", out: "Content-Length: 99\n\nshort", status: http.StatusOK, body: "short"},
+		{name: "no blank line", out: "just a body", malformed: true},
+		{name: "bad status", out: "Status: ok\n\n", malformed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := writeCGIResponse(rec, []byte(tt.out))
+			if tt.malformed {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+			for name, values := range tt.header {
+				assert.Equal(t, values, rec.Header().Values(name))
+			}
+			assert.Empty(t, rec.Header().Get("Status"))
+			assert.Empty(t, rec.Header().Get("Content-Length"))
+		})
+	}
+}
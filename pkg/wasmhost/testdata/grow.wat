@@ -0,0 +1,6 @@
+;; Grows its memory by 32MiB and traps if the host refuses.
+(module
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (if (i32.eq (memory.grow (i32.const 512)) (i32.const -1))
+      (then unreachable))))
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: The Fission Authors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wasmhost implements the fission-bundle --wasmHostPort subsystem: the
+// long-lived host pod the wasm executor type runs WASI modules in. One host
+// serves every wasm function of a namespace. Its fetcher sidecar deploys each
+// function's package onto the shared volume and specializes the host with a
+// FunctionLoadRequest, as it would an environment runtime; the host compiles
+// the module and runs a fresh instance of it for every request the router
+// proxies, selecting the module by the function UID and generation headers.
+//
+// A request maps onto the module CGI-style (see fv1.WasmConfig), with the
+// function's memory and execution-time limits enforced by the runtime in
+// process.
+package wasmhost
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/sync/errgroup"
+
+	fv1 "github.com/fission/fission/pkg/apis/core/v1"
+	hmacauth "github.com/fission/fission/pkg/auth/hmac"
+	"github.com/fission/fission/pkg/utils/httpserver"
+)
+
+// defaultSharedMountPath is where the fetcher sidecar deploys packages in a
+// host pod (see fetcher/config.MakeFetcherConfig).
+const defaultSharedMountPath = "/userfunc"
+
+// Options configures Start. The listener is either pre-bound by the caller
+// (Listener, e.g. a test harness binding 127.0.0.1:0) or bound here from
+// Port. SharedMountPath is the fetcher's shared volume; modules are only
+// loaded from under it.
+type Options struct {
+	Port            int
+	Listener        net.Listener
+	SharedMountPath string
+}
+
+// Start runs the wasm host until ctx is done. It serves the specialization
+// API the fetcher and executor call and the function invocations the router
+// proxies on the same port, like an environment runtime.
+//
+// The specialization API verifies requests with the key the pod's fetcher
+// verifies its own with (see cmd/fetcher/app): the namespace's derived
+// fetcher key when provisioned, else the one derived from the master, else
+// none when internal auth is disabled. The fetcher signs its load requests
+// with it and the executor its loaded/unload calls.
+func Start(ctx context.Context, logger logr.Logger, mgr *errgroup.Group, opts Options) error {
+	logger = logger.WithName("wasmhost")
+	if err := fv1.ValidateInternalAuthEnv(); err != nil {
+		return err
+	}
+	root := opts.SharedMountPath
+	if root == "" {
+		root = defaultSharedMountPath
+	}
+	verifier := hmacauth.VerifierFromKeyOrMaster(
+		hmacauth.DecodeKeyFromEnv(os.Getenv("FISSION_FETCHER_KEY")),
+		hmacauth.DecodeKeyFromEnv(os.Getenv("FISSION_FETCHER_KEY_OLD")),
+		[]byte(os.Getenv("FISSION_INTERNAL_AUTH_SECRET")),
+		[]byte(os.Getenv("FISSION_INTERNAL_AUTH_SECRET_OLD")),
+		hmacauth.ServiceFetcher, hmacauth.VerifierOpts{
+			SkewSec:      60,
+			Bypass:       []string{"/healthz"},
+			MaxBodyBytes: hmacauth.DefaultMaxBodyBytes,
+			Logger:       logger.WithName("hmac"),
+		})
+	host := NewHost(logger, root, verifier)
+
+	mgr.Go(func() error {
+		httpserver.Serve(ctx, logger, mgr, httpserver.ServerOptions{
+			Name: "wasmhost", Addr: strconv.Itoa(opts.Port), Listener: opts.Listener, Handler: host,
+		})
+		return host.Close(context.Background())
+	})
+	logger.Info("starting wasm host", "port", opts.Port, "sharedMountPath", root)
+	return nil
+}
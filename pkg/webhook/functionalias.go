@@ -21,7 +21,7 @@ import (
 // FunctionAlias is the admission webhook for v1.FunctionAlias (RFC-0025): on
 // top of the type's own field-shape rules, it checks that a name-pinned
 // target (spec.version / spec.secondaryVersion) actually exists and belongs
-// to the alias's own function.
+// to the alias's own function, and so does a spec.mirror target.
 type FunctionAlias struct {
 	GenericWebhook[*v1.FunctionAlias]
 	// reader is the uncached API reader used to look up the referenced
@@ -52,6 +52,11 @@ func (r *FunctionAlias) Validate(new *v1.FunctionAlias) error {
 	if err := r.validateVersionRef(context.Background(), new, new.Spec.SecondaryVersion, "FunctionAliasSpec.SecondaryVersion"); err != nil {
 		return v1.AggregateValidationErrors("FunctionAlias", err)
 	}
+	if new.Spec.Mirror != nil {
+		if err := r.validateVersionRef(context.Background(), new, new.Spec.Mirror.Version, "FunctionAliasSpec.Mirror.Version"); err != nil {
+			return v1.AggregateValidationErrors("FunctionAlias", err)
+		}
+	}
 	return nil
 }
 
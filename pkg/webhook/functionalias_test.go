@@ -133,6 +133,29 @@ func TestFunctionAliasWebhook_ReferenceIntegrity(t *testing.T) {
 		}
 	})
 
+	t.Run("mirror version belonging to a different function rejected", func(t *testing.T) {
+		primary := functionVersionFor("fn", "fn-v1")
+		shadow := functionVersionFor("other-fn", "fn-v2")
+		a := makeValidFunctionAlias()
+		a.Spec.Mirror = &v1.TrafficMirror{Version: "fn-v2", Percent: 10}
+		r := &FunctionAlias{reader: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(primary, shadow).Build()}
+		err := r.Validate(a)
+		if err == nil || !strings.Contains(err.Error(), "FunctionAliasSpec.Mirror.Version") {
+			t.Fatalf("expected wrong-function rejection for mirror.version, got: %v", err)
+		}
+	})
+
+	t.Run("mirror version for the right function accepted", func(t *testing.T) {
+		primary := functionVersionFor("fn", "fn-v1")
+		shadow := functionVersionFor("fn", "fn-v2")
+		a := makeValidFunctionAlias()
+		a.Spec.Mirror = &v1.TrafficMirror{Version: "fn-v2"}
+		r := &FunctionAlias{reader: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(primary, shadow).Build()}
+		if err := r.Validate(a); err != nil {
+			t.Fatalf("unexpected rejection: %v", err)
+		}
+	})
+
 	t.Run("no reader: reference checks skipped (fail open)", func(t *testing.T) {
 		r := &FunctionAlias{}
 		if err := r.Validate(makeValidFunctionAlias()); err != nil {
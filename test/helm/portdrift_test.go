@@ -51,6 +51,10 @@ func TestChartPortsMatchSvcinfo(t *testing.T) {
 		require.Len(t, internal, 1)
 		assert.EqualValues(t, svcinfo.PortRouterInternal, internal[0]["port"])
 		assert.EqualValues(t, svcinfo.PortRouterInternal, internal[0]["targetPort"])
+
+		peers := servicePorts(t, find(docs, "Service", svcinfo.SvcRouterPeers))
+		require.Len(t, peers, 1)
+		assert.EqualValues(t, svcinfo.PortRouterInternal, peers[0]["targetPort"])
 	})
 
 	t.Run("executor and storagesvc services", func(t *testing.T) {
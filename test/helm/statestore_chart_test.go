@@ -108,3 +108,28 @@ func TestStateSvcChart(t *testing.T) {
 		assert.Nil(t, find(docs, "Deployment", svcinfo.SvcStateSvc))
 	})
 }
+
+// TestExecutorUsageAccountingChart: with usage accounting on, the executor
+// gets its env and — with the statestore on — the client-driver wiring and a
+// row in the statestore NetworkPolicy allowlist for its hourly rollups.
+func TestExecutorUsageAccountingChart(t *testing.T) {
+	docs := render(t,
+		"--set", "executor.usageAccounting.enabled=true",
+		"--set", "statestore.enabled=true", "--set", "statestore.mode=embedded",
+		"--set", "networkPolicy.enabled=true")
+
+	env := containerEnv(t, find(docs, "Deployment", svcinfo.SvcExecutor))
+	assert.Equal(t, "true", env["EXECUTOR_USAGE_ACCOUNTING_ENABLED"])
+	assert.Equal(t, "30s", env["EXECUTOR_USAGE_ACCOUNTING_INTERVAL"])
+	assert.Equal(t, "2160h", env["EXECUTOR_USAGE_ACCOUNTING_RETENTION"])
+	assert.Equal(t, "client", env["STATESTORE_DRIVER"])
+	assert.Contains(t, env["STATESTORE_DSN"], svcinfo.SvcStatestore)
+
+	ssNP := find(docs, "NetworkPolicy", "statestore")
+	require.NotNil(t, ssNP)
+	assert.True(t, npAllowsFromSvc(ssNP, svcinfo.SvcExecutor))
+
+	off := containerEnv(t, find(render(t), "Deployment", svcinfo.SvcExecutor))
+	assert.NotContains(t, off, "EXECUTOR_USAGE_ACCOUNTING_ENABLED")
+	assert.NotContains(t, off, "STATESTORE_DRIVER")
+}